@@ -1,34 +1,69 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/zde37/pinata-go-sdk/pinata"
 )
 
-type PinataClient struct {
-	client *pinata.Client
+// example is a named walkthrough of a part of the SDK. Examples run in order and share a
+// scenario, so that later ones can use the content pinned by earlier ones.
+type example struct {
+	name string
+	run  func(ctx context.Context, s *scenario) error
 }
 
-func NewPinataClient(auth *pinata.Auth) *PinataClient {
-	client := pinata.New(auth)
-	return &PinataClient{
-		client: client,
-	}
+// scenario is the state shared by the examples of a run. The examples use the SDK through its
+// interfaces, so that they run against a pinatatest.Mock as well as a Client.
+type scenario struct {
+	client  pinata.PinataAPI
+	groups  pinata.GroupsAPI
+	apiKeys pinata.KeysAPI
+	log     *log.Logger
+	dir     string
+
+	fileCid string
+	jsonCid string
+	groupID pinata.GroupID
+	keys    []string
+}
+
+var examples = []example{
+	{"authentication", testAuthentication},
+	{"pin-file", pinFile},
+	{"pin-json", pinJSON},
+	{"list-files", listFiles},
+	{"update-metadata", updateFileMetadata},
+	{"gateway", getContent},
+	{"groups", groups},
+	{"signatures", signatures},
+	{"pin-by-cid", pinByCid},
+	{"api-keys", apiKeys},
+	{"cleanup", cleanup},
 }
 
-func (p *PinataClient) TestAuthentication() error {
-	res, err := p.client.TestAuthentication()
+func testAuthentication(ctx context.Context, s *scenario) error {
+	res, err := s.client.TestAuthentication()
 	if err != nil {
 		return err
 	}
 
-	log.Println(res.Message)
+	s.log.Println(res.Message)
 	return nil
 }
 
-func (p *PinataClient) PinFile(filePath string) error {
-	options := &pinata.PinOptions{
+func pinFile(ctx context.Context, s *scenario) error {
+	path := filepath.Join(s.dir, "hi.txt")
+	if err := os.WriteFile(path, []byte("hi from the pinata-go-sdk examples\n"), 0644); err != nil {
+		return err
+	}
+
+	response, err := s.client.PinFile(path, &pinata.PinOptions{
 		PinataMetadata: pinata.PinataMetadata{
 			Name: "hi.txt",
 			KeyValues: map[string]interface{}{
@@ -39,323 +74,216 @@ func (p *PinataClient) PinFile(filePath string) error {
 		PinataOptions: pinata.Options{
 			CidVersion: 1,
 		},
-	}
-	response, err := p.client.PinFileToIPFS(filePath, options)
+	})
 	if err != nil {
 		return err
 	}
 
-	log.Printf("file pinned successfully. Details: %+v\n", response)
+	s.fileCid = response.IpfsHash
+	s.log.Printf("file pinned as %s (%d bytes)\n", response.IpfsHash, response.PinSize)
 	return nil
 }
 
-func (p *PinataClient) PinJSON(jsonData map[string]interface{}) error {
-	options := &pinata.PinOptions{
+func pinJSON(ctx context.Context, s *scenario) error {
+	data := map[string]interface{}{
+		"title": "important docs",
+		"pages": 3,
+	}
+	response, err := s.client.PinJSON(data, &pinata.PinOptions{
 		PinataMetadata: pinata.PinataMetadata{
 			Name: "important-docs.json",
 			KeyValues: map[string]interface{}{
 				"category": "example",
-				"version":  2,
 			},
 		},
-		PinataOptions: pinata.Options{
-			CidVersion: 1,
-		},
-	}
-
-	response, err := p.client.PinJSONToIPFS(jsonData, options)
-	if err != nil {
-		return err
-	}
-
-	log.Printf("json pinned successfully. Details: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) GetFile(cid string) error {
-	options := &pinata.ListFilesOptions{
-		Cid:          cid,
-		IncludeCount: true,
-	}
-	response, err := p.client.ListFiles(options)
+	})
 	if err != nil {
 		return err
 	}
 
-	log.Printf("pin info: %+v", response.Rows[0])
+	s.jsonCid = response.IpfsHash
+	s.log.Printf("json pinned as %s\n", response.IpfsHash)
 	return nil
 }
 
-func (p *PinataClient) ListFiles() error {
-	options := &pinata.ListFilesOptions{
+func listFiles(ctx context.Context, s *scenario) error {
+	response, err := s.client.ListFiles(&pinata.ListFilesOptions{
 		IncludeCount: true,
 		Status:       "pinned",
-	}
-	response, err := p.client.ListFiles(options)
+	})
 	if err != nil {
 		return err
 	}
 
-	log.Printf("total pins: %d\n", response.Count)
+	s.log.Printf("total pins: %d\n", response.Count)
 	for _, pin := range response.Rows {
-		log.Printf("pin info: %+v\n\n", pin)
+		s.log.Printf("pin %s: %v\n", pin.IPFSPinHash, pin.Metadata["name"])
 	}
 	return nil
 }
 
-func (p *PinataClient) UpdateFileMetadata(cid string) error {
-	options := &pinata.PinMetadataUpdateOptions{
-		Name: "1",
+func updateFileMetadata(ctx context.Context, s *scenario) error {
+	err := s.client.UpdateFileMetadata(s.fileCid, &pinata.PinMetadataUpdateOptions{
+		Name: "hello.txt",
 		KeyValues: map[string]interface{}{
-			"category": "1",
-			"version":  1,
+			"version": 3,
 		},
-	}
-
-	err := p.client.UpdateFileMetadata(cid, options)
+	})
 	if err != nil {
 		return err
 	}
 
-	log.Println("file metadata updated successfully")
-	return nil
-}
-
-func (p *PinataClient) DeleteFile(cid string) error {
-	err := p.client.DeleteFile(cid)
+	pin, err := s.client.GetPinByCid(ctx, s.fileCid, nil)
 	if err != nil {
 		return err
 	}
-
-	log.Println("file deleted successfully")
+	s.log.Printf("file metadata updated: %v\n", pin.Metadata)
 	return nil
 }
 
-func (p *PinataClient) PinByCid(cid string) error {
-	options := &pinata.PinByCidOptions{
-		PinataMetadata: pinata.PinataMetadata{
-			Name: "hi.txt",
-			KeyValues: map[string]interface{}{
-				"category": "example",
-				"version":  2,
-			},
-		},
-		PinataOptions: pinata.PinOpts{
-			HostNodes: []string{
-				"/ip4/172.22.33.3/tcp/4001/p2p/12D3KooWKyePX78pS5dtxkEubRDd7iyB3ihkUHsdLXLxJRAAAZu8",
-				"/ip4/172.22.33.3/udp/4001/quic-v1/p2p/12D3KooWKyePX78pS5dtxkEubRDd7iyB3ihkUHsdLXLxJRAAAZu8",
-			},
-		},
-	}
-	response, err := p.client.PinByCid(cid, options)
+func getContent(ctx context.Context, s *scenario) error {
+	content, err := s.client.GetContent(ctx, s.fileCid)
 	if err != nil {
 		return err
 	}
+	defer content.Body.Close()
 
-	log.Printf("pin info: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) ListPinByCidJobs(cid string) error {
-	options := &pinata.ListPinByCidOptions{
-		Sort:        pinata.SortOrderASC,
-		Status:      pinata.PinStatusPrechecking,
-		IPFSPinHash: cid,
-	}
-
-	response, err := p.client.ListPinByCidJobs(options)
+	body, err := io.ReadAll(content.Body)
 	if err != nil {
 		return err
 	}
-
-	log.Printf("total pins: %d\n", response.Count)
-	for _, pin := range response.Rows {
-		log.Printf("pin info: %+v\n\n", pin)
-	}
+	s.log.Printf("content of %s served by %s: %q\n", s.fileCid, content.Gateway, body)
 	return nil
 }
 
-func (p *PinataClient) CreateGroup(name string) error {
-	response, err := p.client.CreateGroup(name)
+func groups(ctx context.Context, s *scenario) error {
+	group, err := s.groups.Create("examples")
 	if err != nil {
 		return err
 	}
-
-	log.Printf("group created successfully. Details: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) GetGroup(groupName string) error {
-	response, err := p.client.GetGroup(groupName)
+	// group IDs and CIDs are distinct types, so that they cannot be swapped
+	id, err := pinata.ParseGroupID(group.ID)
 	if err != nil {
 		return err
 	}
+	s.groupID = id
+	s.log.Printf("group %s created\n", id)
 
-	log.Printf("group info: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) ListGroups() error {
-	options := &pinata.ListGroupsOptions{}
-
-	response, err := p.client.ListGroups(options)
+	cids, err := pinata.ParseCids(s.fileCid, s.jsonCid)
 	if err != nil {
 		return err
 	}
-
-	for _, group := range response {
-		log.Printf("group info: %+v\n\n", group)
+	if err := s.groups.AddCids(id, cids); err != nil {
+		return err
 	}
-	return nil
-}
-
-func (p *PinataClient) UpdateGroupName(groupID, newName string) error {
-	response, err := p.client.UpdateGroup(groupID, newName)
-	if err != nil {
+	if err := s.groups.RemoveCids(id, cids[1:]); err != nil {
 		return err
 	}
-
-	log.Printf("group info: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) AddCidToGroup(groupID string, cids []string) error {
-	err := p.client.AddCidToGroup(groupID, cids)
+	group, err = s.groups.Update(id, "examples-renamed")
 	if err != nil {
 		return err
 	}
 
-	log.Println("cids added to group successfully")
-	return nil
-}
-
-func (p *PinataClient) RemoveCidFromGroup(groupID string, cids []string) error {
-	err := p.client.RemoveCidFromGroup(groupID, cids)
+	list, err := s.groups.List(&pinata.ListGroupsOptions{NameContains: "examples"})
 	if err != nil {
 		return err
 	}
+	for _, g := range list {
+		s.log.Printf("group %s: %s\n", g.ID, g.GroupName)
+	}
 
-	log.Println("cids removed from group successfully")
-	return nil
-}
-
-func (p *PinataClient) RemoveGroup(groupID string) error {
-	err := p.client.RemoveGroup(groupID)
+	members, err := s.client.ListFiles(&pinata.ListFilesOptions{GroupID: group.ID, IncludeCount: true})
 	if err != nil {
 		return err
 	}
-
-	log.Println("group removed successfully")
+	s.log.Printf("group %s holds %d pins\n", group.ID, members.Count)
 	return nil
 }
 
-func (p *PinataClient) AddCidSignature(cid, signature string) error {
-	response, err := p.client.AddCidSignature(cid, signature)
-	if err != nil {
+func signatures(ctx context.Context, s *scenario) error {
+	if _, err := s.client.AddCidSignature(s.fileCid, "0xexamplesignature"); err != nil {
 		return err
 	}
 
-	log.Printf("cid signature added successfully. Details: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) GetCidSignature(cid string) error {
-	response, err := p.client.GetCidSignature(cid)
+	signature, err := s.client.GetCidSignature(s.fileCid)
 	if err != nil {
 		return err
 	}
+	s.log.Printf("signature of %s: %s\n", signature.Data.Cid, signature.Data.Signature)
 
-	log.Printf("cid signature info: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) RemoveCidSignature(cid string) error {
-	err := p.client.RemoveCidSignature(cid)
-	if err != nil {
+	if err := s.client.RemoveCidSignature(s.fileCid); err != nil {
 		return err
 	}
-
-	log.Println("cid signature removed successfully")
+	s.log.Println("cid signature removed")
 	return nil
 }
 
-func (p *PinataClient) CreateAPIKey(name string) error {
-	options := &pinata.GenerateApiKeyOptions{
-		KeyName: name,
-		Permissions: pinata.Permissions{
-			Admin: true,
+func pinByCid(ctx context.Context, s *scenario) error {
+	response, err := s.client.PinByCid(s.jsonCid, &pinata.PinByCidOptions{
+		PinataMetadata: pinata.PinataMetadata{
+			Name: "important-docs-copy",
 		},
-		MaxUses: 100,
+	})
+	if err != nil {
+		return err
 	}
+	s.log.Printf("pin by cid job %s is %s\n", response.ID, response.Status)
 
-	response, err := p.client.GenerateApiKey(options)
+	jobs, err := s.client.ListPinByCidJobs(&pinata.ListPinByCidOptions{
+		Sort:        pinata.SortOrderASC,
+		IPFSPinHash: s.jsonCid,
+	})
 	if err != nil {
 		return err
 	}
-
-	log.Printf("api key created successfully. Details: %+v\n", response)
+	s.log.Printf("pin by cid jobs for %s: %d\n", s.jsonCid, jobs.Count)
 	return nil
 }
 
-func (p *PinataClient) CreateAPIKeyV3(name string) error {
-	options := &pinata.GenerateApiKeyOptions{
-		KeyName: name,
+func apiKeys(ctx context.Context, s *scenario) error {
+	secret, err := s.apiKeys.Generate(&pinata.GenerateApiKeyOptions{
+		KeyName: "examples",
 		Permissions: pinata.Permissions{
 			Admin: true,
 		},
 		MaxUses: 100,
-	}
-
-	res, err := p.client.GenerateApiKeyV3(options)
+	})
 	if err != nil {
 		return err
 	}
+	s.keys = append(s.keys, secret.PinataApiKey)
+	s.log.Printf("api key %s created\n", secret.PinataApiKey)
 
-	log.Printf("api key created successfully. Details: %+v\n", res)
-	return nil
-}
-
-func (p *PinataClient) ListAPIKeys() error {
-	response, err := p.client.ListApiKeys()
-	if err != nil {
-		return err
-	}
-
-	log.Printf("api keys: %+v\n", response)
-	return nil
-}
-
-func (p *PinataClient) ListApiKeyV3() error {
 	revoked := false
-	options := &pinata.ListApiKeysOptions{
-		Revoked: &revoked,
-	}
-
-	res, err := p.client.ListApiKeyV3(options)
-	if err != nil {
-		return err
-	}
-
-	log.Printf("api keys: %+v\n", res)
-	return nil
-}
-
-func (p *PinataClient) RevokeApiKey(apiKey string) error {
-	err := p.client.RevokeApiKey(apiKey)
+	keys, err := s.apiKeys.List(&pinata.ListApiKeysOptions{Revoked: &revoked})
 	if err != nil {
 		return err
 	}
-
-	log.Println("api key revoked successfully")
+	s.log.Printf("active api keys: %d\n", keys.Count)
 	return nil
 }
 
-func (p *PinataClient) RevokeApiKeyV3(apiKey string) error {
-	err := p.client.RevokeApiKeyV3(apiKey)
-	if err != nil {
-		return err
-	}
-
-	log.Println("api key revoked successfully")
+// cleanup removes everything created by the other examples, so that runs against Pinata leave
+// the account as they found it.
+func cleanup(ctx context.Context, s *scenario) error {
+	for _, key := range s.keys {
+		if err := s.apiKeys.Revoke(key); err != nil {
+			return fmt.Errorf("revoke api key %s: %w", key, err)
+		}
+	}
+	if s.groupID != "" {
+		if err := s.groups.Remove(s.groupID); err != nil {
+			return fmt.Errorf("remove group %s: %w", s.groupID, err)
+		}
+	}
+	for _, cid := range []string{s.fileCid, s.jsonCid} {
+		if cid == "" {
+			continue
+		}
+		if err := s.client.Unpin(cid); err != nil {
+			return fmt.Errorf("unpin %s: %w", cid, err)
+		}
+	}
+	s.log.Println("examples cleaned up")
 	return nil
 }
@@ -1,15 +1,116 @@
+// Command examples walks through the pinata-go-sdk: it authenticates, pins a file and a JSON
+// document, reads them back, groups and signs them, queues a pin by CID, manages an API key, and
+// finally removes everything it created.
+//
+// It runs against Pinata with the JWT in PINATA_JWT, read from the environment or a .env file:
+//
+//	go run .
+//
+// or against the in-memory fake of pinatatest, without credentials or network access:
+//
+//	go run . -offline
+//
+// -example runs the examples up to and including the named one, and then the cleanup.
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
 	"os"
+	"strings"
 
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/zde37/pinata-go-sdk/pinata"
+	"github.com/zde37/pinata-go-sdk/pinata/pinatatest"
 )
 
 func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "examples:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses args and runs the examples, logging to out.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("examples", flag.ContinueOnError)
+	flags.SetOutput(out)
+	offline := flags.Bool("offline", false, "run against the in-memory pinatatest server instead of Pinata")
+	only := flags.String("example", "", "run the examples up to this one, then clean up; one of "+exampleNames())
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	selected, err := selectExamples(*only)
+	if err != nil {
+		return err
+	}
+
 	auth := pinata.NewAuthWithJWT(os.Getenv("PINATA_JWT"))
+	var opts []pinata.ClientOption
+	if *offline {
+		server := pinatatest.NewServer()
+		defer server.Close()
+		auth = pinata.NewAuthWithJWT("offline")
+		opts = append(opts, server.Option(), pinatatest.Deterministic())
+	} else if os.Getenv("PINATA_JWT") == "" {
+		return errors.New("PINATA_JWT is not set; set it or run with -offline")
+	}
+
+	dir, err := os.MkdirTemp("", "pinata-examples-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	client := pinata.New(auth, opts...)
+	s := &scenario{
+		client:  client,
+		groups:  client.Groups(),
+		apiKeys: client.Keys(),
+		log:     log.New(out, "", 0),
+		dir:     dir,
+	}
+	for _, e := range selected {
+		s.log.Printf("== %s\n", e.name)
+		if err := e.run(ctx, s); err != nil {
+			// whatever was created is still removed when an example fails
+			if e.name != "cleanup" {
+				cleanup(ctx, s)
+			}
+			return fmt.Errorf("%s: %w", e.name, err)
+		}
+	}
+	return nil
+}
+
+// selectExamples returns the examples to run for the -example flag: every example when name is
+// empty, and otherwise the examples up to name followed by the cleanup.
+func selectExamples(name string) ([]example, error) {
+	if name == "" {
+		return examples, nil
+	}
+	for i, e := range examples {
+		if e.name != name {
+			continue
+		}
+		selected := append([]example(nil), examples[:i+1]...)
+		if name != "cleanup" {
+			selected = append(selected, examples[len(examples)-1])
+		}
+		return selected, nil
+	}
+	return nil, fmt.Errorf("unknown example %q; want one of %s", name, exampleNames())
+}
 
-	pinataClient := NewPinataClient(auth)
-	pinataClient.TestAuthentication()
+func exampleNames() string {
+	names := make([]string, len(examples))
+	for i, e := range examples {
+		names[i] = e.name
+	}
+	return strings.Join(names, ", ")
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata"
+	"github.com/zde37/pinata-go-sdk/pinata/pinatatest"
+)
+
+// TestOffline runs every example against the pinatatest server, so that the examples are built
+// and run against the SDK of this repository by every test run.
+func TestOffline(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"-offline"}, &out), out.String())
+
+	for _, e := range examples {
+		require.Contains(t, out.String(), "== "+e.name+"\n")
+	}
+	require.Contains(t, out.String(), `"hi from the pinata-go-sdk examples\n"`)
+	require.Contains(t, out.String(), "group group-")
+	require.Contains(t, out.String(), "holds 1 pins")
+	require.Contains(t, out.String(), "examples cleaned up")
+}
+
+func TestSelectedExample(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"-offline", "-example", "pin-json"}, &out), out.String())
+	require.Contains(t, out.String(), "== pin-json\n")
+	require.NotContains(t, out.String(), "== list-files\n")
+	require.Contains(t, out.String(), "== cleanup\n")
+
+	require.ErrorContains(t, run(context.Background(), []string{"-offline", "-example", "nope"}, &out), `unknown example "nope"`)
+}
+
+func TestOnlineRequiresJWT(t *testing.T) {
+	t.Setenv("PINATA_JWT", "")
+	var out bytes.Buffer
+	require.ErrorContains(t, run(context.Background(), nil, &out), "PINATA_JWT is not set")
+}
+
+// TestMocked runs examples against pinatatest.Mock, which the scenario takes in place of a Client.
+func TestMocked(t *testing.T) {
+	var out bytes.Buffer
+	api := &pinatatest.Mock{
+		TestAuthenticationFunc: func() (*pinata.AuthTestResponse, error) {
+			return &pinata.AuthTestResponse{Message: "mocked"}, nil
+		},
+		PinFileFunc: func(path string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+			return &pinata.PinResponse{IpfsHash: pinatatest.FixtureCid, PinSize: 35}, nil
+		},
+	}
+	s := &scenario{client: api, groups: &pinatatest.GroupsMock{}, apiKeys: &pinatatest.KeysMock{}, log: log.New(&out, "", 0), dir: t.TempDir()}
+
+	require.NoError(t, testAuthentication(context.Background(), s))
+	require.NoError(t, pinFile(context.Background(), s))
+	require.Equal(t, pinatatest.FixtureCid, s.fileCid)
+	require.Contains(t, out.String(), "mocked\n")
+	require.Len(t, api.CallsTo("PinFile"), 1)
+
+	require.ErrorIs(t, groups(context.Background(), s), pinatatest.ErrNotMocked)
+}
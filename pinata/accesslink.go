@@ -0,0 +1,178 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityV3AccessLinks)
+}
+
+const (
+	// DefaultAccessLinkExpiry is the lifetime of access links created without an ExpiresIn.
+	DefaultAccessLinkExpiry = time.Minute
+	// MinAccessLinkExpiry is the shortest lifetime of an access link.
+	MinAccessLinkExpiry = time.Second
+	// MaxAccessLinkExpiry is the longest lifetime of an access link.
+	MaxAccessLinkExpiry = 7 * 24 * time.Hour
+)
+
+// ErrAccessLinkExpired is returned when the gateway refuses an access link because it has
+// expired. A fresh link can be created with CreateAccessLink.
+var ErrAccessLinkExpired = errors.New("access link expired")
+
+// AccessLinkOptions represents the options for creating an access link to a private file.
+// Cid is the CID of the file. Exactly one of Cid and FileID must be set.
+// FileID is the v3 ID of the file; it is resolved to its CID before the link is created.
+// ExpiresIn is the lifetime of the link, in whole seconds between MinAccessLinkExpiry and
+// MaxAccessLinkExpiry; zero uses DefaultAccessLinkExpiry.
+type AccessLinkOptions struct {
+	Cid       string
+	FileID    string
+	ExpiresIn time.Duration
+}
+
+// CreateAccessLink returns a signed URL through which a private file can be retrieved without
+// credentials until the link expires. The link points at the first gateway of the client, which
+// must be a dedicated gateway of the account: the public gateway does not serve private files.
+func (f *FilesClient) CreateAccessLink(ctx context.Context, opts AccessLinkOptions) (string, error) {
+	if (opts.Cid == "") == (opts.FileID == "") {
+		return "", fmt.Errorf("exactly one of cid and file id is required")
+	}
+	expiresIn := opts.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = DefaultAccessLinkExpiry
+	}
+	if expiresIn < MinAccessLinkExpiry || expiresIn > MaxAccessLinkExpiry {
+		return "", fmt.Errorf("access link expiry %s is out of range [%s, %s]", expiresIn, MinAccessLinkExpiry, MaxAccessLinkExpiry)
+	}
+	if expiresIn%time.Second != 0 {
+		return "", fmt.Errorf("access link expiry %s is not a whole number of seconds", expiresIn)
+	}
+
+	cid := opts.Cid
+	if cid == "" {
+		var err error
+		if cid, err = f.privateFileCid(ctx, opts.FileID); err != nil {
+			return "", err
+		}
+	}
+
+	payload := map[string]interface{}{
+		"url":     f.c.gatewayHosts("")[0] + "/files/" + url.PathEscape(cid),
+		"expires": int64(expiresIn / time.Second),
+		"date":    f.c.now().Unix(),
+		"method":  http.MethodGet,
+	}
+	req, err := f.c.NewRequest(http.MethodPost, "/v3/files/private/download_link").
+		WithContext(ctx).
+		SetJSONBody(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to set JSON body: %w", err)
+	}
+
+	var response struct {
+		Data string `json:"data"`
+	}
+	if err := req.Send(&response); err != nil {
+		return "", err
+	}
+	if response.Data == "" {
+		return "", fmt.Errorf("no access link returned for %s", cid)
+	}
+	return response.Data, nil
+}
+
+// privateFileCid returns the CID of the private file with the v3 ID id.
+func (f *FilesClient) privateFileCid(ctx context.Context, id string) (string, error) {
+	var response struct {
+		Data File `json:"data"`
+	}
+	err := f.c.NewRequest(http.MethodGet, "/v3/files/private/{id}").
+		WithContext(ctx).
+		AddPathParam("id", id).
+		Send(&response)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve file %s: %w", id, err)
+	}
+	if response.Data.Cid == "" {
+		return "", fmt.Errorf("file %s has no cid", id)
+	}
+	return response.Data.Cid, nil
+}
+
+// GetAccessLink streams the content served through an access link. A link refused because it has
+// expired fails with an error wrapping ErrAccessLinkExpired; other statuses than 200 OK are
+// returned as *APIError. The download is reported by ActiveOperations, under the link without its
+// signature, until the body of the content is closed.
+func (f *FilesClient) GetAccessLink(ctx context.Context, link string) (*GatewayContent, error) {
+	target, _, _ := strings.Cut(link, "?")
+	return f.getAccessLink(ctx, f.c.startOp("Files.GetAccessLink", target), link)
+}
+
+// getAccessLink implements GetAccessLink, counting the download in op, which it ends when the
+// request fails or the body of the content is closed.
+func (f *FilesClient) getAccessLink(ctx context.Context, op *activeOp, link string) (*GatewayContent, error) {
+	ctx, deadline := f.c.startDownload(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		f.c.endOp(op)
+		deadline.stop()
+		return nil, fmt.Errorf("invalid access link: %w", err)
+	}
+	f.c.setUserAgent(req)
+	resp, err := f.c.httpClient.Do(req)
+	if err != nil {
+		f.c.endOp(op)
+		deadline.stop()
+		if ctx.Err() != nil {
+			return nil, transportError(deadline.err(ctx, ctx.Err()))
+		}
+		return nil, transportError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		f.c.endOp(op)
+		defer deadline.stop()
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if f.accessLinkExpired(req.URL, resp.StatusCode, string(body)) {
+			return nil, fmt.Errorf("%w: the gateway returned %s", ErrAccessLinkExpired, resp.Status)
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("access link returned %s", resp.Status)}
+	}
+
+	deadline.fit(resp.ContentLength)
+	body := deadline.body(ctx, resp.Body)
+	return &GatewayContent{
+		Body:        &opReadCloser{opReader: opReader{r: body, op: op}, c: f.c, closer: body},
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		Gateway:     req.URL.Scheme + "://" + req.URL.Host,
+	}, nil
+}
+
+// accessLinkExpired reports whether a refusal of link is due to its expiry: the gateway says so in
+// the body, or the X-Date and X-Expires parameters of the signed link show it has run out.
+func (f *FilesClient) accessLinkExpired(link *url.URL, status int, body string) bool {
+	if status != http.StatusUnauthorized && status != http.StatusForbidden {
+		return false
+	}
+	if strings.Contains(strings.ToLower(body), "expired") {
+		return true
+	}
+	query := link.Query()
+	date, err1 := strconv.ParseInt(query.Get("X-Date"), 10, 64)
+	expires, err2 := strconv.ParseInt(query.Get("X-Expires"), 10, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return !f.c.now().Before(time.Unix(date+expires, 0))
+}
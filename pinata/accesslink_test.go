@@ -0,0 +1,126 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAccessLink(t *testing.T) {
+	var payloads []map[string]interface{}
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/v3/files/private/file-id":
+			w.Write([]byte(`{"data": {"id": "file-id", "cid": "` + cidV1 + `"}}`))
+		case "/v3/files/private/download_link":
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			payloads = append(payloads, payload)
+			w.Write([]byte(`{"data": "https://example.mypinata.cloud/files/` + cidV1 + `?X-Signature=abc"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := New(NewAuthWithJWT("test_token"), WithGateways("https://example.mypinata.cloud"))
+	client.baseURL = mockServer.URL
+	client.clock = &fakeClock{now: time.Unix(1700000000, 0)}
+
+	t.Run("by cid", func(t *testing.T) {
+		payloads, requests = nil, nil
+
+		link, err := client.Files().CreateAccessLink(context.Background(), AccessLinkOptions{Cid: cidV1, ExpiresIn: time.Hour})
+
+		require.NoError(t, err)
+		require.Equal(t, "https://example.mypinata.cloud/files/"+cidV1+"?X-Signature=abc", link)
+		require.Equal(t, []map[string]interface{}{{
+			"url":     "https://example.mypinata.cloud/files/" + cidV1,
+			"expires": float64(3600),
+			"date":    float64(1700000000),
+			"method":  "GET",
+		}}, payloads)
+	})
+
+	t.Run("by file id", func(t *testing.T) {
+		payloads, requests = nil, nil
+
+		_, err := client.Files().CreateAccessLink(context.Background(), AccessLinkOptions{FileID: "file-id"})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"GET /v3/files/private/file-id", "POST /v3/files/private/download_link"}, requests)
+		require.Equal(t, float64(60), payloads[0]["expires"])
+		require.Equal(t, "https://example.mypinata.cloud/files/"+cidV1, payloads[0]["url"])
+	})
+
+	invalid := map[string]AccessLinkOptions{
+		"no target":     {},
+		"two targets":   {Cid: cidV1, FileID: "file-id"},
+		"negative":      {Cid: cidV1, ExpiresIn: -time.Second},
+		"too short":     {Cid: cidV1, ExpiresIn: 500 * time.Millisecond},
+		"too long":      {Cid: cidV1, ExpiresIn: MaxAccessLinkExpiry + time.Second},
+		"whole seconds": {Cid: cidV1, ExpiresIn: 1500 * time.Millisecond},
+	}
+	for name, opts := range invalid {
+		t.Run(name, func(t *testing.T) {
+			requests = nil
+
+			_, err := client.Files().CreateAccessLink(context.Background(), opts)
+
+			require.Error(t, err)
+			require.Empty(t, requests)
+		})
+	}
+}
+
+func TestGetAccessLinkExpiry(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/expired-message":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": "Signature has expired"}`))
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": "Forbidden"}`))
+		default:
+			w.Write([]byte("private content"))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := New(NewAuthWithJWT("test_token"))
+	client.clock = &fakeClock{now: time.Unix(1700000100, 0)}
+	files := client.Files()
+
+	t.Run("expiry reported by the gateway", func(t *testing.T) {
+		_, err := files.GetAccessLink(context.Background(), mockServer.URL+"/expired-message")
+		require.ErrorIs(t, err, ErrAccessLinkExpired)
+	})
+
+	t.Run("expiry from the link parameters", func(t *testing.T) {
+		_, err := files.GetAccessLink(context.Background(), mockServer.URL+"/forbidden?X-Date=1700000000&X-Expires=60")
+		require.ErrorIs(t, err, ErrAccessLinkExpired)
+	})
+
+	t.Run("other refusals", func(t *testing.T) {
+		_, err := files.GetAccessLink(context.Background(), mockServer.URL+"/forbidden?X-Date=1700000000&X-Expires=3600")
+		require.False(t, errors.Is(err, ErrAccessLinkExpired))
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	})
+
+	t.Run("valid link", func(t *testing.T) {
+		content, err := files.GetAccessLink(context.Background(), mockServer.URL+"/ok")
+		require.NoError(t, err)
+		content.Body.Close()
+	})
+}
@@ -0,0 +1,137 @@
+package pinata
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityActiveOperations)
+}
+
+// ActiveOp describes an upload or download in flight, as reported by ActiveOperations.
+// ID identifies the operation among those of the client.
+// Operation is the name of the method that started it, such as "PinFile" or "Gateway.Get".
+// Target is the local path, file name, URL or CID the operation works on.
+// Bytes is the number of bytes of content sent so far by an upload, or read so far from a download.
+// A retried upload starts over from zero.
+// StartedAt is the time the operation started.
+type ActiveOp struct {
+	ID        uint64
+	Operation string
+	Target    string
+	Bytes     int64
+	StartedAt time.Time
+}
+
+// activeOp is the entry of an operation in flight. Its byte count is updated without locking.
+type activeOp struct {
+	id        uint64
+	operation string
+	target    string
+	startedAt time.Time
+	bytes     atomic.Int64
+}
+
+// activeOps is the registry of the operations in flight of a client. Operations are added and
+// removed through a sync.Map, so that concurrent uploads do not contend on a lock.
+type activeOps struct {
+	nextID atomic.Uint64
+	ops    sync.Map
+}
+
+// ActiveOperations returns a snapshot of the uploads and downloads in flight, oldest first. It is
+// meant for inspecting a running process, such as during an incident, and is cheap enough to be
+// polled.
+//
+// The pin methods that upload content are tracked from their start until they return. Downloads
+// started with Gateway().Get are tracked until the body of the content is closed.
+func (c *Client) ActiveOperations() []ActiveOp {
+	var ops []ActiveOp
+	c.activeOps.ops.Range(func(_, value any) bool {
+		op := value.(*activeOp)
+		ops = append(ops, ActiveOp{
+			ID:        op.id,
+			Operation: op.operation,
+			Target:    op.target,
+			Bytes:     op.bytes.Load(),
+			StartedAt: op.startedAt,
+		})
+		return true
+	})
+	sort.Slice(ops, func(i, j int) bool {
+		if !ops[i].StartedAt.Equal(ops[j].StartedAt) {
+			return ops[i].StartedAt.Before(ops[j].StartedAt)
+		}
+		return ops[i].ID < ops[j].ID
+	})
+	return ops
+}
+
+// startOp registers an operation in flight. The caller must end it with endOp.
+func (c *Client) startOp(operation, target string) *activeOp {
+	op := &activeOp{
+		id:        c.activeOps.nextID.Add(1),
+		operation: operation,
+		target:    target,
+		startedAt: c.now(),
+	}
+	c.activeOps.ops.Store(op.id, op)
+	return op
+}
+
+// endOp removes op from the operations in flight.
+func (c *Client) endOp(op *activeOp) {
+	c.activeOps.ops.Delete(op.id)
+}
+
+// opReader counts the bytes read from r in the byte count of op.
+type opReader struct {
+	r  io.Reader
+	op *activeOp
+}
+
+func (o *opReader) Read(p []byte) (int, error) {
+	n, err := o.r.Read(p)
+	o.op.bytes.Add(int64(n))
+	return n, err
+}
+
+// opReadCloser is an opReader that ends its operation when closed.
+type opReadCloser struct {
+	opReader
+	c      *Client
+	closer io.Closer
+	once   sync.Once
+}
+
+func (o *opReadCloser) Close() error {
+	o.once.Do(func() { o.c.endOp(o.op) })
+	return o.closer.Close()
+}
+
+// countBody wraps the request body of req, and the bodies of its retries, so that what is sent is
+// counted in the byte count of op. The count starts over with each body.
+func countBody(req *http.Request, op *activeOp) {
+	wrap := func(body io.ReadCloser) io.ReadCloser {
+		op.bytes.Store(0)
+		return struct {
+			io.Reader
+			io.Closer
+		}{&opReader{r: body, op: op}, body}
+	}
+	req.Body = wrap(req.Body)
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return wrap(body), nil
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveOperations(t *testing.T) {
+	t.Run("upload", func(t *testing.T) {
+		received := make(chan int64)
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n, _ := io.Copy(io.Discard, r.Body)
+			received <- n
+			<-release
+			serveFixture(w, r)
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartContentLength(true))
+		client.baseURL = mockServer.URL
+		require.Empty(t, client.ActiveOperations())
+
+		path := filepath.Join(writeTree(t, "a.txt"), "a.txt")
+		done := make(chan error)
+		go func() {
+			_, err := client.PinFile(path, nil)
+			done <- err
+		}()
+
+		sent := <-received
+		ops := client.ActiveOperations()
+		require.Len(t, ops, 1)
+		require.Equal(t, "PinFile", ops[0].Operation)
+		require.Equal(t, path, ops[0].Target)
+		require.Equal(t, sent, ops[0].Bytes)
+		require.False(t, ops[0].StartedAt.IsZero())
+
+		close(release)
+		require.NoError(t, <-done)
+		require.Empty(t, client.ActiveOperations())
+	})
+
+	t.Run("concurrent uploads", func(t *testing.T) {
+		received := make(chan struct{}, 2)
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			received <- struct{}{}
+			<-release
+			serveFixture(w, r)
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		done := make(chan error)
+		go func() {
+			_, err := client.PinJSON(map[string]string{"a": "b"}, &PinOptions{PinataMetadata: PinataMetadata{Name: "doc.json"}})
+			done <- err
+		}()
+		<-received
+		pr, pw := io.Pipe()
+		go func() {
+			_, err := client.PinReader(context.Background(), pr, "stream", nil, nil)
+			done <- err
+		}()
+		pw.Write([]byte("first chunk"))
+
+		// the stream is still open, so only the JSON upload is complete
+		ops := client.ActiveOperations()
+		require.Len(t, ops, 2)
+		require.Equal(t, "PinJSON", ops[0].Operation)
+		require.Equal(t, "doc.json", ops[0].Target)
+		require.Positive(t, ops[0].Bytes)
+		require.Equal(t, "PinReader", ops[1].Operation)
+		require.Equal(t, "stream", ops[1].Target)
+		require.Less(t, ops[0].ID, ops[1].ID)
+
+		close(release)
+		pw.Close()
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+		require.Empty(t, client.ActiveOperations())
+	})
+
+	t.Run("download", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("hello, gateway"))
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(mockServer.URL))
+
+		content, err := client.Gateway().Get(context.Background(), cidV0)
+		require.NoError(t, err)
+		ops := client.ActiveOperations()
+		require.Len(t, ops, 1)
+		require.Equal(t, "Gateway.Get", ops[0].Operation)
+		require.Equal(t, cidV0, ops[0].Target)
+		require.Zero(t, ops[0].Bytes)
+
+		body, err := io.ReadAll(content.Body)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(body)), client.ActiveOperations()[0].Bytes)
+		require.NoError(t, content.Body.Close())
+		require.Empty(t, client.ActiveOperations())
+	})
+
+	t.Run("file downloads", func(t *testing.T) {
+		f := newFakeFiles(t)
+		client := New(NewAuthWithJWT("test_token"), WithProfile(Profile{APIURL: f.server.URL, UploadURL: f.server.URL}), WithGateways(f.server.URL))
+
+		for _, network := range []Network{NetworkPrivate, NetworkPublic} {
+			content, err := client.Files().DownloadFile(context.Background(), &File{Cid: cidV1, Network: network})
+			require.NoError(t, err)
+			ops := client.ActiveOperations()
+			require.Len(t, ops, 1)
+			require.Equal(t, "Files.DownloadFile", ops[0].Operation)
+			require.Equal(t, cidV1, ops[0].Target)
+
+			body, err := io.ReadAll(content.Body)
+			require.NoError(t, err)
+			require.Equal(t, int64(len(body)), client.ActiveOperations()[0].Bytes)
+			require.NoError(t, content.Body.Close())
+			require.Empty(t, client.ActiveOperations())
+		}
+
+		link, err := client.Files().CreateAccessLink(context.Background(), AccessLinkOptions{Cid: cidV1})
+		require.NoError(t, err)
+		content, err := client.Files().GetAccessLink(context.Background(), link)
+		require.NoError(t, err)
+		ops := client.ActiveOperations()
+		require.Len(t, ops, 1)
+		require.Equal(t, "Files.GetAccessLink", ops[0].Operation)
+		require.Equal(t, f.server.URL+"/signed/"+cidV1, ops[0].Target)
+		require.NoError(t, content.Body.Close())
+		require.Empty(t, client.ActiveOperations())
+
+		_, err = client.Files().GetAccessLink(context.Background(), f.server.URL+"/signed/"+cidV1+"?X-Signature=wrong")
+		require.Error(t, err)
+		require.Empty(t, client.ActiveOperations())
+	})
+
+	t.Run("failed download", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(mockServer.URL))
+
+		_, err := client.Gateway().Get(context.Background(), cidV0)
+		require.Error(t, err)
+		require.Empty(t, client.ActiveOperations())
+	})
+}
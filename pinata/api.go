@@ -0,0 +1,216 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// PinataAPI is the set of methods of Client, so that code depending on the SDK can take an
+// interface and be tested with a fake, such as pinatatest.Mock, instead of a server. It is made of
+// one interface per domain, so that code needing a single domain can take the smaller interface.
+//
+// PinataAPI leaves out the methods returning values bound to a Client, which a fake cannot make,
+// and the deprecated methods. The former are NewRequest, NewPinQueue and the accessors of the
+// sub-clients, whose methods have interfaces of their own: GroupsAPI for Groups, KeysAPI for Keys
+// and FilesAPI for Files, while ContentReader covers Gateway. Code managing groups, for instance,
+// takes a GroupsAPI next to its PinataAPI, given client.Groups().
+//
+// Methods may be added to these interfaces as they are added to the SDK, so implementations
+// outside this module should embed one, such as pinatatest.Mock, rather than implement every
+// method.
+type PinataAPI interface {
+	Pinner
+	FileManager
+	GroupManager
+	KeyManager
+	SignatureManager
+	SwapManager
+	ContentReader
+	AccountReader
+}
+
+var (
+	_ PinataAPI = (*Client)(nil)
+	_ GroupsAPI = (*GroupsClient)(nil)
+	_ KeysAPI   = (*KeysClient)(nil)
+	_ FilesAPI  = (*FilesClient)(nil)
+)
+
+// Pinner is the part of PinataAPI that pins content, by upload or by CID, and follows the pin by
+// CID jobs.
+type Pinner interface {
+	PinFile(path string, options *PinOptions) (*PinResponse, error)
+	PinFileWithContext(ctx context.Context, path string, options *PinOptions) (*PinResponse, error)
+	PinOpenFile(f *os.File, name string, options *PinOptions) (*PinResponse, error)
+	PinOpenFileWithContext(ctx context.Context, f *os.File, name string, options *PinOptions) (*PinResponse, error)
+	PinFilesAsync(paths []string, options []PinOptions) ([]*PinResponse, error)
+	PinFilesAsyncWithContext(ctx context.Context, paths []string, options []PinOptions) ([]*PinResponse, error)
+	PinURL(url string, options *PinOptions) (*PinResponse, error)
+	PinURLWithContext(ctx context.Context, url string, options *PinOptions) (*PinResponse, error)
+	PinFolder(filePaths []string, options *PinOptions) (*PinResponse, error)
+	PinFolderWithContext(ctx context.Context, filePaths []string, options *PinOptions) (*PinResponse, error)
+	PinNestedFolders(baseDir string, paths []string, options *PinOptions) (*PinResponse, error)
+	PinNestedFoldersWithContext(ctx context.Context, baseDir string, paths []string, options *PinOptions) (*PinResponse, error)
+	PinJSON(data interface{}, options *PinOptions) (*PinResponse, error)
+	PinJSONWithContext(ctx context.Context, data interface{}, options *PinOptions) (*PinResponse, error)
+	PinJSONsAsync(ctx context.Context, items []JSONPinItem, options *JSONBatchOptions) ([]JSONPinResult, error)
+	PinReader(ctx context.Context, r io.Reader, name string, options *PinOptions, readerOptions *PinReaderOptions) (*PinResponse, error)
+	PinBatch(ctx context.Context, paths []string, options *BatchOptions) ([]BatchItemResult, error)
+	PinDirectory(ctx context.Context, dir string, options *BatchOptions) ([]BatchItemResult, error)
+	PinLargeDirectory(ctx context.Context, dir string, opts LargeDirOptions) (*LargeDirResult, error)
+	MirrorURL(ctx context.Context, url string, options *MirrorOptions) (*MirrorResult, error)
+	PinByCid(hashToPin string, options *PinByCidOptions) (*PinByCidResponse, error)
+	PinByCidWithContext(ctx context.Context, hashToPin string, options *PinByCidOptions) (*PinByCidResponse, error)
+	ListPinByCidJobs(options *ListPinByCidOptions) (*ListPinByCidResponse, error)
+	ListPinByCidJobsWithContext(ctx context.Context, options *ListPinByCidOptions) (*ListPinByCidResponse, error)
+	ListPinByCidJobsPage(options *ListPinByCidOptions) (*Page[PinEntry], error)
+	ListPinByCidJobsPageWithContext(ctx context.Context, options *ListPinByCidOptions) (*Page[PinEntry], error)
+	ListPinByCidJobsAll(options *ListPinByCidOptions) ([]PinEntry, error)
+	ListPinByCidJobsAllWithContext(ctx context.Context, options *ListPinByCidOptions) ([]PinEntry, error)
+	WatchPinJobs(ctx context.Context, interval time.Duration, filter *ListPinByCidOptions) (<-chan PinJobEvent, error)
+}
+
+// FileManager is the part of PinataAPI that lists, inspects, updates and deletes pinned content.
+type FileManager interface {
+	ListFiles(options *ListFilesOptions) (*ListFilesResponse, error)
+	ListFilesWithContext(ctx context.Context, options *ListFilesOptions) (*ListFilesResponse, error)
+	ListFilesPage(options *ListFilesOptions) (*Page[Pin], error)
+	ListFilesPageWithContext(ctx context.Context, options *ListFilesOptions) (*Page[Pin], error)
+	ListFilesAll(options *ListFilesOptions) ([]Pin, error)
+	ListFilesAllWithContext(ctx context.Context, options *ListFilesOptions) ([]Pin, error)
+	StreamFiles(ctx context.Context, options *ListFilesOptions, buffer int) (<-chan Pin, <-chan error)
+	SearchFilesByName(ctx context.Context, options *NameSearchOptions) (<-chan Pin, <-chan error)
+	ListUngroupedPins(ctx context.Context, options *UngroupedOptions) (<-chan Pin, <-chan error)
+	GetPinByCid(ctx context.Context, cid string, options *PinLookupOptions) (*Pin, error)
+	IsPinned(ctx context.Context, cid string, options *PinLookupOptions) (bool, error)
+	UpdateFileMetadata(fileHash string, options *PinMetadataUpdateOptions) error
+	UpdateFileMetadataWithContext(ctx context.Context, fileHash string, options *PinMetadataUpdateOptions) error
+	DeleteFile(cid string) error
+	DeleteFileWithContext(ctx context.Context, cid string) error
+	Unpin(cid string) error
+	UnpinWithContext(ctx context.Context, cid string) error
+	DeleteFileByID(id string) error
+	DeleteFileByIDWithContext(ctx context.Context, id string) error
+	DeleteFilesAsync(cids []string) []error
+	DeleteFilesAsyncWithContext(ctx context.Context, cids []string) []error
+	ListFolderContents(ctx context.Context, cid string) ([]DirEntry, error)
+	ListFolderContentsWithOptions(ctx context.Context, cid string, options *ListFolderOptions) ([]DirEntry, error)
+	UpdateFolderFileMetadata(ctx context.Context, folder *PinResponse, files map[string]map[string]interface{}, options *FolderMetadataOptions) (*FolderMetadataReport, error)
+	DeleteFolder(ctx context.Context, cid string, options *DeleteFolderOptions) (*DeleteFolderReport, error)
+	AuditPins(ctx context.Context, options *AuditOptions) (*AuditReport, error)
+	PinStats(ctx context.Context, options *ListFilesOptions) (*PinStatsReport, error)
+	VectorizeFile(ctx context.Context, fileID string) error
+	DeleteFileVectors(ctx context.Context, fileID string) error
+	QueryVectors(ctx context.Context, groupID string, text string, topK int) (*VectorQueryResult, error)
+}
+
+// GroupManager is the part of PinataAPI that creates, lists and reconciles groups. The methods
+// addressing a single group are those of GroupsAPI.
+type GroupManager interface {
+	CreateGroup(groupName string) (*Group, error)
+	CreateGroupWithContext(ctx context.Context, groupName string) (*Group, error)
+	ListGroups(options *ListGroupsOptions) ([]Group, error)
+	ListGroupsWithContext(ctx context.Context, options *ListGroupsOptions) ([]Group, error)
+	ListGroupsPage(options *ListGroupsOptions) (*Page[Group], error)
+	ListGroupsPageWithContext(ctx context.Context, options *ListGroupsOptions) (*Page[Group], error)
+	ReconcileGroups(ctx context.Context, desired map[string][]string, options ReconcileOptions) (*ReconcileReport, error)
+}
+
+// KeyManager is the part of PinataAPI that manages API keys, with the legacy and the v3 endpoints.
+// KeysAPI covers the v3 endpoints as well.
+type KeyManager interface {
+	GenerateApiKey(options *GenerateApiKeyOptions) (*ApiKeySecret, error)
+	GenerateApiKeyWithContext(ctx context.Context, options *GenerateApiKeyOptions) (*ApiKeySecret, error)
+	GenerateApiKeyV3(options *GenerateApiKeyOptions) (*ApiKeySecret, error)
+	ListApiKeys() (*ApiKeyResponse, error)
+	ListApiKeysWithContext(ctx context.Context) (*ApiKeyResponse, error)
+	ListApiKeyV3(options *ListApiKeysOptions) (*ApiKeyResponse, error)
+	ListApiKeyV3Page(options *ListApiKeysOptions) (*Page[ApiKey], error)
+	RevokeApiKey(apiKey string) error
+	RevokeApiKeyWithContext(ctx context.Context, apiKey string) error
+	RevokeApiKeyV3(key string) error
+}
+
+// SignatureManager is the part of PinataAPI that manages the signatures of CIDs.
+type SignatureManager interface {
+	AddCidSignature(cid, signature string) (*CidSignature, error)
+	AddCidSignatureWithContext(ctx context.Context, cid, signature string) (*CidSignature, error)
+	GetCidSignature(cid string) (*CidSignature, error)
+	GetCidSignatureWithContext(ctx context.Context, cid string) (*CidSignature, error)
+	RemoveCidSignature(cid string) error
+	RemoveCidSignatureWithContext(ctx context.Context, cid string) error
+}
+
+// SwapManager is the part of PinataAPI that manages hot swaps.
+type SwapManager interface {
+	AddSwap(cid, swapCid string, options *AddSwapOptions) (*AddSwapResponse, error)
+	AddSwapWithContext(ctx context.Context, cid, swapCid string, options *AddSwapOptions) (*AddSwapResponse, error)
+	SwapTo(ctx context.Context, cid, newContentPath string) (*AddSwapResponse, error)
+	GetSwapHistory(cid, domain string) (*GetSwapResponse, error)
+	GetSwapHistoryWithContext(ctx context.Context, cid, domain string) (*GetSwapResponse, error)
+	RemoveSwap(cid string) (*RemoveSwapResponse, error)
+	RemoveSwapWithContext(ctx context.Context, cid string) (*RemoveSwapResponse, error)
+}
+
+// ContentReader is the part of PinataAPI that reads content from the gateways.
+type ContentReader interface {
+	GetContent(ctx context.Context, cid string) (*GatewayContent, error)
+	StatContent(ctx context.Context, cid string) (*GatewayStat, error)
+	HealthCheck(ctx context.Context) []GatewayHealth
+}
+
+// AccountReader is the part of PinataAPI that reports on the account and on the client itself.
+type AccountReader interface {
+	TestAuthentication() (*AuthTestResponse, error)
+	TestAuthenticationWithContext(ctx context.Context) (*AuthTestResponse, error)
+	AuthHealthCheck(ctx context.Context) AuthHealth
+	GetPinnedDataTotal(ctx context.Context) (*PinnedDataTotal, error)
+	PinnedFileCount() (int, error)
+	PinnedFileCountWithContext(ctx context.Context) (int, error)
+	TotalStorageSize() (int, int, error)
+	TotalStorageSizeWithContext(ctx context.Context) (int, int, error)
+	LastRateLimit() (RateLimitInfo, bool)
+	ActiveOperations() []ActiveOp
+}
+
+// GroupsAPI is the set of methods of GroupsClient, the sub-client returned by Client.Groups.
+type GroupsAPI interface {
+	Create(name string) (*Group, error)
+	CreateWithContext(ctx context.Context, name string) (*Group, error)
+	Get(id GroupID) (*Group, error)
+	GetWithContext(ctx context.Context, id GroupID) (*Group, error)
+	List(options *ListGroupsOptions) ([]Group, error)
+	ListWithContext(ctx context.Context, options *ListGroupsOptions) ([]Group, error)
+	Update(id GroupID, name string) (*Group, error)
+	UpdateWithContext(ctx context.Context, id GroupID, name string) (*Group, error)
+	AddCids(id GroupID, cids []Cid) error
+	AddCidsWithContext(ctx context.Context, id GroupID, cids []Cid) error
+	RemoveCids(id GroupID, cids []Cid) error
+	RemoveCidsWithContext(ctx context.Context, id GroupID, cids []Cid) error
+	Remove(id GroupID) error
+	RemoveWithContext(ctx context.Context, id GroupID) error
+	PinCid(ctx context.Context, cid Cid, id GroupID, metadata *PinataMetadata, wait bool) (*PinByCidResponse, error)
+}
+
+// KeysAPI is the set of methods of KeysClient, the sub-client returned by Client.Keys.
+type KeysAPI interface {
+	Generate(options *GenerateApiKeyOptions) (*ApiKeySecret, error)
+	GenerateWithContext(ctx context.Context, options *GenerateApiKeyOptions) (*ApiKeySecret, error)
+	List(options *ListApiKeysOptions) (*ApiKeyResponse, error)
+	ListWithContext(ctx context.Context, options *ListApiKeysOptions) (*ApiKeyResponse, error)
+	Revoke(key string) error
+	RevokeWithContext(ctx context.Context, key string) error
+	ListPage(options *ListApiKeysOptions) (*Page[ApiKey], error)
+	ListPageWithContext(ctx context.Context, options *ListApiKeysOptions) (*Page[ApiKey], error)
+}
+
+// FilesAPI is the set of methods of FilesClient, the sub-client returned by Client.Files.
+type FilesAPI interface {
+	Upload(ctx context.Context, path string, options *FileUploadOptions) (*File, error)
+	List(ctx context.Context, options *FileListOptions) (*FileList, error)
+	DownloadFile(ctx context.Context, file *File) (*GatewayContent, error)
+	CreateAccessLink(ctx context.Context, opts AccessLinkOptions) (string, error)
+	GetAccessLink(ctx context.Context, link string) (*GatewayContent, error)
+}
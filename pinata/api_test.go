@@ -0,0 +1,47 @@
+package pinata
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// apiExempt are the exported methods of Client left out of PinataAPI.
+var apiExempt = map[string]string{
+	"NewRequest":         "request builder bound to the client",
+	"NewPinQueue":        "constructor of a queue bound to the client",
+	"Files":              "sub-client accessor; see FilesAPI",
+	"Gateway":            "sub-client accessor; see ContentReader",
+	"Groups":             "sub-client accessor; see GroupsAPI",
+	"Keys":               "sub-client accessor; see KeysAPI",
+	"GetGroup":           "deprecated",
+	"UpdateGroup":        "deprecated",
+	"AddCidToGroup":      "deprecated",
+	"RemoveCidFromGroup": "deprecated",
+	"RemoveGroup":        "deprecated",
+	"PinCidToGroup":      "deprecated",
+}
+
+func TestAPIInterfaces(t *testing.T) {
+	t.Run("PinataAPI covers the methods of Client", func(t *testing.T) {
+		api := reflect.TypeOf((*PinataAPI)(nil)).Elem()
+		client := reflect.TypeOf(&Client{})
+		for i := 0; i < client.NumMethod(); i++ {
+			name := client.Method(i).Name
+			_, inAPI := api.MethodByName(name)
+			_, exempt := apiExempt[name]
+			require.Truef(t, inAPI != exempt, "Client.%s must be either in PinataAPI or in apiExempt", name)
+		}
+	})
+
+	t.Run("sub-client interfaces cover their methods", func(t *testing.T) {
+		for api, impl := range map[reflect.Type]reflect.Type{
+			reflect.TypeOf((*GroupsAPI)(nil)).Elem(): reflect.TypeOf(&GroupsClient{}),
+			reflect.TypeOf((*KeysAPI)(nil)).Elem():   reflect.TypeOf(&KeysClient{}),
+			reflect.TypeOf((*FilesAPI)(nil)).Elem():  reflect.TypeOf(&FilesClient{}),
+		} {
+			require.Equalf(t, impl.NumMethod(), api.NumMethod(), "methods of %s missing from %s", impl, api)
+		}
+	})
+}
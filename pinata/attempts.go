@@ -0,0 +1,104 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+)
+
+// AttemptsKey is the keyvalue in which attempt tracking records how many times content was
+// uploaded.
+const AttemptsKey = "sdk_attempts"
+
+// WithAttemptTracking makes the client count the uploads of the same content under the AttemptsKey
+// keyvalue, so pipelines that re-submit files can tell how often each was attempted. It applies to
+// uploads by path: PinFile, PinFilesAsync, PinBatch and PinQueue jobs.
+//
+// A first upload carries an attempt count of 1 in its metadata. Pinata does not apply the metadata
+// of an upload of content that is already pinned, so for a duplicate the count is read from the
+// pin, incremented and written back through hashMetadata. The update is a read-modify-write, not
+// an atomic increment: the count is read back after the write, and WarningAttemptsRace is emitted
+// when another upload changed it in between. The count is reported as PinResponse.Attempts and
+// BatchItemResult.Attempts.
+func WithAttemptTracking() ClientOption {
+	return func(c *Client) {
+		c.trackAttempts = true
+	}
+}
+
+// withFirstAttempt returns the options sent with an upload, carrying an attempt count of 1 when
+// attempt tracking is enabled. options, already a copy owned by the call, is not modified.
+func (c *Client) withFirstAttempt(options *PinOptions) *PinOptions {
+	if !c.trackAttempts {
+		return options
+	}
+	upload := options.Clone()
+	if upload == nil {
+		upload = &PinOptions{}
+	}
+	if upload.PinataMetadata.KeyValues == nil {
+		upload.PinataMetadata.KeyValues = make(map[string]interface{}, 1)
+	}
+	upload.PinataMetadata.KeyValues[AttemptsKey] = 1
+	return upload
+}
+
+// recordAttempt sets the attempt count of response when attempt tracking is enabled, incrementing
+// the count stored on the pin when the upload was a duplicate. Failures are reported as warnings,
+// since the upload itself succeeded.
+func (c *Client) recordAttempt(ctx context.Context, response *PinResponse) {
+	if !c.trackAttempts {
+		return
+	}
+	if !response.IsDuplicate {
+		response.Attempts = 1
+		return
+	}
+
+	attempts, err := c.storedAttempts(ctx, response.IpfsHash)
+	if err == nil {
+		attempts++
+		err = c.updateFileMetadata(ctx, response.IpfsHash, &PinMetadataUpdateOptions{
+			KeyValues: map[string]interface{}{AttemptsKey: attempts},
+		})
+	}
+	if err != nil {
+		w := Warning{
+			Code:    WarningAttemptsNotRecorded,
+			Message: "failed to record the upload attempt",
+			Detail:  map[string]interface{}{"cid": response.IpfsHash, "error": err.Error()},
+		}
+		response.Warnings = append(response.Warnings, w)
+		c.warn(w)
+		return
+	}
+	response.Attempts = attempts
+
+	stored, err := c.storedAttempts(ctx, response.IpfsHash)
+	if err == nil && stored != attempts {
+		w := Warning{
+			Code:    WarningAttemptsRace,
+			Message: "the attempt count was changed by a concurrent upload",
+			Detail:  map[string]interface{}{"cid": response.IpfsHash, "written": attempts, "stored": stored},
+		}
+		response.Warnings = append(response.Warnings, w)
+		c.warn(w)
+	}
+}
+
+// storedAttempts returns the attempt count recorded on the pin of cid. Content pinned without a
+// count was uploaded at least once, so it counts as one attempt.
+func (c *Client) storedAttempts(ctx context.Context, cid string) (int, error) {
+	row, err := c.GetPinByCid(ctx, cid, nil)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := row.KeyValues()[AttemptsKey]
+	if !ok {
+		return 1, nil
+	}
+	attempts, ok := value.AsFloat()
+	if !ok || attempts < 1 {
+		return 0, fmt.Errorf("invalid %s keyvalue %v", AttemptsKey, value.Raw())
+	}
+	return int(attempts), nil
+}
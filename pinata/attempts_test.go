@@ -0,0 +1,164 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAttempts is an in-memory Pinata pinning every upload under the same CID and keeping the
+// keyvalues of that pin.
+type fakeAttempts struct {
+	mu        sync.Mutex
+	pinned    bool
+	keyValues map[string]interface{}
+	uploads   []map[string]interface{}
+	// concurrent simulates another upload bumping the count right after each hashMetadata update
+	concurrent bool
+}
+
+func (f *fakeAttempts) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/pinning/pinFileToIPFS":
+		var metadata PinataMetadata
+		json.Unmarshal([]byte(r.FormValue("pinataMetadata")), &metadata)
+		f.uploads = append(f.uploads, metadata.KeyValues)
+		duplicate := f.pinned
+		if !f.pinned {
+			f.pinned, f.keyValues = true, metadata.KeyValues
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"IpfsHash": cidV0, "PinSize": 10, "IsDuplicate": duplicate})
+
+	case "/data/pinList":
+		row := map[string]interface{}{"ipfs_pin_hash": cidV0, "metadata": map[string]interface{}{"keyvalues": f.keyValues}}
+		if r.URL.Query().Get("pageOffset") != "" && r.URL.Query().Get("pageOffset") != "0" {
+			w.Write([]byte(`{"rows": []}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"rows": []interface{}{row}})
+
+	case "/pinning/hashMetadata":
+		var payload struct {
+			KeyValues map[string]interface{} `json:"keyvalues"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if f.keyValues == nil {
+			f.keyValues = map[string]interface{}{}
+		}
+		for k, v := range payload.KeyValues {
+			f.keyValues[k] = v
+		}
+		if f.concurrent {
+			f.keyValues[AttemptsKey] = f.keyValues[AttemptsKey].(float64) + 1
+		}
+		w.Write([]byte(`OK`))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestAttemptTracking(t *testing.T) {
+	path := filepath.Join(writeTree(t, "a.txt"), "a.txt")
+	newClient := func(t *testing.T, fake *fakeAttempts, opts ...ClientOption) *Client {
+		server := httptest.NewServer(fake)
+		t.Cleanup(server.Close)
+		client := New(NewAuthWithJWT("test_token"), opts...)
+		client.baseURL = server.URL
+		return client
+	}
+
+	t.Run("counts uploads of identical content", func(t *testing.T) {
+		fake := &fakeAttempts{}
+		client := newClient(t, fake, WithAttemptTracking())
+		options := &PinOptions{PinataMetadata: PinataMetadata{Name: "a.txt", KeyValues: map[string]interface{}{"env": "prod"}}}
+
+		first, err := client.PinFile(path, options)
+		require.NoError(t, err)
+		require.Equal(t, 1, first.Attempts)
+
+		second, err := client.PinFile(path, options)
+		require.NoError(t, err)
+		require.True(t, second.IsDuplicate)
+		require.Equal(t, 2, second.Attempts)
+
+		require.Equal(t, map[string]interface{}{"env": "prod", AttemptsKey: float64(2)}, fake.keyValues)
+		require.Equal(t, map[string]interface{}{"env": "prod"}, options.PinataMetadata.KeyValues)
+	})
+
+	t.Run("batch results", func(t *testing.T) {
+		fake := &fakeAttempts{}
+		client := newClient(t, fake, WithAttemptTracking())
+
+		results, err := client.PinBatch(context.Background(), []string{path}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, results[0].Attempts)
+
+		results, err = client.PinBatch(context.Background(), []string{path}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, results[0].Attempts)
+	})
+
+	t.Run("open files", func(t *testing.T) {
+		fake := &fakeAttempts{}
+		client := newClient(t, fake, WithAttemptTracking())
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		first, err := client.PinOpenFile(f, "", nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, first.Attempts)
+
+		second, err := client.PinOpenFile(f, "", nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, second.Attempts)
+		require.Equal(t, float64(2), fake.keyValues[AttemptsKey])
+	})
+
+	t.Run("content pinned before tracking", func(t *testing.T) {
+		fake := &fakeAttempts{pinned: true}
+		client := newClient(t, fake, WithAttemptTracking())
+
+		response, err := client.PinFile(path, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, response.Attempts)
+		require.Equal(t, float64(2), fake.keyValues[AttemptsKey])
+	})
+
+	t.Run("warns on concurrent updates", func(t *testing.T) {
+		fake := &fakeAttempts{pinned: true, keyValues: map[string]interface{}{AttemptsKey: float64(3)}, concurrent: true}
+		var warnings []Warning
+		client := newClient(t, fake, WithAttemptTracking(), WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+
+		response, err := client.PinFile(path, nil)
+		require.NoError(t, err)
+		require.Equal(t, 4, response.Attempts)
+		require.Len(t, warnings, 1)
+		require.Equal(t, WarningAttemptsRace, warnings[0].Code)
+		require.Equal(t, warnings, response.Warnings)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		fake := &fakeAttempts{}
+		client := newClient(t, fake)
+
+		for i := 0; i < 2; i++ {
+			response, err := client.PinFile(path, nil)
+			require.NoError(t, err)
+			require.Zero(t, response.Attempts)
+		}
+		require.Equal(t, []map[string]interface{}{nil, nil}, fake.uploads)
+	})
+}
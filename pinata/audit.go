@@ -0,0 +1,236 @@
+package pinata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuditIssueKind classifies a problem found while auditing a pin.
+type AuditIssueKind string
+
+const (
+	AuditIssueUnreachable  AuditIssueKind = "unreachable"
+	AuditIssueSizeMismatch AuditIssueKind = "size_mismatch"
+)
+
+// AuditOptions represents the options for auditing pinned content.
+// Concurrency is the number of pins checked concurrently (the client concurrency when
+// zero, see WithConcurrency).
+// SampleRate is the fraction (0 to 1) of pins fully downloaded to verify their byte count and
+// compute their sha256 digest; the remaining pins are only checked with a HEAD request.
+// Gateway is the gateway base URL the content is checked against (the client's gateways when empty).
+// SizeTolerance is the allowed relative difference between the pin size and the size reported by
+// the gateway, since pin sizes include DAG overhead (0 requires an exact match).
+// Filter optionally narrows the pins being audited; pagination fields are managed by the audit.
+// Progress, if set, is called after every checked pin.
+type AuditOptions struct {
+	Concurrency   int
+	SampleRate    float64
+	Gateway       string
+	SizeTolerance float64
+	Filter        *ListFilesOptions
+	Progress      func(AuditProgress)
+}
+
+// AuditProgress reports the progress of a running audit.
+// Listed is the number of pins fetched from pinList so far.
+// Checked is the number of pins checked so far.
+// Issues is the number of unreachable or mismatched pins found so far.
+type AuditProgress struct {
+	Listed  int
+	Checked int
+	Issues  int
+}
+
+// AuditIssue describes a pin that failed the audit.
+// Cid is the IPFS hash of the pin.
+// PinID is the pinList row ID of the pin.
+// Kind classifies the problem.
+// ExpectedSize is the size recorded for the pin.
+// ActualSize is the size reported (or downloaded) from the gateway, or -1 when unknown.
+// StatusCode is the HTTP status code returned by the gateway, if a response was received.
+// Error describes the problem.
+type AuditIssue struct {
+	Cid          string         `json:"cid"`
+	PinID        string         `json:"pinId,omitempty"`
+	Kind         AuditIssueKind `json:"kind"`
+	ExpectedSize int64          `json:"expectedSize"`
+	ActualSize   int64          `json:"actualSize"`
+	StatusCode   int            `json:"statusCode,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// AuditReport represents the outcome of auditing pinned content.
+// Checked is the number of pins checked.
+// Sampled is the number of pins fully downloaded for verification.
+// Unreachable lists the pins that could not be retrieved from the gateway.
+// Mismatched lists the pins whose retrieved size differs from the recorded pin size.
+// Digests maps the CIDs of sampled pins to the hex-encoded sha256 of their content.
+type AuditReport struct {
+	Checked     int               `json:"checked"`
+	Sampled     int               `json:"sampled"`
+	Unreachable []AuditIssue      `json:"unreachable,omitempty"`
+	Mismatched  []AuditIssue      `json:"mismatched,omitempty"`
+	Digests     map[string]string `json:"digests,omitempty"`
+	StartedAt   time.Time         `json:"startedAt"`
+	FinishedAt  time.Time         `json:"finishedAt"`
+}
+
+// AuditPins walks all pinned content and verifies that each CID is retrievable from the gateway and
+// that its size matches the recorded pin size.
+//
+// The pin listing is paginated while pins are checked concurrently, so memory use stays bounded by
+// the page size. Size checks are skipped for folder pins and when the gateway does not report a
+// Content-Length. When ctx is cancelled, the partial report gathered so far is returned together
+// with the context error. Gateway requests are retried only as set with WithGatewayRetryPolicy;
+// with retries enabled, each unreachable pin holds its worker for the pauses of every retry.
+func (c *Client) AuditPins(ctx context.Context, options *AuditOptions) (*AuditReport, error) {
+	if options == nil {
+		options = &AuditOptions{}
+	}
+	if options.SampleRate < 0 || options.SampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be between 0 and 1")
+	}
+
+	concurrency := c.workers(options.Concurrency)
+
+	report := &AuditReport{StartedAt: time.Now(), Digests: make(map[string]string)}
+	var mu sync.Mutex
+	var progress AuditProgress
+
+	pins := make(chan Pin, concurrency)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range pins {
+				if ctx.Err() != nil {
+					continue
+				}
+				sample := options.SampleRate > 0 && rand.Float64() < options.SampleRate
+				issue, digest := c.auditPin(ctx, options, p, sample)
+				if ctx.Err() != nil {
+					// checks interrupted by cancellation say nothing about the pin
+					continue
+				}
+
+				mu.Lock()
+				report.Checked++
+				progress.Checked++
+				if sample && issue == nil {
+					report.Sampled++
+					report.Digests[p.IPFSPinHash] = digest
+				}
+				if issue != nil {
+					progress.Issues++
+					if issue.Kind == AuditIssueUnreachable {
+						report.Unreachable = append(report.Unreachable, *issue)
+					} else {
+						report.Mismatched = append(report.Mismatched, *issue)
+					}
+				}
+				current := progress
+				mu.Unlock()
+
+				if options.Progress != nil {
+					options.Progress(current)
+				}
+			}
+		}()
+	}
+
+	listErr := c.forEachPinPage(ctx, options.Filter, func(rows []Pin) error {
+		mu.Lock()
+		progress.Listed += len(rows)
+		mu.Unlock()
+		for _, row := range rows {
+			select {
+			case pins <- row:
+			case <-ctx.Done():
+				return transportError(ctx.Err())
+			}
+		}
+		return nil
+	})
+	close(pins)
+	wg.Wait()
+
+	report.FinishedAt = time.Now()
+	if listErr != nil {
+		return report, listErr
+	}
+	if err := ctx.Err(); err != nil {
+		return report, transportError(err)
+	}
+	return report, nil
+}
+
+// auditPin checks a single pin against the gateway. Sampled pins are fully downloaded and their
+// sha256 digest is returned; other pins are probed with a HEAD request.
+func (c *Client) auditPin(ctx context.Context, options *AuditOptions, p Pin, sample bool) (*AuditIssue, string) {
+	issue := &AuditIssue{Cid: p.IPFSPinHash, PinID: p.ID, ExpectedSize: int64(p.Size), ActualSize: -1}
+
+	var size int64
+	var digest string
+	if sample {
+		ctx, deadline := c.startDownload(ctx)
+		defer deadline.stop()
+		resp, _, _, err := c.gatewayDo(ctx, options.Gateway, http.MethodGet, p.IPFSPinHash, "", nil)
+		if err != nil {
+			issue.Kind, issue.Error = AuditIssueUnreachable, deadline.err(ctx, err).Error()
+			return issue, ""
+		}
+		deadline.fit(resp.ContentLength)
+		body := deadline.body(ctx, resp.Body)
+		defer body.Close()
+		if resp.StatusCode != http.StatusOK {
+			issue.Kind, issue.StatusCode = AuditIssueUnreachable, resp.StatusCode
+			issue.Error = fmt.Sprintf("gateway returned %s", resp.Status)
+			return issue, ""
+		}
+		hasher := sha256.New()
+		size, err = io.Copy(hasher, body)
+		if err != nil {
+			issue.Kind, issue.Error = AuditIssueUnreachable, err.Error()
+			return issue, ""
+		}
+		digest = hex.EncodeToString(hasher.Sum(nil))
+	} else {
+		stat, err := c.statGateway(ctx, options.Gateway, p.IPFSPinHash, true)
+		if err != nil {
+			issue.Kind, issue.Error = AuditIssueUnreachable, err.Error()
+			return issue, ""
+		}
+		if stat.StatusCode != http.StatusOK {
+			issue.Kind, issue.StatusCode = AuditIssueUnreachable, stat.StatusCode
+			issue.Error = fmt.Sprintf("gateway returned status %d", stat.StatusCode)
+			return issue, ""
+		}
+		size = stat.Size
+	}
+
+	if p.NumberOfFiles <= 1 && size >= 0 && !sizeWithinTolerance(int64(p.Size), size, options.SizeTolerance) {
+		issue.Kind, issue.ActualSize = AuditIssueSizeMismatch, size
+		issue.Error = fmt.Sprintf("expected %d bytes, gateway reported %d", p.Size, size)
+		return issue, ""
+	}
+
+	return nil, digest
+}
+
+// sizeWithinTolerance reports whether actual is within the relative tolerance of expected.
+func sizeWithinTolerance(expected, actual int64, tolerance float64) bool {
+	diff := expected - actual
+	if diff < 0 {
+		diff = -diff
+	}
+	return float64(diff) <= float64(expected)*tolerance
+}
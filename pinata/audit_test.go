@@ -0,0 +1,145 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditServer serves pinList pages of n pins ("QmPin0".."QmPin<n-1>", 4 bytes each) and the
+// gateway content for them. CIDs listed in missing return 404 and those in resized serve 8 bytes.
+func newAuditServer(t *testing.T, n int, missing, resized map[string]bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/data/pinList":
+			require.Equal(t, "pinned", r.URL.Query().Get("status"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("pageLimit"))
+			offset, _ := strconv.Atoi(r.URL.Query().Get("pageOffset"))
+			var rows []string
+			for i := offset; i < n && i < offset+limit; i++ {
+				rows = append(rows, fmt.Sprintf(`{"id":"id%d","ipfs_pin_hash":"QmPin%d","size":4,"number_of_files":1}`, i, i))
+			}
+			fmt.Fprintf(w, `{"count":%d,"rows":[%s]}`, n, strings.Join(rows, ","))
+		case strings.HasPrefix(r.URL.Path, "/ipfs/"):
+			cid := strings.TrimPrefix(r.URL.Path, "/ipfs/")
+			if missing[cid] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			body := "abcd"
+			if resized[cid] {
+				body = "abcdefgh"
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodGet {
+				w.Write([]byte(body))
+			}
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestAuditPins(t *testing.T) {
+	t.Run("reports unreachable and mismatched pins", func(t *testing.T) {
+		mockServer := newAuditServer(t, 5, map[string]bool{"QmPin1": true}, map[string]bool{"QmPin3": true})
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		var calls int32
+		report, err := client.AuditPins(context.Background(), &AuditOptions{
+			Concurrency: 2,
+			Gateway:     mockServer.URL,
+			Progress: func(p AuditProgress) {
+				atomic.AddInt32(&calls, 1)
+				require.LessOrEqual(t, p.Checked, p.Listed)
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 5, report.Checked)
+		require.Equal(t, int32(5), atomic.LoadInt32(&calls))
+		require.Len(t, report.Unreachable, 1)
+		require.Equal(t, "QmPin1", report.Unreachable[0].Cid)
+		require.Equal(t, http.StatusNotFound, report.Unreachable[0].StatusCode)
+		require.Len(t, report.Mismatched, 1)
+		require.Equal(t, "QmPin3", report.Mismatched[0].Cid)
+		require.Equal(t, int64(4), report.Mismatched[0].ExpectedSize)
+		require.Equal(t, int64(8), report.Mismatched[0].ActualSize)
+		require.Zero(t, report.Sampled)
+	})
+
+	t.Run("full verification of sampled pins", func(t *testing.T) {
+		mockServer := newAuditServer(t, 3, nil, nil)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		report, err := client.AuditPins(context.Background(), &AuditOptions{
+			SampleRate: 1,
+			Gateway:    mockServer.URL,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Sampled)
+		require.Len(t, report.Digests, 3)
+		// sha256("abcd")
+		require.Equal(t, "88d4266fd4e6338d13b845fcf289579d209c897823b9217da3e161936f031589", report.Digests["QmPin0"])
+	})
+
+	t.Run("paginates large accounts", func(t *testing.T) {
+		mockServer := newAuditServer(t, MaxPinListPageLimit+10, nil, nil)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		report, err := client.AuditPins(context.Background(), &AuditOptions{Concurrency: 20, Gateway: mockServer.URL})
+
+		require.NoError(t, err)
+		require.Equal(t, MaxPinListPageLimit+10, report.Checked)
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		mockServer := newAuditServer(t, 50, nil, nil)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		ctx, cancel := context.WithCancel(context.Background())
+		report, err := client.AuditPins(ctx, &AuditOptions{
+			Concurrency: 1,
+			Gateway:     mockServer.URL,
+			Progress: func(p AuditProgress) {
+				if p.Checked == 2 {
+					cancel()
+				}
+			},
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotNil(t, report)
+		require.Less(t, report.Checked, 50)
+	})
+
+	t.Run("invalid sample rate", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		report, err := client.AuditPins(context.Background(), &AuditOptions{SampleRate: 2})
+
+		require.Error(t, err)
+		require.Nil(t, report)
+	})
+}
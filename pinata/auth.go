@@ -1,7 +1,13 @@
 package pinata
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 )
 
 // Auth represents the authentication credentials for the Pinata API.
@@ -44,3 +50,91 @@ func (a *Auth) setAuthHeader(req *http.Request) {
 	req.Header.Set("pinata_api_key", a.apiKey)
 	req.Header.Set("pinata_secret_api_key", a.apiSecret)
 }
+
+// keyID returns the API key the credentials belong to: the API key itself, or the scopedKeyKey
+// claim of a JWT issued by Pinata. It returns "" when the key is unknown; the JWT is not verified.
+func (a *Auth) keyID() string {
+	if a == nil {
+		return ""
+	}
+	if a.jwt == "" {
+		return a.apiKey
+	}
+	parts := strings.Split(a.jwt, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		ScopedKeyKey string `json:"scopedKeyKey"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	return claims.ScopedKeyKey
+}
+
+// authRefresh coordinates credential refreshes so that concurrent 401 responses share a single
+// invocation of the client's OnUnauthorized hook.
+type authRefresh struct {
+	mu       sync.Mutex
+	inflight *authRefreshCall
+}
+
+// authRefreshCall represents a refresh in progress. done is closed once auth and err are set.
+type authRefreshCall struct {
+	done chan struct{}
+	auth *Auth
+	err  error
+}
+
+// currentAuth returns the credentials currently used by the client.
+func (c *Client) currentAuth() *Auth {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.auth
+}
+
+// refreshAuth returns fresh credentials after a request authenticated with stale was rejected.
+// If the credentials were already replaced since stale was used, the current credentials are
+// returned without invoking the hook. Otherwise the hook is invoked once for all concurrent callers.
+func (c *Client) refreshAuth(ctx context.Context, stale *Auth) (*Auth, error) {
+	c.refresh.mu.Lock()
+	if current := c.currentAuth(); current != stale {
+		c.refresh.mu.Unlock()
+		return current, nil
+	}
+	call := c.refresh.inflight
+	if call == nil {
+		call = &authRefreshCall{done: make(chan struct{})}
+		c.refresh.inflight = call
+		go func() {
+			auth, err := c.onUnauthorized(context.WithoutCancel(ctx))
+			if err == nil && auth == nil {
+				err = fmt.Errorf("unauthorized hook returned no credentials")
+			}
+			if err == nil {
+				c.authMu.Lock()
+				c.auth = auth
+				c.authMu.Unlock()
+			}
+
+			c.refresh.mu.Lock()
+			call.auth, call.err = auth, err
+			c.refresh.inflight = nil
+			c.refresh.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	c.refresh.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.auth, call.err
+	case <-ctx.Done():
+		return nil, transportError(ctx.Err())
+	}
+}
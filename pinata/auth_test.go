@@ -1,8 +1,15 @@
 package pinata
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -137,3 +144,128 @@ func TestSetAuthHeader(t *testing.T) {
 		require.Empty(t, req.Header.Get("pinata_secret_api_key"))
 	})
 }
+
+func TestOnUnauthorized(t *testing.T) {
+	newServer := func(validToken string, requests *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(requests, 1)
+			if r.Header.Get("Authorization") != "Bearer "+validToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Invalid authentication credentials"}`))
+				return
+			}
+			if r.Method == http.MethodPost {
+				body, _ := io.ReadAll(r.Body)
+				require.Equal(t, `{"name":"group"}`, string(body))
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(fixture(t, "GET /data/testAuthentication"))
+		}))
+	}
+
+	t.Run("refreshes credentials and retries once", func(t *testing.T) {
+		var requests int32
+		mockServer := newServer("fresh_token", &requests)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+			return NewAuthWithJWT("fresh_token"), nil
+		}))
+		client.baseURL = mockServer.URL
+
+		response, err := client.TestAuthentication()
+
+		require.NoError(t, err)
+		require.Equal(t, "Congratulations! You are communicating with the Pinata API!", response.Message)
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+		require.Equal(t, "fresh_token", client.currentAuth().jwt)
+	})
+
+	t.Run("replays request body on retry", func(t *testing.T) {
+		var requests int32
+		mockServer := newServer("fresh_token", &requests)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+			return NewAuthWithJWT("fresh_token"), nil
+		}))
+		client.baseURL = mockServer.URL
+
+		req, err := client.NewRequest(http.MethodPost, "/groups").SetJSONBody(map[string]string{"name": "group"})
+		require.NoError(t, err)
+
+		err = req.Send(nil)
+
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("repeated 401 fails normally", func(t *testing.T) {
+		var requests, refreshes int32
+		mockServer := newServer("never_valid", &requests)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+			atomic.AddInt32(&refreshes, 1)
+			return NewAuthWithJWT("still_invalid"), nil
+		}))
+		client.baseURL = mockServer.URL
+
+		response, err := client.TestAuthentication()
+
+		require.Error(t, err)
+		require.Nil(t, response)
+		require.Contains(t, err.Error(), "Invalid authentication credentials")
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+		require.Equal(t, int32(1), atomic.LoadInt32(&refreshes))
+	})
+
+	t.Run("hook error", func(t *testing.T) {
+		var requests int32
+		mockServer := newServer("fresh_token", &requests)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+			return nil, errors.New("vault unavailable")
+		}))
+		client.baseURL = mockServer.URL
+
+		response, err := client.TestAuthentication()
+
+		require.Error(t, err)
+		require.Nil(t, response)
+		require.Contains(t, err.Error(), "vault unavailable")
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("concurrent 401s share one refresh", func(t *testing.T) {
+		var requests, refreshes int32
+		mockServer := newServer("fresh_token", &requests)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+			atomic.AddInt32(&refreshes, 1)
+			time.Sleep(50 * time.Millisecond)
+			return NewAuthWithJWT("fresh_token"), nil
+		}))
+		client.baseURL = mockServer.URL
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.TestAuthentication()
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(1), atomic.LoadInt32(&refreshes))
+	})
+}
@@ -0,0 +1,83 @@
+package pinata
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityAuthFallback)
+}
+
+// DefaultAuthFallbackTTL is how long a client that fell back to its API key keeps using it when no
+// TTL is set with WithAuthFallback.
+const DefaultAuthFallbackTTL = 5 * time.Minute
+
+// authFallback remembers the credentials whose JWT was rejected, and until when requests are sent
+// with their API key and secret instead.
+type authFallback struct {
+	enabled bool
+	ttl     time.Duration
+	mu      sync.Mutex
+	auth    *Auth
+	until   time.Time
+}
+
+// WithAuthFallback makes a client whose credentials hold both a JWT and an API key and secret
+// fall back to the API key when the JWT is rejected, as when it was revoked mid-rotation. A
+// request rejected with 401 while sent with the JWT is retried once with the API key headers, and
+// a WarningJWTRejected is emitted. Later requests go straight to the API key for ttl
+// (DefaultAuthFallbackTTL when zero), after which the JWT is tried again. Credentials returned by
+// the WithOnUnauthorized hook start with their JWT.
+//
+// The fallback is tried before the WithOnUnauthorized hook, which is only called if the API key is
+// rejected as well. Requests whose body cannot be replayed are not retried, and the fallback has no
+// effect with AuthStyleBearer and AuthStyleNone.
+func WithAuthFallback(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl <= 0 {
+			ttl = DefaultAuthFallbackTTL
+		}
+		c.authFallback.enabled = true
+		c.authFallback.ttl = ttl
+	}
+}
+
+// fallingBack reports whether requests authenticated with auth are sent with its API key.
+func (c *Client) fallingBack(auth *Auth) bool {
+	f := &c.authFallback
+	if !f.enabled {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.auth == auth && c.now().Before(f.until)
+}
+
+// canFallBack reports whether req, sent with the JWT of auth and rejected with 401, may be retried
+// with the API key of auth.
+func (c *Client) canFallBack(auth *Auth, req *http.Request) bool {
+	return c.authFallback.enabled && c.authStyle == AuthStyleAuto &&
+		auth.jwt != "" && auth.apiKey != "" && auth.apiSecret != "" &&
+		req.Header.Get("Authorization") != "" &&
+		(!hasBody(req) || req.GetBody != nil)
+}
+
+// retryWithAPIKey retries req, whose JWT was rejected with resp, with the API key of auth, and
+// sends the requests of the next TTL with the API key as well.
+func (rb *requestBuilder) retryWithAPIKey(req *http.Request, resp *http.Response, auth *Auth) (*http.Response, error) {
+	f := &rb.client.authFallback
+	f.mu.Lock()
+	f.auth, f.until = auth, rb.client.now().Add(f.ttl)
+	f.mu.Unlock()
+
+	rb.warn(Warning{
+		Code:    WarningJWTRejected,
+		Message: "the JWT was rejected, falling back to the API key",
+		Detail:  map[string]interface{}{"path": rb.path, "ttl": f.ttl.String()},
+	})
+
+	resp.Body.Close()
+	return rb.resend(req, auth)
+}
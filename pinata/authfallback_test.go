@@ -0,0 +1,143 @@
+package pinata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// credentialServer is a mock API server that accepts the API key "key" with the secret "secret",
+// and the JWT "jwt" while jwtValid is set. It records the credentials of every request.
+type credentialServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	jwtValid bool
+	seen     []string
+}
+
+func newCredentialServer(t *testing.T) *credentialServer {
+	s := &credentialServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			s.seen = append(s.seen, auth)
+			if auth != "Bearer jwt" || !s.jwtValid {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":{"reason":"INVALID_CREDENTIALS"}}`))
+				return
+			}
+		} else {
+			s.seen = append(s.seen, "key "+r.Header.Get("pinata_api_key"))
+			if r.Header.Get("pinata_api_key") != "key" || r.Header.Get("pinata_secret_api_key") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		serveFixture(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// credentials returns the credentials seen since the last call.
+func (s *credentialServer) credentials() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := s.seen
+	s.seen = nil
+	return seen
+}
+
+func TestAuthFallback(t *testing.T) {
+	t.Run("falls back to the API key", func(t *testing.T) {
+		server := newCredentialServer(t)
+		var warnings []Warning
+		client := New(NewAuth("key", "secret", "jwt"), WithAuthFallback(time.Minute), WithWarningHandler(func(w Warning) {
+			warnings = append(warnings, w)
+		}))
+		client.baseURL = server.URL
+		client.clock = newFakeClock()
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+		require.Equal(t, []string{"Bearer jwt", "key key"}, server.credentials())
+		require.Len(t, warnings, 1)
+		require.Equal(t, WarningJWTRejected, warnings[0].Code)
+		require.Equal(t, "1m0s", warnings[0].Detail["ttl"])
+
+		// later requests go straight to the API key
+		_, err = client.TestAuthentication()
+		require.NoError(t, err)
+		require.Equal(t, []string{"key key"}, server.credentials())
+		require.Len(t, warnings, 1)
+	})
+
+	t.Run("tries the JWT again once the TTL expired", func(t *testing.T) {
+		server := newCredentialServer(t)
+		clk := newFakeClock()
+		client := New(NewAuth("key", "secret", "jwt"), WithAuthFallback(0))
+		client.baseURL = server.URL
+		client.clock = clk
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+		require.Equal(t, []string{"Bearer jwt", "key key"}, server.credentials())
+
+		clk.Advance(DefaultAuthFallbackTTL - time.Second)
+		_, err = client.TestAuthentication()
+		require.NoError(t, err)
+		require.Equal(t, []string{"key key"}, server.credentials())
+
+		server.mu.Lock()
+		server.jwtValid = true
+		server.mu.Unlock()
+		clk.Advance(time.Second)
+		_, err = client.TestAuthentication()
+		require.NoError(t, err)
+		require.Equal(t, []string{"Bearer jwt"}, server.credentials())
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		server := newCredentialServer(t)
+		client := New(NewAuth("key", "secret", "jwt"))
+		client.baseURL = server.URL
+
+		_, err := client.TestAuthentication()
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		require.Equal(t, []string{"Bearer jwt"}, server.credentials())
+	})
+
+	t.Run("needs an API key", func(t *testing.T) {
+		server := newCredentialServer(t)
+		client := New(NewAuthWithJWT("jwt"), WithAuthFallback(time.Minute))
+		client.baseURL = server.URL
+
+		_, err := client.TestAuthentication()
+		require.Error(t, err)
+		require.Equal(t, []string{"Bearer jwt"}, server.credentials())
+	})
+
+	t.Run("rejected API key", func(t *testing.T) {
+		server := newCredentialServer(t)
+		client := New(NewAuth("other", "secret", "jwt"), WithAuthFallback(time.Minute))
+		client.baseURL = server.URL
+
+		_, err := client.TestAuthentication()
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		require.Equal(t, []string{"Bearer jwt", "key other"}, server.credentials())
+
+		// the API key is not retried with the JWT
+		_, err = client.TestAuthentication()
+		require.Error(t, err)
+		require.Equal(t, []string{"key other"}, server.credentials())
+	})
+}
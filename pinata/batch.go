@@ -0,0 +1,430 @@
+package pinata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"text/template"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityBatch)
+}
+
+// ErrNameCollision is returned when two files of a batch derive the same metadata name.
+var ErrNameCollision = errors.New("metadata name collision")
+
+// NameData is the data available to BatchOptions.NameTemplate, PinOptions.NameTemplate and
+// PinOptions.PartNameTemplate when deriving a per-file name.
+// Index is the position of the file in the batch.
+// Path is the file path as provided to the batch.
+// Rel is the slash-separated path relative to BatchOptions.BaseDir (or Path when no base dir is set).
+// For PinFilesAsync, Rel is Path; for PinFolder it is the base name of the file, and for
+// PinNestedFolders the path relative to the base dir.
+// Dir is the directory part of Rel ("." for files at the root).
+// Base is the last element of Rel.
+// Ext is the file name extension of Base, including the dot.
+type NameData struct {
+	Index int
+	Path  string
+	Rel   string
+	Dir   string
+	Base  string
+	Ext   string
+}
+
+// BatchOptions represents the options for pinning a batch of files to Pinata, one pin per file.
+// Metadata is the batch-level metadata; its keyvalues are merged into every file's metadata.
+// PinataOptions contains options specific to the Pinata platform, such as the CID version.
+// NameTemplate is a text/template executed with NameData to derive each file's metadata name,
+// e.g. "{{.Dir}}/{{.Base}}".
+// NameFunc derives each file's metadata name from its path and takes precedence over NameTemplate.
+// KeyValuesFunc returns per-file keyvalues that override the batch-level keyvalues.
+// BaseDir is the directory relative paths are computed from.
+// Concurrency is the number of concurrent uploads (the client concurrency when zero, see
+// WithConcurrency).
+// FailFast stops scheduling new uploads after the first failure.
+// PerJobTimeout bounds each upload on its own; an upload running longer fails with an error
+// wrapping context.DeadlineExceeded while the rest of the batch proceeds. Zero means no limit.
+// Checkpoint records completed uploads so they are skipped when the batch is re-run; it defaults
+// to the client's CheckpointStore.
+// CheckpointKey derives the checkpoint key of a file; by default the key is built from the file's
+// absolute path, size and modification time.
+// IgnoreQuota skips the storage quota check configured with WithQuotaGuard, for emergencies.
+// SkipInvalid skips the files failing the pre-flight check instead of failing the batch; each is
+// reported as a WarningFileSkipped and by a result whose Err wraps ErrPreflight.
+type BatchOptions struct {
+	Metadata      PinataMetadata
+	PinataOptions Options
+	NameTemplate  string
+	NameFunc      func(path string) string
+	KeyValuesFunc func(path string) map[string]interface{}
+	BaseDir       string
+	Concurrency   int
+	FailFast      bool
+	PerJobTimeout time.Duration
+	Checkpoint    CheckpointStore
+	CheckpointKey func(path string) string
+	IgnoreQuota   bool
+	SkipInvalid   bool
+}
+
+// BatchItemResult represents the outcome of pinning a single file of a batch.
+// Index is the position of the file in the batch.
+// Path is the file path as provided to the batch.
+// Name is the metadata name the file was pinned with.
+// Response is the pin response, or nil if the pin failed.
+// Skipped reports whether the file was not uploaded because a checkpoint recorded it as pinned.
+// Attempts is the number of uploads of the file's content recorded when the client tracks attempts
+// (see WithAttemptTracking); it is zero for skipped and failed files.
+// Err is the error that occurred while pinning the file, if any.
+type BatchItemResult struct {
+	Index    int
+	Path     string
+	Name     string
+	Response *PinResponse
+	Skipped  bool
+	Attempts int
+	Err      error
+}
+
+// batchJob represents a single upload of a batch, carrying the per-file options derived for it.
+// A job of PinJSONsAsync pins its document instead of a file; its path only names the item in
+// errors.
+type batchJob struct {
+	index         int
+	path          string
+	document      *JSONPinItem
+	options       *PinOptions
+	checkpointKey string
+}
+
+// PinBatch pins each of the given files to IPFS as its own pin, using a bounded worker pool.
+//
+// Per-file metadata names are derived from options.NameFunc or options.NameTemplate, and the
+// batch-level keyvalues are merged with any per-file keyvalues. Name collisions are detected before
+// any upload starts and reported as an error wrapping ErrNameCollision.
+//
+// The returned slice has one result per path, in input order. When options.FailFast is set, the first
+// failure cancels the remaining uploads and is returned as the error; otherwise failures are only
+// reported through the per-item Err fields.
+//
+// With a CheckpointStore, completed uploads are recorded as they finish and files already recorded
+// are reported as Skipped instead of being uploaded again.
+//
+// Before any upload starts, a pre-flight check stats and opens every file. Directories and other
+// non-regular files, unreadable files and empty files make the batch fail with a *PreflightError
+// listing all of them, unless options.SkipInvalid is set, in which case they are skipped.
+//
+// With a quota guard (see WithQuotaGuard), a batch that would exceed the storage quota fails with
+// a *QuotaExceededError before any upload starts, unless options.IgnoreQuota is set.
+func (c *Client) PinBatch(ctx context.Context, paths []string, options *BatchOptions) ([]BatchItemResult, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("at least one filepath is required")
+	}
+	if options == nil {
+		options = &BatchOptions{}
+	}
+	return c.pinBatch(ctx, paths, "paths", nil, options)
+}
+
+// pinBatch pins paths as PinBatch does. source tells why the paths are part of the batch, for the
+// pre-flight report, and walkIssues are the issues found while listing them.
+func (c *Client) pinBatch(ctx context.Context, paths []string, source string, walkIssues []PreflightIssue, options *BatchOptions) ([]BatchItemResult, error) {
+	jobs, err := options.plan(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := append(walkIssues, preflight(jobs, source)...)
+	if len(issues) > 0 && !options.SkipInvalid {
+		return nil, &PreflightError{Issues: issues}
+	}
+	skipped := make(map[int]PreflightIssue, len(issues))
+	for _, issue := range issues {
+		if issue.Index < 0 {
+			c.warnPreflight(issue)
+			continue
+		}
+		skipped[issue.Index] = issue
+	}
+	upload := jobs
+	if len(skipped) > 0 {
+		upload = make([]*batchJob, 0, len(jobs)-len(skipped))
+		for _, job := range jobs {
+			if _, ok := skipped[job.index]; !ok {
+				upload = append(upload, job)
+			}
+		}
+	}
+
+	store := options.Checkpoint
+	if store == nil {
+		store = c.checkpoints
+	}
+
+	if !options.IgnoreQuota {
+		if err := c.checkQuota(ctx, upload, store); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := c.runBatch(ctx, upload, len(jobs), store, options)
+	for index, issue := range skipped {
+		results[index] = c.skippedResult(jobs[index], issue)
+	}
+	if store != nil {
+		if ferr := flushCheckpoints(store); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return results, err
+}
+
+// PinDirectory walks dir and pins every regular file beneath it as its own pin using PinBatch.
+// Symbolic links and other non-regular files are skipped, each reported as a WarningFileSkipped to
+// the client's warning handler. Subdirectories that cannot be read are reported by the pre-flight
+// check, along with the files found unfit for upload. options.BaseDir defaults to dir, so
+// NameTemplate paths are relative to the directory being pinned.
+func (c *Client) PinDirectory(ctx context.Context, dir string, options *BatchOptions) ([]BatchItemResult, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+
+	source := "walk of " + dir
+	var paths []string
+	var walkIssues []PreflightIssue
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir || !errors.Is(err, fs.ErrPermission) {
+				return err
+			}
+			walkIssues = append(walkIssues, PreflightIssue{Index: -1, Path: p, Source: source, Problem: ProblemPermissionDenied, Err: err})
+			return nil
+		}
+		switch {
+		case d.Type().IsRegular():
+			paths = append(paths, p)
+		case !d.IsDir():
+			c.warn(Warning{
+				Code:    WarningFileSkipped,
+				Message: "skipping non-regular file",
+				Detail:  map[string]interface{}{"path": p, "mode": d.Type().String()},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	opts := BatchOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.BaseDir == "" {
+		opts.BaseDir = dir
+	}
+
+	if len(paths) == 0 {
+		if len(walkIssues) > 0 && !opts.SkipInvalid {
+			return nil, &PreflightError{Issues: walkIssues}
+		}
+		return nil, fmt.Errorf("no files found in %s", dir)
+	}
+	return c.pinBatch(ctx, paths, source, walkIssues, &opts)
+}
+
+// plan derives the per-file pin options of a batch and detects metadata name collisions.
+func (o *BatchOptions) plan(paths []string) ([]*batchJob, error) {
+	names := newNamer()
+	jobs := make([]*batchJob, len(paths))
+	for i, p := range paths {
+		name, ok, err := names.derive(nameData(i, p, o.BaseDir), o.NameFunc, o.NameTemplate)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			name = o.Metadata.Name
+		}
+
+		jobs[i] = &batchJob{
+			index: i,
+			path:  p,
+			options: &PinOptions{
+				PinataMetadata: PinataMetadata{Name: name, KeyValues: mergeKeyValues(o.Metadata.KeyValues, o.KeyValuesFunc, p)},
+				PinataOptions:  o.PinataOptions,
+			},
+		}
+		if o.CheckpointKey != nil {
+			jobs[i].checkpointKey = o.CheckpointKey(p)
+		}
+	}
+
+	return jobs, nil
+}
+
+// mergeKeyValues returns a copy of keyValues overridden by the keyvalues keyValuesFunc returns for
+// the file at p, or nil when the result is empty.
+func mergeKeyValues(keyValues map[string]interface{}, keyValuesFunc func(path string) map[string]interface{}, p string) map[string]interface{} {
+	merged := make(map[string]interface{}, len(keyValues))
+	for k, v := range keyValues {
+		merged[k] = cloneValue(v)
+	}
+	if keyValuesFunc != nil {
+		for k, v := range keyValuesFunc(p) {
+			merged[k] = cloneValue(v)
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// nameData builds the NameTemplate data for the file p at index i, whose Rel is relative to
+// baseDir when it is set.
+func nameData(i int, p, baseDir string) NameData {
+	rel := p
+	if baseDir != "" {
+		if r, err := filepath.Rel(baseDir, p); err == nil {
+			rel = r
+		}
+	}
+	rel = filepath.ToSlash(rel)
+
+	return NameData{
+		Index: i,
+		Path:  p,
+		Rel:   rel,
+		Dir:   path.Dir(rel),
+		Base:  path.Base(rel),
+		Ext:   path.Ext(rel),
+	}
+}
+
+// namer derives per-file names from a NameFunc or a NameTemplate, parsing each template once and
+// detecting the collisions between the names it derives.
+type namer struct {
+	templates map[string]*template.Template
+	seen      map[string]string
+}
+
+// newNamer returns a namer that has derived no name yet.
+func newNamer() *namer {
+	return &namer{templates: make(map[string]*template.Template), seen: make(map[string]string)}
+}
+
+// derive derives the name of the file described by data using nameFunc or, when nil, by executing
+// nameTemplate with data. ok is false when neither is set. A non-empty name already derived for
+// another file is reported as an error wrapping ErrNameCollision.
+func (n *namer) derive(data NameData, nameFunc func(path string) string, nameTemplate string) (name string, ok bool, err error) {
+	switch {
+	case nameFunc != nil:
+		name = nameFunc(data.Path)
+	case nameTemplate != "":
+		tmpl, parsed := n.templates[nameTemplate]
+		if !parsed {
+			tmpl, err = template.New("name").Option("missingkey=error").Parse(nameTemplate)
+			if err != nil {
+				return "", false, fmt.Errorf("invalid name template: %w", err)
+			}
+			n.templates[nameTemplate] = tmpl
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", false, fmt.Errorf("failed to execute name template for %s: %w", data.Path, err)
+		}
+		name = buf.String()
+	default:
+		return "", false, nil
+	}
+
+	if name != "" {
+		if other, ok := n.seen[name]; ok {
+			return "", false, fmt.Errorf("%w: %q derived for both %s and %s", ErrNameCollision, name, other, data.Path)
+		}
+		n.seen[name] = data.Path
+	}
+	return name, true, nil
+}
+
+// runBatch uploads the jobs using a pool of workers and returns size results, in which the result
+// of each job is at its index. When options.FailFast is set, the first failure cancels the
+// remaining jobs and is returned as the error. Completed uploads are recorded in store, which may
+// be nil.
+func (c *Client) runBatch(ctx context.Context, jobs []*batchJob, size int, store CheckpointStore, options *BatchOptions) ([]BatchItemResult, error) {
+	concurrency, failFast := c.workers(options.Concurrency), options.FailFast
+	numWorkers := min(len(jobs), concurrency)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]BatchItemResult, size)
+	queue := make(chan *batchJob, len(jobs))
+	done := make(chan int, len(jobs))
+
+	// start worker pool
+	for w := 0; w < numWorkers; w++ {
+		go batchWorker(ctx, c, store, options.PerJobTimeout, queue, results, done)
+	}
+
+	// send jobs to workers
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+
+	// collect results
+	var firstErr error
+	for range jobs {
+		i := <-done
+		if failFast && firstErr == nil && results[i].Err != nil {
+			firstErr = fmt.Errorf("failed to pin %s: %w", results[i].Path, results[i].Err)
+			cancel()
+		}
+	}
+
+	return results, firstErr
+}
+
+// batchWorker pins the files and documents received from the jobs channel and records each result
+// at its index. Jobs received after ctx is done are not uploaded and fail with the context error.
+// Each upload runs with its own deadline when timeout is positive. Documents are encoded into a
+// buffer reused across the jobs of the worker.
+func batchWorker(ctx context.Context, c *Client, store CheckpointStore, timeout time.Duration, jobs <-chan *batchJob, results []BatchItemResult, done chan<- int) {
+	var buf bytes.Buffer
+	for job := range jobs {
+		result := BatchItemResult{
+			Index: job.index,
+			Path:  job.path,
+		}
+		if job.options != nil {
+			result.Name = job.options.PinataMetadata.Name
+		}
+		if err := ctx.Err(); err != nil {
+			result.Err = transportError(err)
+		} else {
+			jobCtx, cancel := ctx, context.CancelFunc(func() {})
+			if timeout > 0 {
+				jobCtx, cancel = context.WithTimeout(ctx, timeout)
+			}
+			if job.document != nil {
+				result.Response, result.Err = c.pinJSONBuffered(jobCtx, job.document.Data, job.options, &buf)
+			} else {
+				result.Response, result.Skipped, result.Err = c.pinFileCheckpointed(jobCtx, store, job.checkpointKey, job.path, job.options)
+			}
+			cancel()
+			if result.Response != nil && !result.Skipped {
+				result.Attempts = result.Response.Attempts
+			}
+		}
+		results[job.index] = result
+		done <- job.index
+	}
+}
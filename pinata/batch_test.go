@@ -0,0 +1,419 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+// writeTree creates the given files (slash-separated paths relative to the returned dir).
+func writeTree(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte("content of "+f), 0644))
+	}
+	return dir
+}
+
+// metadataRecorder is a mock pinning server that records the pinataMetadata it receives.
+type metadataRecorder struct {
+	mu       sync.Mutex
+	metadata []PinataMetadata
+}
+
+func (m *metadataRecorder) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+
+		var metadata PinataMetadata
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("pinataMetadata")), &metadata))
+
+		m.mu.Lock()
+		m.metadata = append(m.metadata, metadata)
+		m.mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}
+}
+
+func (m *metadataRecorder) names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var names []string
+	for _, md := range m.metadata {
+		names = append(names, md.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPinDirectoryNameTemplate(t *testing.T) {
+	t.Run("nested tree with template", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "docs/b.md", "docs/deep/c.json")
+
+		recorder := &metadataRecorder{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinDirectory(context.Background(), dir, &BatchOptions{
+			NameTemplate: "release/{{.Rel}}",
+			Metadata: PinataMetadata{
+				KeyValues: map[string]interface{}{"release": "2024.1", "env": "prod"},
+			},
+			KeyValuesFunc: func(path string) map[string]interface{} {
+				return map[string]interface{}{"env": "staging", "ext": filepath.Ext(path)}
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		for _, result := range results {
+			require.NoError(t, result.Err)
+			require.Equal(t, fixtures.Cid, result.Response.IpfsHash)
+		}
+		require.Equal(t, []string{"release/a.txt", "release/docs/b.md", "release/docs/deep/c.json"}, recorder.names())
+
+		for _, md := range recorder.metadata {
+			require.Equal(t, "2024.1", md.KeyValues["release"])
+			require.Equal(t, "staging", md.KeyValues["env"])
+			require.Equal(t, filepath.Ext(md.Name), md.KeyValues["ext"])
+		}
+	})
+
+	t.Run("dir and base fields", func(t *testing.T) {
+		dir := writeTree(t, "x/one.txt", "y/two.txt")
+
+		recorder := &metadataRecorder{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		_, err := client.PinDirectory(context.Background(), dir, &BatchOptions{
+			NameTemplate: "{{.Dir}}/{{.Base}}",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"x/one.txt", "y/two.txt"}, recorder.names())
+	})
+
+	t.Run("name func collision", func(t *testing.T) {
+		dir := writeTree(t, "x/same.txt", "y/same.txt")
+
+		called := false
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinDirectory(context.Background(), dir, &BatchOptions{
+			NameFunc: filepath.Base,
+		})
+
+		require.Error(t, err)
+		require.Nil(t, results)
+		require.ErrorIs(t, err, ErrNameCollision)
+		require.Contains(t, err.Error(), "same.txt")
+		require.False(t, called)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		results, err := client.PinBatch(context.Background(), []string{"a.txt"}, &BatchOptions{
+			NameTemplate: "{{.Unknown",
+		})
+
+		require.Error(t, err)
+		require.Nil(t, results)
+		require.Contains(t, err.Error(), "invalid name template")
+	})
+}
+
+func TestPinFilesAsyncNameTemplate(t *testing.T) {
+	dir := writeTree(t, "a.txt", "docs/b.md", "docs/deep/c.json")
+	paths := []string{
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "docs", "b.md"),
+		filepath.Join(dir, "docs", "deep", "c.json"),
+	}
+
+	t.Run("nested tree", func(t *testing.T) {
+		recorder := &metadataRecorder{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		options := PinOptions{
+			PinataMetadata: PinataMetadata{
+				KeyValues: map[string]interface{}{"release": "2024.1", "env": "prod"},
+			},
+			NameFunc: func(path string) string {
+				rel, err := filepath.Rel(dir, path)
+				require.NoError(t, err)
+				return "release/" + filepath.ToSlash(rel)
+			},
+			KeyValuesFunc: func(path string) map[string]interface{} {
+				return map[string]interface{}{"env": "staging", "ext": filepath.Ext(path)}
+			},
+		}
+		responses, err := client.PinFilesAsync(paths, []PinOptions{options, options, options})
+
+		require.NoError(t, err)
+		require.Len(t, responses, 3)
+		require.Equal(t, []string{"release/a.txt", "release/docs/b.md", "release/docs/deep/c.json"}, recorder.names())
+		for _, md := range recorder.metadata {
+			require.Equal(t, "2024.1", md.KeyValues["release"])
+			require.Equal(t, "staging", md.KeyValues["env"])
+			require.Equal(t, filepath.Ext(md.Name), md.KeyValues["ext"])
+		}
+		require.Equal(t, "prod", options.PinataMetadata.KeyValues["env"])
+	})
+
+	t.Run("template", func(t *testing.T) {
+		recorder := &metadataRecorder{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		options := PinOptions{NameTemplate: "{{.Index}}-{{.Base}}"}
+		_, err := client.PinFilesAsync(paths, []PinOptions{options, options, options})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"0-a.txt", "1-b.md", "2-c.json"}, recorder.names())
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		called := false
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		options := PinOptions{NameTemplate: "{{.Ext}}"}
+		responses, err := client.PinFilesAsync(append(paths, filepath.Join(dir, "a.txt")), []PinOptions{options, options, options, options})
+
+		require.ErrorIs(t, err, ErrNameCollision)
+		require.Nil(t, responses)
+		require.False(t, called)
+	})
+}
+
+func TestPinBatch(t *testing.T) {
+	t.Run("collect all failures", func(t *testing.T) {
+		dir := writeTree(t, "ok.txt")
+		paths := []string{filepath.Join(dir, "ok.txt"), filepath.Join(dir, "missing.txt")}
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinBatch(context.Background(), paths, nil)
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.NoError(t, results[0].Err)
+		require.Equal(t, 0, results[0].Index)
+		require.Error(t, results[1].Err)
+		require.Contains(t, results[1].Err.Error(), "failed to open file")
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		results, err := client.PinBatch(context.Background(), []string{"/non/existent/file.txt"}, &BatchOptions{FailFast: true})
+
+		require.Error(t, err)
+		require.Len(t, results, 1)
+		require.Contains(t, err.Error(), "failed to pin /non/existent/file.txt")
+	})
+
+	t.Run("per job timeout", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "slow.txt", "b.txt", "c.txt")
+		paths := []string{
+			filepath.Join(dir, "a.txt"),
+			filepath.Join(dir, "slow.txt"),
+			filepath.Join(dir, "b.txt"),
+			filepath.Join(dir, "c.txt"),
+		}
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				return
+			}
+			if strings.Contains(string(body), `filename="slow.txt"`) {
+				// stall until the client gives up on the upload
+				select {
+				case <-r.Context().Done():
+				case <-time.After(10 * time.Second):
+				}
+				return
+			}
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		start := time.Now()
+		results, err := client.PinBatch(context.Background(), paths, &BatchOptions{
+			Concurrency:   2,
+			PerJobTimeout: 100 * time.Millisecond,
+		})
+
+		require.NoError(t, err)
+		require.Less(t, time.Since(start), 2*time.Second)
+		require.Len(t, results, 4)
+		for i, result := range results {
+			if i == 1 {
+				require.ErrorIs(t, result.Err, context.DeadlineExceeded)
+				require.Nil(t, result.Response)
+				continue
+			}
+			require.NoError(t, result.Err)
+			require.Equal(t, fixtures.Cid, result.Response.IpfsHash)
+		}
+	})
+
+	t.Run("empty paths", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		results, err := client.PinBatch(context.Background(), nil, nil)
+
+		require.Error(t, err)
+		require.Nil(t, results)
+		require.Contains(t, err.Error(), "at least one filepath is required")
+	})
+}
+
+func TestPinBatchPreflight(t *testing.T) {
+	var uploads int
+	var mu sync.Mutex
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		uploads++
+		mu.Unlock()
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}))
+	defer mockServer.Close()
+
+	newClient := func(options ...ClientOption) *Client {
+		uploads = 0
+		client := New(&Auth{jwt: "valid_jwt_token"}, options...)
+		client.baseURL = mockServer.URL
+		return client
+	}
+
+	t.Run("directory passed as a file", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "sub/b.txt")
+		paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "sub")}
+		client := newClient()
+
+		results, err := client.PinBatch(context.Background(), paths, nil)
+		require.ErrorIs(t, err, ErrPreflight)
+		require.Nil(t, results)
+		require.Zero(t, uploads)
+
+		var preflightErr *PreflightError
+		require.ErrorAs(t, err, &preflightErr)
+		require.Len(t, preflightErr.Issues, 1)
+		require.Equal(t, 1, preflightErr.Issues[0].Index)
+		require.Equal(t, paths[1], preflightErr.Issues[0].Path)
+		require.Equal(t, "paths", preflightErr.Issues[0].Source)
+		require.Equal(t, ProblemNotRegular, preflightErr.Issues[0].Problem)
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root can read files without read permission")
+		}
+		dir := writeTree(t, "a.txt", "locked.txt")
+		require.NoError(t, os.Chmod(filepath.Join(dir, "locked.txt"), 0))
+		client := newClient()
+
+		_, err := client.PinDirectory(context.Background(), dir, nil)
+		var preflightErr *PreflightError
+		require.ErrorAs(t, err, &preflightErr)
+		require.Len(t, preflightErr.Issues, 1)
+		require.Equal(t, ProblemPermissionDenied, preflightErr.Issues[0].Problem)
+		require.Equal(t, "walk of "+dir, preflightErr.Issues[0].Source)
+		require.Zero(t, uploads)
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		dir := writeTree(t, "a.txt")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.txt"), nil, 0644))
+		client := newClient()
+
+		_, err := client.PinDirectory(context.Background(), dir, nil)
+		var preflightErr *PreflightError
+		require.ErrorAs(t, err, &preflightErr)
+		require.Len(t, preflightErr.Issues, 1)
+		require.Equal(t, ProblemEmptyFile, preflightErr.Issues[0].Problem)
+		require.Contains(t, err.Error(), "empty.txt (from walk of "+dir+"): empty_file")
+		require.Zero(t, uploads)
+	})
+
+	t.Run("skip and continue", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "b.txt", "sub/c.txt")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.txt"), nil, 0644))
+		paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "sub"), filepath.Join(dir, "empty.txt"), filepath.Join(dir, "b.txt")}
+		recorder := &warningRecorder{}
+		client := newClient(WithWarningHandler(recorder.handle))
+
+		results, err := client.PinBatch(context.Background(), paths, &BatchOptions{SkipInvalid: true, FailFast: true})
+		require.NoError(t, err)
+		require.Equal(t, 2, uploads)
+		require.Len(t, results, 4)
+
+		require.NoError(t, results[0].Err)
+		require.NoError(t, results[3].Err)
+		require.Equal(t, fixtures.Cid, results[3].Response.IpfsHash)
+		for _, i := range []int{1, 2} {
+			require.Equal(t, i, results[i].Index)
+			require.Equal(t, paths[i], results[i].Path)
+			require.Nil(t, results[i].Response)
+			require.ErrorIs(t, results[i].Err, ErrPreflight)
+		}
+		require.Contains(t, results[1].Err.Error(), "not_regular")
+		require.Contains(t, results[2].Err.Error(), "empty_file")
+		require.Equal(t, []WarningCode{WarningFileSkipped, WarningFileSkipped}, recorder.codes())
+	})
+}
@@ -0,0 +1,261 @@
+package pinata
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+func init() {
+	registerCapability(CapabilityCheckpoints)
+}
+
+// DefaultCheckpointFlushEvery is the number of checkpoints a FileCheckpointStore buffers before
+// writing them to disk.
+const DefaultCheckpointFlushEvery = 100
+
+// Checkpoint records a completed upload, so it can be skipped when a batch is re-run.
+// IpfsHash is the IPFS hash of the pinned content.
+// PinSize is the size of the pinned content.
+// Timestamp is the timestamp of the pin.
+type Checkpoint struct {
+	IpfsHash  string `json:"ipfsHash"`
+	PinSize   int    `json:"pinSize"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// CheckpointStore persists the progress of batch uploads. Keys identify a single item of a
+// batch; by default they are derived from the file path, size and modification time.
+// Stores that buffer writes can also implement `Flush() error`, which is called once a batch
+// completes.
+type CheckpointStore interface {
+	Get(key string) (*Checkpoint, bool, error)
+	Set(key string, checkpoint Checkpoint) error
+}
+
+// checkpointFlusher is implemented by stores that buffer checkpoint writes.
+type checkpointFlusher interface {
+	Flush() error
+}
+
+// WithCheckpointStore records uploads completed by PinFilesAsync and PinBatch in store and skips
+// the files already recorded there, so an interrupted batch can be resumed.
+func WithCheckpointStore(store CheckpointStore) ClientOption {
+	return func(c *Client) {
+		c.checkpoints = store
+	}
+}
+
+// fileCheckpointKey derives the checkpoint key of a file from its absolute path, size and
+// modification time, so a file changed since its upload is pinned again.
+func fileCheckpointKey(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file %s: %w", path, err)
+	}
+	return fmt.Sprintf("%s|%d|%d", abs, info.Size(), info.ModTime().UnixNano()), nil
+}
+
+// pinFileCheckpointed pins the file at path unless store holds a checkpoint for it, in which case
+// the recorded response is returned and skipped is true. An empty key is derived from the file
+// with fileCheckpointKey. Successful uploads are recorded in store; a nil store disables
+// checkpointing.
+func (c *Client) pinFileCheckpointed(ctx context.Context, store CheckpointStore, key, path string, options *PinOptions) (response *PinResponse, skipped bool, err error) {
+	if store == nil {
+		response, err = c.pinFile(ctx, path, options)
+		return response, false, err
+	}
+
+	if key == "" {
+		key, err = fileCheckpointKey(path)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	checkpoint, ok, err := store.Get(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if ok {
+		return &PinResponse{IpfsHash: checkpoint.IpfsHash, PinSize: checkpoint.PinSize, Timestamp: checkpoint.Timestamp}, true, nil
+	}
+
+	response, err = c.pinFile(ctx, path, options)
+	if err != nil {
+		return nil, false, err
+	}
+	err = store.Set(key, Checkpoint{IpfsHash: response.IpfsHash, PinSize: response.PinSize, Timestamp: response.Timestamp})
+	if err != nil {
+		return response, false, fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return response, false, nil
+}
+
+// flushCheckpoints flushes store if it buffers writes.
+func flushCheckpoints(store CheckpointStore) error {
+	if flusher, ok := store.(checkpointFlusher); ok {
+		if err := flusher.Flush(); err != nil {
+			return fmt.Errorf("failed to flush checkpoints: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkpointRecord is a single line of a FileCheckpointStore file.
+type checkpointRecord struct {
+	Key string `json:"key"`
+	Checkpoint
+}
+
+// FileCheckpointStore is a CheckpointStore backed by an append-only file of JSON lines.
+//
+// Writes are buffered in memory and appended to the file (followed by a single fsync) every
+// flushEvery checkpoints, on Flush and on Close. Checkpoints not yet flushed when the process dies
+// are lost, and those items are uploaded again on the next run.
+type FileCheckpointStore struct {
+	mu         sync.Mutex
+	file       *os.File
+	flushEvery int
+	entries    map[string]Checkpoint
+	pending    []checkpointRecord
+}
+
+// NewFileCheckpointStore opens (or creates) the checkpoint file at path and loads the checkpoints
+// it holds. flushEvery is the number of checkpoints buffered before they are written to disk
+// (DefaultCheckpointFlushEvery when zero or negative).
+func NewFileCheckpointStore(path string, flushEvery int) (*FileCheckpointStore, error) {
+	if flushEvery <= 0 {
+		flushEvery = DefaultCheckpointFlushEvery
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint file: %w", err)
+	}
+
+	entries := make(map[string]Checkpoint)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record checkpointRecord
+		// a line torn by a crash during a write is ignored, its item is simply uploaded again
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Key == "" {
+			continue
+		}
+		entries[record.Key] = record.Checkpoint
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+
+	store := &FileCheckpointStore{file: file, flushEvery: flushEvery, entries: entries}
+	if err := store.terminateTornLine(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// terminateTornLine ends a line torn by a crash with a newline, so that appended checkpoints
+// start on a line of their own.
+func (s *FileCheckpointStore) terminateTornLine() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat checkpoint file: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+
+	last := make([]byte, 1)
+	if _, err := s.file.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+	if last[0] != '\n' {
+		if _, err := s.file.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("failed to write checkpoint file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Get returns the checkpoint recorded for key.
+func (s *FileCheckpointStore) Get(key string) (*Checkpoint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return &checkpoint, true, nil
+}
+
+// Set records the checkpoint for key, writing the buffered checkpoints to disk once flushEvery
+// of them are pending.
+func (s *FileCheckpointStore) Set(key string, checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = checkpoint
+	s.pending = append(s.pending, checkpointRecord{Key: key, Checkpoint: checkpoint})
+	if len(s.pending) >= s.flushEvery {
+		return s.flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered checkpoints to disk.
+func (s *FileCheckpointStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flush()
+}
+
+// Close flushes the buffered checkpoints and closes the checkpoint file.
+func (s *FileCheckpointStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+	return s.file.Close()
+}
+
+// flush appends the pending checkpoints with a single write and fsync. s.mu must be held.
+func (s *FileCheckpointStore) flush() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, record := range s.pending {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode checkpoint: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	if _, err := s.file.Write(buf); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync checkpoint file: %w", err)
+	}
+
+	s.pending = s.pending[:0]
+	return nil
+}
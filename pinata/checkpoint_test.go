@@ -0,0 +1,271 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// uploadCounter is a mock pinning server that counts uploads per file name and fails the
+// uploads of the files listed in failing.
+type uploadCounter struct {
+	mu      sync.Mutex
+	uploads map[string]int
+	failing map[string]bool
+}
+
+func newUploadCounter(failing ...string) *uploadCounter {
+	u := &uploadCounter{uploads: make(map[string]int), failing: make(map[string]bool)}
+	for _, name := range failing {
+		u.failing[name] = true
+	}
+	return u
+}
+
+func (u *uploadCounter) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+		_, header, err := r.FormFile("file")
+		require.NoError(t, err)
+
+		u.mu.Lock()
+		u.uploads[header.Filename]++
+		fail := u.failing[header.Filename]
+		u.mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"Internal server error"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"IpfsHash":"Qm` + header.Filename + `","PinSize":100,"Timestamp":"2023-05-15T12:00:00Z"}`))
+	}
+}
+
+func (u *uploadCounter) setFailing(failing ...string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.failing = make(map[string]bool)
+	for _, name := range failing {
+		u.failing[name] = true
+	}
+}
+
+func (u *uploadCounter) counts() map[string]int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	counts := make(map[string]int, len(u.uploads))
+	for k, v := range u.uploads {
+		counts[k] = v
+	}
+	return counts
+}
+
+func TestPinBatchCheckpoint(t *testing.T) {
+	t.Run("resume after crash", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "b.txt", "c.txt")
+		paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "c.txt")}
+		checkpointFile := filepath.Join(t.TempDir(), "checkpoints.jsonl")
+
+		counter := newUploadCounter("c.txt")
+		mockServer := httptest.NewServer(counter.handler(t))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		// first run: c.txt fails, then the process "dies" without closing the store
+		store, err := NewFileCheckpointStore(checkpointFile, 10)
+		require.NoError(t, err)
+		results, err := client.PinBatch(context.Background(), paths, &BatchOptions{Checkpoint: store})
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+		require.NoError(t, results[1].Err)
+		require.Error(t, results[2].Err)
+
+		// second run in a "new process" against a healthy server
+		counter.setFailing()
+		store, err = NewFileCheckpointStore(checkpointFile, 10)
+		require.NoError(t, err)
+		defer store.Close()
+		results, err = client.PinBatch(context.Background(), paths, &BatchOptions{Checkpoint: store})
+		require.NoError(t, err)
+
+		require.True(t, results[0].Skipped)
+		require.True(t, results[1].Skipped)
+		require.False(t, results[2].Skipped)
+		require.Equal(t, "Qma.txt", results[0].Response.IpfsHash)
+		require.Equal(t, "Qmc.txt", results[2].Response.IpfsHash)
+		require.Equal(t, map[string]int{"a.txt": 1, "b.txt": 1, "c.txt": 2}, counter.counts())
+	})
+
+	t.Run("modified file is uploaded again", func(t *testing.T) {
+		dir := writeTree(t, "a.txt")
+		path := filepath.Join(dir, "a.txt")
+
+		counter := newUploadCounter()
+		mockServer := httptest.NewServer(counter.handler(t))
+		defer mockServer.Close()
+
+		store, err := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.jsonl"), 0)
+		require.NoError(t, err)
+		defer store.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithCheckpointStore(store))
+		client.baseURL = mockServer.URL
+
+		_, err = client.PinBatch(context.Background(), []string{path}, nil)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, []byte("changed content"), 0644))
+		results, err := client.PinBatch(context.Background(), []string{path}, nil)
+		require.NoError(t, err)
+
+		require.False(t, results[0].Skipped)
+		require.Equal(t, map[string]int{"a.txt": 2}, counter.counts())
+	})
+
+	t.Run("caller key", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "b.txt")
+		paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+
+		counter := newUploadCounter()
+		mockServer := httptest.NewServer(counter.handler(t))
+		defer mockServer.Close()
+
+		store, err := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.jsonl"), 0)
+		require.NoError(t, err)
+		defer store.Close()
+		require.NoError(t, store.Set("item-b", Checkpoint{IpfsHash: "QmPrevious", PinSize: 10}))
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinBatch(context.Background(), paths, &BatchOptions{
+			Checkpoint:    store,
+			CheckpointKey: func(path string) string { return "item-" + filepath.Base(path)[:1] },
+		})
+		require.NoError(t, err)
+
+		require.False(t, results[0].Skipped)
+		require.True(t, results[1].Skipped)
+		require.Equal(t, "QmPrevious", results[1].Response.IpfsHash)
+		require.Equal(t, map[string]int{"a.txt": 1}, counter.counts())
+	})
+}
+
+func TestPinFilesAsyncCheckpoint(t *testing.T) {
+	dir := writeTree(t, "a.txt", "b.txt")
+	paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoints.jsonl")
+
+	counter := newUploadCounter()
+	mockServer := httptest.NewServer(counter.handler(t))
+	defer mockServer.Close()
+
+	for run := 0; run < 2; run++ {
+		store, err := NewFileCheckpointStore(checkpointFile, 0)
+		require.NoError(t, err)
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithCheckpointStore(store))
+		client.baseURL = mockServer.URL
+
+		responses, err := client.PinFilesAsync(paths, nil)
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+	}
+
+	require.Equal(t, map[string]int{"a.txt": 1, "b.txt": 1}, counter.counts())
+}
+
+// TestPinFilesAsyncCheckpointOnFailure checks that the files uploaded before a failure are saved,
+// so that a rerun does not upload them again.
+func TestPinFilesAsyncCheckpointOnFailure(t *testing.T) {
+	dir := writeTree(t, "a.txt", "b.txt")
+	paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoints.jsonl")
+
+	counter := newUploadCounter("b.txt")
+	mockServer := httptest.NewServer(counter.handler(t))
+	defer mockServer.Close()
+
+	// the store only writes on flush, and a single worker uploads a.txt before b.txt fails
+	store, err := NewFileCheckpointStore(checkpointFile, 100)
+	require.NoError(t, err)
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithCheckpointStore(store), WithConcurrency(1), WithRetryPolicy(fastRetries))
+	client.baseURL = mockServer.URL
+	_, err = client.PinFilesAsync(paths, nil)
+	require.Error(t, err)
+
+	counter.setFailing()
+	store, err = NewFileCheckpointStore(checkpointFile, 100)
+	require.NoError(t, err)
+	defer store.Close()
+	client = New(&Auth{jwt: "valid_jwt_token"}, WithCheckpointStore(store))
+	client.baseURL = mockServer.URL
+	_, err = client.PinFilesAsync(paths, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, counter.counts()["a.txt"])
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	t.Run("batched writes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "checkpoints.jsonl")
+		store, err := NewFileCheckpointStore(path, 2)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Set("a", Checkpoint{IpfsHash: "QmA"}))
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Zero(t, info.Size())
+
+		require.NoError(t, store.Set("b", Checkpoint{IpfsHash: "QmB"}))
+		require.NoError(t, store.Set("c", Checkpoint{IpfsHash: "QmC"}))
+
+		// simulate a crash: the unflushed checkpoint of "c" is lost
+		reopened, err := NewFileCheckpointStore(path, 2)
+		require.NoError(t, err)
+		defer reopened.Close()
+
+		checkpoint, ok, err := reopened.Get("b")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "QmB", checkpoint.IpfsHash)
+
+		_, ok, err = reopened.Get("c")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("torn line is ignored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "checkpoints.jsonl")
+		content := `{"key":"a","ipfsHash":"QmA","pinSize":1}` + "\n" + `{"key":"b","ipfsH`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		store, err := NewFileCheckpointStore(path, 0)
+		require.NoError(t, err)
+		defer store.Close()
+
+		_, ok, err := store.Get("a")
+		require.NoError(t, err)
+		require.True(t, ok)
+		_, ok, err = store.Get("b")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, store.Set("c", Checkpoint{IpfsHash: "QmC"}))
+		require.NoError(t, store.Flush())
+		reopened, err := NewFileCheckpointStore(path, 0)
+		require.NoError(t, err)
+		defer reopened.Close()
+		_, ok, err = reopened.Get("c")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+}
@@ -0,0 +1,205 @@
+package pinata
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidCid is returned when a string is not a CID the conversion helpers can decode.
+var ErrInvalidCid = errors.New("invalid cid")
+
+// ErrCidVersionMismatch is returned when content pinned with PinOptions.CidVersionPolicy set to
+// CidVersionStrict is returned with a CID of another version than requested.
+var ErrCidVersionMismatch = errors.New("cid version mismatch")
+
+// CidVersionMismatchError reports a pinned CID whose version differs from the requested one. It
+// matches ErrCidVersionMismatch with errors.Is.
+// Cid is the CID returned by the API.
+// Requested is the CID version requested with PinOptions.PinataOptions.CidVersion.
+// Returned is the version of Cid.
+type CidVersionMismatchError struct {
+	Cid       string
+	Requested int
+	Returned  int
+}
+
+// Error implements the error interface.
+func (e *CidVersionMismatchError) Error() string {
+	return fmt.Sprintf("%s: requested a v%d cid but %s is v%d", ErrCidVersionMismatch, e.Requested, e.Cid, e.Returned)
+}
+
+// Unwrap returns the underlying error.
+func (e *CidVersionMismatchError) Unwrap() error {
+	return ErrCidVersionMismatch
+}
+
+// CidVersionPolicy selects what a pin method does when the API returns a CID of another version
+// than PinOptions.PinataOptions.CidVersion, as happens for duplicates of content first pinned with
+// another version.
+type CidVersionPolicy int
+
+const (
+	// CidVersionIgnore returns the CID as given by the API. It is the default.
+	CidVersionIgnore CidVersionPolicy = iota
+	// CidVersionConvert converts the CID to the requested version. The response IpfsHash holds the
+	// converted CID and ReturnedIpfsHash the CID given by the API.
+	CidVersionConvert
+	// CidVersionStrict fails the call with a *CidVersionMismatchError. The content stays pinned.
+	CidVersionStrict
+)
+
+// dag-pb codec and sha2-256 multihash prefix shared by every CIDv0.
+const (
+	cidCodecDagPB   = 0x70
+	multihashSHA256 = 0x12
+	sha256Length    = 32
+)
+
+// cidBase32 is the lowercase, unpadded base32 of the multibase prefix "b".
+var cidBase32 = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// base58Alphabet is the bitcoin base58 alphabet used by CIDv0.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// CidVersion returns the version of cid: 0 for a base58 "Qm" CID, 1 for a base32 CID with the
+// multibase prefix "b". Other encodings are reported as invalid.
+func CidVersion(cid string) (int, error) {
+	if _, err := decodeCidV0(cid); err == nil {
+		return 0, nil
+	}
+	if _, _, err := decodeCidV1(cid); err == nil {
+		return 1, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidCid, cid)
+}
+
+// CidToV1 returns cid as a base32 CIDv1. A CIDv1 is returned unchanged.
+func CidToV1(cid string) (string, error) {
+	if _, _, err := decodeCidV1(cid); err == nil {
+		return cid, nil
+	}
+	multihash, err := decodeCidV0(cid)
+	if err != nil {
+		return "", err
+	}
+	return "b" + cidBase32.EncodeToString(append([]byte{1, cidCodecDagPB}, multihash...)), nil
+}
+
+// CidToV0 returns cid as a CIDv0. Only dag-pb CIDs with a sha2-256 multihash have a v0 form. A
+// CIDv0 is returned unchanged.
+func CidToV0(cid string) (string, error) {
+	if _, err := decodeCidV0(cid); err == nil {
+		return cid, nil
+	}
+	codec, multihash, err := decodeCidV1(cid)
+	if err != nil {
+		return "", err
+	}
+	if codec != cidCodecDagPB || !isSHA256Multihash(multihash) {
+		return "", fmt.Errorf("%w: %q has no v0 form", ErrInvalidCid, cid)
+	}
+	return encodeBase58(multihash), nil
+}
+
+// decodeCidV0 returns the multihash of a CIDv0.
+func decodeCidV0(cid string) ([]byte, error) {
+	if len(cid) != 46 || !strings.HasPrefix(cid, "Qm") {
+		return nil, fmt.Errorf("%w: %q is not a v0 cid", ErrInvalidCid, cid)
+	}
+	multihash, err := decodeBase58(cid)
+	if err != nil || !isSHA256Multihash(multihash) {
+		return nil, fmt.Errorf("%w: %q is not a v0 cid", ErrInvalidCid, cid)
+	}
+	return multihash, nil
+}
+
+// decodeCidV1 returns the codec and multihash of a base32 CIDv1.
+func decodeCidV1(cid string) (uint64, []byte, error) {
+	invalid := fmt.Errorf("%w: %q is not a base32 v1 cid", ErrInvalidCid, cid)
+	if !strings.HasPrefix(cid, "b") {
+		return 0, nil, invalid
+	}
+	raw, err := cidBase32.DecodeString(cid[1:])
+	if err != nil {
+		return 0, nil, invalid
+	}
+	version, n := protoVarint(raw)
+	if n == 0 || version != 1 {
+		return 0, nil, invalid
+	}
+	codec, m := protoVarint(raw[n:])
+	if m == 0 || len(raw[n+m:]) < 2 {
+		return 0, nil, invalid
+	}
+	return codec, raw[n+m:], nil
+}
+
+// isSHA256Multihash reports whether multihash is a sha2-256 multihash, the only kind a CIDv0 holds.
+func isSHA256Multihash(multihash []byte) bool {
+	return len(multihash) == 2+sha256Length && multihash[0] == multihashSHA256 && multihash[1] == sha256Length
+}
+
+// decodeBase58 decodes s from base58, keeping each leading "1" as a zero byte.
+func decodeBase58(s string) ([]byte, error) {
+	n := new(big.Int)
+	for _, r := range s {
+		digit := strings.IndexRune(base58Alphabet, r)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", r)
+		}
+		n.Mul(n, big.NewInt(58))
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+	zeros := len(s) - len(strings.TrimLeft(s, "1"))
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// encodeBase58 encodes b to base58, writing each leading zero byte as "1".
+func encodeBase58(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	base, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < len(b) && b[i] == 0; i++ {
+		out = append(out, '1')
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// checkCidVersion applies options.CidVersionPolicy to the CID of response.
+func (c *Client) checkCidVersion(response *PinResponse, options *PinOptions) error {
+	if options == nil || options.CidVersionPolicy == CidVersionIgnore {
+		return nil
+	}
+	requested := options.PinataOptions.CidVersion
+	returned, err := CidVersion(response.IpfsHash)
+	if err != nil || returned == requested {
+		// CIDs in encodings the helpers do not decode are left as is
+		return nil
+	}
+
+	mismatch := &CidVersionMismatchError{Cid: response.IpfsHash, Requested: requested, Returned: returned}
+	if options.CidVersionPolicy == CidVersionStrict {
+		return mismatch
+	}
+	convert := CidToV1
+	if requested == 0 {
+		convert = CidToV0
+	}
+	converted, err := convert(response.IpfsHash)
+	if err != nil {
+		return fmt.Errorf("%w: %w", mismatch, err)
+	}
+	response.ReturnedIpfsHash = response.IpfsHash
+	response.IpfsHash = converted
+	return nil
+}
@@ -0,0 +1,93 @@
+package pinata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cidV0 and cidV1 are the two forms of the same content, as listed in the IPFS docs.
+const (
+	cidV0 = "QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR"
+	cidV1 = "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+)
+
+func TestCidConversion(t *testing.T) {
+	version, err := CidVersion(cidV0)
+	require.NoError(t, err)
+	require.Equal(t, 0, version)
+
+	version, err = CidVersion(cidV1)
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	v1, err := CidToV1(cidV0)
+	require.NoError(t, err)
+	require.Equal(t, cidV1, v1)
+
+	v0, err := CidToV0(cidV1)
+	require.NoError(t, err)
+	require.Equal(t, cidV0, v0)
+
+	same, err := CidToV1(cidV1)
+	require.NoError(t, err)
+	require.Equal(t, cidV1, same)
+
+	for _, invalid := range []string{"", "not a cid", "Qm" + cidV0[2:45] + "0", "b" + cidV1[2:], "zdj7WWeQ43G6JJvLWQWZpyHuAMq6uYWRjkBXFad11vE2LHhQ7"} {
+		_, err := CidVersion(invalid)
+		require.ErrorIs(t, err, ErrInvalidCid, invalid)
+	}
+
+	// a raw-codec CIDv1 has no v0 form
+	_, err = CidToV0("bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy")
+	require.ErrorIs(t, err, ErrInvalidCid)
+}
+
+func TestPinCidVersionPolicy(t *testing.T) {
+	// Pinata answers a v1 request for content first pinned as v0 with the v0 CID
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"IpfsHash":"` + cidV0 + `","PinSize":100,"Timestamp":"2023-05-15T12:00:00Z","isDuplicate":true}`))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+	pinV1 := func(policy CidVersionPolicy) (*PinResponse, error) {
+		return client.PinJSON(map[string]string{"hello": "world"}, &PinOptions{
+			PinataOptions:    Options{CidVersion: 1},
+			CidVersionPolicy: policy,
+		})
+	}
+
+	t.Run("ignore", func(t *testing.T) {
+		response, err := pinV1(CidVersionIgnore)
+		require.NoError(t, err)
+		require.Equal(t, cidV0, response.IpfsHash)
+		require.Empty(t, response.ReturnedIpfsHash)
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		response, err := pinV1(CidVersionConvert)
+		require.NoError(t, err)
+		require.Equal(t, cidV1, response.IpfsHash)
+		require.Equal(t, cidV0, response.ReturnedIpfsHash)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		response, err := pinV1(CidVersionStrict)
+		require.Nil(t, response)
+		require.ErrorIs(t, err, ErrCidVersionMismatch)
+
+		var mismatch *CidVersionMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		require.Equal(t, CidVersionMismatchError{Cid: cidV0, Requested: 1, Returned: 0}, *mismatch)
+	})
+
+	t.Run("matching version", func(t *testing.T) {
+		response, err := client.PinJSON(map[string]string{"hello": "world"}, &PinOptions{CidVersionPolicy: CidVersionStrict})
+		require.NoError(t, err)
+		require.Equal(t, cidV0, response.IpfsHash)
+	})
+}
@@ -0,0 +1,100 @@
+package pinata
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+func init() {
+	registerCapability(CapabilityPerCidLocking)
+}
+
+// WithPerCidLocking sets whether the client runs the mutating operations on the same CID one at a
+// time: UpdateFileMetadata, DeleteFile and Unpin, adding a CID to or removing it from a group, the
+// swaps of a CID and its signatures. Operations on different CIDs still run in parallel. An
+// operation waiting for its turn gives up with the error of its context. It is disabled by default.
+//
+// Only the operations of the same client are serialized; the lock of a CID is dropped as soon as no
+// operation holds it or waits for it, so the lock table only holds the CIDs in use.
+func WithPerCidLocking(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.cidLocks = nil
+		if enabled {
+			c.cidLocks = &cidLocks{locks: make(map[string]*cidLock)}
+		}
+	}
+}
+
+// cidLocks is the lock table of WithPerCidLocking.
+type cidLocks struct {
+	mu    sync.Mutex
+	locks map[string]*cidLock
+}
+
+// cidLock is the lock of a CID. sem holds a token while the lock is held, so that waiting can be
+// cancelled. refs counts the operations holding or waiting for the lock.
+type cidLock struct {
+	sem  chan struct{}
+	refs int
+}
+
+// lockCids locks every CID of cids for a mutating operation, waiting for the operations in
+// progress on them, and returns the function unlocking them. CIDs are locked in sorted order, so
+// that operations on overlapping sets of CIDs cannot deadlock. It returns immediately when per-CID
+// locking is disabled.
+func (c *Client) lockCids(ctx context.Context, cids ...string) (func(), error) {
+	if c.cidLocks == nil {
+		return func() {}, nil
+	}
+	cids = slices.Clone(cids)
+	slices.Sort(cids)
+	cids = slices.Compact(cids)
+
+	var held []string
+	unlock := func() {
+		for _, cid := range held {
+			c.cidLocks.release(cid, true)
+		}
+	}
+	for _, cid := range cids {
+		lock := c.cidLocks.acquire(cid)
+		select {
+		case lock.sem <- struct{}{}:
+			held = append(held, cid)
+		case <-ctx.Done():
+			c.cidLocks.release(cid, false)
+			unlock()
+			return nil, transportError(ctx.Err())
+		}
+	}
+	return unlock, nil
+}
+
+// acquire returns the lock of cid, counting a reference to it.
+func (l *cidLocks) acquire(cid string) *cidLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock, ok := l.locks[cid]
+	if !ok {
+		lock = &cidLock{sem: make(chan struct{}, 1)}
+		l.locks[cid] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+// release drops a reference to the lock of cid, unlocking it if held, and removes the lock from
+// the table once it has no references left.
+func (l *cidLocks) release(cid string, held bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock := l.locks[cid]
+	if held {
+		<-lock.sem
+	}
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, cid)
+	}
+}
@@ -0,0 +1,158 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lockTableSize returns the number of CIDs in the lock table of c.
+func lockTableSize(c *Client) int {
+	c.cidLocks.mu.Lock()
+	defer c.cidLocks.mu.Unlock()
+	return len(c.cidLocks.locks)
+}
+
+func TestPerCidLocking(t *testing.T) {
+	t.Run("serializes mutations of the same cid", func(t *testing.T) {
+		var inFlight, maxInFlight, requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				max := maxInFlight.Load()
+				if n <= max || maxInFlight.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			w.Write([]byte(`{}`))
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPerCidLocking(true))
+		client.baseURL = mockServer.URL
+
+		group := GroupID("5b9a1c2e-0d57-4a8b-9a8e-7d1f0a1b2c3d")
+		operations := []func() error{
+			func() error {
+				return client.UpdateFileMetadata(cidV0, &PinMetadataUpdateOptions{Name: "name"})
+			},
+			func() error { return client.DeleteFile(cidV0) },
+			func() error { return client.Groups().AddCids(group, []Cid{Cid(cidV0), Cid(cidV1)}) },
+			func() error { return client.Groups().RemoveCids(group, []Cid{Cid(cidV1), Cid(cidV0)}) },
+			func() error {
+				_, err := client.AddSwap(cidV0, cidV1, &AddSwapOptions{VerifyTarget: Bool(false)})
+				return err
+			},
+			func() error {
+				_, err := client.RemoveSwap(cidV0)
+				return err
+			},
+			func() error {
+				_, err := client.AddCidSignature(cidV0, "0xsignature")
+				return err
+			},
+			func() error { return client.RemoveCidSignature(cidV0) },
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				require.NoError(t, operations[i%len(operations)]())
+			}(i)
+		}
+		wg.Wait()
+
+		require.EqualValues(t, 20, requests.Load())
+		require.EqualValues(t, 1, maxInFlight.Load())
+		require.Zero(t, lockTableSize(client))
+	})
+
+	t.Run("different cids proceed in parallel", func(t *testing.T) {
+		arrived := make(chan struct{}, 2)
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			arrived <- struct{}{}
+			<-release
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPerCidLocking(true))
+		client.baseURL = mockServer.URL
+
+		done := make(chan error, 2)
+		for _, cid := range []string{cidV0, cidV1} {
+			go func(cid string) { done <- client.DeleteFile(cid) }(cid)
+		}
+		// both requests reach the server while neither has finished
+		<-arrived
+		<-arrived
+		require.Equal(t, 2, lockTableSize(client))
+		close(release)
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+		require.Zero(t, lockTableSize(client))
+	})
+
+	t.Run("waiting gives up with the context", func(t *testing.T) {
+		arrived := make(chan struct{})
+		release := make(chan struct{})
+		var requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			close(arrived)
+			<-release
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPerCidLocking(true))
+		client.baseURL = mockServer.URL
+
+		done := make(chan error)
+		go func() { done <- client.DeleteFile(cidV0) }()
+		<-arrived
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := client.RemoveCidSignatureWithContext(ctx, cidV0)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 1, lockTableSize(client))
+
+		close(release)
+		require.NoError(t, <-done)
+		require.EqualValues(t, 1, requests.Load())
+		require.Zero(t, lockTableSize(client))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		arrived := make(chan struct{}, 2)
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.True(t, strings.HasSuffix(r.URL.Path, cidV0))
+			arrived <- struct{}{}
+			<-release
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		done := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() { done <- client.DeleteFile(cidV0) }()
+		}
+		<-arrived
+		<-arrived
+		close(release)
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+		require.Nil(t, client.cidLocks)
+	})
+}
@@ -1,7 +1,11 @@
 package pinata
 
 import (
+	"context"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -10,15 +14,80 @@ const BaseURL = "https://api.pinata.cloud"
 // Client is the main struct for interacting with the Pinata API. It contains the necessary
 // configuration and authentication details to make requests to the API.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	auth       *Auth
-	transport  *http.Transport
+	baseURL        string
+	httpClient     *http.Client
+	auth           *Auth
+	authMu         sync.RWMutex
+	transport      *http.Transport
+	onUnauthorized func(ctx context.Context) (*Auth, error)
+	refresh        authRefresh
+
+	maxGroupNameLength    int
+	maxMetadataNameLength int
+
+	codec              Codec
+	checkpoints        CheckpointStore
+	logger             *slog.Logger
+	onWarning          func(Warning)
+	pageLimitPolicy    PageLimitPolicy
+	pagination         PaginationOptions
+	gateways           []string
+	multipartBoundary  string
+	pinJobPollInterval time.Duration
+
+	multipartContentLength bool
+	uploadURL              string
+	gatewayURL             string
+	authStyle              AuthStyle
+	keyUsesThreshold       int
+	signer                 RequestSigner
+	healthTTL              time.Duration
+	health                 authHealthCache
+	clock                  clock
+	trackAttempts          bool
+	maxURLLength           int
+	quotaGuard             QuotaGuard
+	gatewayRetry           *GatewayRetryPolicy
+	retry                  RetryPolicy
+	retryReasons           map[string]ReasonPolicy
+	gatewayLimiter         *rateLimiter
+	apiLimiter             *rateLimiter
+	gatewayToken           string
+	concurrency            int
+	nameClock              func() time.Time
+	connTrace              bool
+	onConnTrace            func(ConnTrace)
+	skipStartupCheck       bool
+	startupRetryDelay      time.Duration
+	activeOps              activeOps
+	cidLocks               *cidLocks
+	authFallback           authFallback
+	readCache              *readCache
+	userAgentSuffix        string
+	logBodyLimit           int
+	logRedactedHeaders     map[string]bool
+	extraFormFields        func(*multipart.Writer) error
+	requestInterceptors    []func(*http.Request) error
+	responseInterceptors   []func(*http.Response) error
+	pollKeepAlive          *PollKeepAlive
+	lastRateLimit          lastRateLimit
+	timeoutPolicy          *TimeoutPolicy
+
+	keysOnce      sync.Once
+	keysClient    *KeysClient
+	gatewayOnce   sync.Once
+	gatewayClient *GatewayClient
+	filesOnce     sync.Once
+	filesClient   *FilesClient
+	groupsOnce    sync.Once
+	groupsClient  *GroupsClient
+	pollOnce      sync.Once
+	pollClient    *http.Client
 }
 
-// authTestResponse represents the response from the Pinata API's test authentication endpoint.
+// AuthTestResponse represents the response from the Pinata API's test authentication endpoint.
 // It contains a message field with the result of the authentication test.
-type authTestResponse struct {
+type AuthTestResponse struct {
 	Message string `json:"message"`
 }
 
@@ -26,14 +95,15 @@ type authTestResponse struct {
 // It configures the HTTP client with a transport that has a maximum of 100 idle connections,
 // a maximum of 100 idle connections per host, and an idle connection timeout of 90 seconds.
 // The HTTP client also has a timeout of 30 seconds.
-func New(auth *Auth) *Client {
+// Optional behavior can be configured with ClientOption values.
+func New(auth *Auth, opts ...ClientOption) *Client {
 	transport := &http.Transport{
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 100,
 		IdleConnTimeout:     90 * time.Second,
 	}
 
-	return &Client{
+	c := &Client{
 		baseURL: BaseURL,
 		httpClient: &http.Client{
 			Timeout:   time.Second * 90,
@@ -42,11 +112,20 @@ func New(auth *Auth) *Client {
 		auth:      auth,
 		transport: transport,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // NewRequest creates a new request builder for the Pinata API. The request builder
 // allows for configuring the HTTP method, path, path parameters, query parameters,
-// and headers before sending the request.
+// and headers before sending the request. A builder sends a single request.
+//
+// NewRequest also serves endpoints the SDK does not cover yet: build the request against their path
+// and send it with Do to decode the raw response yourself.
 func (c *Client) NewRequest(method, path string) *requestBuilder {
 	return &requestBuilder{
 		client:      c,
@@ -61,9 +140,20 @@ func (c *Client) NewRequest(method, path string) *requestBuilder {
 // TestAuthentication tests the authentication credentials configured in the Pinata API client.
 // It sends a GET request to the "/data/testAuthentication" endpoint and returns the response
 // message indicating whether the authentication was successful or not.
-func (c *Client) TestAuthentication() (*authTestResponse, error) {
-	var response authTestResponse
+func (c *Client) TestAuthentication() (*AuthTestResponse, error) {
+	return c.testAuthentication(context.Background())
+}
+
+// TestAuthenticationWithContext is like TestAuthentication but uses ctx for the requests.
+func (c *Client) TestAuthenticationWithContext(ctx context.Context) (*AuthTestResponse, error) {
+	return c.testAuthentication(ctx)
+}
+
+// testAuthentication implements TestAuthentication, using ctx for the request.
+func (c *Client) testAuthentication(ctx context.Context) (*AuthTestResponse, error) {
+	var response AuthTestResponse
 	err := c.NewRequest(http.MethodGet, "/data/testAuthentication").
+		WithContext(ctx).
 		Send(&response)
 
 	if err != nil {
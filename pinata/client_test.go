@@ -102,8 +102,9 @@ func TestTestAuthentication(t *testing.T) {
 			require.Equal(t, "/data/testAuthentication", r.URL.Path)
 			require.Equal(t, http.MethodGet, r.Method)
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
+			require.NotContains(t, r.Header, "Content-Type")
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"message":"Congratulations! You are authenticated"}`))
+			w.Write(fixture(t, "GET /data/testAuthentication"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -112,7 +113,7 @@ func TestTestAuthentication(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "Congratulations! You are authenticated", response.Message)
+		require.Equal(t, "Congratulations! You are communicating with the Pinata API!", response.Message)
 	})
 
 	t.Run("authentication failure", func(t *testing.T) {
@@ -0,0 +1,94 @@
+package pinata
+
+import "time"
+
+// Clone returns a deep copy of the options, sharing no maps, slices or nested keyvalues with o.
+// Pin methods clone the options they receive before using them, so an options value can be shared
+// by concurrent calls and modified once a call returns, or once a job is enqueued.
+func (o *PinOptions) Clone() *PinOptions {
+	if o == nil {
+		return nil
+	}
+	clone := *o
+	clone.PinataMetadata.KeyValues = cloneKeyValues(o.PinataMetadata.KeyValues)
+	clone.AllowedContentTypes = cloneStrings(o.AllowedContentTypes)
+	clone.DeniedContentTypes = cloneStrings(o.DeniedContentTypes)
+	return &clone
+}
+
+// Clone returns a deep copy of the options, sharing no maps or time values with o.
+func (o *ListFilesOptions) Clone() *ListFilesOptions {
+	if o == nil {
+		return nil
+	}
+	clone := *o
+	clone.Metadata = cloneKeyValues(o.Metadata)
+	clone.PinStart = cloneTime(o.PinStart)
+	clone.PinEnd = cloneTime(o.PinEnd)
+	clone.UnpinStart = cloneTime(o.UnpinStart)
+	clone.UnpinEnd = cloneTime(o.UnpinEnd)
+	return &clone
+}
+
+// Clone returns a deep copy of the options, sharing no limit or offset with o.
+func (o *ListPinByCidOptions) Clone() *ListPinByCidOptions {
+	if o == nil {
+		return nil
+	}
+	clone := *o
+	clone.Limit = cloneInt(o.Limit)
+	clone.Offset = cloneInt(o.Offset)
+	return &clone
+}
+
+// cloneKeyValues returns a deep copy of keyvalues, copying nested objects and arrays.
+func cloneKeyValues(keyValues map[string]interface{}) map[string]interface{} {
+	if keyValues == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(keyValues))
+	for k, v := range keyValues {
+		clone[k] = cloneValue(v)
+	}
+	return clone
+}
+
+// cloneValue returns a deep copy of a keyvalues value. Values other than objects and arrays are
+// immutable and returned as is.
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return cloneKeyValues(v)
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	case []string:
+		return cloneStrings(v)
+	}
+	return v
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+func cloneInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+	return Int(*i)
+}
@@ -0,0 +1,126 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinOptionsClone(t *testing.T) {
+	options := &PinOptions{
+		PinataMetadata: PinataMetadata{
+			Name:      "report",
+			KeyValues: map[string]interface{}{"env": "prod", "tags": []interface{}{"a", map[string]interface{}{"b": 1}}},
+		},
+		PinataOptions:       Options{CidVersion: 1},
+		AllowedContentTypes: []string{"image/png"},
+	}
+
+	clone := options.Clone()
+	require.Equal(t, options, clone)
+
+	clone.PinataMetadata.KeyValues["env"] = "dev"
+	clone.PinataMetadata.KeyValues["tags"].([]interface{})[1].(map[string]interface{})["b"] = 2
+	clone.AllowedContentTypes[0] = "text/plain"
+	require.Equal(t, "prod", options.PinataMetadata.KeyValues["env"])
+	require.Equal(t, 1, options.PinataMetadata.KeyValues["tags"].([]interface{})[1].(map[string]interface{})["b"])
+	require.Equal(t, "image/png", options.AllowedContentTypes[0])
+
+	require.Nil(t, (*PinOptions)(nil).Clone())
+
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	filter := &ListFilesOptions{PinStart: &start, Metadata: map[string]interface{}{"env": "prod"}}
+	filterClone := filter.Clone()
+	*filterClone.PinStart = start.Add(time.Hour)
+	filterClone.Metadata["env"] = "dev"
+	require.Equal(t, start, *filter.PinStart)
+	require.Equal(t, "prod", filter.Metadata["env"])
+}
+
+// recordMetadata returns a handler that records the pinataMetadata field of every upload.
+func recordMetadata(mu *sync.Mutex, uploads *[]PinataMetadata) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var metadata PinataMetadata
+		if err := json.Unmarshal([]byte(r.FormValue("pinataMetadata")), &metadata); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		*uploads = append(*uploads, metadata)
+		mu.Unlock()
+		serveFixture(w, r)
+	}
+}
+
+func TestSharedPinOptions(t *testing.T) {
+	t.Run("concurrent uploads share one keyvalues map", func(t *testing.T) {
+		var mu sync.Mutex
+		var uploads []PinataMetadata
+		mockServer := httptest.NewServer(recordMetadata(&mu, &uploads))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		tempDir := t.TempDir()
+		keyValues := map[string]interface{}{"env": "prod", "tags": []interface{}{"a", "b"}}
+		contentTypes := []string{"text/plain"}
+		var paths []string
+		var options []PinOptions
+		for i := 0; i < 50; i++ {
+			path := filepath.Join(tempDir, fmt.Sprintf("file%02d.txt", i))
+			require.NoError(t, os.WriteFile(path, []byte("test content"), 0644))
+			paths = append(paths, path)
+			options = append(options, PinOptions{
+				PinataMetadata:      PinataMetadata{Name: fmt.Sprintf(" file%02d ", i), KeyValues: keyValues},
+				AllowedContentTypes: contentTypes,
+			})
+		}
+
+		responses, err := client.PinFilesAsync(paths, options)
+
+		require.NoError(t, err)
+		require.Len(t, responses, 50)
+		require.Len(t, uploads, 50)
+		for _, metadata := range uploads {
+			require.Equal(t, map[string]interface{}{"env": "prod", "tags": []interface{}{"a", "b"}}, metadata.KeyValues)
+		}
+		require.Equal(t, " file00 ", options[0].PinataMetadata.Name)
+		require.Equal(t, map[string]interface{}{"env": "prod", "tags": []interface{}{"a", "b"}}, keyValues)
+	})
+
+	t.Run("queued jobs keep the options they were enqueued with", func(t *testing.T) {
+		var mu sync.Mutex
+		var uploads []PinataMetadata
+		mockServer := httptest.NewServer(recordMetadata(&mu, &uploads))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+		queue := newPinQueue(client, &PinQueueOptions{Workers: 2, DrainOnShutdown: true}, newFakeClock())
+		path := filepath.Join(writeTree(t, "a.txt"), "a.txt")
+
+		options := &PinOptions{PinataMetadata: PinataMetadata{Name: "shared", KeyValues: map[string]interface{}{"run": 0}}}
+		for i := 0; i < 10; i++ {
+			require.NoError(t, queue.Enqueue(PinJob{Path: path, Options: options}))
+			options.PinataMetadata.KeyValues["run"] = i + 1
+		}
+		require.NoError(t, queue.Shutdown(context.Background()))
+
+		require.Len(t, uploads, 10)
+		runs := make(map[float64]bool)
+		for _, metadata := range uploads {
+			runs[metadata.KeyValues["run"].(float64)] = true
+		}
+		require.Len(t, runs, 10)
+	})
+}
@@ -0,0 +1,113 @@
+package pinata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Codec is the JSON implementation used by the client to encode request bodies and decode
+// responses. It defaults to encoding/json and can be replaced with WithCodec, e.g. with an
+// adapter for jsoniter or segmentio/encoding.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder decodes JSON values from a stream. *json.Decoder satisfies it.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// jsonCodec is the default Codec, backed by encoding/json.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// WithCodec replaces the JSON implementation used for request bodies and responses.
+func WithCodec(codec Codec) ClientOption {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// codec returns the JSON codec used by the request builder, falling back to encoding/json.
+func (rb *requestBuilder) codec() Codec {
+	if rb.client != nil && rb.client.codec != nil {
+		return rb.client.codec
+	}
+	return jsonCodec{}
+}
+
+// canonicalJSON re-encodes the JSON document raw with the keys of every object sorted and no
+// insignificant whitespace. Array order and number literals are preserved.
+func canonicalJSON(raw []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON content: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonicalJSON(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeCanonicalJSON writes the decoded JSON value v to buf, sorting object keys recursively.
+func writeCanonicalJSON(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package pinata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+// stubCodec is a Codec that delegates to encoding/json while counting its calls, proving that
+// the client routes all JSON handling through the configured codec.
+type stubCodec struct {
+	marshals int32
+	decodes  int32
+}
+
+func (s *stubCodec) Marshal(v interface{}) ([]byte, error) {
+	atomic.AddInt32(&s.marshals, 1)
+	return json.Marshal(v)
+}
+
+func (s *stubCodec) NewDecoder(r io.Reader) Decoder {
+	return stubDecoder{codec: s, dec: json.NewDecoder(r)}
+}
+
+type stubDecoder struct {
+	codec *stubCodec
+	dec   *json.Decoder
+}
+
+func (d stubDecoder) Decode(v interface{}) error {
+	atomic.AddInt32(&d.codec.decodes, 1)
+	return d.dec.Decode(v)
+}
+
+func TestCodec(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pinning/pinJSONToIPFS":
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			require.Equal(t, map[string]interface{}{"hello": "world"}, payload["pinataContent"])
+			w.Write(fixture(t, "POST /pinning/pinJSONToIPFS"))
+		case "/data/pinList":
+			require.Equal(t, `{"env":"prod"}`, r.URL.Query().Get("metadata"))
+			w.Write(fixture(t, "GET /data/pinList"))
+		case "/groups":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Bad group"}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	stub := &stubCodec{}
+	codecs := map[string]Codec{
+		"encoding/json": nil,
+		"stub codec":    stub,
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			var client *Client
+			if codec == nil {
+				client = New(&Auth{jwt: "valid_jwt_token"})
+			} else {
+				client = New(&Auth{jwt: "valid_jwt_token"}, WithCodec(codec))
+			}
+			client.baseURL = mockServer.URL
+
+			pinned, err := client.PinJSON(map[string]string{"hello": "world"}, nil)
+			require.NoError(t, err)
+			require.Equal(t, fixtures.Cid, pinned.IpfsHash)
+
+			files, err := client.ListFiles(&ListFilesOptions{Metadata: map[string]interface{}{"env": "prod"}})
+			require.NoError(t, err)
+			require.Equal(t, fixtures.Cid, files.Rows[0].IPFSPinHash)
+
+			_, err = client.CreateGroup("group")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "Bad group")
+		})
+	}
+
+	require.Equal(t, int32(3), atomic.LoadInt32(&stub.marshals))
+	require.Equal(t, int32(3), atomic.LoadInt32(&stub.decodes))
+}
+
+func TestDefaultCodec(t *testing.T) {
+	rb := &requestBuilder{}
+
+	require.Equal(t, jsonCodec{}, rb.codec())
+
+	client := New(&Auth{}, WithCodec(&stubCodec{}))
+	rb = client.NewRequest(http.MethodGet, "/test")
+
+	require.IsType(t, &stubCodec{}, rb.codec())
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	canonical, err := canonicalJSON([]byte(`{"b": [3, {"d": 1, "c": 12345678901234567890}], "a": "<x>"}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"a":"\u003cx\u003e","b":[3,{"c":12345678901234567890,"d":1}]}`, string(canonical))
+
+	_, err = canonicalJSON([]byte(`{"a":`))
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package pinata
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig is returned by NewFromConfig when the Config is invalid.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Config is a plain-struct alternative to ClientOption values, for configurations loaded from
+// YAML, JSON or the environment. Its tags follow the conventions of the common decoders, such as
+// gopkg.in/yaml.v3, encoding/json and environment-variable loaders reading the env tag.
+// BaseURL is the base URL of API requests (BaseURL when empty).
+// UploadURL is the base URL of file uploads (the API host when empty); see Profile.
+// GatewayHost is the gateway content is retrieved from (GatewayURL when empty). A bare host, such
+// as "example.mypinata.cloud", is reached over HTTPS.
+// TimeoutSeconds is the time limit of each request, see WithTimeout (90 seconds when zero).
+// MaxRetries is the number of retries of API requests, see WithRetryPolicy, and of gateway requests,
+// see GatewayRetryPolicy. Zero keeps the defaults, which retry neither, and -1 disables both.
+// RateLimitPerMinute limits API requests, see WithAPIRateLimit, with bursts of up to a second's
+// worth of requests (no limit when zero).
+// Concurrency is the default concurrency of the batch helpers, see WithConcurrency.
+// JWT, or APIKey and Secret, are the credentials of the client; one of them is required.
+// GatewayToken is the access token of a restricted dedicated gateway, see WithGatewayToken.
+type Config struct {
+	BaseURL            string `yaml:"base_url" json:"base_url" env:"PINATA_BASE_URL"`
+	UploadURL          string `yaml:"upload_url" json:"upload_url" env:"PINATA_UPLOAD_URL"`
+	GatewayHost        string `yaml:"gateway_host" json:"gateway_host" env:"PINATA_GATEWAY_HOST"`
+	TimeoutSeconds     int    `yaml:"timeout_seconds" json:"timeout_seconds" env:"PINATA_TIMEOUT_SECONDS"`
+	MaxRetries         int    `yaml:"max_retries" json:"max_retries" env:"PINATA_MAX_RETRIES"`
+	RateLimitPerMinute int    `yaml:"rate_limit_per_minute" json:"rate_limit_per_minute" env:"PINATA_RATE_LIMIT_PER_MINUTE"`
+	Concurrency        int    `yaml:"concurrency" json:"concurrency" env:"PINATA_CONCURRENCY"`
+	JWT                string `yaml:"jwt" json:"jwt" env:"PINATA_JWT"`
+	APIKey             string `yaml:"api_key" json:"api_key" env:"PINATA_API_KEY"`
+	Secret             string `yaml:"secret" json:"secret" env:"PINATA_SECRET"`
+	GatewayToken       string `yaml:"gateway_token" json:"gateway_token" env:"PINATA_GATEWAY_TOKEN"`
+}
+
+// NewFromConfig creates a client from cfg. The fields of cfg are validated and translated to the
+// equivalent ClientOption values, which are applied before opts, so the two ways of configuring a
+// client behave the same. Every invalid field is reported in an error wrapping ErrInvalidConfig.
+func NewFromConfig(cfg Config, opts ...ClientOption) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return New(NewAuth(cfg.APIKey, cfg.Secret, cfg.JWT), append(cfg.options(), opts...)...), nil
+}
+
+// Validate checks cfg, returning an error wrapping ErrInvalidConfig that lists every invalid field.
+func (cfg Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidConfig}, args...)...))
+	}
+
+	switch {
+	case cfg.JWT == "" && cfg.APIKey == "" && cfg.Secret == "":
+		invalid("credentials are required: set jwt, or api_key and secret")
+	case cfg.APIKey != "" && cfg.Secret == "":
+		invalid("api_key is set without secret")
+	case cfg.Secret != "" && cfg.APIKey == "":
+		invalid("secret is set without api_key")
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"base_url", cfg.BaseURL},
+		{"upload_url", cfg.UploadURL},
+		{"gateway_host", gatewayHostURL(cfg.GatewayHost)},
+	} {
+		if field.value == "" {
+			continue
+		}
+		u, err := url.Parse(field.value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("%s %q is not an http or https URL", field.name, field.value)
+		}
+	}
+
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"timeout_seconds", cfg.TimeoutSeconds},
+		{"rate_limit_per_minute", cfg.RateLimitPerMinute},
+		{"concurrency", cfg.Concurrency},
+	} {
+		if field.value < 0 {
+			invalid("%s must not be negative, got %d", field.name, field.value)
+		}
+	}
+	if cfg.MaxRetries < -1 {
+		invalid("max_retries must be -1 (no retries) or more, got %d", cfg.MaxRetries)
+	}
+
+	return errors.Join(errs...)
+}
+
+// options returns the ClientOption values equivalent to cfg.
+func (cfg Config) options() []ClientOption {
+	profile := DefaultProfile()
+	profile.UploadURL = cfg.UploadURL
+	if cfg.BaseURL != "" {
+		profile.APIURL = cfg.BaseURL
+	}
+	if cfg.GatewayHost != "" {
+		profile.GatewayURL = gatewayHostURL(cfg.GatewayHost)
+	}
+
+	opts := []ClientOption{WithProfile(profile)}
+	if cfg.TimeoutSeconds > 0 {
+		opts = append(opts, WithTimeout(time.Duration(cfg.TimeoutSeconds)*time.Second))
+	}
+	if cfg.MaxRetries != 0 {
+		opts = append(opts,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: cfg.MaxRetries + 1}),
+			WithGatewayRetryPolicy(GatewayRetryPolicy{MaxRetries: cfg.MaxRetries}),
+		)
+	}
+	if cfg.RateLimitPerMinute > 0 {
+		rate := float64(cfg.RateLimitPerMinute) / 60
+		opts = append(opts, WithAPIRateLimit(rate, int(math.Ceil(rate))))
+	}
+	if cfg.Concurrency > 0 {
+		opts = append(opts, WithConcurrency(cfg.Concurrency))
+	}
+	if cfg.GatewayToken != "" {
+		opts = append(opts, WithGatewayToken(cfg.GatewayToken))
+	}
+	return opts
+}
+
+// gatewayHostURL returns the URL of the gateway host, which is reached over HTTPS when it has no
+// scheme.
+func gatewayHostURL(host string) string {
+	if host == "" || strings.Contains(host, "://") {
+		return host
+	}
+	return "https://" + host
+}
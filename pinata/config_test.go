@@ -0,0 +1,185 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	t.Run("yaml fixture", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/config.yaml")
+		require.NoError(t, err)
+		var cfg Config
+		require.NoError(t, yaml.Unmarshal(data, &cfg))
+
+		client, err := NewFromConfig(cfg)
+		require.NoError(t, err)
+		require.Equal(t, "https://api.staging.example.com", client.baseURL)
+		require.Equal(t, "https://uploads.staging.example.com", client.uploadURL)
+		require.Equal(t, "https://example.mypinata.cloud", client.gatewayURL)
+		require.Equal(t, 45*time.Second, client.httpClient.Timeout)
+		require.Equal(t, 2, client.gatewayRetryPolicy().MaxRetries)
+		require.Equal(t, 3, client.retryPolicy().MaxAttempts)
+		require.Equal(t, 3.0, client.apiLimiter.rate)
+		require.Equal(t, 8, client.workers(0))
+		require.Equal(t, "gateway-secret", client.gatewayToken)
+		require.Equal(t, "staging-jwt", client.currentAuth().jwt)
+
+		// the same client built with options
+		withOptions := New(NewAuthWithJWT("staging-jwt"),
+			WithProfile(Profile{
+				APIURL:     "https://api.staging.example.com/",
+				UploadURL:  "https://uploads.staging.example.com",
+				GatewayURL: "https://example.mypinata.cloud",
+			}),
+			WithTimeout(45*time.Second),
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 3}),
+			WithGatewayRetryPolicy(GatewayRetryPolicy{MaxRetries: 2}),
+			WithAPIRateLimit(3, 3),
+			WithConcurrency(8),
+			WithGatewayToken("gateway-secret"),
+		)
+		require.Equal(t, withOptions.baseURL, client.baseURL)
+		require.Equal(t, withOptions.uploadURL, client.uploadURL)
+		require.Equal(t, withOptions.gatewayURL, client.gatewayURL)
+		require.Equal(t, withOptions.httpClient.Timeout, client.httpClient.Timeout)
+		require.Equal(t, withOptions.gatewayRetry, client.gatewayRetry)
+		require.Equal(t, withOptions.retry, client.retry)
+		require.Equal(t, withOptions.apiLimiter.rate, client.apiLimiter.rate)
+		require.Equal(t, withOptions.apiLimiter.burst, client.apiLimiter.burst)
+		require.Equal(t, withOptions.concurrency, client.concurrency)
+		require.Equal(t, withOptions.gatewayToken, client.gatewayToken)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		client, err := NewFromConfig(Config{APIKey: "key", Secret: "secret"})
+		require.NoError(t, err)
+		defaults := New(NewAuth("key", "secret", ""))
+		require.Equal(t, defaults.baseURL, client.baseURL)
+		require.Equal(t, defaults.gatewayHosts(""), client.gatewayHosts(""))
+		require.Equal(t, defaults.hostFor("/pinning/pinFileToIPFS"), client.hostFor("/pinning/pinFileToIPFS"))
+		require.Equal(t, defaults.httpClient.Timeout, client.httpClient.Timeout)
+		require.Equal(t, defaults.gatewayRetryPolicy(), client.gatewayRetryPolicy())
+		require.Equal(t, defaults.retryPolicy(), client.retryPolicy())
+		require.Nil(t, client.apiLimiter)
+		require.Equal(t, defaultConcurrency, client.workers(0))
+	})
+
+	t.Run("options are applied after the config", func(t *testing.T) {
+		client, err := NewFromConfig(Config{JWT: "jwt", TimeoutSeconds: 10}, WithTimeout(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, client.httpClient.Timeout)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			cfg  Config
+			want []string
+		}{
+			{"no credentials", Config{}, []string{"credentials are required"}},
+			{"key without secret", Config{APIKey: "key"}, []string{"api_key is set without secret"}},
+			{"secret without key", Config{Secret: "secret"}, []string{"secret is set without api_key"}},
+			{"relative base url", Config{JWT: "jwt", BaseURL: "api.pinata.cloud"}, []string{`base_url "api.pinata.cloud" is not an http or https URL`}},
+			{"ftp upload url", Config{JWT: "jwt", UploadURL: "ftp://uploads"}, []string{`upload_url "ftp://uploads" is not an http or https URL`}},
+			{"negative numbers", Config{JWT: "jwt", TimeoutSeconds: -1, RateLimitPerMinute: -5, Concurrency: -2, MaxRetries: -3}, []string{
+				"timeout_seconds must not be negative, got -1",
+				"rate_limit_per_minute must not be negative, got -5",
+				"concurrency must not be negative, got -2",
+				"max_retries must be -1 (no retries) or more, got -3",
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				client, err := NewFromConfig(tt.cfg)
+				require.ErrorIs(t, err, ErrInvalidConfig)
+				require.Nil(t, client)
+				for _, want := range tt.want {
+					require.ErrorContains(t, err, want)
+				}
+			})
+		}
+	})
+}
+
+func TestConfigOptions(t *testing.T) {
+	t.Run("requests use the configured hosts and tokens", func(t *testing.T) {
+		var paths []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			if r.URL.Path == "/data/testAuthentication" {
+				require.Equal(t, "Bearer jwt", r.Header.Get("Authorization"))
+				serveFixture(w, r)
+				return
+			}
+			require.Equal(t, "gateway-secret", r.Header.Get("x-pinata-gateway-token"))
+			w.Write([]byte("content"))
+		}))
+		defer mockServer.Close()
+
+		client, err := NewFromConfig(Config{JWT: "jwt", BaseURL: mockServer.URL, GatewayHost: mockServer.URL, GatewayToken: "gateway-secret"})
+		require.NoError(t, err)
+		_, err = client.TestAuthentication()
+		require.NoError(t, err)
+		content, err := client.Gateway().Get(context.Background(), cidV0)
+		require.NoError(t, err)
+		content.Body.Close()
+		require.Equal(t, []string{"/data/testAuthentication", "/ipfs/" + cidV0}, paths)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		var requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			serveFixture(w, r)
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithAPIRateLimit(1.0/60, 1))
+		client.baseURL = mockServer.URL
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+
+		// the next request is only allowed a minute later, so it waits until its context is done
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = client.TestAuthenticationWithContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				max := maxInFlight.Load()
+				if n <= max || maxInFlight.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			serveFixture(w, r)
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithConcurrency(2))
+		client.baseURL = mockServer.URL
+
+		items := make([]JSONPinItem, 20)
+		for i := range items {
+			items[i] = JSONPinItem{Data: map[string]int{"n": i}}
+		}
+		_, err := client.PinJSONsAsync(context.Background(), items, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, maxInFlight.Load())
+	})
+}
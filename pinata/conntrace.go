@@ -0,0 +1,144 @@
+package pinata
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityConnTrace)
+}
+
+// ConnTrace describes the connection used by an API request, as observed with net/http/httptrace.
+// Method and Path identify the request.
+// Proto is the protocol of the response, such as "HTTP/1.1" or "HTTP/2.0"; it is empty when the
+// request failed before a response.
+// Reused reports whether the connection had served an earlier request.
+// WasIdle reports whether the connection was taken from the idle pool, and IdleTime how long it
+// had been idle.
+// DNS, Connect and TLSHandshake are the durations of the DNS lookup, the TCP connection and the
+// TLS handshake; they are zero for reused connections.
+// TimeToFirstByte is the time from the start of the request to the first byte of the response.
+type ConnTrace struct {
+	Method          string
+	Path            string
+	Proto           string
+	Reused          bool
+	WasIdle         bool
+	IdleTime        time.Duration
+	DNS             time.Duration
+	Connect         time.Duration
+	TLSHandshake    time.Duration
+	TimeToFirstByte time.Duration
+}
+
+// WithConnTrace enables connection tracing of API requests, to diagnose connection churn in
+// long-running uploaders. After every request, handler, when not nil, receives the ConnTrace of
+// the request, and the logger set with WithLogger logs it at the debug level. The handler may be
+// called concurrently by calls running in parallel.
+func WithConnTrace(handler func(ConnTrace)) ClientOption {
+	return func(c *Client) {
+		c.connTrace = true
+		c.onConnTrace = handler
+	}
+}
+
+// connTracer collects the events of a request. Some events are delivered from the goroutines
+// dialing the connection, hence the mutex.
+type connTracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	trace        ConnTrace
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+}
+
+// traceConn attaches a ClientTrace to req when connection tracing is enabled. The returned
+// function reports the trace once the response, or the error, of the request is known.
+func (c *Client) traceConn(req *http.Request) (*http.Request, func(*http.Response)) {
+	if !c.connTrace {
+		return req, func(*http.Response) {}
+	}
+
+	t := &connTracer{start: time.Now(), trace: ConnTrace{Method: req.Method, Path: req.URL.Path}}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.trace.Reused = info.Reused
+			t.trace.WasIdle = info.WasIdle
+			t.trace.IdleTime = info.IdleTime
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.since(t.dnsStart, &t.trace.DNS)
+		},
+		ConnectStart: func(string, string) { t.mark(&t.connectStart) },
+		ConnectDone: func(string, string, error) {
+			t.since(t.connectStart, &t.trace.Connect)
+		},
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.since(t.tlsStart, &t.trace.TLSHandshake)
+		},
+		GotFirstResponseByte: func() {
+			t.since(t.start, &t.trace.TimeToFirstByte)
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	return req, func(resp *http.Response) {
+		t.mu.Lock()
+		result := t.trace
+		t.mu.Unlock()
+		if resp != nil {
+			result.Proto = resp.Proto
+		}
+		c.reportConnTrace(result)
+	}
+}
+
+// mark records the current time in *at, keeping the first one when several connections are dialed.
+func (t *connTracer) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+// since stores the time elapsed since start in *d.
+func (t *connTracer) since(start time.Time, d *time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !start.IsZero() {
+		*d = time.Since(start)
+	}
+}
+
+// reportConnTrace passes trace to the connection trace handler and to the logger, when they are set.
+func (c *Client) reportConnTrace(trace ConnTrace) {
+	if c.onConnTrace != nil {
+		c.onConnTrace(trace)
+	}
+	if c.logger != nil {
+		c.logger.Debug("connection trace",
+			slog.String("method", trace.Method),
+			slog.String("path", trace.Path),
+			slog.String("proto", trace.Proto),
+			slog.Bool("reused", trace.Reused),
+			slog.Bool("was_idle", trace.WasIdle),
+			slog.Duration("idle_time", trace.IdleTime),
+			slog.Duration("dns", trace.DNS),
+			slog.Duration("connect", trace.Connect),
+			slog.Duration("tls_handshake", trace.TLSHandshake),
+			slog.Duration("ttfb", trace.TimeToFirstByte),
+			slog.String("sdk_version", Version()),
+		)
+	}
+}
@@ -0,0 +1,75 @@
+package pinata
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnTrace(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	t.Run("reports connection reuse", func(t *testing.T) {
+		var mu sync.Mutex
+		var traces []ConnTrace
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(logger), WithConnTrace(func(trace ConnTrace) {
+			mu.Lock()
+			defer mu.Unlock()
+			traces = append(traces, trace)
+		}))
+		client.baseURL = mockServer.URL
+
+		for i := 0; i < 2; i++ {
+			var response AuthTestResponse
+			require.NoError(t, client.NewRequest(http.MethodGet, "/data/testAuthentication").Send(&response))
+		}
+
+		require.Len(t, traces, 2)
+		require.Equal(t, "GET", traces[0].Method)
+		require.Equal(t, "/data/testAuthentication", traces[0].Path)
+		require.Equal(t, "HTTP/1.1", traces[0].Proto)
+		require.False(t, traces[0].Reused)
+		require.Positive(t, traces[0].Connect)
+		require.Positive(t, traces[0].TimeToFirstByte)
+
+		require.True(t, traces[1].Reused)
+		require.True(t, traces[1].WasIdle)
+		require.Zero(t, traces[1].Connect)
+
+		require.Contains(t, logs.String(), `msg="connection trace"`)
+		require.Contains(t, logs.String(), "reused=true")
+	})
+
+	t.Run("reports failed requests", func(t *testing.T) {
+		var traces []ConnTrace
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithConnTrace(func(trace ConnTrace) {
+			traces = append(traces, trace)
+		}))
+		client.baseURL = "http://127.0.0.1:1"
+
+		require.Error(t, client.NewRequest(http.MethodGet, "/data/testAuthentication").Send(nil))
+		require.Len(t, traces, 1)
+		require.Empty(t, traces[0].Proto)
+		require.False(t, traces[0].Reused)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(logger))
+		client.baseURL = mockServer.URL
+
+		require.NoError(t, client.NewRequest(http.MethodGet, "/data/testAuthentication").Send(nil))
+		require.NotContains(t, logs.String(), "connection trace")
+	})
+}
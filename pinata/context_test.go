@@ -0,0 +1,303 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// contextFreeMethods are the exported methods with neither a context parameter nor a WithContext
+// variant.
+var contextFreeMethods = map[string]string{
+	"Client.NewRequest":         "request builder; see requestBuilder.WithContext",
+	"Client.Files":              "sub-client accessor",
+	"Client.Gateway":            "sub-client accessor",
+	"Client.Groups":             "sub-client accessor",
+	"Client.Keys":               "sub-client accessor",
+	"Client.NewPinQueue":        "constructor; uploads are cancelled by PinQueue.Shutdown",
+	"Client.GetGroup":           "deprecated; use Groups().GetWithContext",
+	"Client.UpdateGroup":        "deprecated; use Groups().UpdateWithContext",
+	"Client.AddCidToGroup":      "deprecated; use Groups().AddCidsWithContext",
+	"Client.RemoveCidFromGroup": "deprecated; use Groups().RemoveCidsWithContext",
+	"Client.RemoveGroup":        "deprecated; use Groups().RemoveWithContext",
+	"Client.GenerateApiKeyV3":   "alias; use Keys().GenerateWithContext",
+	"Client.ListApiKeyV3":       "alias; use Keys().ListWithContext",
+	"Client.ListApiKeyV3Page":   "alias; use Keys().ListPageWithContext",
+	"Client.RevokeApiKeyV3":     "alias; use Keys().RevokeWithContext",
+	"Client.ActiveOperations":   "local snapshot; sends no request",
+	"Client.LastRateLimit":      "local snapshot; sends no request",
+}
+
+// contextCalls calls every WithContext method with ctx, against a client whose API is served by
+// server and with the files of dir.
+var contextCalls = map[string]func(ctx context.Context, c *Client, server, dir string) error{
+	"Client.TestAuthenticationWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.TestAuthenticationWithContext(ctx)
+		return err
+	},
+	"Client.PinFileWithContext": func(ctx context.Context, c *Client, _, dir string) error {
+		_, err := c.PinFileWithContext(ctx, filepath.Join(dir, "a.txt"), nil)
+		return err
+	},
+	"Client.PinOpenFileWithContext": func(ctx context.Context, c *Client, _, dir string) error {
+		f, err := os.Open(filepath.Join(dir, "a.txt"))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = c.PinOpenFileWithContext(ctx, f, "", nil)
+		return err
+	},
+	"Client.PinFilesAsyncWithContext": func(ctx context.Context, c *Client, _, dir string) error {
+		_, err := c.PinFilesAsyncWithContext(ctx, []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, nil)
+		return err
+	},
+	"Client.PinURLWithContext": func(ctx context.Context, c *Client, server, _ string) error {
+		_, err := c.PinURLWithContext(ctx, server+"/origin/file.txt", nil)
+		return err
+	},
+	"Client.PinFolderWithContext": func(ctx context.Context, c *Client, _, dir string) error {
+		_, err := c.PinFolderWithContext(ctx, []string{filepath.Join(dir, "a.txt")}, nil)
+		return err
+	},
+	"Client.PinNestedFoldersWithContext": func(ctx context.Context, c *Client, _, dir string) error {
+		_, err := c.PinNestedFoldersWithContext(ctx, dir, []string{filepath.Join(dir, "a.txt")}, nil)
+		return err
+	},
+	"Client.PinJSONWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.PinJSONWithContext(ctx, map[string]string{"a": "b"}, nil)
+		return err
+	},
+	"Client.PinByCidWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.PinByCidWithContext(ctx, cidV0, nil)
+		return err
+	},
+	"Client.ListFilesWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListFilesWithContext(ctx, nil)
+		return err
+	},
+	"Client.ListFilesPageWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListFilesPageWithContext(ctx, nil)
+		return err
+	},
+	"Client.ListFilesAllWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListFilesAllWithContext(ctx, nil)
+		return err
+	},
+	"Client.ListPinByCidJobsWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListPinByCidJobsWithContext(ctx, nil)
+		return err
+	},
+	"Client.ListPinByCidJobsPageWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListPinByCidJobsPageWithContext(ctx, nil)
+		return err
+	},
+	"Client.ListPinByCidJobsAllWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListPinByCidJobsAllWithContext(ctx, nil)
+		return err
+	},
+	"Client.UpdateFileMetadataWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.UpdateFileMetadataWithContext(ctx, cidV0, &PinMetadataUpdateOptions{Name: "renamed"})
+	},
+	"Client.DeleteFileWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.DeleteFileWithContext(ctx, cidV0)
+	},
+	"Client.UnpinWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.UnpinWithContext(ctx, cidV0)
+	},
+	"Client.DeleteFileByIDWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.DeleteFileByIDWithContext(ctx, "pin-1")
+	},
+	"Client.DeleteFilesAsyncWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return errors.Join(c.DeleteFilesAsyncWithContext(ctx, []string{cidV0, cidV1})...)
+	},
+	"Client.CreateGroupWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.CreateGroupWithContext(ctx, "docs")
+		return err
+	},
+	"Client.ListGroupsWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListGroupsWithContext(ctx, nil)
+		return err
+	},
+	"Client.ListGroupsPageWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListGroupsPageWithContext(ctx, nil)
+		return err
+	},
+	"Client.AddCidSignatureWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.AddCidSignatureWithContext(ctx, cidV0, "0xsignature")
+		return err
+	},
+	"Client.GetCidSignatureWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.GetCidSignatureWithContext(ctx, cidV0)
+		return err
+	},
+	"Client.RemoveCidSignatureWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.RemoveCidSignatureWithContext(ctx, cidV0)
+	},
+	"Client.AddSwapWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.AddSwapWithContext(ctx, cidV0, cidV1, nil)
+		return err
+	},
+	"Client.GetSwapHistoryWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.GetSwapHistoryWithContext(ctx, cidV0, "example.mypinata.cloud")
+		return err
+	},
+	"Client.RemoveSwapWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.RemoveSwapWithContext(ctx, cidV0)
+		return err
+	},
+	"Client.GenerateApiKeyWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.GenerateApiKeyWithContext(ctx, &GenerateApiKeyOptions{KeyName: "key"})
+		return err
+	},
+	"Client.ListApiKeysWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.ListApiKeysWithContext(ctx)
+		return err
+	},
+	"Client.RevokeApiKeyWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.RevokeApiKeyWithContext(ctx, "key")
+	},
+	"Client.PinnedFileCountWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.PinnedFileCountWithContext(ctx)
+		return err
+	},
+	"Client.TotalStorageSizeWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, _, err := c.TotalStorageSizeWithContext(ctx)
+		return err
+	},
+	"GroupsClient.CreateWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.Groups().CreateWithContext(ctx, "docs")
+		return err
+	},
+	"GroupsClient.GetWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.Groups().GetWithContext(ctx, "g1")
+		return err
+	},
+	"GroupsClient.ListWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.Groups().ListWithContext(ctx, nil)
+		return err
+	},
+	"GroupsClient.UpdateWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.Groups().UpdateWithContext(ctx, "g1", "docs")
+		return err
+	},
+	"GroupsClient.AddCidsWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.Groups().AddCidsWithContext(ctx, "g1", []Cid{cidV0})
+	},
+	"GroupsClient.RemoveCidsWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.Groups().RemoveCidsWithContext(ctx, "g1", []Cid{cidV0})
+	},
+	"GroupsClient.RemoveWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.Groups().RemoveWithContext(ctx, "g1")
+	},
+	"KeysClient.GenerateWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.Keys().GenerateWithContext(ctx, &GenerateApiKeyOptions{KeyName: "key"})
+		return err
+	},
+	"KeysClient.ListWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.Keys().ListWithContext(ctx, nil)
+		return err
+	},
+	"KeysClient.ListPageWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		_, err := c.Keys().ListPageWithContext(ctx, nil)
+		return err
+	},
+	"KeysClient.RevokeWithContext": func(ctx context.Context, c *Client, _, _ string) error {
+		return c.Keys().RevokeWithContext(ctx, "key")
+	},
+}
+
+// contextTypes are the types whose exported methods must all have a context entry point.
+var contextTypes = []reflect.Type{
+	reflect.TypeOf(&Client{}),
+	reflect.TypeOf(&GroupsClient{}),
+	reflect.TypeOf(&KeysClient{}),
+	reflect.TypeOf(&FilesClient{}),
+	reflect.TypeOf(&GatewayClient{}),
+}
+
+func TestContextEntryPoints(t *testing.T) {
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	for _, typ := range contextTypes {
+		for i := 0; i < typ.NumMethod(); i++ {
+			method := typ.Method(i)
+			name := typ.Elem().Name() + "." + method.Name
+			if _, ok := contextFreeMethods[name]; ok {
+				continue
+			}
+			// the receiver is the first parameter
+			if method.Type.NumIn() > 1 && method.Type.In(1) == contextType {
+				if strings.HasSuffix(method.Name, "WithContext") {
+					require.Containsf(t, contextCalls, name, "%s is not covered by TestContextCancellation", name)
+				}
+				continue
+			}
+
+			variant, ok := typ.MethodByName(method.Name + "WithContext")
+			require.Truef(t, ok, "%s has no context parameter and no WithContext variant", name)
+			require.Equalf(t, method.Type.NumIn()+1, variant.Type.NumIn(), "parameters of %sWithContext", name)
+			require.Equalf(t, contextType, variant.Type.In(1), "%sWithContext does not take a context first", name)
+			for in := 1; in < method.Type.NumIn(); in++ {
+				require.Equalf(t, method.Type.In(in), variant.Type.In(in+1), "parameter %d of %sWithContext", in, name)
+			}
+			require.Equalf(t, method.Type.NumOut(), variant.Type.NumOut(), "results of %sWithContext", name)
+			for out := 0; out < method.Type.NumOut(); out++ {
+				require.Equalf(t, method.Type.Out(out), variant.Type.Out(out), "result %d of %sWithContext", out, name)
+			}
+		}
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	// the server holds every request until the client gives up on it
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the disconnection of the client is only noticed once the body was read
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	// the subtests run in parallel, after the test function returns
+	t.Cleanup(mockServer.Close)
+	dir := writeTree(t, "a.txt", "b.txt")
+
+	for name, call := range contextCalls {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(mockServer.URL))
+			client.baseURL = mockServer.URL
+			client.uploadURL = mockServer.URL
+
+			t.Run("deadline", func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				start := time.Now()
+				err := call(ctx, client, mockServer.URL, dir)
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+				require.Less(t, time.Since(start), 2*time.Second)
+			})
+
+			t.Run("cancel", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+
+				start := time.Now()
+				err := call(ctx, client, mockServer.URL, dir)
+				require.ErrorIs(t, err, context.Canceled)
+				require.Less(t, time.Since(start), 2*time.Second)
+			})
+		})
+	}
+}
@@ -0,0 +1,370 @@
+// Package contracts describes the request payloads sent by the pinata client, so that the JSON
+// schemas used by contract tests against Pinata mocks can be generated instead of hand-written.
+//
+// Every operation is run with representative arguments against a Capture transport, which
+// records the requests instead of sending them. The captured bodies are reduced to schemas that
+// reflect exactly what the SDK sends, and OpenAPI assembles them into an OpenAPI document.
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zde37/pinata-go-sdk/pinata"
+)
+
+// Request is a request recorded by Capture.
+// Method is the HTTP method.
+// Path is the unescaped URL path.
+// ContentType is the Content-Type header, if any.
+// Body is the request body.
+type Request struct {
+	Method      string
+	Path        string
+	ContentType string
+	Body        []byte
+}
+
+// Capture is an http.RoundTripper that records every request and answers it with 200 OK and an
+// empty JSON object, without sending anything. It is safe for concurrent use.
+type Capture struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Capture) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	c.requests = append(c.requests, Request{
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		ContentType: req.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	c.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the requests recorded so far.
+func (c *Capture) Requests() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Request(nil), c.requests...)
+}
+
+// Operation is a client operation run with representative arguments. Path parameters are passed
+// as "{name}" placeholders, so captured paths are the endpoint templates.
+// Name is the name of the client method.
+// Call runs the operation with client.
+type Operation struct {
+	Name string
+	Call func(client *pinata.Client) error
+}
+
+// Operations returns the operations whose payloads are described, in a stable order. file is the
+// path of the file uploaded by the file pinning operation.
+func Operations(file string) []Operation {
+	metadata := pinata.PinataMetadata{
+		Name:      "example",
+		KeyValues: map[string]interface{}{"env": "test", "version": 1},
+	}
+	options := &pinata.PinOptions{PinataMetadata: metadata, PinataOptions: pinata.Options{CidVersion: 1}}
+	key := &pinata.GenerateApiKeyOptions{KeyName: "ci", Permissions: pinata.PermissionsUploadOnly(), MaxUses: 10}
+
+	return []Operation{
+		{"PinFile", func(c *pinata.Client) error {
+			_, err := c.PinFile(file, options)
+			return err
+		}},
+		{"PinJSON", func(c *pinata.Client) error {
+			_, err := c.PinJSON(map[string]interface{}{"hello": "world"}, options)
+			return err
+		}},
+		{"PinByCid", func(c *pinata.Client) error {
+			_, err := c.PinByCid(exampleCID, &pinata.PinByCidOptions{
+				PinataMetadata: metadata,
+				PinataOptions:  pinata.PinOpts{GroupId: "group-id", HostNodes: []string{"/ip4/203.0.113.1/tcp/4001/p2p/12D3KooWExample"}},
+			})
+			return err
+		}},
+		{"UpdateFileMetadata", func(c *pinata.Client) error {
+			return c.UpdateFileMetadata(exampleCID, &pinata.PinMetadataUpdateOptions{Name: metadata.Name, KeyValues: metadata.KeyValues})
+		}},
+		{"GenerateApiKey", func(c *pinata.Client) error {
+			_, err := c.GenerateApiKey(key)
+			return err
+		}},
+		{"GenerateApiKeyV3", func(c *pinata.Client) error {
+			_, err := c.Keys().Generate(key)
+			return err
+		}},
+		{"CreateGroup", func(c *pinata.Client) error {
+			_, err := c.CreateGroup("example")
+			return err
+		}},
+		{"UpdateGroup", func(c *pinata.Client) error {
+			_, err := c.UpdateGroup("{id}", "example")
+			return err
+		}},
+		{"AddCidToGroup", func(c *pinata.Client) error {
+			return c.AddCidToGroup("{id}", []string{exampleCID})
+		}},
+		{"RemoveCidFromGroup", func(c *pinata.Client) error {
+			return c.RemoveCidFromGroup("{id}", []string{exampleCID})
+		}},
+		{"AddSwap", func(c *pinata.Client) error {
+			_, err := c.AddSwap("{cid}", exampleCID, &pinata.AddSwapOptions{VerifyTarget: pinata.Bool(false)})
+			return err
+		}},
+		{"AddCidSignature", func(c *pinata.Client) error {
+			_, err := c.AddCidSignature("{cid}", "0x1234")
+			return err
+		}},
+		{"QueryVectors", func(c *pinata.Client) error {
+			_, err := c.QueryVectors(context.Background(), "{id}", "example query", 10)
+			return err
+		}},
+	}
+}
+
+// exampleCID is the CID used in representative payloads.
+const exampleCID = "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+
+// Payload describes the request sent by an operation.
+// Operation is the name of the client method.
+// Method and Path identify the endpoint; Path is the endpoint template.
+// ContentType is the media type of the body, without parameters such as the multipart boundary.
+// Example is the body sent with the representative arguments. Multipart bodies are given as an
+// object of their fields, with JSON fields decoded and files replaced by "<binary>".
+// Schema is the schema of Example.
+type Payload struct {
+	Operation   string          `json:"operation"`
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	ContentType string          `json:"contentType"`
+	Example     json.RawMessage `json:"example"`
+	Schema      *Schema         `json:"schema"`
+}
+
+// Describe runs every operation against a Capture and returns the payload of each request sent,
+// in the order of Operations.
+func Describe() ([]Payload, error) {
+	file, err := os.CreateTemp("", "contracts-*.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString("example content"); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	var payloads []Payload
+	for _, op := range Operations(file.Name()) {
+		capture := &Capture{}
+		client := pinata.New(pinata.NewAuthWithJWT("jwt"), pinata.WithTransport(capture))
+		if err := op.Call(client); err != nil {
+			return nil, fmt.Errorf("%s: %w", op.Name, err)
+		}
+
+		for _, req := range capture.Requests() {
+			payload, err := describe(op.Name, req)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op.Name, err)
+			}
+			payloads = append(payloads, payload)
+		}
+	}
+	return payloads, nil
+}
+
+// describe builds the Payload of a captured request.
+func describe(operation string, req Request) (Payload, error) {
+	payload := Payload{Operation: operation, Method: req.Method, Path: req.Path}
+	if len(req.Body) == 0 {
+		return payload, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.ContentType)
+	if err != nil {
+		return payload, fmt.Errorf("invalid content type %q: %w", req.ContentType, err)
+	}
+	payload.ContentType = mediaType
+
+	var example interface{}
+	switch mediaType {
+	case "application/json":
+		decoder := json.NewDecoder(bytes.NewReader(req.Body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&example); err != nil {
+			return payload, err
+		}
+	case "multipart/form-data":
+		if example, err = multipartFields(req.Body, params["boundary"]); err != nil {
+			return payload, err
+		}
+	default:
+		example = string(req.Body)
+	}
+
+	if payload.Example, err = json.Marshal(example); err != nil {
+		return payload, err
+	}
+	payload.Schema = SchemaOf(example)
+	return payload, nil
+}
+
+// multipartFields returns the fields of a multipart body as an object. Fields holding JSON are
+// decoded, and file parts are replaced by "<binary>".
+func multipartFields(body []byte, boundary string) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return fields, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FileName() != "" {
+			fields[part.FormName()] = binaryExample
+			continue
+		}
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		var decoded interface{}
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		decoder.UseNumber()
+		if decoder.Decode(&decoded) == nil {
+			fields[part.FormName()] = decoded
+		} else {
+			fields[part.FormName()] = string(value)
+		}
+	}
+}
+
+// binaryExample stands for the content of file parts in multipart examples.
+const binaryExample = "<binary>"
+
+// Schema is the subset of an OpenAPI 3.0 schema that describes a payload. Nullable marks a value
+// sent as null, whose type is left unset as it cannot be told from the payload.
+type Schema struct {
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Nullable   bool               `json:"nullable,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+}
+
+// SchemaOf returns the schema of v, a value decoded from JSON with json.Number numbers. Every
+// property of an object is required, since the SDK omits the fields it does not send. The items
+// of an array are described by the schema of the first item.
+func SchemaOf(v interface{}) *Schema {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema, len(v))}
+		for key, value := range v {
+			schema.Properties[key] = SchemaOf(value)
+			schema.Required = append(schema.Required, key)
+		}
+		sort.Strings(schema.Required)
+		return schema
+	case []interface{}:
+		schema := &Schema{Type: "array"}
+		if len(v) > 0 {
+			schema.Items = SchemaOf(v[0])
+		}
+		return schema
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &Schema{Type: "integer"}
+		}
+		return &Schema{Type: "number"}
+	case float64:
+		return &Schema{Type: "number"}
+	case bool:
+		return &Schema{Type: "boolean"}
+	case string:
+		if v == binaryExample {
+			return &Schema{Type: "string", Format: "binary"}
+		}
+		return &Schema{Type: "string"}
+	}
+	return &Schema{Nullable: true}
+}
+
+// OpenAPI returns an OpenAPI 3.0 document describing the request bodies of the operations
+// returned by Describe, indented for committing to a repository.
+func OpenAPI() ([]byte, error) {
+	payloads, err := Describe()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]map[string]interface{})
+	for _, payload := range payloads {
+		operation := map[string]interface{}{"operationId": payload.Operation}
+		if payload.Schema != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					payload.ContentType: map[string]interface{}{
+						"schema":  payload.Schema,
+						"example": payload.Example,
+					},
+				},
+			}
+		}
+		operation["responses"] = map[string]interface{}{"default": map[string]string{"description": "Pinata API response"}}
+
+		if paths[payload.Path] == nil {
+			paths[payload.Path] = make(map[string]interface{})
+		}
+		paths[payload.Path][strings.ToLower(payload.Method)] = operation
+	}
+
+	document := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]string{"title": "Pinata API requests sent by pinata-go-sdk", "version": "1"},
+		"paths":   paths,
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
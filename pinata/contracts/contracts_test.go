@@ -0,0 +1,82 @@
+package contracts
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/openapi.json with the current payloads")
+
+func TestDescribe(t *testing.T) {
+	payloads, err := Describe()
+	require.NoError(t, err)
+
+	endpoints := make(map[string]string, len(payloads))
+	for _, payload := range payloads {
+		endpoints[payload.Operation] = payload.Method + " " + payload.Path
+		require.NotNil(t, payload.Schema, payload.Operation)
+		require.Equal(t, "object", payload.Schema.Type, payload.Operation)
+	}
+	require.Equal(t, map[string]string{
+		"PinFile":            "POST /pinning/pinFileToIPFS",
+		"PinJSON":            "POST /pinning/pinJSONToIPFS",
+		"PinByCid":           "POST /pinning/pinByHash",
+		"UpdateFileMetadata": "PUT /pinning/hashMetadata",
+		"GenerateApiKey":     "POST /users/generateApiKey",
+		"GenerateApiKeyV3":   "POST /v3/pinata/keys",
+		"CreateGroup":        "POST /groups",
+		"UpdateGroup":        "PUT /groups/{id}",
+		"AddCidToGroup":      "PUT /groups/{id}/cids",
+		"RemoveCidFromGroup": "DELETE /groups/{id}/cids",
+		"AddSwap":            "PUT /v3/ipfs/swap/{cid}",
+		"AddCidSignature":    "POST /v3/ipfs/signature/{cid}",
+		"QueryVectors":       "POST /v3/vectorize/groups/{id}/query",
+	}, endpoints)
+
+	t.Run("multipart fields", func(t *testing.T) {
+		upload := payloads[0]
+		require.Equal(t, "PinFile", upload.Operation)
+		require.Equal(t, "multipart/form-data", upload.ContentType)
+		require.Equal(t, &Schema{Type: "string", Format: "binary"}, upload.Schema.Properties["file"])
+		require.Equal(t, "object", upload.Schema.Properties["pinataMetadata"].Type)
+	})
+}
+
+// TestOpenAPI guards against accidental payload changes. Run the test with -update after an
+// intended change to regenerate the document.
+func TestOpenAPI(t *testing.T) {
+	document, err := OpenAPI()
+	require.NoError(t, err)
+	require.True(t, json.Valid(document))
+
+	if *update {
+		require.NoError(t, os.WriteFile("testdata/openapi.json", append(document, '\n'), 0o644))
+	}
+	expected, err := os.ReadFile("testdata/openapi.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(document), "request payloads changed, run go test -run TestOpenAPI -update if this is intended")
+}
+
+func TestSchemaOf(t *testing.T) {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(`{"a": 1, "b": 1.5, "c": [true], "d": null, "e": []}`))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&value))
+
+	require.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"a": {Type: "integer"},
+			"b": {Type: "number"},
+			"c": {Type: "array", Items: &Schema{Type: "boolean"}},
+			"d": {Nullable: true},
+			"e": {Type: "array"},
+		},
+		Required: []string{"a", "b", "c", "d", "e"},
+	}, SchemaOf(value))
+}
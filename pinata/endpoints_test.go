@@ -0,0 +1,293 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+// endpointResponses are the bodies returned by the endpoint table server for the requests that
+// do not go to the API, such as gateway and origin requests, keyed by method and path. API
+// requests are answered with the fixtures of internal/fixtures.
+var endpointResponses = map[string]string{
+	"GET /source":                  `source content`,
+	"HEAD /ipfs/" + HealthCheckCID: ``,
+	"GET /ipfs/" + cidV0:           `{"Data":{"/":{"bytes":"CAE"}},"Links":[{"Hash":{"/":"` + cidV1 + `"},"Name":"a.txt","Tsize":13}]}`,
+	"HEAD /ipfs/" + cidV0:          ``,
+	"GET /ipfs/" + cidV1:           `{"Data":{"/":{"bytes":"CAIYDQ"}},"Links":[]}`,
+}
+
+// fixture returns the response body of internal/fixtures for endpoint, a net/http pattern such as
+// "GET /groups/{id}". Mocks answer with the shared fixtures rather than JSON written for each test,
+// so that every test sees the same body.
+func fixture(t *testing.T, endpoint string) []byte {
+	t.Helper()
+	body, ok := fixtures.Body(endpoint)
+	require.Truef(t, ok, "no fixture for %s in internal/fixtures", endpoint)
+	return body
+}
+
+// fixtureHandler answers each API request with the fixture of its endpoint.
+var fixtureHandler = fixtures.Handler()
+
+// serveFixture answers r with the fixture of its endpoint, for mocks that stand in for several
+// endpoints.
+func serveFixture(w http.ResponseWriter, r *http.Request) {
+	fixtureHandler.ServeHTTP(w, r)
+}
+
+// endpointTable lists the requests each operation sends, as "METHOD /path" in any order. An
+// operation that sends no request, or whose requests depend on timing, is listed in
+// endpointTableExempt instead.
+var endpointTable = []struct {
+	op   Operation
+	call func(c *Client, dir string) error
+	want []string
+}{
+	{OpTestAuthentication, func(c *Client, _ string) error { _, err := c.TestAuthentication(); return err }, []string{"GET /data/testAuthentication"}},
+	{OpAuthHealthCheck, func(c *Client, _ string) error { c.AuthHealthCheck(context.Background()); return nil }, []string{"GET /data/testAuthentication"}},
+	{OpPinFile, func(c *Client, dir string) error { _, err := c.PinFile(filepath.Join(dir, "a.txt"), nil); return err }, []string{"POST /pinning/pinFileToIPFS"}},
+	{OpPinOpenFile, func(c *Client, dir string) error {
+		f, err := os.Open(filepath.Join(dir, "a.txt"))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = c.PinOpenFile(f, "a.txt", nil)
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS"}},
+	{OpPinReader, func(c *Client, _ string) error {
+		_, err := c.PinReader(context.Background(), strings.NewReader("content"), "stdin", nil, nil)
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS"}},
+	{OpPinFilesAsync, func(c *Client, dir string) error {
+		_, err := c.PinFilesAsync([]string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, nil)
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS", "POST /pinning/pinFileToIPFS"}},
+	{OpPinBatch, func(c *Client, dir string) error {
+		_, err := c.PinBatch(context.Background(), []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, nil)
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS", "POST /pinning/pinFileToIPFS"}},
+	{OpPinDirectory, func(c *Client, dir string) error {
+		_, err := c.PinDirectory(context.Background(), dir, nil)
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS", "POST /pinning/pinFileToIPFS"}},
+	{OpPinFolder, func(c *Client, dir string) error {
+		_, err := c.PinFolder([]string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, nil)
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS"}},
+	{OpPinNestedFolders, func(c *Client, dir string) error {
+		_, err := c.PinNestedFolders(dir, []string{filepath.Join(dir, "a.txt")}, nil)
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS"}},
+	{OpPinLargeDirectory, func(c *Client, dir string) error {
+		_, err := c.PinLargeDirectory(context.Background(), dir, LargeDirOptions{MaxFilesPerPart: 1})
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS", "POST /pinning/pinFileToIPFS", "POST /pinning/pinJSONToIPFS"}},
+	{OpPinURL, func(c *Client, _ string) error { _, err := c.PinURL(c.baseURL+"/source", nil); return err }, []string{"GET /source", "POST /pinning/pinFileToIPFS"}},
+	{OpMirrorURL, func(c *Client, _ string) error {
+		_, err := c.MirrorURL(context.Background(), c.baseURL+"/source", nil)
+		return err
+	}, []string{"GET /source", "POST /pinning/pinFileToIPFS"}},
+	{OpSwapTo, func(c *Client, dir string) error {
+		_, err := c.SwapTo(context.Background(), cidV1, filepath.Join(dir, "a.txt"))
+		return err
+	}, []string{"POST /pinning/pinFileToIPFS", "PUT /v3/ipfs/swap/" + cidV1}},
+	{OpPinJSON, func(c *Client, _ string) error { _, err := c.PinJSON(map[string]string{"a": "b"}, nil); return err }, []string{"POST /pinning/pinJSONToIPFS"}},
+	{OpPinJSONsAsync, func(c *Client, _ string) error {
+		_, err := c.PinJSONsAsync(context.Background(), []JSONPinItem{{Data: map[string]string{"a": "b"}}}, nil)
+		return err
+	}, []string{"POST /pinning/pinJSONToIPFS"}},
+	{OpPinByCid, func(c *Client, _ string) error { _, err := c.PinByCid(cidV0, nil); return err }, []string{"POST /pinning/pinByHash"}},
+	{OpPinCidToGroup, func(c *Client, _ string) error {
+		_, err := c.PinCidToGroup(context.Background(), cidV0, fixtures.GroupID, nil, false)
+		return err
+	}, []string{"GET /groups/" + fixtures.GroupID, "POST /pinning/pinByHash"}},
+	{OpListPinByCidJobs, func(c *Client, _ string) error { _, err := c.ListPinByCidJobs(nil); return err }, []string{"GET /pinning/pinJobs"}},
+	{OpListPinByCidJobsPage, func(c *Client, _ string) error { _, err := c.ListPinByCidJobsPage(nil); return err }, []string{"GET /pinning/pinJobs"}},
+	{OpListPinByCidJobsAll, func(c *Client, _ string) error { _, err := c.ListPinByCidJobsAll(nil); return err }, []string{"GET /pinning/pinJobs"}},
+	{OpUpdateFileMetadata, func(c *Client, _ string) error {
+		return c.UpdateFileMetadata(cidV0, &PinMetadataUpdateOptions{Name: "file"})
+	}, []string{"PUT /pinning/hashMetadata"}},
+	{OpDeleteFile, func(c *Client, _ string) error { return c.DeleteFile(cidV0) }, []string{"DELETE /pinning/unpin/" + cidV0}},
+	{OpUnpin, func(c *Client, _ string) error { return c.Unpin(cidV0) }, []string{"DELETE /pinning/unpin/" + cidV0}},
+	{OpDeleteFileByID, func(c *Client, _ string) error { return c.DeleteFileByID(fixtures.PinID) }, []string{"GET /data/pinList", "DELETE /pinning/unpin/" + cidV0}},
+	{OpDeleteFolder, func(c *Client, _ string) error {
+		_, err := c.DeleteFolder(context.Background(), cidV0, &DeleteFolderOptions{CheckChildren: true})
+		return err
+	}, []string{"GET /ipfs/" + cidV0, "GET /ipfs/" + cidV1, "DELETE /pinning/unpin/" + cidV0, "GET /data/pinList"}},
+	{OpDeleteFilesAsync, func(c *Client, _ string) error { c.DeleteFilesAsync([]string{cidV0, cidV1}); return nil }, []string{"DELETE /pinning/unpin/" + cidV0, "DELETE /pinning/unpin/" + cidV1}},
+	{OpListFiles, func(c *Client, _ string) error { _, err := c.ListFiles(nil); return err }, []string{"GET /data/pinList"}},
+	{OpListFilesPage, func(c *Client, _ string) error { _, err := c.ListFilesPage(nil); return err }, []string{"GET /data/pinList"}},
+	{OpListFilesAll, func(c *Client, _ string) error { _, err := c.ListFilesAll(nil); return err }, []string{"GET /data/pinList"}},
+	{OpStreamFiles, func(c *Client, _ string) error {
+		pins, errs := c.StreamFiles(context.Background(), nil, 0)
+		for range pins {
+		}
+		return <-errs
+	}, []string{"GET /data/pinList"}},
+	{OpListUngroupedPins, func(c *Client, _ string) error {
+		pins, errs := c.ListUngroupedPins(context.Background(), nil)
+		for range pins {
+		}
+		return <-errs
+	}, []string{"GET /groups", "GET /data/pinList", "GET /data/pinList"}},
+	{OpSearchFilesByName, func(c *Client, _ string) error {
+		pins, errs := c.SearchFilesByName(context.Background(), &NameSearchOptions{NamePrefix: "file"})
+		for range pins {
+		}
+		return <-errs
+	}, []string{"GET /data/pinList"}},
+	{OpGetPinByCid, func(c *Client, _ string) error { _, err := c.GetPinByCid(context.Background(), cidV0, nil); return err }, []string{"GET /data/pinList"}},
+	{OpIsPinned, func(c *Client, _ string) error { _, err := c.IsPinned(context.Background(), cidV0, nil); return err }, []string{"GET /data/pinList"}},
+	{OpPinStats, func(c *Client, _ string) error { _, err := c.PinStats(context.Background(), nil); return err }, []string{"GET /data/pinList", "GET /groups", "GET /data/pinList"}},
+	{OpAuditPins, func(c *Client, _ string) error { _, err := c.AuditPins(context.Background(), nil); return err }, []string{"GET /data/pinList", "HEAD /ipfs/" + cidV0}},
+	{OpPinnedFileCount, func(c *Client, _ string) error { _, err := c.PinnedFileCount(); return err }, []string{"GET /data/userPinnedDataTotal"}},
+	{OpTotalStorageSize, func(c *Client, _ string) error { _, _, err := c.TotalStorageSize(); return err }, []string{"GET /data/userPinnedDataTotal"}},
+	{OpGetPinnedDataTotal, func(c *Client, _ string) error { _, err := c.GetPinnedDataTotal(context.Background()); return err }, []string{"GET /data/userPinnedDataTotal"}},
+	{OpStatContent, func(c *Client, _ string) error { _, err := c.StatContent(context.Background(), cidV0); return err }, []string{"HEAD /ipfs/" + cidV0}},
+	{OpGetContent, func(c *Client, _ string) error {
+		content, err := c.GetContent(context.Background(), cidV0)
+		if err == nil {
+			content.Body.Close()
+		}
+		return err
+	}, []string{"GET /ipfs/" + cidV0}},
+	{OpHealthCheck, func(c *Client, _ string) error { c.HealthCheck(context.Background()); return nil }, []string{"HEAD /ipfs/" + HealthCheckCID}},
+	{OpListFolderContents, func(c *Client, _ string) error {
+		_, err := c.ListFolderContents(context.Background(), cidV0)
+		return err
+	}, []string{"GET /ipfs/" + cidV0, "GET /ipfs/" + cidV1}},
+	{OpListFolderContentsWithOptions, func(c *Client, _ string) error {
+		_, err := c.ListFolderContentsWithOptions(context.Background(), cidV0, nil)
+		return err
+	}, []string{"GET /ipfs/" + cidV0, "GET /ipfs/" + cidV1}},
+	{OpUpdateFolderFileMetadata, func(c *Client, _ string) error {
+		_, err := c.UpdateFolderFileMetadata(context.Background(), &PinResponse{IpfsHash: cidV0}, map[string]map[string]interface{}{"a.txt": {"k": "v"}}, nil)
+		return err
+	}, []string{"GET /ipfs/" + cidV0, "PUT /pinning/hashMetadata"}},
+	{OpCreateGroup, func(c *Client, _ string) error { _, err := c.CreateGroup("docs"); return err }, []string{"POST /groups"}},
+	{OpGetGroup, func(c *Client, _ string) error { _, err := c.GetGroup(fixtures.GroupID); return err }, []string{"GET /groups/" + fixtures.GroupID}},
+	{OpListGroups, func(c *Client, _ string) error { _, err := c.ListGroups(nil); return err }, []string{"GET /groups"}},
+	{OpListGroupsPage, func(c *Client, _ string) error { _, err := c.ListGroupsPage(nil); return err }, []string{"GET /groups"}},
+	{OpUpdateGroup, func(c *Client, _ string) error { _, err := c.UpdateGroup(fixtures.GroupID, "docs"); return err }, []string{"PUT /groups/" + fixtures.GroupID}},
+	{OpAddCidToGroup, func(c *Client, _ string) error { return c.AddCidToGroup(fixtures.GroupID, []string{cidV0}) }, []string{"PUT /groups/" + fixtures.GroupID + "/cids"}},
+	{OpRemoveCidFromGroup, func(c *Client, _ string) error { return c.RemoveCidFromGroup(fixtures.GroupID, []string{cidV0}) }, []string{"DELETE /groups/" + fixtures.GroupID + "/cids"}},
+	{OpRemoveGroup, func(c *Client, _ string) error { return c.RemoveGroup(fixtures.GroupID) }, []string{"DELETE /groups/" + fixtures.GroupID}},
+	{OpReconcileGroups, func(c *Client, _ string) error {
+		_, err := c.ReconcileGroups(context.Background(), map[string][]string{"docs": {cidV0}}, ReconcileOptions{})
+		return err
+	}, []string{"GET /groups", "POST /groups", "PUT /groups/" + fixtures.GroupID + "/cids"}},
+	{OpAddSwap, func(c *Client, _ string) error { _, err := c.AddSwap(cidV1, cidV0, nil); return err }, []string{"GET /data/pinList", "PUT /v3/ipfs/swap/" + cidV1}},
+	{OpGetSwapHistory, func(c *Client, _ string) error {
+		_, err := c.GetSwapHistory(cidV0, "example.mypinata.cloud")
+		return err
+	}, []string{"GET /v3/ipfs/swap/" + cidV0}},
+	{OpRemoveSwap, func(c *Client, _ string) error { _, err := c.RemoveSwap(cidV0); return err }, []string{"DELETE /v3/ipfs/swap/" + cidV0}},
+	{OpAddCidSignature, func(c *Client, _ string) error { _, err := c.AddCidSignature(cidV0, "0xsig"); return err }, []string{"POST /v3/ipfs/signature/" + cidV0}},
+	{OpGetCidSignature, func(c *Client, _ string) error { _, err := c.GetCidSignature(cidV0); return err }, []string{"GET /v3/ipfs/signature/" + cidV0}},
+	{OpRemoveCidSignature, func(c *Client, _ string) error { return c.RemoveCidSignature(cidV0) }, []string{"DELETE /v3/ipfs/signature/" + cidV0}},
+	{OpGenerateApiKey, func(c *Client, _ string) error {
+		_, err := c.GenerateApiKey(&GenerateApiKeyOptions{KeyName: "key", Permissions: PermissionsAdmin()})
+		return err
+	}, []string{"POST /users/generateApiKey"}},
+	{OpGenerateApiKeyV3, func(c *Client, _ string) error {
+		_, err := c.GenerateApiKeyV3(&GenerateApiKeyOptions{KeyName: "key", Permissions: PermissionsAdmin()})
+		return err
+	}, []string{"POST /v3/pinata/keys"}},
+	{OpListApiKeys, func(c *Client, _ string) error { _, err := c.ListApiKeys(); return err }, []string{"GET /users/apiKeys"}},
+	{OpListApiKeyV3, func(c *Client, _ string) error { _, err := c.ListApiKeyV3(nil); return err }, []string{"GET /v3/pinata/keys"}},
+	{OpListApiKeyV3Page, func(c *Client, _ string) error { _, err := c.ListApiKeyV3Page(nil); return err }, []string{"GET /v3/pinata/keys"}},
+	{OpRevokeApiKey, func(c *Client, _ string) error { return c.RevokeApiKey("key") }, []string{"PUT /users/revokeApiKey"}},
+	{OpRevokeApiKeyV3, func(c *Client, _ string) error { return c.RevokeApiKeyV3("key") }, []string{"PUT /v3/pinata/keys/key"}},
+	{OpVectorizeFile, func(c *Client, _ string) error { return c.VectorizeFile(context.Background(), "f1") }, []string{"POST /v3/vectorize/files/f1"}},
+	{OpDeleteFileVectors, func(c *Client, _ string) error { return c.DeleteFileVectors(context.Background(), "f1") }, []string{"DELETE /v3/vectorize/files/f1"}},
+	{OpQueryVectors, func(c *Client, _ string) error {
+		_, err := c.QueryVectors(context.Background(), fixtures.GroupID, "text", 0)
+		return err
+	}, []string{"POST /v3/vectorize/groups/" + fixtures.GroupID + "/query"}},
+}
+
+// endpointTableExempt lists the operations left out of endpointTable, with the reason.
+var endpointTableExempt = map[Operation]string{
+	OpNewPinQueue:  "sends no request; queued jobs are pinned with PinFile",
+	OpWatchPinJobs: "polls ListPinByCidJobs on an interval",
+}
+
+// TestEndpointVerbs runs every operation of endpointTable against a server that records the
+// method and path of each request, so a wrong verb or path fails the test. The server answers
+// with the fixtures of internal/fixtures, and every API request must have one. It runs as its own
+// step in CI.
+func TestEndpointVerbs(t *testing.T) {
+	require.Equal(t, fixtures.Cid, cidV0, "the fixtures must carry the CID of the tests")
+	var mu sync.Mutex
+	served := make(map[string]bool)
+
+	covered := make(map[Operation]bool, len(endpointTable))
+	for _, entry := range endpointTable {
+		require.Falsef(t, covered[entry.op], "%s is listed twice in endpointTable", entry.op)
+		covered[entry.op] = true
+	}
+	for _, op := range Operations() {
+		_, exempt := endpointTableExempt[op]
+		require.Truef(t, covered[op] != exempt, "%s must be listed in exactly one of endpointTable and endpointTableExempt", op)
+	}
+
+	dir := writeTree(t, "a.txt", "b.txt")
+	ran := 0
+	t.Cleanup(func() {
+		if ran < len(endpointTable) {
+			return // some operations were filtered out with -run
+		}
+		for _, endpoint := range fixtures.Endpoints() {
+			require.Truef(t, served[endpoint], "the fixture of %s is not requested by any operation of endpointTable", endpoint)
+		}
+	})
+	for _, entry := range endpointTable {
+		t.Run(string(entry.op), func(t *testing.T) {
+			ran++
+			var got, missing []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				route := r.Method + " " + r.URL.Path
+				mu.Lock()
+				got = append(got, route)
+				mu.Unlock()
+
+				if body, ok := endpointResponses[route]; ok {
+					w.Write([]byte(body))
+					return
+				}
+				_, endpoint := fixtureHandler.Handler(r)
+				mu.Lock()
+				if endpoint == "" {
+					missing = append(missing, route)
+				}
+				served[endpoint] = true
+				mu.Unlock()
+				fixtureHandler.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			client := New(&Auth{jwt: "jwt"}, WithGateways(server.URL))
+			client.baseURL = server.URL
+			client.uploadURL = server.URL
+			client.pinJobPollInterval = time.Millisecond
+
+			err := entry.call(client, dir)
+			mu.Lock()
+			defer mu.Unlock()
+			require.ElementsMatchf(t, entry.want, got, "requests sent by %s (call error: %v)", entry.op, err)
+			require.Emptyf(t, missing, "requests sent by %s without a fixture in internal/fixtures", entry.op)
+			require.NoErrorf(t, err, "%s failed on the fixtures", entry.op)
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrUnexpectedContentType is returned when the content served by an origin does not
+// satisfy the content type allowlist or denylist configured on the pin options.
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// ErrPinNotFound is returned when no pinned content matches the requested identifier.
+var ErrPinNotFound = errors.New("pin not found")
+
+// ErrAmbiguousPinID is returned when a pin ID resolves to more than one CID.
+var ErrAmbiguousPinID = errors.New("ambiguous pin id")
+
+// ErrDuplicatePath is returned by PinFolder and PinNestedFolders when two files map to the same
+// path in the folder, or to paths that differ only by case.
+var ErrDuplicatePath = errors.New("duplicate path in folder")
+
+// ErrRequestAlreadySent is returned by Send and Do when one is called again on a request builder. A
+// builder sends a single request; build a new one with NewRequest to send another.
+var ErrRequestAlreadySent = errors.New("request already sent")
+
+// Errors matched with errors.Is by the APIError of the corresponding statuses, so that common
+// failures can be told apart without inspecting status codes or messages. They match API and
+// gateway errors alike, wrapped or not.
+var (
+	// ErrUnauthorized is matched by 401 and 403 errors: the credentials were rejected, or lack
+	// the scopes of the request.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is matched by 404 errors, such as for an unknown group or an unsigned CID.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is matched by 429 errors. See APIError.RetryAfter for the delay requested.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// ContentTypeError describes a content type rejected during content type validation.
+// ContentType is the media type that was received (or sniffed when the origin omitted it).
+// Sniffed indicates whether the content type was detected from the body rather than a header.
+// Allowed and Denied hold the patterns the content type was checked against.
+type ContentTypeError struct {
+	ContentType string
+	Sniffed     bool
+	Allowed     []string
+	Denied      []string
+}
+
+// Error implements the error interface.
+func (e *ContentTypeError) Error() string {
+	source := "header"
+	if e.Sniffed {
+		source = "sniffed"
+	}
+	msg := fmt.Sprintf("%s: received %q (%s)", ErrUnexpectedContentType, e.ContentType, source)
+	if len(e.Allowed) > 0 {
+		msg += fmt.Sprintf(", allowed: %s", strings.Join(e.Allowed, ", "))
+	}
+	if len(e.Denied) > 0 {
+		msg += fmt.Sprintf(", denied: %s", strings.Join(e.Denied, ", "))
+	}
+	return msg
+}
+
+// Unwrap allows errors.Is(err, ErrUnexpectedContentType) to match a ContentTypeError.
+func (e *ContentTypeError) Unwrap() error {
+	return ErrUnexpectedContentType
+}
+
+// OriginError is returned by PinURL and MirrorURL when the origin serving the content responds
+// with a status other than 200 OK. The failure is the origin's rather than Pinata's, so it matches
+// none of ErrUnauthorized, ErrNotFound and ErrRateLimited, and IsRetryable reports false for it.
+// URL is the URL fetched from the origin.
+// StatusCode is the HTTP status code of the origin response.
+// Status is the status line of the origin response, such as "404 Not Found".
+type OriginError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+// Error implements the error interface.
+func (e *OriginError) Error() string {
+	return fmt.Sprintf("origin %s: HTTP error: %s", e.URL, e.Status)
+}
+
+// Errors caused by talking to Pinata are reported as one of three types, so callers can tell
+// them apart with errors.As:
+//   - TransportError: the request never got a response, e.g. a refused connection, a failed DNS
+//     lookup or a cancelled context.
+//   - TimeoutError: the request did not complete in time, because of the http.Client timeout or
+//     an expired context deadline.
+//   - APIError: Pinata (or a gateway) responded with an error status.
+//
+// IsRetryable reports whether retrying a failed request is likely to succeed.
+
+// TransportError is returned when a request failed before a response was received.
+// Err is the underlying error, usually a *url.Error.
+type TransportError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned when a request did not complete in time.
+// Err is the underlying error, usually a *url.Error or context.DeadlineExceeded.
+type TimeoutError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout always reports true, so a TimeoutError also satisfies net.Error style timeout checks.
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// transportError classifies an error returned while sending a request or reading its response
+// as a TimeoutError or a TransportError. Errors that are already classified are returned as is.
+func transportError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var timeoutErr *TimeoutError
+	var transportErr *TransportError
+	if errors.As(err, &timeoutErr) || errors.As(err, &transportErr) {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &TimeoutError{Err: err}
+	}
+	return &TransportError{Err: err}
+}
+
+// readError classifies an error returned while reading a response body. Network and context
+// failures are classified like transportError; other errors, such as malformed JSON, are
+// returned as is.
+func readError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return transportError(err)
+	}
+	return err
+}
+
+// IsRetryable reports whether the request that failed with err is likely to succeed if retried:
+//   - timeouts are retryable;
+//   - transport errors are retryable, except cancelled contexts and DNS lookups of unknown hosts;
+//   - API errors carrying a reason of DefaultRetryReasons are classified by that reason, such as
+//     "CURRENTLY_PINNING", which is retryable even with a 400 status;
+//   - other API errors are retryable for 408, 425, 429 and 5xx statuses other than 501 and 505;
+//   - any other error, including nil and local validation errors, is not.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if policy, ok := defaultRetryReasons[apiErr.Reason]; ok {
+			return policy.Retryable
+		}
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+			return true
+		case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+			return false
+		}
+		return apiErr.StatusCode >= 500
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return true
+	}
+
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		var dnsErr *net.DNSError
+		return !errors.As(err, &dnsErr) || !dnsErr.IsNotFound
+	}
+	return false
+}
+
+// APIError is returned when the Pinata API or an IPFS gateway responds with an error status code. Every API method returns it, possibly wrapped, for
+// non-2xx responses, so callers can branch on the status with errors.As.
+// StatusCode is the HTTP status code of the response.
+// Message is the error reported in the response body, the body itself when it is not one of the
+// error shapes of Pinata, or the status line when the body is empty.
+// Reason is the machine-readable reason of the error, such as "INVALID_CREDENTIALS", when the
+// body carries one.
+// Details is the explanation accompanying Reason, when the body carries one.
+// RetryAfter is the delay requested by the Retry-After header, or zero when absent.
+// RateLimit is the rate limit state reported by the headers of the response, or nil when it
+// carries none.
+// Raw is the response body as received, JSON or not, for API responses; it is nil for gateway
+// responses, whose bodies are not read.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Reason     string
+	Details    string
+	RetryAfter time.Duration
+	RateLimit  *RateLimitInfo
+	Raw        []byte
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Is reports whether e matches target, one of ErrUnauthorized, ErrNotFound and ErrRateLimited,
+// by its status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// parseErrorBody fills apiErr from a decoded error body of one of the shapes returned by Pinata:
+//
+//	{"error": "message"}
+//	{"error": {"reason": "INVALID_CREDENTIALS", "details": "..."}}
+//	{"error": {"message": "..."}}
+//
+// It reports false for other bodies.
+func parseErrorBody(body interface{}, apiErr *APIError) bool {
+	object, ok := body.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	switch e := object["error"].(type) {
+	case string:
+		apiErr.Message = e
+		return e != ""
+	case map[string]interface{}:
+		reason, _ := e["reason"].(string)
+		details := errorText(e["details"])
+		message := errorText(e["message"])
+		if message == "" {
+			message = reason
+			if details != "" {
+				message = strings.TrimPrefix(message+": "+details, ": ")
+			}
+		}
+		if message == "" {
+			return false
+		}
+		apiErr.Message, apiErr.Reason, apiErr.Details = message, reason, details
+		return true
+	}
+	return false
+}
+
+// errorText returns a field of an error body as text: strings as they are, other values as JSON.
+func errorText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	text, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(text)
+}
@@ -0,0 +1,214 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorClassification(t *testing.T) {
+	t.Run("refused connection", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = "http://" + addr
+
+		err = client.NewRequest(http.MethodGet, "/test").Send(nil)
+
+		var transportErr *TransportError
+		require.ErrorAs(t, err, &transportErr)
+		require.NotErrorIs(t, err, context.DeadlineExceeded)
+		require.True(t, IsRetryable(err))
+	})
+
+	t.Run("timing out server", func(t *testing.T) {
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer mockServer.Close()
+		defer close(release)
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = mockServer.URL
+		client.httpClient.Timeout = 20 * time.Millisecond
+
+		err := client.NewRequest(http.MethodGet, "/test").Send(nil)
+
+		var timeoutErr *TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.True(t, timeoutErr.Timeout())
+		require.True(t, IsRetryable(err))
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer mockServer.Close()
+		defer close(release)
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = mockServer.URL
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := client.NewRequest(http.MethodGet, "/test").WithContext(ctx).Send(nil)
+
+		var timeoutErr *TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.True(t, IsRetryable(err))
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		client := New(NewAuthWithJWT("test_token"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := client.NewRequest(http.MethodGet, "/test").WithContext(ctx).Send(nil)
+
+		var transportErr *TransportError
+		require.ErrorAs(t, err, &transportErr)
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, IsRetryable(err))
+	})
+
+	statuses := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusRequestTimeout, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusNotImplemented, false},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+	}
+	for _, tc := range statuses {
+		t.Run(fmt.Sprintf("status %d", tc.status), func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"error":"failed"}`))
+			}))
+			defer mockServer.Close()
+
+			client := New(NewAuthWithJWT("test_token"))
+			client.baseURL = mockServer.URL
+
+			err := client.NewRequest(http.MethodGet, "/test").Send(nil)
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tc.status, apiErr.StatusCode)
+			require.Equal(t, tc.retryable, IsRetryable(err))
+		})
+	}
+
+	t.Run("gateway errors", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("test_token"), WithGateways(mockServer.URL))
+
+		_, err := client.GetContent(context.Background(), "QmTest")
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		require.False(t, IsRetryable(err))
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	require.False(t, IsRetryable(nil))
+	require.False(t, IsRetryable(errors.New("validation failed")))
+	require.False(t, IsRetryable(&TransportError{Err: &net.DNSError{Err: "no such host", Name: "invalid.test", IsNotFound: true}}))
+	require.True(t, IsRetryable(&TransportError{Err: &net.DNSError{Err: "server misbehaving", Name: "pinata.cloud", IsTemporary: true}}))
+	require.True(t, IsRetryable(fmt.Errorf("pin failed: %w", &APIError{StatusCode: http.StatusServiceUnavailable})))
+	require.False(t, IsRetryable(fmt.Errorf("pin failed: %w", &APIError{StatusCode: http.StatusBadRequest})))
+	require.False(t, IsRetryable(&OriginError{URL: "https://example.com/a.txt", StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}))
+}
+
+func TestSentinelErrors(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrRateLimited}
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadRequest, nil},
+		{http.StatusInternalServerError, nil},
+	}
+	calls := map[string]func(c *Client) error{
+		"DeleteFile": func(c *Client) error { return c.DeleteFile(cidV0) },
+		"GetGroup": func(c *Client) error {
+			_, err := c.Groups().Get("5b9a1c2e-0d57-4a8b-9a8e-7d1f0a1b2c3d")
+			return err
+		},
+		"GetCidSignature": func(c *Client) error {
+			_, err := c.GetCidSignature(cidV0)
+			return err
+		},
+	}
+	for _, tc := range tests {
+		for name, call := range calls {
+			t.Run(fmt.Sprintf("%s %d", name, tc.status), func(t *testing.T) {
+				mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tc.status)
+					w.Write([]byte(`{"error":"failed"}`))
+				}))
+				defer mockServer.Close()
+
+				client := New(NewAuthWithJWT("test_token"))
+				client.baseURL = mockServer.URL
+
+				err := call(client)
+				require.Error(t, err)
+				for _, sentinel := range sentinels {
+					require.Equal(t, sentinel == tc.want, errors.Is(err, sentinel), "errors.Is(%v, %v)", err, sentinel)
+				}
+				if tc.want != nil {
+					require.ErrorIs(t, fmt.Errorf("wrapped: %w", err), tc.want)
+				}
+			})
+		}
+	}
+
+	t.Run("gateway errors", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("test_token"), WithGateways(mockServer.URL))
+
+		_, err := client.Gateway().Get(context.Background(), cidV0)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		require.NotErrorIs(t, &APIError{StatusCode: http.StatusNotFound}, ErrPinNotFound)
+		require.NotErrorIs(t, errors.New("not found"), ErrNotFound)
+		require.NotErrorIs(t, &OriginError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}, ErrNotFound)
+	})
+}
@@ -0,0 +1,236 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+)
+
+func init() {
+	registerCapability(CapabilityV3Files)
+}
+
+// Network is the IPFS network a v3 file is stored on.
+type Network string
+
+const (
+	// NetworkPublic files are announced on the public IPFS network and served by any gateway.
+	NetworkPublic Network = "public"
+	// NetworkPrivate files are not announced and are only served through temporary access links.
+	NetworkPrivate Network = "private"
+)
+
+// FilesClient groups the v3 Files API endpoints, which store files on the public or the private
+// network. It is obtained with Client.Files and shares the transport, credentials, options and
+// hooks of its Client.
+type FilesClient struct {
+	c *Client
+}
+
+// Files returns the sub-client for the v3 Files API. It is created on first use, and every call
+// returns the same value; it is safe for concurrent use.
+func (c *Client) Files() *FilesClient {
+	c.filesOnce.Do(func() {
+		c.filesClient = &FilesClient{c: c}
+	})
+	return c.filesClient
+}
+
+// File is a file of the v3 Files API.
+// ID is the v3 ID of the file.
+// Name is the name of the file.
+// Cid is the CID of the file content.
+// Size is the size of the file in bytes.
+// NumberOfFiles is the number of files of a folder upload.
+// MimeType is the detected media type of the file.
+// GroupID is the ID of the group the file belongs to, if any.
+// KeyValues are the keyvalues of the file.
+// CreatedAt is the time the file was uploaded.
+// Network is the network the file is stored on. It is filled in from the request when the API
+// response omits it.
+type File struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Cid           string            `json:"cid"`
+	Size          int64             `json:"size"`
+	NumberOfFiles int               `json:"number_of_files"`
+	MimeType      string            `json:"mime_type"`
+	GroupID       string            `json:"group_id,omitempty"`
+	KeyValues     map[string]string `json:"keyvalues,omitempty"`
+	CreatedAt     string            `json:"created_at"`
+	Network       Network           `json:"network,omitempty"`
+}
+
+// FileUploadOptions represents the options for uploading a file with the v3 Files API.
+// Name is the name of the file; it defaults to the base name of the path.
+// Network is the network the file is stored on; it defaults to NetworkPublic.
+// GroupID is the ID of the group to add the file to.
+// KeyValues are the keyvalues of the file.
+type FileUploadOptions struct {
+	Name      string
+	Network   Network
+	GroupID   string
+	KeyValues map[string]string
+}
+
+// FileListOptions represents the options for listing files with the v3 Files API.
+// Network is the network whose files are listed; it defaults to NetworkPublic.
+// Name filters the files by name.
+// Cid filters the files by CID.
+// GroupID filters the files by group.
+// Limit is the maximum number of files returned.
+// PageToken is the NextPageToken of the previous page.
+type FileListOptions struct {
+	Network   Network
+	Name      string
+	Cid       string
+	GroupID   string
+	Limit     int
+	PageToken string
+}
+
+// FileList is a page of files of the v3 Files API.
+// Files are the files of the page.
+// NextPageToken is the token of the next page, or empty on the last page.
+type FileList struct {
+	Files         []File `json:"files"`
+	NextPageToken string `json:"next_page_token"`
+}
+
+// networkOrDefault returns network, or NetworkPublic when it is empty, and rejects unknown networks.
+func networkOrDefault(network Network) (Network, error) {
+	switch network {
+	case "":
+		return NetworkPublic, nil
+	case NetworkPublic, NetworkPrivate:
+		return network, nil
+	}
+	return "", fmt.Errorf("unknown network %q", network)
+}
+
+// Upload uploads the file at path with the v3 Files API, on the network selected by
+// options.Network.
+func (f *FilesClient) Upload(ctx context.Context, path string, options *FileUploadOptions) (*File, error) {
+	if path == "" {
+		return nil, fmt.Errorf("filepath is required")
+	}
+	if options == nil {
+		options = &FileUploadOptions{}
+	}
+	network, err := networkOrDefault(options.Network)
+	if err != nil {
+		return nil, err
+	}
+	name := options.Name
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	body, contentType, length, err := f.c.multipartBody(func(form *multipartForm) error {
+		fields := [][2]string{{"network", string(network)}, {"name", name}}
+		if options.GroupID != "" {
+			fields = append(fields, [2]string{"group_id", options.GroupID})
+		}
+		if len(options.KeyValues) > 0 {
+			keyValues, err := json.Marshal(options.KeyValues)
+			if err != nil {
+				return fmt.Errorf("failed to marshal keyvalues: %w", err)
+			}
+			fields = append(fields, [2]string{"keyvalues", string(keyValues)})
+		}
+		for _, field := range fields {
+			if err := form.WriteField(field[0], field[1]); err != nil {
+				return fmt.Errorf("failed to write %s field: %w", field[0], err)
+			}
+		}
+		return writeFormFile(form, path, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data File `json:"data"`
+	}
+	err = f.c.NewRequest(http.MethodPost, "/v3/files").
+		WithContext(ctx).
+		SetBodyFactory(body, contentType).
+		SetContentLength(length).
+		withPayloadSize(fileSizes(path)).
+		Send(&response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Data.Network == "" {
+		response.Data.Network = network
+	}
+	return &response.Data, nil
+}
+
+// List returns a page of the files stored on the network selected by options.Network.
+func (f *FilesClient) List(ctx context.Context, options *FileListOptions) (*FileList, error) {
+	if options == nil {
+		options = &FileListOptions{}
+	}
+	network, err := networkOrDefault(options.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	req := f.c.NewRequest(http.MethodGet, "/v3/files/{network}").
+		WithContext(ctx).
+		AddPathParam("network", string(network))
+	params := map[string]string{"name": options.Name, "cid": options.Cid, "group": options.GroupID, "pageToken": options.PageToken}
+	for key, value := range params {
+		if value != "" {
+			req.AddQueryParam(key, value)
+		}
+	}
+	if options.Limit > 0 {
+		req.AddQueryParam("limit", options.Limit)
+	}
+
+	var response struct {
+		Data FileList `json:"data"`
+	}
+	if err := req.Send(&response); err != nil {
+		return nil, err
+	}
+
+	for i := range response.Data.Files {
+		if response.Data.Files[i].Network == "" {
+			response.Data.Files[i].Network = network
+		}
+	}
+	return &response.Data, nil
+}
+
+// DownloadFile streams the content of file. Public files are retrieved from the configured
+// gateways, as GatewayClient.Get does. Private files are retrieved through an access link created
+// with CreateAccessLink, since gateways only serve them to signed requests. Statuses other than
+// 200 OK are returned as errors. The download is reported by ActiveOperations until the body of
+// the content is closed.
+func (f *FilesClient) DownloadFile(ctx context.Context, file *File) (*GatewayContent, error) {
+	if file == nil || file.Cid == "" {
+		return nil, fmt.Errorf("file with a cid is required")
+	}
+	network, err := networkOrDefault(file.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	op := f.c.startOp("Files.DownloadFile", file.Cid)
+	if network == NetworkPublic {
+		return f.c.gatewayGet(ctx, op, file.Cid)
+	}
+
+	link, err := f.CreateAccessLink(ctx, AccessLinkOptions{Cid: file.Cid})
+	if err != nil {
+		f.c.endOp(op)
+		return nil, fmt.Errorf("failed to create access link: %w", err)
+	}
+	return f.getAccessLink(ctx, op, link)
+}
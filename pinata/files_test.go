@@ -0,0 +1,151 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFiles is an in-memory v3 Files API and gateway. Private content is only served through the
+// signed links it hands out.
+type fakeFiles struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []string
+	link     map[string]interface{}
+}
+
+func newFakeFiles(t *testing.T) *fakeFiles {
+	f := &fakeFiles{}
+	f.server = httptest.NewServer(f)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeFiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v3/files":
+		keyValues := map[string]string{}
+		json.Unmarshal([]byte(r.FormValue("keyvalues")), &keyValues)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"id": "file-id", "name": r.FormValue("name"), "cid": cidV1, "size": 12,
+			"network": r.FormValue("network"), "group_id": r.FormValue("group_id"), "keyvalues": keyValues,
+		}})
+	case r.Method == http.MethodGet && r.URL.Path == "/v3/files/private":
+		w.Write([]byte(`{"data": {"files": [{"id": "file-id", "name": "report.pdf", "cid": "` + cidV1 + `", "size": 12}], "next_page_token": "next"}}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/v3/files/private/download_link":
+		json.NewDecoder(r.Body).Decode(&f.link)
+		w.Write([]byte(`{"data": "` + f.server.URL + `/signed/` + cidV1 + `?X-Algorithm=PINATA1&X-Signature=abc"}`))
+	case r.URL.Path == "/signed/"+cidV1 && r.URL.Query().Get("X-Signature") == "abc":
+		w.Write([]byte("private content"))
+	case r.URL.Path == "/ipfs/"+cidV1:
+		w.Write([]byte("public content"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	newClient := func(f *fakeFiles) *Client {
+		client := New(NewAuthWithJWT("test_token"), WithProfile(Profile{APIURL: f.server.URL, UploadURL: f.server.URL}), WithGateways(f.server.URL))
+		client.clock = &fakeClock{now: time.Unix(1700000000, 0)}
+		return client
+	}
+
+	t.Run("upload to the private network", func(t *testing.T) {
+		f := newFakeFiles(t)
+		client := newClient(f)
+		path := filepath.Join(writeTree(t, "report.pdf"), "report.pdf")
+
+		file, err := client.Files().Upload(context.Background(), path, &FileUploadOptions{
+			Network:   NetworkPrivate,
+			GroupID:   "group-id",
+			KeyValues: map[string]string{"team": "compliance"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, &File{
+			ID: "file-id", Name: "report.pdf", Cid: cidV1, Size: 12, GroupID: "group-id",
+			KeyValues: map[string]string{"team": "compliance"}, Network: NetworkPrivate,
+		}, file)
+		require.Equal(t, []string{"POST /v3/files"}, f.requests)
+	})
+
+	t.Run("uploads default to the public network", func(t *testing.T) {
+		f := newFakeFiles(t)
+		client := newClient(f)
+		path := filepath.Join(writeTree(t, "logo.png"), "logo.png")
+
+		file, err := client.Files().Upload(context.Background(), path, nil)
+
+		require.NoError(t, err)
+		require.Equal(t, NetworkPublic, file.Network)
+		require.Equal(t, "logo.png", file.Name)
+	})
+
+	t.Run("list fills in the network", func(t *testing.T) {
+		f := newFakeFiles(t)
+		client := newClient(f)
+
+		list, err := client.Files().List(context.Background(), &FileListOptions{Network: NetworkPrivate})
+
+		require.NoError(t, err)
+		require.Equal(t, "next", list.NextPageToken)
+		require.Len(t, list.Files, 1)
+		require.Equal(t, NetworkPrivate, list.Files[0].Network)
+	})
+
+	t.Run("private download goes through an access link", func(t *testing.T) {
+		f := newFakeFiles(t)
+		client := newClient(f)
+
+		content, err := client.Files().DownloadFile(context.Background(), &File{Cid: cidV1, Network: NetworkPrivate})
+		require.NoError(t, err)
+		defer content.Body.Close()
+		body, err := io.ReadAll(content.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, "private content", string(body))
+		require.Equal(t, []string{"POST /v3/files/private/download_link", "GET /signed/" + cidV1}, f.requests)
+		require.Equal(t, map[string]interface{}{
+			"url":     f.server.URL + "/files/" + cidV1,
+			"expires": float64(60),
+			"date":    float64(1700000000),
+			"method":  "GET",
+		}, f.link)
+	})
+
+	t.Run("public download goes to the gateway", func(t *testing.T) {
+		f := newFakeFiles(t)
+		client := newClient(f)
+
+		content, err := client.Files().DownloadFile(context.Background(), &File{Cid: cidV1, Network: NetworkPublic})
+		require.NoError(t, err)
+		defer content.Body.Close()
+		body, err := io.ReadAll(content.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, "public content", string(body))
+		require.Equal(t, []string{"GET /ipfs/" + cidV1}, f.requests)
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		client := New(NewAuthWithJWT("test_token"))
+
+		_, err := client.Files().List(context.Background(), &FileListOptions{Network: "intranet"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `unknown network "intranet"`)
+	})
+}
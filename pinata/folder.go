@@ -0,0 +1,359 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrFolderPathNotFound is returned when a path cannot be resolved within a pinned folder.
+var ErrFolderPathNotFound = errors.New("path not found in folder")
+
+// ErrShardedDirectory is returned when a folder operation reaches a HAMT-sharded directory,
+// whose entries are spread over shards and are not listed or resolved by the SDK.
+var ErrShardedDirectory = errors.New("sharded directory")
+
+// FolderMetadataOptions represents the options for annotating the files of a pinned folder.
+// Gateway is the gateway base URL used to list the folder (the client's gateways when empty).
+type FolderMetadataOptions struct {
+	Gateway string
+}
+
+// FolderFileResult represents the outcome of annotating a single file of a folder.
+// Path is the slash-separated path of the file relative to the folder root.
+// Cid is the CID the path resolved to, if it was resolved.
+// Err is the error that prevented the file from being resolved or updated, if any.
+type FolderFileResult struct {
+	Path string
+	Cid  string
+	Err  error
+}
+
+// FolderMetadataReport represents the outcome of annotating the files of a pinned folder.
+// Updated lists the files whose metadata was updated.
+// Unresolved lists the paths that could not be resolved to a CID within the folder.
+// Failed lists the files that were resolved but whose metadata update failed.
+// All lists are sorted by path.
+type FolderMetadataReport struct {
+	Updated    []FolderFileResult
+	Unresolved []FolderFileResult
+	Failed     []FolderFileResult
+}
+
+// UpdateFolderFileMetadata attaches per-file keyvalues to the files of a pinned folder.
+//
+// Folder uploads carry a single pinataMetadata for the whole folder, so each path of files (relative
+// to the folder root, e.g. "docs/readme.md") is resolved to its own CID by walking the folder's
+// dag-json listing on the gateway, and its metadata is then updated through hashMetadata with the
+// file name as metadata name. Paths that cannot be resolved are reported as Unresolved; an error is
+// only returned when the folder itself cannot be listed.
+func (c *Client) UpdateFolderFileMetadata(ctx context.Context, folder *PinResponse, files map[string]map[string]interface{}, options *FolderMetadataOptions) (*FolderMetadataReport, error) {
+	if folder == nil || folder.IpfsHash == "" {
+		return nil, fmt.Errorf("folder pin response is required")
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("at least one file is required")
+	}
+	if options == nil {
+		options = &FolderMetadataOptions{}
+	}
+
+	resolver := &folderResolver{client: c, gateway: options.Gateway, nodes: make(map[string]*dagNode)}
+	if _, err := resolver.node(ctx, folder.IpfsHash); err != nil {
+		return nil, fmt.Errorf("failed to list folder %s: %w", folder.IpfsHash, err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	report := &FolderMetadataReport{}
+	for _, p := range paths {
+		result := FolderFileResult{Path: p}
+
+		result.Cid, result.Err = resolver.resolve(ctx, folder.IpfsHash, p)
+		if result.Err != nil {
+			report.Unresolved = append(report.Unresolved, result)
+			continue
+		}
+
+		result.Err = c.updateFileMetadata(ctx, result.Cid, &PinMetadataUpdateOptions{
+			Name:      path.Base(p),
+			KeyValues: files[p],
+		})
+		if result.Err != nil {
+			report.Failed = append(report.Failed, result)
+			continue
+		}
+		report.Updated = append(report.Updated, result)
+	}
+
+	return report, nil
+}
+
+// folderResolver resolves paths within a folder, caching the dag-json nodes it fetched.
+type folderResolver struct {
+	client  *Client
+	gateway string
+	nodes   map[string]*dagNode
+}
+
+// node returns the dag-json node of cid, fetching it on first use.
+func (r *folderResolver) node(ctx context.Context, cid string) (*dagNode, error) {
+	if node, ok := r.nodes[cid]; ok {
+		return node, nil
+	}
+	node, err := r.client.fetchDAGNode(ctx, r.gateway, cid)
+	if err != nil {
+		return nil, err
+	}
+	r.nodes[cid] = node
+	return node, nil
+}
+
+// resolve walks the slash-separated path p from the folder root and returns the CID it names.
+func (r *folderResolver) resolve(ctx context.Context, root, p string) (string, error) {
+	segments := strings.Split(strings.Trim(path.Clean("/"+p), "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return "", fmt.Errorf("%w: empty path", ErrFolderPathNotFound)
+	}
+
+	cid := root
+	for i, segment := range segments {
+		node, err := r.node(ctx, cid)
+		if err != nil {
+			return "", err
+		}
+		kind, _, err := node.unixfs()
+		if err != nil {
+			return "", err
+		}
+		dir := strings.Join(segments[:i], "/")
+		if dir == "" {
+			dir = "folder root"
+		}
+		switch kind {
+		case unixfsDirectory:
+		case unixfsHAMTShard:
+			return "", fmt.Errorf("%w: %s cannot be resolved", ErrShardedDirectory, dir)
+		default:
+			return "", fmt.Errorf("%w: %s is not a directory", ErrFolderPathNotFound, dir)
+		}
+
+		next := ""
+		for _, link := range node.Links {
+			if link.Name == segment {
+				next = link.Hash.Slash
+				break
+			}
+		}
+		if next == "" {
+			return "", fmt.Errorf("%w: %s", ErrFolderPathNotFound, strings.Join(segments[:i+1], "/"))
+		}
+		cid = next
+	}
+	return cid, nil
+}
+
+// DirEntryType is the type of an entry of a pinned folder.
+type DirEntryType string
+
+const (
+	DirEntryFile      DirEntryType = "file"
+	DirEntryDirectory DirEntryType = "directory"
+	// DirEntrySharded is a HAMT-sharded directory; its entries are not listed.
+	DirEntrySharded DirEntryType = "sharded_directory"
+)
+
+// DirEntry describes an entry of a pinned folder.
+// Name is the name of the entry within its directory.
+// Path is the slash-separated path of the entry relative to the listed folder.
+// Cid is the CID of the entry.
+// Type is the type of the entry.
+// Size is the file size for files, or the cumulative DAG size for directories.
+// Depth is the nesting level of the entry, 0 for direct entries of the listed folder.
+type DirEntry struct {
+	Name  string       `json:"name"`
+	Path  string       `json:"path"`
+	Cid   string       `json:"cid"`
+	Type  DirEntryType `json:"type"`
+	Size  int64        `json:"size"`
+	Depth int          `json:"depth"`
+}
+
+// ListFolderOptions represents the options for listing the contents of a pinned folder.
+// Gateway is the gateway base URL the folder is listed from (the client's gateways when empty).
+// MaxDepth is the number of nested levels listed below the direct entries of the folder; 0 lists
+// only the direct entries and a negative value lists the whole tree.
+type ListFolderOptions struct {
+	Gateway  string
+	MaxDepth int
+}
+
+// ListFolderContents returns the direct entries of the pinned folder cid, using the client's gateways.
+func (c *Client) ListFolderContents(ctx context.Context, cid string) ([]DirEntry, error) {
+	return c.ListFolderContentsWithOptions(ctx, cid, nil)
+}
+
+// ListFolderContentsWithOptions returns the entries of the pinned folder cid, read from the
+// gateway's dag-json representation of the UnixFS directory, recursing up to options.MaxDepth.
+//
+// Every entry is fetched once to determine its type and size; raw leaves (CIDv1 "bafk..." CIDs)
+// are reported as files without being fetched. HAMT-sharded subdirectories are reported with
+// type DirEntrySharded and not descended into; a sharded folder cid fails with an error wrapping
+// ErrShardedDirectory. Entries are returned in depth-first order.
+func (c *Client) ListFolderContentsWithOptions(ctx context.Context, cid string, options *ListFolderOptions) ([]DirEntry, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("cid is required")
+	}
+	if options == nil {
+		options = &ListFolderOptions{}
+	}
+
+	node, err := c.fetchDAGNode(ctx, options.Gateway, cid)
+	if err != nil {
+		return nil, err
+	}
+	kind, _, err := node.unixfs()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case unixfsDirectory:
+	case unixfsHAMTShard:
+		return nil, fmt.Errorf("%w: %s", ErrShardedDirectory, cid)
+	default:
+		return nil, fmt.Errorf("%s is not a directory", cid)
+	}
+
+	var entries []DirEntry
+	err = c.listFolderNode(ctx, options, node, "", 0, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// listFolderNode appends the entries of the directory node at dir to entries, recursing into
+// subdirectories while depth is within options.MaxDepth.
+func (c *Client) listFolderNode(ctx context.Context, options *ListFolderOptions, node *dagNode, dir string, depth int, entries *[]DirEntry) error {
+	for _, link := range node.Links {
+		entry := DirEntry{
+			Name:  link.Name,
+			Path:  path.Join(dir, link.Name),
+			Cid:   link.Hash.Slash,
+			Type:  DirEntryFile,
+			Size:  link.Tsize,
+			Depth: depth,
+		}
+
+		if strings.HasPrefix(entry.Cid, "bafk") {
+			*entries = append(*entries, entry)
+			continue
+		}
+
+		child, err := c.fetchDAGNode(ctx, options.Gateway, entry.Cid)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", entry.Path, err)
+		}
+		kind, size, err := child.unixfs()
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", entry.Path, err)
+		}
+
+		switch kind {
+		case unixfsDirectory:
+			entry.Type = DirEntryDirectory
+		case unixfsHAMTShard:
+			entry.Type = DirEntrySharded
+		default:
+			if size >= 0 {
+				entry.Size = size
+			}
+		}
+		*entries = append(*entries, entry)
+
+		if entry.Type == DirEntryDirectory && (options.MaxDepth < 0 || depth < options.MaxDepth) {
+			if err := c.listFolderNode(ctx, options, child, entry.Path, depth+1, entries); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteFolderOptions represents the options for unpinning a folder.
+// CheckChildren makes DeleteFolder report the entries of the folder that remain pinned on their
+// own once the folder is unpinned.
+// Gateway is the gateway base URL the folder is listed from (the client's gateways when empty).
+// MaxDepth is the number of nested levels checked below the direct entries of the folder, as in
+// ListFolderOptions; a negative value checks the whole tree.
+type DeleteFolderOptions struct {
+	CheckChildren bool
+	Gateway       string
+	MaxDepth      int
+}
+
+// DeleteFolderReport represents the outcome of unpinning a folder.
+// Cid is the CID of the unpinned folder.
+// Checked is the number of folder entries checked for pins of their own.
+// PinnedChildren lists the entries that are still pinned, because they were also pinned directly.
+// Unchecked lists the entries whose pin status could not be read, with the error.
+type DeleteFolderReport struct {
+	Cid            string
+	Checked        int
+	PinnedChildren []DirEntry
+	Unchecked      []FolderFileResult
+}
+
+// DeleteFolder unpins the folder cid. Unpinning a folder only removes the pin of its root: files and
+// subdirectories of the folder that were also pinned directly stay pinned, and remain retrievable.
+// With options.CheckChildren, the entries of the folder are listed and each is looked up in the
+// pin list once the root is unpinned; the entries still pinned are reported, not unpinned, and can
+// be unpinned with DeleteFile if they are no longer needed.
+//
+// The folder is listed before its root is unpinned, since gateways may stop serving it afterwards,
+// and a folder that cannot be listed is left pinned and fails with the listing error.
+func (c *Client) DeleteFolder(ctx context.Context, cid string, options *DeleteFolderOptions) (*DeleteFolderReport, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("cid is required")
+	}
+	if options == nil {
+		options = &DeleteFolderOptions{}
+	}
+
+	var entries []DirEntry
+	if options.CheckChildren {
+		var err error
+		entries, err = c.ListFolderContentsWithOptions(ctx, cid, &ListFolderOptions{Gateway: options.Gateway, MaxDepth: options.MaxDepth})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list folder %s: %w", cid, err)
+		}
+	}
+
+	if err := c.DeleteFileWithContext(ctx, cid); err != nil {
+		return nil, err
+	}
+
+	report := &DeleteFolderReport{Cid: cid}
+	for _, entry := range entries {
+		pinned, err := c.IsPinned(ctx, entry.Cid, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			report.Unchecked = append(report.Unchecked, FolderFileResult{Path: entry.Path, Cid: entry.Cid, Err: err})
+			continue
+		}
+		report.Checked++
+		if pinned {
+			report.PinnedChildren = append(report.PinnedChildren, entry)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,271 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+// folderFixture holds dag-json nodes of a small folder:
+//
+//	QmRoot/
+//	  a.txt       (QmA)
+//	  docs/       (QmDocs)
+//	    b.md      (QmB)
+//	    raw.bin   (bafkraw, raw leaf)
+//	  shard/      (QmShard, HAMT sharded)
+var folderFixture = map[string]string{
+	"QmRoot": `{"Data":{"/":{"bytes":"CAE"}},"Links":[
+		{"Hash":{"/":"QmA"},"Name":"a.txt","Tsize":12},
+		{"Hash":{"/":"QmDocs"},"Name":"docs","Tsize":70},
+		{"Hash":{"/":"QmShard"},"Name":"shard","Tsize":300}]}`,
+	"QmDocs": `{"Data":{"/":{"bytes":"CAE"}},"Links":[
+		{"Hash":{"/":"QmB"},"Name":"b.md","Tsize":20},
+		{"Hash":{"/":"bafkraw"},"Name":"raw.bin","Tsize":9}]}`,
+	"QmA":     `{"Data":{"/":{"bytes":"CAIYBQ"}},"Links":[]}`,
+	"QmB":     `{"Data":{"/":{"bytes":"CAIYBQ"}},"Links":[]}`,
+	"QmShard": `{"Data":{"/":{"bytes":"CAUoIDCAAg"}},"Links":[{"Hash":{"/":"QmC"},"Name":"0Ac.txt","Tsize":12}]}`,
+}
+
+// newFolderServer serves folderFixture as a gateway and records the hashMetadata updates it receives.
+func newFolderServer(t *testing.T, updates map[string]map[string]interface{}, mu *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/ipfs/"):
+			require.Equal(t, "dag-json", r.URL.Query().Get("format"))
+			node, ok := folderFixture[strings.TrimPrefix(r.URL.Path, "/ipfs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(node))
+		case r.URL.Path == "/pinning/hashMetadata":
+			var payload struct {
+				IpfsPinHash string                 `json:"ipfsPinHash"`
+				Name        string                 `json:"name"`
+				KeyValues   map[string]interface{} `json:"keyvalues"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			if payload.IpfsPinHash == "QmB" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"Invalid keyvalues"}`))
+				return
+			}
+			mu.Lock()
+			updates[payload.IpfsPinHash] = payload.KeyValues
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestUpdateFolderFileMetadata(t *testing.T) {
+	t.Run("resolves and updates files", func(t *testing.T) {
+		var mu sync.Mutex
+		updates := make(map[string]map[string]interface{})
+		mockServer := newFolderServer(t, updates, &mu)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		report, err := client.UpdateFolderFileMetadata(context.Background(), &PinResponse{IpfsHash: "QmRoot"},
+			map[string]map[string]interface{}{
+				"a.txt":         {"lang": "en"},
+				"docs/b.md":     {"lang": "fr"},
+				"missing.txt":   {"lang": "de"},
+				"a.txt/nested":  {"lang": "es"},
+				"shard/0Ac.txt": {"lang": "it"},
+			},
+			&FolderMetadataOptions{Gateway: mockServer.URL})
+		require.NoError(t, err)
+
+		require.Equal(t, []FolderFileResult{{Path: "a.txt", Cid: "QmA"}}, report.Updated)
+		require.Equal(t, map[string]map[string]interface{}{"QmA": {"lang": "en"}}, updates)
+
+		require.Len(t, report.Failed, 1)
+		require.Equal(t, "docs/b.md", report.Failed[0].Path)
+		require.Equal(t, "QmB", report.Failed[0].Cid)
+		require.Contains(t, report.Failed[0].Err.Error(), "Invalid keyvalues")
+
+		require.Len(t, report.Unresolved, 3)
+		require.Equal(t, "a.txt/nested", report.Unresolved[0].Path)
+		require.ErrorIs(t, report.Unresolved[0].Err, ErrFolderPathNotFound)
+		require.Equal(t, "missing.txt", report.Unresolved[1].Path)
+		require.ErrorIs(t, report.Unresolved[1].Err, ErrFolderPathNotFound)
+		require.Equal(t, "shard/0Ac.txt", report.Unresolved[2].Path)
+		require.ErrorIs(t, report.Unresolved[2].Err, ErrShardedDirectory)
+	})
+
+	t.Run("unknown folder", func(t *testing.T) {
+		var mu sync.Mutex
+		mockServer := newFolderServer(t, map[string]map[string]interface{}{}, &mu)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		_, err := client.UpdateFolderFileMetadata(context.Background(), &PinResponse{IpfsHash: "QmUnknown"},
+			map[string]map[string]interface{}{"a.txt": {"lang": "en"}},
+			&FolderMetadataOptions{Gateway: mockServer.URL})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("missing arguments", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		_, err := client.UpdateFolderFileMetadata(context.Background(), nil, map[string]map[string]interface{}{"a": nil}, nil)
+		require.Error(t, err)
+
+		_, err = client.UpdateFolderFileMetadata(context.Background(), &PinResponse{IpfsHash: "QmRoot"}, nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestDAGNodeUnixFSType(t *testing.T) {
+	for cid, want := range map[string]unixfsType{"QmRoot": unixfsDirectory, "QmA": unixfsFile, "QmShard": unixfsHAMTShard} {
+		var node dagNode
+		require.NoError(t, json.Unmarshal([]byte(folderFixture[cid]), &node))
+		kind, _, err := node.unixfs()
+		require.NoError(t, err)
+		require.Equal(t, want, kind, cid)
+	}
+}
+
+func TestListFolderContents(t *testing.T) {
+	var mu sync.Mutex
+	mockServer := newFolderServer(t, map[string]map[string]interface{}{}, &mu)
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+
+	t.Run("direct entries", func(t *testing.T) {
+		entries, err := client.ListFolderContentsWithOptions(context.Background(), "QmRoot", &ListFolderOptions{Gateway: mockServer.URL})
+		require.NoError(t, err)
+		require.Equal(t, []DirEntry{
+			{Name: "a.txt", Path: "a.txt", Cid: "QmA", Type: DirEntryFile, Size: 5},
+			{Name: "docs", Path: "docs", Cid: "QmDocs", Type: DirEntryDirectory, Size: 70},
+			{Name: "shard", Path: "shard", Cid: "QmShard", Type: DirEntrySharded, Size: 300},
+		}, entries)
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		entries, err := client.ListFolderContentsWithOptions(context.Background(), "QmRoot", &ListFolderOptions{Gateway: mockServer.URL, MaxDepth: -1})
+		require.NoError(t, err)
+		require.Equal(t, []DirEntry{
+			{Name: "a.txt", Path: "a.txt", Cid: "QmA", Type: DirEntryFile, Size: 5},
+			{Name: "docs", Path: "docs", Cid: "QmDocs", Type: DirEntryDirectory, Size: 70},
+			{Name: "b.md", Path: "docs/b.md", Cid: "QmB", Type: DirEntryFile, Size: 5, Depth: 1},
+			{Name: "raw.bin", Path: "docs/raw.bin", Cid: "bafkraw", Type: DirEntryFile, Size: 9, Depth: 1},
+			{Name: "shard", Path: "shard", Cid: "QmShard", Type: DirEntrySharded, Size: 300},
+		}, entries)
+	})
+
+	t.Run("sharded folder", func(t *testing.T) {
+		_, err := client.ListFolderContentsWithOptions(context.Background(), "QmShard", &ListFolderOptions{Gateway: mockServer.URL})
+		require.ErrorIs(t, err, ErrShardedDirectory)
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		_, err := client.ListFolderContentsWithOptions(context.Background(), "QmA", &ListFolderOptions{Gateway: mockServer.URL})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a directory")
+	})
+
+	t.Run("empty cid", func(t *testing.T) {
+		_, err := client.ListFolderContents(context.Background(), "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cid is required")
+	})
+}
+
+func TestDeleteFolder(t *testing.T) {
+	// a.txt is also pinned directly, and the pin list fails for the sharded entry
+	newServer := func(t *testing.T, unpinned *[]string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasPrefix(r.URL.Path, "/ipfs/"):
+				node, ok := folderFixture[strings.TrimPrefix(r.URL.Path, "/ipfs/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(node))
+			case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/pinning/unpin/"):
+				*unpinned = append(*unpinned, strings.TrimPrefix(r.URL.Path, "/pinning/unpin/"))
+				w.Write([]byte("OK"))
+			case r.URL.Path == "/data/pinList":
+				switch cid := r.URL.Query().Get("cid"); cid {
+				case "QmA":
+					w.Write([]byte(`{"count":1,"rows":[{"id":"` + fixtures.PinID + `","ipfs_pin_hash":"QmA","date_pinned":"2024-05-21T19:27:03.391Z"}]}`))
+				case "QmShard":
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":"Invalid request"}`))
+				default:
+					w.Write([]byte(`{"count":0,"rows":[]}`))
+				}
+			default:
+				t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("reports children pinned on their own", func(t *testing.T) {
+		var unpinned []string
+		server := newServer(t, &unpinned)
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+
+		report, err := client.DeleteFolder(context.Background(), "QmRoot", &DeleteFolderOptions{CheckChildren: true, Gateway: server.URL})
+		require.NoError(t, err)
+		require.Equal(t, []string{"QmRoot"}, unpinned)
+		require.Equal(t, "QmRoot", report.Cid)
+		require.Equal(t, 2, report.Checked)
+		require.Equal(t, []DirEntry{{Name: "a.txt", Path: "a.txt", Cid: "QmA", Type: DirEntryFile, Size: 5}}, report.PinnedChildren)
+		require.Len(t, report.Unchecked, 1)
+		require.Equal(t, "QmShard", report.Unchecked[0].Cid)
+		require.Error(t, report.Unchecked[0].Err)
+	})
+
+	t.Run("without CheckChildren only the root is unpinned", func(t *testing.T) {
+		var unpinned []string
+		server := newServer(t, &unpinned)
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+
+		report, err := client.DeleteFolder(context.Background(), "QmRoot", nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"QmRoot"}, unpinned)
+		require.Equal(t, &DeleteFolderReport{Cid: "QmRoot"}, report)
+	})
+
+	t.Run("folders that cannot be listed stay pinned", func(t *testing.T) {
+		var unpinned []string
+		server := newServer(t, &unpinned)
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+
+		_, err := client.DeleteFolder(context.Background(), "QmMissing", &DeleteFolderOptions{CheckChildren: true, Gateway: server.URL})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to list folder QmMissing")
+		require.Empty(t, unpinned)
+	})
+
+	t.Run("empty cid", func(t *testing.T) {
+		_, err := New(&Auth{jwt: "valid_jwt_token"}).DeleteFolder(context.Background(), "", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cid is required")
+	})
+}
@@ -0,0 +1,108 @@
+package pinata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// ErrReservedFormField is returned by uploads whose extra form fields, set with
+// WithExtraFormFields, include a field written by the SDK.
+var ErrReservedFormField = errors.New("reserved form field")
+
+// reservedFormFields are the fields of pinFileToIPFS uploads written by the SDK.
+var reservedFormFields = map[string]bool{
+	"file":           true,
+	"pinataMetadata": true,
+	"pinataOptions":  true,
+}
+
+// WithExtraFormFields adds form fields the SDK does not know, such as the fields accepted by beta
+// endpoints of Pinata, to the uploads of PinFile, PinOpenFile, PinFolder, PinNestedFolders,
+// PinReader and PinURL. write is called once per upload, before anything is sent, with a
+// multipart writer whose parts are held in memory, so it should only write small fields. The parts
+// are then added to the body of every attempt, after the fields and files of the SDK and before
+// the form is closed. The fields written by the SDK, "file", "pinataMetadata" and "pinataOptions",
+// are reserved: an upload whose write adds one fails with an error wrapping ErrReservedFormField
+// before anything is sent, as does an upload whose write fails, with its error.
+func WithExtraFormFields(write func(w *multipart.Writer) error) ClientOption {
+	return func(c *Client) {
+		c.extraFormFields = write
+	}
+}
+
+// formPart is a part of a multipart form, held in memory.
+type formPart struct {
+	header textproto.MIMEHeader
+	data   []byte
+}
+
+// extraFormParts calls the function set with WithExtraFormFields and returns the parts it wrote,
+// or nil when no function is set. Parts named after a reserved field are rejected.
+func (c *Client) extraFormParts() ([]formPart, error) {
+	if c.extraFormFields == nil {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := c.extraFormFields(writer); err != nil {
+		return nil, fmt.Errorf("failed to write extra form fields: %w", err)
+	}
+	// the reader stops at the first closing delimiter, so write may close the writer itself
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write extra form fields: %w", err)
+	}
+
+	var parts []formPart
+	reader := multipart.NewReader(&buf, writer.Boundary())
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read extra form fields: %w", err)
+		}
+		if name := part.FormName(); reservedFormFields[name] {
+			return nil, fmt.Errorf("%w: %q is written by the SDK", ErrReservedFormField, name)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read extra form fields: %w", err)
+		}
+		parts = append(parts, formPart{header: part.Header, data: data})
+	}
+}
+
+// writeFormParts adds parts to writer.
+func writeFormParts(writer *multipart.Writer, parts []formPart) error {
+	for _, p := range parts {
+		w, err := writer.CreatePart(p.header)
+		if err != nil {
+			return fmt.Errorf("failed to write extra form field: %w", err)
+		}
+		if _, err := w.Write(p.data); err != nil {
+			return fmt.Errorf("failed to write extra form field: %w", err)
+		}
+	}
+	return nil
+}
+
+// pinMultipartBody is multipartBody for uploads to pinFileToIPFS, adding the extra form fields set
+// with WithExtraFormFields after the parts written by write.
+func (c *Client) pinMultipartBody(write func(*multipartForm) error) (func() (io.ReadCloser, error), string, int64, error) {
+	extra, err := c.extraFormParts()
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return c.multipartBody(func(form *multipartForm) error {
+		if err := write(form); err != nil {
+			return err
+		}
+		return writeFormParts(form.Writer, extra)
+	})
+}
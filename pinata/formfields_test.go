@@ -0,0 +1,87 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtraFormFields(t *testing.T) {
+	var requests atomic.Int32
+	var form *multipart.Form
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		form = r.MultipartForm
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	collection := WithExtraFormFields(func(w *multipart.Writer) error {
+		return w.WriteField("collection", "summer-drop")
+	})
+	dir := writeTree(t, "a.txt", "b.txt")
+
+	uploads := []struct {
+		name string
+		pin  func(c *Client) error
+	}{
+		{"PinFile", func(c *Client) error {
+			_, err := c.PinFile(filepath.Join(dir, "a.txt"), &PinOptions{PinataMetadata: PinataMetadata{Name: "a"}})
+			return err
+		}},
+		{"PinFolder", func(c *Client) error {
+			_, err := c.PinFolder([]string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, &PinOptions{})
+			return err
+		}},
+		{"PinReader", func(c *Client) error {
+			_, err := c.PinReader(context.Background(), strings.NewReader("stream"), "stream.txt", nil, nil)
+			return err
+		}},
+	}
+	for _, upload := range uploads {
+		t.Run(upload.name, func(t *testing.T) {
+			// the measured length of the body includes the extra fields
+			client := New(&Auth{jwt: "valid_jwt_token"}, collection, WithMultipartContentLength(true))
+			client.uploadURL = mockServer.URL
+
+			require.NoError(t, upload.pin(client))
+			require.Equal(t, []string{"summer-drop"}, form.Value["collection"])
+			require.NotEmpty(t, form.File["file"])
+		})
+	}
+
+	t.Run("reserved fields are rejected", func(t *testing.T) {
+		for _, name := range []string{"file", "pinataOptions"} {
+			client := New(&Auth{jwt: "valid_jwt_token"}, WithExtraFormFields(func(w *multipart.Writer) error {
+				return w.WriteField(name, "{}")
+			}))
+			client.uploadURL = mockServer.URL
+
+			before := requests.Load()
+			_, err := client.PinFile(filepath.Join(dir, "a.txt"), nil)
+			require.ErrorIs(t, err, ErrReservedFormField)
+			require.Contains(t, err.Error(), name)
+			require.Equal(t, before, requests.Load())
+		}
+	})
+
+	t.Run("write errors are returned", func(t *testing.T) {
+		errWrite := errors.New("no collection")
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithExtraFormFields(func(w *multipart.Writer) error {
+			return errWrite
+		}))
+		client.uploadURL = mockServer.URL
+
+		_, err := client.PinReader(context.Background(), strings.NewReader("stream"), "stream.txt", nil, nil)
+		require.ErrorIs(t, err, errWrite)
+	})
+}
@@ -1,2 +1,446 @@
 package pinata
 
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityGateway)
+}
+
+// GatewayURL is the default IPFS gateway used to retrieve pinned content.
+const GatewayURL = "https://gateway.pinata.cloud"
+
+// HealthCheckCID is the CID probed by HealthCheck: the empty UnixFS directory, which every
+// gateway can serve without fetching content from the network.
+const HealthCheckCID = "bafybeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354"
+
+// WithGateways sets the ordered list of gateway base URLs used by the gateway helpers. A request
+// falls back to the next gateway when the previous one fails with a connection error or a 5xx
+// status; other statuses, such as 404, are returned as is. When none are set, the gateway of the
+// client's Profile is used, GatewayURL by default.
+func WithGateways(gateways ...string) ClientOption {
+	return func(c *Client) {
+		c.gateways = gateways
+	}
+}
+
+// WithGatewayToken sets the access token of a dedicated gateway with restricted access. It is sent
+// in the x-pinata-gateway-token header of every request of the gateway helpers, to every gateway
+// tried, so it should only be set when every configured gateway belongs to the account.
+func WithGatewayToken(token string) ClientOption {
+	return func(c *Client) {
+		c.gatewayToken = token
+	}
+}
+
+// GatewayStat represents the result of probing a CID on a gateway.
+// StatusCode is the HTTP status code returned by the gateway.
+// Size is the Content-Length reported by the gateway, or -1 when unknown.
+// ContentType is the Content-Type reported by the gateway.
+// Gateway is the gateway that served the response.
+// Warnings lists the gateways that failed before Gateway served the response.
+type GatewayStat struct {
+	StatusCode  int       `json:"statusCode"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"contentType,omitempty"`
+	Gateway     string    `json:"gateway"`
+	Warnings    []Warning `json:"warnings,omitempty"`
+}
+
+// GatewayContent is the content of a CID streamed from a gateway. Body must be closed by the caller.
+// Size is the Content-Length reported by the gateway, or -1 when unknown.
+// ContentType is the Content-Type reported by the gateway.
+// Gateway is the gateway that served the content.
+// Warnings lists the gateways that failed before Gateway served the content.
+type GatewayContent struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+	Gateway     string
+	Warnings    []Warning
+}
+
+// GatewayHealth is the result of probing a single gateway.
+// Gateway is the gateway base URL.
+// Healthy reports whether the gateway served HealthCheckCID successfully.
+// StatusCode is the HTTP status code returned by the gateway, if a response was received.
+// Latency is the time taken by the probe.
+// Err describes why the gateway is unhealthy, if it is.
+type GatewayHealth struct {
+	Gateway    string
+	Healthy    bool
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+}
+
+// gatewayContentURL returns the URL of cid on the given gateway, falling back to GatewayURL
+// when gateway is empty.
+func gatewayContentURL(gateway, cid string) string {
+	if gateway == "" {
+		gateway = GatewayURL
+	}
+	return fmt.Sprintf("%s/ipfs/%s", strings.TrimRight(gateway, "/"), cid)
+}
+
+// contentPath returns cid followed by the slash-separated subPath segments, each escaped for use
+// in a URL path.
+func contentPath(cid string, subPath []string) string {
+	parts := []string{cid}
+	for _, p := range subPath {
+		for _, segment := range strings.Split(p, "/") {
+			if segment != "" {
+				parts = append(parts, url.PathEscape(segment))
+			}
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// contentGatewayURL returns the URL of cid and subPath on the first gateway of c, or on
+// GatewayURL when c is nil.
+func contentGatewayURL(c *Client, cid string, subPath []string) string {
+	gateway := GatewayURL
+	if c != nil {
+		gateway = c.gatewayHosts("")[0]
+	}
+	return gatewayContentURL(gateway, contentPath(cid, subPath))
+}
+
+// URI returns the ipfs:// URI of the pinned content. For a directory pin, subPath selects a file
+// inside the directory, e.g. URI("images", "cat.png").
+func (p PinResponse) URI(subPath ...string) string {
+	return "ipfs://" + contentPath(p.IpfsHash, subPath)
+}
+
+// GatewayURL returns the URL of the pinned content on the first gateway of c: the gateway set with
+// WithGateways, else the gateway of the client's Profile. A nil c uses GatewayURL. For a directory
+// pin, subPath selects a file inside the directory.
+func (p PinResponse) GatewayURL(c *Client, subPath ...string) string {
+	return contentGatewayURL(c, p.IpfsHash, subPath)
+}
+
+// URI returns the ipfs:// URI of the pinned content. For a directory pin, subPath selects a file
+// inside the directory.
+func (p Pin) URI(subPath ...string) string {
+	return "ipfs://" + contentPath(p.IPFSPinHash, subPath)
+}
+
+// GatewayURL returns the URL of the pinned content on the first gateway of c, as
+// PinResponse.GatewayURL does.
+func (p Pin) GatewayURL(c *Client, subPath ...string) string {
+	return contentGatewayURL(c, p.IPFSPinHash, subPath)
+}
+
+// gatewayHosts returns the gateways to try in order: gateway alone when set, otherwise the
+// gateways configured with WithGateways, otherwise the gateway of the client's Profile.
+func (c *Client) gatewayHosts(gateway string) []string {
+	if gateway != "" {
+		return []string{gateway}
+	}
+	if len(c.gateways) > 0 {
+		return c.gateways
+	}
+	if c.gatewayURL != "" {
+		return []string{c.gatewayURL}
+	}
+	return []string{GatewayURL}
+}
+
+// gatewayRound sends a request for cid to the gateways returned by gatewayHosts, moving on to the
+// next gateway on connection errors and 5xx responses. It returns the response, the gateway that
+// served it and a WarningGatewayFallback per gateway skipped; the last gateway's 5xx response is
+// returned as is. Every request waits for the gateway rate limit.
+func (c *Client) gatewayRound(ctx context.Context, gateway, method, cid, query string, header http.Header) (*http.Response, string, []Warning, error) {
+	hosts := c.gatewayHosts(gateway)
+
+	var warnings []Warning
+	var lastErr error
+	for i, host := range hosts {
+		if lastErr != nil {
+			w := Warning{
+				Code:    WarningGatewayFallback,
+				Message: "gateway failed, falling back to the next gateway",
+				Detail:  map[string]interface{}{"gateway": hosts[i-1], "next": host, "error": lastErr.Error()},
+			}
+			warnings = append(warnings, w)
+			c.warn(w)
+		}
+
+		if err := c.gatewayLimiter.wait(ctx); err != nil {
+			return nil, "", warnings, err
+		}
+		req, err := http.NewRequestWithContext(ctx, method, gatewayContentURL(host, cid)+query, nil)
+		if err != nil {
+			return nil, "", warnings, err
+		}
+		c.setUserAgent(req)
+		if c.gatewayToken != "" {
+			req.Header.Set("x-pinata-gateway-token", c.gatewayToken)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, "", warnings, transportError(ctx.Err())
+			}
+			lastErr = fmt.Errorf("gateway %s: %w", host, transportError(err))
+			continue
+		}
+		if resp.StatusCode >= 500 && i < len(hosts)-1 {
+			resp.Body.Close()
+			lastErr = &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("gateway %s returned %s", host, resp.Status)}
+			continue
+		}
+		return resp, host, warnings, nil
+	}
+	return nil, "", warnings, lastErr
+}
+
+// statGateway issues a HEAD request for cid and reports the status code and content length,
+// retrying it according to the gateway retry policy when retry is set. Transport errors are
+// returned as errors; HTTP error statuses are not.
+func (c *Client) statGateway(ctx context.Context, gateway, cid string, retry bool) (*GatewayStat, error) {
+	do := c.gatewayRound
+	if retry {
+		do = c.gatewayDo
+	}
+	ctx, deadline := c.startDownload(ctx)
+	defer deadline.stop()
+	resp, host, warnings, err := do(ctx, gateway, http.MethodHead, cid, "", nil)
+	if err != nil {
+		return nil, deadline.err(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	return &GatewayStat{
+		StatusCode:  resp.StatusCode,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		Gateway:     host,
+		Warnings:    warnings,
+	}, nil
+}
+
+// GatewayClient groups the helpers that retrieve content from IPFS gateways. It is obtained with
+// Client.Gateway and shares the gateways, transport and hooks of its Client.
+type GatewayClient struct {
+	c *Client
+}
+
+// Gateway returns the sub-client for content retrieval from gateways. It is created on first use,
+// and every call returns the same value; it is safe for concurrent use.
+func (c *Client) Gateway() *GatewayClient {
+	c.gatewayOnce.Do(func() {
+		c.gatewayClient = &GatewayClient{c: c}
+	})
+	return c.gatewayClient
+}
+
+// Stat probes cid on the configured gateways with a HEAD request. HTTP error statuses
+// are reported through StatusCode rather than as errors. The request is retried only as set with
+// WithGatewayRetryPolicy, whose pauses add to the time Stat takes.
+func (g *GatewayClient) Stat(ctx context.Context, cid string) (*GatewayStat, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("cid is required")
+	}
+	return g.c.statGateway(ctx, "", cid, true)
+}
+
+// Get streams the content of cid from the configured gateways. Statuses other than
+// 200 OK are returned as errors. The download is reported by ActiveOperations until the body of
+// the content is closed. The request is retried only as set with WithGatewayRetryPolicy, whose
+// pauses add to the time Get takes before it returns.
+func (g *GatewayClient) Get(ctx context.Context, cid string) (*GatewayContent, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("cid is required")
+	}
+	return g.c.gatewayGet(ctx, g.c.startOp("Gateway.Get", cid), cid)
+}
+
+// gatewayGet implements GatewayClient.Get, counting the download in op, which it ends when the
+// request fails or the body of the content is closed.
+func (c *Client) gatewayGet(ctx context.Context, op *activeOp, cid string) (*GatewayContent, error) {
+	ctx, deadline := c.startDownload(ctx)
+	resp, host, warnings, err := c.gatewayDo(ctx, "", http.MethodGet, cid, "", nil)
+	if err != nil {
+		c.endOp(op)
+		deadline.stop()
+		return nil, deadline.err(ctx, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		c.endOp(op)
+		resp.Body.Close()
+		deadline.stop()
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("gateway %s returned %s for %s", host, resp.Status, cid)}
+	}
+	deadline.fit(resp.ContentLength)
+	body := deadline.body(ctx, resp.Body)
+
+	return &GatewayContent{
+		Body:        &opReadCloser{opReader: opReader{r: body, op: op}, c: c, closer: body},
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		Gateway:     host,
+		Warnings:    warnings,
+	}, nil
+}
+
+// HealthCheck probes each configured gateway, without fallback or retries, by requesting
+// HealthCheckCID, and returns one result per gateway in order.
+func (g *GatewayClient) HealthCheck(ctx context.Context) []GatewayHealth {
+	hosts := g.c.gatewayHosts("")
+	results := make([]GatewayHealth, len(hosts))
+	for i, host := range hosts {
+		start := time.Now()
+		stat, err := g.c.statGateway(ctx, host, HealthCheckCID, false)
+		results[i] = GatewayHealth{Gateway: host, Latency: time.Since(start), Err: err}
+		if err != nil {
+			continue
+		}
+		results[i].StatusCode = stat.StatusCode
+		results[i].Healthy = stat.StatusCode >= 200 && stat.StatusCode < 300
+		if !results[i].Healthy {
+			results[i].Err = &APIError{StatusCode: stat.StatusCode, Message: fmt.Sprintf("gateway returned status %d", stat.StatusCode)}
+		}
+	}
+	return results
+}
+
+// StatContent probes cid on the configured gateways. It is an alias of c.Gateway().Stat, kept for
+// compatibility.
+func (c *Client) StatContent(ctx context.Context, cid string) (*GatewayStat, error) {
+	return c.Gateway().Stat(ctx, cid)
+}
+
+// GetContent streams the content of cid from the configured gateways. It is an alias of
+// c.Gateway().Get, kept for compatibility.
+func (c *Client) GetContent(ctx context.Context, cid string) (*GatewayContent, error) {
+	return c.Gateway().Get(ctx, cid)
+}
+
+// HealthCheck probes each configured gateway. It is an alias of c.Gateway().HealthCheck, kept for
+// compatibility.
+func (c *Client) HealthCheck(ctx context.Context) []GatewayHealth {
+	return c.Gateway().HealthCheck(ctx)
+}
+
+// unixfsType is the UnixFS node type carried in the Data field of a dag-pb node.
+type unixfsType int
+
+const (
+	unixfsRaw       unixfsType = 0
+	unixfsDirectory unixfsType = 1
+	unixfsFile      unixfsType = 2
+	unixfsHAMTShard unixfsType = 5
+)
+
+// dagNode is a dag-pb node as served by a gateway in the dag-json format.
+type dagNode struct {
+	Data struct {
+		Slash struct {
+			Bytes string `json:"bytes"`
+		} `json:"/"`
+	} `json:"Data"`
+	Links []dagLink `json:"Links"`
+}
+
+// dagLink is a link of a dag-pb node. Tsize is the cumulative size of the linked DAG.
+type dagLink struct {
+	Hash struct {
+		Slash string `json:"/"`
+	} `json:"Hash"`
+	Name  string `json:"Name"`
+	Tsize int64  `json:"Tsize"`
+}
+
+// unixfs decodes the UnixFS type (protobuf field 1) and file size (field 3) from the node data.
+// Nodes without data are reported as raw. The file size is -1 when the node does not carry one.
+func (n *dagNode) unixfs() (unixfsType, int64, error) {
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(n.Data.Slash.Bytes, "="))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid node data: %w", err)
+	}
+
+	kind, size := unixfsRaw, int64(-1)
+	for len(data) > 0 {
+		key, n := protoVarint(data)
+		if n == 0 {
+			return 0, 0, fmt.Errorf("invalid node data: truncated field")
+		}
+		data = data[n:]
+
+		switch key & 0x7 {
+		case 0: // varint
+			value, n := protoVarint(data)
+			if n == 0 {
+				return 0, 0, fmt.Errorf("invalid node data: truncated varint")
+			}
+			data = data[n:]
+			switch key >> 3 {
+			case 1:
+				kind = unixfsType(value)
+			case 3:
+				size = int64(value)
+			}
+		case 2: // length-delimited
+			length, n := protoVarint(data)
+			if n == 0 || uint64(len(data)-n) < length {
+				return 0, 0, fmt.Errorf("invalid node data: truncated bytes")
+			}
+			data = data[n+int(length):]
+		default:
+			return 0, 0, fmt.Errorf("invalid node data: unsupported wire type %d", key&0x7)
+		}
+	}
+	return kind, size, nil
+}
+
+// protoVarint decodes a protobuf varint, the unsigned varint also used by multiformats, returning
+// the value and the number of bytes read (0 when data is truncated).
+func protoVarint(data []byte) (uint64, int) {
+	var value uint64
+	for i, shift := 0, uint(0); i < len(data) && shift < 64; i, shift = i+1, shift+7 {
+		value |= uint64(data[i]&0x7f) << shift
+		if data[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// fetchDAGNode retrieves the dag-pb node of cid from the gateway in the dag-json format.
+func (c *Client) fetchDAGNode(ctx context.Context, gateway, cid string) (*dagNode, error) {
+	header := http.Header{"Accept": []string{"application/vnd.ipld.dag-json"}}
+	ctx, deadline := c.startDownload(ctx)
+	defer deadline.stop()
+	resp, _, _, err := c.gatewayDo(ctx, gateway, http.MethodGet, cid, "?format=dag-json", header)
+	if err != nil {
+		return nil, deadline.err(ctx, err)
+	}
+	deadline.fit(resp.ContentLength)
+	body := deadline.body(ctx, resp.Body)
+	defer body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("gateway returned %s for %s", resp.Status, cid)}
+	}
+
+	var node dagNode
+	if err := json.NewDecoder(body).Decode(&node); err != nil {
+		return nil, fmt.Errorf("failed to decode dag-json node %s: %w", cid, readError(err))
+	}
+	return &node, nil
+}
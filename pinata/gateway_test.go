@@ -0,0 +1,357 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newGatewayServer serves "hello" for every CID with the given status, counting the requests.
+func newGatewayServer(status int, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", "5")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write([]byte("hello"))
+		}
+	}))
+}
+
+func TestGatewayFallback(t *testing.T) {
+	t.Run("falls back on 5xx", func(t *testing.T) {
+		var primaryRequests, secondaryRequests int32
+		primary := newGatewayServer(http.StatusBadGateway, &primaryRequests)
+		defer primary.Close()
+		secondary := newGatewayServer(http.StatusOK, &secondaryRequests)
+		defer secondary.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(primary.URL, secondary.URL))
+
+		content, err := client.GetContent(context.Background(), "QmHello")
+		require.NoError(t, err)
+		defer content.Body.Close()
+		body, err := io.ReadAll(content.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, "hello", string(body))
+		require.Equal(t, secondary.URL, content.Gateway)
+		require.Equal(t, "text/plain", content.ContentType)
+		require.Equal(t, int32(1), atomic.LoadInt32(&primaryRequests))
+		require.Equal(t, int32(1), atomic.LoadInt32(&secondaryRequests))
+	})
+
+	t.Run("falls back on connection error", func(t *testing.T) {
+		var requests int32
+		down := newGatewayServer(http.StatusOK, &requests)
+		down.Close()
+		secondary := newGatewayServer(http.StatusOK, &requests)
+		defer secondary.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(down.URL, secondary.URL))
+
+		stat, err := client.StatContent(context.Background(), "QmHello")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, stat.StatusCode)
+		require.Equal(t, int64(5), stat.Size)
+		require.Equal(t, secondary.URL, stat.Gateway)
+	})
+
+	t.Run("does not fall back on 404", func(t *testing.T) {
+		var primaryRequests, secondaryRequests int32
+		primary := newGatewayServer(http.StatusNotFound, &primaryRequests)
+		defer primary.Close()
+		secondary := newGatewayServer(http.StatusOK, &secondaryRequests)
+		defer secondary.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(primary.URL, secondary.URL))
+
+		stat, err := client.StatContent(context.Background(), "QmMissing")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, stat.StatusCode)
+		require.Equal(t, primary.URL, stat.Gateway)
+		require.Zero(t, atomic.LoadInt32(&secondaryRequests))
+
+		_, err = client.GetContent(context.Background(), "QmMissing")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("all gateways down", func(t *testing.T) {
+		var requests int32
+		first := newGatewayServer(http.StatusOK, &requests)
+		first.Close()
+		second := newGatewayServer(http.StatusOK, &requests)
+		second.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(first.URL, second.URL),
+			WithGatewayRetryPolicy(GatewayRetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}))
+
+		_, err := client.GetContent(context.Background(), "QmHello")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), second.URL)
+	})
+
+	t.Run("last gateway 5xx is returned", func(t *testing.T) {
+		var requests int32
+		primary := newGatewayServer(http.StatusServiceUnavailable, &requests)
+		defer primary.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(primary.URL),
+			WithGatewayRetryPolicy(GatewayRetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}))
+
+		stat, err := client.StatContent(context.Background(), "QmHello")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, stat.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	var requests int32
+	down := newGatewayServer(http.StatusOK, &requests)
+	down.Close()
+	failing := newGatewayServer(http.StatusInternalServerError, &requests)
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/ipfs/"+HealthCheckCID, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(down.URL, failing.URL, healthy.URL))
+
+	results := client.HealthCheck(context.Background())
+
+	require.Len(t, results, 3)
+	require.Equal(t, down.URL, results[0].Gateway)
+	require.False(t, results[0].Healthy)
+	require.Error(t, results[0].Err)
+
+	require.Equal(t, failing.URL, results[1].Gateway)
+	require.False(t, results[1].Healthy)
+	require.Equal(t, http.StatusInternalServerError, results[1].StatusCode)
+
+	require.Equal(t, healthy.URL, results[2].Gateway)
+	require.True(t, results[2].Healthy)
+	require.NoError(t, results[2].Err)
+	require.Equal(t, http.StatusOK, results[2].StatusCode)
+}
+
+// throttlingServer answers the first throttled requests of every path with 429, then serves
+// "hello" for gateway paths and a successful authentication test for API paths.
+type throttlingServer struct {
+	mu        sync.Mutex
+	throttled int
+	requests  map[string]int
+}
+
+func (s *throttlingServer) handler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.URL.Path]++
+	n := s.requests[r.URL.Path]
+	s.mu.Unlock()
+
+	if n <= s.throttled {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/ipfs/") {
+		w.Write([]byte("hello"))
+		return
+	}
+	serveFixture(w, r)
+}
+
+func (s *throttlingServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func TestGatewayRetries(t *testing.T) {
+	fast := WithGatewayRetryPolicy(GatewayRetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	t.Run("404 is never retried", func(t *testing.T) {
+		var requests int32
+		gateway := newGatewayServer(http.StatusNotFound, &requests)
+		defer gateway.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(gateway.URL), fast)
+
+		_, err := client.Gateway().Get(context.Background(), "QmMissing")
+		require.Error(t, err)
+		require.False(t, IsRetryable(err))
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("5xx is retried until the retries run out", func(t *testing.T) {
+		var requests int32
+		gateway := newGatewayServer(http.StatusBadGateway, &requests)
+		defer gateway.Close()
+		recorder := &warningRecorder{}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(gateway.URL), fast, WithWarningHandler(recorder.handle))
+
+		_, err := client.Gateway().Get(context.Background(), "QmHello")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "502")
+		require.Equal(t, int32(4), atomic.LoadInt32(&requests))
+		require.Equal(t, []WarningCode{WarningGatewayRetry, WarningGatewayRetry, WarningGatewayRetry}, recorder.codes())
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		var requests int32
+		gateway := newGatewayServer(http.StatusBadGateway, &requests)
+		defer gateway.Close()
+		recorder := &warningRecorder{}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(gateway.URL), WithWarningHandler(recorder.handle))
+
+		_, err := client.Gateway().Get(context.Background(), "QmHello")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "502")
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+		require.Empty(t, recorder.codes())
+	})
+
+	t.Run("retries with the defaults", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGatewayRetryPolicy(GatewayRetryPolicy{}))
+
+		policy := client.gatewayRetryPolicy()
+		require.Equal(t, DefaultGatewayMaxRetries, policy.MaxRetries)
+		require.Equal(t, DefaultGatewayInitialBackoff, policy.InitialBackoff)
+		require.Equal(t, DefaultGatewayMaxBackoff, policy.MaxBackoff)
+	})
+
+	t.Run("retries can be disabled", func(t *testing.T) {
+		var requests int32
+		gateway := newGatewayServer(http.StatusServiceUnavailable, &requests)
+		defer gateway.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(gateway.URL), WithGatewayRetryPolicy(GatewayRetryPolicy{MaxRetries: -1}))
+
+		stat, err := client.Gateway().Stat(context.Background(), "QmHello")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, stat.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("health checks are not retried", func(t *testing.T) {
+		var requests int32
+		gateway := newGatewayServer(http.StatusServiceUnavailable, &requests)
+		defer gateway.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(gateway.URL), fast)
+
+		results := client.Gateway().HealthCheck(context.Background())
+		require.False(t, results[0].Healthy)
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("simultaneous throttling of the API and the gateway", func(t *testing.T) {
+		server := &throttlingServer{throttled: 2, requests: make(map[string]int)}
+		mockServer := httptest.NewServer(http.HandlerFunc(server.handler))
+		defer mockServer.Close()
+		recorder := &warningRecorder{}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(mockServer.URL), fast, WithWarningHandler(recorder.handle))
+		client.baseURL = mockServer.URL
+
+		// the gateway retries past the throttling, within its own retry budget
+		content, err := client.Gateway().Get(context.Background(), "QmHello")
+		require.NoError(t, err)
+		body, err := io.ReadAll(content.Body)
+		content.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(body))
+		require.Equal(t, 3, server.count("/ipfs/QmHello"))
+		require.Equal(t, []WarningCode{WarningGatewayRetry, WarningGatewayRetry}, recorder.codes())
+
+		// the API does not share the gateway retry policy: the 429 is returned to the caller
+		_, err = client.TestAuthentication()
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		require.Equal(t, time.Second, apiErr.RetryAfter)
+		require.Equal(t, 1, server.count("/data/testAuthentication"))
+	})
+
+	t.Run("rate limit applies to gateway requests only", func(t *testing.T) {
+		server := &throttlingServer{requests: make(map[string]int)}
+		mockServer := httptest.NewServer(http.HandlerFunc(server.handler))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(mockServer.URL), WithGatewayRateLimit(20, 1))
+		client.baseURL = mockServer.URL
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := client.Gateway().Stat(context.Background(), "QmHello")
+			require.NoError(t, err)
+		}
+		require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+		start = time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := client.TestAuthentication()
+			require.NoError(t, err)
+		}
+		require.Less(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("rate limit wait is cancelled with the context", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways("http://127.0.0.1:1"), WithGatewayRateLimit(0.001, 1))
+		client.gatewayLimiter.tokens = 0
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := client.Gateway().Stat(ctx, "QmHello")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestContentURLs(t *testing.T) {
+	const (
+		cidV0 = "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"
+		cidV1 = "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+	)
+
+	t.Run("v0 and v1 CIDs", func(t *testing.T) {
+		for _, cid := range []string{cidV0, cidV1} {
+			response := PinResponse{IpfsHash: cid}
+			require.Equal(t, "ipfs://"+cid, response.URI())
+			require.Equal(t, GatewayURL+"/ipfs/"+cid, response.GatewayURL(nil))
+			require.Equal(t, GatewayURL+"/ipfs/"+cid, response.GatewayURL(New(NewAuthWithJWT("test_token"))))
+
+			listed := Pin{IPFSPinHash: cid}
+			require.Equal(t, response.URI(), listed.URI())
+			require.Equal(t, response.GatewayURL(nil), listed.GatewayURL(nil))
+		}
+	})
+
+	t.Run("custom gateway hosts", func(t *testing.T) {
+		response := PinResponse{IpfsHash: cidV1}
+
+		client := New(NewAuthWithJWT("test_token"), WithGateways("https://example.mypinata.cloud/", "https://ipfs.io"))
+		require.Equal(t, "https://example.mypinata.cloud/ipfs/"+cidV1, response.GatewayURL(client))
+
+		client = New(NewAuthWithJWT("test_token"), WithProfile(Profile{GatewayURL: "http://localhost:8080"}))
+		require.Equal(t, "http://localhost:8080/ipfs/"+cidV1, response.GatewayURL(client))
+	})
+
+	t.Run("directory sub-path", func(t *testing.T) {
+		response := PinResponse{IpfsHash: cidV1}
+
+		require.Equal(t, "ipfs://"+cidV1+"/images/cat.png", response.URI("images", "cat.png"))
+		require.Equal(t, "ipfs://"+cidV1+"/images/cat.png", response.URI("/images/cat.png"))
+		require.Equal(t, GatewayURL+"/ipfs/"+cidV1+"/my%20docs/a%3Fb.txt", response.GatewayURL(nil, "my docs/a?b.txt"))
+	})
+}
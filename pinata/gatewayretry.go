@@ -0,0 +1,178 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults of GatewayRetryPolicy, tuned for content retrieval: gateways often answer 429 or 5xx
+// while they fetch content from the network, so they are retried more, and waited for longer,
+// than API requests. They apply once retries are enabled with WithGatewayRetryPolicy; with all of
+// them, a request may wait up to 62 seconds between its six attempts, on top of the attempts
+// themselves.
+const (
+	DefaultGatewayMaxRetries     = 5
+	DefaultGatewayInitialBackoff = 2 * time.Second
+	DefaultGatewayMaxBackoff     = time.Minute
+)
+
+// GatewayRetryPolicy configures the retries of the requests of the gateway helpers, such as
+// GatewayClient.Get and Stat, which are not retried unless a policy is set with
+// WithGatewayRetryPolicy. It is separate from the handling of API rate limits: throttling by a
+// gateway never delays API requests, and the reverse.
+// MaxRetries is the number of retries after the first attempt (DefaultGatewayMaxRetries when zero);
+// a negative value disables retries.
+// InitialBackoff is the pause before the first retry; it doubles on every retry
+// (DefaultGatewayInitialBackoff when zero). A longer Retry-After sent by the gateway is honored.
+// MaxBackoff caps every pause (DefaultGatewayMaxBackoff when zero).
+type GatewayRetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// WithGatewayRetryPolicy enables the retries of the gateway helpers, which send each request once
+// by default, and sets their policy; GatewayRetryPolicy{} retries with the defaults. A request is
+// retried when every gateway failed with a connection error, or when the last gateway tried
+// answered 429, 408, 425 or a 5xx status other than 501 and 505; 404 and other statuses are never
+// retried. Each retry goes through the gateways again in order and is reported as a
+// WarningGatewayRetry.
+func WithGatewayRetryPolicy(policy GatewayRetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.gatewayRetry = &policy
+	}
+}
+
+// WithGatewayRateLimit limits the gateway helpers to requestsPerSecond requests per second, with
+// bursts of up to burst requests, across every gateway. Requests over the limit wait for their
+// turn. The limit does not apply to API requests, see WithAPIRateLimit. Gateway requests are not
+// limited by default, and a requestsPerSecond of zero or less removes the limit.
+func WithGatewayRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.gatewayLimiter = nil
+		if requestsPerSecond > 0 {
+			c.gatewayLimiter = newRateLimiter(requestsPerSecond, burst)
+		}
+	}
+}
+
+// gatewayRetryPolicy returns the gateway retry policy of c with the defaults applied, or a policy
+// without retries when none is set.
+func (c *Client) gatewayRetryPolicy() GatewayRetryPolicy {
+	if c.gatewayRetry == nil {
+		return GatewayRetryPolicy{}
+	}
+	policy := *c.gatewayRetry
+	switch {
+	case policy.MaxRetries < 0:
+		policy.MaxRetries = 0
+	case policy.MaxRetries == 0:
+		policy.MaxRetries = DefaultGatewayMaxRetries
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultGatewayInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultGatewayMaxBackoff
+	}
+	return policy
+}
+
+// gatewayDo sends a request for cid to the gateways returned by gatewayHosts as gatewayRound does,
+// retrying it according to the gateway retry policy. Warnings of every round and retry are
+// returned.
+func (c *Client) gatewayDo(ctx context.Context, gateway, method, cid, query string, header http.Header) (*http.Response, string, []Warning, error) {
+	policy := c.gatewayRetryPolicy()
+	backoff := policy.InitialBackoff
+
+	var warnings []Warning
+	for attempt := 1; ; attempt++ {
+		resp, host, roundWarnings, err := c.gatewayRound(ctx, gateway, method, cid, query, header)
+		warnings = append(warnings, roundWarnings...)
+		if attempt > policy.MaxRetries || ctx.Err() != nil || !gatewayRetryable(resp, err) {
+			return resp, host, warnings, err
+		}
+
+		delay := backoff
+		detail := map[string]interface{}{"attempt": attempt}
+		if err != nil {
+			detail["error"] = err.Error()
+		} else {
+			detail["gateway"] = host
+			detail["status"] = resp.StatusCode
+			delay = max(delay, parseRetryAfter(resp.Header.Get("Retry-After")))
+			resp.Body.Close()
+		}
+		delay = min(delay, policy.MaxBackoff)
+		backoff = min(backoff*2, policy.MaxBackoff)
+		detail["delay"] = delay.String()
+
+		w := Warning{Code: WarningGatewayRetry, Message: "gateway request failed, retrying", Detail: detail}
+		warnings = append(warnings, w)
+		c.warn(w)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, "", warnings, transportError(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// gatewayRetryable reports whether the outcome of a gateway round is worth retrying: retryable
+// transport errors and retryable statuses, as classified by IsRetryable.
+func gatewayRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return IsRetryable(err)
+	}
+	return IsRetryable(&APIError{StatusCode: resp.StatusCode})
+}
+
+// rateLimiter is a token bucket limiting the rate of gateway or API requests. Requests over the
+// limit reserve a future token and wait for it.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	b := float64(max(burst, 1))
+	return &rateLimiter{rate: rate, burst: b, tokens: b, last: time.Now()}
+}
+
+// wait blocks until the request may be sent, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return transportError(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
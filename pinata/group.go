@@ -1,10 +1,32 @@
 package pinata
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"time"
 )
 
+func init() {
+	registerCapability(CapabilityGroups)
+}
+
+// ErrGroupNotFound is returned when a group referenced by an operation does not exist.
+var ErrGroupNotFound = errors.New("group not found")
+
+// ErrPinJobFailed is returned when a pin by CID job ends in a failure status, such as expired or
+// over_free_limit.
+var ErrPinJobFailed = errors.New("pin job failed")
+
+// defaultPinJobPollInterval is how often PinCidToGroup polls pinJobs while waiting for a pin.
+const defaultPinJobPollInterval = 5 * time.Second
+
+// groupsPageLimit is the number of groups fetched per page when listing every group.
+const groupsPageLimit = 1000
+
 // Group represents a group in the Pinata platform.
 // It contains information about the group, such as its ID, owner ID, name, creation time, and last update time.
 type Group struct {
@@ -18,30 +40,48 @@ type Group struct {
 // ListGroupsOptions represents the options for listing Pinata groups.
 // The NameContains field filters the groups by name, the Limit field sets the maximum number of groups to return,
 // and the Offset field sets the starting index for the returned groups.
+// Limit and Offset are only sent when set, so an explicit zero can be requested with Int(0).
 type ListGroupsOptions struct {
 	NameContains string `json:"nameContains,omitempty"`
-	Limit        int    `json:"limit,omitempty"`
-	Offset       int    `json:"offset,omitempty"`
+	Limit        *int   `json:"limit,omitempty"`
+	Offset       *int   `json:"offset,omitempty"`
 }
 
 // CreateGroup creates a new Pinata group with the specified name.
 // It returns the newly created Group object, or an error if the creation failed.
-// The group name is required and cannot be an empty string.
+// The group name is required and cannot be an empty string. Surrounding whitespace is trimmed, and
+// names with control characters or exceeding the client's group name limit are rejected with an
+// error wrapping ErrInvalidName before any request is made.
 func (c *Client) CreateGroup(groupName string) (*Group, error) {
+	return c.createGroup(context.Background(), groupName)
+}
+
+// CreateGroupWithContext is like CreateGroup but uses ctx for the requests.
+func (c *Client) CreateGroupWithContext(ctx context.Context, groupName string) (*Group, error) {
+	return c.createGroup(ctx, groupName)
+}
+
+// createGroup implements CreateGroup, using ctx for the request.
+func (c *Client) createGroup(ctx context.Context, groupName string) (*Group, error) {
 	if groupName == "" {
 		return nil, fmt.Errorf("group name is required")
 	}
+	groupName, err := c.normalizeGroupName(groupName)
+	if err != nil {
+		return nil, err
+	}
 
 	payload := make(map[string]string)
 	payload["name"] = groupName
 
-	req, err := c.NewRequest(http.MethodPost, "/groups").SetJSONBody(payload)
+	req, err := c.NewRequest(http.MethodPost, "/groups").WithContext(ctx).SetJSONBody(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set JSON body: %w", err)
 	}
 
 	var response Group
 	err = req.Send(&response)
+	c.readCache.purge()
 	if err != nil {
 		return nil, err
 	}
@@ -53,15 +93,30 @@ func (c *Client) CreateGroup(groupName string) (*Group, error) {
 // If the provided groupID is empty, an error is returned.
 // Otherwise, the function makes a GET request to the "/groups/{id}" endpoint
 // and returns the corresponding Group struct, or an error if the request fails.
+//
+// Deprecated: use c.Groups().Get, which takes a GroupID. GetGroup will be removed in the next
+// release.
 func (c *Client) GetGroup(groupID string) (*Group, error) {
+	return c.getGroup(context.Background(), groupID)
+}
+
+// getGroup implements GetGroup, using ctx for the request.
+func (c *Client) getGroup(ctx context.Context, groupID string) (*Group, error) {
 	if groupID == "" {
 		return nil, fmt.Errorf("group id is required")
 	}
+	if err := checkGroupID(groupID); err != nil {
+		return nil, err
+	}
 
-	var response Group
-	err := c.NewRequest(http.MethodGet, "/groups/{id}").
-		AddPathParam("id", groupID).
-		Send(&response)
+	response, err := cachedRead(ctx, c, "group "+groupID, func(ctx context.Context) (Group, error) {
+		var response Group
+		err := c.NewRequest(http.MethodGet, "/groups/{id}").
+			WithContext(ctx).
+			AddPathParam("id", groupID).
+			Send(&response)
+		return response, err
+	})
 
 	if err != nil {
 		return nil, err
@@ -73,7 +128,30 @@ func (c *Client) GetGroup(groupID string) (*Group, error) {
 // If options is nil, the function will return all groups without any filtering or pagination.
 // Otherwise, the function will apply the specified limit and offset to the list of groups.
 func (c *Client) ListGroups(options *ListGroupsOptions) ([]Group, error) {
-	req := c.NewRequest(http.MethodGet, "/groups")
+	return c.listGroups(context.Background(), options)
+}
+
+// ListGroupsWithContext is like ListGroups but uses ctx for the requests.
+func (c *Client) ListGroupsWithContext(ctx context.Context, options *ListGroupsOptions) ([]Group, error) {
+	return c.listGroups(ctx, options)
+}
+
+// listGroups implements ListGroups, using ctx for the request. The groups are served from the read
+// cache when enabled.
+func (c *Client) listGroups(ctx context.Context, options *ListGroupsOptions) ([]Group, error) {
+	key, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := cachedRead(ctx, c, "groups "+string(key), func(ctx context.Context) ([]Group, error) {
+		return c.fetchGroups(ctx, options)
+	})
+	return slices.Clone(groups), err
+}
+
+// fetchGroups lists the groups matching options, bypassing the read cache.
+func (c *Client) fetchGroups(ctx context.Context, options *ListGroupsOptions) ([]Group, error) {
+	req := c.NewRequest(http.MethodGet, "/groups").WithContext(ctx)
 	if options != nil {
 		req.setListGroupsQueryParams(options)
 	}
@@ -87,21 +165,60 @@ func (c *Client) ListGroups(options *ListGroupsOptions) ([]Group, error) {
 	return response, nil
 }
 
+// listAllGroups returns every group of the account, walking the groups endpoint with pages of
+// groupsPageLimit. Rate limited pages are retried as configured with WithPagination.
+func (c *Client) listAllGroups(ctx context.Context) ([]Group, error) {
+	var all []Group
+	pages := c.newPaginator("/groups")
+	for offset := 0; ; offset += groupsPageLimit {
+		var groups []Group
+		err := pages.fetch(ctx, func() (int, error) {
+			var err error
+			groups, err = c.fetchGroups(ctx, &ListGroupsOptions{Limit: Int(groupsPageLimit), Offset: Int(offset)})
+			return len(groups), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, groups...)
+		if len(groups) < groupsPageLimit {
+			return all, nil
+		}
+	}
+}
+
 // UpdateGroup updates the name of the Pinata group with the specified ID.
 //
 // If the provided groupID or newGroupName is empty, an error is returned.
+// The new group name is validated the same way as in CreateGroup.
 // Otherwise, the function makes a PUT request to the "/groups/{id}" endpoint
 // with the new group name in the request body, and returns the updated
 // Group struct, or an error if the request fails.
+//
+// Deprecated: use c.Groups().Update, which takes a GroupID. UpdateGroup will be removed in the
+// next release.
 func (c *Client) UpdateGroup(groupID, newGroupName string) (*Group, error) {
+	return c.updateGroup(context.Background(), groupID, newGroupName)
+}
+
+// updateGroup implements UpdateGroup, using ctx for the request.
+func (c *Client) updateGroup(ctx context.Context, groupID, newGroupName string) (*Group, error) {
 	if groupID == "" || newGroupName == "" {
 		return nil, fmt.Errorf("group id and new group name are required")
 	}
+	if err := checkGroupID(groupID); err != nil {
+		return nil, err
+	}
+	newGroupName, err := c.normalizeGroupName(newGroupName)
+	if err != nil {
+		return nil, err
+	}
 
 	payload := make(map[string]string)
 	payload["name"] = newGroupName
 
 	req, err := c.NewRequest(http.MethodPut, "/groups/{id}").
+		WithContext(ctx).
 		AddPathParam("id", groupID).
 		SetJSONBody(payload)
 	if err != nil {
@@ -110,6 +227,7 @@ func (c *Client) UpdateGroup(groupID, newGroupName string) (*Group, error) {
 
 	var response Group
 	err = req.Send(&response)
+	c.readCache.purge()
 	if err != nil {
 		return nil, err
 	}
@@ -118,15 +236,33 @@ func (c *Client) UpdateGroup(groupID, newGroupName string) (*Group, error) {
 
 // AddCidToGroup adds the specified CIDs to the group with the given ID.
 // If the group ID or the list of CIDs is empty, an error is returned.
+//
+// Deprecated: use c.Groups().AddCids, which takes a GroupID and Cids, so that the compiler catches
+// swapped arguments. AddCidToGroup will be removed in the next release.
 func (c *Client) AddCidToGroup(groupID string, cids []string) error {
+	return c.addCidToGroup(context.Background(), groupID, cids)
+}
+
+// addCidToGroup implements AddCidToGroup, using ctx for the request.
+func (c *Client) addCidToGroup(ctx context.Context, groupID string, cids []string) error {
 	if groupID == "" || len(cids) == 0 {
 		return fmt.Errorf("group id and at least one cid is required")
 	}
+	if err := checkGroupID(groupID); err != nil {
+		return err
+	}
+
+	unlock, err := c.lockCids(ctx, cids...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	payload := make(map[string][]string)
 	payload["cids"] = cids
 
 	req, err := c.NewRequest(http.MethodPut, "/groups/{id}/cids").
+		WithContext(ctx).
 		AddPathParam("id", groupID).
 		SetJSONBody(payload)
 	if err != nil {
@@ -142,15 +278,33 @@ func (c *Client) AddCidToGroup(groupID string, cids []string) error {
 
 // RemoveCidFromGroup removes the specified CIDs from the group with the given ID.
 // If the group ID or the list of CIDs is empty, an error is returned.
+//
+// Deprecated: use c.Groups().RemoveCids, which takes a GroupID and Cids, so that the compiler catches
+// swapped arguments. RemoveCidFromGroup will be removed in the next release.
 func (c *Client) RemoveCidFromGroup(groupID string, cids []string) error {
+	return c.removeCidFromGroup(context.Background(), groupID, cids)
+}
+
+// removeCidFromGroup implements RemoveCidFromGroup, using ctx for the request.
+func (c *Client) removeCidFromGroup(ctx context.Context, groupID string, cids []string) error {
 	if groupID == "" || len(cids) == 0 {
 		return fmt.Errorf("group id and at least one cid is required")
 	}
+	if err := checkGroupID(groupID); err != nil {
+		return err
+	}
+
+	unlock, err := c.lockCids(ctx, cids...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	payload := make(map[string][]string)
 	payload["cids"] = cids
 
 	req, err := c.NewRequest(http.MethodDelete, "/groups/{id}/cids").
+		WithContext(ctx).
 		AddPathParam("id", groupID).
 		SetJSONBody(payload)
 	if err != nil {
@@ -166,17 +320,179 @@ func (c *Client) RemoveCidFromGroup(groupID string, cids []string) error {
 
 // RemoveGroup removes the group with the specified ID.
 // If the group ID is empty, an error is returned.
+//
+// Deprecated: use c.Groups().Remove, which takes a GroupID. RemoveGroup will be removed in the
+// next release.
 func (c *Client) RemoveGroup(groupID string) error {
+	return c.removeGroup(context.Background(), groupID)
+}
+
+// removeGroup implements RemoveGroup, using ctx for the request.
+func (c *Client) removeGroup(ctx context.Context, groupID string) error {
 	if groupID == "" {
 		return fmt.Errorf("group id is required")
 	}
+	if err := checkGroupID(groupID); err != nil {
+		return err
+	}
 
 	err := c.NewRequest(http.MethodDelete, "/groups/{id}").
+		WithContext(ctx).
 		AddPathParam("id", groupID).
 		Send(nil)
+	c.readCache.purge()
 
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// PinStage identifies a step of PinCidToGroup.
+type PinStage string
+
+const (
+	// PinStageVerifyGroup is the lookup of the group, before anything is pinned.
+	PinStageVerifyGroup PinStage = "verify_group"
+	// PinStagePin is the pin by CID request.
+	PinStagePin PinStage = "pin"
+	// PinStageWait is the polling of the pin job until the content is pinned.
+	PinStageWait PinStage = "wait"
+	// PinStageMetadata is the update of the metadata of the pinned content.
+	PinStageMetadata PinStage = "metadata"
+)
+
+// PinStageError reports the step of PinCidToGroup that failed.
+// Stage is the step that failed.
+// Job is the pin by CID job, when the pin request was accepted before the failure.
+// Err is the underlying error.
+type PinStageError struct {
+	Stage PinStage
+	Job   *PinByCidResponse
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *PinStageError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PinStageError) Unwrap() error {
+	return e.Err
+}
+
+// PinCidToGroup pins cid by hash into the group groupID in a single call.
+//
+// The group is verified first, and a missing group is reported as an error wrapping
+// ErrGroupNotFound before anything is pinned. When wait is false, metadata is sent with the pin
+// request and the queued job is returned. When wait is true, PinCidToGroup polls pinJobs until
+// the job leaves the queue, failing with ErrPinJobFailed if it ends in a failure status or if the
+// content is then missing from pinList, and then applies metadata to the pinned content; the
+// returned job then has the status "pinned". Waiting is bounded by ctx.
+//
+// Failures are returned as a *PinStageError naming the step that failed, so callers can tell
+// whether the content was pinned.
+//
+// Deprecated: use c.Groups().PinCid, which takes a Cid and a GroupID, so that the compiler catches
+// swapped arguments. PinCidToGroup will be removed in the next release.
+func (c *Client) PinCidToGroup(ctx context.Context, cid, groupID string, metadata *PinataMetadata, wait bool) (*PinByCidResponse, error) {
+	return c.pinCidToGroup(ctx, cid, groupID, metadata, wait)
+}
+
+// pinCidToGroup implements PinCidToGroup.
+func (c *Client) pinCidToGroup(ctx context.Context, cid, groupID string, metadata *PinataMetadata, wait bool) (*PinByCidResponse, error) {
+	if cid == "" || groupID == "" {
+		return nil, fmt.Errorf("cid and group id are required")
+	}
+
+	if _, err := c.getGroup(ctx, groupID); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+		}
+		return nil, &PinStageError{Stage: PinStageVerifyGroup, Err: err}
+	}
+
+	options := &PinByCidOptions{PinataOptions: PinOpts{GroupId: groupID}}
+	if metadata != nil && !wait {
+		options.PinataMetadata = *metadata
+	}
+	job, err := c.pinByCid(ctx, cid, options)
+	if err != nil {
+		return nil, &PinStageError{Stage: PinStagePin, Err: err}
+	}
+	if !wait {
+		return job, nil
+	}
+
+	if err := c.waitForPinJob(ctx, job); err != nil {
+		return job, &PinStageError{Stage: PinStageWait, Job: job, Err: err}
+	}
+	job.Status = "pinned"
+
+	if metadata != nil && (metadata.Name != "" || len(metadata.KeyValues) > 0) {
+		err := c.updateFileMetadata(ctx, cid, &PinMetadataUpdateOptions{Name: metadata.Name, KeyValues: metadata.KeyValues})
+		if err != nil {
+			return job, &PinStageError{Stage: PinStageMetadata, Job: job, Err: err}
+		}
+	}
+	return job, nil
+}
+
+// waitForPinJob polls pinJobs until job is no longer queued, then checks that its content is
+// pinned. Jobs that end in a failure status, or that leave the queue without the content being
+// pinned, are reported as an error wrapping ErrPinJobFailed.
+func (c *Client) waitForPinJob(ctx context.Context, job *PinByCidResponse) error {
+	interval := c.pinJobPollInterval
+	if interval <= 0 {
+		interval = defaultPinJobPollInterval
+	}
+
+	for {
+		response, err := c.pollPinJobs(ctx, &ListPinByCidOptions{IPFSPinHash: job.IpfsHash})
+		if err != nil {
+			return err
+		}
+
+		var current *PinEntry
+		for i := range response.Rows {
+			if response.Rows[i].ID == job.ID {
+				current = &response.Rows[i]
+				break
+			}
+		}
+		if current == nil {
+			// a job also leaves the queue when it is dropped, so only the pin list tells it succeeded
+			pinned, err := c.IsPinned(ctx, job.IpfsHash, nil)
+			if err != nil {
+				return fmt.Errorf("failed to verify the pin of %s: %w", job.IpfsHash, err)
+			}
+			if !pinned {
+				return fmt.Errorf("%w: job %s left the queue but %s is not pinned", ErrPinJobFailed, job.ID, job.IpfsHash)
+			}
+			return nil
+		}
+		job.Status = current.Status
+		if pinJobFailed(current.Status) {
+			return fmt.Errorf("%w: job %s is %s", ErrPinJobFailed, job.ID, current.Status)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return transportError(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// pinJobFailed reports whether status is one of the statuses a pin by CID job fails with.
+func pinJobFailed(status string) bool {
+	switch PinStatus(status) {
+	case PinStatusExpired, PinStatusOverFreeLimit, PinStatusOverMaxSize, PinStatusInvalidObject, PinStatusBadHostNode:
+		return true
+	}
+	return false
+}
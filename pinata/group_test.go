@@ -1,12 +1,17 @@
 package pinata
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
 )
 
 func TestCreateGroup(t *testing.T) {
@@ -25,7 +30,7 @@ func TestCreateGroup(t *testing.T) {
 			require.Equal(t, "test_group", payload["name"])
 
 			w.WriteHeader(http.StatusCreated)
-			w.Write([]byte(`{"id":"group123","name":"test_group"}`))
+			w.Write(fixture(t, "POST /groups"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -34,8 +39,9 @@ func TestCreateGroup(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, group)
-		require.Equal(t, "group123", group.ID)
-		require.Equal(t, "test_group", group.GroupName)
+		require.Equal(t, fixtures.GroupID, group.ID)
+		require.Equal(t, "my-group", group.GroupName)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", group.CreatedAt)
 	})
 
 	t.Run("empty group name", func(t *testing.T) {
@@ -89,22 +95,24 @@ func TestGetGroup(t *testing.T) {
 		auth := &Auth{jwt: "valid_jwt_token"}
 		client := New(auth)
 		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			require.Equal(t, "/groups/group123", r.URL.Path)
+			require.Equal(t, "/groups/"+fixtures.GroupID, r.URL.Path)
 			require.Equal(t, http.MethodGet, r.Method)
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"id":"group123","name":"test_group"}`))
+			w.Write(fixture(t, "GET /groups/{id}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
-		group, err := client.GetGroup("group123")
+		group, err := client.GetGroup(fixtures.GroupID)
 
 		require.NoError(t, err)
 		require.NotNil(t, group)
-		require.Equal(t, "group123", group.ID)
-		require.Equal(t, "test_group", group.GroupName)
+		require.Equal(t, fixtures.GroupID, group.ID)
+		require.Equal(t, "my-group", group.GroupName)
+		require.Equal(t, "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d", group.OwnerID)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", group.UpdatedAt)
 	})
 
 	t.Run("empty group ID", func(t *testing.T) {
@@ -180,7 +188,7 @@ func TestListGroups(t *testing.T) {
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[{"id":"group1","name":"test_group1"},{"id":"group2","name":"test_group2"}]`))
+			w.Write(fixture(t, "GET /groups"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -189,11 +197,10 @@ func TestListGroups(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, groups)
-		require.Len(t, groups, 2)
-		require.Equal(t, "group1", groups[0].ID)
-		require.Equal(t, "test_group1", groups[0].GroupName)
-		require.Equal(t, "group2", groups[1].ID)
-		require.Equal(t, "test_group2", groups[1].GroupName)
+		require.Len(t, groups, 1)
+		require.Equal(t, fixtures.GroupID, groups[0].ID)
+		require.Equal(t, "my-group", groups[0].GroupName)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", groups[0].CreatedAt)
 	})
 
 	t.Run("with query parameters", func(t *testing.T) {
@@ -207,22 +214,22 @@ func TestListGroups(t *testing.T) {
 			require.Equal(t, "5", r.URL.Query().Get("offset"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`[{"id":"group3","name":"test_group3"}]`))
+			w.Write(fixture(t, "GET /groups"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
 		options := &ListGroupsOptions{
-			Limit:  10,
-			Offset: 5,
+			Limit:  Int(10),
+			Offset: Int(5),
 		}
 		groups, err := client.ListGroups(options)
 
 		require.NoError(t, err)
 		require.NotNil(t, groups)
 		require.Len(t, groups, 1)
-		require.Equal(t, "group3", groups[0].ID)
-		require.Equal(t, "test_group3", groups[0].GroupName)
+		require.Equal(t, fixtures.GroupID, groups[0].ID)
+		require.Equal(t, "my-group", groups[0].GroupName)
 	})
 
 	t.Run("empty response", func(t *testing.T) {
@@ -282,7 +289,7 @@ func TestUpdateGroup(t *testing.T) {
 		auth := &Auth{jwt: "valid_jwt_token"}
 		client := New(auth)
 		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			require.Equal(t, "/groups/group123", r.URL.Path)
+			require.Equal(t, "/groups/"+fixtures.GroupID, r.URL.Path)
 			require.Equal(t, http.MethodPut, r.Method)
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
@@ -293,17 +300,17 @@ func TestUpdateGroup(t *testing.T) {
 			require.Equal(t, "new_group_name", payload["name"])
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"id":"group123","name":"new_group_name"}`))
+			w.Write(fixture(t, "PUT /groups/{id}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
-		group, err := client.UpdateGroup("group123", "new_group_name")
+		group, err := client.UpdateGroup(fixtures.GroupID, "new_group_name")
 
 		require.NoError(t, err)
 		require.NotNil(t, group)
-		require.Equal(t, "group123", group.ID)
-		require.Equal(t, "new_group_name", group.GroupName)
+		require.Equal(t, fixtures.GroupID, group.ID)
+		require.Equal(t, "my-group", group.GroupName)
 	})
 
 	t.Run("empty group ID", func(t *testing.T) {
@@ -602,3 +609,176 @@ func TestRemoveGroup(t *testing.T) {
 		require.Contains(t, err.Error(), "Unauthorized")
 	})
 }
+
+// pinGroupServer is a mock API for PinCidToGroup. The pin job stays queued with the given statuses
+// for as many pinJobs polls, then leaves the queue. The content is then listed by pinList, unless
+// the job was dropped.
+type pinGroupServer struct {
+	mu           sync.Mutex
+	groupStatus  int
+	jobStatuses  []string
+	pinPayload   map[string]interface{}
+	metadata     map[string]interface{}
+	polls        int
+	metadataFail bool
+	dropped      bool
+}
+
+func (s *pinGroupServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/groups/"+fixtures.GroupID:
+			if s.groupStatus != 0 {
+				w.WriteHeader(s.groupStatus)
+				w.Write([]byte(`{"error":"group not found"}`))
+				return
+			}
+			w.Write(fixture(t, "GET /groups/{id}"))
+		case r.URL.Path == "/pinning/pinByHash":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&s.pinPayload))
+			w.Write(fixture(t, "POST /pinning/pinByHash"))
+		case r.URL.Path == "/pinning/pinJobs":
+			require.Equal(t, fixtures.Cid, r.URL.Query().Get("ipfs_pin_hash"))
+			s.polls++
+			if s.polls > len(s.jobStatuses) {
+				w.Write([]byte(`{"count":0,"rows":[]}`))
+				return
+			}
+			fmt.Fprintf(w, `{"count":1,"rows":[{"id":%q,"ipfs_pin_hash":%q,"status":%q}]}`, fixtures.JobID, fixtures.Cid, s.jobStatuses[s.polls-1])
+		case r.URL.Path == "/data/pinList":
+			require.Equal(t, fixtures.Cid, r.URL.Query().Get("cid"))
+			if s.dropped {
+				w.Write([]byte(`{"count":0,"rows":[]}`))
+				return
+			}
+			w.Write(fixture(t, "GET /data/pinList"))
+		case r.URL.Path == "/pinning/hashMetadata":
+			if s.metadataFail {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":"metadata unavailable"}`))
+				return
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&s.metadata))
+			w.Write(fixture(t, "PUT /pinning/hashMetadata"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+func TestPinCidToGroup(t *testing.T) {
+	metadata := &PinataMetadata{Name: "test_pin", KeyValues: map[string]interface{}{"env": "prod"}}
+
+	newClient := func(t *testing.T, server *pinGroupServer) *Client {
+		mockServer := httptest.NewServer(server.handler(t))
+		t.Cleanup(mockServer.Close)
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+		client.pinJobPollInterval = time.Millisecond
+		return client
+	}
+
+	t.Run("without waiting", func(t *testing.T) {
+		server := &pinGroupServer{}
+		client := newClient(t, server)
+
+		job, err := client.PinCidToGroup(context.Background(), fixtures.Cid, fixtures.GroupID, metadata, false)
+
+		require.NoError(t, err)
+		require.Equal(t, fixtures.JobID, job.ID)
+		require.Equal(t, "prechecking", job.Status)
+		require.Equal(t, fixtures.GroupID, server.pinPayload["pinataOptions"].(map[string]interface{})["groupId"])
+		require.Equal(t, "test_pin", server.pinPayload["pinataMetadata"].(map[string]interface{})["name"])
+		require.Zero(t, server.polls)
+		require.Nil(t, server.metadata)
+	})
+
+	t.Run("waiting for the job", func(t *testing.T) {
+		server := &pinGroupServer{jobStatuses: []string{"prechecking", "retrieving"}}
+		client := newClient(t, server)
+
+		job, err := client.PinCidToGroup(context.Background(), fixtures.Cid, fixtures.GroupID, metadata, true)
+
+		require.NoError(t, err)
+		require.Equal(t, "pinned", job.Status)
+		require.Equal(t, 3, server.polls)
+		require.Equal(t, fixtures.Cid, server.metadata["ipfsPinHash"])
+		require.Equal(t, "test_pin", server.metadata["name"])
+		require.Equal(t, map[string]interface{}{"env": "prod"}, server.metadata["keyvalues"])
+	})
+
+	t.Run("missing group", func(t *testing.T) {
+		server := &pinGroupServer{groupStatus: http.StatusNotFound}
+		client := newClient(t, server)
+
+		job, err := client.PinCidToGroup(context.Background(), fixtures.Cid, fixtures.GroupID, metadata, true)
+
+		require.Nil(t, job)
+		require.ErrorIs(t, err, ErrGroupNotFound)
+		var stageErr *PinStageError
+		require.ErrorAs(t, err, &stageErr)
+		require.Equal(t, PinStageVerifyGroup, stageErr.Stage)
+		require.Nil(t, server.pinPayload)
+	})
+
+	t.Run("failed job", func(t *testing.T) {
+		server := &pinGroupServer{jobStatuses: []string{"retrieving", "expired"}}
+		client := newClient(t, server)
+
+		job, err := client.PinCidToGroup(context.Background(), fixtures.Cid, fixtures.GroupID, metadata, true)
+
+		require.ErrorIs(t, err, ErrPinJobFailed)
+		var stageErr *PinStageError
+		require.ErrorAs(t, err, &stageErr)
+		require.Equal(t, PinStageWait, stageErr.Stage)
+		require.Equal(t, "expired", job.Status)
+		require.Nil(t, server.metadata)
+	})
+
+	t.Run("job dropped from the queue", func(t *testing.T) {
+		server := &pinGroupServer{jobStatuses: []string{"retrieving"}, dropped: true}
+		client := newClient(t, server)
+
+		job, err := client.PinCidToGroup(context.Background(), fixtures.Cid, fixtures.GroupID, metadata, true)
+
+		require.ErrorIs(t, err, ErrPinJobFailed)
+		require.Contains(t, err.Error(), "not pinned")
+		var stageErr *PinStageError
+		require.ErrorAs(t, err, &stageErr)
+		require.Equal(t, PinStageWait, stageErr.Stage)
+		require.Equal(t, "retrieving", job.Status)
+		require.Nil(t, server.metadata)
+	})
+
+	t.Run("metadata failure after pinning", func(t *testing.T) {
+		server := &pinGroupServer{metadataFail: true}
+		client := newClient(t, server)
+
+		job, err := client.PinCidToGroup(context.Background(), fixtures.Cid, fixtures.GroupID, metadata, true)
+
+		var stageErr *PinStageError
+		require.ErrorAs(t, err, &stageErr)
+		require.Equal(t, PinStageMetadata, stageErr.Stage)
+		require.Equal(t, "pinned", stageErr.Job.Status)
+		require.Equal(t, "pinned", job.Status)
+	})
+
+	t.Run("wait bounded by context", func(t *testing.T) {
+		server := &pinGroupServer{jobStatuses: []string{"retrieving", "retrieving", "retrieving"}}
+		client := newClient(t, server)
+		client.pinJobPollInterval = time.Hour
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := client.PinCidToGroup(ctx, fixtures.Cid, fixtures.GroupID, nil, true)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		var stageErr *PinStageError
+		require.ErrorAs(t, err, &stageErr)
+		require.Equal(t, PinStageWait, stageErr.Stage)
+	})
+}
@@ -0,0 +1,97 @@
+package pinata
+
+import (
+	"context"
+)
+
+// GroupsClient groups the group endpoints, taking group IDs and CIDs as GroupID and Cid so that
+// swapped arguments are caught by the compiler. It is obtained with Client.Groups and shares the
+// transport, credentials, options and hooks of its Client.
+type GroupsClient struct {
+	c *Client
+}
+
+// Groups returns the sub-client for the group endpoints. It is created on first use, and every
+// call returns the same value; it is safe for concurrent use.
+func (c *Client) Groups() *GroupsClient {
+	c.groupsOnce.Do(func() {
+		c.groupsClient = &GroupsClient{c: c}
+	})
+	return c.groupsClient
+}
+
+// Create creates a group named name. The name is validated as in Client.CreateGroup.
+func (g *GroupsClient) Create(name string) (*Group, error) {
+	return g.CreateWithContext(context.Background(), name)
+}
+
+// CreateWithContext is like Create but uses ctx for the requests.
+func (g *GroupsClient) CreateWithContext(ctx context.Context, name string) (*Group, error) {
+	return g.c.createGroup(ctx, name)
+}
+
+// Get returns the group id.
+func (g *GroupsClient) Get(id GroupID) (*Group, error) {
+	return g.GetWithContext(context.Background(), id)
+}
+
+// GetWithContext is like Get but uses ctx for the requests.
+func (g *GroupsClient) GetWithContext(ctx context.Context, id GroupID) (*Group, error) {
+	return g.c.getGroup(ctx, string(id))
+}
+
+// List returns the groups matching options, as Client.ListGroups does.
+func (g *GroupsClient) List(options *ListGroupsOptions) ([]Group, error) {
+	return g.ListWithContext(context.Background(), options)
+}
+
+// ListWithContext is like List but uses ctx for the requests.
+func (g *GroupsClient) ListWithContext(ctx context.Context, options *ListGroupsOptions) ([]Group, error) {
+	return g.c.listGroups(ctx, options)
+}
+
+// Update renames the group id to name. The name is validated as in Client.CreateGroup.
+func (g *GroupsClient) Update(id GroupID, name string) (*Group, error) {
+	return g.UpdateWithContext(context.Background(), id, name)
+}
+
+// UpdateWithContext is like Update but uses ctx for the requests.
+func (g *GroupsClient) UpdateWithContext(ctx context.Context, id GroupID, name string) (*Group, error) {
+	return g.c.updateGroup(ctx, string(id), name)
+}
+
+// AddCids adds cids to the group id.
+func (g *GroupsClient) AddCids(id GroupID, cids []Cid) error {
+	return g.AddCidsWithContext(context.Background(), id, cids)
+}
+
+// AddCidsWithContext is like AddCids but uses ctx for the requests.
+func (g *GroupsClient) AddCidsWithContext(ctx context.Context, id GroupID, cids []Cid) error {
+	return g.c.addCidToGroup(ctx, string(id), cidStrings(cids))
+}
+
+// RemoveCids removes cids from the group id.
+func (g *GroupsClient) RemoveCids(id GroupID, cids []Cid) error {
+	return g.RemoveCidsWithContext(context.Background(), id, cids)
+}
+
+// RemoveCidsWithContext is like RemoveCids but uses ctx for the requests.
+func (g *GroupsClient) RemoveCidsWithContext(ctx context.Context, id GroupID, cids []Cid) error {
+	return g.c.removeCidFromGroup(ctx, string(id), cidStrings(cids))
+}
+
+// Remove removes the group id. The content of the group stays pinned.
+func (g *GroupsClient) Remove(id GroupID) error {
+	return g.RemoveWithContext(context.Background(), id)
+}
+
+// RemoveWithContext is like Remove but uses ctx for the requests.
+func (g *GroupsClient) RemoveWithContext(ctx context.Context, id GroupID) error {
+	return g.c.removeGroup(ctx, string(id))
+}
+
+// PinCid pins cid by hash into the group id in a single call, as documented on
+// Client.PinCidToGroup.
+func (g *GroupsClient) PinCid(ctx context.Context, cid Cid, id GroupID, metadata *PinataMetadata, wait bool) (*PinByCidResponse, error) {
+	return g.c.pinCidToGroup(ctx, string(cid), string(id), metadata, wait)
+}
@@ -0,0 +1,101 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+func TestGroupsClient(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/groups":
+			if r.Method == http.MethodGet {
+				w.Write(fixture(t, "GET /groups"))
+				return
+			}
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			require.Equal(t, "my-group", payload["name"])
+			w.Write(fixture(t, "POST /groups"))
+		case "/groups/" + fixtures.GroupID + "/cids":
+			var payload map[string][]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			require.Equal(t, []string{cidV0, cidV1}, payload["cids"])
+			w.Write(fixture(t, r.Method+" /groups/{id}/cids"))
+		default:
+			w.Write(fixture(t, r.Method+" /groups/{id}"))
+		}
+	}))
+	defer mockServer.Close()
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+	groups := client.Groups()
+
+	require.Same(t, groups, client.Groups())
+
+	group, err := groups.Create("  my-group  ")
+	require.NoError(t, err)
+	require.Equal(t, fixtures.GroupID, group.ID)
+
+	group, err = groups.Get(fixtures.GroupID)
+	require.NoError(t, err)
+	require.Equal(t, "my-group", group.GroupName)
+
+	list, err := groups.List(nil)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	_, err = groups.Update(fixtures.GroupID, "my-group")
+	require.NoError(t, err)
+	require.NoError(t, groups.AddCids(fixtures.GroupID, []Cid{cidV0, cidV1}))
+	require.NoError(t, groups.RemoveCids(fixtures.GroupID, []Cid{cidV0, cidV1}))
+	require.NoError(t, groups.Remove(fixtures.GroupID))
+
+	require.Equal(t, []string{
+		"POST /groups",
+		"GET /groups/" + fixtures.GroupID,
+		"GET /groups",
+		"PUT /groups/" + fixtures.GroupID,
+		"PUT /groups/" + fixtures.GroupID + "/cids",
+		"DELETE /groups/" + fixtures.GroupID + "/cids",
+		"DELETE /groups/" + fixtures.GroupID,
+	}, requests)
+}
+
+func TestGroupsClientValidation(t *testing.T) {
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	groups := client.Groups()
+
+	_, err := groups.Create("")
+	require.Error(t, err)
+	_, err = groups.Update("group123", "   ")
+	require.ErrorIs(t, err, ErrInvalidName)
+	require.ErrorContains(t, groups.AddCids("group123", nil), "group id and at least one cid is required")
+	require.ErrorContains(t, groups.RemoveCids("", []Cid{cidV0}), "group id and at least one cid is required")
+	require.ErrorContains(t, groups.Remove(""), "group id is required")
+}
+
+func TestGroupsClientPinCid(t *testing.T) {
+	server := &pinGroupServer{jobStatuses: []string{"retrieving"}}
+	mockServer := httptest.NewServer(server.handler(t))
+	defer mockServer.Close()
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+	client.pinJobPollInterval = time.Millisecond
+
+	job, err := client.Groups().PinCid(context.Background(), fixtures.Cid, fixtures.GroupID, &PinataMetadata{Name: "test_pin"}, true)
+
+	require.NoError(t, err)
+	require.Equal(t, "pinned", job.Status)
+	require.Equal(t, fixtures.GroupID, server.pinPayload["pinataOptions"].(map[string]interface{})["groupId"])
+	require.Equal(t, "test_pin", server.metadata["name"])
+}
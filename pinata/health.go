@@ -0,0 +1,86 @@
+package pinata
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultAuthHealthTTL is how long AuthHealthCheck reuses a successful probe when no TTL is set
+// with WithAuthHealthTTL.
+const DefaultAuthHealthTTL = 30 * time.Second
+
+// AuthHealth is the result of AuthHealthCheck.
+// Healthy reports whether the client's credentials were accepted by the last probe.
+// LastChecked is the time of the last probe.
+// LastError is the error of the last probe, or nil when it succeeded.
+// Cached reports whether the status was served from the cache instead of a new probe.
+type AuthHealth struct {
+	Healthy     bool
+	LastChecked time.Time
+	LastError   error
+	Cached      bool
+}
+
+// authHealthCache memoizes the last successful AuthHealthCheck probe. mu serializes probes, so
+// concurrent health checks share a single request. stale is set by any request that observes a
+// 401, and is kept outside of mu so that requests never wait for a running probe.
+type authHealthCache struct {
+	mu     sync.Mutex
+	status AuthHealth
+	stale  atomic.Bool
+}
+
+// WithAuthHealthTTL sets how long AuthHealthCheck reuses a successful probe
+// (DefaultAuthHealthTTL when zero).
+func WithAuthHealthTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.healthTTL = ttl
+	}
+}
+
+// AuthHealthCheck reports whether the client can authenticate with the Pinata API, for readiness
+// probes that would otherwise call TestAuthentication on every check. A successful
+// TestAuthentication is memoized for the TTL set with WithAuthHealthTTL; the API is only probed
+// again once the TTL expired or after any request of the client was rejected with 401. Failed
+// probes are not memoized, so an unhealthy client is probed on every call.
+//
+// The name differs from HealthCheck, which probes the configured IPFS gateways.
+func (c *Client) AuthHealthCheck(ctx context.Context) AuthHealth {
+	ttl := c.healthTTL
+	if ttl <= 0 {
+		ttl = DefaultAuthHealthTTL
+	}
+
+	h := &c.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := c.now()
+	if h.status.Healthy && !h.stale.Load() && now.Sub(h.status.LastChecked) < ttl {
+		status := h.status
+		status.Cached = true
+		return status
+	}
+
+	// clear the flag before probing, so that a 401 seen during the probe invalidates its result
+	h.stale.Store(false)
+	_, err := c.testAuthentication(ctx)
+	h.status = AuthHealth{Healthy: err == nil, LastChecked: now, LastError: err}
+	return h.status
+}
+
+// invalidateAuthHealth makes the next AuthHealthCheck probe the API again. It is called when a
+// request is rejected with 401.
+func (c *Client) invalidateAuthHealth() {
+	c.health.stale.Store(true)
+}
+
+// now returns the current time of the client's clock.
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
@@ -0,0 +1,123 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHealthCheck(t *testing.T) {
+	// newHealthServer answers testAuthentication with the status returned by status, counting the
+	// probes, and every other path with 401.
+	newHealthServer := func(probes *int32, status func() int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/data/testAuthentication" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Unauthorized"}`))
+				return
+			}
+			atomic.AddInt32(probes, 1)
+			w.WriteHeader(status())
+			w.Write(fixture(t, "GET /data/testAuthentication"))
+		}))
+	}
+
+	t.Run("caches success for the TTL", func(t *testing.T) {
+		var probes int32
+		server := newHealthServer(&probes, func() int { return http.StatusOK })
+		defer server.Close()
+
+		clk := newFakeClock()
+		client := New(NewAuthWithJWT("test_token"), WithAuthHealthTTL(10*time.Second))
+		client.baseURL = server.URL
+		client.clock = clk
+
+		status := client.AuthHealthCheck(context.Background())
+		require.True(t, status.Healthy)
+		require.False(t, status.Cached)
+		require.NoError(t, status.LastError)
+		require.Equal(t, clk.Now(), status.LastChecked)
+		checked := status.LastChecked
+
+		clk.Advance(9 * time.Second)
+		status = client.AuthHealthCheck(context.Background())
+		require.True(t, status.Healthy)
+		require.True(t, status.Cached)
+		require.Equal(t, checked, status.LastChecked)
+		require.EqualValues(t, 1, atomic.LoadInt32(&probes))
+
+		clk.Advance(time.Second)
+		status = client.AuthHealthCheck(context.Background())
+		require.True(t, status.Healthy)
+		require.False(t, status.Cached)
+		require.Equal(t, clk.Now(), status.LastChecked)
+		require.EqualValues(t, 2, atomic.LoadInt32(&probes))
+	})
+
+	t.Run("default TTL", func(t *testing.T) {
+		var probes int32
+		server := newHealthServer(&probes, func() int { return http.StatusOK })
+		defer server.Close()
+
+		clk := newFakeClock()
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = server.URL
+		client.clock = clk
+
+		client.AuthHealthCheck(context.Background())
+		clk.Advance(DefaultAuthHealthTTL - time.Second)
+		require.True(t, client.AuthHealthCheck(context.Background()).Cached)
+		clk.Advance(time.Second)
+		require.False(t, client.AuthHealthCheck(context.Background()).Cached)
+		require.EqualValues(t, 2, atomic.LoadInt32(&probes))
+	})
+
+	t.Run("401 invalidates the cache", func(t *testing.T) {
+		var probes int32
+		status := int32(http.StatusOK)
+		server := newHealthServer(&probes, func() int { return int(atomic.LoadInt32(&status)) })
+		defer server.Close()
+
+		clk := newFakeClock()
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = server.URL
+		client.clock = clk
+
+		require.True(t, client.AuthHealthCheck(context.Background()).Healthy)
+
+		_, err := client.ListFiles(nil)
+		require.Error(t, err)
+
+		atomic.StoreInt32(&status, http.StatusUnauthorized)
+		health := client.AuthHealthCheck(context.Background())
+		require.False(t, health.Healthy)
+		require.False(t, health.Cached)
+		require.Error(t, health.LastError)
+		require.Contains(t, health.LastError.Error(), "Congratulations")
+		require.EqualValues(t, 2, atomic.LoadInt32(&probes))
+	})
+
+	t.Run("failures are not cached", func(t *testing.T) {
+		var probes int32
+		status := int32(http.StatusUnauthorized)
+		server := newHealthServer(&probes, func() int { return int(atomic.LoadInt32(&status)) })
+		defer server.Close()
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = server.URL
+		client.clock = newFakeClock()
+
+		require.False(t, client.AuthHealthCheck(context.Background()).Healthy)
+
+		atomic.StoreInt32(&status, http.StatusOK)
+		health := client.AuthHealthCheck(context.Background())
+		require.True(t, health.Healthy)
+		require.NoError(t, health.LastError)
+		require.EqualValues(t, 2, atomic.LoadInt32(&probes))
+	})
+}
@@ -0,0 +1,98 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// ipfsAPIClient is the HTTP client of HostNodesFromIPFSAPI. Its timeout bounds the query when ctx
+// has no deadline, as a local node that accepts the connection may never answer.
+var ipfsAPIClient = &http.Client{Timeout: 30 * time.Second}
+
+// kuboIDResponse is the subset of the Kubo /api/v0/id response used to discover host nodes.
+type kuboIDResponse struct {
+	ID        string   `json:"ID"`
+	Addresses []string `json:"Addresses"`
+}
+
+// HostNodesFromIPFSAPI queries the /api/v0/id endpoint of the Kubo RPC API at apiURL (e.g.
+// "http://127.0.0.1:5001") and returns the node's announced swarm addresses that are publicly
+// routable, ready to be used as PinOpts.HostNodes.
+//
+// Loopback, link-local, private, unspecified and other non-global IP addresses are dropped, as are
+// "localhost" DNS names and circuit relay addresses. Every returned address ends with the node's
+// /p2p peer ID. An error is returned when the node announces no public address. The query fails
+// after 30 seconds, or earlier when ctx is done.
+func HostNodesFromIPFSAPI(ctx context.Context, apiURL string) ([]string, error) {
+	if apiURL == "" {
+		return nil, fmt.Errorf("api url is required")
+	}
+
+	// the Kubo RPC API only accepts POST requests
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(apiURL, "/")+"/api/v0/id", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating id request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := ipfsAPIClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying IPFS node: %w", transportError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("IPFS node returned %s", resp.Status)}
+	}
+
+	var id kuboIDResponse
+	if err := json.NewDecoder(resp.Body).Decode(&id); err != nil {
+		return nil, fmt.Errorf("failed to decode id response: %w", readError(err))
+	}
+
+	var nodes []string
+	seen := make(map[string]bool)
+	for _, addr := range id.Addresses {
+		if !isPublicMultiaddr(addr) {
+			continue
+		}
+		if id.ID != "" && !strings.Contains(addr, "/p2p/") {
+			addr += "/p2p/" + id.ID
+		}
+		if !seen[addr] {
+			seen[addr] = true
+			nodes = append(nodes, addr)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("IPFS node %s announces no publicly routable addresses", id.ID)
+	}
+	return nodes, nil
+}
+
+// isPublicMultiaddr reports whether the multiaddr addr starts with a publicly routable IP address
+// or DNS name, and does not go through a circuit relay.
+func isPublicMultiaddr(addr string) bool {
+	parts := strings.Split(strings.TrimPrefix(addr, "/"), "/")
+	if len(parts) < 2 || strings.Contains(addr, "/p2p-circuit") {
+		return false
+	}
+
+	switch parts[0] {
+	case "ip4", "ip6":
+		ip, err := netip.ParseAddr(parts[1])
+		if err != nil {
+			return false
+		}
+		return ip.IsGlobalUnicast() && !ip.IsPrivate()
+	case "dns", "dns4", "dns6", "dnsaddr":
+		host := strings.ToLower(parts[1])
+		return host != "localhost" && !strings.HasSuffix(host, ".localhost") && !strings.HasSuffix(host, ".local")
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostNodesFromIPFSAPI(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/kubo_id.json")
+	require.NoError(t, err)
+
+	t.Run("public addresses from recorded response", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/api/v0/id", r.URL.Path)
+			w.Write(fixture)
+		}))
+		defer mockServer.Close()
+
+		nodes, err := HostNodesFromIPFSAPI(context.Background(), mockServer.URL+"/")
+
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"/ip4/203.0.113.7/tcp/4001/p2p/12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf",
+			"/ip4/203.0.113.7/udp/4001/quic-v1/p2p/12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf",
+			"/ip6/2001:db8:85a3::8a2e:370:7334/tcp/4001/p2p/12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf",
+		}, nodes)
+	})
+
+	t.Run("peer id appended and duplicates removed", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"ID":"12D3KooWPeer","Addresses":["/dns4/node.example.com/tcp/4001","/dns4/localhost/tcp/4001","/dns4/node.example.com/tcp/4001/p2p/12D3KooWPeer"]}`))
+		}))
+		defer mockServer.Close()
+
+		nodes, err := HostNodesFromIPFSAPI(context.Background(), mockServer.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"/dns4/node.example.com/tcp/4001/p2p/12D3KooWPeer"}, nodes)
+	})
+
+	t.Run("no public addresses", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"ID":"12D3KooWPeer","Addresses":["/ip4/127.0.0.1/tcp/4001","/ip6/fe80::1/tcp/4001"]}`))
+		}))
+		defer mockServer.Close()
+
+		nodes, err := HostNodesFromIPFSAPI(context.Background(), mockServer.URL)
+
+		require.Error(t, err)
+		require.Nil(t, nodes)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}))
+		defer mockServer.Close()
+
+		_, err := HostNodesFromIPFSAPI(context.Background(), mockServer.URL)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusMethodNotAllowed, apiErr.StatusCode)
+	})
+
+	t.Run("unresponsive node times out", func(t *testing.T) {
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer mockServer.Close()
+		defer close(release)
+		defer func(client *http.Client) { ipfsAPIClient = client }(ipfsAPIClient)
+		ipfsAPIClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+		_, err := HostNodesFromIPFSAPI(context.Background(), mockServer.URL)
+
+		require.ErrorContains(t, err, "error querying IPFS node")
+	})
+
+	t.Run("empty url", func(t *testing.T) {
+		_, err := HostNodesFromIPFSAPI(context.Background(), "")
+		require.Error(t, err)
+	})
+}
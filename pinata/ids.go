@@ -0,0 +1,84 @@
+package pinata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidGroupID is returned when a string is not usable as a group ID, such as a CID passed
+// where a group ID is expected.
+var ErrInvalidGroupID = errors.New("invalid group id")
+
+// Cid is a content identifier. Its distinct type keeps CIDs and group IDs apart in the signatures
+// of the GroupsClient, so that swapped arguments do not compile. ParseCid validates a string
+// before converting it; a plain conversion, Cid(s), does not.
+type Cid string
+
+// GroupID is the ID of a group. ParseGroupID validates a string before converting it; a plain
+// conversion, GroupID(s), does not.
+type GroupID string
+
+// ParseCid returns s as a Cid, or an error wrapping ErrInvalidCid when s is not a CIDv0 or a base32
+// CIDv1.
+func ParseCid(s string) (Cid, error) {
+	if _, err := CidVersion(s); err != nil {
+		return "", err
+	}
+	return Cid(s), nil
+}
+
+// ParseCids returns the strings as Cids, or the error of the first invalid one.
+func ParseCids(ss ...string) ([]Cid, error) {
+	cids := make([]Cid, len(ss))
+	for i, s := range ss {
+		cid, err := ParseCid(s)
+		if err != nil {
+			return nil, err
+		}
+		cids[i] = cid
+	}
+	return cids, nil
+}
+
+// ParseGroupID returns s as a GroupID, or an error wrapping ErrInvalidGroupID when s is empty,
+// contains whitespace or a slash, or is a CID.
+func ParseGroupID(s string) (GroupID, error) {
+	if s == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidGroupID)
+	}
+	if strings.ContainsAny(s, " \t\r\n/") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidGroupID, s)
+	}
+	if err := checkGroupID(s); err != nil {
+		return "", err
+	}
+	return GroupID(s), nil
+}
+
+// String returns c as a string.
+func (c Cid) String() string {
+	return string(c)
+}
+
+// String returns id as a string.
+func (id GroupID) String() string {
+	return string(id)
+}
+
+// checkGroupID rejects a CID used as a group ID, which the API answers with an unhelpful error.
+func checkGroupID(groupID string) error {
+	if _, err := CidVersion(groupID); err == nil {
+		return fmt.Errorf("%w: %s is a cid, not a group id; check the order of the arguments", ErrInvalidGroupID, groupID)
+	}
+	return nil
+}
+
+// cidStrings returns cids as strings.
+func cidStrings(cids []Cid) []string {
+	ss := make([]string, len(cids))
+	for i, cid := range cids {
+		ss[i] = string(cid)
+	}
+	return ss
+}
@@ -0,0 +1,83 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDs(t *testing.T) {
+	cid, err := ParseCid(cidV1)
+	require.NoError(t, err)
+	require.Equal(t, Cid(cidV1), cid)
+	_, err = ParseCid("group123")
+	require.ErrorIs(t, err, ErrInvalidCid)
+
+	cids, err := ParseCids(cidV0, cidV1)
+	require.NoError(t, err)
+	require.Equal(t, []Cid{cidV0, cidV1}, cids)
+	_, err = ParseCids(cidV0, "")
+	require.ErrorIs(t, err, ErrInvalidCid)
+
+	id, err := ParseGroupID("5b9a1c2e-0d57-4a8b-9a8e-7d1f0a1b2c3d")
+	require.NoError(t, err)
+	require.Equal(t, "5b9a1c2e-0d57-4a8b-9a8e-7d1f0a1b2c3d", id.String())
+	for _, invalid := range []string{"", "a b", "groups/1", cidV0, cidV1} {
+		_, err := ParseGroupID(invalid)
+		require.ErrorIs(t, err, ErrInvalidGroupID, invalid)
+	}
+}
+
+func TestCidAsGroupID(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer mockServer.Close()
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	// the deprecated string methods take their arguments in either order
+	err := client.AddCidToGroup(cidV0, []string{"group123"})
+	require.ErrorIs(t, err, ErrInvalidGroupID)
+	require.ErrorContains(t, err, "check the order of the arguments")
+
+	_, err = client.Groups().Get(GroupID(cidV1))
+	require.ErrorIs(t, err, ErrInvalidGroupID)
+	_, err = client.Groups().PinCid(context.Background(), cidV0, GroupID(cidV0), nil, false)
+	require.ErrorIs(t, err, ErrInvalidGroupID)
+}
+
+func TestGroupStringShims(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/groups/group123/cids" {
+			var payload map[string][]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			require.Equal(t, []string{cidV0}, payload["cids"])
+		}
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	_, err := client.GetGroup("group123")
+	require.NoError(t, err)
+	_, err = client.UpdateGroup("group123", "docs")
+	require.NoError(t, err)
+	require.NoError(t, client.AddCidToGroup("group123", []string{cidV0}))
+	require.NoError(t, client.RemoveCidFromGroup("group123", []string{cidV0}))
+	require.NoError(t, client.RemoveGroup("group123"))
+	require.Equal(t, []string{
+		"GET /groups/group123",
+		"PUT /groups/group123",
+		"PUT /groups/group123/cids",
+		"DELETE /groups/group123/cids",
+		"DELETE /groups/group123",
+	}, requests)
+}
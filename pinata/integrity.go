@@ -0,0 +1,77 @@
+package pinata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrIntegrityCheckFailed is returned when content pinned with PinOptions.VerifyIntegrity does
+// not match the bytes that were uploaded.
+var ErrIntegrityCheckFailed = errors.New("integrity check failed")
+
+// IntegrityError reports an integrity check failure. It matches ErrIntegrityCheckFailed with
+// errors.Is.
+// Cid is the IPFS hash of the pinned content.
+// SentSHA256 is the hex-encoded sha256 digest of the file content written to the upload body.
+// PinnedSHA256 is the hex-encoded sha256 digest of the content served for Cid by the gateway.
+type IntegrityError struct {
+	Cid          string
+	SentSHA256   string
+	PinnedSHA256 string
+}
+
+// Error implements the error interface.
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("%s: %s was uploaded with sha256 %s but is served with sha256 %s",
+		ErrIntegrityCheckFailed, e.Cid, e.SentSHA256, e.PinnedSHA256)
+}
+
+// Unwrap returns the underlying error.
+func (e *IntegrityError) Unwrap() error {
+	return ErrIntegrityCheckFailed
+}
+
+// uploadDigest records the sha256 digest of the file content written to an upload body. Every
+// attempt of the upload overwrites it, so it describes the attempt that was sent last.
+type uploadDigest struct {
+	mu  sync.Mutex
+	sum string
+}
+
+func (d *uploadDigest) set(sum string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sum = sum
+}
+
+func (d *uploadDigest) get() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.sum
+}
+
+// verifyIntegrity downloads cid from the client's gateways and compares the sha256 digest of the
+// content with sent, the digest of the uploaded bytes.
+func (c *Client) verifyIntegrity(ctx context.Context, cid string, sent *uploadDigest) error {
+	content, err := c.Gateway().Get(ctx, cid)
+	if err != nil {
+		return fmt.Errorf("failed to download pinned content for verification: %w", err)
+	}
+	defer content.Body.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, content.Body); err != nil {
+		return fmt.Errorf("failed to download pinned content for verification: %w", readError(err))
+	}
+
+	pinned := hex.EncodeToString(hasher.Sum(nil))
+	if sum := sent.get(); pinned != sum {
+		return &IntegrityError{Cid: cid, SentSHA256: sum, PinnedSHA256: pinned}
+	}
+	return nil
+}
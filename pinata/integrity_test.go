@@ -0,0 +1,114 @@
+package pinata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+// corruptingTransport flips the first occurrence of target in request bodies, as a faulty
+// middlebox would, before passing the request on.
+type corruptingTransport struct {
+	target []byte
+}
+
+func (t *corruptingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if i := bytes.Index(body, t.target); i >= 0 {
+			body[i] ^= 0x20
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.TransferEncoding = nil
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// pinAndServeServer is a mock that pins uploaded files under a fake CID and serves them back
+// through /ipfs/.
+func pinAndServeServer(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	pinned := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/pinning/pinFileToIPFS" {
+			file, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+			pinned[fixtures.Cid] = content
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+			return
+		}
+		require.Equal(t, "/ipfs/"+fixtures.Cid, r.URL.Path)
+		w.Write(pinned[fixtures.Cid])
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVerifyIntegrity(t *testing.T) {
+	path := filepath.Join(writeTree(t, "a.txt"), "a.txt")
+	sum := sha256.Sum256([]byte("content of a.txt"))
+	sent := hex.EncodeToString(sum[:])
+
+	t.Run("matching content", func(t *testing.T) {
+		server := pinAndServeServer(t)
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(server.URL))
+		client.baseURL = server.URL
+
+		response, err := client.PinFile(path, &PinOptions{VerifyIntegrity: true})
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+	})
+
+	t.Run("corrupted in transit", func(t *testing.T) {
+		server := pinAndServeServer(t)
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(server.URL), WithMultipartContentLength(true))
+		client.baseURL = server.URL
+		client.httpClient.Transport = &corruptingTransport{target: []byte("content of a.txt")}
+
+		_, err := client.PinFile(path, &PinOptions{VerifyIntegrity: true})
+
+		require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+		var integrityErr *IntegrityError
+		require.True(t, errors.As(err, &integrityErr))
+		require.Equal(t, fixtures.Cid, integrityErr.Cid)
+		require.Equal(t, sent, integrityErr.SentSHA256)
+		require.NotEqual(t, sent, integrityErr.PinnedSHA256)
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = client.PinOpenFile(f, "", &PinOptions{VerifyIntegrity: true})
+		require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		server := pinAndServeServer(t)
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+		client.httpClient.Transport = &corruptingTransport{target: []byte("content of a.txt")}
+
+		_, err := client.PinFile(path, nil)
+		require.NoError(t, err)
+	})
+}
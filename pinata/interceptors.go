@@ -0,0 +1,55 @@
+package pinata
+
+import (
+	"net/http"
+)
+
+func init() {
+	registerCapability(CapabilityInterceptors)
+}
+
+// WithRequestInterceptor adds an interceptor called with every API request of the client, such as
+// uploads, before it is sent, e.g. to add tracing headers or to audit outgoing calls. Interceptors
+// run in the order they were added, once per call, after the SDK has set the headers of the
+// request, which they may change, and before it is signed; retries of the request keep their
+// changes. They must not read req.Body. An interceptor returning an error aborts the call, which
+// fails with that error without sending anything, and the later interceptors are not called.
+// Requests to IPFS gateways are not intercepted.
+func WithRequestInterceptor(intercept func(req *http.Request) error) ClientOption {
+	return func(c *Client) {
+		c.requestInterceptors = append(c.requestInterceptors, intercept)
+	}
+}
+
+// WithResponseInterceptor adds an interceptor called with the response of every API request of
+// the client, once retries are over and before its status is checked, so that error responses are
+// seen as well. Interceptors run in the order they were added. They may read resp.Body only if
+// they replace it with a reader of the same content. An interceptor returning an error aborts the
+// call, which fails with that error, and the later interceptors are not called.
+func WithResponseInterceptor(intercept func(resp *http.Response) error) ClientOption {
+	return func(c *Client) {
+		c.responseInterceptors = append(c.responseInterceptors, intercept)
+	}
+}
+
+// interceptRequest passes req to the request interceptors of the client, in order, stopping at
+// the first error.
+func (c *Client) interceptRequest(req *http.Request) error {
+	for _, intercept := range c.requestInterceptors {
+		if err := intercept(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interceptResponse passes resp to the response interceptors of the client, in order, stopping
+// at the first error.
+func (c *Client) interceptResponse(resp *http.Response) error {
+	for _, intercept := range c.responseInterceptors {
+		if err := intercept(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package pinata
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptors(t *testing.T) {
+	var traceIDs []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceIDs = append(traceIDs, r.Header.Get("X-Trace-Id"))
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	t.Run("run in registration order", func(t *testing.T) {
+		traceIDs = nil
+		var calls []string
+		client := New(&Auth{jwt: "valid_jwt_token"},
+			WithRequestInterceptor(func(req *http.Request) error {
+				calls = append(calls, "request 1 "+req.Method+" "+req.URL.Path)
+				req.Header.Set("X-Trace-Id", "first")
+				return nil
+			}),
+			WithResponseInterceptor(func(resp *http.Response) error {
+				calls = append(calls, "response 1")
+				return nil
+			}),
+			WithRequestInterceptor(func(req *http.Request) error {
+				calls = append(calls, "request 2 "+req.Header.Get("X-Trace-Id"))
+				req.Header.Set("X-Trace-Id", "second")
+				return nil
+			}),
+			WithResponseInterceptor(func(resp *http.Response) error {
+				calls = append(calls, "response 2")
+				return nil
+			}),
+		)
+		client.baseURL = mockServer.URL
+		client.uploadURL = mockServer.URL
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+		// multipart uploads are intercepted as well
+		_, err = client.PinFile(filepath.Join(writeTree(t, "a.txt"), "a.txt"), nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"request 1 GET /data/testAuthentication", "request 2 first", "response 1", "response 2",
+			"request 1 POST /pinning/pinFileToIPFS", "request 2 first", "response 1", "response 2",
+		}, calls)
+		require.Equal(t, []string{"second", "second"}, traceIDs)
+	})
+
+	t.Run("retries keep the changes of a single call", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`, http.StatusServiceUnavailable)
+		var calls int
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(fastRetries),
+			WithRequestInterceptor(func(req *http.Request) error {
+				calls++
+				req.Header.Set("X-Trace-Id", "retried")
+				return nil
+			}),
+			WithResponseInterceptor(func(resp *http.Response) error {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				return nil
+			}),
+		)
+		client.baseURL = server.URL
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+		require.EqualValues(t, 2, server.requests.Load())
+	})
+
+	t.Run("request interceptor errors abort the call", func(t *testing.T) {
+		traceIDs = nil
+		errDenied := errors.New("denied by policy")
+		var later bool
+		client := New(&Auth{jwt: "valid_jwt_token"},
+			WithRequestInterceptor(func(req *http.Request) error { return errDenied }),
+			WithRequestInterceptor(func(req *http.Request) error {
+				later = true
+				return nil
+			}),
+		)
+		client.baseURL = mockServer.URL
+		client.uploadURL = mockServer.URL
+
+		_, err := client.TestAuthentication()
+		require.ErrorIs(t, err, errDenied)
+		_, err = client.PinFile(filepath.Join(writeTree(t, "a.txt"), "a.txt"), nil)
+		require.ErrorIs(t, err, errDenied)
+		require.False(t, later)
+		require.Empty(t, traceIDs)
+	})
+
+	t.Run("response interceptor errors abort the call", func(t *testing.T) {
+		errRejected := errors.New("rejected response")
+		var later bool
+		client := New(&Auth{jwt: "valid_jwt_token"},
+			WithResponseInterceptor(func(resp *http.Response) error { return errRejected }),
+			WithResponseInterceptor(func(resp *http.Response) error {
+				later = true
+				return nil
+			}),
+		)
+		client.baseURL = mockServer.URL
+
+		_, err := client.TestAuthentication()
+		require.ErrorIs(t, err, errRejected)
+		require.False(t, later)
+	})
+}
@@ -0,0 +1,107 @@
+// Package fixtures holds one response body per endpoint of the Pinata API, so that the tests of
+// package pinata, and the tests of SDK users through package pinatatest, share a single reviewed
+// body per endpoint instead of each inventing its own JSON. They are served by the tests of package
+// pinata and offered to the tests of SDK users by package pinatatest.
+//
+// Every fixture is hand-written after the response documented in the Pinata API reference, with
+// placeholder identifiers and secrets. Adding an endpoint to the SDK means adding its documented
+// response to testdata and listing it in endpoints; an endpoint whose response is not documented
+// cannot have a fixture.
+package fixtures
+
+import (
+	"embed"
+	"net/http"
+	"path"
+	"sort"
+)
+
+// The identifiers found in the fixtures, so that tests can chain requests: the pin of pin_list.json
+// is Cid with the ID PinID, the pin by CID job of pin_by_hash.json and pin_jobs.json is JobID, and
+// the group of group.json and groups.json is GroupID.
+const (
+	Cid     = "QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR"
+	PinID   = "0c58d4e2-3f9a-4b7c-8d1e-2a6f9b3c5e7d"
+	JobID   = "3f2a1b4c-5d6e-4f7a-8b9c-0d1e2f3a4b5c"
+	GroupID = "0191f6a2-5c3e-7b8d-9e4f-1a2b3c4d5e6f"
+)
+
+//go:embed testdata
+var files embed.FS
+
+// endpoints maps each endpoint of the API, as a net/http pattern, to the file of its fixture.
+var endpoints = map[string]string{
+	"GET /data/testAuthentication":         "test_authentication.json",
+	"GET /data/userPinnedDataTotal":        "pinned_data_total.json",
+	"GET /data/pinList":                    "pin_list.json",
+	"POST /pinning/pinFileToIPFS":          "pin_file.json",
+	"POST /pinning/pinJSONToIPFS":          "pin_json.json",
+	"POST /pinning/pinByHash":              "pin_by_hash.json",
+	"GET /pinning/pinJobs":                 "pin_jobs.json",
+	"PUT /pinning/hashMetadata":            "ok.txt",
+	"DELETE /pinning/unpin/{cid}":          "ok.txt",
+	"POST /groups":                         "group.json",
+	"GET /groups":                          "groups.json",
+	"GET /groups/{id}":                     "group.json",
+	"PUT /groups/{id}":                     "group.json",
+	"DELETE /groups/{id}":                  "ok.txt",
+	"PUT /groups/{id}/cids":                "ok.txt",
+	"DELETE /groups/{id}/cids":             "ok.txt",
+	"POST /v3/ipfs/signature/{cid}":        "signature.json",
+	"GET /v3/ipfs/signature/{cid}":         "signature.json",
+	"DELETE /v3/ipfs/signature/{cid}":      "ok.txt",
+	"PUT /v3/ipfs/swap/{cid}":              "swap.json",
+	"GET /v3/ipfs/swap/{cid}":              "swap_history.json",
+	"DELETE /v3/ipfs/swap/{cid}":           "swap_removed.json",
+	"POST /users/generateApiKey":           "generate_key.json",
+	"POST /v3/pinata/keys":                 "generate_key.json",
+	"GET /users/apiKeys":                   "keys.json",
+	"GET /v3/pinata/keys":                  "keys.json",
+	"PUT /users/revokeApiKey":              "revoked.txt",
+	"PUT /v3/pinata/keys/{key}":            "revoked.txt",
+	"POST /v3/vectorize/files/{id}":        "vector_status.json",
+	"DELETE /v3/vectorize/files/{id}":      "vector_status.json",
+	"POST /v3/vectorize/groups/{id}/query": "vector_query.json",
+}
+
+// Endpoints returns the endpoints with a fixture, as net/http patterns such as "GET /groups/{id}",
+// sorted.
+func Endpoints() []string {
+	list := make([]string, 0, len(endpoints))
+	for endpoint := range endpoints {
+		list = append(list, endpoint)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Body returns the fixture of endpoint, given as listed by Endpoints.
+func Body(endpoint string) ([]byte, bool) {
+	file, ok := endpoints[endpoint]
+	if !ok {
+		return nil, false
+	}
+	body, err := files.ReadFile(path.Join("testdata", file))
+	if err != nil {
+		panic(err)
+	}
+	return body, true
+}
+
+// Handler returns a handler answering the requests to each endpoint with its fixture, and the
+// other requests with 404 Not Found. The endpoint of a request is returned by its Handler method.
+func Handler() *http.ServeMux {
+	mux := http.NewServeMux()
+	for endpoint, file := range endpoints {
+		body, _ := Body(endpoint)
+		contentType := "application/json"
+		if path.Ext(file) == ".txt" {
+			contentType = "text/plain; charset=utf-8"
+		}
+		mux.HandleFunc(endpoint, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Write(body)
+		})
+	}
+	return mux
+}
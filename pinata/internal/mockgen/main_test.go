@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpToDate fails when the interfaces of api.go changed without running go generate.
+func TestUpToDate(t *testing.T) {
+	want, err := generate("../../api.go")
+	require.NoError(t, err)
+	got, err := os.ReadFile("../../pinatatest/mock.go")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got), "pinatatest/mock.go is out of date; run go generate ./pinatatest")
+}
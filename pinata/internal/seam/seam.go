@@ -0,0 +1,11 @@
+// Package seam connects package pinata to package pinatatest. It carries the test-only client
+// options that make requests deterministic, so that pinatatest can offer them without adding them
+// to the API of package pinata.
+package seam
+
+import "time"
+
+// WithNameClock is set by package pinata. It returns a pinata.ClientOption making the client take
+// the time in the names it generates, such as the default folder and URL upload names, from now
+// instead of the wall clock.
+var WithNameClock func(now func() time.Time) interface{}
@@ -0,0 +1,78 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// JSONPinItem is a document of a PinJSONsAsync batch.
+// Data is the JSON-serializable value to pin, as for PinJSON.
+// Options are the pin options of the document; nil pins it without metadata.
+type JSONPinItem struct {
+	Data    interface{}
+	Options *PinOptions
+}
+
+// JSONBatchOptions represents the options of PinJSONsAsync.
+// Concurrency is the number of concurrent uploads (the client concurrency when zero, see
+// WithConcurrency).
+// FailFast stops scheduling new uploads after the first failure, which is then returned as the
+// error of the batch; otherwise every document is pinned and failures are only reported through
+// the per-item Err fields.
+// PerJobTimeout bounds each upload on its own; zero means no limit.
+type JSONBatchOptions struct {
+	Concurrency   int
+	FailFast      bool
+	PerJobTimeout time.Duration
+}
+
+// JSONPinResult represents the outcome of pinning a single document of a PinJSONsAsync batch.
+// Index is the position of the document in the batch.
+// Name is the metadata name the document was pinned with.
+// Response is the pin response, or nil if the pin failed.
+// Err is the error that occurred while pinning the document, if any.
+type JSONPinResult struct {
+	Index    int
+	Name     string
+	Response *PinResponse
+	Err      error
+}
+
+// PinJSONsAsync pins each of the given documents as its own pin, as PinJSON does, using a bounded
+// worker pool. The options of every item are copied before the uploads start, so items may share
+// keyvalues maps and be reused afterwards. Each worker encodes its request bodies into a single
+// reused buffer, so a batch of thousands of small documents allocates no more per document than
+// the requests themselves.
+//
+// The returned slice has one result per item, in input order. When options.FailFast is set, the
+// first failure cancels the remaining uploads, which fail with the context error, and is returned
+// as the error.
+func (c *Client) PinJSONsAsync(ctx context.Context, items []JSONPinItem, options *JSONBatchOptions) ([]JSONPinResult, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("at least one item is required")
+	}
+	var opts JSONBatchOptions
+	if options != nil {
+		opts = *options
+	}
+
+	jobs := make([]*batchJob, len(items))
+	for i := range items {
+		jobs[i] = &batchJob{index: i, path: fmt.Sprintf("item %d", i), document: &items[i]}
+		if items[i].Options != nil {
+			jobs[i].options = items[i].Options.Clone()
+		}
+	}
+
+	batch, err := c.runBatch(ctx, jobs, len(jobs), nil, &BatchOptions{
+		Concurrency:   opts.Concurrency,
+		FailFast:      opts.FailFast,
+		PerJobTimeout: opts.PerJobTimeout,
+	})
+	results := make([]JSONPinResult, len(batch))
+	for i, result := range batch {
+		results[i] = JSONPinResult{Index: result.Index, Name: result.Name, Response: result.Response, Err: result.Err}
+	}
+	return results, err
+}
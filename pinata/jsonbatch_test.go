@@ -0,0 +1,155 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinJSONsAsync(t *testing.T) {
+	// countingServer pins every document, answering with its "n" field as the CID, and records the
+	// number of requests and the highest number of requests in flight. Documents whose n is in fail
+	// are rejected.
+	type counters struct{ requests, inFlight, maxInFlight atomic.Int32 }
+	countingServer := func(counts *counters, fail map[int]bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			counts.requests.Add(1)
+			n := counts.inFlight.Add(1)
+			defer counts.inFlight.Add(-1)
+			for {
+				max := counts.maxInFlight.Load()
+				if n <= max || counts.maxInFlight.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+
+			var payload struct {
+				PinataContent  struct{ N int } `json:"pinataContent"`
+				PinataMetadata PinataMetadata  `json:"pinataMetadata"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			require.Equal(t, fmt.Sprintf("doc-%d", payload.PinataContent.N), payload.PinataMetadata.Name)
+			if fail[payload.PinataContent.N] {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"invalid document"}`))
+				return
+			}
+			fmt.Fprintf(w, `{"IpfsHash":"Qm%d","PinSize":10}`, payload.PinataContent.N)
+		}))
+	}
+	documents := func(n int) []JSONPinItem {
+		items := make([]JSONPinItem, n)
+		for i := range items {
+			items[i] = JSONPinItem{
+				Data:    map[string]int{"n": i},
+				Options: &PinOptions{PinataMetadata: PinataMetadata{Name: fmt.Sprintf("doc-%d", i)}},
+			}
+		}
+		return items
+	}
+
+	t.Run("1000 documents within the concurrency bound", func(t *testing.T) {
+		var counts counters
+		mockServer := countingServer(&counts, nil)
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinJSONsAsync(context.Background(), documents(1000), &JSONBatchOptions{Concurrency: 8})
+		require.NoError(t, err)
+		require.Len(t, results, 1000)
+		for i, result := range results {
+			require.NoError(t, result.Err)
+			require.Equal(t, i, result.Index)
+			require.Equal(t, fmt.Sprintf("doc-%d", i), result.Name)
+			require.Equal(t, fmt.Sprintf("Qm%d", i), result.Response.IpfsHash)
+		}
+		require.EqualValues(t, 1000, counts.requests.Load())
+		require.LessOrEqual(t, counts.maxInFlight.Load(), int32(8))
+		require.Greater(t, counts.maxInFlight.Load(), int32(1))
+	})
+
+	t.Run("collect all", func(t *testing.T) {
+		var counts counters
+		mockServer := countingServer(&counts, map[int]bool{3: true, 7: true})
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinJSONsAsync(context.Background(), documents(10), nil)
+		require.NoError(t, err)
+		for i, result := range results {
+			if i == 3 || i == 7 {
+				require.ErrorContains(t, result.Err, "invalid document")
+				require.Nil(t, result.Response)
+			} else {
+				require.NoError(t, result.Err)
+			}
+		}
+		require.EqualValues(t, 10, counts.requests.Load())
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		var counts counters
+		mockServer := countingServer(&counts, map[int]bool{0: true})
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinJSONsAsync(context.Background(), documents(100), &JSONBatchOptions{Concurrency: 1, FailFast: true})
+		require.ErrorContains(t, err, "failed to pin item 0")
+		require.ErrorIs(t, results[99].Err, context.Canceled)
+		require.EqualValues(t, 1, counts.requests.Load())
+	})
+
+	t.Run("options are copied", func(t *testing.T) {
+		var counts counters
+		mockServer := countingServer(&counts, nil)
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		items := documents(2)
+		options := items[0].Options
+		_, err := client.PinJSONsAsync(context.Background(), items, nil)
+		require.NoError(t, err)
+		require.Same(t, options, items[0].Options)
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		_, err := New(&Auth{jwt: "valid_jwt_token"}).PinJSONsAsync(context.Background(), nil, nil)
+		require.ErrorContains(t, err, "at least one item is required")
+	})
+}
+
+func TestPinJSONBufferedBody(t *testing.T) {
+	var bodies []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	data := map[string]string{"html": "<b>&</b>"}
+	_, err := client.PinJSON(data, nil)
+	require.NoError(t, err)
+	_, err = client.PinJSONsAsync(context.Background(), []JSONPinItem{{Data: data}}, nil)
+	require.NoError(t, err)
+
+	// the reused buffer produces the body of PinJSON, byte for byte
+	require.Len(t, bodies, 2)
+	require.Equal(t, bodies[0], bodies[1])
+}
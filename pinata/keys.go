@@ -0,0 +1,167 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+func init() {
+	registerCapability(CapabilityV3Keys)
+}
+
+// KeysClient groups the v3 API key endpoints. It is obtained with Client.Keys and shares the
+// transport, credentials, options and hooks of its Client.
+type KeysClient struct {
+	c *Client
+}
+
+// Keys returns the sub-client for the v3 API key endpoints. It is created on first use, and every
+// call returns the same value; it is safe for concurrent use.
+func (c *Client) Keys() *KeysClient {
+	c.keysOnce.Do(func() {
+		c.keysClient = &KeysClient{c: c}
+	})
+	return c.keysClient
+}
+
+// Generate generates a new API key for the Pinata platform.
+//
+// The provided GenerateApiKeyOptions struct specifies the options for the new API key, such as the name, permissions, and expiration.
+// If the options are nil, an error will be returned.
+//
+// The function returns a Secret struct containing the new API key and secret.
+// If there is an error generating the API key, an error will be returned.
+func (k *KeysClient) Generate(options *GenerateApiKeyOptions) (*ApiKeySecret, error) {
+	return k.GenerateWithContext(context.Background(), options)
+}
+
+// GenerateWithContext is like Generate but uses ctx for the requests.
+func (k *KeysClient) GenerateWithContext(ctx context.Context, options *GenerateApiKeyOptions) (*ApiKeySecret, error) {
+	if options == nil {
+		return nil, fmt.Errorf("options cannot be nil")
+	}
+
+	req, err := k.c.NewRequest(http.MethodPost, "/v3/pinata/keys").WithContext(ctx).
+		SetJSONBody(options)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to set JSON body: %w", err)
+	}
+
+	var response ApiKeySecret
+	err = req.Send(&response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// List returns a list of API keys associated with the current user.
+// The response includes information about each API key, such as whether it is revoked, limited use, or exhausted.
+// The options parameter can be used to filter the results by various criteria.
+func (k *KeysClient) List(options *ListApiKeysOptions) (*ApiKeyResponse, error) {
+	return k.ListWithContext(context.Background(), options)
+}
+
+// ListWithContext is like List but uses ctx for the requests.
+func (k *KeysClient) ListWithContext(ctx context.Context, options *ListApiKeysOptions) (*ApiKeyResponse, error) {
+	req := k.c.NewRequest(http.MethodGet, "/v3/pinata/keys").WithContext(ctx)
+	if options != nil {
+		req.setListApiKeysQueryParams(options)
+	}
+
+	var response ApiKeyResponse
+	err := req.Send(&response)
+	if err != nil {
+		return nil, err
+	}
+
+	k.c.checkKeyUsage(&response)
+	return &response, nil
+}
+
+// Revoke revokes the specified API key.
+// The key parameter is required and must be a valid API key.
+// If the key is successfully revoked, this method returns nil. Otherwise, it returns an error.
+func (k *KeysClient) Revoke(key string) error {
+	return k.RevokeWithContext(context.Background(), key)
+}
+
+// RevokeWithContext is like Revoke but uses ctx for the requests.
+func (k *KeysClient) RevokeWithContext(ctx context.Context, key string) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	err := k.c.NewRequest(http.MethodPut, "/v3/pinata/keys/{key}").WithContext(ctx).
+		AddPathParam("key", key).
+		Send(nil)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListPage returns a single page of API keys. The count reported by the keys endpoint is
+// the size of the page rather than a total, so HasMore uses the full-page heuristic.
+func (k *KeysClient) ListPage(options *ListApiKeysOptions) (*Page[ApiKey], error) {
+	return k.ListPageWithContext(context.Background(), options)
+}
+
+// ListPageWithContext is like ListPage but uses ctx for the requests.
+func (k *KeysClient) ListPageWithContext(ctx context.Context, options *ListApiKeysOptions) (*Page[ApiKey], error) {
+	response, err := k.ListWithContext(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if options != nil {
+		offset = intValue(options.Offset)
+	}
+	return newPage(response.Keys, offset, apiKeysPageSize, -1), nil
+}
+
+// WithKeyUsageWarning makes the client emit WarningKeyUsesLow when the API key it authenticates
+// with is a limited-use key with threshold or fewer uses left. The API does not report usage on
+// every response, so the check runs whenever keys are listed with ListApiKeys, ListApiKeyV3 or
+// Keys().List and the client's key is among them. The key is identified by the API key
+// credentials, or by the scopedKeyKey claim of a JWT. A threshold of zero or less disables the
+// warning.
+func WithKeyUsageWarning(threshold int) ClientOption {
+	return func(c *Client) {
+		c.keyUsesThreshold = threshold
+	}
+}
+
+// checkKeyUsage emits WarningKeyUsesLow, recording it on response, when response lists the
+// client's own key with no more uses left than the configured threshold.
+func (c *Client) checkKeyUsage(response *ApiKeyResponse) {
+	if c.keyUsesThreshold <= 0 {
+		return
+	}
+	current := c.currentAuth().keyID()
+	if current == "" {
+		return
+	}
+	for _, key := range response.Keys {
+		if key.Key != current {
+			continue
+		}
+		remaining := key.RemainingUses()
+		if remaining < 0 || remaining > c.keyUsesThreshold {
+			return
+		}
+		w := Warning{
+			Code:    WarningKeyUsesLow,
+			Message: fmt.Sprintf("API key has %d of %d uses left", remaining, key.MaxUses),
+			Detail:  map[string]interface{}{"key": key.Key, "remaining": remaining, "maxUses": key.MaxUses},
+		}
+		response.Warnings = append(response.Warnings, w)
+		c.warn(w)
+		return
+	}
+}
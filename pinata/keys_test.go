@@ -0,0 +1,137 @@
+package pinata
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubClients(t *testing.T) {
+	t.Run("initialized once under concurrent use", func(t *testing.T) {
+		client := New(NewAuthWithJWT("test_token"))
+
+		var wg sync.WaitGroup
+		keys := make([]*KeysClient, 20)
+		gateways := make([]*GatewayClient, 20)
+		for i := range keys {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				keys[i] = client.Keys()
+				gateways[i] = client.Gateway()
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range keys {
+			require.Same(t, client.Keys(), keys[i])
+			require.Same(t, client.Gateway(), gateways[i])
+		}
+	})
+
+	t.Run("keys share the client", func(t *testing.T) {
+		var paths []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer test_token", r.Header.Get("Authorization"))
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			switch r.Method {
+			case http.MethodPost:
+				w.Write(fixture(t, "POST /v3/pinata/keys"))
+			case http.MethodGet:
+				w.Write(fixture(t, "GET /v3/pinata/keys"))
+			}
+		}))
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = mockServer.URL
+		keys := client.Keys()
+
+		generated, err := keys.Generate(&GenerateApiKeyOptions{KeyName: "sub"})
+		require.NoError(t, err)
+		require.Equal(t, "0a1b2c3d4e5f6a7b8c9d", generated.PinataApiKey)
+		require.NotEmpty(t, generated.JWT)
+
+		page, err := keys.ListPage(&ListApiKeysOptions{Offset: Int(10)})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		require.Equal(t, 10, page.Offset)
+
+		require.NoError(t, keys.Revoke("key"))
+
+		require.Equal(t, []string{"POST /v3/pinata/keys", "GET /v3/pinata/keys", "PUT /v3/pinata/keys/key"}, paths)
+	})
+
+	t.Run("gateway shares the client", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/ipfs/QmTest", r.URL.Path)
+			w.Write([]byte("content"))
+		}))
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("test_token"), WithGateways(mockServer.URL))
+
+		content, err := client.Gateway().Get(context.Background(), "QmTest")
+		require.NoError(t, err)
+		defer content.Body.Close()
+		require.Equal(t, mockServer.URL, content.Gateway)
+
+		stat, err := client.Gateway().Stat(context.Background(), "QmTest")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, stat.StatusCode)
+	})
+}
+
+func TestKeyUsageWarning(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[{"key":"other","max_uses":5,"uses":5},{"key":"mine","max_uses":10,"uses":8},{"key":"unlimited","uses":100}]}`))
+	}))
+	defer mockServer.Close()
+
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"scopedKeyKey":"mine"}`))
+	jwt := "eyJhbGciOiJIUzI1NiJ9." + claims + ".signature"
+
+	tests := []struct {
+		name      string
+		auth      *Auth
+		threshold int
+		warned    bool
+	}{
+		{"api key at threshold", NewAuth("mine", "secret", ""), 2, true},
+		{"jwt below threshold", NewAuthWithJWT(jwt), 5, true},
+		{"above threshold", NewAuth("mine", "secret", ""), 1, false},
+		{"unlimited key", NewAuth("unlimited", "secret", ""), 5, false},
+		{"key not listed", NewAuth("missing", "secret", ""), 5, false},
+		{"opaque jwt", NewAuthWithJWT("opaque"), 5, false},
+		{"disabled", NewAuth("mine", "secret", ""), 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := &warningRecorder{}
+			client := New(tc.auth, WithKeyUsageWarning(tc.threshold), WithWarningHandler(recorder.handle))
+			client.baseURL = mockServer.URL
+
+			v2, err := client.ListApiKeys()
+			require.NoError(t, err)
+			v3, err := client.Keys().List(nil)
+			require.NoError(t, err)
+
+			if !tc.warned {
+				require.Empty(t, v2.Warnings)
+				require.Empty(t, v3.Warnings)
+				require.Empty(t, recorder.codes())
+				return
+			}
+			require.Len(t, v2.Warnings, 1)
+			require.Len(t, v3.Warnings, 1)
+			require.Equal(t, []WarningCode{WarningKeyUsesLow, WarningKeyUsesLow}, recorder.codes())
+			require.Equal(t, 2, v2.Warnings[0].Detail["remaining"])
+			require.Equal(t, "mine", v2.Warnings[0].Detail["key"])
+		})
+	}
+}
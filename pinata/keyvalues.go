@@ -0,0 +1,186 @@
+package pinata
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// ValueKind is the JSON type of a Value.
+type ValueKind int
+
+const (
+	// ValueNull is the kind of JSON null and of missing values.
+	ValueNull ValueKind = iota
+	// ValueString is the kind of JSON strings.
+	ValueString
+	// ValueNumber is the kind of JSON numbers.
+	ValueNumber
+	// ValueBool is the kind of JSON booleans.
+	ValueBool
+	// ValueObject is the kind of JSON objects.
+	ValueObject
+	// ValueArray is the kind of JSON arrays.
+	ValueArray
+)
+
+// String returns the JSON name of the kind, e.g. "string".
+func (k ValueKind) String() string {
+	switch k {
+	case ValueString:
+		return "string"
+	case ValueNumber:
+		return "number"
+	case ValueBool:
+		return "boolean"
+	case ValueObject:
+		return "object"
+	case ValueArray:
+		return "array"
+	}
+	return "null"
+}
+
+// Value is a decoded keyvalues value of any JSON type. Pinata returns keyvalues with the type they
+// were stored with, so the same key can hold a string on one pin and a number on another. The
+// accessors of Value never panic: they report whether the value could be converted instead.
+type Value struct {
+	raw interface{}
+}
+
+// NewValue returns the Value of v, a value decoded from JSON.
+func NewValue(v interface{}) Value {
+	return Value{raw: v}
+}
+
+// Kind returns the JSON type of the value as it was returned by the API.
+func (v Value) Kind() ValueKind {
+	switch v.raw.(type) {
+	case nil:
+		return ValueNull
+	case string:
+		return ValueString
+	case float64, float32, int, int64, int32, uint, uint64, uint32, json.Number:
+		return ValueNumber
+	case bool:
+		return ValueBool
+	case map[string]interface{}:
+		return ValueObject
+	case []interface{}:
+		return ValueArray
+	}
+	return ValueNull
+}
+
+// Raw returns the value as decoded, e.g. a float64 for a number or a map[string]interface{} for
+// an object.
+func (v Value) Raw() interface{} {
+	return v.raw
+}
+
+// AsString returns the value as a string. Strings are returned as is, and numbers and booleans
+// in their JSON form, e.g. "42" or "true". Null, objects and arrays are not converted.
+func (v Value) AsString() (string, bool) {
+	switch raw := v.raw.(type) {
+	case string:
+		return raw, true
+	case bool:
+		return strconv.FormatBool(raw), true
+	case json.Number:
+		return raw.String(), true
+	}
+	if f, ok := v.number(); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// AsFloat returns the value as a float64. Numbers and strings holding a number, e.g. "3.5", are
+// converted.
+func (v Value) AsFloat() (float64, bool) {
+	if raw, ok := v.raw.(string); ok {
+		f, err := strconv.ParseFloat(raw, 64)
+		return f, err == nil
+	}
+	return v.number()
+}
+
+// AsBool returns the value as a bool. Booleans and the strings "true" and "false" (in any form
+// accepted by strconv.ParseBool) are converted.
+func (v Value) AsBool() (bool, bool) {
+	switch raw := v.raw.(type) {
+	case bool:
+		return raw, true
+	case string:
+		b, err := strconv.ParseBool(raw)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// AsTime returns the value as a time. Strings in RFC 3339 format or holding a date such as
+// "2024-05-01" are parsed, and numbers are read as Unix timestamps in seconds.
+func (v Value) AsTime() (time.Time, bool) {
+	if raw, ok := v.raw.(string); ok {
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+	if f, ok := v.number(); ok {
+		seconds := int64(f)
+		return time.Unix(seconds, int64((f-float64(seconds))*float64(time.Second))).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// number returns the value as a float64 when it is a JSON number.
+func (v Value) number() (float64, bool) {
+	switch raw := v.raw.(type) {
+	case float64:
+		return raw, true
+	case float32:
+		return float64(raw), true
+	case int:
+		return float64(raw), true
+	case int64:
+		return float64(raw), true
+	case int32:
+		return float64(raw), true
+	case uint:
+		return float64(raw), true
+	case uint64:
+		return float64(raw), true
+	case uint32:
+		return float64(raw), true
+	case json.Number:
+		f, err := raw.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// KeyValues returns the keyvalues of the pin's metadata as Values. Keyvalues returned as a
+// JSON-encoded string are decoded. It returns nil when the pin has no keyvalues.
+func (p Pin) KeyValues() map[string]Value {
+	var keyValues map[string]interface{}
+	switch raw := p.Metadata["keyvalues"].(type) {
+	case map[string]interface{}:
+		keyValues = raw
+	case string:
+		if err := json.Unmarshal([]byte(raw), &keyValues); err != nil {
+			return nil
+		}
+	}
+	if len(keyValues) == 0 {
+		return nil
+	}
+
+	values := make(map[string]Value, len(keyValues))
+	for k, v := range keyValues {
+		values[k] = NewValue(v)
+	}
+	return values
+}
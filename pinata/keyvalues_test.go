@@ -0,0 +1,165 @@
+package pinata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mixedKeyValuesResponse is a recorded pinList response whose keyvalues were stored with
+// different JSON types.
+const mixedKeyValuesResponse = `{
+	"count": 2,
+	"rows": [
+		{
+			"id": "8a6f3c3e-6f5b-4b7a-9c55-0d8a1c1f4e21",
+			"ipfs_pin_hash": "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi",
+			"size": 1024,
+			"date_pinned": "2024-05-01T12:00:00.000Z",
+			"metadata": {
+				"name": "report.pdf",
+				"keyvalues": {
+					"owner": "alice",
+					"version": 3,
+					"ratio": 0.75,
+					"public": true,
+					"reviewed": "false",
+					"published": "2024-05-01T12:00:00Z",
+					"expires": 1717243200,
+					"tags": ["a", "b"],
+					"extra": {"nested": 1},
+					"deleted": null
+				}
+			}
+		},
+		{
+			"id": "1f0d2e5b-2c4e-4f0e-8d8b-7c2f9b1d3a40",
+			"ipfs_pin_hash": "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			"size": 2048,
+			"date_pinned": "2024-05-02T12:00:00.000Z",
+			"metadata": {"name": "empty", "keyvalues": null}
+		}
+	]
+}`
+
+func TestPinKeyValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(mixedKeyValuesResponse))
+	}))
+	defer server.Close()
+
+	client := New(NewAuthWithJWT("test_token"))
+	client.baseURL = server.URL
+	response, err := client.ListFiles(nil)
+	require.NoError(t, err)
+	require.Len(t, response.Rows, 2)
+
+	values := response.Rows[0].KeyValues()
+	require.Len(t, values, 10)
+	require.Nil(t, response.Rows[1].KeyValues())
+
+	t.Run("kinds", func(t *testing.T) {
+		kinds := map[string]ValueKind{
+			"owner":     ValueString,
+			"version":   ValueNumber,
+			"ratio":     ValueNumber,
+			"public":    ValueBool,
+			"reviewed":  ValueString,
+			"published": ValueString,
+			"expires":   ValueNumber,
+			"tags":      ValueArray,
+			"extra":     ValueObject,
+			"deleted":   ValueNull,
+		}
+		for key, kind := range kinds {
+			require.Equal(t, kind, values[key].Kind(), key)
+		}
+		require.Equal(t, ValueNull, values["missing"].Kind())
+	})
+
+	t.Run("strings", func(t *testing.T) {
+		s, ok := values["owner"].AsString()
+		require.True(t, ok)
+		require.Equal(t, "alice", s)
+
+		s, ok = values["version"].AsString()
+		require.True(t, ok)
+		require.Equal(t, "3", s)
+
+		s, ok = values["public"].AsString()
+		require.True(t, ok)
+		require.Equal(t, "true", s)
+
+		for _, key := range []string{"tags", "extra", "deleted", "missing"} {
+			_, ok = values[key].AsString()
+			require.False(t, ok, key)
+		}
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		f, ok := values["ratio"].AsFloat()
+		require.True(t, ok)
+		require.Equal(t, 0.75, f)
+
+		f, ok = NewValue("3.5").AsFloat()
+		require.True(t, ok)
+		require.Equal(t, 3.5, f)
+
+		f, ok = NewValue(json.Number("12")).AsFloat()
+		require.True(t, ok)
+		require.Equal(t, 12.0, f)
+
+		for _, key := range []string{"owner", "public", "extra", "deleted"} {
+			_, ok = values[key].AsFloat()
+			require.False(t, ok, key)
+		}
+	})
+
+	t.Run("booleans", func(t *testing.T) {
+		b, ok := values["public"].AsBool()
+		require.True(t, ok)
+		require.True(t, b)
+
+		b, ok = values["reviewed"].AsBool()
+		require.True(t, ok)
+		require.False(t, b)
+
+		for _, key := range []string{"owner", "version", "tags"} {
+			_, ok = values[key].AsBool()
+			require.False(t, ok, key)
+		}
+	})
+
+	t.Run("times", func(t *testing.T) {
+		ts, ok := values["published"].AsTime()
+		require.True(t, ok)
+		require.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ts)
+
+		ts, ok = values["expires"].AsTime()
+		require.True(t, ok)
+		require.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), ts)
+
+		ts, ok = NewValue("2024-05-01").AsTime()
+		require.True(t, ok)
+		require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), ts)
+
+		for _, key := range []string{"owner", "public", "extra", "deleted"} {
+			_, ok = values[key].AsTime()
+			require.False(t, ok, key)
+		}
+	})
+
+	t.Run("keyvalues encoded as a string", func(t *testing.T) {
+		row := Pin{Metadata: map[string]interface{}{"keyvalues": `{"count": 2}`}}
+
+		values := row.KeyValues()
+		require.Equal(t, ValueNumber, values["count"].Kind())
+
+		row.Metadata["keyvalues"] = "not json"
+		require.Nil(t, row.KeyValues())
+	})
+}
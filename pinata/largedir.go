@@ -0,0 +1,268 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+func init() {
+	registerCapability(CapabilityLargeDirectory)
+}
+
+// Default limits of the parts of PinLargeDirectory.
+const (
+	DefaultMaxFilesPerPart       = 1000
+	DefaultMaxBytesPerPart int64 = 1 << 30
+)
+
+// LargeDirManifestVersion is the version of the manifest format written by PinLargeDirectory.
+const LargeDirManifestVersion = 1
+
+// LargeDirOptions represents the options for pinning a large directory with PinLargeDirectory.
+// MaxFilesPerPart is the maximum number of files of a part; it defaults to DefaultMaxFilesPerPart.
+// MaxBytesPerPart is the maximum total size of the files of a part in bytes; it defaults to
+// DefaultMaxBytesPerPart. A file larger than the limit is pinned as a part of its own.
+// Name is the base name of the pins; it defaults to the base name of the directory. Parts are
+// named Name-part-0001, Name-part-0002 and so on, and the manifest Name-manifest.
+// PinataOptions contains options applied to every pin, such as the CID version.
+type LargeDirOptions struct {
+	MaxFilesPerPart int
+	MaxBytesPerPart int64
+	Name            string
+	PinataOptions   Options
+}
+
+// LargeDirPart is a part of a directory pinned with PinLargeDirectory.
+// Cid is the CID of the part folder. A file with the relative path p in the directory is
+// addressable as /ipfs/Cid/p.
+// Files is the number of files of the part.
+// Bytes is the total size of the files of the part.
+type LargeDirPart struct {
+	Cid   string `json:"cid"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// LargeDirManifest is the manifest of a directory pinned with PinLargeDirectory. It is pinned as
+// JSON, after every part.
+// Version is the version of the manifest format, LargeDirManifestVersion.
+// Name is the base name of the pins.
+// Parts are the parts of the directory, in partition order.
+// Files maps the slash-separated relative path of every file of the directory to the CID of the
+// part holding it.
+type LargeDirManifest struct {
+	Version int               `json:"version"`
+	Name    string            `json:"name"`
+	Parts   []LargeDirPart    `json:"parts"`
+	Files   map[string]string `json:"files"`
+}
+
+// LargeDirResult is the result of PinLargeDirectory.
+// ManifestCid is the CID of the pinned manifest.
+// PartCids are the CIDs of the parts, in partition order.
+// Manifest is the pinned manifest.
+type LargeDirResult struct {
+	ManifestCid string
+	PartCids    []string
+	Manifest    *LargeDirManifest
+}
+
+// largeDirFile is a regular file of a large directory.
+type largeDirFile struct {
+	rel  string
+	path string
+	size int64
+}
+
+// largeDirNode is a directory of a large directory, with its entries in name order. An entry is
+// either a file or a subdirectory.
+type largeDirNode struct {
+	entries []largeDirEntry
+	files   int
+	bytes   int64
+}
+
+type largeDirEntry struct {
+	file *largeDirFile
+	dir  *largeDirNode
+}
+
+// PinLargeDirectory pins the regular files beneath dir as several folder pins, called parts, so
+// that no request exceeds opts.MaxFilesPerPart files or opts.MaxBytesPerPart bytes, then pins a
+// LargeDirManifest mapping every relative path to the CID of its part, which keeps the content
+// addressable. Every part keeps the relative paths of its files, so a file is found at the same
+// path under the CID of its part as under dir.
+//
+// Partitioning is deterministic and follows the directory tree: a subdirectory within the limits
+// is never split, and the files and subdirectories of a directory are packed into parts in name
+// order. Re-running on an unchanged tree produces the same parts, and a change only alters the
+// part holding the changed file and the later parts packed from the same directory. Symbolic links
+// and other non-regular files are skipped, each reported as a WarningFileSkipped.
+//
+// Parts are pinned one at a time; the first failure stops the call.
+func (c *Client) PinLargeDirectory(ctx context.Context, dir string, opts LargeDirOptions) (*LargeDirResult, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+	if opts.MaxFilesPerPart <= 0 {
+		opts.MaxFilesPerPart = DefaultMaxFilesPerPart
+	}
+	if opts.MaxBytesPerPart <= 0 {
+		opts.MaxBytesPerPart = DefaultMaxBytesPerPart
+	}
+	if opts.Name == "" {
+		opts.Name = filepath.Base(filepath.Clean(dir))
+	}
+
+	root, err := c.readLargeDir(dir, "")
+	if err != nil {
+		return nil, err
+	}
+	if root.files == 0 {
+		return nil, fmt.Errorf("no files found in %s", dir)
+	}
+	parts := partitionLargeDir(root, opts.MaxFilesPerPart, opts.MaxBytesPerPart)
+
+	manifest := &LargeDirManifest{
+		Version: LargeDirManifestVersion,
+		Name:    opts.Name,
+		Files:   make(map[string]string, root.files),
+	}
+	result := &LargeDirResult{Manifest: manifest}
+	for i, part := range parts {
+		paths := make([]string, len(part))
+		var bytes int64
+		for j, file := range part {
+			paths[j] = file.path
+			bytes += file.size
+		}
+
+		response, err := c.pinNestedFolders(ctx, dir, paths, &PinOptions{
+			PinataMetadata: PinataMetadata{Name: fmt.Sprintf("%s-part-%04d", opts.Name, i+1)},
+			PinataOptions:  opts.PinataOptions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to pin part %d of %d: %w", i+1, len(parts), err)
+		}
+
+		manifest.Parts = append(manifest.Parts, LargeDirPart{Cid: response.IpfsHash, Files: len(part), Bytes: bytes})
+		for _, file := range part {
+			manifest.Files[file.rel] = response.IpfsHash
+		}
+		result.PartCids = append(result.PartCids, response.IpfsHash)
+	}
+
+	response, err := c.pinJSON(ctx, manifest, &PinOptions{
+		PinataMetadata: PinataMetadata{Name: opts.Name + "-manifest"},
+		PinataOptions:  opts.PinataOptions,
+		CanonicalJSON:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin manifest: %w", err)
+	}
+	result.ManifestCid = response.IpfsHash
+	return result, nil
+}
+
+// readLargeDir reads the tree of the directory at dir, whose slash-separated path relative to the
+// root of the large directory is rel.
+func (c *Client) readLargeDir(dir, rel string) (*largeDirNode, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	node := &largeDirNode{}
+	for _, entry := range entries {
+		p := filepath.Join(dir, entry.Name())
+		r := path.Join(rel, entry.Name())
+		switch {
+		case entry.IsDir():
+			sub, err := c.readLargeDir(p, r)
+			if err != nil {
+				return nil, err
+			}
+			if sub.files == 0 {
+				continue
+			}
+			node.entries = append(node.entries, largeDirEntry{dir: sub})
+			node.files += sub.files
+			node.bytes += sub.bytes
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat %s: %w", p, err)
+			}
+			node.entries = append(node.entries, largeDirEntry{file: &largeDirFile{rel: r, path: p, size: info.Size()}})
+			node.files++
+			node.bytes += info.Size()
+		default:
+			c.warn(Warning{
+				Code:    WarningFileSkipped,
+				Message: "skipping non-regular file",
+				Detail:  map[string]interface{}{"path": p, "mode": entry.Type().String()},
+			})
+		}
+	}
+	return node, nil
+}
+
+// partitionLargeDir splits the files of node into parts of at most maxFiles files and maxBytes
+// bytes. A node within the limits is a single part. Otherwise its entries are packed into parts
+// in order, a subdirectory within the limits being packed as a whole and a larger one being
+// partitioned on its own.
+func partitionLargeDir(node *largeDirNode, maxFiles int, maxBytes int64) [][]*largeDirFile {
+	if node.files <= maxFiles && node.bytes <= maxBytes {
+		return [][]*largeDirFile{node.collect(nil)}
+	}
+
+	var parts [][]*largeDirFile
+	var current []*largeDirFile
+	var bytes int64
+	flush := func() {
+		if len(current) > 0 {
+			parts = append(parts, current)
+			current, bytes = nil, 0
+		}
+	}
+	for _, entry := range node.entries {
+		files, size := 1, int64(0)
+		if entry.dir != nil {
+			if entry.dir.files > maxFiles || entry.dir.bytes > maxBytes {
+				flush()
+				parts = append(parts, partitionLargeDir(entry.dir, maxFiles, maxBytes)...)
+				continue
+			}
+			files, size = entry.dir.files, entry.dir.bytes
+		} else {
+			size = entry.file.size
+		}
+
+		if len(current)+files > maxFiles || bytes+size > maxBytes {
+			flush()
+		}
+		if entry.dir != nil {
+			current = entry.dir.collect(current)
+		} else {
+			current = append(current, entry.file)
+		}
+		bytes += size
+	}
+	flush()
+	return parts
+}
+
+// collect appends the files of the tree of n to files, in order.
+func (n *largeDirNode) collect(files []*largeDirFile) []*largeDirFile {
+	for _, entry := range n.entries {
+		if entry.dir != nil {
+			files = entry.dir.collect(files)
+		} else {
+			files = append(files, entry.file)
+		}
+	}
+	return files
+}
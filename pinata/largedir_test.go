@@ -0,0 +1,178 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// largeDirServer is a mock pinning server that records the files of each part upload and the
+// manifest pinned as JSON.
+type largeDirServer struct {
+	parts    [][]string
+	names    []string
+	manifest json.RawMessage
+}
+
+func (s *largeDirServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pinning/pinJSONToIPFS" {
+			var payload struct {
+				PinataContent  json.RawMessage `json:"pinataContent"`
+				PinataMetadata PinataMetadata  `json:"pinataMetadata"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			s.manifest = payload.PinataContent
+			s.names = append(s.names, payload.PinataMetadata.Name)
+			w.Write([]byte(`{"IpfsHash":"QmManifest","PinSize":10,"Timestamp":"2023-05-01T12:00:00Z"}`))
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		require.NoError(t, err)
+		var files []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			_, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			require.NoError(t, err)
+			switch params["name"] {
+			case "file":
+				files = append(files, params["filename"])
+			case "pinataMetadata":
+				var metadata PinataMetadata
+				require.NoError(t, json.NewDecoder(part).Decode(&metadata))
+				s.names = append(s.names, metadata.Name)
+			}
+		}
+		s.parts = append(s.parts, files)
+		w.Write([]byte(`{"IpfsHash":"QmPart` + string(rune('0'+len(s.parts))) + `","PinSize":10,"Timestamp":"2023-05-01T12:00:00Z"}`))
+	}
+}
+
+func TestPinLargeDirectory(t *testing.T) {
+	dir := writeTree(t, "a.txt", "b.txt", "docs/1.md", "docs/2.md", "docs/3.md", "img/x.png")
+
+	t.Run("partitions by file count", func(t *testing.T) {
+		recorder := &largeDirServer{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		result, err := client.PinLargeDirectory(context.Background(), dir, LargeDirOptions{MaxFilesPerPart: 2, Name: "site"})
+		require.NoError(t, err)
+
+		require.Equal(t, [][]string{
+			{"site-part-0001/a.txt", "site-part-0001/b.txt"},
+			{"site-part-0002/docs/1.md", "site-part-0002/docs/2.md"},
+			{"site-part-0003/docs/3.md"},
+			{"site-part-0004/img/x.png"},
+		}, recorder.parts)
+		require.Equal(t, []string{"site-part-0001", "site-part-0002", "site-part-0003", "site-part-0004", "site-manifest"}, recorder.names)
+		require.Equal(t, "QmManifest", result.ManifestCid)
+		require.Equal(t, []string{"QmPart1", "QmPart2", "QmPart3", "QmPart4"}, result.PartCids)
+
+		require.Equal(t, &LargeDirManifest{
+			Version: LargeDirManifestVersion,
+			Name:    "site",
+			Parts: []LargeDirPart{
+				{Cid: "QmPart1", Files: 2, Bytes: int64(len("content of a.txt") + len("content of b.txt"))},
+				{Cid: "QmPart2", Files: 2, Bytes: int64(len("content of docs/1.md") + len("content of docs/2.md"))},
+				{Cid: "QmPart3", Files: 1, Bytes: int64(len("content of docs/3.md"))},
+				{Cid: "QmPart4", Files: 1, Bytes: int64(len("content of img/x.png"))},
+			},
+			Files: map[string]string{
+				"a.txt":     "QmPart1",
+				"b.txt":     "QmPart1",
+				"docs/1.md": "QmPart2",
+				"docs/2.md": "QmPart2",
+				"docs/3.md": "QmPart3",
+				"img/x.png": "QmPart4",
+			},
+		}, result.Manifest)
+
+		var pinned LargeDirManifest
+		require.NoError(t, json.Unmarshal(recorder.manifest, &pinned))
+		require.Equal(t, *result.Manifest, pinned)
+	})
+
+	t.Run("partitions by size", func(t *testing.T) {
+		recorder := &largeDirServer{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		// the files are 16 or 20 bytes long: docs fills a part on its own and is kept whole
+		_, err := client.PinLargeDirectory(context.Background(), dir, LargeDirOptions{MaxBytesPerPart: 60, Name: "site"})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"site-part-0001/a.txt", "site-part-0001/b.txt"},
+			{"site-part-0002/docs/1.md", "site-part-0002/docs/2.md", "site-part-0002/docs/3.md"},
+			{"site-part-0003/img/x.png"},
+		}, recorder.parts)
+	})
+
+	t.Run("whole directory in one part", func(t *testing.T) {
+		recorder := &largeDirServer{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		result, err := client.PinLargeDirectory(context.Background(), dir, LargeDirOptions{})
+		require.NoError(t, err)
+		require.Len(t, recorder.parts, 1)
+		require.Len(t, recorder.parts[0], 6)
+		require.Equal(t, filepath.Base(dir)+"-manifest", recorder.names[1])
+		require.Equal(t, []string{"QmPart1"}, result.PartCids)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		_, err := client.PinLargeDirectory(context.Background(), t.TempDir(), LargeDirOptions{})
+		require.ErrorContains(t, err, "no files found")
+	})
+}
+
+func TestPartitionLargeDir(t *testing.T) {
+	partition := func(dir string) []string {
+		root, err := New(&Auth{jwt: "valid_jwt_token"}).readLargeDir(dir, "")
+		require.NoError(t, err)
+		var parts []string
+		for _, part := range partitionLargeDir(root, 3, DefaultMaxBytesPerPart) {
+			rels := make([]string, len(part))
+			for i, file := range part {
+				rels[i] = file.rel
+			}
+			parts = append(parts, strings.Join(rels, ","))
+		}
+		return parts
+	}
+
+	dir := writeTree(t, "a/1", "a/2", "b/1", "b/2", "b/3", "b/4", "c/1", "d")
+	before := partition(dir)
+	require.Equal(t, []string{"a/1,a/2", "b/1,b/2,b/3", "b/4", "c/1,d"}, before)
+	require.Equal(t, before, partition(dir))
+
+	// a change in c leaves the parts of a and b unchanged
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c", "2"), []byte("new"), 0644))
+	require.Equal(t, []string{"a/1,a/2", "b/1,b/2,b/3", "b/4", "c/1,c/2,d"}, partition(dir))
+
+	// c no longer fits with d, which moves to a part of its own
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c", "3"), []byte("new"), 0644))
+	require.Equal(t, []string{"a/1,a/2", "b/1,b/2,b/3", "b/4", "c/1,c/2,c/3", "d"}, partition(dir))
+}
@@ -0,0 +1,183 @@
+package pinata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// ErrDigestMismatch is returned by MirrorURL when the sha256 digest of the origin content
+// does not match the digest expected by the caller.
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// MirrorOptions represents the options for mirroring a URL to Pinata.
+// PinOptions contains the metadata and options used for the pin, including content type checks.
+// ExpectedSHA256 is an optional hex-encoded sha256 digest the origin content must match.
+type MirrorOptions struct {
+	PinOptions     *PinOptions
+	ExpectedSHA256 string
+}
+
+// OriginMetadata describes the response of the origin a URL was mirrored from.
+// StatusCode is the HTTP status code returned by the origin.
+// ContentType is the Content-Type header returned by the origin.
+// ETag is the ETag header returned by the origin, if any.
+type OriginMetadata struct {
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+}
+
+// MirrorResult represents the result of mirroring a URL to Pinata.
+// Cid is the IPFS hash of the pinned content.
+// Bytes is the number of bytes streamed from the origin.
+// SHA256 is the hex-encoded sha256 digest of the streamed content.
+// Origin holds metadata of the origin response.
+// Pin is the raw pin response returned by the Pinata API.
+type MirrorResult struct {
+	Cid    string         `json:"cid"`
+	Bytes  int64          `json:"bytes"`
+	SHA256 string         `json:"sha256"`
+	Origin OriginMetadata `json:"origin"`
+	Pin    *PinResponse   `json:"pin,omitempty"`
+}
+
+// MirrorURL streams the content at url to Pinata while computing its sha256 digest.
+//
+// The origin body is piped directly into the upload, so the content is never fully buffered in memory.
+// If options.ExpectedSHA256 is set and the computed digest differs, the upload is aborted before
+// the multipart body is completed and an error wrapping ErrDigestMismatch is returned; nothing is
+// reported as pinned in that case.
+func (c *Client) MirrorURL(ctx context.Context, url string, options *MirrorOptions) (*MirrorResult, error) {
+	if url == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+
+	var pinOptions *PinOptions
+	var expected string
+	if options != nil {
+		pinOptions = options.PinOptions
+		expected = strings.ToLower(strings.TrimSpace(options.ExpectedSHA256))
+	}
+	pinOptions, err := c.normalizePinOptions(pinOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchCtx, deadline := c.startDownload(ctx)
+	defer deadline.stop()
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating origin request: %w", err)
+	}
+	c.setUserAgent(req)
+
+	resp, err := c.originHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching URL: %w", transportError(deadline.err(fetchCtx, err)))
+	}
+	deadline.fit(resp.ContentLength)
+	origin := deadline.body(fetchCtx, resp.Body)
+	defer origin.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &OriginError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	result := &MirrorResult{
+		Origin: OriginMetadata{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			ETag:        resp.Header.Get("ETag"),
+		},
+	}
+
+	content := io.Reader(origin)
+	if pinOptions != nil && (len(pinOptions.AllowedContentTypes) > 0 || len(pinOptions.DeniedContentTypes) > 0) {
+		content, err = checkContentType(result.Origin.ContentType, origin, pinOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	urlName := c.defaultName("url_upload")
+	if pinOptions != nil && pinOptions.PinataMetadata.Name != "" {
+		urlName = pinOptions.PinataMetadata.Name
+	}
+
+	pr, pw := io.Pipe()
+	writer, err := c.newMultipartWriter(pw)
+	if err != nil {
+		return nil, err
+	}
+	bodyErr := make(chan error, 1)
+
+	go func() {
+		err := writeMirrorBody(writer, content, filepath.Base(url), pinOptions, urlName, expected, result)
+		pw.CloseWithError(err)
+		bodyErr <- err
+	}()
+
+	op := c.startOp("MirrorURL", url)
+	defer c.endOp(op)
+
+	var response PinResponse
+	err = c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").
+		WithContext(ctx).
+		withActiveOp(op).
+		SetBody(pr, writer.FormDataContentType()).
+		Send(&response)
+
+	// unblock the body writer in case the request finished before consuming the whole body
+	pr.Close()
+	if werr := <-bodyErr; werr != nil && !errors.Is(werr, io.ErrClosedPipe) {
+		return nil, werr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Cid = response.IpfsHash
+	result.Pin = &response
+	return result, nil
+}
+
+// writeMirrorBody writes the multipart upload body for MirrorURL, hashing and counting the
+// content as it is streamed. The multipart writer is only closed when the digest check passes,
+// so a mismatch leaves the body incomplete and the upload fails.
+func writeMirrorBody(writer *multipart.Writer, content io.Reader, fileName string, options *PinOptions, name, expected string, result *MirrorResult) error {
+	if options != nil {
+		if err := addMetadataAndOptions(writer, options, name); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return fmt.Errorf("error creating form file: %w", err)
+	}
+
+	hasher := sha256.New()
+	n, err := io.Copy(part, io.TeeReader(content, hasher))
+	if err != nil {
+		return fmt.Errorf("error copying file content: %w", err)
+	}
+
+	result.Bytes = n
+	result.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	if expected != "" && expected != result.SHA256 {
+		return fmt.Errorf("%w: expected %s, got %s", ErrDigestMismatch, expected, result.SHA256)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+	return nil
+}
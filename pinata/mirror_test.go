@@ -0,0 +1,121 @@
+package pinata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+func TestMirrorURL(t *testing.T) {
+	content := []byte("archived external asset")
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+
+	newOrigin := func() *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("ETag", `"abc123"`)
+			w.WriteHeader(http.StatusOK)
+			w.Write(content)
+		}))
+	}
+
+	newPinata := func(pinned *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/pinning/pinFileToIPFS", r.URL.Path)
+			if err := r.ParseMultipartForm(10 << 20); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"incomplete body"}`))
+				return
+			}
+			file, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			defer file.Close()
+			got, err := io.ReadAll(file)
+			require.NoError(t, err)
+			require.Equal(t, content, got)
+
+			atomic.AddInt32(pinned, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+		}))
+	}
+
+	t.Run("successful mirror with digest verification", func(t *testing.T) {
+		origin := newOrigin()
+		defer origin.Close()
+		var pinned int32
+		mockServer := newPinata(&pinned)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		result, err := client.MirrorURL(context.Background(), origin.URL+"/asset.txt", &MirrorOptions{
+			ExpectedSHA256: digest,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, result.Cid)
+		require.Equal(t, int64(len(content)), result.Bytes)
+		require.Equal(t, digest, result.SHA256)
+		require.Equal(t, http.StatusOK, result.Origin.StatusCode)
+		require.Equal(t, "text/plain", result.Origin.ContentType)
+		require.Equal(t, `"abc123"`, result.Origin.ETag)
+		require.Equal(t, int32(1), atomic.LoadInt32(&pinned))
+	})
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		origin := newOrigin()
+		defer origin.Close()
+		var pinned int32
+		mockServer := newPinata(&pinned)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		result, err := client.MirrorURL(context.Background(), origin.URL+"/asset.txt", &MirrorOptions{
+			ExpectedSHA256: "0000000000000000000000000000000000000000000000000000000000000000",
+		})
+
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.ErrorIs(t, err, ErrDigestMismatch)
+		require.Contains(t, err.Error(), digest)
+		require.Equal(t, int32(0), atomic.LoadInt32(&pinned))
+	})
+
+	t.Run("origin error", func(t *testing.T) {
+		origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer origin.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		result, err := client.MirrorURL(context.Background(), origin.URL, nil)
+
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("empty url", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		result, err := client.MirrorURL(context.Background(), "", nil)
+
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "url is required")
+	})
+}
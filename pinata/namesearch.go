@@ -0,0 +1,122 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	registerCapability(CapabilityNameSearch)
+}
+
+// NameSearchOptions represents the options of SearchFilesByName.
+// Filter restricts the pins considered, as for StreamFiles; its PageLimit and PageOffset are
+// managed by the listing. Content of any status is considered unless Filter sets one.
+// NamePrefix keeps the pins whose metadata name starts with it.
+// NameRegexp keeps the pins whose metadata name matches it, with the syntax of package regexp. It
+// is unanchored, like regexp.MatchString; use ^ and $ to match the whole name.
+// CaseInsensitive makes both NamePrefix and NameRegexp ignore case.
+// Buffer is the capacity of the pin channel.
+//
+// NamePrefix, NameRegexp and CaseInsensitive are applied client-side: the API only filters names
+// with Filter.Name, so every page matching the server-side filter is downloaded and the rows are
+// refined as they stream through. Searching a large account with no server-side filter therefore
+// walks its whole pin list.
+type NameSearchOptions struct {
+	Filter          *ListFilesOptions
+	NamePrefix      string
+	NameRegexp      string
+	CaseInsensitive bool
+	Buffer          int
+}
+
+// SearchFilesByName sends the pins whose metadata name matches options on the returned pin channel.
+// Pins must match both NamePrefix and NameRegexp when both are set, and Filter.Name when it is set.
+//
+// To narrow the pages fetched, the name filter of the API is set to the literal part the search
+// starts with when options.Filter leaves it empty: NamePrefix, or the literal prefix of NameRegexp,
+// whichever is longer. The API matches names that contain its filter, so every pin starting with
+// the prefix is still listed. The search is only narrowed this way when it is case-sensitive, since
+// the name filter of the API is not documented to ignore case.
+//
+// A NameRegexp that does not compile is reported on the error channel. Otherwise the channels
+// behave as for StreamFiles.
+func (c *Client) SearchFilesByName(ctx context.Context, options *NameSearchOptions) (<-chan Pin, <-chan error) {
+	var opts NameSearchOptions
+	if options != nil {
+		opts = *options
+	}
+	filter := ListFilesOptions{Status: "all"}
+	if opts.Filter != nil {
+		filter = *opts.Filter.Clone()
+		if filter.Status == "" {
+			filter.Status = "all"
+		}
+	}
+	filter.PageLimit = MaxPinListPageLimit
+	filter.PageOffset = 0
+
+	pins := make(chan Pin, max(opts.Buffer, 0))
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(pins)
+
+		match, serverName, err := newNameMatcher(opts)
+		if err != nil {
+			errs <- err
+			return
+		}
+		if filter.Name == "" {
+			filter.Name = serverName
+		}
+		err = c.streamPins(ctx, filter, pins, func(p Pin) bool {
+			name, _ := p.Metadata["name"].(string)
+			return match(name)
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return pins, errs
+}
+
+// newNameMatcher returns the client-side name match of opts, and the name filter that narrows the
+// listing to a superset of its matches, or "" when there is none.
+func newNameMatcher(opts NameSearchOptions) (match func(name string) bool, serverName string, err error) {
+	prefix := opts.NamePrefix
+	var re *regexp.Regexp
+	if opts.NameRegexp != "" {
+		expr := opts.NameRegexp
+		if opts.CaseInsensitive {
+			expr = "(?i)" + expr
+		}
+		if re, err = regexp.Compile(expr); err != nil {
+			return nil, "", fmt.Errorf("invalid name regexp: %w", err)
+		}
+	}
+
+	if !opts.CaseInsensitive {
+		serverName = prefix
+		if re != nil {
+			if literal, _ := re.LiteralPrefix(); len(literal) > len(serverName) {
+				serverName = literal
+			}
+		}
+	} else {
+		prefix = strings.ToLower(prefix)
+	}
+
+	return func(name string) bool {
+		if opts.CaseInsensitive {
+			if !strings.HasPrefix(strings.ToLower(name), prefix) {
+				return false
+			}
+		} else if !strings.HasPrefix(name, prefix) {
+			return false
+		}
+		return re == nil || re.MatchString(name)
+	}, serverName, nil
+}
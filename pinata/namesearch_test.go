@@ -0,0 +1,109 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// namedPinServer serves total pins named after their index modulo 5: "release-2024-i",
+// "Release-2024-i", "release-2023-i", "nightly-release-2024-i" and "other-i". It applies the
+// metadata[name] filter as a case-sensitive contains match and records the filter of every page.
+func namedPinServer(t *testing.T, total int, names *[]string) *httptest.Server {
+	formats := []string{"release-2024-%d", "Release-2024-%d", "release-2023-%d", "nightly-release-2024-%d", "other-%d"}
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		mu.Lock()
+		*names = append(*names, query.Get("metadata[name]"))
+		mu.Unlock()
+
+		var matching []string
+		for i := 0; i < total; i++ {
+			name := fmt.Sprintf(formats[i%len(formats)], i)
+			if strings.Contains(name, query.Get("metadata[name]")) {
+				matching = append(matching, fmt.Sprintf(`{"ipfs_pin_hash":"Qm%d","metadata":{"name":%q}}`, i, name))
+			}
+		}
+		offset, _ := strconv.Atoi(query.Get("pageOffset"))
+		limit, err := strconv.Atoi(query.Get("pageLimit"))
+		require.NoError(t, err)
+		rows := matching[min(offset, len(matching)):min(offset+limit, len(matching))]
+		w.Write([]byte(`{"rows":[` + strings.Join(rows, ",") + `]}`))
+	}))
+}
+
+func TestSearchFilesByName(t *testing.T) {
+	search := func(t *testing.T, options *NameSearchOptions) (matched []string, names []string, err error) {
+		mockServer := namedPinServer(t, 3000, &names)
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		pins, errs := client.SearchFilesByName(context.Background(), options)
+		for p := range pins {
+			matched = append(matched, p.Metadata["name"].(string))
+		}
+		return matched, names, <-errs
+	}
+
+	t.Run("prefix narrowed server-side", func(t *testing.T) {
+		matched, names, err := search(t, &NameSearchOptions{NamePrefix: "release-2024"})
+		require.NoError(t, err)
+
+		// the server returns the 1200 names containing the prefix over two pages, and the
+		// nightly ones are dropped client-side
+		require.Equal(t, []string{"release-2024", "release-2024"}, names)
+		require.Len(t, matched, 600)
+		for _, name := range matched {
+			require.True(t, strings.HasPrefix(name, "release-2024-"), name)
+		}
+	})
+
+	t.Run("case-insensitive prefix", func(t *testing.T) {
+		matched, names, err := search(t, &NameSearchOptions{NamePrefix: "RELEASE-2024", CaseInsensitive: true})
+		require.NoError(t, err)
+
+		// the API filter is not relied on to ignore case, so every page is listed
+		require.Equal(t, []string{"", "", "", ""}, names)
+		require.Len(t, matched, 1200)
+		require.Contains(t, matched, "release-2024-0")
+		require.Contains(t, matched, "Release-2024-1")
+	})
+
+	t.Run("regexp combined with a server-side name", func(t *testing.T) {
+		matched, names, err := search(t, &NameSearchOptions{
+			Filter:     &ListFilesOptions{Name: "2024"},
+			NameRegexp: `^release-2024-\d*5$`,
+		})
+		require.NoError(t, err)
+
+		// the server-side name set by the caller is kept
+		require.Equal(t, []string{"2024", "2024"}, names)
+		require.Len(t, matched, 300)
+		for _, name := range matched {
+			require.Regexp(t, `^release-2024-\d*5$`, name)
+		}
+	})
+
+	t.Run("regexp literal prefix narrows the listing", func(t *testing.T) {
+		matched, names, err := search(t, &NameSearchOptions{NamePrefix: "nightly", NameRegexp: `nightly-release-2024-1\d\d$`})
+		require.NoError(t, err)
+		require.Equal(t, []string{"nightly-release-2024-1"}, names)
+		require.Len(t, matched, 20)
+	})
+
+	t.Run("invalid regexp", func(t *testing.T) {
+		matched, names, err := search(t, &NameSearchOptions{NameRegexp: "release-("})
+		require.ErrorContains(t, err, "invalid name regexp")
+		require.Empty(t, matched)
+		require.Empty(t, names)
+	})
+}
@@ -0,0 +1,121 @@
+package pinata
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures optional behavior of a Client. Options are applied by New in order.
+type ClientOption func(*Client)
+
+// WithOnUnauthorized registers a hook invoked when a request is rejected with 401 Unauthorized.
+// The hook returns fresh credentials, which replace the client's credentials before the failed
+// request is retried once. Concurrent 401s share a single invocation of the hook, and a request
+// that fails with 401 again after the retry fails normally.
+func WithOnUnauthorized(fn func(ctx context.Context) (*Auth, error)) ClientOption {
+	return func(c *Client) {
+		c.onUnauthorized = fn
+	}
+}
+
+// WithLogger sets the logger used to report noteworthy client behavior. Every Warning, such as a
+// page size clamped to the server caps, is logged at the warn level with its code, its details and
+// the SDK version as sdk_version. Every attempt of an API request is logged at the debug level,
+// once with its method, path, status or error, duration and attempt number, and once with its
+// headers, credentials redacted (see WithLogRedactedHeaders), and the start of its body (see
+// WithLogBodyLimit). Connection traces enabled with WithConnTrace are logged at the debug level.
+// Nothing is logged when no logger is set.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithMultipartBoundary sets a fixed boundary for multipart upload bodies instead of a random
+// one, which makes raw upload bodies reproducible when debugging proxies. The boundary must be
+// 1 to 70 characters long and made of characters allowed by RFC 2046; an invalid boundary makes
+// uploads fail.
+func WithMultipartBoundary(boundary string) ClientOption {
+	return func(c *Client) {
+		c.multipartBoundary = boundary
+	}
+}
+
+// WithMultipartContentLength makes file uploads send a Content-Length header instead of using
+// chunked transfer encoding, for proxies and servers that reject or buffer chunked bodies. The
+// exact body length is computed before sending from the sizes of the files on disk, without
+// reading them; uploads still stream the files. A file whose size changes between the two fails
+// its upload. Uploads of content with no known length, such as MirrorURL, are always chunked.
+func WithMultipartContentLength(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.multipartContentLength = enabled
+	}
+}
+
+// WithTransport replaces the transport of API requests, e.g. with a transport that records or
+// stubs requests in tests. The connection pool settings of the default transport no longer apply.
+// Gateway requests made by the client use the same transport.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithTimeout sets the time limit of each request made by the client, including reading the
+// response body. The default is 90 seconds; zero means no limit. Contexts passed to the
+// WithContext methods can only shorten it.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithAPIRateLimit limits API requests to requestsPerSecond requests per second, with bursts of up
+// to burst requests. Requests over the limit wait for their turn, or fail with the error of their
+// context once it is done. The limit is shared by every request of the client, including those of
+// the worker pools of PinFilesAsync, DeleteFilesAsync and the other batch helpers, whatever their
+// concurrency. Gateway requests are limited separately with WithGatewayRateLimit. API requests are
+// not limited by default, and a requestsPerSecond of zero or less removes the limit.
+func WithAPIRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.apiLimiter = nil
+		if requestsPerSecond > 0 {
+			c.apiLimiter = newRateLimiter(requestsPerSecond, burst)
+		}
+	}
+}
+
+// WithConcurrency sets the number of concurrent requests of the batch helpers, such as
+// PinFilesAsync, DeleteFilesAsync, PinBatch, PinJSONsAsync and AuditPins, when their options do
+// not set one. Zero or less keeps the default of 5.
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// workers returns n, or the concurrency of the client when n is zero or less.
+func (c *Client) workers(n int) int {
+	switch {
+	case n > 0:
+		return n
+	case c.concurrency > 0:
+		return c.concurrency
+	}
+	return defaultConcurrency
+}
+
+// defaultConcurrency is the number of concurrent requests of the batch helpers unless set with
+// WithConcurrency or their options.
+const defaultConcurrency = 5
+
+// WithMaxURLLength sets the maximum length, in bytes, of API request URLs. Requests whose URL would
+// be longer fail before being sent with an error wrapping ErrQueryTooLong, instead of an opaque 414
+// or proxy error. Zero keeps DefaultMaxURLLength and a negative value disables the check.
+func WithMaxURLLength(length int) ClientOption {
+	return func(c *Client) {
+		c.maxURLLength = length
+	}
+}
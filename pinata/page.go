@@ -0,0 +1,359 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	// MaxPinListPageLimit is the largest pageLimit accepted by the pinList endpoint.
+	MaxPinListPageLimit = 1000
+	// MaxPinJobsLimit is the largest limit accepted by the pinJobs endpoint.
+	MaxPinJobsLimit = 1000
+)
+
+// ErrPageLimitExceeded is returned when a page size above the server cap is requested and the
+// client is configured with PageLimitReject.
+var ErrPageLimitExceeded = errors.New("page limit exceeded")
+
+// PageLimitPolicy controls how page sizes above the server caps are handled.
+type PageLimitPolicy int
+
+const (
+	// PageLimitClamp lowers page sizes above the cap to the cap and logs a warning. It is the default.
+	PageLimitClamp PageLimitPolicy = iota
+	// PageLimitReject fails the request with an error wrapping ErrPageLimitExceeded.
+	PageLimitReject
+)
+
+// WithPageLimitPolicy sets how page sizes above MaxPinListPageLimit and MaxPinJobsLimit are handled.
+func WithPageLimitPolicy(policy PageLimitPolicy) ClientOption {
+	return func(c *Client) {
+		c.pageLimitPolicy = policy
+	}
+}
+
+const (
+	// DefaultPaginationMaxWait is the default total time a listing helper waits on rate limits.
+	DefaultPaginationMaxWait = 5 * time.Minute
+	// DefaultPaginationBackoff is the default first pause after a rate limited page.
+	DefaultPaginationBackoff = time.Second
+	// maxPaginationBackoff caps the exponential backoff between rate limited pages.
+	maxPaginationBackoff = 30 * time.Second
+)
+
+// PaginationOptions configures the helpers that walk every page of a listing, such as
+// ListFilesAll, AuditPins and PinStats.
+// MaxWait is the total time a listing may spend paused on 429 responses before it fails with the
+// last 429 error (DefaultPaginationMaxWait when zero).
+// InitialBackoff is the pause after a 429 without a Retry-After header; it doubles on consecutive
+// 429s, up to 30 seconds (DefaultPaginationBackoff when zero).
+// Progress, if set, is called after every page and before every pause.
+type PaginationOptions struct {
+	MaxWait        time.Duration
+	InitialBackoff time.Duration
+	Progress       func(PaginationProgress)
+}
+
+// PaginationProgress reports the progress of a listing.
+// Path is the API path being listed.
+// Pages is the number of pages fetched so far.
+// Rows is the number of rows fetched so far.
+// Waiting is the length of the pause about to start after a 429, or zero after a page was fetched.
+type PaginationProgress struct {
+	Path    string
+	Pages   int
+	Rows    int
+	Waiting time.Duration
+}
+
+// WithPagination configures how the listing helpers handle rate limits and report progress.
+func WithPagination(options PaginationOptions) ClientOption {
+	return func(c *Client) {
+		c.pagination = options
+	}
+}
+
+// paginator fetches the pages of a single listing. A page rejected with 429 is retried at the same
+// offset after a pause, as long as the total time paused stays within MaxWait.
+type paginator struct {
+	options  PaginationOptions
+	progress PaginationProgress
+	waited   time.Duration
+	// backoffs counts the pauses without Retry-After since the last page fetched
+	backoffs int
+}
+
+// newPaginator returns a paginator for the listing of path, using the client's PaginationOptions.
+func (c *Client) newPaginator(path string) *paginator {
+	options := c.pagination
+	if options.MaxWait <= 0 {
+		options.MaxWait = DefaultPaginationMaxWait
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = DefaultPaginationBackoff
+	}
+	return &paginator{options: options, progress: PaginationProgress{Path: path}}
+}
+
+// fetch calls fetchPage, which returns the number of rows of the page, until it succeeds, fails
+// with an error other than 429, or the rate limit pauses exceed MaxWait.
+func (p *paginator) fetch(ctx context.Context, fetchPage func() (int, error)) error {
+	for {
+		n, err := fetchPage()
+		if err == nil {
+			p.backoffs = 0
+			p.progress.Pages++
+			p.progress.Rows += n
+			p.report(0)
+			return nil
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+
+		delay := apiErr.RetryAfter
+		if delay <= 0 {
+			p.backoffs++
+			delay = backoff(p.options.InitialBackoff, maxPaginationBackoff, p.backoffs)
+		}
+		if p.waited+delay > p.options.MaxWait {
+			return err
+		}
+		p.waited += delay
+		p.report(delay)
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// report calls the Progress callback, if any.
+func (p *paginator) report(waiting time.Duration) {
+	if p.options.Progress != nil {
+		progress := p.progress
+		progress.Waiting = waiting
+		p.options.Progress(progress)
+	}
+}
+
+const (
+	// defaultPinListPageLimit is the page size pinList uses when pageLimit is not set.
+	defaultPinListPageLimit = 10
+	// defaultPinJobsLimit is the page size pinJobs uses when limit is not set.
+	defaultPinJobsLimit = 5
+	// defaultGroupsLimit is the page size the groups endpoint uses when limit is not set.
+	defaultGroupsLimit = 10
+	// apiKeysPageSize is the fixed page size of the v3 keys endpoint.
+	apiKeysPageSize = 10
+)
+
+// Int returns a pointer to v, for setting the optional limits and offsets of the list options,
+// e.g. ListGroupsOptions{Offset: Int(0)}.
+func Int(v int) *int {
+	return &v
+}
+
+// Bool returns a pointer to v, for setting optional flags that default to true,
+// e.g. AddSwapOptions{VerifyTarget: Bool(false)}.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// intValue returns the value of p, or zero when p is nil.
+func intValue(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+// Page is a single page of a paginated listing.
+// Items holds the rows of the page.
+// TotalCount is the total number of matching rows, or -1 when the endpoint did not report it.
+// Offset is the offset of the first item of the page.
+// HasMore reports whether another page is likely to exist.
+// Warnings holds the non-fatal conditions encountered while fetching the page, such as a clamped
+// page size.
+//
+// When the total is known, HasMore is exact: Offset+len(Items) < TotalCount. Otherwise HasMore is
+// derived from a full-page heuristic: a page holding as many items as the requested page size is
+// assumed to be followed by another one. The heuristic can report a final, exactly full page as
+// HasMore, in which case the next page is simply empty.
+type Page[T any] struct {
+	Items      []T  `json:"items"`
+	TotalCount int  `json:"totalCount"`
+	Offset     int  `json:"offset"`
+	HasMore    bool `json:"hasMore"`
+
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// newPage builds a Page from the items returned at offset. total is the total number of matching
+// rows, or a negative value when unknown, in which case pageSize drives the full-page heuristic.
+func newPage[T any](items []T, offset, pageSize, total int) *Page[T] {
+	page := &Page[T]{Items: items, Offset: offset, TotalCount: -1}
+	if total >= 0 {
+		page.TotalCount = total
+		page.HasMore = offset+len(items) < total
+		return page
+	}
+	page.HasMore = pageSize > 0 && len(items) >= pageSize
+	return page
+}
+
+// ListFilesPage returns a single page of pinned files. If options.IncludeCount is set, the total
+// reported by the API makes HasMore exact; otherwise the full-page heuristic is used.
+func (c *Client) ListFilesPage(options *ListFilesOptions) (*Page[Pin], error) {
+	return c.ListFilesPageWithContext(context.Background(), options)
+}
+
+// ListFilesPageWithContext is like ListFilesPage but uses ctx for the requests.
+func (c *Client) ListFilesPageWithContext(ctx context.Context, options *ListFilesOptions) (*Page[Pin], error) {
+	response, err := c.listFiles(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, pageSize, total := 0, defaultPinListPageLimit, -1
+	if options != nil {
+		offset = options.PageOffset
+		if options.PageLimit > 0 {
+			pageSize = min(options.PageLimit, MaxPinListPageLimit)
+		}
+		if options.IncludeCount {
+			total = response.Count
+		}
+	}
+	page := newPage(response.Rows, offset, pageSize, total)
+	page.Warnings = response.Warnings
+	return page, nil
+}
+
+// ListPinByCidJobsPage returns a single page of pin by CID jobs. The pinJobs endpoint does not
+// report a reliable total, so HasMore uses the full-page heuristic.
+func (c *Client) ListPinByCidJobsPage(options *ListPinByCidOptions) (*Page[PinEntry], error) {
+	return c.ListPinByCidJobsPageWithContext(context.Background(), options)
+}
+
+// ListPinByCidJobsPageWithContext is like ListPinByCidJobsPage but uses ctx for the requests.
+func (c *Client) ListPinByCidJobsPageWithContext(ctx context.Context, options *ListPinByCidOptions) (*Page[PinEntry], error) {
+	response, err := c.listPinByCidJobs(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, pageSize := 0, defaultPinJobsLimit
+	if options != nil {
+		offset = intValue(options.Offset)
+		if limit := intValue(options.Limit); limit > 0 {
+			pageSize = min(limit, MaxPinJobsLimit)
+		}
+	}
+	page := newPage(response.Rows, offset, pageSize, -1)
+	page.Warnings = response.Warnings
+	return page, nil
+}
+
+// ListGroupsPage returns a single page of groups. The groups endpoint does not report a total,
+// so HasMore uses the full-page heuristic.
+func (c *Client) ListGroupsPage(options *ListGroupsOptions) (*Page[Group], error) {
+	return c.ListGroupsPageWithContext(context.Background(), options)
+}
+
+// ListGroupsPageWithContext is like ListGroupsPage but uses ctx for the requests.
+func (c *Client) ListGroupsPageWithContext(ctx context.Context, options *ListGroupsOptions) (*Page[Group], error) {
+	groups, err := c.listGroups(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, pageSize := 0, defaultGroupsLimit
+	if options != nil {
+		offset = intValue(options.Offset)
+		if limit := intValue(options.Limit); limit > 0 {
+			pageSize = limit
+		}
+	}
+	return newPage(groups, offset, pageSize, -1), nil
+}
+
+// ListApiKeyV3Page returns a single page of API keys. It is an alias of c.Keys().ListPage, kept
+// for compatibility.
+func (c *Client) ListApiKeyV3Page(options *ListApiKeysOptions) (*Page[ApiKey], error) {
+	return c.Keys().ListPage(options)
+}
+
+// ListFilesAll returns every pinned file matching options, walking pinList with pages of
+// MaxPinListPageLimit. Unlike the audit and stats helpers it lists content of any status unless
+// options sets one; options.PageLimit and options.PageOffset are managed by the listing.
+// Rate limited pages are retried as configured with WithPagination.
+func (c *Client) ListFilesAll(options *ListFilesOptions) ([]Pin, error) {
+	return c.ListFilesAllWithContext(context.Background(), options)
+}
+
+// ListFilesAllWithContext is like ListFilesAll but uses ctx for the requests.
+func (c *Client) ListFilesAllWithContext(ctx context.Context, options *ListFilesOptions) ([]Pin, error) {
+	filter := ListFilesOptions{Status: "all"}
+	if options != nil {
+		filter = *options
+		if filter.Status == "" {
+			filter.Status = "all"
+		}
+	}
+
+	var rows []Pin
+	err := c.forEachPinPage(ctx, &filter, func(page []Pin) error {
+		rows = append(rows, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListPinByCidJobsAll returns every pin by CID job matching options, walking pinJobs with pages
+// of MaxPinJobsLimit. options.Limit and options.Offset are managed by the listing.
+// Rate limited pages are retried as configured with WithPagination.
+func (c *Client) ListPinByCidJobsAll(options *ListPinByCidOptions) ([]PinEntry, error) {
+	return c.ListPinByCidJobsAllWithContext(context.Background(), options)
+}
+
+// ListPinByCidJobsAllWithContext is like ListPinByCidJobsAll but uses ctx for the requests.
+func (c *Client) ListPinByCidJobsAllWithContext(ctx context.Context, options *ListPinByCidOptions) ([]PinEntry, error) {
+	filter := ListPinByCidOptions{}
+	if options != nil {
+		filter = *options
+	}
+	limit, offset := MaxPinJobsLimit, 0
+	filter.Limit = &limit
+	filter.Offset = &offset
+
+	var rows []PinEntry
+	pages := c.newPaginator("/pinning/pinJobs")
+	for {
+		var response *ListPinByCidResponse
+		err := pages.fetch(ctx, func() (int, error) {
+			var err error
+			response, err = c.listPinByCidJobs(ctx, &filter)
+			if err != nil {
+				return 0, err
+			}
+			return len(response.Rows), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, response.Rows...)
+		if len(response.Rows) < limit {
+			return rows, nil
+		}
+		offset += len(response.Rows)
+	}
+}
@@ -0,0 +1,382 @@
+package pinata
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPage(t *testing.T) {
+	t.Run("derived from total", func(t *testing.T) {
+		page := newPage([]int{1, 2, 3}, 10, 3, 13)
+
+		require.Equal(t, 13, page.TotalCount)
+		require.Equal(t, 10, page.Offset)
+		require.False(t, page.HasMore)
+
+		page = newPage([]int{1, 2, 3}, 0, 3, 13)
+		require.True(t, page.HasMore)
+	})
+
+	t.Run("total of zero", func(t *testing.T) {
+		page := newPage([]int{}, 0, 10, 0)
+
+		require.Equal(t, 0, page.TotalCount)
+		require.False(t, page.HasMore)
+	})
+
+	t.Run("full page heuristic", func(t *testing.T) {
+		page := newPage([]int{1, 2, 3}, 0, 3, -1)
+
+		require.Equal(t, -1, page.TotalCount)
+		require.True(t, page.HasMore)
+	})
+
+	t.Run("short page heuristic", func(t *testing.T) {
+		page := newPage([]int{1, 2}, 3, 3, -1)
+
+		require.Equal(t, -1, page.TotalCount)
+		require.False(t, page.HasMore)
+	})
+}
+
+func TestListFilesPage(t *testing.T) {
+	rows := func(n int) string {
+		var r []string
+		for i := 0; i < n; i++ {
+			r = append(r, fmt.Sprintf(`{"ipfs_pin_hash":"Qm%d"}`, i))
+		}
+		return "[" + strings.Join(r, ",") + "]"
+	}
+
+	t.Run("with include count", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "true", r.URL.Query().Get("includeCount"))
+			w.Write([]byte(`{"count":25,"rows":` + rows(10) + `}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		page, err := client.ListFilesPage(&ListFilesOptions{PageLimit: 10, PageOffset: 10, IncludeCount: true})
+
+		require.NoError(t, err)
+		require.Len(t, page.Items, 10)
+		require.Equal(t, 25, page.TotalCount)
+		require.Equal(t, 10, page.Offset)
+		require.True(t, page.HasMore)
+	})
+
+	t.Run("count not requested", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NotContains(t, r.URL.Query(), "includeCount")
+			w.Write([]byte(`{"count":0,"rows":` + rows(3) + `}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		page, err := client.ListFilesPage(&ListFilesOptions{PageLimit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, page.Items, 3)
+		require.Equal(t, -1, page.TotalCount)
+		require.False(t, page.HasMore)
+	})
+
+	t.Run("count requested with zero results", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "true", r.URL.Query().Get("includeCount"))
+			w.Write([]byte(`{"count":0,"rows":[]}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		page, err := client.ListFilesPage(&ListFilesOptions{IncludeCount: true})
+
+		require.NoError(t, err)
+		require.Empty(t, page.Items)
+		require.Equal(t, 0, page.TotalCount)
+		require.False(t, page.HasMore)
+	})
+
+	t.Run("count requested with many results", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "true", r.URL.Query().Get("includeCount"))
+			w.Write([]byte(`{"count":2500,"rows":` + rows(MaxPinListPageLimit) + `}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		page, err := client.ListFilesPage(&ListFilesOptions{PageLimit: MaxPinListPageLimit, PageOffset: 1000, IncludeCount: true})
+
+		require.NoError(t, err)
+		require.Len(t, page.Items, MaxPinListPageLimit)
+		require.Equal(t, 2500, page.TotalCount)
+		require.True(t, page.HasMore)
+	})
+
+	t.Run("heuristic with default page size", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"rows":` + rows(defaultPinListPageLimit) + `}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		page, err := client.ListFilesPage(nil)
+
+		require.NoError(t, err)
+		require.Equal(t, -1, page.TotalCount)
+		require.True(t, page.HasMore)
+	})
+}
+
+func TestListPagesHeuristic(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pinning/pinJobs":
+			w.Write([]byte(`{"count":2,"rows":[{"id":"1"},{"id":"2"}]}`))
+		case "/groups":
+			w.Write([]byte(`[{"id":"g1"},{"id":"g2"}]`))
+		case "/v3/pinata/keys":
+			w.Write([]byte(`{"keys":[{"id":"k1"}],"count":1}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	jobs, err := client.ListPinByCidJobsPage(&ListPinByCidOptions{Limit: Int(2), Offset: Int(4)})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 2)
+	require.Equal(t, 4, jobs.Offset)
+	require.True(t, jobs.HasMore)
+
+	groups, err := client.ListGroupsPage(&ListGroupsOptions{Limit: Int(5)})
+	require.NoError(t, err)
+	require.Len(t, groups.Items, 2)
+	require.False(t, groups.HasMore)
+
+	keys, err := client.ListApiKeyV3Page(nil)
+	require.NoError(t, err)
+	require.Len(t, keys.Items, 1)
+	require.Equal(t, -1, keys.TotalCount)
+	require.False(t, keys.HasMore)
+}
+
+func TestPageLimitCaps(t *testing.T) {
+	t.Run("clamped with warning", func(t *testing.T) {
+		var logs bytes.Buffer
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+		rb := client.NewRequest(http.MethodGet, "/data/pinList").
+			setListPinsQueryParams(&ListFilesOptions{PageLimit: 5000})
+		require.NoError(t, rb.err)
+		require.Equal(t, "1000", rb.queryParams["pageLimit"])
+
+		rb = client.NewRequest(http.MethodGet, "/pinning/pinJobs").
+			setListPinsByCidQueryParams(&ListPinByCidOptions{Limit: Int(1001)})
+		require.NoError(t, rb.err)
+		require.Equal(t, "1000", rb.queryParams["limit"])
+
+		require.Contains(t, logs.String(), "level=WARN")
+		require.Contains(t, logs.String(), "requested=5000")
+		require.Contains(t, logs.String(), "requested=1001")
+	})
+
+	t.Run("values at the cap are kept", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPageLimitPolicy(PageLimitReject))
+
+		rb := client.NewRequest(http.MethodGet, "/data/pinList").
+			setListPinsQueryParams(&ListFilesOptions{PageLimit: MaxPinListPageLimit})
+		require.NoError(t, rb.err)
+		require.Equal(t, "1000", rb.queryParams["pageLimit"])
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("request should not be sent")
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPageLimitPolicy(PageLimitReject))
+		client.baseURL = mockServer.URL
+
+		_, err := client.ListFiles(&ListFilesOptions{PageLimit: 1001})
+		require.ErrorIs(t, err, ErrPageLimitExceeded)
+
+		_, err = client.ListPinByCidJobs(&ListPinByCidOptions{Limit: Int(2000)})
+		require.ErrorIs(t, err, ErrPageLimitExceeded)
+	})
+}
+
+func TestListAllUsesMaxPageSize(t *testing.T) {
+	rows := func(n int, key string) string {
+		var r []string
+		for i := 0; i < n; i++ {
+			r = append(r, fmt.Sprintf(`{"%s":"Qm%d"}`, key, i))
+		}
+		return "[" + strings.Join(r, ",") + "]"
+	}
+
+	t.Run("ListFilesAll", func(t *testing.T) {
+		var offsets []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			require.Equal(t, "1000", query.Get("pageLimit"))
+			require.Equal(t, "all", query.Get("status"))
+			offsets = append(offsets, query.Get("pageOffset"))
+
+			n := MaxPinListPageLimit
+			if query.Get("pageOffset") != "" {
+				n = 3
+			}
+			w.Write([]byte(`{"rows":` + rows(n, "ipfs_pin_hash") + `}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		files, err := client.ListFilesAll(&ListFilesOptions{PageLimit: 10})
+		require.NoError(t, err)
+		require.Len(t, files, 1003)
+		require.Equal(t, []string{"", "1000"}, offsets)
+	})
+
+	t.Run("ListPinByCidJobsAll", func(t *testing.T) {
+		var offsets []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			require.Equal(t, "1000", query.Get("limit"))
+			require.Equal(t, "retrieving", query.Get("status"))
+			offsets = append(offsets, query.Get("offset"))
+
+			n := MaxPinJobsLimit
+			if query.Get("offset") != "0" {
+				n = 0
+			}
+			w.Write([]byte(`{"rows":` + rows(n, "ipfs_pin_hash") + `}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		jobs, err := client.ListPinByCidJobsAll(&ListPinByCidOptions{Status: PinStatusRetrieving, Offset: Int(50)})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1000)
+		require.Equal(t, []string{"0", "1000"}, offsets)
+	})
+}
+
+func TestPaginationRateLimit(t *testing.T) {
+	// newRateLimitedServer serves two pinList pages (1000 rows, then 5), answering the first
+	// request for the second page with 429 for the given number of times.
+	newRateLimitedServer := func(t *testing.T, limited int, retryAfter string, offsets *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			offset := r.URL.Query().Get("pageOffset")
+			*offsets = append(*offsets, offset)
+
+			if offset == "1000" && limited > 0 {
+				limited--
+				if retryAfter != "" {
+					w.Header().Set("Retry-After", retryAfter)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			n := MaxPinListPageLimit
+			if offset != "" {
+				n = 5
+			}
+			var rows []string
+			for i := 0; i < n; i++ {
+				rows = append(rows, `{"ipfs_pin_hash":"Qm"}`)
+			}
+			w.Write([]byte(`{"rows":[` + strings.Join(rows, ",") + `]}`))
+		}))
+	}
+
+	t.Run("resumes from the same offset", func(t *testing.T) {
+		var offsets []string
+		mockServer := newRateLimitedServer(t, 2, "", &offsets)
+		defer mockServer.Close()
+
+		var progress []PaginationProgress
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPagination(PaginationOptions{
+			InitialBackoff: time.Millisecond,
+			Progress:       func(p PaginationProgress) { progress = append(progress, p) },
+		}))
+		client.baseURL = mockServer.URL
+
+		files, err := client.ListFilesAll(nil)
+		require.NoError(t, err)
+		require.Len(t, files, 1005)
+		require.Equal(t, []string{"", "1000", "1000", "1000"}, offsets)
+
+		require.Equal(t, []PaginationProgress{
+			{Path: "/data/pinList", Pages: 1, Rows: 1000},
+			{Path: "/data/pinList", Pages: 1, Rows: 1000, Waiting: time.Millisecond},
+			{Path: "/data/pinList", Pages: 1, Rows: 1000, Waiting: 2 * time.Millisecond},
+			{Path: "/data/pinList", Pages: 2, Rows: 1005},
+		}, progress)
+	})
+
+	t.Run("honors retry after", func(t *testing.T) {
+		var offsets []string
+		mockServer := newRateLimitedServer(t, 1, "60", &offsets)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPagination(PaginationOptions{
+			InitialBackoff: time.Millisecond,
+			MaxWait:        time.Second,
+		}))
+		client.baseURL = mockServer.URL
+
+		_, err := client.ListFilesAll(nil)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, time.Minute, apiErr.RetryAfter)
+		// the requested pause exceeds MaxWait, so the listing fails without retrying
+		require.Equal(t, []string{"", "1000"}, offsets)
+	})
+
+	t.Run("fails after max wait", func(t *testing.T) {
+		var offsets []string
+		mockServer := newRateLimitedServer(t, 100, "", &offsets)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPagination(PaginationOptions{
+			InitialBackoff: time.Millisecond,
+			MaxWait:        10 * time.Millisecond,
+		}))
+		client.baseURL = mockServer.URL
+
+		_, err := client.ListFilesAll(nil)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		// pauses of 1, 2 and 4ms fit in 10ms, the next one of 8ms does not
+		require.Equal(t, []string{"", "1000", "1000", "1000", "1000"}, offsets)
+	})
+}
@@ -0,0 +1,286 @@
+package pinata
+
+import "sort"
+
+// Operation identifies a Client method for the purpose of computing the API key permissions it requires.
+// The context-aware variant of a method, such as PinFileWithContext, requires the permissions of the
+// operation of the method it varies.
+type Operation string
+
+const (
+	OpTestAuthentication            Operation = "TestAuthentication"
+	OpPinFile                       Operation = "PinFile"
+	OpPinFilesAsync                 Operation = "PinFilesAsync"
+	OpPinURL                        Operation = "PinURL"
+	OpPinFolder                     Operation = "PinFolder"
+	OpPinNestedFolders              Operation = "PinNestedFolders"
+	OpPinBatch                      Operation = "PinBatch"
+	OpPinDirectory                  Operation = "PinDirectory"
+	OpMirrorURL                     Operation = "MirrorURL"
+	OpPinJSON                       Operation = "PinJSON"
+	OpPinByCid                      Operation = "PinByCid"
+	OpListPinByCidJobs              Operation = "ListPinByCidJobs"
+	OpUpdateFileMetadata            Operation = "UpdateFileMetadata"
+	OpDeleteFile                    Operation = "DeleteFile"
+	OpDeleteFilesAsync              Operation = "DeleteFilesAsync"
+	OpListFiles                     Operation = "ListFiles"
+	OpAuditPins                     Operation = "AuditPins"
+	OpPinStats                      Operation = "PinStats"
+	OpPinnedFileCount               Operation = "PinnedFileCount"
+	OpTotalStorageSize              Operation = "TotalStorageSize"
+	OpCreateGroup                   Operation = "CreateGroup"
+	OpGetGroup                      Operation = "GetGroup"
+	OpListGroups                    Operation = "ListGroups"
+	OpUpdateGroup                   Operation = "UpdateGroup"
+	OpAddCidToGroup                 Operation = "AddCidToGroup"
+	OpRemoveCidFromGroup            Operation = "RemoveCidFromGroup"
+	OpRemoveGroup                   Operation = "RemoveGroup"
+	OpAddSwap                       Operation = "AddSwap"
+	OpGetSwapHistory                Operation = "GetSwapHistory"
+	OpRemoveSwap                    Operation = "RemoveSwap"
+	OpAddCidSignature               Operation = "AddCidSignature"
+	OpGetCidSignature               Operation = "GetCidSignature"
+	OpRemoveCidSignature            Operation = "RemoveCidSignature"
+	OpGenerateApiKey                Operation = "GenerateApiKey"
+	OpGenerateApiKeyV3              Operation = "GenerateApiKeyV3"
+	OpListApiKeys                   Operation = "ListApiKeys"
+	OpListApiKeyV3                  Operation = "ListApiKeyV3"
+	OpRevokeApiKey                  Operation = "RevokeApiKey"
+	OpRevokeApiKeyV3                Operation = "RevokeApiKeyV3"
+	OpListFilesPage                 Operation = "ListFilesPage"
+	OpListPinByCidJobsPage          Operation = "ListPinByCidJobsPage"
+	OpListGroupsPage                Operation = "ListGroupsPage"
+	OpListApiKeyV3Page              Operation = "ListApiKeyV3Page"
+	OpListFilesAll                  Operation = "ListFilesAll"
+	OpListPinByCidJobsAll           Operation = "ListPinByCidJobsAll"
+	OpUnpin                         Operation = "Unpin"
+	OpDeleteFileByID                Operation = "DeleteFileByID"
+	OpUpdateFolderFileMetadata      Operation = "UpdateFolderFileMetadata"
+	OpListFolderContents            Operation = "ListFolderContents"
+	OpListFolderContentsWithOptions Operation = "ListFolderContentsWithOptions"
+	OpStatContent                   Operation = "StatContent"
+	OpGetContent                    Operation = "GetContent"
+	OpHealthCheck                   Operation = "HealthCheck"
+	OpPinCidToGroup                 Operation = "PinCidToGroup"
+	OpPinOpenFile                   Operation = "PinOpenFile"
+	OpNewPinQueue                   Operation = "NewPinQueue"
+	OpWatchPinJobs                  Operation = "WatchPinJobs"
+	OpIsPinned                      Operation = "IsPinned"
+	OpSwapTo                        Operation = "SwapTo"
+	OpAuthHealthCheck               Operation = "AuthHealthCheck"
+	OpReconcileGroups               Operation = "ReconcileGroups"
+	OpGetPinByCid                   Operation = "GetPinByCid"
+	OpVectorizeFile                 Operation = "VectorizeFile"
+	OpDeleteFileVectors             Operation = "DeleteFileVectors"
+	OpQueryVectors                  Operation = "QueryVectors"
+	OpGetPinnedDataTotal            Operation = "GetPinnedDataTotal"
+	OpPinLargeDirectory             Operation = "PinLargeDirectory"
+	OpPinReader                     Operation = "PinReader"
+	OpStreamFiles                   Operation = "StreamFiles"
+	OpListUngroupedPins             Operation = "ListUngroupedPins"
+	OpPinJSONsAsync                 Operation = "PinJSONsAsync"
+	OpSearchFilesByName             Operation = "SearchFilesByName"
+	OpDeleteFolder                  Operation = "DeleteFolder"
+)
+
+// operationPermissions maps every Client method that calls the Pinata API to the API key permissions
+// it requires. Endpoints without a scoped permission in Permissions (groups, swaps, signatures and
+// key management) require an admin key. TestAuthentication works with any valid key.
+//
+// Every exported Client method must have an entry here or in operationsWithoutPermissions, the
+// WithContext variants through the method they vary; this is enforced by
+// TestOperationRegistryComplete.
+var operationPermissions = map[Operation]Permissions{
+	OpTestAuthentication:            {},
+	OpPinFile:                       {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpPinFilesAsync:                 {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpPinURL:                        {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpPinFolder:                     {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpPinNestedFolders:              {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpPinBatch:                      {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpPinDirectory:                  {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpMirrorURL:                     {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpPinJSON:                       {Endpoints: &EndPoint{Pinning: Pinning{PinJSONToIPFS: true}}},
+	OpPinByCid:                      {Endpoints: &EndPoint{Pinning: Pinning{PinByHash: true}}},
+	OpListPinByCidJobs:              {Endpoints: &EndPoint{Pinning: Pinning{PinJobs: true}}},
+	OpListPinByCidJobsPage:          {Endpoints: &EndPoint{Pinning: Pinning{PinJobs: true}}},
+	OpUpdateFileMetadata:            {Endpoints: &EndPoint{Pinning: Pinning{HashMetadata: true}}},
+	OpDeleteFile:                    {Endpoints: &EndPoint{Pinning: Pinning{UnPin: true}}},
+	OpDeleteFilesAsync:              {Endpoints: &EndPoint{Pinning: Pinning{UnPin: true}}},
+	OpListFiles:                     {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpListFilesPage:                 {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpAuditPins:                     {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpPinStats:                      {Admin: true},
+	OpPinnedFileCount:               {Endpoints: &EndPoint{Data: Data{UserPinnedDataTotal: true}}},
+	OpTotalStorageSize:              {Endpoints: &EndPoint{Data: Data{UserPinnedDataTotal: true}}},
+	OpCreateGroup:                   {Admin: true},
+	OpGetGroup:                      {Admin: true},
+	OpListGroups:                    {Admin: true},
+	OpListGroupsPage:                {Admin: true},
+	OpUpdateGroup:                   {Admin: true},
+	OpAddCidToGroup:                 {Admin: true},
+	OpRemoveCidFromGroup:            {Admin: true},
+	OpRemoveGroup:                   {Admin: true},
+	OpAddSwap:                       {Admin: true},
+	OpGetSwapHistory:                {Admin: true},
+	OpRemoveSwap:                    {Admin: true},
+	OpAddCidSignature:               {Admin: true},
+	OpGetCidSignature:               {Admin: true},
+	OpRemoveCidSignature:            {Admin: true},
+	OpGenerateApiKey:                {Admin: true},
+	OpGenerateApiKeyV3:              {Admin: true},
+	OpListApiKeys:                   {Admin: true},
+	OpListApiKeyV3:                  {Admin: true},
+	OpListApiKeyV3Page:              {Admin: true},
+	OpRevokeApiKey:                  {Admin: true},
+	OpRevokeApiKeyV3:                {Admin: true},
+	OpListFilesAll:                  {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpListPinByCidJobsAll:           {Endpoints: &EndPoint{Pinning: Pinning{PinJobs: true}}},
+	OpUnpin:                         {Endpoints: &EndPoint{Pinning: Pinning{UnPin: true}}},
+	OpDeleteFileByID:                {Endpoints: &EndPoint{Data: Data{PinList: true}, Pinning: Pinning{UnPin: true}}},
+	OpUpdateFolderFileMetadata:      {Endpoints: &EndPoint{Pinning: Pinning{HashMetadata: true}}},
+	OpListFolderContents:            {},
+	OpListFolderContentsWithOptions: {},
+	OpStatContent:                   {},
+	OpGetContent:                    {},
+	OpHealthCheck:                   {},
+	OpPinCidToGroup:                 {Admin: true},
+	OpPinOpenFile:                   {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpNewPinQueue:                   {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpWatchPinJobs:                  {Endpoints: &EndPoint{Pinning: Pinning{PinJobs: true}}},
+	OpIsPinned:                      {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpSwapTo:                        {Admin: true},
+	OpAuthHealthCheck:               {},
+	OpReconcileGroups:               {Admin: true},
+	OpGetPinByCid:                   {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpVectorizeFile:                 {Admin: true},
+	OpDeleteFileVectors:             {Admin: true},
+	OpQueryVectors:                  {Admin: true},
+	OpGetPinnedDataTotal:            {Endpoints: &EndPoint{Data: Data{UserPinnedDataTotal: true}}},
+	OpPinLargeDirectory:             {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true, PinJSONToIPFS: true}}},
+	OpPinReader:                     {Endpoints: &EndPoint{Pinning: Pinning{PinFileToIPFS: true}}},
+	OpStreamFiles:                   {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpListUngroupedPins:             {Admin: true},
+	OpPinJSONsAsync:                 {Endpoints: &EndPoint{Pinning: Pinning{PinJSONToIPFS: true}}},
+	OpSearchFilesByName:             {Endpoints: &EndPoint{Data: Data{PinList: true}}},
+	OpDeleteFolder:                  {Endpoints: &EndPoint{Data: Data{PinList: true}, Pinning: Pinning{UnPin: true}}},
+}
+
+// operationsWithoutPermissions lists Client methods that do not map to a fixed endpoint, such as
+// the accessors of sub-clients.
+var operationsWithoutPermissions = map[string]bool{
+	"NewRequest": true,
+	"Gateway":    true,
+	"Keys":       true,
+	"Files":      true,
+	"Groups":     true,
+
+	"ActiveOperations": true,
+	"LastRateLimit":    true,
+}
+
+// Operations returns every registered operation, sorted by name.
+func Operations() []Operation {
+	ops := make([]Operation, 0, len(operationPermissions))
+	for op := range operationPermissions {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i] < ops[j] })
+	return ops
+}
+
+// PermissionsFor returns the least-privilege Permissions that allow all of the given operations,
+// ready to be used in GenerateApiKeyOptions. Unknown operations are ignored.
+func PermissionsFor(ops ...Operation) Permissions {
+	var result Permissions
+	for _, op := range ops {
+		if perms, ok := operationPermissions[op]; ok {
+			result = Merge(result, perms)
+		}
+	}
+	return result
+}
+
+// PermissionsUploadOnly returns the Permissions of a key that can only upload content: files and
+// JSON. It cannot list, unpin, pin by CID or change metadata.
+func PermissionsUploadOnly() Permissions {
+	return Permissions{
+		Admin: false,
+		Endpoints: &EndPoint{
+			Data: Data{
+				PinList:             false,
+				UserPinnedDataTotal: false,
+			},
+			Pinning: Pinning{
+				HashMetadata:  false,
+				HashPinPolicy: false,
+				PinByHash:     false,
+				PinFileToIPFS: true,
+				PinJSONToIPFS: true,
+				PinJobs:       false,
+				UnPin:         false,
+				UserPinPolicy: false,
+			},
+		},
+	}
+}
+
+// PermissionsReadOnly returns the Permissions of a key that can only read: list pins and pin
+// jobs, and query the pinned data totals. It cannot upload, unpin or change metadata.
+func PermissionsReadOnly() Permissions {
+	return Permissions{
+		Admin: false,
+		Endpoints: &EndPoint{
+			Data: Data{
+				PinList:             true,
+				UserPinnedDataTotal: true,
+			},
+			Pinning: Pinning{
+				HashMetadata:  false,
+				HashPinPolicy: false,
+				PinByHash:     false,
+				PinFileToIPFS: false,
+				PinJSONToIPFS: false,
+				PinJobs:       true,
+				UnPin:         false,
+				UserPinPolicy: false,
+			},
+		},
+	}
+}
+
+// PermissionsAdmin returns the Permissions of an admin key, which can use every endpoint,
+// including key management and groups.
+func PermissionsAdmin() Permissions {
+	return Permissions{Admin: true}
+}
+
+// Merge returns the Permissions that allow everything allowed by a or by b. Neither argument is
+// modified.
+func Merge(a, b Permissions) Permissions {
+	result := Permissions{Admin: a.Admin || b.Admin}
+	for _, endpoints := range []*EndPoint{a.Endpoints, b.Endpoints} {
+		if endpoints == nil {
+			continue
+		}
+		if result.Endpoints == nil {
+			result.Endpoints = &EndPoint{}
+		}
+		result.Endpoints.or(endpoints)
+	}
+	return result
+}
+
+// or sets every permission of e that is set in other.
+func (e *EndPoint) or(other *EndPoint) {
+	e.Data.PinList = e.Data.PinList || other.Data.PinList
+	e.Data.UserPinnedDataTotal = e.Data.UserPinnedDataTotal || other.Data.UserPinnedDataTotal
+	e.Pinning.HashMetadata = e.Pinning.HashMetadata || other.Pinning.HashMetadata
+	e.Pinning.HashPinPolicy = e.Pinning.HashPinPolicy || other.Pinning.HashPinPolicy
+	e.Pinning.PinByHash = e.Pinning.PinByHash || other.Pinning.PinByHash
+	e.Pinning.PinFileToIPFS = e.Pinning.PinFileToIPFS || other.Pinning.PinFileToIPFS
+	e.Pinning.PinJSONToIPFS = e.Pinning.PinJSONToIPFS || other.Pinning.PinJSONToIPFS
+	e.Pinning.PinJobs = e.Pinning.PinJobs || other.Pinning.PinJobs
+	e.Pinning.UnPin = e.Pinning.UnPin || other.Pinning.UnPin
+	e.Pinning.UserPinPolicy = e.Pinning.UserPinPolicy || other.Pinning.UserPinPolicy
+}
@@ -0,0 +1,134 @@
+package pinata
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationRegistryComplete(t *testing.T) {
+	clientType := reflect.TypeOf(&Client{})
+	for i := 0; i < clientType.NumMethod(); i++ {
+		name := clientType.Method(i).Name
+		if base, ok := strings.CutSuffix(name, "WithContext"); ok {
+			_, ok := clientType.MethodByName(base)
+			require.Truef(t, ok, "Client.%s has no context-free counterpart %s", name, base)
+			name = base
+		}
+		if operationsWithoutPermissions[name] {
+			continue
+		}
+		_, ok := operationPermissions[Operation(name)]
+		require.Truef(t, ok, "Client.%s has no entry in operationPermissions (permissions.go)", name)
+	}
+
+	for op := range operationPermissions {
+		_, ok := clientType.MethodByName(string(op))
+		require.Truef(t, ok, "operationPermissions has an entry for unknown method %s", op)
+	}
+}
+
+func TestPermissionsFor(t *testing.T) {
+	t.Run("combines endpoint permissions", func(t *testing.T) {
+		perms := PermissionsFor(OpPinFile, OpPinJSON, OpListFiles, OpDeleteFile)
+
+		require.False(t, perms.Admin)
+		require.NotNil(t, perms.Endpoints)
+		require.True(t, perms.Endpoints.Pinning.PinFileToIPFS)
+		require.True(t, perms.Endpoints.Pinning.PinJSONToIPFS)
+		require.True(t, perms.Endpoints.Pinning.UnPin)
+		require.True(t, perms.Endpoints.Data.PinList)
+		require.False(t, perms.Endpoints.Pinning.PinByHash)
+
+		encoded, err := json.Marshal(perms)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"endpoints":{"data":{"pinList":true},"pinning":{"pinFileToIPFS":true,"pinJSONToIPFS":true,"unpin":true}}}`, string(encoded))
+	})
+
+	t.Run("admin operations", func(t *testing.T) {
+		perms := PermissionsFor(OpPinFile, OpCreateGroup)
+
+		require.True(t, perms.Admin)
+		require.True(t, perms.Endpoints.Pinning.PinFileToIPFS)
+	})
+
+	t.Run("no operations", func(t *testing.T) {
+		perms := PermissionsFor()
+
+		require.False(t, perms.Admin)
+		require.Nil(t, perms.Endpoints)
+	})
+
+	t.Run("operations without scoped permissions", func(t *testing.T) {
+		perms := PermissionsFor(OpTestAuthentication, Operation("Unknown"))
+
+		require.Equal(t, Permissions{}, perms)
+	})
+
+	t.Run("does not mutate the registry", func(t *testing.T) {
+		PermissionsFor(OpPinFile, OpListFiles)
+
+		require.False(t, operationPermissions[OpPinFile].Endpoints.Data.PinList)
+	})
+
+	t.Run("operations lists the registry", func(t *testing.T) {
+		ops := Operations()
+		require.Len(t, ops, len(operationPermissions))
+		require.True(t, sort.SliceIsSorted(ops, func(i, j int) bool { return ops[i] < ops[j] }))
+		require.Equal(t, ops, Operations())
+	})
+}
+
+func TestPermissionPresets(t *testing.T) {
+	presets := []struct {
+		name     string
+		perms    Permissions
+		expected string
+	}{
+		{"upload only", PermissionsUploadOnly(), `{"endpoints":{"data":{},"pinning":{"pinFileToIPFS":true,"pinJSONToIPFS":true}}}`},
+		{"read only", PermissionsReadOnly(), `{"endpoints":{"data":{"pinList":true,"userPinnedDataTotal":true},"pinning":{"pinJobs":true}}}`},
+		{"admin", PermissionsAdmin(), `{"admin":true}`},
+	}
+	for _, tc := range presets {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tc.perms)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(encoded))
+		})
+	}
+
+	t.Run("presets are independent", func(t *testing.T) {
+		perms := PermissionsUploadOnly()
+		perms.Endpoints.Pinning.UnPin = true
+
+		require.False(t, PermissionsUploadOnly().Endpoints.Pinning.UnPin)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("unions endpoint permissions", func(t *testing.T) {
+		upload, read := PermissionsUploadOnly(), PermissionsReadOnly()
+		merged := Merge(upload, read)
+
+		encoded, err := json.Marshal(merged)
+		require.NoError(t, err)
+		require.Equal(t, `{"endpoints":{"data":{"pinList":true,"userPinnedDataTotal":true},"pinning":{"pinFileToIPFS":true,"pinJSONToIPFS":true,"pinJobs":true}}}`, string(encoded))
+		require.Equal(t, PermissionsUploadOnly(), upload)
+		require.Equal(t, PermissionsReadOnly(), read)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		merged := Merge(PermissionsAdmin(), PermissionsReadOnly())
+
+		require.True(t, merged.Admin)
+		require.True(t, merged.Endpoints.Data.PinList)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Equal(t, Permissions{}, Merge(Permissions{}, Permissions{}))
+	})
+}
@@ -0,0 +1,38 @@
+package pinatatest
+
+import (
+	"net/http"
+
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+// The identifiers found in the fixtures: the pin listed by the "GET /data/pinList" fixture has the
+// CID FixtureCid and the ID FixturePinID, the pin by CID job of the "POST /pinning/pinByHash" and
+// "GET /pinning/pinJobs" fixtures has the ID FixtureJobID, and the group fixtures describe the
+// group FixtureGroupID.
+const (
+	FixtureCid     = fixtures.Cid
+	FixturePinID   = fixtures.PinID
+	FixtureJobID   = fixtures.JobID
+	FixtureGroupID = fixtures.GroupID
+)
+
+// Fixture returns the response body of endpoint, or nil when the endpoint has no fixture. The body
+// is hand-written after the response documented in the Pinata API reference. The endpoint is given
+// as a net/http pattern listed by FixtureEndpoints, such as "GET /groups/{id}".
+func Fixture(endpoint string) []byte {
+	body, _ := fixtures.Body(endpoint)
+	return body
+}
+
+// FixtureEndpoints returns the endpoints with a fixture, sorted.
+func FixtureEndpoints() []string {
+	return fixtures.Endpoints()
+}
+
+// FixtureHandler returns a handler answering the requests to each endpoint of FixtureEndpoints with
+// its fixture, and other requests with 404 Not Found. Unlike Server, it keeps no state: every
+// request to an endpoint gets the same response, which suits tests of response handling.
+func FixtureHandler() http.Handler {
+	return fixtures.Handler()
+}
@@ -0,0 +1,44 @@
+package pinatatest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata"
+)
+
+func TestFixtures(t *testing.T) {
+	server := httptest.NewServer(FixtureHandler())
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client := pinata.New(pinata.NewAuthWithJWT("jwt"), pinata.WithTransport(&rewriteTransport{target: target, next: http.DefaultTransport}))
+
+	group, err := client.Groups().Get(FixtureGroupID)
+	require.NoError(t, err)
+	require.Equal(t, FixtureGroupID, group.ID)
+	require.Equal(t, "2024-05-21T19:27:03.391Z", group.CreatedAt)
+
+	keys, err := client.ListApiKeyV3(nil)
+	require.NoError(t, err)
+	require.Len(t, keys.Keys, 1)
+	require.Equal(t, 97, keys.Keys[0].RemainingUses())
+
+	pin, err := client.GetPinByCid(context.Background(), FixtureCid, nil)
+	require.NoError(t, err)
+	require.Equal(t, FixturePinID, pin.ID)
+
+	for _, endpoint := range FixtureEndpoints() {
+		body := Fixture(endpoint)
+		require.NotEmptyf(t, body, "fixture of %s", endpoint)
+		if body[0] == '{' || body[0] == '[' {
+			require.Truef(t, json.Valid(body), "fixture of %s", endpoint)
+		}
+	}
+	require.Nil(t, Fixture("GET /unknown"))
+}
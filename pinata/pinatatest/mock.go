@@ -0,0 +1,1362 @@
+// Code generated by pinata/internal/mockgen from pinata/api.go. DO NOT EDIT.
+
+//go:generate go run ../internal/mockgen
+
+package pinatatest
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zde37/pinata-go-sdk/pinata"
+)
+
+// Mock is a fake pinata.PinataAPI, standing in for a *pinata.Client in tests.
+// Each method records its call, see Calls, and calls the field named after it with a Func
+// suffix, such as PinFileFunc for PinFile; when the field is nil, the method fails with an
+// error wrapping ErrNotMocked. Set the fields before the first call; the mock is then safe
+// for concurrent use.
+type Mock struct {
+	recorder
+
+	PinFileFunc                         func(path string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinFileWithContextFunc              func(ctx context.Context, path string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinOpenFileFunc                     func(f *os.File, name string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinOpenFileWithContextFunc          func(ctx context.Context, f *os.File, name string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinFilesAsyncFunc                   func(paths []string, options []pinata.PinOptions) ([]*pinata.PinResponse, error)
+	PinFilesAsyncWithContextFunc        func(ctx context.Context, paths []string, options []pinata.PinOptions) ([]*pinata.PinResponse, error)
+	PinURLFunc                          func(url string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinURLWithContextFunc               func(ctx context.Context, url string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinFolderFunc                       func(filePaths []string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinFolderWithContextFunc            func(ctx context.Context, filePaths []string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinNestedFoldersFunc                func(baseDir string, paths []string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinNestedFoldersWithContextFunc     func(ctx context.Context, baseDir string, paths []string, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinJSONFunc                         func(data interface{}, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinJSONWithContextFunc              func(ctx context.Context, data interface{}, options *pinata.PinOptions) (*pinata.PinResponse, error)
+	PinJSONsAsyncFunc                   func(ctx context.Context, items []pinata.JSONPinItem, options *pinata.JSONBatchOptions) ([]pinata.JSONPinResult, error)
+	PinReaderFunc                       func(ctx context.Context, r io.Reader, name string, options *pinata.PinOptions, readerOptions *pinata.PinReaderOptions) (*pinata.PinResponse, error)
+	PinBatchFunc                        func(ctx context.Context, paths []string, options *pinata.BatchOptions) ([]pinata.BatchItemResult, error)
+	PinDirectoryFunc                    func(ctx context.Context, dir string, options *pinata.BatchOptions) ([]pinata.BatchItemResult, error)
+	PinLargeDirectoryFunc               func(ctx context.Context, dir string, opts pinata.LargeDirOptions) (*pinata.LargeDirResult, error)
+	MirrorURLFunc                       func(ctx context.Context, url string, options *pinata.MirrorOptions) (*pinata.MirrorResult, error)
+	PinByCidFunc                        func(hashToPin string, options *pinata.PinByCidOptions) (*pinata.PinByCidResponse, error)
+	PinByCidWithContextFunc             func(ctx context.Context, hashToPin string, options *pinata.PinByCidOptions) (*pinata.PinByCidResponse, error)
+	ListPinByCidJobsFunc                func(options *pinata.ListPinByCidOptions) (*pinata.ListPinByCidResponse, error)
+	ListPinByCidJobsWithContextFunc     func(ctx context.Context, options *pinata.ListPinByCidOptions) (*pinata.ListPinByCidResponse, error)
+	ListPinByCidJobsPageFunc            func(options *pinata.ListPinByCidOptions) (*pinata.Page[pinata.PinEntry], error)
+	ListPinByCidJobsPageWithContextFunc func(ctx context.Context, options *pinata.ListPinByCidOptions) (*pinata.Page[pinata.PinEntry], error)
+	ListPinByCidJobsAllFunc             func(options *pinata.ListPinByCidOptions) ([]pinata.PinEntry, error)
+	ListPinByCidJobsAllWithContextFunc  func(ctx context.Context, options *pinata.ListPinByCidOptions) ([]pinata.PinEntry, error)
+	WatchPinJobsFunc                    func(ctx context.Context, interval time.Duration, filter *pinata.ListPinByCidOptions) (<-chan pinata.PinJobEvent, error)
+	ListFilesFunc                       func(options *pinata.ListFilesOptions) (*pinata.ListFilesResponse, error)
+	ListFilesWithContextFunc            func(ctx context.Context, options *pinata.ListFilesOptions) (*pinata.ListFilesResponse, error)
+	ListFilesPageFunc                   func(options *pinata.ListFilesOptions) (*pinata.Page[pinata.Pin], error)
+	ListFilesPageWithContextFunc        func(ctx context.Context, options *pinata.ListFilesOptions) (*pinata.Page[pinata.Pin], error)
+	ListFilesAllFunc                    func(options *pinata.ListFilesOptions) ([]pinata.Pin, error)
+	ListFilesAllWithContextFunc         func(ctx context.Context, options *pinata.ListFilesOptions) ([]pinata.Pin, error)
+	StreamFilesFunc                     func(ctx context.Context, options *pinata.ListFilesOptions, buffer int) (<-chan pinata.Pin, <-chan error)
+	SearchFilesByNameFunc               func(ctx context.Context, options *pinata.NameSearchOptions) (<-chan pinata.Pin, <-chan error)
+	ListUngroupedPinsFunc               func(ctx context.Context, options *pinata.UngroupedOptions) (<-chan pinata.Pin, <-chan error)
+	GetPinByCidFunc                     func(ctx context.Context, cid string, options *pinata.PinLookupOptions) (*pinata.Pin, error)
+	IsPinnedFunc                        func(ctx context.Context, cid string, options *pinata.PinLookupOptions) (bool, error)
+	UpdateFileMetadataFunc              func(fileHash string, options *pinata.PinMetadataUpdateOptions) error
+	UpdateFileMetadataWithContextFunc   func(ctx context.Context, fileHash string, options *pinata.PinMetadataUpdateOptions) error
+	DeleteFileFunc                      func(cid string) error
+	DeleteFileWithContextFunc           func(ctx context.Context, cid string) error
+	UnpinFunc                           func(cid string) error
+	UnpinWithContextFunc                func(ctx context.Context, cid string) error
+	DeleteFileByIDFunc                  func(id string) error
+	DeleteFileByIDWithContextFunc       func(ctx context.Context, id string) error
+	DeleteFilesAsyncFunc                func(cids []string) []error
+	DeleteFilesAsyncWithContextFunc     func(ctx context.Context, cids []string) []error
+	ListFolderContentsFunc              func(ctx context.Context, cid string) ([]pinata.DirEntry, error)
+	ListFolderContentsWithOptionsFunc   func(ctx context.Context, cid string, options *pinata.ListFolderOptions) ([]pinata.DirEntry, error)
+	UpdateFolderFileMetadataFunc        func(ctx context.Context, folder *pinata.PinResponse, files map[string]map[string]interface{}, options *pinata.FolderMetadataOptions) (*pinata.FolderMetadataReport, error)
+	DeleteFolderFunc                    func(ctx context.Context, cid string, options *pinata.DeleteFolderOptions) (*pinata.DeleteFolderReport, error)
+	AuditPinsFunc                       func(ctx context.Context, options *pinata.AuditOptions) (*pinata.AuditReport, error)
+	PinStatsFunc                        func(ctx context.Context, options *pinata.ListFilesOptions) (*pinata.PinStatsReport, error)
+	VectorizeFileFunc                   func(ctx context.Context, fileID string) error
+	DeleteFileVectorsFunc               func(ctx context.Context, fileID string) error
+	QueryVectorsFunc                    func(ctx context.Context, groupID string, text string, topK int) (*pinata.VectorQueryResult, error)
+	CreateGroupFunc                     func(groupName string) (*pinata.Group, error)
+	CreateGroupWithContextFunc          func(ctx context.Context, groupName string) (*pinata.Group, error)
+	ListGroupsFunc                      func(options *pinata.ListGroupsOptions) ([]pinata.Group, error)
+	ListGroupsWithContextFunc           func(ctx context.Context, options *pinata.ListGroupsOptions) ([]pinata.Group, error)
+	ListGroupsPageFunc                  func(options *pinata.ListGroupsOptions) (*pinata.Page[pinata.Group], error)
+	ListGroupsPageWithContextFunc       func(ctx context.Context, options *pinata.ListGroupsOptions) (*pinata.Page[pinata.Group], error)
+	ReconcileGroupsFunc                 func(ctx context.Context, desired map[string][]string, options pinata.ReconcileOptions) (*pinata.ReconcileReport, error)
+	GenerateApiKeyFunc                  func(options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error)
+	GenerateApiKeyWithContextFunc       func(ctx context.Context, options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error)
+	GenerateApiKeyV3Func                func(options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error)
+	ListApiKeysFunc                     func() (*pinata.ApiKeyResponse, error)
+	ListApiKeysWithContextFunc          func(ctx context.Context) (*pinata.ApiKeyResponse, error)
+	ListApiKeyV3Func                    func(options *pinata.ListApiKeysOptions) (*pinata.ApiKeyResponse, error)
+	ListApiKeyV3PageFunc                func(options *pinata.ListApiKeysOptions) (*pinata.Page[pinata.ApiKey], error)
+	RevokeApiKeyFunc                    func(apiKey string) error
+	RevokeApiKeyWithContextFunc         func(ctx context.Context, apiKey string) error
+	RevokeApiKeyV3Func                  func(key string) error
+	AddCidSignatureFunc                 func(cid string, signature string) (*pinata.CidSignature, error)
+	AddCidSignatureWithContextFunc      func(ctx context.Context, cid string, signature string) (*pinata.CidSignature, error)
+	GetCidSignatureFunc                 func(cid string) (*pinata.CidSignature, error)
+	GetCidSignatureWithContextFunc      func(ctx context.Context, cid string) (*pinata.CidSignature, error)
+	RemoveCidSignatureFunc              func(cid string) error
+	RemoveCidSignatureWithContextFunc   func(ctx context.Context, cid string) error
+	AddSwapFunc                         func(cid string, swapCid string, options *pinata.AddSwapOptions) (*pinata.AddSwapResponse, error)
+	AddSwapWithContextFunc              func(ctx context.Context, cid string, swapCid string, options *pinata.AddSwapOptions) (*pinata.AddSwapResponse, error)
+	SwapToFunc                          func(ctx context.Context, cid string, newContentPath string) (*pinata.AddSwapResponse, error)
+	GetSwapHistoryFunc                  func(cid string, domain string) (*pinata.GetSwapResponse, error)
+	GetSwapHistoryWithContextFunc       func(ctx context.Context, cid string, domain string) (*pinata.GetSwapResponse, error)
+	RemoveSwapFunc                      func(cid string) (*pinata.RemoveSwapResponse, error)
+	RemoveSwapWithContextFunc           func(ctx context.Context, cid string) (*pinata.RemoveSwapResponse, error)
+	GetContentFunc                      func(ctx context.Context, cid string) (*pinata.GatewayContent, error)
+	StatContentFunc                     func(ctx context.Context, cid string) (*pinata.GatewayStat, error)
+	HealthCheckFunc                     func(ctx context.Context) []pinata.GatewayHealth
+	TestAuthenticationFunc              func() (*pinata.AuthTestResponse, error)
+	TestAuthenticationWithContextFunc   func(ctx context.Context) (*pinata.AuthTestResponse, error)
+	AuthHealthCheckFunc                 func(ctx context.Context) pinata.AuthHealth
+	GetPinnedDataTotalFunc              func(ctx context.Context) (*pinata.PinnedDataTotal, error)
+	PinnedFileCountFunc                 func() (int, error)
+	PinnedFileCountWithContextFunc      func(ctx context.Context) (int, error)
+	TotalStorageSizeFunc                func() (int, int, error)
+	TotalStorageSizeWithContextFunc     func(ctx context.Context) (int, int, error)
+	LastRateLimitFunc                   func() (pinata.RateLimitInfo, bool)
+	ActiveOperationsFunc                func() []pinata.ActiveOp
+}
+
+var _ pinata.PinataAPI = (*Mock)(nil)
+
+// PinFile calls PinFileFunc.
+func (m *Mock) PinFile(path string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinFile", path, options)
+	if m.PinFileFunc == nil {
+		return nil, notMocked("PinFile")
+	}
+	return m.PinFileFunc(path, options)
+}
+
+// PinFileWithContext calls PinFileWithContextFunc.
+func (m *Mock) PinFileWithContext(ctx context.Context, path string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinFileWithContext", ctx, path, options)
+	if m.PinFileWithContextFunc == nil {
+		return nil, notMocked("PinFileWithContext")
+	}
+	return m.PinFileWithContextFunc(ctx, path, options)
+}
+
+// PinOpenFile calls PinOpenFileFunc.
+func (m *Mock) PinOpenFile(f *os.File, name string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinOpenFile", f, name, options)
+	if m.PinOpenFileFunc == nil {
+		return nil, notMocked("PinOpenFile")
+	}
+	return m.PinOpenFileFunc(f, name, options)
+}
+
+// PinOpenFileWithContext calls PinOpenFileWithContextFunc.
+func (m *Mock) PinOpenFileWithContext(ctx context.Context, f *os.File, name string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinOpenFileWithContext", ctx, f, name, options)
+	if m.PinOpenFileWithContextFunc == nil {
+		return nil, notMocked("PinOpenFileWithContext")
+	}
+	return m.PinOpenFileWithContextFunc(ctx, f, name, options)
+}
+
+// PinFilesAsync calls PinFilesAsyncFunc.
+func (m *Mock) PinFilesAsync(paths []string, options []pinata.PinOptions) ([]*pinata.PinResponse, error) {
+	m.record("PinFilesAsync", paths, options)
+	if m.PinFilesAsyncFunc == nil {
+		return nil, notMocked("PinFilesAsync")
+	}
+	return m.PinFilesAsyncFunc(paths, options)
+}
+
+// PinFilesAsyncWithContext calls PinFilesAsyncWithContextFunc.
+func (m *Mock) PinFilesAsyncWithContext(ctx context.Context, paths []string, options []pinata.PinOptions) ([]*pinata.PinResponse, error) {
+	m.record("PinFilesAsyncWithContext", ctx, paths, options)
+	if m.PinFilesAsyncWithContextFunc == nil {
+		return nil, notMocked("PinFilesAsyncWithContext")
+	}
+	return m.PinFilesAsyncWithContextFunc(ctx, paths, options)
+}
+
+// PinURL calls PinURLFunc.
+func (m *Mock) PinURL(url string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinURL", url, options)
+	if m.PinURLFunc == nil {
+		return nil, notMocked("PinURL")
+	}
+	return m.PinURLFunc(url, options)
+}
+
+// PinURLWithContext calls PinURLWithContextFunc.
+func (m *Mock) PinURLWithContext(ctx context.Context, url string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinURLWithContext", ctx, url, options)
+	if m.PinURLWithContextFunc == nil {
+		return nil, notMocked("PinURLWithContext")
+	}
+	return m.PinURLWithContextFunc(ctx, url, options)
+}
+
+// PinFolder calls PinFolderFunc.
+func (m *Mock) PinFolder(filePaths []string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinFolder", filePaths, options)
+	if m.PinFolderFunc == nil {
+		return nil, notMocked("PinFolder")
+	}
+	return m.PinFolderFunc(filePaths, options)
+}
+
+// PinFolderWithContext calls PinFolderWithContextFunc.
+func (m *Mock) PinFolderWithContext(ctx context.Context, filePaths []string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinFolderWithContext", ctx, filePaths, options)
+	if m.PinFolderWithContextFunc == nil {
+		return nil, notMocked("PinFolderWithContext")
+	}
+	return m.PinFolderWithContextFunc(ctx, filePaths, options)
+}
+
+// PinNestedFolders calls PinNestedFoldersFunc.
+func (m *Mock) PinNestedFolders(baseDir string, paths []string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinNestedFolders", baseDir, paths, options)
+	if m.PinNestedFoldersFunc == nil {
+		return nil, notMocked("PinNestedFolders")
+	}
+	return m.PinNestedFoldersFunc(baseDir, paths, options)
+}
+
+// PinNestedFoldersWithContext calls PinNestedFoldersWithContextFunc.
+func (m *Mock) PinNestedFoldersWithContext(ctx context.Context, baseDir string, paths []string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinNestedFoldersWithContext", ctx, baseDir, paths, options)
+	if m.PinNestedFoldersWithContextFunc == nil {
+		return nil, notMocked("PinNestedFoldersWithContext")
+	}
+	return m.PinNestedFoldersWithContextFunc(ctx, baseDir, paths, options)
+}
+
+// PinJSON calls PinJSONFunc.
+func (m *Mock) PinJSON(data interface{}, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinJSON", data, options)
+	if m.PinJSONFunc == nil {
+		return nil, notMocked("PinJSON")
+	}
+	return m.PinJSONFunc(data, options)
+}
+
+// PinJSONWithContext calls PinJSONWithContextFunc.
+func (m *Mock) PinJSONWithContext(ctx context.Context, data interface{}, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+	m.record("PinJSONWithContext", ctx, data, options)
+	if m.PinJSONWithContextFunc == nil {
+		return nil, notMocked("PinJSONWithContext")
+	}
+	return m.PinJSONWithContextFunc(ctx, data, options)
+}
+
+// PinJSONsAsync calls PinJSONsAsyncFunc.
+func (m *Mock) PinJSONsAsync(ctx context.Context, items []pinata.JSONPinItem, options *pinata.JSONBatchOptions) ([]pinata.JSONPinResult, error) {
+	m.record("PinJSONsAsync", ctx, items, options)
+	if m.PinJSONsAsyncFunc == nil {
+		return nil, notMocked("PinJSONsAsync")
+	}
+	return m.PinJSONsAsyncFunc(ctx, items, options)
+}
+
+// PinReader calls PinReaderFunc.
+func (m *Mock) PinReader(ctx context.Context, r io.Reader, name string, options *pinata.PinOptions, readerOptions *pinata.PinReaderOptions) (*pinata.PinResponse, error) {
+	m.record("PinReader", ctx, r, name, options, readerOptions)
+	if m.PinReaderFunc == nil {
+		return nil, notMocked("PinReader")
+	}
+	return m.PinReaderFunc(ctx, r, name, options, readerOptions)
+}
+
+// PinBatch calls PinBatchFunc.
+func (m *Mock) PinBatch(ctx context.Context, paths []string, options *pinata.BatchOptions) ([]pinata.BatchItemResult, error) {
+	m.record("PinBatch", ctx, paths, options)
+	if m.PinBatchFunc == nil {
+		return nil, notMocked("PinBatch")
+	}
+	return m.PinBatchFunc(ctx, paths, options)
+}
+
+// PinDirectory calls PinDirectoryFunc.
+func (m *Mock) PinDirectory(ctx context.Context, dir string, options *pinata.BatchOptions) ([]pinata.BatchItemResult, error) {
+	m.record("PinDirectory", ctx, dir, options)
+	if m.PinDirectoryFunc == nil {
+		return nil, notMocked("PinDirectory")
+	}
+	return m.PinDirectoryFunc(ctx, dir, options)
+}
+
+// PinLargeDirectory calls PinLargeDirectoryFunc.
+func (m *Mock) PinLargeDirectory(ctx context.Context, dir string, opts pinata.LargeDirOptions) (*pinata.LargeDirResult, error) {
+	m.record("PinLargeDirectory", ctx, dir, opts)
+	if m.PinLargeDirectoryFunc == nil {
+		return nil, notMocked("PinLargeDirectory")
+	}
+	return m.PinLargeDirectoryFunc(ctx, dir, opts)
+}
+
+// MirrorURL calls MirrorURLFunc.
+func (m *Mock) MirrorURL(ctx context.Context, url string, options *pinata.MirrorOptions) (*pinata.MirrorResult, error) {
+	m.record("MirrorURL", ctx, url, options)
+	if m.MirrorURLFunc == nil {
+		return nil, notMocked("MirrorURL")
+	}
+	return m.MirrorURLFunc(ctx, url, options)
+}
+
+// PinByCid calls PinByCidFunc.
+func (m *Mock) PinByCid(hashToPin string, options *pinata.PinByCidOptions) (*pinata.PinByCidResponse, error) {
+	m.record("PinByCid", hashToPin, options)
+	if m.PinByCidFunc == nil {
+		return nil, notMocked("PinByCid")
+	}
+	return m.PinByCidFunc(hashToPin, options)
+}
+
+// PinByCidWithContext calls PinByCidWithContextFunc.
+func (m *Mock) PinByCidWithContext(ctx context.Context, hashToPin string, options *pinata.PinByCidOptions) (*pinata.PinByCidResponse, error) {
+	m.record("PinByCidWithContext", ctx, hashToPin, options)
+	if m.PinByCidWithContextFunc == nil {
+		return nil, notMocked("PinByCidWithContext")
+	}
+	return m.PinByCidWithContextFunc(ctx, hashToPin, options)
+}
+
+// ListPinByCidJobs calls ListPinByCidJobsFunc.
+func (m *Mock) ListPinByCidJobs(options *pinata.ListPinByCidOptions) (*pinata.ListPinByCidResponse, error) {
+	m.record("ListPinByCidJobs", options)
+	if m.ListPinByCidJobsFunc == nil {
+		return nil, notMocked("ListPinByCidJobs")
+	}
+	return m.ListPinByCidJobsFunc(options)
+}
+
+// ListPinByCidJobsWithContext calls ListPinByCidJobsWithContextFunc.
+func (m *Mock) ListPinByCidJobsWithContext(ctx context.Context, options *pinata.ListPinByCidOptions) (*pinata.ListPinByCidResponse, error) {
+	m.record("ListPinByCidJobsWithContext", ctx, options)
+	if m.ListPinByCidJobsWithContextFunc == nil {
+		return nil, notMocked("ListPinByCidJobsWithContext")
+	}
+	return m.ListPinByCidJobsWithContextFunc(ctx, options)
+}
+
+// ListPinByCidJobsPage calls ListPinByCidJobsPageFunc.
+func (m *Mock) ListPinByCidJobsPage(options *pinata.ListPinByCidOptions) (*pinata.Page[pinata.PinEntry], error) {
+	m.record("ListPinByCidJobsPage", options)
+	if m.ListPinByCidJobsPageFunc == nil {
+		return nil, notMocked("ListPinByCidJobsPage")
+	}
+	return m.ListPinByCidJobsPageFunc(options)
+}
+
+// ListPinByCidJobsPageWithContext calls ListPinByCidJobsPageWithContextFunc.
+func (m *Mock) ListPinByCidJobsPageWithContext(ctx context.Context, options *pinata.ListPinByCidOptions) (*pinata.Page[pinata.PinEntry], error) {
+	m.record("ListPinByCidJobsPageWithContext", ctx, options)
+	if m.ListPinByCidJobsPageWithContextFunc == nil {
+		return nil, notMocked("ListPinByCidJobsPageWithContext")
+	}
+	return m.ListPinByCidJobsPageWithContextFunc(ctx, options)
+}
+
+// ListPinByCidJobsAll calls ListPinByCidJobsAllFunc.
+func (m *Mock) ListPinByCidJobsAll(options *pinata.ListPinByCidOptions) ([]pinata.PinEntry, error) {
+	m.record("ListPinByCidJobsAll", options)
+	if m.ListPinByCidJobsAllFunc == nil {
+		return nil, notMocked("ListPinByCidJobsAll")
+	}
+	return m.ListPinByCidJobsAllFunc(options)
+}
+
+// ListPinByCidJobsAllWithContext calls ListPinByCidJobsAllWithContextFunc.
+func (m *Mock) ListPinByCidJobsAllWithContext(ctx context.Context, options *pinata.ListPinByCidOptions) ([]pinata.PinEntry, error) {
+	m.record("ListPinByCidJobsAllWithContext", ctx, options)
+	if m.ListPinByCidJobsAllWithContextFunc == nil {
+		return nil, notMocked("ListPinByCidJobsAllWithContext")
+	}
+	return m.ListPinByCidJobsAllWithContextFunc(ctx, options)
+}
+
+// WatchPinJobs calls WatchPinJobsFunc.
+func (m *Mock) WatchPinJobs(ctx context.Context, interval time.Duration, filter *pinata.ListPinByCidOptions) (<-chan pinata.PinJobEvent, error) {
+	m.record("WatchPinJobs", ctx, interval, filter)
+	if m.WatchPinJobsFunc == nil {
+		return nil, notMocked("WatchPinJobs")
+	}
+	return m.WatchPinJobsFunc(ctx, interval, filter)
+}
+
+// ListFiles calls ListFilesFunc.
+func (m *Mock) ListFiles(options *pinata.ListFilesOptions) (*pinata.ListFilesResponse, error) {
+	m.record("ListFiles", options)
+	if m.ListFilesFunc == nil {
+		return nil, notMocked("ListFiles")
+	}
+	return m.ListFilesFunc(options)
+}
+
+// ListFilesWithContext calls ListFilesWithContextFunc.
+func (m *Mock) ListFilesWithContext(ctx context.Context, options *pinata.ListFilesOptions) (*pinata.ListFilesResponse, error) {
+	m.record("ListFilesWithContext", ctx, options)
+	if m.ListFilesWithContextFunc == nil {
+		return nil, notMocked("ListFilesWithContext")
+	}
+	return m.ListFilesWithContextFunc(ctx, options)
+}
+
+// ListFilesPage calls ListFilesPageFunc.
+func (m *Mock) ListFilesPage(options *pinata.ListFilesOptions) (*pinata.Page[pinata.Pin], error) {
+	m.record("ListFilesPage", options)
+	if m.ListFilesPageFunc == nil {
+		return nil, notMocked("ListFilesPage")
+	}
+	return m.ListFilesPageFunc(options)
+}
+
+// ListFilesPageWithContext calls ListFilesPageWithContextFunc.
+func (m *Mock) ListFilesPageWithContext(ctx context.Context, options *pinata.ListFilesOptions) (*pinata.Page[pinata.Pin], error) {
+	m.record("ListFilesPageWithContext", ctx, options)
+	if m.ListFilesPageWithContextFunc == nil {
+		return nil, notMocked("ListFilesPageWithContext")
+	}
+	return m.ListFilesPageWithContextFunc(ctx, options)
+}
+
+// ListFilesAll calls ListFilesAllFunc.
+func (m *Mock) ListFilesAll(options *pinata.ListFilesOptions) ([]pinata.Pin, error) {
+	m.record("ListFilesAll", options)
+	if m.ListFilesAllFunc == nil {
+		return nil, notMocked("ListFilesAll")
+	}
+	return m.ListFilesAllFunc(options)
+}
+
+// ListFilesAllWithContext calls ListFilesAllWithContextFunc.
+func (m *Mock) ListFilesAllWithContext(ctx context.Context, options *pinata.ListFilesOptions) ([]pinata.Pin, error) {
+	m.record("ListFilesAllWithContext", ctx, options)
+	if m.ListFilesAllWithContextFunc == nil {
+		return nil, notMocked("ListFilesAllWithContext")
+	}
+	return m.ListFilesAllWithContextFunc(ctx, options)
+}
+
+// StreamFiles calls StreamFilesFunc.
+func (m *Mock) StreamFiles(ctx context.Context, options *pinata.ListFilesOptions, buffer int) (<-chan pinata.Pin, <-chan error) {
+	m.record("StreamFiles", ctx, options, buffer)
+	if m.StreamFilesFunc == nil {
+		return notMockedStream[pinata.Pin]("StreamFiles")
+	}
+	return m.StreamFilesFunc(ctx, options, buffer)
+}
+
+// SearchFilesByName calls SearchFilesByNameFunc.
+func (m *Mock) SearchFilesByName(ctx context.Context, options *pinata.NameSearchOptions) (<-chan pinata.Pin, <-chan error) {
+	m.record("SearchFilesByName", ctx, options)
+	if m.SearchFilesByNameFunc == nil {
+		return notMockedStream[pinata.Pin]("SearchFilesByName")
+	}
+	return m.SearchFilesByNameFunc(ctx, options)
+}
+
+// ListUngroupedPins calls ListUngroupedPinsFunc.
+func (m *Mock) ListUngroupedPins(ctx context.Context, options *pinata.UngroupedOptions) (<-chan pinata.Pin, <-chan error) {
+	m.record("ListUngroupedPins", ctx, options)
+	if m.ListUngroupedPinsFunc == nil {
+		return notMockedStream[pinata.Pin]("ListUngroupedPins")
+	}
+	return m.ListUngroupedPinsFunc(ctx, options)
+}
+
+// GetPinByCid calls GetPinByCidFunc.
+func (m *Mock) GetPinByCid(ctx context.Context, cid string, options *pinata.PinLookupOptions) (*pinata.Pin, error) {
+	m.record("GetPinByCid", ctx, cid, options)
+	if m.GetPinByCidFunc == nil {
+		return nil, notMocked("GetPinByCid")
+	}
+	return m.GetPinByCidFunc(ctx, cid, options)
+}
+
+// IsPinned calls IsPinnedFunc.
+func (m *Mock) IsPinned(ctx context.Context, cid string, options *pinata.PinLookupOptions) (bool, error) {
+	m.record("IsPinned", ctx, cid, options)
+	if m.IsPinnedFunc == nil {
+		return false, notMocked("IsPinned")
+	}
+	return m.IsPinnedFunc(ctx, cid, options)
+}
+
+// UpdateFileMetadata calls UpdateFileMetadataFunc.
+func (m *Mock) UpdateFileMetadata(fileHash string, options *pinata.PinMetadataUpdateOptions) error {
+	m.record("UpdateFileMetadata", fileHash, options)
+	if m.UpdateFileMetadataFunc == nil {
+		return notMocked("UpdateFileMetadata")
+	}
+	return m.UpdateFileMetadataFunc(fileHash, options)
+}
+
+// UpdateFileMetadataWithContext calls UpdateFileMetadataWithContextFunc.
+func (m *Mock) UpdateFileMetadataWithContext(ctx context.Context, fileHash string, options *pinata.PinMetadataUpdateOptions) error {
+	m.record("UpdateFileMetadataWithContext", ctx, fileHash, options)
+	if m.UpdateFileMetadataWithContextFunc == nil {
+		return notMocked("UpdateFileMetadataWithContext")
+	}
+	return m.UpdateFileMetadataWithContextFunc(ctx, fileHash, options)
+}
+
+// DeleteFile calls DeleteFileFunc.
+func (m *Mock) DeleteFile(cid string) error {
+	m.record("DeleteFile", cid)
+	if m.DeleteFileFunc == nil {
+		return notMocked("DeleteFile")
+	}
+	return m.DeleteFileFunc(cid)
+}
+
+// DeleteFileWithContext calls DeleteFileWithContextFunc.
+func (m *Mock) DeleteFileWithContext(ctx context.Context, cid string) error {
+	m.record("DeleteFileWithContext", ctx, cid)
+	if m.DeleteFileWithContextFunc == nil {
+		return notMocked("DeleteFileWithContext")
+	}
+	return m.DeleteFileWithContextFunc(ctx, cid)
+}
+
+// Unpin calls UnpinFunc.
+func (m *Mock) Unpin(cid string) error {
+	m.record("Unpin", cid)
+	if m.UnpinFunc == nil {
+		return notMocked("Unpin")
+	}
+	return m.UnpinFunc(cid)
+}
+
+// UnpinWithContext calls UnpinWithContextFunc.
+func (m *Mock) UnpinWithContext(ctx context.Context, cid string) error {
+	m.record("UnpinWithContext", ctx, cid)
+	if m.UnpinWithContextFunc == nil {
+		return notMocked("UnpinWithContext")
+	}
+	return m.UnpinWithContextFunc(ctx, cid)
+}
+
+// DeleteFileByID calls DeleteFileByIDFunc.
+func (m *Mock) DeleteFileByID(id string) error {
+	m.record("DeleteFileByID", id)
+	if m.DeleteFileByIDFunc == nil {
+		return notMocked("DeleteFileByID")
+	}
+	return m.DeleteFileByIDFunc(id)
+}
+
+// DeleteFileByIDWithContext calls DeleteFileByIDWithContextFunc.
+func (m *Mock) DeleteFileByIDWithContext(ctx context.Context, id string) error {
+	m.record("DeleteFileByIDWithContext", ctx, id)
+	if m.DeleteFileByIDWithContextFunc == nil {
+		return notMocked("DeleteFileByIDWithContext")
+	}
+	return m.DeleteFileByIDWithContextFunc(ctx, id)
+}
+
+// DeleteFilesAsync calls DeleteFilesAsyncFunc.
+func (m *Mock) DeleteFilesAsync(cids []string) []error {
+	m.record("DeleteFilesAsync", cids)
+	if m.DeleteFilesAsyncFunc == nil {
+		return notMockedErrors("DeleteFilesAsync", len(cids))
+	}
+	return m.DeleteFilesAsyncFunc(cids)
+}
+
+// DeleteFilesAsyncWithContext calls DeleteFilesAsyncWithContextFunc.
+func (m *Mock) DeleteFilesAsyncWithContext(ctx context.Context, cids []string) []error {
+	m.record("DeleteFilesAsyncWithContext", ctx, cids)
+	if m.DeleteFilesAsyncWithContextFunc == nil {
+		return notMockedErrors("DeleteFilesAsyncWithContext", len(cids))
+	}
+	return m.DeleteFilesAsyncWithContextFunc(ctx, cids)
+}
+
+// ListFolderContents calls ListFolderContentsFunc.
+func (m *Mock) ListFolderContents(ctx context.Context, cid string) ([]pinata.DirEntry, error) {
+	m.record("ListFolderContents", ctx, cid)
+	if m.ListFolderContentsFunc == nil {
+		return nil, notMocked("ListFolderContents")
+	}
+	return m.ListFolderContentsFunc(ctx, cid)
+}
+
+// ListFolderContentsWithOptions calls ListFolderContentsWithOptionsFunc.
+func (m *Mock) ListFolderContentsWithOptions(ctx context.Context, cid string, options *pinata.ListFolderOptions) ([]pinata.DirEntry, error) {
+	m.record("ListFolderContentsWithOptions", ctx, cid, options)
+	if m.ListFolderContentsWithOptionsFunc == nil {
+		return nil, notMocked("ListFolderContentsWithOptions")
+	}
+	return m.ListFolderContentsWithOptionsFunc(ctx, cid, options)
+}
+
+// UpdateFolderFileMetadata calls UpdateFolderFileMetadataFunc.
+func (m *Mock) UpdateFolderFileMetadata(ctx context.Context, folder *pinata.PinResponse, files map[string]map[string]interface{}, options *pinata.FolderMetadataOptions) (*pinata.FolderMetadataReport, error) {
+	m.record("UpdateFolderFileMetadata", ctx, folder, files, options)
+	if m.UpdateFolderFileMetadataFunc == nil {
+		return nil, notMocked("UpdateFolderFileMetadata")
+	}
+	return m.UpdateFolderFileMetadataFunc(ctx, folder, files, options)
+}
+
+// DeleteFolder calls DeleteFolderFunc.
+func (m *Mock) DeleteFolder(ctx context.Context, cid string, options *pinata.DeleteFolderOptions) (*pinata.DeleteFolderReport, error) {
+	m.record("DeleteFolder", ctx, cid, options)
+	if m.DeleteFolderFunc == nil {
+		return nil, notMocked("DeleteFolder")
+	}
+	return m.DeleteFolderFunc(ctx, cid, options)
+}
+
+// AuditPins calls AuditPinsFunc.
+func (m *Mock) AuditPins(ctx context.Context, options *pinata.AuditOptions) (*pinata.AuditReport, error) {
+	m.record("AuditPins", ctx, options)
+	if m.AuditPinsFunc == nil {
+		return nil, notMocked("AuditPins")
+	}
+	return m.AuditPinsFunc(ctx, options)
+}
+
+// PinStats calls PinStatsFunc.
+func (m *Mock) PinStats(ctx context.Context, options *pinata.ListFilesOptions) (*pinata.PinStatsReport, error) {
+	m.record("PinStats", ctx, options)
+	if m.PinStatsFunc == nil {
+		return nil, notMocked("PinStats")
+	}
+	return m.PinStatsFunc(ctx, options)
+}
+
+// VectorizeFile calls VectorizeFileFunc.
+func (m *Mock) VectorizeFile(ctx context.Context, fileID string) error {
+	m.record("VectorizeFile", ctx, fileID)
+	if m.VectorizeFileFunc == nil {
+		return notMocked("VectorizeFile")
+	}
+	return m.VectorizeFileFunc(ctx, fileID)
+}
+
+// DeleteFileVectors calls DeleteFileVectorsFunc.
+func (m *Mock) DeleteFileVectors(ctx context.Context, fileID string) error {
+	m.record("DeleteFileVectors", ctx, fileID)
+	if m.DeleteFileVectorsFunc == nil {
+		return notMocked("DeleteFileVectors")
+	}
+	return m.DeleteFileVectorsFunc(ctx, fileID)
+}
+
+// QueryVectors calls QueryVectorsFunc.
+func (m *Mock) QueryVectors(ctx context.Context, groupID string, text string, topK int) (*pinata.VectorQueryResult, error) {
+	m.record("QueryVectors", ctx, groupID, text, topK)
+	if m.QueryVectorsFunc == nil {
+		return nil, notMocked("QueryVectors")
+	}
+	return m.QueryVectorsFunc(ctx, groupID, text, topK)
+}
+
+// CreateGroup calls CreateGroupFunc.
+func (m *Mock) CreateGroup(groupName string) (*pinata.Group, error) {
+	m.record("CreateGroup", groupName)
+	if m.CreateGroupFunc == nil {
+		return nil, notMocked("CreateGroup")
+	}
+	return m.CreateGroupFunc(groupName)
+}
+
+// CreateGroupWithContext calls CreateGroupWithContextFunc.
+func (m *Mock) CreateGroupWithContext(ctx context.Context, groupName string) (*pinata.Group, error) {
+	m.record("CreateGroupWithContext", ctx, groupName)
+	if m.CreateGroupWithContextFunc == nil {
+		return nil, notMocked("CreateGroupWithContext")
+	}
+	return m.CreateGroupWithContextFunc(ctx, groupName)
+}
+
+// ListGroups calls ListGroupsFunc.
+func (m *Mock) ListGroups(options *pinata.ListGroupsOptions) ([]pinata.Group, error) {
+	m.record("ListGroups", options)
+	if m.ListGroupsFunc == nil {
+		return nil, notMocked("ListGroups")
+	}
+	return m.ListGroupsFunc(options)
+}
+
+// ListGroupsWithContext calls ListGroupsWithContextFunc.
+func (m *Mock) ListGroupsWithContext(ctx context.Context, options *pinata.ListGroupsOptions) ([]pinata.Group, error) {
+	m.record("ListGroupsWithContext", ctx, options)
+	if m.ListGroupsWithContextFunc == nil {
+		return nil, notMocked("ListGroupsWithContext")
+	}
+	return m.ListGroupsWithContextFunc(ctx, options)
+}
+
+// ListGroupsPage calls ListGroupsPageFunc.
+func (m *Mock) ListGroupsPage(options *pinata.ListGroupsOptions) (*pinata.Page[pinata.Group], error) {
+	m.record("ListGroupsPage", options)
+	if m.ListGroupsPageFunc == nil {
+		return nil, notMocked("ListGroupsPage")
+	}
+	return m.ListGroupsPageFunc(options)
+}
+
+// ListGroupsPageWithContext calls ListGroupsPageWithContextFunc.
+func (m *Mock) ListGroupsPageWithContext(ctx context.Context, options *pinata.ListGroupsOptions) (*pinata.Page[pinata.Group], error) {
+	m.record("ListGroupsPageWithContext", ctx, options)
+	if m.ListGroupsPageWithContextFunc == nil {
+		return nil, notMocked("ListGroupsPageWithContext")
+	}
+	return m.ListGroupsPageWithContextFunc(ctx, options)
+}
+
+// ReconcileGroups calls ReconcileGroupsFunc.
+func (m *Mock) ReconcileGroups(ctx context.Context, desired map[string][]string, options pinata.ReconcileOptions) (*pinata.ReconcileReport, error) {
+	m.record("ReconcileGroups", ctx, desired, options)
+	if m.ReconcileGroupsFunc == nil {
+		return nil, notMocked("ReconcileGroups")
+	}
+	return m.ReconcileGroupsFunc(ctx, desired, options)
+}
+
+// GenerateApiKey calls GenerateApiKeyFunc.
+func (m *Mock) GenerateApiKey(options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error) {
+	m.record("GenerateApiKey", options)
+	if m.GenerateApiKeyFunc == nil {
+		return nil, notMocked("GenerateApiKey")
+	}
+	return m.GenerateApiKeyFunc(options)
+}
+
+// GenerateApiKeyWithContext calls GenerateApiKeyWithContextFunc.
+func (m *Mock) GenerateApiKeyWithContext(ctx context.Context, options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error) {
+	m.record("GenerateApiKeyWithContext", ctx, options)
+	if m.GenerateApiKeyWithContextFunc == nil {
+		return nil, notMocked("GenerateApiKeyWithContext")
+	}
+	return m.GenerateApiKeyWithContextFunc(ctx, options)
+}
+
+// GenerateApiKeyV3 calls GenerateApiKeyV3Func.
+func (m *Mock) GenerateApiKeyV3(options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error) {
+	m.record("GenerateApiKeyV3", options)
+	if m.GenerateApiKeyV3Func == nil {
+		return nil, notMocked("GenerateApiKeyV3")
+	}
+	return m.GenerateApiKeyV3Func(options)
+}
+
+// ListApiKeys calls ListApiKeysFunc.
+func (m *Mock) ListApiKeys() (*pinata.ApiKeyResponse, error) {
+	m.record("ListApiKeys")
+	if m.ListApiKeysFunc == nil {
+		return nil, notMocked("ListApiKeys")
+	}
+	return m.ListApiKeysFunc()
+}
+
+// ListApiKeysWithContext calls ListApiKeysWithContextFunc.
+func (m *Mock) ListApiKeysWithContext(ctx context.Context) (*pinata.ApiKeyResponse, error) {
+	m.record("ListApiKeysWithContext", ctx)
+	if m.ListApiKeysWithContextFunc == nil {
+		return nil, notMocked("ListApiKeysWithContext")
+	}
+	return m.ListApiKeysWithContextFunc(ctx)
+}
+
+// ListApiKeyV3 calls ListApiKeyV3Func.
+func (m *Mock) ListApiKeyV3(options *pinata.ListApiKeysOptions) (*pinata.ApiKeyResponse, error) {
+	m.record("ListApiKeyV3", options)
+	if m.ListApiKeyV3Func == nil {
+		return nil, notMocked("ListApiKeyV3")
+	}
+	return m.ListApiKeyV3Func(options)
+}
+
+// ListApiKeyV3Page calls ListApiKeyV3PageFunc.
+func (m *Mock) ListApiKeyV3Page(options *pinata.ListApiKeysOptions) (*pinata.Page[pinata.ApiKey], error) {
+	m.record("ListApiKeyV3Page", options)
+	if m.ListApiKeyV3PageFunc == nil {
+		return nil, notMocked("ListApiKeyV3Page")
+	}
+	return m.ListApiKeyV3PageFunc(options)
+}
+
+// RevokeApiKey calls RevokeApiKeyFunc.
+func (m *Mock) RevokeApiKey(apiKey string) error {
+	m.record("RevokeApiKey", apiKey)
+	if m.RevokeApiKeyFunc == nil {
+		return notMocked("RevokeApiKey")
+	}
+	return m.RevokeApiKeyFunc(apiKey)
+}
+
+// RevokeApiKeyWithContext calls RevokeApiKeyWithContextFunc.
+func (m *Mock) RevokeApiKeyWithContext(ctx context.Context, apiKey string) error {
+	m.record("RevokeApiKeyWithContext", ctx, apiKey)
+	if m.RevokeApiKeyWithContextFunc == nil {
+		return notMocked("RevokeApiKeyWithContext")
+	}
+	return m.RevokeApiKeyWithContextFunc(ctx, apiKey)
+}
+
+// RevokeApiKeyV3 calls RevokeApiKeyV3Func.
+func (m *Mock) RevokeApiKeyV3(key string) error {
+	m.record("RevokeApiKeyV3", key)
+	if m.RevokeApiKeyV3Func == nil {
+		return notMocked("RevokeApiKeyV3")
+	}
+	return m.RevokeApiKeyV3Func(key)
+}
+
+// AddCidSignature calls AddCidSignatureFunc.
+func (m *Mock) AddCidSignature(cid string, signature string) (*pinata.CidSignature, error) {
+	m.record("AddCidSignature", cid, signature)
+	if m.AddCidSignatureFunc == nil {
+		return nil, notMocked("AddCidSignature")
+	}
+	return m.AddCidSignatureFunc(cid, signature)
+}
+
+// AddCidSignatureWithContext calls AddCidSignatureWithContextFunc.
+func (m *Mock) AddCidSignatureWithContext(ctx context.Context, cid string, signature string) (*pinata.CidSignature, error) {
+	m.record("AddCidSignatureWithContext", ctx, cid, signature)
+	if m.AddCidSignatureWithContextFunc == nil {
+		return nil, notMocked("AddCidSignatureWithContext")
+	}
+	return m.AddCidSignatureWithContextFunc(ctx, cid, signature)
+}
+
+// GetCidSignature calls GetCidSignatureFunc.
+func (m *Mock) GetCidSignature(cid string) (*pinata.CidSignature, error) {
+	m.record("GetCidSignature", cid)
+	if m.GetCidSignatureFunc == nil {
+		return nil, notMocked("GetCidSignature")
+	}
+	return m.GetCidSignatureFunc(cid)
+}
+
+// GetCidSignatureWithContext calls GetCidSignatureWithContextFunc.
+func (m *Mock) GetCidSignatureWithContext(ctx context.Context, cid string) (*pinata.CidSignature, error) {
+	m.record("GetCidSignatureWithContext", ctx, cid)
+	if m.GetCidSignatureWithContextFunc == nil {
+		return nil, notMocked("GetCidSignatureWithContext")
+	}
+	return m.GetCidSignatureWithContextFunc(ctx, cid)
+}
+
+// RemoveCidSignature calls RemoveCidSignatureFunc.
+func (m *Mock) RemoveCidSignature(cid string) error {
+	m.record("RemoveCidSignature", cid)
+	if m.RemoveCidSignatureFunc == nil {
+		return notMocked("RemoveCidSignature")
+	}
+	return m.RemoveCidSignatureFunc(cid)
+}
+
+// RemoveCidSignatureWithContext calls RemoveCidSignatureWithContextFunc.
+func (m *Mock) RemoveCidSignatureWithContext(ctx context.Context, cid string) error {
+	m.record("RemoveCidSignatureWithContext", ctx, cid)
+	if m.RemoveCidSignatureWithContextFunc == nil {
+		return notMocked("RemoveCidSignatureWithContext")
+	}
+	return m.RemoveCidSignatureWithContextFunc(ctx, cid)
+}
+
+// AddSwap calls AddSwapFunc.
+func (m *Mock) AddSwap(cid string, swapCid string, options *pinata.AddSwapOptions) (*pinata.AddSwapResponse, error) {
+	m.record("AddSwap", cid, swapCid, options)
+	if m.AddSwapFunc == nil {
+		return nil, notMocked("AddSwap")
+	}
+	return m.AddSwapFunc(cid, swapCid, options)
+}
+
+// AddSwapWithContext calls AddSwapWithContextFunc.
+func (m *Mock) AddSwapWithContext(ctx context.Context, cid string, swapCid string, options *pinata.AddSwapOptions) (*pinata.AddSwapResponse, error) {
+	m.record("AddSwapWithContext", ctx, cid, swapCid, options)
+	if m.AddSwapWithContextFunc == nil {
+		return nil, notMocked("AddSwapWithContext")
+	}
+	return m.AddSwapWithContextFunc(ctx, cid, swapCid, options)
+}
+
+// SwapTo calls SwapToFunc.
+func (m *Mock) SwapTo(ctx context.Context, cid string, newContentPath string) (*pinata.AddSwapResponse, error) {
+	m.record("SwapTo", ctx, cid, newContentPath)
+	if m.SwapToFunc == nil {
+		return nil, notMocked("SwapTo")
+	}
+	return m.SwapToFunc(ctx, cid, newContentPath)
+}
+
+// GetSwapHistory calls GetSwapHistoryFunc.
+func (m *Mock) GetSwapHistory(cid string, domain string) (*pinata.GetSwapResponse, error) {
+	m.record("GetSwapHistory", cid, domain)
+	if m.GetSwapHistoryFunc == nil {
+		return nil, notMocked("GetSwapHistory")
+	}
+	return m.GetSwapHistoryFunc(cid, domain)
+}
+
+// GetSwapHistoryWithContext calls GetSwapHistoryWithContextFunc.
+func (m *Mock) GetSwapHistoryWithContext(ctx context.Context, cid string, domain string) (*pinata.GetSwapResponse, error) {
+	m.record("GetSwapHistoryWithContext", ctx, cid, domain)
+	if m.GetSwapHistoryWithContextFunc == nil {
+		return nil, notMocked("GetSwapHistoryWithContext")
+	}
+	return m.GetSwapHistoryWithContextFunc(ctx, cid, domain)
+}
+
+// RemoveSwap calls RemoveSwapFunc.
+func (m *Mock) RemoveSwap(cid string) (*pinata.RemoveSwapResponse, error) {
+	m.record("RemoveSwap", cid)
+	if m.RemoveSwapFunc == nil {
+		return nil, notMocked("RemoveSwap")
+	}
+	return m.RemoveSwapFunc(cid)
+}
+
+// RemoveSwapWithContext calls RemoveSwapWithContextFunc.
+func (m *Mock) RemoveSwapWithContext(ctx context.Context, cid string) (*pinata.RemoveSwapResponse, error) {
+	m.record("RemoveSwapWithContext", ctx, cid)
+	if m.RemoveSwapWithContextFunc == nil {
+		return nil, notMocked("RemoveSwapWithContext")
+	}
+	return m.RemoveSwapWithContextFunc(ctx, cid)
+}
+
+// GetContent calls GetContentFunc.
+func (m *Mock) GetContent(ctx context.Context, cid string) (*pinata.GatewayContent, error) {
+	m.record("GetContent", ctx, cid)
+	if m.GetContentFunc == nil {
+		return nil, notMocked("GetContent")
+	}
+	return m.GetContentFunc(ctx, cid)
+}
+
+// StatContent calls StatContentFunc.
+func (m *Mock) StatContent(ctx context.Context, cid string) (*pinata.GatewayStat, error) {
+	m.record("StatContent", ctx, cid)
+	if m.StatContentFunc == nil {
+		return nil, notMocked("StatContent")
+	}
+	return m.StatContentFunc(ctx, cid)
+}
+
+// HealthCheck calls HealthCheckFunc.
+func (m *Mock) HealthCheck(ctx context.Context) []pinata.GatewayHealth {
+	m.record("HealthCheck", ctx)
+	if m.HealthCheckFunc == nil {
+		return nil
+	}
+	return m.HealthCheckFunc(ctx)
+}
+
+// TestAuthentication calls TestAuthenticationFunc.
+func (m *Mock) TestAuthentication() (*pinata.AuthTestResponse, error) {
+	m.record("TestAuthentication")
+	if m.TestAuthenticationFunc == nil {
+		return nil, notMocked("TestAuthentication")
+	}
+	return m.TestAuthenticationFunc()
+}
+
+// TestAuthenticationWithContext calls TestAuthenticationWithContextFunc.
+func (m *Mock) TestAuthenticationWithContext(ctx context.Context) (*pinata.AuthTestResponse, error) {
+	m.record("TestAuthenticationWithContext", ctx)
+	if m.TestAuthenticationWithContextFunc == nil {
+		return nil, notMocked("TestAuthenticationWithContext")
+	}
+	return m.TestAuthenticationWithContextFunc(ctx)
+}
+
+// AuthHealthCheck calls AuthHealthCheckFunc.
+func (m *Mock) AuthHealthCheck(ctx context.Context) pinata.AuthHealth {
+	m.record("AuthHealthCheck", ctx)
+	if m.AuthHealthCheckFunc == nil {
+		return pinata.AuthHealth{}
+	}
+	return m.AuthHealthCheckFunc(ctx)
+}
+
+// GetPinnedDataTotal calls GetPinnedDataTotalFunc.
+func (m *Mock) GetPinnedDataTotal(ctx context.Context) (*pinata.PinnedDataTotal, error) {
+	m.record("GetPinnedDataTotal", ctx)
+	if m.GetPinnedDataTotalFunc == nil {
+		return nil, notMocked("GetPinnedDataTotal")
+	}
+	return m.GetPinnedDataTotalFunc(ctx)
+}
+
+// PinnedFileCount calls PinnedFileCountFunc.
+func (m *Mock) PinnedFileCount() (int, error) {
+	m.record("PinnedFileCount")
+	if m.PinnedFileCountFunc == nil {
+		return 0, notMocked("PinnedFileCount")
+	}
+	return m.PinnedFileCountFunc()
+}
+
+// PinnedFileCountWithContext calls PinnedFileCountWithContextFunc.
+func (m *Mock) PinnedFileCountWithContext(ctx context.Context) (int, error) {
+	m.record("PinnedFileCountWithContext", ctx)
+	if m.PinnedFileCountWithContextFunc == nil {
+		return 0, notMocked("PinnedFileCountWithContext")
+	}
+	return m.PinnedFileCountWithContextFunc(ctx)
+}
+
+// TotalStorageSize calls TotalStorageSizeFunc.
+func (m *Mock) TotalStorageSize() (int, int, error) {
+	m.record("TotalStorageSize")
+	if m.TotalStorageSizeFunc == nil {
+		return 0, 0, notMocked("TotalStorageSize")
+	}
+	return m.TotalStorageSizeFunc()
+}
+
+// TotalStorageSizeWithContext calls TotalStorageSizeWithContextFunc.
+func (m *Mock) TotalStorageSizeWithContext(ctx context.Context) (int, int, error) {
+	m.record("TotalStorageSizeWithContext", ctx)
+	if m.TotalStorageSizeWithContextFunc == nil {
+		return 0, 0, notMocked("TotalStorageSizeWithContext")
+	}
+	return m.TotalStorageSizeWithContextFunc(ctx)
+}
+
+// LastRateLimit calls LastRateLimitFunc.
+func (m *Mock) LastRateLimit() (pinata.RateLimitInfo, bool) {
+	m.record("LastRateLimit")
+	if m.LastRateLimitFunc == nil {
+		return pinata.RateLimitInfo{}, false
+	}
+	return m.LastRateLimitFunc()
+}
+
+// ActiveOperations calls ActiveOperationsFunc.
+func (m *Mock) ActiveOperations() []pinata.ActiveOp {
+	m.record("ActiveOperations")
+	if m.ActiveOperationsFunc == nil {
+		return nil
+	}
+	return m.ActiveOperationsFunc()
+}
+
+// GroupsMock is a fake pinata.GroupsAPI, standing in for a *pinata.GroupsClient in tests.
+// Each method records its call, see Calls, and calls the field named after it with a Func
+// suffix, such as CreateFunc for Create; when the field is nil, the method fails with an
+// error wrapping ErrNotMocked. Set the fields before the first call; the mock is then safe
+// for concurrent use.
+type GroupsMock struct {
+	recorder
+
+	CreateFunc                func(name string) (*pinata.Group, error)
+	CreateWithContextFunc     func(ctx context.Context, name string) (*pinata.Group, error)
+	GetFunc                   func(id pinata.GroupID) (*pinata.Group, error)
+	GetWithContextFunc        func(ctx context.Context, id pinata.GroupID) (*pinata.Group, error)
+	ListFunc                  func(options *pinata.ListGroupsOptions) ([]pinata.Group, error)
+	ListWithContextFunc       func(ctx context.Context, options *pinata.ListGroupsOptions) ([]pinata.Group, error)
+	UpdateFunc                func(id pinata.GroupID, name string) (*pinata.Group, error)
+	UpdateWithContextFunc     func(ctx context.Context, id pinata.GroupID, name string) (*pinata.Group, error)
+	AddCidsFunc               func(id pinata.GroupID, cids []pinata.Cid) error
+	AddCidsWithContextFunc    func(ctx context.Context, id pinata.GroupID, cids []pinata.Cid) error
+	RemoveCidsFunc            func(id pinata.GroupID, cids []pinata.Cid) error
+	RemoveCidsWithContextFunc func(ctx context.Context, id pinata.GroupID, cids []pinata.Cid) error
+	RemoveFunc                func(id pinata.GroupID) error
+	RemoveWithContextFunc     func(ctx context.Context, id pinata.GroupID) error
+	PinCidFunc                func(ctx context.Context, cid pinata.Cid, id pinata.GroupID, metadata *pinata.PinataMetadata, wait bool) (*pinata.PinByCidResponse, error)
+}
+
+var _ pinata.GroupsAPI = (*GroupsMock)(nil)
+
+// Create calls CreateFunc.
+func (m *GroupsMock) Create(name string) (*pinata.Group, error) {
+	m.record("Create", name)
+	if m.CreateFunc == nil {
+		return nil, notMocked("Create")
+	}
+	return m.CreateFunc(name)
+}
+
+// CreateWithContext calls CreateWithContextFunc.
+func (m *GroupsMock) CreateWithContext(ctx context.Context, name string) (*pinata.Group, error) {
+	m.record("CreateWithContext", ctx, name)
+	if m.CreateWithContextFunc == nil {
+		return nil, notMocked("CreateWithContext")
+	}
+	return m.CreateWithContextFunc(ctx, name)
+}
+
+// Get calls GetFunc.
+func (m *GroupsMock) Get(id pinata.GroupID) (*pinata.Group, error) {
+	m.record("Get", id)
+	if m.GetFunc == nil {
+		return nil, notMocked("Get")
+	}
+	return m.GetFunc(id)
+}
+
+// GetWithContext calls GetWithContextFunc.
+func (m *GroupsMock) GetWithContext(ctx context.Context, id pinata.GroupID) (*pinata.Group, error) {
+	m.record("GetWithContext", ctx, id)
+	if m.GetWithContextFunc == nil {
+		return nil, notMocked("GetWithContext")
+	}
+	return m.GetWithContextFunc(ctx, id)
+}
+
+// List calls ListFunc.
+func (m *GroupsMock) List(options *pinata.ListGroupsOptions) ([]pinata.Group, error) {
+	m.record("List", options)
+	if m.ListFunc == nil {
+		return nil, notMocked("List")
+	}
+	return m.ListFunc(options)
+}
+
+// ListWithContext calls ListWithContextFunc.
+func (m *GroupsMock) ListWithContext(ctx context.Context, options *pinata.ListGroupsOptions) ([]pinata.Group, error) {
+	m.record("ListWithContext", ctx, options)
+	if m.ListWithContextFunc == nil {
+		return nil, notMocked("ListWithContext")
+	}
+	return m.ListWithContextFunc(ctx, options)
+}
+
+// Update calls UpdateFunc.
+func (m *GroupsMock) Update(id pinata.GroupID, name string) (*pinata.Group, error) {
+	m.record("Update", id, name)
+	if m.UpdateFunc == nil {
+		return nil, notMocked("Update")
+	}
+	return m.UpdateFunc(id, name)
+}
+
+// UpdateWithContext calls UpdateWithContextFunc.
+func (m *GroupsMock) UpdateWithContext(ctx context.Context, id pinata.GroupID, name string) (*pinata.Group, error) {
+	m.record("UpdateWithContext", ctx, id, name)
+	if m.UpdateWithContextFunc == nil {
+		return nil, notMocked("UpdateWithContext")
+	}
+	return m.UpdateWithContextFunc(ctx, id, name)
+}
+
+// AddCids calls AddCidsFunc.
+func (m *GroupsMock) AddCids(id pinata.GroupID, cids []pinata.Cid) error {
+	m.record("AddCids", id, cids)
+	if m.AddCidsFunc == nil {
+		return notMocked("AddCids")
+	}
+	return m.AddCidsFunc(id, cids)
+}
+
+// AddCidsWithContext calls AddCidsWithContextFunc.
+func (m *GroupsMock) AddCidsWithContext(ctx context.Context, id pinata.GroupID, cids []pinata.Cid) error {
+	m.record("AddCidsWithContext", ctx, id, cids)
+	if m.AddCidsWithContextFunc == nil {
+		return notMocked("AddCidsWithContext")
+	}
+	return m.AddCidsWithContextFunc(ctx, id, cids)
+}
+
+// RemoveCids calls RemoveCidsFunc.
+func (m *GroupsMock) RemoveCids(id pinata.GroupID, cids []pinata.Cid) error {
+	m.record("RemoveCids", id, cids)
+	if m.RemoveCidsFunc == nil {
+		return notMocked("RemoveCids")
+	}
+	return m.RemoveCidsFunc(id, cids)
+}
+
+// RemoveCidsWithContext calls RemoveCidsWithContextFunc.
+func (m *GroupsMock) RemoveCidsWithContext(ctx context.Context, id pinata.GroupID, cids []pinata.Cid) error {
+	m.record("RemoveCidsWithContext", ctx, id, cids)
+	if m.RemoveCidsWithContextFunc == nil {
+		return notMocked("RemoveCidsWithContext")
+	}
+	return m.RemoveCidsWithContextFunc(ctx, id, cids)
+}
+
+// Remove calls RemoveFunc.
+func (m *GroupsMock) Remove(id pinata.GroupID) error {
+	m.record("Remove", id)
+	if m.RemoveFunc == nil {
+		return notMocked("Remove")
+	}
+	return m.RemoveFunc(id)
+}
+
+// RemoveWithContext calls RemoveWithContextFunc.
+func (m *GroupsMock) RemoveWithContext(ctx context.Context, id pinata.GroupID) error {
+	m.record("RemoveWithContext", ctx, id)
+	if m.RemoveWithContextFunc == nil {
+		return notMocked("RemoveWithContext")
+	}
+	return m.RemoveWithContextFunc(ctx, id)
+}
+
+// PinCid calls PinCidFunc.
+func (m *GroupsMock) PinCid(ctx context.Context, cid pinata.Cid, id pinata.GroupID, metadata *pinata.PinataMetadata, wait bool) (*pinata.PinByCidResponse, error) {
+	m.record("PinCid", ctx, cid, id, metadata, wait)
+	if m.PinCidFunc == nil {
+		return nil, notMocked("PinCid")
+	}
+	return m.PinCidFunc(ctx, cid, id, metadata, wait)
+}
+
+// KeysMock is a fake pinata.KeysAPI, standing in for a *pinata.KeysClient in tests.
+// Each method records its call, see Calls, and calls the field named after it with a Func
+// suffix, such as GenerateFunc for Generate; when the field is nil, the method fails with an
+// error wrapping ErrNotMocked. Set the fields before the first call; the mock is then safe
+// for concurrent use.
+type KeysMock struct {
+	recorder
+
+	GenerateFunc            func(options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error)
+	GenerateWithContextFunc func(ctx context.Context, options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error)
+	ListFunc                func(options *pinata.ListApiKeysOptions) (*pinata.ApiKeyResponse, error)
+	ListWithContextFunc     func(ctx context.Context, options *pinata.ListApiKeysOptions) (*pinata.ApiKeyResponse, error)
+	RevokeFunc              func(key string) error
+	RevokeWithContextFunc   func(ctx context.Context, key string) error
+	ListPageFunc            func(options *pinata.ListApiKeysOptions) (*pinata.Page[pinata.ApiKey], error)
+	ListPageWithContextFunc func(ctx context.Context, options *pinata.ListApiKeysOptions) (*pinata.Page[pinata.ApiKey], error)
+}
+
+var _ pinata.KeysAPI = (*KeysMock)(nil)
+
+// Generate calls GenerateFunc.
+func (m *KeysMock) Generate(options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error) {
+	m.record("Generate", options)
+	if m.GenerateFunc == nil {
+		return nil, notMocked("Generate")
+	}
+	return m.GenerateFunc(options)
+}
+
+// GenerateWithContext calls GenerateWithContextFunc.
+func (m *KeysMock) GenerateWithContext(ctx context.Context, options *pinata.GenerateApiKeyOptions) (*pinata.ApiKeySecret, error) {
+	m.record("GenerateWithContext", ctx, options)
+	if m.GenerateWithContextFunc == nil {
+		return nil, notMocked("GenerateWithContext")
+	}
+	return m.GenerateWithContextFunc(ctx, options)
+}
+
+// List calls ListFunc.
+func (m *KeysMock) List(options *pinata.ListApiKeysOptions) (*pinata.ApiKeyResponse, error) {
+	m.record("List", options)
+	if m.ListFunc == nil {
+		return nil, notMocked("List")
+	}
+	return m.ListFunc(options)
+}
+
+// ListWithContext calls ListWithContextFunc.
+func (m *KeysMock) ListWithContext(ctx context.Context, options *pinata.ListApiKeysOptions) (*pinata.ApiKeyResponse, error) {
+	m.record("ListWithContext", ctx, options)
+	if m.ListWithContextFunc == nil {
+		return nil, notMocked("ListWithContext")
+	}
+	return m.ListWithContextFunc(ctx, options)
+}
+
+// Revoke calls RevokeFunc.
+func (m *KeysMock) Revoke(key string) error {
+	m.record("Revoke", key)
+	if m.RevokeFunc == nil {
+		return notMocked("Revoke")
+	}
+	return m.RevokeFunc(key)
+}
+
+// RevokeWithContext calls RevokeWithContextFunc.
+func (m *KeysMock) RevokeWithContext(ctx context.Context, key string) error {
+	m.record("RevokeWithContext", ctx, key)
+	if m.RevokeWithContextFunc == nil {
+		return notMocked("RevokeWithContext")
+	}
+	return m.RevokeWithContextFunc(ctx, key)
+}
+
+// ListPage calls ListPageFunc.
+func (m *KeysMock) ListPage(options *pinata.ListApiKeysOptions) (*pinata.Page[pinata.ApiKey], error) {
+	m.record("ListPage", options)
+	if m.ListPageFunc == nil {
+		return nil, notMocked("ListPage")
+	}
+	return m.ListPageFunc(options)
+}
+
+// ListPageWithContext calls ListPageWithContextFunc.
+func (m *KeysMock) ListPageWithContext(ctx context.Context, options *pinata.ListApiKeysOptions) (*pinata.Page[pinata.ApiKey], error) {
+	m.record("ListPageWithContext", ctx, options)
+	if m.ListPageWithContextFunc == nil {
+		return nil, notMocked("ListPageWithContext")
+	}
+	return m.ListPageWithContextFunc(ctx, options)
+}
+
+// FilesMock is a fake pinata.FilesAPI, standing in for a *pinata.FilesClient in tests.
+// Each method records its call, see Calls, and calls the field named after it with a Func
+// suffix, such as UploadFunc for Upload; when the field is nil, the method fails with an
+// error wrapping ErrNotMocked. Set the fields before the first call; the mock is then safe
+// for concurrent use.
+type FilesMock struct {
+	recorder
+
+	UploadFunc           func(ctx context.Context, path string, options *pinata.FileUploadOptions) (*pinata.File, error)
+	ListFunc             func(ctx context.Context, options *pinata.FileListOptions) (*pinata.FileList, error)
+	DownloadFileFunc     func(ctx context.Context, file *pinata.File) (*pinata.GatewayContent, error)
+	CreateAccessLinkFunc func(ctx context.Context, opts pinata.AccessLinkOptions) (string, error)
+	GetAccessLinkFunc    func(ctx context.Context, link string) (*pinata.GatewayContent, error)
+}
+
+var _ pinata.FilesAPI = (*FilesMock)(nil)
+
+// Upload calls UploadFunc.
+func (m *FilesMock) Upload(ctx context.Context, path string, options *pinata.FileUploadOptions) (*pinata.File, error) {
+	m.record("Upload", ctx, path, options)
+	if m.UploadFunc == nil {
+		return nil, notMocked("Upload")
+	}
+	return m.UploadFunc(ctx, path, options)
+}
+
+// List calls ListFunc.
+func (m *FilesMock) List(ctx context.Context, options *pinata.FileListOptions) (*pinata.FileList, error) {
+	m.record("List", ctx, options)
+	if m.ListFunc == nil {
+		return nil, notMocked("List")
+	}
+	return m.ListFunc(ctx, options)
+}
+
+// DownloadFile calls DownloadFileFunc.
+func (m *FilesMock) DownloadFile(ctx context.Context, file *pinata.File) (*pinata.GatewayContent, error) {
+	m.record("DownloadFile", ctx, file)
+	if m.DownloadFileFunc == nil {
+		return nil, notMocked("DownloadFile")
+	}
+	return m.DownloadFileFunc(ctx, file)
+}
+
+// CreateAccessLink calls CreateAccessLinkFunc.
+func (m *FilesMock) CreateAccessLink(ctx context.Context, opts pinata.AccessLinkOptions) (string, error) {
+	m.record("CreateAccessLink", ctx, opts)
+	if m.CreateAccessLinkFunc == nil {
+		return "", notMocked("CreateAccessLink")
+	}
+	return m.CreateAccessLinkFunc(ctx, opts)
+}
+
+// GetAccessLink calls GetAccessLinkFunc.
+func (m *FilesMock) GetAccessLink(ctx context.Context, link string) (*pinata.GatewayContent, error) {
+	m.record("GetAccessLink", ctx, link)
+	if m.GetAccessLinkFunc == nil {
+		return nil, notMocked("GetAccessLink")
+	}
+	return m.GetAccessLinkFunc(ctx, link)
+}
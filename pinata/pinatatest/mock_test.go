@@ -0,0 +1,86 @@
+package pinatatest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata"
+)
+
+// pinAndGroup is code under test, taking the interfaces of the SDK rather than a Client.
+func pinAndGroup(ctx context.Context, api pinata.PinataAPI, groups pinata.GroupsAPI, path string, group pinata.GroupID) (string, error) {
+	response, err := api.PinFileWithContext(ctx, path, nil)
+	if err != nil {
+		return "", err
+	}
+	cid, err := pinata.ParseCid(response.IpfsHash)
+	if err != nil {
+		return "", err
+	}
+	return response.IpfsHash, groups.AddCidsWithContext(ctx, group, []pinata.Cid{cid})
+}
+
+func TestMock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("functions are called and calls recorded", func(t *testing.T) {
+		api := &Mock{
+			PinFileWithContextFunc: func(ctx context.Context, path string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+				return &pinata.PinResponse{IpfsHash: FixtureCid, PinSize: 7}, nil
+			},
+		}
+		groups := &GroupsMock{
+			AddCidsWithContextFunc: func(ctx context.Context, id pinata.GroupID, cids []pinata.Cid) error {
+				return nil
+			},
+		}
+
+		cid, err := pinAndGroup(ctx, api, groups, "a.txt", FixtureGroupID)
+		require.NoError(t, err)
+		require.Equal(t, FixtureCid, cid)
+
+		require.Equal(t, []Call{{Method: "PinFileWithContext", Args: []interface{}{ctx, "a.txt", (*pinata.PinOptions)(nil)}}}, api.Calls())
+		calls := groups.CallsTo("AddCidsWithContext")
+		require.Len(t, calls, 1)
+		require.Equal(t, pinata.GroupID(FixtureGroupID), calls[0].Args[1])
+		require.Equal(t, []pinata.Cid{pinata.Cid(FixtureCid)}, calls[0].Args[2])
+		require.Empty(t, groups.CallsTo("Remove"))
+	})
+
+	t.Run("methods without a function fail", func(t *testing.T) {
+		api := &Mock{}
+		_, err := pinAndGroup(ctx, api, &GroupsMock{}, "a.txt", FixtureGroupID)
+		require.ErrorIs(t, err, ErrNotMocked)
+		require.ErrorContains(t, err, "PinFileWithContext")
+
+		pins, errs := api.StreamFiles(ctx, nil, 0)
+		for range pins {
+			t.Fatal("no pin expected")
+		}
+		require.ErrorIs(t, <-errs, ErrNotMocked)
+
+		deleted := api.DeleteFilesAsync([]string{"a", "b"})
+		require.Len(t, deleted, 2)
+		require.True(t, errors.Is(deleted[1], ErrNotMocked))
+
+		_, ok := api.LastRateLimit()
+		require.False(t, ok)
+	})
+
+	t.Run("mocks embed into partial fakes", func(t *testing.T) {
+		var api pinata.PinataAPI = &unpinOnly{}
+		require.NoError(t, api.Unpin(FixtureCid))
+		require.ErrorIs(t, api.DeleteFile(FixtureCid), ErrNotMocked)
+	})
+}
+
+// unpinOnly implements Unpin itself and leaves the other methods to the embedded Mock.
+type unpinOnly struct {
+	Mock
+}
+
+func (u *unpinOnly) Unpin(cid string) error {
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package pinatatest provides options that make the requests of a pinata client deterministic, so
+// that tests can compare request bodies byte for byte against golden files.
+//
+// Two things vary between runs of the same call: the boundary of multipart uploads, which is
+// random, and the default names of uploads without a name, such as PinFolder's
+// "folder_from_sdk_<time>", which carry the current time. Deterministic fixes both:
+//
+//	client := pinata.New(auth, pinatatest.Deterministic(), pinata.WithTransport(recorder))
+//
+// Code taking the interfaces of the SDK, such as pinata.PinataAPI, can be tested without any
+// request with Mock and the mocks of the sub-clients, whose methods call the functions set by the
+// test and record their calls:
+//
+//	api := &pinatatest.Mock{PinFileFunc: func(path string, options *pinata.PinOptions) (*pinata.PinResponse, error) {
+//		return &pinata.PinResponse{IpfsHash: pinatatest.FixtureCid}, nil
+//	}}
+package pinatatest
+
+import (
+	"time"
+
+	"github.com/zde37/pinata-go-sdk/pinata"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/seam"
+)
+
+// Boundary is the multipart boundary set by Deterministic.
+const Boundary = "pinatatest-boundary"
+
+// Time is the time of the generated names set by Deterministic.
+var Time = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// WithBoundary makes the client use boundary for every multipart upload. It is
+// pinata.WithMultipartBoundary.
+func WithBoundary(boundary string) pinata.ClientOption {
+	return pinata.WithMultipartBoundary(boundary)
+}
+
+// WithNameClock makes the client take the time in the names it generates from now instead of the
+// wall clock. A time in UTC gives the same names on every machine.
+func WithNameClock(now func() time.Time) pinata.ClientOption {
+	return seam.WithNameClock(now).(pinata.ClientOption)
+}
+
+// Deterministic fixes the multipart boundary to Boundary and the time of generated names to Time.
+func Deterministic() pinata.ClientOption {
+	boundary := WithBoundary(Boundary)
+	names := WithNameClock(func() time.Time { return Time })
+	return func(c *pinata.Client) {
+		boundary(c)
+		names(c)
+	}
+}
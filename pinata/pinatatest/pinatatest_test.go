@@ -0,0 +1,31 @@
+package pinatatest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata"
+	"github.com/zde37/pinata-go-sdk/pinata/contracts"
+)
+
+func TestDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(file, []byte("content"), 0644))
+
+	pinFolder := func() contracts.Request {
+		capture := &contracts.Capture{}
+		client := pinata.New(pinata.NewAuthWithJWT("jwt"), Deterministic(), pinata.WithTransport(capture))
+		_, err := client.PinFolder([]string{file}, &pinata.PinOptions{})
+		require.NoError(t, err)
+		require.Len(t, capture.Requests(), 1)
+		return capture.Requests()[0]
+	}
+
+	first := pinFolder()
+	require.Equal(t, "multipart/form-data; boundary="+Boundary, first.ContentType)
+	require.Contains(t, string(first.Body), `"name":"folder_from_sdk_2024-01-01 00:00:00 +0000 UTC"`)
+	require.Equal(t, string(first.Body), string(pinFolder().Body))
+}
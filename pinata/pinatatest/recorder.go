@@ -0,0 +1,73 @@
+package pinatatest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotMocked is returned by the methods of the mocks whose function is not set.
+var ErrNotMocked = errors.New("method not mocked")
+
+// Call is a call recorded by a mock: the name of the method and its arguments, in the order of
+// its parameters, the context included.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// recorder records the calls of a mock.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls made to the mock so far, in order.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls made to the method of the mock so far, in order.
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func notMocked(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotMocked, method)
+}
+
+// notMockedStream returns the channels of a streaming method that is not mocked: no item, and the
+// error.
+func notMockedStream[T any](method string) (<-chan T, <-chan error) {
+	items := make(chan T)
+	close(items)
+	errs := make(chan error, 1)
+	errs <- notMocked(method)
+	close(errs)
+	return items, errs
+}
+
+// notMockedErrors returns the errors of a batch method that is not mocked, one per item.
+func notMockedErrors(method string, n int) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = notMocked(method)
+	}
+	return errs
+}
@@ -0,0 +1,718 @@
+package pinatatest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zde37/pinata-go-sdk/pinata"
+)
+
+// Server is an in-memory fake of the Pinata API and gateway, for tests and offline runs of code
+// using the SDK. It keeps pins, groups, CID signatures and API keys in memory and serves pinned
+// content through /ipfs/, so calls made against it behave like calls made against Pinata: a
+// pinned file can be listed, renamed, grouped, fetched through the gateway and unpinned.
+//
+// Content identifiers are CIDv0 computed from the uploaded bytes, and every date is Time. Requests
+// without credentials are rejected with 401 Unauthorized; any credentials are accepted.
+type Server struct {
+	// URL is the base URL of the server, such as "http://127.0.0.1:41235".
+	URL string
+
+	server *httptest.Server
+
+	mu         sync.Mutex
+	nextID     int
+	pins       []*fakePin
+	jobs       []map[string]interface{}
+	groups     []*fakeGroup
+	signatures map[string]string
+	keys       []*fakeKey
+}
+
+type fakePin struct {
+	id        string
+	cid       string
+	name      string
+	keyValues map[string]interface{}
+	files     map[string][]byte
+	size      int
+	unpinned  bool
+}
+
+type fakeGroup struct {
+	id   string
+	name string
+	cids map[string]bool
+}
+
+type fakeKey struct {
+	id      string
+	name    string
+	key     string
+	maxUses int
+	revoked bool
+}
+
+// NewServer starts a Server. It is closed with Close.
+func NewServer() *Server {
+	s := &Server{signatures: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /data/testAuthentication", s.testAuthentication)
+	mux.HandleFunc("GET /data/userPinnedDataTotal", s.pinnedDataTotal)
+	mux.HandleFunc("GET /data/pinList", s.pinList)
+	mux.HandleFunc("POST /pinning/pinFileToIPFS", s.pinFile)
+	mux.HandleFunc("POST /pinning/pinJSONToIPFS", s.pinJSON)
+	mux.HandleFunc("POST /pinning/pinByHash", s.pinByHash)
+	mux.HandleFunc("GET /pinning/pinJobs", s.pinJobs)
+	mux.HandleFunc("PUT /pinning/hashMetadata", s.hashMetadata)
+	mux.HandleFunc("DELETE /pinning/unpin/{cid}", s.unpin)
+	mux.HandleFunc("POST /groups", s.createGroup)
+	mux.HandleFunc("GET /groups", s.listGroups)
+	mux.HandleFunc("GET /groups/{id}", s.getGroup)
+	mux.HandleFunc("PUT /groups/{id}", s.updateGroup)
+	mux.HandleFunc("DELETE /groups/{id}", s.removeGroup)
+	mux.HandleFunc("PUT /groups/{id}/cids", s.groupCids)
+	mux.HandleFunc("DELETE /groups/{id}/cids", s.groupCids)
+	mux.HandleFunc("POST /v3/ipfs/signature/{cid}", s.addSignature)
+	mux.HandleFunc("GET /v3/ipfs/signature/{cid}", s.getSignature)
+	mux.HandleFunc("DELETE /v3/ipfs/signature/{cid}", s.removeSignature)
+	mux.HandleFunc("POST /users/generateApiKey", s.generateKey)
+	mux.HandleFunc("POST /v3/pinata/keys", s.generateKey)
+	mux.HandleFunc("GET /users/apiKeys", s.listKeys)
+	mux.HandleFunc("GET /v3/pinata/keys", s.listKeys)
+	mux.HandleFunc("PUT /users/revokeApiKey", s.revokeKey)
+	mux.HandleFunc("PUT /v3/pinata/keys/{key}", s.revokeKey)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", r.Method+" "+r.URL.Path+" is not served by pinatatest")
+	})
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/ipfs/") {
+			s.gateway(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") == "" && r.Header.Get("pinata_api_key") == "" {
+			writeError(w, http.StatusUnauthorized, "NO_CREDENTIALS", "no credentials provided")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Option routes every request of the client to s, whatever its host: API and upload requests as
+// well as gateway requests.
+func (s *Server) Option() pinata.ClientOption {
+	target, _ := url.Parse(s.URL)
+	return pinata.WithTransport(&rewriteTransport{target: target, next: s.server.Client().Transport})
+}
+
+// rewriteTransport sends every request to target.
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = ""
+	return t.next.RoundTrip(req)
+}
+
+// Pins returns the CIDs of the content currently pinned, in pinning order.
+func (s *Server) Pins() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var cids []string
+	for _, p := range s.pins {
+		if !p.unpinned {
+			cids = append(cids, p.cid)
+		}
+	}
+	return cids
+}
+
+func (s *Server) testAuthentication(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"message": "Congratulations! You are communicating with the Pinata API!"})
+}
+
+func (s *Server) pinnedDataTotal(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count, size := 0, 0
+	for _, p := range s.pins {
+		if !p.unpinned {
+			count++
+			size += p.size
+		}
+	}
+	writeJSON(w, map[string]int{"pin_count": count, "pin_size_total": size, "pin_size_with_replications_total": size})
+}
+
+func (s *Server) pinFile(w http.ResponseWriter, r *http.Request) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	var metadata pinata.PinataMetadata
+	files := make(map[string][]byte)
+	var names []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+		switch params["name"] {
+		case "pinataMetadata":
+			if err := json.Unmarshal(data, &metadata); err != nil {
+				writeError(w, http.StatusBadRequest, "INVALID_METADATA", err.Error())
+				return
+			}
+		case "file":
+			files[params["filename"]] = data
+			names = append(names, params["filename"])
+		}
+	}
+	if len(files) == 0 {
+		writeError(w, http.StatusBadRequest, "NO_FILE", "no file was uploaded")
+		return
+	}
+
+	// a single file is served at the root of its CID, the files of a folder under their paths
+	// within the folder
+	if len(files) == 1 {
+		files = map[string][]byte{"": files[names[0]]}
+		if metadata.Name == "" {
+			metadata.Name = names[0]
+		}
+	} else {
+		folder := make(map[string][]byte, len(files))
+		for name, data := range files {
+			_, rel, _ := strings.Cut(name, "/")
+			folder[rel] = data
+		}
+		files = folder
+	}
+	s.addPin(w, metadata, files)
+}
+
+func (s *Server) pinJSON(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		PinataContent  json.RawMessage       `json:"pinataContent"`
+		PinataMetadata pinata.PinataMetadata `json:"pinataMetadata"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || len(payload.PinataContent) == 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "pinataContent is required")
+		return
+	}
+	s.addPin(w, payload.PinataMetadata, map[string][]byte{"": payload.PinataContent})
+}
+
+// addPin pins files, keyed by their path within the pinned content, and answers with the pin
+// response.
+func (s *Server) addPin(w http.ResponseWriter, metadata pinata.PinataMetadata, files map[string][]byte) {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	hash := sha256.New()
+	size := 0
+	for _, p := range paths {
+		fmt.Fprintf(hash, "%s\x00%d\x00", p, len(files[p]))
+		hash.Write(files[p])
+		size += len(files[p])
+	}
+	cid := cidV0(hash.Sum(nil))
+
+	s.mu.Lock()
+	duplicate := s.findPin(cid) != nil
+	if !duplicate {
+		s.pins = append(s.pins, &fakePin{
+			id:        s.newID("pin"),
+			cid:       cid,
+			name:      metadata.Name,
+			keyValues: metadata.KeyValues,
+			files:     files,
+			size:      size,
+		})
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{
+		"IpfsHash":    cid,
+		"PinSize":     size,
+		"Timestamp":   Time.Format(time.RFC3339),
+		"IsDuplicate": duplicate,
+	})
+}
+
+func (s *Server) pinList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	status := query.Get("status")
+	offset, _ := strconv.Atoi(query.Get("pageOffset"))
+	limit, err := strconv.Atoi(query.Get("pageLimit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var group *fakeGroup
+	if id := query.Get("groupId"); id != "" {
+		if group = s.findGroup(id); group == nil {
+			writeJSON(w, map[string]interface{}{"count": 0, "rows": []interface{}{}})
+			return
+		}
+	}
+
+	rows := []map[string]interface{}{}
+	for _, p := range s.pins {
+		switch {
+		case query.Get("cid") != "" && p.cid != query.Get("cid"):
+		case status == "pinned" && p.unpinned, status == "unpinned" && !p.unpinned:
+		case group != nil && !group.cids[p.cid]:
+		default:
+			rows = append(rows, p.row())
+		}
+	}
+	count := len(rows)
+	rows = rows[min(offset, len(rows)):min(offset+limit, len(rows))]
+	writeJSON(w, map[string]interface{}{"count": count, "rows": rows})
+}
+
+func (p *fakePin) row() map[string]interface{} {
+	row := map[string]interface{}{
+		"id":              p.id,
+		"ipfs_pin_hash":   p.cid,
+		"size":            p.size,
+		"user_id":         "pinatatest",
+		"date_pinned":     Time.Format(time.RFC3339),
+		"metadata":        map[string]interface{}{"name": p.name, "keyvalues": p.keyValues},
+		"number_of_files": len(p.files),
+		"mime_type":       "application/octet-stream",
+	}
+	if p.unpinned {
+		row["date_unpinned"] = Time.Format(time.RFC3339)
+	}
+	return row
+}
+
+func (s *Server) pinByHash(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		HashToPin      string                `json:"hashToPin"`
+		PinataMetadata pinata.PinataMetadata `json:"pinataMetadata"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.HashToPin == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "hashToPin is required")
+		return
+	}
+
+	s.mu.Lock()
+	job := map[string]interface{}{
+		"id":            s.newID("job"),
+		"ipfs_pin_hash": payload.HashToPin,
+		"date_queued":   Time.Format(time.RFC3339),
+		"name":          payload.PinataMetadata.Name,
+		"status":        "prechecking",
+		"keyvalues":     payload.PinataMetadata.KeyValues,
+	}
+	s.jobs = append(s.jobs, job)
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{
+		"id":       job["id"],
+		"ipfsHash": payload.HashToPin,
+		"status":   job["status"],
+		"name":     payload.PinataMetadata.Name,
+	})
+}
+
+func (s *Server) pinJobs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := []map[string]interface{}{}
+	for _, job := range s.jobs {
+		if hash := query.Get("ipfs_pin_hash"); hash != "" && job["ipfs_pin_hash"] != hash {
+			continue
+		}
+		if status := query.Get("status"); status != "" && job["status"] != status {
+			continue
+		}
+		rows = append(rows, job)
+	}
+	writeJSON(w, map[string]interface{}{"count": len(rows), "rows": rows})
+}
+
+func (s *Server) hashMetadata(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		IpfsPinHash string                 `json:"ipfsPinHash"`
+		Name        string                 `json:"name"`
+		KeyValues   map[string]interface{} `json:"keyvalues"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPin(payload.IpfsPinHash)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "PIN_NOT_FOUND", "no pin for "+payload.IpfsPinHash)
+		return
+	}
+	if payload.Name != "" {
+		p.name = payload.Name
+	}
+	for k, v := range payload.KeyValues {
+		if p.keyValues == nil {
+			p.keyValues = make(map[string]interface{})
+		}
+		if v == nil {
+			delete(p.keyValues, k)
+		} else {
+			p.keyValues[k] = v
+		}
+	}
+	w.Write([]byte("OK"))
+}
+
+func (s *Server) unpin(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPin(r.PathValue("cid"))
+	if p == nil || p.unpinned {
+		writeError(w, http.StatusBadRequest, "CURRENT_USER_HAS_NOT_PINNED_CID", "the current user has not pinned "+r.PathValue("cid"))
+		return
+	}
+	p.unpinned = true
+	w.Write([]byte("OK"))
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Name == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "name is required")
+		return
+	}
+	s.mu.Lock()
+	g := &fakeGroup{id: s.newID("group"), name: payload.Name, cids: make(map[string]bool)}
+	s.groups = append(s.groups, g)
+	s.mu.Unlock()
+	writeJSON(w, g.json())
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups := []map[string]interface{}{}
+	for _, g := range s.groups {
+		if strings.Contains(g.name, query.Get("nameContains")) {
+			groups = append(groups, g.json())
+		}
+	}
+	writeJSON(w, groups[min(offset, len(groups)):min(offset+limit, len(groups))])
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g := s.groupOrError(w, r); g != nil {
+		writeJSON(w, g.json())
+	}
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Name == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "name is required")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g := s.groupOrError(w, r); g != nil {
+		g.name = payload.Name
+		writeJSON(w, g.json())
+	}
+}
+
+func (s *Server) removeGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g := s.groupOrError(w, r); g != nil {
+		for i := range s.groups {
+			if s.groups[i] == g {
+				s.groups = append(s.groups[:i], s.groups[i+1:]...)
+				break
+			}
+		}
+		w.Write([]byte("OK"))
+	}
+}
+
+func (s *Server) groupCids(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Cids []string `json:"cids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || len(payload.Cids) == 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "cids are required")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g := s.groupOrError(w, r); g != nil {
+		for _, cid := range payload.Cids {
+			if r.Method == http.MethodDelete {
+				delete(g.cids, cid)
+			} else {
+				g.cids[cid] = true
+			}
+		}
+		w.Write([]byte("OK"))
+	}
+}
+
+func (g *fakeGroup) json() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        g.id,
+		"name":      g.name,
+		"user_id":   "pinatatest",
+		"createdAt": Time.Format(time.RFC3339),
+		"updatedAt": Time.Format(time.RFC3339),
+	}
+}
+
+// groupOrError returns the group of the id path value, or answers 404. s.mu must be held.
+func (s *Server) groupOrError(w http.ResponseWriter, r *http.Request) *fakeGroup {
+	g := s.findGroup(r.PathValue("id"))
+	if g == nil {
+		writeError(w, http.StatusNotFound, "GROUP_NOT_FOUND", "no group "+r.PathValue("id"))
+	}
+	return g
+}
+
+func (s *Server) addSignature(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Signature string `json:"signature"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Signature == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "signature is required")
+		return
+	}
+	cid := r.PathValue("cid")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findPin(cid) == nil {
+		writeError(w, http.StatusNotFound, "PIN_NOT_FOUND", "no pin for "+cid)
+		return
+	}
+	s.signatures[cid] = payload.Signature
+	writeJSON(w, map[string]interface{}{"data": map[string]string{"cid": cid, "signature": payload.Signature}})
+}
+
+func (s *Server) getSignature(w http.ResponseWriter, r *http.Request) {
+	cid := r.PathValue("cid")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	signature, ok := s.signatures[cid]
+	if !ok {
+		writeError(w, http.StatusNotFound, "SIGNATURE_NOT_FOUND", "no signature for "+cid)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"data": map[string]string{"cid": cid, "signature": signature}})
+}
+
+func (s *Server) removeSignature(w http.ResponseWriter, r *http.Request) {
+	cid := r.PathValue("cid")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.signatures[cid]; !ok {
+		writeError(w, http.StatusNotFound, "SIGNATURE_NOT_FOUND", "no signature for "+cid)
+		return
+	}
+	delete(s.signatures, cid)
+	w.Write([]byte("OK"))
+}
+
+func (s *Server) generateKey(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		KeyName string `json:"keyName"`
+		MaxUses int    `json:"maxUses"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.KeyName == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "keyName is required")
+		return
+	}
+	s.mu.Lock()
+	k := &fakeKey{id: s.newID("key"), name: payload.KeyName, maxUses: payload.MaxUses}
+	k.key = "pinatatest-" + k.id
+	s.keys = append(s.keys, k)
+	s.mu.Unlock()
+	writeJSON(w, map[string]string{"JWT": "pinatatest.jwt." + k.id, "pinata_api_key": k.key, "pinata_api_secret": "secret-" + k.id})
+}
+
+func (s *Server) listKeys(w http.ResponseWriter, r *http.Request) {
+	revoked := r.URL.Query().Get("revoked")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := []map[string]interface{}{}
+	for _, k := range s.keys {
+		if revoked != "" && strconv.FormatBool(k.revoked) != revoked {
+			continue
+		}
+		keys = append(keys, map[string]interface{}{
+			"id":        k.id,
+			"name":      k.name,
+			"key":       k.key,
+			"max_uses":  k.maxUses,
+			"revoked":   k.revoked,
+			"user_id":   "pinatatest",
+			"createdAt": Time.Format(time.RFC3339),
+			"updatedAt": Time.Format(time.RFC3339),
+		})
+	}
+	writeJSON(w, map[string]interface{}{"keys": keys, "count": len(keys)})
+}
+
+func (s *Server) revokeKey(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if key == "" {
+		var payload struct {
+			APIKey string `json:"apiKey"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		key = payload.APIKey
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.keys {
+		if k.key == key {
+			k.revoked = true
+			w.Write([]byte(`"Revoked"`))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "KEY_NOT_FOUND", "no key "+key)
+}
+
+// gateway serves pinned content at /ipfs/<cid>[/<path>].
+func (s *Server) gateway(w http.ResponseWriter, r *http.Request) {
+	cid, rel, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/ipfs/"), "/")
+	s.mu.Lock()
+	var data []byte
+	found := false
+	if p := s.findPin(cid); p != nil && !p.unpinned {
+		data, found = p.files[rel]
+	}
+	s.mu.Unlock()
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if r.Method != http.MethodHead {
+		w.Write(data)
+	}
+}
+
+// findPin returns the pin of cid, or nil. s.mu must be held.
+func (s *Server) findPin(cid string) *fakePin {
+	for _, p := range s.pins {
+		if p.cid == cid {
+			return p
+		}
+	}
+	return nil
+}
+
+// findGroup returns the group with the given id, or nil. s.mu must be held.
+func (s *Server) findGroup(id string) *fakeGroup {
+	for _, g := range s.groups {
+		if g.id == id {
+			return g
+		}
+	}
+	return nil
+}
+
+// newID returns a new identifier starting with prefix. s.mu must be held.
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with status and an error body of the shape used by Pinata.
+func writeError(w http.ResponseWriter, status int, reason, details string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"reason": reason, "details": details}})
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// cidV0 returns the CIDv0 of a SHA-256 digest: the base58btc encoding of its multihash.
+func cidV0(digest []byte) string {
+	multihash := append([]byte{0x12, 0x20}, digest...)
+	n := new(big.Int).SetBytes(multihash)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var encoded []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
@@ -0,0 +1,93 @@
+package pinatatest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := pinata.New(pinata.NewAuthWithJWT("jwt"), server.Option())
+	ctx := context.Background()
+
+	auth, err := client.TestAuthentication()
+	require.NoError(t, err)
+	require.NotEmpty(t, auth.Message)
+
+	file := filepath.Join(t.TempDir(), "hello.txt")
+	require.NoError(t, os.WriteFile(file, []byte("hello"), 0644))
+	pinned, err := client.PinFile(file, &pinata.PinOptions{PinataMetadata: pinata.PinataMetadata{Name: "hello"}})
+	require.NoError(t, err)
+	require.Regexp(t, `^Qm[1-9A-HJ-NP-Za-km-z]{44}$`, pinned.IpfsHash)
+	require.Equal(t, 5, pinned.PinSize)
+
+	again, err := client.PinFile(file, nil)
+	require.NoError(t, err)
+	require.Equal(t, pinned.IpfsHash, again.IpfsHash)
+	require.True(t, again.IsDuplicate)
+
+	document, err := client.PinJSON(map[string]string{"a": "b"}, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, pinned.IpfsHash, document.IpfsHash)
+	require.Equal(t, []string{pinned.IpfsHash, document.IpfsHash}, server.Pins())
+
+	require.NoError(t, client.UpdateFileMetadata(pinned.IpfsHash, &pinata.PinMetadataUpdateOptions{
+		Name:      "renamed",
+		KeyValues: map[string]interface{}{"env": "test"},
+	}))
+	row, err := client.GetPinByCid(ctx, pinned.IpfsHash, nil)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", row.Metadata["name"])
+	require.Equal(t, map[string]interface{}{"env": "test"}, row.Metadata["keyvalues"])
+
+	content, err := client.Gateway().Get(ctx, pinned.IpfsHash)
+	require.NoError(t, err)
+	body, err := io.ReadAll(content.Body)
+	content.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(body))
+
+	group, err := client.Groups().Create("docs")
+	require.NoError(t, err)
+	require.NoError(t, client.Groups().AddCids(pinata.GroupID(group.ID), []pinata.Cid{pinata.Cid(document.IpfsHash)}))
+	listed, err := client.ListFiles(&pinata.ListFilesOptions{GroupID: group.ID, IncludeCount: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, listed.Count)
+	require.Equal(t, document.IpfsHash, listed.Rows[0].IPFSPinHash)
+	require.NoError(t, client.Groups().Remove(pinata.GroupID(group.ID)))
+	_, err = client.Groups().Get(pinata.GroupID(group.ID))
+	require.Error(t, err)
+
+	signature, err := client.AddCidSignature(pinned.IpfsHash, "0xsig")
+	require.NoError(t, err)
+	require.Equal(t, "0xsig", signature.Data.Signature)
+
+	require.NoError(t, client.Unpin(pinned.IpfsHash))
+	require.Equal(t, []string{document.IpfsHash}, server.Pins())
+	_, err = client.Gateway().Get(ctx, pinned.IpfsHash)
+	require.Error(t, err)
+
+	err = client.Unpin(pinned.IpfsHash)
+	var apiErr *pinata.APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, "CURRENT_USER_HAS_NOT_PINNED_CID", apiErr.Reason)
+}
+
+func TestServerRequiresCredentials(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := pinata.New(pinata.NewAuthWithJWT(""), server.Option())
+
+	_, err := client.TestAuthentication()
+	var apiErr *pinata.APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, 401, apiErr.StatusCode)
+}
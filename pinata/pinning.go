@@ -2,13 +2,20 @@ package pinata
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -34,9 +41,42 @@ const (
 // PinOptions represents the options for pinning a file or directory to Pinata.
 // PinataMetadata contains metadata about the file or directory being pinned.
 // PinataOptions contains options specific to the Pinata platform, such as the CID version.
+// AllowedContentTypes restricts PinURL to origins serving one of the listed media types
+// (wildcard subtypes such as "image/*" are supported).
+// DeniedContentTypes rejects origins serving any of the listed media types in PinURL.
+// The content type lists are client-side checks and are never sent to the API.
+// CanonicalJSON makes PinJSON serialize the content with the keys of every object sorted,
+// recursively, so equal values always produce the same document and CID. Array order is kept.
+// VerifyIntegrity makes PinFile and PinOpenFile hash the file content as it is written to the
+// upload body, then download the pinned content from the gateway and fail with an
+// *IntegrityError if its digest differs.
+// CidVersionPolicy selects what happens when the API returns a CID of another version than
+// PinataOptions.CidVersion; by default the CID is returned as given.
+// DisambiguatePaths makes PinFolder and PinNestedFolders rename a file whose path in the folder
+// collides with an earlier one, by suffixing its base name with "_2", "_3" and so on, instead of
+// failing with ErrDuplicatePath.
+// NameTemplate is a text/template executed with NameData to derive each file's metadata name in
+// PinFilesAsync, and NameFunc derives it from the file's path, taking precedence over NameTemplate.
+// PartNameTemplate and PartNameFunc derive in the same way the path of each file within the folder
+// uploaded by PinFolder and PinNestedFolders, in place of its base name or relative path. Two files
+// deriving the same name make the call fail with an error wrapping ErrNameCollision.
+// KeyValuesFunc returns per-file keyvalues that PinFilesAsync merges over PinataMetadata.KeyValues.
+// Pin methods copy the options they are given, so one value can be shared by concurrent calls and
+// modified once a call has started.
 type PinOptions struct {
-	PinataMetadata PinataMetadata `json:"pinataMetadata,omitempty"`
-	PinataOptions  Options        `json:"pinataOptions,omitempty"`
+	PinataMetadata      PinataMetadata                           `json:"pinataMetadata,omitempty"`
+	PinataOptions       Options                                  `json:"pinataOptions,omitempty"`
+	AllowedContentTypes []string                                 `json:"-"`
+	DeniedContentTypes  []string                                 `json:"-"`
+	CanonicalJSON       bool                                     `json:"-"`
+	VerifyIntegrity     bool                                     `json:"-"`
+	CidVersionPolicy    CidVersionPolicy                         `json:"-"`
+	DisambiguatePaths   bool                                     `json:"-"`
+	NameTemplate        string                                   `json:"-"`
+	NameFunc            func(path string) string                 `json:"-"`
+	PartNameTemplate    string                                   `json:"-"`
+	PartNameFunc        func(path string) string                 `json:"-"`
+	KeyValuesFunc       func(path string) map[string]interface{} `json:"-"`
 }
 
 // Options represents options specific to the Pinata platform, such as the CID version.
@@ -61,12 +101,12 @@ type PinOpts struct {
 	HostNodes []string `json:"hostNodes,omitempty"`
 }
 
-// pinByCidResponse represents the response from pinning a file or directory to Pinata by its CID.
+// PinByCidResponse represents the response from pinning a file or directory to Pinata by its CID.
 // ID is the unique identifier for the pin.
 // IpfsHash is the IPFS hash of the pinned content.
 // Status is the status of the pin operation.
 // Name is the name of the pinned content.
-type pinByCidResponse struct {
+type PinByCidResponse struct {
 	ID       string `json:"id,omitempty"`
 	IpfsHash string `json:"ipfsHash,omitempty"`
 	Status   string `json:"status,omitempty"`
@@ -81,21 +121,31 @@ type PinataMetadata struct {
 	KeyValues map[string]interface{} `json:"keyvalues,omitempty"`
 }
 
-// pinResponse represents the response from pinning a file or directory to Pinata.
+// PinResponse represents the response from pinning a file or directory to Pinata.
 // IpfsHash is the IPFS hash of the pinned content.
 // PinSize is the size of the pinned content in bytes.
 // Timestamp is the timestamp of when the content was pinned.
 // IsDuplicate indicates whether the pinned content is a duplicate of an existing pin.
-type pinResponse struct {
+// ReturnedIpfsHash is the CID given by the API when IpfsHash was converted under CidVersionConvert.
+// Attempts is the number of uploads of the content recorded under AttemptsKey when attempt tracking
+// is enabled with WithAttemptTracking, otherwise zero.
+// Warnings holds the non-fatal conditions encountered by the call; it is not part of the API response.
+type PinResponse struct {
 	IpfsHash    string `json:"IpfsHash,omitempty"`
 	PinSize     int    `json:"PinSize,omitempty"`
 	Timestamp   string `json:"Timestamp,omitempty"`
 	IsDuplicate bool   `json:"IsDuplicate,omitempty"`
+
+	ReturnedIpfsHash string    `json:"-"`
+	Attempts         int       `json:"-"`
+	Warnings         []Warning `json:"-"`
 }
 
 // PinMetadataUpdateOptions represents the options for updating the metadata of a file or directory pinned to Pinata.
-// Name is the new name for the pinned content.
+// Name is the new name for the pinned content; an empty name leaves the current name unchanged.
 // KeyValues is a map of new key-value pairs containing additional metadata about the pinned content.
+// A nil map leaves the existing keyvalues untouched. A non-nil map is sent as is: Pinata merges its
+// keys into the existing keyvalues, and a key set to nil removes that key from the pin.
 type PinMetadataUpdateOptions struct {
 	Name      string                 `json:"name,omitempty"`
 	KeyValues map[string]interface{} `json:"keyvalues,omitempty"`
@@ -104,20 +154,27 @@ type PinMetadataUpdateOptions struct {
 // ListFilesOptions represents the options for listing files pinned to Pinata.
 // Cid is the IPFS content identifier to filter pins by.
 // GroupID is the ID of the group to filter pins by.
+// Name filters pins by metadata name, sent as the "metadata[name]" query parameter; the API
+// matches the names that contain it. See SearchFilesByName for prefix and regexp matching.
 // Status is the status to filter pins by.
 // PageLimit is the maximum number of pins to return per page.
 // PageOffset is the number of pins to skip before returning results.
-// Metadata is a map of key-value pairs to filter pins by.
+// Metadata is a map of key-value pairs to filter pins by. It is sent JSON-encoded in the "metadata"
+// query parameter, which counts toward the URL length limit (DefaultMaxURLLength, see
+// WithMaxURLLength); percent-encoding roughly doubles the size of the JSON, so in practice a filter
+// should stay under about 3 KB of JSON. Larger filters fail with an error wrapping ErrQueryTooLong.
 // PinSizeMin is the minimum size in bytes of pins to return.
 // PinSizeMax is the maximum size in bytes of pins to return.
 // PinStart is the earliest date that pins were created.
 // PinEnd is the latest date that pins were created.
 // UnpinStart is the earliest date that pins were unpinned.
 // UnpinEnd is the latest date that pins were unpinned.
-// IncludeCount indicates whether to include the total count of matching pins.
+// IncludeCount indicates whether to include the total count of matching pins; the includeCount
+// parameter is only sent when it is set. ListFilesPage reports the count as Page.TotalCount.
 type ListFilesOptions struct {
 	Cid          string                 `json:"cid,omitempty"`
 	GroupID      string                 `json:"groupId,omitempty"`
+	Name         string                 `json:"name,omitempty"`
 	Status       string                 `json:"status,omitempty"`
 	PageLimit    int                    `json:"pageLimit,omitempty"`
 	PageOffset   int                    `json:"pageOffset,omitempty"`
@@ -131,15 +188,44 @@ type ListFilesOptions struct {
 	IncludeCount bool                   `json:"includeCount,omitempty"`
 }
 
-// listFilesResponse represents the response from listing files pinned to Pinata.
-// Count is the total number of pinned files.
+// WithPinnedBetween filters the listing to content pinned between start and end, inclusive.
+// A zero start or end leaves that side of the range open. The status filter is left unchanged,
+// so content unpinned since is included when Status is "all".
+func (o *ListFilesOptions) WithPinnedBetween(start, end time.Time) *ListFilesOptions {
+	o.PinStart, o.PinEnd = timeBound(start), timeBound(end)
+	return o
+}
+
+// WithUnpinnedBetween filters the listing to content unpinned between start and end, inclusive.
+// A zero start or end leaves that side of the range open. Status is set to "unpinned", since the
+// API only applies unpin dates to unpinned content.
+func (o *ListFilesOptions) WithUnpinnedBetween(start, end time.Time) *ListFilesOptions {
+	o.UnpinStart, o.UnpinEnd = timeBound(start), timeBound(end)
+	o.Status = "unpinned"
+	return o
+}
+
+// timeBound returns a pointer to t, or nil for the zero time.
+func timeBound(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// ListFilesResponse represents the response from listing files pinned to Pinata.
+// Count is the total number of matching pins. It is only meaningful when IncludeCount was set;
+// otherwise it is zero, so use ListFilesPage to tell a zero total from a total not requested.
 // Rows is a slice of Pin structs representing the pinned files.
-type listFilesResponse struct {
+// Warnings holds the non-fatal conditions encountered by the call; it is not part of the API response.
+type ListFilesResponse struct {
 	Count int   `json:"count,omitempty"`
-	Rows  []pin `json:"rows,omitempty"`
+	Rows  []Pin `json:"rows,omitempty"`
+
+	Warnings []Warning `json:"-"`
 }
 
-// pin represents a file or directory that has been pinned to Pinata.
+// Pin represents a file or directory that has been pinned to Pinata.
 // ID is the unique identifier for the pinned content.
 // IPFSPinHash is the IPFS content identifier for the pinned content.
 // Size is the size of the pinned content in bytes.
@@ -150,7 +236,7 @@ type listFilesResponse struct {
 // Regions is a slice of Region structs representing the regions where the pinned content is replicated.
 // MimeType is the MIME type of the pinned content.
 // NumberOfFiles is the number of files in the pinned content.
-type pin struct {
+type Pin struct {
 	ID            string                 `json:"id,omitempty"`
 	IPFSPinHash   string                 `json:"ipfs_pin_hash,omitempty"`
 	Size          int                    `json:"size,omitempty"`
@@ -163,14 +249,33 @@ type pin struct {
 	NumberOfFiles int                    `json:"number_of_files,omitempty"`
 }
 
+// IsActive reports whether the content is still pinned, i.e. the row has no unpin date. Listings
+// filtered by unpin date or with the status "all" also return rows of unpinned content.
+func (p Pin) IsActive() bool {
+	if p.DateUnpinned == "" {
+		return true
+	}
+	unpinned, err := time.Parse(time.RFC3339Nano, p.DateUnpinned)
+	return err == nil && unpinned.IsZero()
+}
+
+// Status returns the pinList status of the row, "pinned" or "unpinned", as accepted by
+// ListFilesOptions.Status.
+func (p Pin) Status() string {
+	if p.IsActive() {
+		return "pinned"
+	}
+	return "unpinned"
+}
+
 // region represents a geographic region where a file is pinned.
 // RegionID is the unique identifier for the region.
 // CurrentReplicationCount is the current number of replicas of the file in the region.
 // DesiredReplicationCount is the desired number of replicas of the file in the region.
 type region struct {
-	RegionID                string `json:"regionId,omitempty"`
-	CurrentReplicationCount int    `json:"currentReplicationCount,omitempty"`
-	DesiredReplicationCount int    `json:"desiredReplicationCount,omitempty"`
+	RegionID                RegionID `json:"regionId,omitempty"`
+	CurrentReplicationCount int      `json:"currentReplicationCount,omitempty"`
+	DesiredReplicationCount int      `json:"desiredReplicationCount,omitempty"`
 }
 
 // ListPinByCidOptions represents the options for listing pins by IPFS content identifier (CID).
@@ -179,23 +284,27 @@ type region struct {
 // IPFSPinHash specifies the IPFS content identifier to filter the results by.
 // Limit specifies the maximum number of results to return.
 // Offset specifies the number of results to skip before returning results.
+// Limit and Offset are only sent when set, so an explicit zero can be requested with Int(0).
 type ListPinByCidOptions struct {
 	Sort        SortOrder `json:"sort,omitempty"`
 	Status      PinStatus `json:"status,omitempty"`
 	IPFSPinHash string    `json:"ipfs_pin_hash,omitempty"`
-	Limit       int       `json:"limit,omitempty"`
-	Offset      int       `json:"offset,omitempty"`
+	Limit       *int      `json:"limit,omitempty"`
+	Offset      *int      `json:"offset,omitempty"`
 }
 
-// listPinByCidResponse represents the response from a request to list pins by IPFS content identifier (CID).
+// ListPinByCidResponse represents the response from a request to list pins by IPFS content identifier (CID).
 // Count is the total number of pins returned.
 // Rows is a slice of PinEntry structs representing the pins that match the request.
-type listPinByCidResponse struct {
+// Warnings holds the non-fatal conditions encountered by the call; it is not part of the API response.
+type ListPinByCidResponse struct {
 	Count int        `json:"count,omitempty"`
-	Rows  []pinEntry `json:"rows,omitempty"`
+	Rows  []PinEntry `json:"rows,omitempty"`
+
+	Warnings []Warning `json:"-"`
 }
 
-// pinEntry represents a single entry in the list of pinned content.
+// PinEntry represents a single entry in the list of pinned content.
 // ID is the unique identifier for the pinned content.
 // IPFSPinHash is the IPFS content identifier (CID) for the pinned content.
 // DateQueued is the date the content was queued for pinning.
@@ -204,7 +313,7 @@ type listPinByCidResponse struct {
 // KeyValues is a map of key-value pairs containing additional metadata about the pinned content.
 // HostNodes is a list of node IDs where the pinned content is currently hosted.
 // PinPolicy is the policy that governs how the pinned content is replicated across regions.
-type pinEntry struct {
+type PinEntry struct {
 	ID          string      `json:"id,omitempty"`
 	IPFSPinHash string      `json:"ipfs_pin_hash,omitempty"`
 	DateQueued  string      `json:"date_queued,omitempty"`
@@ -227,8 +336,8 @@ type pinPolicy struct {
 // ID is a unique identifier for the region.
 // DesiredReplicationCount is the number of times the file should be replicated within the region.
 type regions struct {
-	ID                      string `json:"id,omitempty"`
-	DesiredReplicationCount int    `json:"desiredReplicationCount,omitempty"`
+	ID                      RegionID `json:"id,omitempty"`
+	DesiredReplicationCount int      `json:"desiredReplicationCount,omitempty"`
 }
 
 // pinJob represents a job to pin a file to IPFS with the specified options.
@@ -248,90 +357,250 @@ type pinJob struct {
 //
 // Returns a PinResponse struct containing the IPFS hash and other details of the
 // pinned file, or an error if the operation fails.
-func (c *Client) PinFile(path string, options *PinOptions) (*pinResponse, error) {
+func (c *Client) PinFile(path string, options *PinOptions) (*PinResponse, error) {
+	return c.pinFile(context.Background(), path, options)
+}
+
+// PinFileWithContext is like PinFile but uses ctx for the requests.
+func (c *Client) PinFileWithContext(ctx context.Context, path string, options *PinOptions) (*PinResponse, error) {
+	return c.pinFile(ctx, path, options)
+}
+
+// pinFile implements PinFile, using ctx for the upload request.
+func (c *Client) pinFile(ctx context.Context, path string, options *PinOptions) (*PinResponse, error) {
 	if path == "" {
 		return nil, fmt.Errorf("filepath is required")
 	}
 
-	file, err := os.Open(path)
+	options, err := c.normalizePinOptions(options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
 
-	part, err := writer.CreateFormFile("file", filepath.Base(path))
+	digest := integrityDigest(options)
+	uploadOptions := c.withFirstAttempt(options)
+	body, contentType, length, err := c.pinMultipartBody(func(form *multipartForm) error {
+		if err := writePinFileFields(form, uploadOptions); err != nil {
+			return err
+		}
+		form.digest = digest
+		return writeFormFile(form, path, filepath.Base(path))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+		return nil, err
 	}
 
-	_, err = io.Copy(part, file)
+	op := c.startOp("PinFile", path)
+	defer c.endOp(op)
+
+	var response PinResponse
+	err = c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").
+		WithContext(ctx).
+		withActiveOp(op).
+		SetBodyFactory(body, contentType).
+		SetContentLength(length).
+		withPayloadSize(fileSizes(path)).
+		Send(&response)
+
 	if err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
+		return nil, err
 	}
-
-	if options != nil {
-		optionsJSON, err := json.Marshal(options)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal options: %w", err)
-		}
-		err = writer.WriteField("pinataOptions", string(optionsJSON))
-		if err != nil {
-			return nil, fmt.Errorf("failed to write pinataOptions field: %w", err)
+	if digest != nil {
+		if err := c.verifyIntegrity(ctx, response.IpfsHash, digest); err != nil {
+			return nil, err
 		}
 	}
+	c.recordAttempt(ctx, &response)
 
-	err = writer.Close()
+	if err := c.checkCidVersion(&response, options); err != nil {
+		return nil, err
+	}
+	c.warnDuplicate(&response, options)
+	return &response, nil
+}
+
+// PinOpenFile uploads the content of an already open file to IPFS and pins it to the Pinata
+// network, without opening it again by path, so it also works for files that were unlinked
+// after being opened.
+//
+// name is the file name sent with the upload and defaults to the base name of f.Name().
+// Regular files are uploaded from their start regardless of the current file offset, which is
+// left unchanged; their size is known, so the upload can carry a Content-Length (see
+// WithMultipartContentLength), and they are re-read when the request is retried. Other files,
+// such as pipes, are read once up to EOF and sent with chunked transfer encoding.
+//
+// The caller remains responsible for closing f.
+func (c *Client) PinOpenFile(f *os.File, name string, options *PinOptions) (*PinResponse, error) {
+	return c.PinOpenFileWithContext(context.Background(), f, name, options)
+}
+
+// PinOpenFileWithContext is like PinOpenFile but uses ctx for the requests.
+func (c *Client) PinOpenFileWithContext(ctx context.Context, f *os.File, name string, options *PinOptions) (*PinResponse, error) {
+	if f == nil {
+		return nil, fmt.Errorf("file is required")
+	}
+	if name == "" {
+		name = filepath.Base(f.Name())
+	}
+
+	options, err := c.normalizePinOptions(options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+		return nil, err
 	}
 
-	var response pinResponse
-	err = c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").
-		SetBody(body, writer.FormDataContentType()).
-		Send(&response)
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	regular := info.Mode().IsRegular()
 
+	digest := integrityDigest(options)
+	uploadOptions := c.withFirstAttempt(options)
+	body, contentType, length, err := c.pinMultipartBody(func(form *multipartForm) error {
+		if err := writePinFileFields(form, uploadOptions); err != nil {
+			return err
+		}
+		form.digest = digest
+		if regular {
+			return writeFormReader(form, io.NewSectionReader(f, 0, info.Size()), info.Size(), name)
+		}
+		return writeFormReader(form, f, -1, name)
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	op := c.startOp("PinOpenFile", name)
+	defer c.endOp(op)
+
+	rb := c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").WithContext(ctx).withActiveOp(op)
+	if regular {
+		rb.SetBodyFactory(body, contentType).SetContentLength(length).withPayloadSize(info.Size())
+	} else {
+		// a pipe can only be read once, so the body is not retry-safe
+		reader, err := body()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		rb.SetBody(reader, contentType)
+	}
+
+	var response PinResponse
+	if err := rb.Send(&response); err != nil {
+		return nil, err
+	}
+	if digest != nil {
+		if err := c.verifyIntegrity(ctx, response.IpfsHash, digest); err != nil {
+			return nil, err
+		}
+	}
+	c.recordAttempt(ctx, &response)
+
+	if err := c.checkCidVersion(&response, options); err != nil {
+		return nil, err
+	}
+	c.warnDuplicate(&response, options)
 	return &response, nil
 }
 
+// integrityDigest returns the digest recording the uploaded content when options requests an
+// integrity check, otherwise nil.
+func integrityDigest(options *PinOptions) *uploadDigest {
+	if options == nil || !options.VerifyIntegrity {
+		return nil
+	}
+	return &uploadDigest{}
+}
+
+// writePinFileFields writes the pinataMetadata and pinataOptions fields of a single file upload.
+func writePinFileFields(form *multipartForm, options *PinOptions) error {
+	if options == nil {
+		return nil
+	}
+	if options.PinataMetadata.Name != "" || len(options.PinataMetadata.KeyValues) > 0 {
+		metadataJSON, err := json.Marshal(options.PinataMetadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		err = form.WriteField("pinataMetadata", string(metadataJSON))
+		if err != nil {
+			return fmt.Errorf("failed to write pinataMetadata field: %w", err)
+		}
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal options: %w", err)
+	}
+	err = form.WriteField("pinataOptions", string(optionsJSON))
+	if err != nil {
+		return fmt.Errorf("failed to write pinataOptions field: %w", err)
+	}
+	return nil
+}
+
 // PinFilesAsync uploads multiple files to IPFS asynchronously using a worker pool.
-// It takes a slice of file paths and an optional slice of PinOptions for each file.
-// The function returns a slice of pinResponse objects, one for each file, or an error.
-// The number of worker goroutines used is the minimum of the number of files and 5.
+// It takes a slice of file paths and an optional slice of PinOptions for each file. The options are
+// copied before the uploads start, so entries may share keyvalues maps and be reused afterwards.
+// The function returns a slice of PinResponse objects, one for each file, or an error.
+// The number of worker goroutines used is the minimum of the number of files and the client
+// concurrency, 5 unless set with WithConcurrency.
 // If any error occurs during the upload of a file, the function will return the error.
-func (c *Client) PinFilesAsync(paths []string, options *[]PinOptions) ([]*pinResponse, error) {
+// When the client has a CheckpointStore, files already recorded in it are not uploaded again.
+// A file whose options set NameTemplate or NameFunc is pinned under the derived metadata name, and
+// its KeyValuesFunc keyvalues are merged over its metadata keyvalues; name collisions are detected
+// before any upload starts and reported as an error wrapping ErrNameCollision.
+func (c *Client) PinFilesAsync(paths []string, options []PinOptions) ([]*PinResponse, error) {
+	return c.PinFilesAsyncWithContext(context.Background(), paths, options)
+}
+
+// PinFilesAsyncWithContext is like PinFilesAsync but uses ctx for the requests. The uploads in
+// flight are aborted once ctx is done or as soon as one of them fails, and the uploads not yet
+// started fail with the context error.
+func (c *Client) PinFilesAsyncWithContext(ctx context.Context, paths []string, options []PinOptions) (responses []*PinResponse, err error) {
 	if len(paths) == 0 {
 		return nil, fmt.Errorf("at least one filepath is required")
 	}
 
-	numWorkers := min(len(paths), 5)
+	opts, err := planPinOptions(paths, options)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if c.checkpoints != nil {
+		// the files uploaded before a failure are saved too, so that a rerun skips them
+		defer func() {
+			if ferr := flushCheckpoints(c.checkpoints); ferr != nil && err == nil {
+				responses, err = nil, ferr
+			}
+		}()
+	}
+
+	numWorkers := min(len(paths), c.workers(0))
 	jobs := make(chan pinJob, len(paths))
-	results := make(chan *pinResponse, len(paths))
+	results := make(chan *PinResponse, len(paths))
 	errors := make(chan error, len(paths))
 
 	// start worker pool
 	for w := 0; w < numWorkers; w++ {
-		go pinFileWorker(c, jobs, results, errors)
+		go pinFileWorker(ctx, c, jobs, results, errors)
 	}
 
 	// send jobs to workers
 	for i, path := range paths {
-		var opt *PinOptions
-		if options != nil && len(*options) > i {
-			opt = &(*options)[i]
-		}
-		jobs <- pinJob{path: path, options: opt}
+		jobs <- pinJob{path: path, options: opts[i]}
 	}
 	close(jobs)
 
 	// collect results
-	var responses []*pinResponse
 	for i := 0; i < len(paths); i++ {
 		select {
 		case result := <-results:
@@ -344,12 +613,64 @@ func (c *Client) PinFilesAsync(paths []string, options *[]PinOptions) ([]*pinRes
 	return responses, nil
 }
 
+// planPinOptions copies the options of each file of PinFilesAsync, applying its NameFunc or
+// NameTemplate and its KeyValuesFunc, and detects metadata name collisions. Files without options
+// get nil.
+func planPinOptions(paths []string, options []PinOptions) ([]*PinOptions, error) {
+	names := newNamer()
+	opts := make([]*PinOptions, len(paths))
+	for i, p := range paths {
+		if len(options) <= i {
+			continue
+		}
+		opt := options[i].Clone()
+		name, ok, err := names.derive(nameData(i, p, ""), opt.NameFunc, opt.NameTemplate)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			opt.PinataMetadata.Name = name
+		}
+		if opt.KeyValuesFunc != nil {
+			opt.PinataMetadata.KeyValues = mergeKeyValues(opt.PinataMetadata.KeyValues, opt.KeyValuesFunc, p)
+		}
+		opts[i] = opt
+	}
+	return opts, nil
+}
+
+// folderPartNames derives the name of the part of each file of a folder upload, folderName joined
+// with the file's name in the folder: rels[i] by default, or the name derived by the options'
+// PartNameFunc or PartNameTemplate, with baseDir the directory the NameData Rel is relative to.
+func folderPartNames(folderName string, paths, rels []string, baseDir string, options *PinOptions) ([]string, error) {
+	names := newNamer()
+	parts := make([]string, len(paths))
+	for i, p := range paths {
+		name := rels[i]
+		if options != nil {
+			dir := baseDir
+			if dir == "" {
+				dir = filepath.Dir(p)
+			}
+			derived, ok, err := names.derive(nameData(i, p, dir), options.PartNameFunc, options.PartNameTemplate)
+			if err != nil {
+				return nil, err
+			}
+			if ok && derived != "" {
+				name = derived
+			}
+		}
+		parts[i] = fmt.Sprintf("%s/%s", folderName, name)
+	}
+	return parts, nil
+}
+
 // pinFileWorker is a worker function that processes pinning jobs concurrently.
 // It receives pinJob instances from the jobs channel, pins the file to IPFS,
-// and sends the pinResponse or any errors to the respective channels.
-func pinFileWorker(c *Client, jobs <-chan pinJob, results chan<- *pinResponse, errors chan<- error) {
+// and sends the PinResponse or any errors to the respective channels.
+func pinFileWorker(ctx context.Context, c *Client, jobs <-chan pinJob, results chan<- *PinResponse, errors chan<- error) {
 	for job := range jobs {
-		response, err := c.PinFile(job.path, job.options)
+		response, _, err := c.pinFileCheckpointed(ctx, c.checkpoints, "", job.path, job.options)
 		if err != nil {
 			errors <- err
 			return
@@ -362,46 +683,83 @@ func pinFileWorker(c *Client, jobs <-chan pinJob, results chan<- *pinResponse, e
 // The optional PinOptions parameter can be used to set metadata and other options for the pin.
 // If the URL is empty, an error is returned.
 // If there is an error fetching the URL or uploading the file, an error is returned.
-// The function returns a pinResponse containing the IPFS hash and other metadata for the pinned file.
-func (c *Client) PinURL(url string, options *PinOptions) (*pinResponse, error) {
+// The function returns a PinResponse containing the IPFS hash and other metadata for the pinned file.
+func (c *Client) PinURL(url string, options *PinOptions) (*PinResponse, error) {
+	return c.PinURLWithContext(context.Background(), url, options)
+}
+
+// PinURLWithContext is like PinURL but uses ctx for the requests.
+func (c *Client) PinURLWithContext(ctx context.Context, url string, options *PinOptions) (*PinResponse, error) {
 	if url == "" {
 		return nil, fmt.Errorf("url is required")
 	}
 
+	options, err := c.normalizePinOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
 	//  fetch the file from the URL
-	client := &http.Client{Timeout: c.httpClient.Timeout}
-	resp, err := client.Get(url) 
+	client := c.originHTTPClient()
+	fetchCtx, deadline := c.startDownload(ctx)
+	defer deadline.stop()
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching URL: %w", err)
 	}
-	defer resp.Body.Close()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching URL: %w", transportError(deadline.err(fetchCtx, err)))
+	}
+	deadline.fit(resp.ContentLength)
+	origin := deadline.body(fetchCtx, resp.Body)
+	defer origin.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error: %s", resp.Status)
+		return nil, &OriginError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	content := io.Reader(origin)
+	if options != nil && (len(options.AllowedContentTypes) > 0 || len(options.DeniedContentTypes) > 0) {
+		content, err = checkContentType(resp.Header.Get("Content-Type"), origin, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	extra, err := c.extraFormParts()
+	if err != nil {
+		return nil, err
 	}
 
 	// prepare the multipart form data
 	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	writer, err := c.newMultipartWriter(body)
+	if err != nil {
+		return nil, err
+	}
 
-	urlName := fmt.Sprintf("url_upload_%s", time.Now().String())
+	urlName := c.defaultName("url_upload")
 	if options != nil && options.PinataMetadata.Name != "" {
 		urlName = options.PinataMetadata.Name
 	}
 
+	if options != nil {
+		if err := addMetadataAndOptions(writer, options, urlName); err != nil {
+			return nil, err
+		}
+	}
+
 	part, err := writer.CreateFormFile("file", filepath.Base(url))
 	if err != nil {
 		return nil, fmt.Errorf("error creating form file: %w", err)
 	}
 
-	if _, err = io.Copy(part, resp.Body); err != nil {
+	if _, err = io.Copy(part, content); err != nil {
 		return nil, fmt.Errorf("error copying file content: %w", err)
 	}
-
-	if options != nil {
-		if err := addMetadataAndOptions(writer, options, urlName); err != nil {
-			return nil, err
-		}
+	if err := writeFormParts(writer, extra); err != nil {
+		return nil, err
 	}
 
 	err = writer.Close()
@@ -409,8 +767,11 @@ func (c *Client) PinURL(url string, options *PinOptions) (*pinResponse, error) {
 		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
 	}
 
-	var response pinResponse
-	err = c.NewRequest("POST", "/pinning/pinFileToIPFS").
+	op := c.startOp("PinURL", url)
+	defer c.endOp(op)
+
+	var response PinResponse
+	err = c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").WithContext(ctx).withActiveOp(op).
 		SetBody(body, writer.FormDataContentType()).
 		Send(&response)
 
@@ -418,136 +779,428 @@ func (c *Client) PinURL(url string, options *PinOptions) (*pinResponse, error) {
 		return nil, err
 	}
 
+	if err := c.checkCidVersion(&response, options); err != nil {
+		return nil, err
+	}
+	c.warnDuplicate(&response, options)
 	return &response, nil
 }
 
+// originHTTPClient returns the HTTP client fetching the content of PinURL and MirrorURL from its
+// origin: a client with the transport and timeout of the API requests, so that WithTransport
+// applies to the origin too, but none of the request options of the API.
+func (c *Client) originHTTPClient() *http.Client {
+	return &http.Client{Transport: c.httpClient.Transport, Timeout: c.httpClient.Timeout}
+}
+
+// checkContentType validates the origin's content type against the allowlist and denylist in options.
+// When the origin omits the Content-Type header, the first 512 bytes of the body are sniffed with
+// http.DetectContentType. The returned reader yields the complete body, including any sniffed bytes.
+// A *ContentTypeError wrapping ErrUnexpectedContentType is returned when the content type is rejected.
+func checkContentType(header string, body io.Reader, options *PinOptions) (io.Reader, error) {
+	contentType := header
+	sniffed := false
+	if contentType == "" {
+		buf := make([]byte, 512)
+		n, err := io.ReadFull(body, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("error reading content for sniffing: %w", err)
+		}
+		contentType = http.DetectContentType(buf[:n])
+		sniffed = true
+		body = io.MultiReader(bytes.NewReader(buf[:n]), body)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	rejected := false
+	if len(options.AllowedContentTypes) > 0 && !matchContentType(mediaType, options.AllowedContentTypes) {
+		rejected = true
+	}
+	if matchContentType(mediaType, options.DeniedContentTypes) {
+		rejected = true
+	}
+	if rejected {
+		return nil, &ContentTypeError{
+			ContentType: mediaType,
+			Sniffed:     sniffed,
+			Allowed:     options.AllowedContentTypes,
+			Denied:      options.DeniedContentTypes,
+		}
+	}
+
+	return body, nil
+}
+
+// matchContentType reports whether mediaType matches any of the patterns.
+// A pattern is either an exact media type ("application/pdf") or a wildcard subtype ("image/*").
+func matchContentType(mediaType string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "*/*" || pattern == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // PinFolder uploads a folder of files to IPFS using the Pinata API.
 // The filePaths parameter is a slice of file paths to be uploaded as a folder.
 // The options parameter is an optional PinOptions struct that can be used to
 // set metadata and other options for the upload.
-// The function returns a pinResponse struct containing the IPFS hash of the
+// Two files with the same base name, or with base names differing only by case, make the call
+// fail with an error wrapping ErrDuplicatePath, unless options.DisambiguatePaths is set.
+// The function returns a PinResponse struct containing the IPFS hash of the
 // uploaded folder, or an error if the upload fails.
-func (c *Client) PinFolder(filePaths []string, options *PinOptions) (*pinResponse, error) {
+func (c *Client) PinFolder(filePaths []string, options *PinOptions) (*PinResponse, error) {
+	return c.PinFolderWithContext(context.Background(), filePaths, options)
+}
+
+// PinFolderWithContext is like PinFolder but uses ctx for the requests.
+func (c *Client) PinFolderWithContext(ctx context.Context, filePaths []string, options *PinOptions) (*PinResponse, error) {
 	if len(filePaths) == 0 {
 		return nil, fmt.Errorf("at least one filepath is required")
 	}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	options, err := c.normalizePinOptions(options)
+	if err != nil {
+		return nil, err
+	}
 
-	folderName := fmt.Sprintf("folder_from_sdk_%s", time.Now().String())
+	folderName := c.defaultName("folder_from_sdk")
 	if options != nil && options.PinataMetadata.Name != "" {
 		folderName = options.PinataMetadata.Name
 	}
 
-	for _, path := range filePaths {
-		file, err := os.Open(path)
-		if err != nil {
+	rels := make([]string, len(filePaths))
+	for i, path := range filePaths {
+		if _, err := os.Stat(path); err != nil {
 			return nil, fmt.Errorf("failed to open file %s: %w", path, err)
 		}
-		defer file.Close()
-
-		part, err := writer.CreateFormFile("file", fmt.Sprintf("%s/%s", folderName, filepath.Base(path)))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create form file: %w", err)
-		}
-
-		_, err = io.Copy(part, file)
-		if err != nil {
-			return nil, fmt.Errorf("failed to copy file content: %w", err)
-		}
+		rels[i] = filepath.Base(path)
+	}
+	names, err := folderPartNames(folderName, filePaths, rels, "", options)
+	if err != nil {
+		return nil, err
+	}
+	if err := dedupePartNames(filePaths, names, options != nil && options.DisambiguatePaths); err != nil {
+		return nil, err
 	}
 
-	if options != nil {
-		if err := addMetadataAndOptions(writer, options, folderName); err != nil {
-			return nil, err
+	body, contentType, length, err := c.pinMultipartBody(func(form *multipartForm) error {
+		if options != nil {
+			if err := addMetadataAndOptions(form.Writer, options, folderName); err != nil {
+				return err
+			}
 		}
-	}
 
-	err := writer.Close()
+		for i, path := range filePaths {
+			if err := writeFormFile(form, path, names[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+		return nil, err
 	}
 
-	var response pinResponse
-	err = c.NewRequest("POST", "/pinning/pinFileToIPFS").
-		SetBody(body, writer.FormDataContentType()).
+	op := c.startOp("PinFolder", folderName)
+	defer c.endOp(op)
+
+	var response PinResponse
+	err = c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").WithContext(ctx).withActiveOp(op).
+		SetBodyFactory(body, contentType).
+		SetContentLength(length).
+		withPayloadSize(fileSizes(filePaths...)).
 		Send(&response)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := c.checkCidVersion(&response, options); err != nil {
+		return nil, err
+	}
+	c.warnDuplicate(&response, options)
 	return &response, nil
 }
 
+// dedupePartNames detects the part names of a folder upload that equal the name of an earlier
+// part, ignoring case. It returns an error wrapping ErrDuplicatePath naming both source paths, or,
+// when disambiguate is set, renames the later part by suffixing its base name with a number.
+func dedupePartNames(paths, names []string, disambiguate bool) error {
+	seen := make(map[string]int, len(names))
+	for i, name := range names {
+		key := strings.ToLower(name)
+		if j, ok := seen[key]; ok {
+			if !disambiguate {
+				return fmt.Errorf("%w: %s and %s both map to %q", ErrDuplicatePath, paths[j], paths[i], names[j])
+			}
+			ext := filepath.Ext(name)
+			stem := strings.TrimSuffix(name, ext)
+			for n := 2; ; n++ {
+				name = fmt.Sprintf("%s_%d%s", stem, n, ext)
+				key = strings.ToLower(name)
+				if _, taken := seen[key]; !taken {
+					break
+				}
+			}
+			names[i] = name
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
 // PinNestedFolders pins the files in the provided paths, relative to the baseDir, to IPFS using the Pinata API.
 //
 // The baseDir parameter specifies the base directory for the relative paths in the paths parameter.
 // The paths parameter is a slice of file paths, relative to the baseDir, that will be pinned to IPFS.
 // The options parameter can be used to provide additional metadata and options for the pin operation.
 //
+// Two files mapping to the same path in the folder, or to paths differing only by case, which
+// would collide when the folder is extracted on a case-insensitive filesystem, make the call fail
+// with an error wrapping ErrDuplicatePath, unless options.DisambiguatePaths is set.
+//
 // This function returns a PinResponse containing the IPFS hash and other details of the pinned data,
 // or an error if the operation fails.
-func (c *Client) PinNestedFolders(baseDir string, paths []string, options *PinOptions) (*pinResponse, error) {
+func (c *Client) PinNestedFolders(baseDir string, paths []string, options *PinOptions) (*PinResponse, error) {
+	return c.pinNestedFolders(context.Background(), baseDir, paths, options)
+}
+
+// PinNestedFoldersWithContext is like PinNestedFolders but uses ctx for the requests.
+func (c *Client) PinNestedFoldersWithContext(ctx context.Context, baseDir string, paths []string, options *PinOptions) (*PinResponse, error) {
+	return c.pinNestedFolders(ctx, baseDir, paths, options)
+}
+
+// pinNestedFolders implements PinNestedFolders, using ctx for the upload request.
+func (c *Client) pinNestedFolders(ctx context.Context, baseDir string, paths []string, options *PinOptions) (*PinResponse, error) {
 	if baseDir == "" || len(paths) == 0 {
 		return nil, fmt.Errorf("base dir and at least one filepath is required")
 	}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	options, err := c.normalizePinOptions(options)
+	if err != nil {
+		return nil, err
+	}
 
-	folderName := fmt.Sprintf("folder_from_sdk_%s", time.Now().String())
+	folderName := c.defaultName("folder_from_sdk")
 	if options != nil && options.PinataMetadata.Name != "" {
 		folderName = options.PinataMetadata.Name
 	}
 
-	for _, path := range paths {
-		file, err := os.Open(path)
-		if err != nil {
+	rels := make([]string, len(paths))
+	for i, path := range paths {
+		if _, err := os.Stat(path); err != nil {
 			return nil, fmt.Errorf("failed to open file %s: %w", path, err)
 		}
-		defer file.Close()
-
 		relPath, err := filepath.Rel(baseDir, path)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get relative path: %w", err)
 		}
-		part, err := writer.CreateFormFile("file", fmt.Sprintf("%s/%s", folderName, relPath))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create form file: %w", err)
+		rels[i] = relPath
+	}
+	names, err := folderPartNames(folderName, paths, rels, baseDir, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := dedupePartNames(paths, names, options != nil && options.DisambiguatePaths); err != nil {
+		return nil, err
+	}
+
+	body, contentType, length, err := c.pinMultipartBody(func(form *multipartForm) error {
+		if options != nil {
+			if err := addMetadataAndOptions(form.Writer, options, folderName); err != nil {
+				return err
+			}
+		}
+
+		for i, path := range paths {
+			if err := writeFormFile(form, path, names[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	op := c.startOp("PinNestedFolders", baseDir)
+	defer c.endOp(op)
+
+	var response PinResponse
+	err = c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").
+		WithContext(ctx).
+		withActiveOp(op).
+		SetBodyFactory(body, contentType).
+		SetContentLength(length).
+		withPayloadSize(fileSizes(paths...)).
+		Send(&response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkCidVersion(&response, options); err != nil {
+		return nil, err
+	}
+	c.warnDuplicate(&response, options)
+	return &response, nil
+}
+
+// newMultipartWriter returns a multipart writer for an upload body, using the boundary configured
+// with WithMultipartBoundary, if any.
+func (c *Client) newMultipartWriter(w io.Writer) (*multipart.Writer, error) {
+	writer := multipart.NewWriter(w)
+	if c.multipartBoundary != "" {
+		if err := writer.SetBoundary(c.multipartBoundary); err != nil {
+			return nil, fmt.Errorf("invalid multipart boundary: %w", err)
 		}
+	}
+	return writer, nil
+}
+
+// multipartForm is the multipart writer handed to the write function of multipartBody. When size
+// is set, the form is only being measured: file parts add the size of the file on disk to size
+// instead of copying its content. When digest is set, the sha256 digest of the file content
+// written to the body is recorded in it.
+type multipartForm struct {
+	*multipart.Writer
+	size   *countingWriter
+	digest *uploadDigest
+}
+
+// countingWriter is an io.Writer that discards its input and counts the bytes written to it.
+// unknown is set when a part of unknown length was added, making the count meaningless.
+type countingWriter struct {
+	n       int64
+	unknown bool
+}
 
-		_, err = io.Copy(part, file)
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// multipartBody returns a retry-safe body factory for a multipart upload. Every call of the factory
+// streams a fresh body produced by write through a pipe, so files are re-read from disk on each
+// attempt instead of being held in memory. The returned content type carries the boundary, which
+// is shared by all attempts.
+//
+// When WithMultipartContentLength is enabled, the returned length is the exact size of the body,
+// computed by running write once in sizing mode, in which file parts are measured with os.Stat
+// instead of being read. Otherwise, or when a part has no known length, the length is -1 and the
+// body is sent chunked.
+func (c *Client) multipartBody(write func(*multipartForm) error) (func() (io.ReadCloser, error), string, int64, error) {
+	writer, err := c.newMultipartWriter(io.Discard)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	boundary, contentType := writer.Boundary(), writer.FormDataContentType()
+
+	length := int64(-1)
+	if c.multipartContentLength {
+		counter := &countingWriter{}
+		form := &multipartForm{Writer: multipart.NewWriter(counter), size: counter}
+		err := form.SetBoundary(boundary)
+		if err == nil {
+			err = write(form)
+		}
+		if err == nil {
+			err = form.Close()
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to copy file content: %w", err)
+			return nil, "", 0, err
+		}
+		if !counter.unknown {
+			length = counter.n
 		}
 	}
 
-	if options != nil {
-		if err := addMetadataAndOptions(writer, options, folderName); err != nil {
-			return nil, err
+	factory := func() (io.ReadCloser, error) {
+		pr, pw := io.Pipe()
+		go func() {
+			form := &multipartForm{Writer: multipart.NewWriter(pw)}
+			err := form.SetBoundary(boundary)
+			if err == nil {
+				err = write(form)
+			}
+			if err == nil {
+				err = form.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+		return pr, nil
+	}
+	return factory, contentType, length, nil
+}
+
+// writeFormFile streams the file at path into a "file" part named name. When form is being
+// measured, only the size of the file is counted.
+func writeFormFile(form *multipartForm, path, name string) error {
+	if form.size != nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", path, err)
 		}
+		return writeFormReader(form, nil, info.Size(), name)
 	}
 
-	err := writer.Close()
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+		return fmt.Errorf("failed to open file %s: %w", path, err)
 	}
+	defer file.Close()
 
-	var response pinResponse
-	err = c.NewRequest("POST", "/pinning/pinFileToIPFS").
-		SetBody(body, writer.FormDataContentType()).
-		Send(&response)
+	return writeFormReader(form, file, -1, name)
+}
 
+// writeFormReader streams content into a "file" part named name. size is the length of content,
+// or -1 when it is unknown; it is only used, and content is not read, when form is being measured.
+// The digest of the written content is recorded in form.digest, if set.
+func writeFormReader(form *multipartForm, content io.Reader, size int64, name string) error {
+	part, err := form.CreateFormFile("file", name)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to create form file: %w", err)
 	}
 
-	return &response, nil
+	if form.size != nil {
+		if size < 0 {
+			form.size.unknown = true
+		} else {
+			form.size.n += size
+		}
+		return nil
+	}
+
+	if form.digest == nil {
+		_, err = io.Copy(part, content)
+		if err != nil {
+			return fmt.Errorf("failed to copy file content: %w", err)
+		}
+		return nil
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(part, hasher), content)
+	if err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	form.digest.set(hex.EncodeToString(hasher.Sum(nil)))
+	return nil
 }
 
 // addMetadataAndOptions adds metadata and options to the multipart writer for a file upload to Pinata.
+// The fields are written before any file part, so servers and proxies see them before large file
+// content.
 // The folderName parameter is used as the name for the metadata, and the options.PinataMetadata.KeyValues
 // are included as additional metadata. The options.PinataOptions.CidVersion is also included as an option.
 func addMetadataAndOptions(writer *multipart.Writer, options *PinOptions, folderName string) error {
@@ -584,38 +1237,106 @@ func addMetadataAndOptions(writer *multipart.Writer, options *PinOptions, folder
 // The data parameter should be a JSON-serializable Go value. The options parameter
 // can be used to provide additional metadata and options for the pin operation.
 //
+// Maps are encoded with sorted keys, but the field order of structs and of pre-encoded values
+// such as json.RawMessage is kept; set options.CanonicalJSON to sort every object.
+//
 // This function returns a PinResponse containing the IPFS hash and other details
 // of the pinned data, or an error if the operation fails.
-func (c *Client) PinJSON(data interface{}, options *PinOptions) (*pinResponse, error) {
+func (c *Client) PinJSON(data interface{}, options *PinOptions) (*PinResponse, error) {
+	return c.pinJSON(context.Background(), data, options)
+}
+
+// PinJSONWithContext is like PinJSON but uses ctx for the requests.
+func (c *Client) PinJSONWithContext(ctx context.Context, data interface{}, options *PinOptions) (*PinResponse, error) {
+	return c.pinJSON(ctx, data, options)
+}
+
+// pinJSON implements PinJSON, using ctx for the request.
+func (c *Client) pinJSON(ctx context.Context, data interface{}, options *PinOptions) (*PinResponse, error) {
+	return c.pinJSONBuffered(ctx, data, options, nil)
+}
+
+// pinJSONBuffered implements PinJSON. With the default codec, a non-nil buf holds the encoded
+// request body, so that batches of small documents reuse a buffer per worker instead of allocating
+// a body per document; buf may be reused once pinJSONBuffered returned.
+func (c *Client) pinJSONBuffered(ctx context.Context, data interface{}, options *PinOptions, buf *bytes.Buffer) (*PinResponse, error) {
 	if data == nil {
 		return nil, fmt.Errorf("jsonData is required")
 	}
+	options, err := c.normalizePinOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	target := ""
+	if options != nil {
+		target = options.PinataMetadata.Name
+	}
+	op := c.startOp("PinJSON", target)
+	defer c.endOp(op)
+
+	req := c.NewRequest(http.MethodPost, "/pinning/pinJSONToIPFS").WithContext(ctx).withActiveOp(op)
+
 	payload := make(map[string]interface{})
 	payload["pinataContent"] = data
 
 	if options != nil {
 		payload["pinataOptions"] = options.PinataOptions
 		payload["pinataMetadata"] = options.PinataMetadata
+
+		if options.CanonicalJSON {
+			content, err := req.codec().Marshal(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal JSON content: %w", err)
+			}
+			if content, err = canonicalJSON(content); err != nil {
+				return nil, err
+			}
+			payload["pinataContent"] = json.RawMessage(content)
+		}
 	}
 
-	req, err := c.NewRequest(http.MethodPost, "/pinning/pinJSONToIPFS").SetJSONBody(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to set JSON body: %w", err)
+	if buf != nil && c.codec == nil {
+		// json.Encoder writes what json.Marshal returns, followed by a newline
+		buf.Reset()
+		if err := json.NewEncoder(buf).Encode(payload); err != nil {
+			return nil, fmt.Errorf("failed to set JSON body: %w", err)
+		}
+		req.SetBody(bytes.NewReader(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), "application/json")
+	} else {
+		req, err = req.SetJSONBody(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set JSON body: %w", err)
+		}
 	}
 
-	var response pinResponse
+	var response PinResponse
 	err = req.Send(&response)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := c.checkCidVersion(&response, options); err != nil {
+		return nil, err
+	}
+	c.warnDuplicate(&response, options)
 	return &response, nil
 }
 
 // PinByCid pins the content identified by the provided hashToPin to IPFS using the Pinata API.
 // The optional PinByCidOptions can be used to provide additional metadata and options for the pin operation.
 // Returns a PinByCidResponse containing information about the pinned content.
-func (c *Client) PinByCid(hashToPin string, options *PinByCidOptions) (*pinByCidResponse, error) {
+func (c *Client) PinByCid(hashToPin string, options *PinByCidOptions) (*PinByCidResponse, error) {
+	return c.pinByCid(context.Background(), hashToPin, options)
+}
+
+// PinByCidWithContext is like PinByCid but uses ctx for the requests.
+func (c *Client) PinByCidWithContext(ctx context.Context, hashToPin string, options *PinByCidOptions) (*PinByCidResponse, error) {
+	return c.pinByCid(ctx, hashToPin, options)
+}
+
+// pinByCid implements PinByCid, using ctx for the request.
+func (c *Client) pinByCid(ctx context.Context, hashToPin string, options *PinByCidOptions) (*PinByCidResponse, error) {
 	if hashToPin == "" {
 		return nil, fmt.Errorf("hashToPin is required")
 	}
@@ -623,16 +1344,22 @@ func (c *Client) PinByCid(hashToPin string, options *PinByCidOptions) (*pinByCid
 	payload["hashToPin"] = hashToPin
 
 	if options != nil {
+		metadata := options.PinataMetadata
+		name, err := c.normalizeMetadataName(metadata.Name)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Name = name
 		payload["pinataOptions"] = options.PinataOptions
-		payload["pinataMetadata"] = options.PinataMetadata
+		payload["pinataMetadata"] = metadata
 	}
 
-	req, err := c.NewRequest(http.MethodPost, "/pinning/pinByHash").SetJSONBody(payload)
+	req, err := c.NewRequest(http.MethodPost, "/pinning/pinByHash").WithContext(ctx).SetJSONBody(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set JSON body: %w", err)
 	}
 
-	var response pinByCidResponse
+	var response PinByCidResponse
 	err = req.Send(&response)
 	if err != nil {
 		return nil, err
@@ -643,35 +1370,160 @@ func (c *Client) PinByCid(hashToPin string, options *PinByCidOptions) (*pinByCid
 
 // ListFiles returns a list of files that have been pinned to Pinata.
 // The options parameter can be used to filter the list of files.
-func (c *Client) ListFiles(options *ListFilesOptions) (*listFilesResponse, error) {
-	req := c.NewRequest(http.MethodGet, "/data/pinList")
+func (c *Client) ListFiles(options *ListFilesOptions) (*ListFilesResponse, error) {
+	return c.listFiles(context.Background(), options)
+}
+
+// ListFilesWithContext is like ListFiles but uses ctx for the requests.
+func (c *Client) ListFilesWithContext(ctx context.Context, options *ListFilesOptions) (*ListFilesResponse, error) {
+	return c.listFiles(ctx, options)
+}
+
+// PinLookupOptions represents the options for looking up pinned content by CID.
+// IncludeUnpinned also matches rows of content that has been unpinned, which are ignored by default.
+type PinLookupOptions struct {
+	IncludeUnpinned bool
+}
+
+// GetPinByCid returns the pinList row of cid. An active row is preferred over the rows of
+// unpinned content, which are only returned with options.IncludeUnpinned.
+// Returns an error wrapping ErrPinNotFound if no row matches.
+func (c *Client) GetPinByCid(ctx context.Context, cid string, options *PinLookupOptions) (*Pin, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("cid is required")
+	}
+	includeUnpinned := options != nil && options.IncludeUnpinned
+
+	filter := &ListFilesOptions{Cid: cid, Status: "pinned"}
+	if includeUnpinned {
+		filter.Status = "all"
+	}
+	var active, unpinned *Pin
+	err := c.forEachPinPage(ctx, filter, func(rows []Pin) error {
+		for _, row := range rows {
+			if row.IPFSPinHash != cid {
+				continue
+			}
+			if row.IsActive() && active == nil {
+				active = &row
+			} else if !row.IsActive() && unpinned == nil {
+				unpinned = &row
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case active != nil:
+		return active, nil
+	case unpinned != nil && includeUnpinned:
+		return unpinned, nil
+	}
+	return nil, fmt.Errorf("%w: no pinned content with cid %s", ErrPinNotFound, cid)
+}
+
+// IsPinned reports whether cid is pinned on the account, i.e. listed by pinList with no unpin
+// date. With options.IncludeUnpinned, content that has been unpinned is reported too.
+func (c *Client) IsPinned(ctx context.Context, cid string, options *PinLookupOptions) (bool, error) {
+	_, err := c.GetPinByCid(ctx, cid, options)
+	if errors.Is(err, ErrPinNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// listFiles implements ListFiles, using ctx for the request.
+func (c *Client) listFiles(ctx context.Context, options *ListFilesOptions) (*ListFilesResponse, error) {
+	req := c.NewRequest(http.MethodGet, "/data/pinList").WithContext(ctx)
 	if options != nil {
+		if err := options.Validate(); err != nil {
+			return nil, err
+		}
 		req.setListPinsQueryParams(options)
 	}
 
-	var response listFilesResponse
+	var response ListFilesResponse
 	err := req.Send(&response)
 	if err != nil {
 		return nil, err
 	}
+	response.Warnings = req.warnings
 
 	return &response, nil
 }
 
+// forEachPinPage lists pinned content page by page, calling fn with the rows of every page.
+// Only pinned content is listed unless filter sets a different status. Rate limited pages are
+// retried at the same offset as configured with WithPagination.
+func (c *Client) forEachPinPage(ctx context.Context, filter *ListFilesOptions, fn func([]Pin) error) error {
+	options := ListFilesOptions{Status: "pinned"}
+	if filter != nil {
+		options = *filter.Clone()
+		if options.Status == "" {
+			options.Status = "pinned"
+		}
+	}
+	options.PageLimit = MaxPinListPageLimit
+	options.PageOffset = 0
+
+	pages := c.newPaginator("/data/pinList")
+	for {
+		var response *ListFilesResponse
+		err := pages.fetch(ctx, func() (int, error) {
+			var err error
+			response, err = c.listFiles(ctx, &options)
+			if err != nil {
+				return 0, err
+			}
+			return len(response.Rows), nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(response.Rows) > 0 {
+			if err := fn(response.Rows); err != nil {
+				return err
+			}
+		}
+		if len(response.Rows) < options.PageLimit {
+			return nil
+		}
+		options.PageOffset += len(response.Rows)
+	}
+}
+
 // ListPinByCidJobs returns a list of pin jobs for the provided ListPinByCidOptions.
 // The ListPinByCidOptions can be used to filter the list of pin jobs.
-// Returns a listPinByCidResponse containing information about the pin jobs.
-func (c *Client) ListPinByCidJobs(options *ListPinByCidOptions) (*listPinByCidResponse, error) {
-	req := c.NewRequest(http.MethodGet, "/pinning/pinJobs")
+// Returns a ListPinByCidResponse containing information about the pin jobs.
+func (c *Client) ListPinByCidJobs(options *ListPinByCidOptions) (*ListPinByCidResponse, error) {
+	return c.listPinByCidJobs(context.Background(), options)
+}
+
+// ListPinByCidJobsWithContext is like ListPinByCidJobs but uses ctx for the requests.
+func (c *Client) ListPinByCidJobsWithContext(ctx context.Context, options *ListPinByCidOptions) (*ListPinByCidResponse, error) {
+	return c.listPinByCidJobs(ctx, options)
+}
+
+// listPinByCidJobs implements ListPinByCidJobs, using ctx for the request.
+func (c *Client) listPinByCidJobs(ctx context.Context, options *ListPinByCidOptions) (*ListPinByCidResponse, error) {
+	return c.sendPinJobs(c.NewRequest(http.MethodGet, "/pinning/pinJobs").WithContext(ctx), options)
+}
+
+// sendPinJobs sends req, a pinJobs listing, with the query parameters of options.
+func (c *Client) sendPinJobs(req *requestBuilder, options *ListPinByCidOptions) (*ListPinByCidResponse, error) {
 	if options != nil {
 		req.setListPinsByCidQueryParams(options)
 	}
 
-	var response listPinByCidResponse
+	var response ListPinByCidResponse
 	err := req.Send(&response)
 	if err != nil {
 		return nil, err
 	}
+	response.Warnings = req.warnings
 
 	return &response, nil
 }
@@ -679,18 +1531,46 @@ func (c *Client) ListPinByCidJobs(options *ListPinByCidOptions) (*listPinByCidRe
 // UpdateFileMetadata updates the metadata for a file that has been pinned to Pinata.
 // The fileHash parameter specifies the hash of the file to update.
 // The options parameter specifies the new metadata to apply, including the name and key-value pairs.
+// Only the fields that are set are sent, so a name-only update keeps the existing keyvalues; see
+// PinMetadataUpdateOptions for how keyvalues are removed.
 // Returns an error if the fileHash or options are not provided, or if there is an error updating the metadata.
 func (c *Client) UpdateFileMetadata(fileHash string, options *PinMetadataUpdateOptions) error {
+	return c.updateFileMetadata(context.Background(), fileHash, options)
+}
+
+// UpdateFileMetadataWithContext is like UpdateFileMetadata but uses ctx for the requests.
+func (c *Client) UpdateFileMetadataWithContext(ctx context.Context, fileHash string, options *PinMetadataUpdateOptions) error {
+	return c.updateFileMetadata(ctx, fileHash, options)
+}
+
+// updateFileMetadata implements UpdateFileMetadata, using ctx for the request.
+func (c *Client) updateFileMetadata(ctx context.Context, fileHash string, options *PinMetadataUpdateOptions) error {
 	if fileHash == "" || options == nil {
 		return fmt.Errorf("fileHash and options are required")
 	}
+	name, err := c.normalizeMetadataName(options.Name)
+	if err != nil {
+		return err
+	}
 
 	payload := make(map[string]interface{})
 	payload["ipfsPinHash"] = fileHash // "ipfsPinHash" wasn't shown as a query param in the docs. Inform the pinata team
-	payload["name"] = options.Name
-	payload["keyvalues"] = options.KeyValues
+	if name != "" {
+		payload["name"] = name
+	}
+	// Pinata merges the "keyvalues" field into the existing keyvalues, removing only the keys set to
+	// nil; a nil map leaves them untouched, so the field is only sent when set
+	if options.KeyValues != nil {
+		payload["keyvalues"] = options.KeyValues
+	}
+
+	unlock, err := c.lockCids(ctx, fileHash)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	req, err := c.NewRequest(http.MethodPut, "/pinning/hashMetadata").SetJSONBody(payload)
+	req, err := c.NewRequest(http.MethodPut, "/pinning/hashMetadata").WithContext(ctx).SetJSONBody(payload)
 	if err != nil {
 		return fmt.Errorf("failed to set JSON body: %w", err)
 	}
@@ -706,11 +1586,22 @@ func (c *Client) UpdateFileMetadata(fileHash string, options *PinMetadataUpdateO
 // If the cid parameter is an empty string, an error is returned.
 // Returns an error if the file could not be deleted.
 func (c *Client) DeleteFile(cid string) error {
+	return c.DeleteFileWithContext(context.Background(), cid)
+}
+
+// DeleteFileWithContext is like DeleteFile but uses ctx for the requests.
+func (c *Client) DeleteFileWithContext(ctx context.Context, cid string) error {
 	if cid == "" {
 		return fmt.Errorf("cid is required")
 	}
 
-	err := c.NewRequest(http.MethodDelete, "/pinning/unpin/{cid}").
+	unlock, err := c.lockCids(ctx, cid)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = c.NewRequest(http.MethodDelete, "/pinning/unpin/{cid}").WithContext(ctx).
 		AddPathParam("cid", cid).
 		Send(nil)
 
@@ -720,22 +1611,76 @@ func (c *Client) DeleteFile(cid string) error {
 	return nil
 }
 
+// Unpin unpins the content with the given CID from the Pinata service. It is an alias of DeleteFile.
+func (c *Client) Unpin(cid string) error {
+	return c.UnpinWithContext(context.Background(), cid)
+}
+
+// UnpinWithContext is like Unpin but uses ctx for the requests.
+func (c *Client) UnpinWithContext(ctx context.Context, cid string) error {
+	return c.DeleteFileWithContext(ctx, cid)
+}
+
+// DeleteFileByID unpins the pinned content with the given pinList row ID.
+// The ID is resolved to its CID by walking the pinned content, since the API only unpins by CID.
+// Returns an error wrapping ErrPinNotFound if no pinned content has the ID, and an error wrapping
+// ErrAmbiguousPinID if the ID matches rows with different CIDs, in which case nothing is unpinned.
+func (c *Client) DeleteFileByID(id string) error {
+	return c.DeleteFileByIDWithContext(context.Background(), id)
+}
+
+// DeleteFileByIDWithContext is like DeleteFileByID but uses ctx for the requests.
+func (c *Client) DeleteFileByIDWithContext(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id is required")
+	}
+
+	var cids []string
+	err := c.forEachPinPage(ctx, nil, func(rows []Pin) error {
+		for _, row := range rows {
+			if row.ID == id && !slices.Contains(cids, row.IPFSPinHash) {
+				cids = append(cids, row.IPFSPinHash)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch len(cids) {
+	case 0:
+		return fmt.Errorf("%w: no pinned content with id %s", ErrPinNotFound, id)
+	case 1:
+		return c.DeleteFileWithContext(ctx, cids[0])
+	default:
+		return fmt.Errorf("%w: id %s matches %s", ErrAmbiguousPinID, id, strings.Join(cids, ", "))
+	}
+}
+
 // DeleteFilesAsync deletes the files with the given CIDs (content identifiers) from the Pinata service asynchronously.
-// It uses a worker pool to delete the files concurrently, up to a maximum of 5 workers.
+// It uses a worker pool to delete the files concurrently, up to the client concurrency
+// (5 unless set with WithConcurrency).
 // If any of the files fail to delete, the corresponding error is returned in the slice of errors.
 // If no CIDs are provided, an error is returned.
 func (c *Client) DeleteFilesAsync(cids []string) []error {
+	return c.DeleteFilesAsyncWithContext(context.Background(), cids)
+}
+
+// DeleteFilesAsyncWithContext is like DeleteFilesAsync but uses ctx for the requests. Once ctx is
+// done, the deletions in flight are aborted and those not yet sent fail with the context error.
+func (c *Client) DeleteFilesAsyncWithContext(ctx context.Context, cids []string) []error {
 	if len(cids) == 0 {
 		return []error{fmt.Errorf("at least one CID is required")}
 	}
 
-	numWorkers := min(len(cids), 5)
+	numWorkers := min(len(cids), c.workers(0))
 	jobs := make(chan string, len(cids))
 	errors := make(chan error, len(cids))
 
 	// start worker pool
 	for w := 0; w < numWorkers; w++ {
-		go deleteFileWorker(c, jobs, errors)
+		go deleteFileWorker(ctx, c, jobs, errors)
 	}
 
 	// send jobs to workers
@@ -755,13 +1700,13 @@ func (c *Client) DeleteFilesAsync(cids []string) []error {
 	return errs
 }
 
-// deleteFileWorker is a worker function that deletes files asynchronously. 
-// It receives CIDs (content identifiers) from the jobs channel, 
-// deletes the corresponding files using the DeleteFile method, 
+// deleteFileWorker is a worker function that deletes files asynchronously.
+// It receives CIDs (content identifiers) from the jobs channel,
+// deletes the corresponding files using the DeleteFileWithContext method,
 // and sends any errors to the errors channel.
-func deleteFileWorker(c *Client, jobs <-chan string, errors chan<- error) {
+func deleteFileWorker(ctx context.Context, c *Client, jobs <-chan string, errors chan<- error) {
 	for cid := range jobs {
-		if err := c.DeleteFile(cid); err != nil {
+		if err := c.DeleteFileWithContext(ctx, cid); err != nil {
 			errors <- fmt.Errorf("failed to delete CID %s: %w", cid, err)
 		} else {
 			errors <- nil
@@ -1,20 +1,59 @@
 package pinata
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
 )
 
+var updateGolden = flag.Bool("update", false, "rewrite the golden request bodies in testdata")
+
+// goldenTime is the time of the generated names in golden request bodies.
+var goldenTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// requireGolden compares body with the golden file testdata/name, rewriting it when -update is set.
+func requireGolden(t *testing.T, name string, body []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, body, 0644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(body))
+}
+
+// goldenServer is a mock server that records the body of every pin request and serves
+// "origin content" at /origin.
+func goldenServer(t *testing.T, bodies *[][]byte) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/origin" {
+			w.Write([]byte("origin content"))
+			return
+		}
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		*bodies = append(*bodies, raw)
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
 func TestPinFile(t *testing.T) {
 	t.Run("successful file pinning", func(t *testing.T) {
 		auth := &Auth{jwt: "valid_jwt_token"}
@@ -45,7 +84,7 @@ func TestPinFile(t *testing.T) {
 			require.Equal(t, "Test content", string(content))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"IpfsHash":"Qm123456","PinSize":123,"Timestamp":"2023-05-01T12:00:00Z"}`))
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -54,9 +93,9 @@ func TestPinFile(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "Qm123456", response.IpfsHash)
-		require.Equal(t, 123, response.PinSize)
-		require.Equal(t, "2023-05-01T12:00:00Z", response.Timestamp)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Equal(t, 13, response.PinSize)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Timestamp)
 	})
 
 	t.Run("empty file path", func(t *testing.T) {
@@ -105,7 +144,7 @@ func TestPinFile(t *testing.T) {
 			require.Equal(t, "test_name", options.PinataMetadata.Name)
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"IpfsHash":"Qm789012","PinSize":456,"Timestamp":"2023-05-02T12:00:00Z"}`))
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -119,9 +158,9 @@ func TestPinFile(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "Qm789012", response.IpfsHash)
-		require.Equal(t, 456, response.PinSize)
-		require.Equal(t, "2023-05-02T12:00:00Z", response.Timestamp)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Equal(t, 13, response.PinSize)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Timestamp)
 	})
 
 	t.Run("server error", func(t *testing.T) {
@@ -167,7 +206,7 @@ func TestPinJSON(t *testing.T) {
 			require.Contains(t, payload, "pinataContent")
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"IpfsHash":"Qm987654","PinSize":789,"Timestamp":"2023-05-03T12:00:00Z"}`))
+			w.Write(fixture(t, "POST /pinning/pinJSONToIPFS"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -177,9 +216,9 @@ func TestPinJSON(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "Qm987654", response.IpfsHash)
-		require.Equal(t, 789, response.PinSize)
-		require.Equal(t, "2023-05-03T12:00:00Z", response.Timestamp)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Equal(t, 13, response.PinSize)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Timestamp)
 	})
 
 	t.Run("nil data", func(t *testing.T) {
@@ -214,7 +253,7 @@ func TestPinJSON(t *testing.T) {
 			require.Equal(t, "test_json", metadata["name"])
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"IpfsHash":"Qm135790","PinSize":246,"Timestamp":"2023-05-04T12:00:00Z"}`))
+			w.Write(fixture(t, "POST /pinning/pinJSONToIPFS"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -234,9 +273,63 @@ func TestPinJSON(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "Qm135790", response.IpfsHash)
-		require.Equal(t, 246, response.PinSize)
-		require.Equal(t, "2023-05-04T12:00:00Z", response.Timestamp)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Equal(t, 13, response.PinSize)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Timestamp)
+	})
+
+	t.Run("canonical JSON", func(t *testing.T) {
+		// the mock derives the CID from the raw pinataContent and reports repeated content as a duplicate
+		var contents []string
+		seen := make(map[string]bool)
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				PinataContent json.RawMessage `json:"pinataContent"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			content := string(payload.PinataContent)
+			contents = append(contents, content)
+			duplicate := seen[content]
+			seen[content] = true
+			fmt.Fprintf(w, `{"IpfsHash":"Qm%x","PinSize":10,"Timestamp":"2023-05-01T12:00:00Z","isDuplicate":%t}`, len(seen), duplicate)
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		first := map[string]interface{}{
+			"name":  "doc",
+			"tags":  []interface{}{"b", "a"},
+			"attrs": json.RawMessage(`{"z": 1, "a": {"y": 2.50, "b": null}}`),
+		}
+		second := struct {
+			Tags  []string        `json:"tags"`
+			Attrs json.RawMessage `json:"attrs"`
+			Name  string          `json:"name"`
+		}{
+			Tags:  []string{"b", "a"},
+			Attrs: json.RawMessage(`{"a":{"b":null,"y":2.50},"z":1}`),
+			Name:  "doc",
+		}
+
+		options := &PinOptions{CanonicalJSON: true}
+		firstResponse, err := client.PinJSON(first, options)
+		require.NoError(t, err)
+		secondResponse, err := client.PinJSON(second, options)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			`{"attrs":{"a":{"b":null,"y":2.50},"z":1},"name":"doc","tags":["b","a"]}`,
+			`{"attrs":{"a":{"b":null,"y":2.50},"z":1},"name":"doc","tags":["b","a"]}`,
+		}, contents)
+		require.False(t, firstResponse.IsDuplicate)
+		require.True(t, secondResponse.IsDuplicate)
+		require.Equal(t, firstResponse.IpfsHash, secondResponse.IpfsHash)
+
+		_, err = client.PinJSON(second, nil)
+		require.NoError(t, err)
+		require.NotEqual(t, contents[0], contents[2])
 	})
 
 	t.Run("server error", func(t *testing.T) {
@@ -273,21 +366,21 @@ func TestPinByCid(t *testing.T) {
 			var payload map[string]interface{}
 			err := json.NewDecoder(r.Body).Decode(&payload)
 			require.NoError(t, err)
-			require.Equal(t, "QmTestHash123", payload["hashToPin"])
+			require.Equal(t, fixtures.Cid, payload["hashToPin"])
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"id":"test_id","ipfsHash":"QmTestHash123","status":"pinned"}`))
+			w.Write(fixture(t, "POST /pinning/pinByHash"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
-		response, err := client.PinByCid("QmTestHash123", nil)
+		response, err := client.PinByCid(fixtures.Cid, nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "test_id", response.ID)
-		require.Equal(t, "QmTestHash123", response.IpfsHash)
-		require.Equal(t, "pinned", response.Status)
+		require.Equal(t, fixtures.JobID, response.ID)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Equal(t, "prechecking", response.Status)
 	})
 
 	t.Run("empty hash to pin", func(t *testing.T) {
@@ -309,7 +402,7 @@ func TestPinByCid(t *testing.T) {
 			var payload map[string]interface{}
 			err := json.NewDecoder(r.Body).Decode(&payload)
 			require.NoError(t, err)
-			require.Equal(t, "QmTestHash456", payload["hashToPin"])
+			require.Equal(t, fixtures.Cid, payload["hashToPin"])
 			require.Contains(t, payload, "pinataOptions")
 			require.Contains(t, payload, "pinataMetadata")
 
@@ -321,7 +414,7 @@ func TestPinByCid(t *testing.T) {
 			require.Equal(t, "test_pin", metadata["name"])
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"id":"test_id_2","ipfsHash":"QmTestHash456","status":"pinned","created":"2023-05-06T12:00:00Z"}`))
+			w.Write(fixture(t, "POST /pinning/pinByHash"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -338,13 +431,13 @@ func TestPinByCid(t *testing.T) {
 				Name: "test_pin",
 			},
 		}
-		response, err := client.PinByCid("QmTestHash456", options)
+		response, err := client.PinByCid(fixtures.Cid, options)
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "test_id_2", response.ID)
-		require.Equal(t, "QmTestHash456", response.IpfsHash)
-		require.Equal(t, "pinned", response.Status)
+		require.Equal(t, fixtures.JobID, response.ID)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Equal(t, "prechecking", response.Status)
 	})
 
 	t.Run("server error", func(t *testing.T) {
@@ -375,9 +468,10 @@ func TestListFiles(t *testing.T) {
 			require.Equal(t, "/data/pinList", r.URL.Path)
 			require.Equal(t, http.MethodGet, r.Method)
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
+			require.NotContains(t, r.Header, "Content-Type")
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"count":2,"rows":[{"id":"file1","ipfs_pin_hash":"Qm123","size":100,"user_id":"user1","date_pinned":"2023-05-07T12:00:00Z"},{"id":"file2","ipfs_pin_hash":"Qm456","size":200,"user_id":"user1","date_pinned":"2023-05-08T12:00:00Z"}]}`))
+			w.Write(fixture(t, "GET /data/pinList"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -386,13 +480,13 @@ func TestListFiles(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, 2, response.Count)
-		require.Len(t, response.Rows, 2)
-		require.Equal(t, "file1", response.Rows[0].ID)
-		require.Equal(t, "Qm123", response.Rows[0].IPFSPinHash)
-		require.Equal(t, 100, response.Rows[0].Size)
-		require.Equal(t, "user1", response.Rows[0].UserID)
-		require.Equal(t, "2023-05-07T12:00:00Z", response.Rows[0].DatePinned)
+		require.Equal(t, 1, response.Count)
+		require.Len(t, response.Rows, 1)
+		require.Equal(t, fixtures.PinID, response.Rows[0].ID)
+		require.Equal(t, fixtures.Cid, response.Rows[0].IPFSPinHash)
+		require.Equal(t, 13, response.Rows[0].Size)
+		require.Equal(t, "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d", response.Rows[0].UserID)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Rows[0].DatePinned)
 	})
 
 	t.Run("list files with options", func(t *testing.T) {
@@ -410,7 +504,7 @@ func TestListFiles(t *testing.T) {
 			// require.Equal(t, "test", query.Get("metadata[name]"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"count":1,"rows":[{"id":"file3","ipfs_pin_hash":"Qm789","size":300,"user_id":"user1","date_pinned":"2023-05-09T12:00:00Z"}]}`))
+			w.Write(fixture(t, "GET /data/pinList"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -428,11 +522,11 @@ func TestListFiles(t *testing.T) {
 		require.NotNil(t, response)
 		require.Equal(t, 1, response.Count)
 		require.Len(t, response.Rows, 1)
-		require.Equal(t, "file3", response.Rows[0].ID)
-		require.Equal(t, "Qm789", response.Rows[0].IPFSPinHash)
-		require.Equal(t, 300, response.Rows[0].Size)
-		require.Equal(t, "user1", response.Rows[0].UserID)
-		require.Equal(t, "2023-05-09T12:00:00Z", response.Rows[0].DatePinned)
+		require.Equal(t, fixtures.PinID, response.Rows[0].ID)
+		require.Equal(t, fixtures.Cid, response.Rows[0].IPFSPinHash)
+		require.Equal(t, 13, response.Rows[0].Size)
+		require.Equal(t, "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d", response.Rows[0].UserID)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Rows[0].DatePinned)
 	})
 
 	t.Run("empty response", func(t *testing.T) {
@@ -484,7 +578,7 @@ func TestListPinByCidJobs(t *testing.T) {
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"count":2,"rows":[{"id":"job1","ipfs_pin_hash":"Qm123","status":"retrieving","date_queued":"2023-05-10T12:00:00Z"},{"id":"job2","ipfs_pin_hash":"Qm456","status":"retrieving","date_queued":"2023-05-11T12:00:00Z"}]}`))
+			w.Write(fixture(t, "GET /pinning/pinJobs"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -493,12 +587,12 @@ func TestListPinByCidJobs(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, 2, response.Count)
-		require.Len(t, response.Rows, 2)
-		require.Equal(t, "job1", response.Rows[0].ID)
-		require.Equal(t, "Qm123", response.Rows[0].IPFSPinHash)
+		require.Equal(t, 1, response.Count)
+		require.Len(t, response.Rows, 1)
+		require.Equal(t, fixtures.JobID, response.Rows[0].ID)
+		require.Equal(t, fixtures.Cid, response.Rows[0].IPFSPinHash)
 		require.Equal(t, "retrieving", response.Rows[0].Status)
-		require.Equal(t, "2023-05-10T12:00:00Z", response.Rows[0].DateQueued)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Rows[0].DateQueued)
 	})
 
 	t.Run("list pin by CID jobs with options", func(t *testing.T) {
@@ -516,14 +610,14 @@ func TestListPinByCidJobs(t *testing.T) {
 			require.Equal(t, "retrieving", query.Get("status"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"count":1,"rows":[{"id":"job3","ipfs_pin_hash":"Qm789","status":"retrieving","date_queued":"2023-05-12T12:00:00Z"}]}`))
+			w.Write(fixture(t, "GET /pinning/pinJobs"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
 		options := &ListPinByCidOptions{
-			Limit:  5,
-			Offset: 10,
+			Limit:  Int(5),
+			Offset: Int(10),
 			Status: "retrieving",
 		}
 		response, err := client.ListPinByCidJobs(options)
@@ -532,10 +626,10 @@ func TestListPinByCidJobs(t *testing.T) {
 		require.NotNil(t, response)
 		require.Equal(t, 1, response.Count)
 		require.Len(t, response.Rows, 1)
-		require.Equal(t, "job3", response.Rows[0].ID)
-		require.Equal(t, "Qm789", response.Rows[0].IPFSPinHash)
+		require.Equal(t, fixtures.JobID, response.Rows[0].ID)
+		require.Equal(t, fixtures.Cid, response.Rows[0].IPFSPinHash)
 		require.Equal(t, "retrieving", response.Rows[0].Status)
-		require.Equal(t, "2023-05-12T12:00:00Z", response.Rows[0].DateQueued)
+		require.Equal(t, "2024-05-21T19:27:03.391Z", response.Rows[0].DateQueued)
 	})
 
 	t.Run("empty response", func(t *testing.T) {
@@ -653,6 +747,61 @@ func TestUpdateFileMetadata(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Internal server error")
 	})
+
+	payloads := []struct {
+		name     string
+		options  *PinMetadataUpdateOptions
+		expected map[string]interface{}
+	}{
+		{
+			name:     "name only",
+			options:  &PinMetadataUpdateOptions{Name: "Renamed"},
+			expected: map[string]interface{}{"ipfsPinHash": "QmTestHash123", "name": "Renamed"},
+		},
+		{
+			name:    "keyvalues only",
+			options: &PinMetadataUpdateOptions{KeyValues: map[string]interface{}{"env": "prod"}},
+			expected: map[string]interface{}{
+				"ipfsPinHash": "QmTestHash123",
+				"keyvalues":   map[string]interface{}{"env": "prod"},
+			},
+		},
+		{
+			name:    "explicit clear of a key",
+			options: &PinMetadataUpdateOptions{KeyValues: map[string]interface{}{"env": nil}},
+			expected: map[string]interface{}{
+				"ipfsPinHash": "QmTestHash123",
+				"keyvalues":   map[string]interface{}{"env": nil},
+			},
+		},
+		{
+			name:    "explicit empty keyvalues",
+			options: &PinMetadataUpdateOptions{Name: "Renamed", KeyValues: map[string]interface{}{}},
+			expected: map[string]interface{}{
+				"ipfsPinHash": "QmTestHash123",
+				"name":        "Renamed",
+				"keyvalues":   map[string]interface{}{},
+			},
+		},
+	}
+	for _, tc := range payloads {
+		t.Run("payload with "+tc.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer mockServer.Close()
+
+			client := New(&Auth{jwt: "valid_jwt_token"})
+			client.baseURL = mockServer.URL
+
+			err := client.UpdateFileMetadata("QmTestHash123", tc.options)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, payload)
+		})
+	}
 }
 
 func TestDeleteFile(t *testing.T) {
@@ -737,6 +886,111 @@ func TestDeleteFile(t *testing.T) {
 	})
 }
 
+func TestUnpin(t *testing.T) {
+	t.Run("successful unpin", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/pinning/unpin/QmTestCID123", r.URL.Path)
+			require.Equal(t, http.MethodDelete, r.Method)
+
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+		client.baseURL = mockServer.URL
+
+		require.NoError(t, client.Unpin("QmTestCID123"))
+	})
+
+	t.Run("empty CID", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		err := client.Unpin("")
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cid is required")
+	})
+}
+
+func TestDeleteFileByID(t *testing.T) {
+	// newPinListServer serves rows on pinList and records the CIDs unpinned.
+	newPinListServer := func(t *testing.T, rows string, unpinned *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/data/pinList":
+				require.Equal(t, "pinned", r.URL.Query().Get("status"))
+				w.Write([]byte(`{"rows":` + rows + `}`))
+			case strings.HasPrefix(r.URL.Path, "/pinning/unpin/"):
+				require.Equal(t, http.MethodDelete, r.Method)
+				*unpinned = append(*unpinned, strings.TrimPrefix(r.URL.Path, "/pinning/unpin/"))
+				w.WriteHeader(http.StatusOK)
+			default:
+				t.Fatalf("unexpected request to %s", r.URL.Path)
+			}
+		}))
+	}
+
+	t.Run("resolves id to cid", func(t *testing.T) {
+		var unpinned []string
+		mockServer := newPinListServer(t, `[{"id":"pin-1","ipfs_pin_hash":"QmOne"},{"id":"pin-2","ipfs_pin_hash":"QmTwo"}]`, &unpinned)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		require.NoError(t, client.DeleteFileByID("pin-2"))
+		require.Equal(t, []string{"QmTwo"}, unpinned)
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		var unpinned []string
+		mockServer := newPinListServer(t, `[{"id":"pin-1","ipfs_pin_hash":"QmOne"}]`, &unpinned)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		err := client.DeleteFileByID("pin-404")
+		require.ErrorIs(t, err, ErrPinNotFound)
+		require.Empty(t, unpinned)
+	})
+
+	t.Run("ambiguous id", func(t *testing.T) {
+		var unpinned []string
+		mockServer := newPinListServer(t, `[{"id":"pin-1","ipfs_pin_hash":"QmOne"},{"id":"pin-1","ipfs_pin_hash":"QmOther"}]`, &unpinned)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		err := client.DeleteFileByID("pin-1")
+		require.ErrorIs(t, err, ErrAmbiguousPinID)
+		require.Contains(t, err.Error(), "QmOne, QmOther")
+		require.Empty(t, unpinned)
+	})
+
+	t.Run("duplicate rows of the same cid", func(t *testing.T) {
+		var unpinned []string
+		mockServer := newPinListServer(t, `[{"id":"pin-1","ipfs_pin_hash":"QmOne"},{"id":"pin-1","ipfs_pin_hash":"QmOne"}]`, &unpinned)
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		require.NoError(t, client.DeleteFileByID("pin-1"))
+		require.Equal(t, []string{"QmOne"}, unpinned)
+	})
+
+	t.Run("empty id", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		err := client.DeleteFileByID("")
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "id is required")
+	})
+}
+
 func TestDeleteFilesAsync(t *testing.T) {
 	t.Run("successful delete multiple files", func(t *testing.T) {
 		auth := &Auth{jwt: "valid_jwt_token"}
@@ -849,7 +1103,7 @@ func TestDeleteFileWorker(t *testing.T) {
 		jobs <- "QmTestCID3"
 		close(jobs)
 
-		go deleteFileWorker(client, jobs, errors)
+		go deleteFileWorker(context.Background(), client, jobs, errors)
 
 		for i := 0; i < 3; i++ {
 			err := <-errors
@@ -880,7 +1134,7 @@ func TestDeleteFileWorker(t *testing.T) {
 		jobs <- "QmTestCID3"
 		close(jobs)
 
-		go deleteFileWorker(client, jobs, errors)
+		go deleteFileWorker(context.Background(), client, jobs, errors)
 
 		errorCount := 0
 		for i := 0; i < 3; i++ {
@@ -912,7 +1166,7 @@ func TestDeleteFileWorker(t *testing.T) {
 		jobs <- "QmTestCID3"
 		close(jobs)
 
-		go deleteFileWorker(client, jobs, errors)
+		go deleteFileWorker(context.Background(), client, jobs, errors)
 
 		for i := 0; i < 3; i++ {
 			err := <-errors
@@ -931,7 +1185,7 @@ func TestDeleteFileWorker(t *testing.T) {
 
 		close(jobs)
 
-		go deleteFileWorker(client, jobs, errors)
+		go deleteFileWorker(context.Background(), client, jobs, errors)
 
 		select {
 		case err := <-errors:
@@ -949,7 +1203,7 @@ func TestPinFilesAsync(t *testing.T) {
 
 		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"IpfsHash":"QmTest","PinSize":100,"Timestamp":"2023-05-15T12:00:00Z"}`))
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -974,9 +1228,9 @@ func TestPinFilesAsync(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, responses, 3)
 		for _, response := range responses {
-			require.Equal(t, "QmTest", response.IpfsHash)
-			require.Equal(t, 100, response.PinSize)
-			require.Equal(t, "2023-05-15T12:00:00Z", response.Timestamp)
+			require.Equal(t, fixtures.Cid, response.IpfsHash)
+			require.Equal(t, 13, response.PinSize)
+			require.Equal(t, "2024-05-21T19:27:03.391Z", response.Timestamp)
 		}
 	})
 
@@ -1008,7 +1262,7 @@ func TestPinFilesAsync(t *testing.T) {
 			require.Equal(t, "test_name", options.PinataMetadata.Name)
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"IpfsHash":"QmTest","PinSize":100,"Timestamp":"2023-05-15T12:00:00Z"}`))
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -1040,14 +1294,14 @@ func TestPinFilesAsync(t *testing.T) {
 			},
 		}
 
-		responses, err := client.PinFilesAsync(filePaths, &options)
+		responses, err := client.PinFilesAsync(filePaths, options)
 
 		require.NoError(t, err)
 		require.Len(t, responses, 2)
 		for _, response := range responses {
-			require.Equal(t, "QmTest", response.IpfsHash)
-			require.Equal(t, 100, response.PinSize)
-			require.Equal(t, "2023-05-15T12:00:00Z", response.Timestamp)
+			require.Equal(t, fixtures.Cid, response.IpfsHash)
+			require.Equal(t, 13, response.PinSize)
+			require.Equal(t, "2024-05-21T19:27:03.391Z", response.Timestamp)
 		}
 	})
 
@@ -1077,9 +1331,701 @@ func TestPinFilesAsync(t *testing.T) {
 			},
 		}
 
-		responses, err := client.PinFilesAsync(filePaths, &options)
+		responses, err := client.PinFilesAsync(filePaths, options)
 
 		require.Error(t, err)
 		require.Nil(t, responses)
 	})
 }
+
+func TestPinURLContentType(t *testing.T) {
+	newOrigin := func(contentType string, body []byte) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if contentType == "" {
+				// an empty slice prevents net/http from sniffing and setting the header itself
+				w.Header()["Content-Type"] = []string{}
+			} else {
+				w.Header().Set("Content-Type", contentType)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(body)
+		}))
+	}
+
+	t.Run("allowlisted content type", func(t *testing.T) {
+		origin := newOrigin("image/png; charset=binary", []byte("png bytes"))
+		defer origin.Close()
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := r.ParseMultipartForm(10 << 20)
+			require.NoError(t, err)
+
+			file, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			defer file.Close()
+
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+			require.Equal(t, "png bytes", string(content))
+
+			w.WriteHeader(http.StatusOK)
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		response, err := client.PinURL(origin.URL+"/image.png", &PinOptions{
+			AllowedContentTypes: []string{"image/*", "application/pdf"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+	})
+
+	t.Run("denied content type", func(t *testing.T) {
+		origin := newOrigin("text/html; charset=utf-8", []byte("<html>not found</html>"))
+		defer origin.Close()
+
+		pinCalled := false
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pinCalled = true
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		response, err := client.PinURL(origin.URL+"/image.png", &PinOptions{
+			AllowedContentTypes: []string{"image/*"},
+			DeniedContentTypes:  []string{"text/html"},
+		})
+
+		require.Error(t, err)
+		require.Nil(t, response)
+		require.False(t, pinCalled)
+		require.ErrorIs(t, err, ErrUnexpectedContentType)
+
+		var ctErr *ContentTypeError
+		require.ErrorAs(t, err, &ctErr)
+		require.Equal(t, "text/html", ctErr.ContentType)
+		require.False(t, ctErr.Sniffed)
+	})
+
+	t.Run("missing header falls back to sniffing", func(t *testing.T) {
+		pdf := []byte("%PDF-1.4\n%fake pdf document")
+		origin := newOrigin("", pdf)
+		defer origin.Close()
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := r.ParseMultipartForm(10 << 20)
+			require.NoError(t, err)
+
+			file, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			defer file.Close()
+
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+			require.Equal(t, pdf, content)
+
+			w.WriteHeader(http.StatusOK)
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		response, err := client.PinURL(origin.URL+"/doc", &PinOptions{
+			AllowedContentTypes: []string{"application/pdf"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+
+		_, err = client.PinURL(origin.URL+"/doc", &PinOptions{
+			AllowedContentTypes: []string{"image/*"},
+		})
+		var ctErr *ContentTypeError
+		require.ErrorAs(t, err, &ctErr)
+		require.Equal(t, "application/pdf", ctErr.ContentType)
+		require.True(t, ctErr.Sniffed)
+	})
+}
+
+// recordingTransport records the paths of the requests it sends with http.DefaultTransport.
+type recordingTransport struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.paths = append(rt.paths, req.URL.Path)
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestPinURLOrigin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/origin" {
+			w.Write([]byte("origin content"))
+			return
+		}
+		serveFixture(w, r)
+	}))
+	defer server.Close()
+
+	t.Run("client transport", func(t *testing.T) {
+		transport := &recordingTransport{}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithTransport(transport))
+		client.baseURL = server.URL
+
+		_, err := client.PinURL(server.URL+"/origin", nil)
+		require.NoError(t, err)
+		_, err = client.MirrorURL(context.Background(), server.URL+"/origin", nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"/origin", "/pinning/pinFileToIPFS", "/origin", "/pinning/pinFileToIPFS"}, transport.paths)
+	})
+
+	t.Run("unreachable origin", func(t *testing.T) {
+		origin := httptest.NewServer(http.NotFoundHandler())
+		origin.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+
+		_, err := client.PinURL(origin.URL+"/origin", nil)
+		var transportErr *TransportError
+		require.ErrorAs(t, err, &transportErr)
+	})
+}
+
+func TestMultipartFieldOrder(t *testing.T) {
+	dir := writeTree(t, "a.txt", "nested/b.txt")
+	fileA := filepath.Join(dir, "a.txt")
+	fileB := filepath.Join(dir, "nested", "b.txt")
+
+	// fieldOrderServer asserts that the upload body uses the fixed boundary and that
+	// pinataMetadata and pinataOptions come before every file part.
+	fieldOrderServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/origin":
+			w.Write([]byte("origin content"))
+			return
+		case "/pinning/pinFileToIPFS":
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+
+		require.Equal(t, "multipart/form-data; boundary=fixed-boundary-1234", r.Header.Get("Content-Type"))
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body := string(raw)
+
+		metadata := strings.Index(body, `name="pinataMetadata"`)
+		options := strings.Index(body, `name="pinataOptions"`)
+		file := strings.Index(body, `name="file"`)
+		require.True(t, strings.HasPrefix(body, "--fixed-boundary-1234\r\n"))
+		require.NotEqual(t, -1, metadata)
+		require.NotEqual(t, -1, options)
+		require.NotEqual(t, -1, file)
+		require.Less(t, metadata, options)
+		require.Less(t, options, file)
+
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}))
+	defer fieldOrderServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartBoundary("fixed-boundary-1234"))
+	client.baseURL = fieldOrderServer.URL
+	options := &PinOptions{PinataMetadata: PinataMetadata{Name: "ordered", KeyValues: map[string]interface{}{"k": "v"}}}
+
+	_, err := client.PinFile(fileA, options)
+	require.NoError(t, err)
+
+	_, err = client.PinURL(fieldOrderServer.URL+"/origin", options)
+	require.NoError(t, err)
+
+	_, err = client.PinFolder([]string{fileA, fileB}, options)
+	require.NoError(t, err)
+
+	_, err = client.PinNestedFolders(dir, []string{fileA, fileB}, options)
+	require.NoError(t, err)
+
+	_, err = client.MirrorURL(context.Background(), fieldOrderServer.URL+"/origin", &MirrorOptions{PinOptions: options})
+	require.NoError(t, err)
+
+	t.Run("invalid boundary", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartBoundary("invalid boundary "))
+		client.baseURL = fieldOrderServer.URL
+
+		_, err := client.PinFile(fileA, options)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid multipart boundary")
+	})
+}
+
+func TestMultipartContentLength(t *testing.T) {
+	dir := writeTree(t, "a.txt", "nested/b.txt")
+	fileA := filepath.Join(dir, "a.txt")
+	fileB := filepath.Join(dir, "nested", "b.txt")
+
+	type upload struct {
+		contentLength    int64
+		transferEncoding []string
+		size             int
+	}
+	var uploads []upload
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		uploads = append(uploads, upload{r.ContentLength, r.TransferEncoding, len(raw)})
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}))
+	defer mockServer.Close()
+
+	options := &PinOptions{PinataMetadata: PinataMetadata{Name: "folder", KeyValues: map[string]interface{}{"k": "v"}}}
+
+	t.Run("enabled", func(t *testing.T) {
+		uploads = nil
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartContentLength(true))
+		client.baseURL = mockServer.URL
+
+		_, err := client.PinFolder([]string{fileA, fileB}, options)
+		require.NoError(t, err)
+		_, err = client.PinNestedFolders(dir, []string{fileA, fileB}, nil)
+		require.NoError(t, err)
+		_, err = client.PinFile(fileA, options)
+		require.NoError(t, err)
+
+		require.Len(t, uploads, 3)
+		for _, u := range uploads {
+			require.Empty(t, u.transferEncoding)
+			require.Equal(t, int64(u.size), u.contentLength)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		uploads = nil
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		_, err := client.PinFolder([]string{fileA, fileB}, options)
+		require.NoError(t, err)
+
+		require.Len(t, uploads, 1)
+		require.Equal(t, []string{"chunked"}, uploads[0].transferEncoding)
+		require.Equal(t, int64(-1), uploads[0].contentLength)
+	})
+
+	t.Run("file changed after sizing", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartContentLength(true))
+		client.baseURL = mockServer.URL
+
+		body, contentType, length, err := client.multipartBody(func(form *multipartForm) error {
+			return writeFormFile(form, fileA, "a.txt")
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(fileA, []byte("content that grew after sizing"), 0644))
+
+		err = client.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").
+			SetBodyFactory(body, contentType).
+			SetContentLength(length).
+			Send(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestPinOpenFile(t *testing.T) {
+	type upload struct {
+		contentLength int64
+		fileName      string
+		content       string
+	}
+	var uploads []upload
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/pinning/pinFileToIPFS", r.URL.Path)
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		uploads = append(uploads, upload{r.ContentLength, header.Filename, string(content)})
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartContentLength(true))
+	client.baseURL = mockServer.URL
+
+	t.Run("unlinked regular file", func(t *testing.T) {
+		uploads = nil
+		path := filepath.Join(writeTree(t, "open.txt"), "open.txt")
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		require.NoError(t, os.Remove(path))
+		_, err = f.Seek(5, io.SeekStart)
+		require.NoError(t, err)
+
+		response, err := client.PinOpenFile(f, "", &PinOptions{PinataMetadata: PinataMetadata{Name: "open"}})
+
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Len(t, uploads, 1)
+		require.Equal(t, "open.txt", uploads[0].fileName)
+		require.Equal(t, "content of open.txt", uploads[0].content)
+		require.Greater(t, uploads[0].contentLength, int64(0))
+
+		offset, err := f.Seek(0, io.SeekCurrent)
+		require.NoError(t, err)
+		require.Equal(t, int64(5), offset)
+	})
+
+	t.Run("pipe", func(t *testing.T) {
+		uploads = nil
+		pr, pw, err := os.Pipe()
+		require.NoError(t, err)
+		defer pr.Close()
+		go func() {
+			pw.Write([]byte("streamed through a pipe"))
+			pw.Close()
+		}()
+
+		_, err = client.PinOpenFile(pr, "piped.txt", nil)
+
+		require.NoError(t, err)
+		require.Len(t, uploads, 1)
+		require.Equal(t, "piped.txt", uploads[0].fileName)
+		require.Equal(t, "streamed through a pipe", uploads[0].content)
+		require.Equal(t, int64(-1), uploads[0].contentLength)
+	})
+
+	t.Run("nil file", func(t *testing.T) {
+		_, err := client.PinOpenFile(nil, "", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "file is required")
+	})
+
+	t.Run("closed file", func(t *testing.T) {
+		f, err := os.Open(filepath.Join(writeTree(t, "closed.txt"), "closed.txt"))
+		require.NoError(t, err)
+		f.Close()
+
+		_, err = client.PinOpenFile(f, "", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to stat file")
+	})
+}
+
+func TestGetPinByCid(t *testing.T) {
+	const cid = "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"
+	// the content was pinned, unpinned, then pinned again
+	const pinnedAgain = `{"count": 2, "rows": [
+		{"id": "row-unpinned", "ipfs_pin_hash": "` + cid + `", "date_pinned": "2024-01-01T00:00:00.000Z", "date_unpinned": "2024-02-01T00:00:00.000Z"},
+		{"id": "row-active", "ipfs_pin_hash": "` + cid + `", "date_pinned": "2024-03-01T00:00:00.000Z", "date_unpinned": null}
+	]}`
+	const unpinnedOnly = `{"count": 1, "rows": [
+		{"id": "row-unpinned", "ipfs_pin_hash": "` + cid + `", "date_pinned": "2024-01-01T00:00:00.000Z", "date_unpinned": "2024-02-01T00:00:00.000Z"}
+	]}`
+
+	newClient := func(t *testing.T, body string, statuses *[]string) *Client {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/data/pinList", r.URL.Path)
+			require.Equal(t, cid, r.URL.Query().Get("cid"))
+			*statuses = append(*statuses, r.URL.Query().Get("status"))
+			if r.URL.Query().Get("pageOffset") != "" && r.URL.Query().Get("pageOffset") != "0" {
+				w.Write([]byte(`{"count": 0, "rows": []}`))
+				return
+			}
+			w.Write([]byte(body))
+		}))
+		t.Cleanup(mockServer.Close)
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+		return client
+	}
+
+	t.Run("row status", func(t *testing.T) {
+		var response ListFilesResponse
+		require.NoError(t, json.Unmarshal([]byte(pinnedAgain), &response))
+
+		require.False(t, response.Rows[0].IsActive())
+		require.Equal(t, "unpinned", response.Rows[0].Status())
+		require.True(t, response.Rows[1].IsActive())
+		require.Equal(t, "pinned", response.Rows[1].Status())
+		require.True(t, Pin{DateUnpinned: "0001-01-01T00:00:00Z"}.IsActive())
+	})
+
+	t.Run("prefers the active row", func(t *testing.T) {
+		var statuses []string
+		client := newClient(t, pinnedAgain, &statuses)
+
+		row, err := client.GetPinByCid(context.Background(), cid, nil)
+		require.NoError(t, err)
+		require.Equal(t, "row-active", row.ID)
+		require.Equal(t, "pinned", statuses[0])
+
+		pinned, err := client.IsPinned(context.Background(), cid, nil)
+		require.NoError(t, err)
+		require.True(t, pinned)
+	})
+
+	t.Run("ignores unpinned rows by default", func(t *testing.T) {
+		var statuses []string
+		client := newClient(t, unpinnedOnly, &statuses)
+
+		_, err := client.GetPinByCid(context.Background(), cid, nil)
+		require.ErrorIs(t, err, ErrPinNotFound)
+
+		pinned, err := client.IsPinned(context.Background(), cid, nil)
+		require.NoError(t, err)
+		require.False(t, pinned)
+	})
+
+	t.Run("includes unpinned rows on request", func(t *testing.T) {
+		var statuses []string
+		client := newClient(t, unpinnedOnly, &statuses)
+		options := &PinLookupOptions{IncludeUnpinned: true}
+
+		row, err := client.GetPinByCid(context.Background(), cid, options)
+		require.NoError(t, err)
+		require.Equal(t, "row-unpinned", row.ID)
+		require.Equal(t, "all", statuses[0])
+
+		pinned, err := client.IsPinned(context.Background(), cid, options)
+		require.NoError(t, err)
+		require.True(t, pinned)
+	})
+
+	t.Run("missing cid", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		_, err := client.GetPinByCid(context.Background(), "", nil)
+		require.Error(t, err)
+	})
+}
+
+func TestListFilesOversizedMetadata(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"count": 0, "rows": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	metadata := make(map[string]interface{})
+	for i := 0; i < 200; i++ {
+		metadata[fmt.Sprintf("key%03d", i)] = map[string]interface{}{"value": strings.Repeat("v", 20), "op": "eq"}
+	}
+	_, err := client.ListFiles(&ListFilesOptions{Metadata: metadata})
+
+	require.ErrorIs(t, err, ErrQueryTooLong)
+	require.Contains(t, err.Error(), `query parameter "metadata"`)
+	require.Zero(t, requests)
+
+	_, err = client.ListFiles(&ListFilesOptions{Metadata: map[string]interface{}{"env": map[string]interface{}{"value": "prod", "op": "eq"}}})
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+}
+
+func TestPinFolderDuplicatePaths(t *testing.T) {
+	dir := writeTree(t, "docs/a.txt", "docs/b.txt", "docs/B.TXT", "notes/a.txt")
+	fileA := filepath.Join(dir, "docs", "a.txt")
+	sameA := filepath.Join(dir, "docs", "..", "docs", "a.txt")
+	fileB := filepath.Join(dir, "docs", "b.txt")
+	upperB := filepath.Join(dir, "docs", "B.TXT")
+	otherA := filepath.Join(dir, "notes", "a.txt")
+
+	var parts []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		require.NoError(t, err)
+		parts = nil
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			// part.FileName drops the directories, so the name is read from the header
+			_, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			require.NoError(t, err)
+			if params["name"] == "file" {
+				parts = append(parts, params["filename"])
+			}
+		}
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+	options := &PinOptions{PinataMetadata: PinataMetadata{Name: "folder"}}
+
+	t.Run("exact duplicate", func(t *testing.T) {
+		parts = nil
+		_, err := client.PinNestedFolders(dir, []string{fileA, sameA}, options)
+		require.ErrorIs(t, err, ErrDuplicatePath)
+		require.Contains(t, err.Error(), fileA)
+		require.Contains(t, err.Error(), sameA)
+		require.Nil(t, parts)
+	})
+
+	t.Run("case-only collision", func(t *testing.T) {
+		parts = nil
+		_, err := client.PinNestedFolders(dir, []string{fileB, upperB}, options)
+		require.ErrorIs(t, err, ErrDuplicatePath)
+		require.Contains(t, err.Error(), fileB)
+		require.Contains(t, err.Error(), upperB)
+		require.Nil(t, parts)
+	})
+
+	t.Run("disambiguated", func(t *testing.T) {
+		disambiguate := &PinOptions{PinataMetadata: PinataMetadata{Name: "folder"}, DisambiguatePaths: true}
+		_, err := client.PinNestedFolders(dir, []string{fileA, sameA, fileB, upperB, sameA}, disambiguate)
+		require.NoError(t, err)
+		require.Equal(t, []string{"folder/docs/a.txt", "folder/docs/a_2.txt", "folder/docs/b.txt", "folder/docs/B_2.TXT", "folder/docs/a_3.txt"}, parts)
+	})
+
+	t.Run("distinct paths", func(t *testing.T) {
+		_, err := client.PinNestedFolders(dir, []string{fileA, fileB}, options)
+		require.NoError(t, err)
+		require.Equal(t, []string{"folder/docs/a.txt", "folder/docs/b.txt"}, parts)
+	})
+
+	t.Run("flat folder", func(t *testing.T) {
+		parts = nil
+		_, err := client.PinFolder([]string{fileA, otherA}, options)
+		require.ErrorIs(t, err, ErrDuplicatePath)
+		require.Contains(t, err.Error(), fileA)
+		require.Contains(t, err.Error(), otherA)
+		require.Nil(t, parts)
+
+		_, err = client.PinFolder([]string{fileB, upperB}, options)
+		require.ErrorIs(t, err, ErrDuplicatePath)
+		require.Nil(t, parts)
+	})
+
+	t.Run("flat folder disambiguated", func(t *testing.T) {
+		disambiguate := &PinOptions{PinataMetadata: PinataMetadata{Name: "folder"}, DisambiguatePaths: true}
+		_, err := client.PinFolder([]string{fileA, otherA, fileB, upperB}, disambiguate)
+		require.NoError(t, err)
+		require.Equal(t, []string{"folder/a.txt", "folder/a_2.txt", "folder/b.txt", "folder/B_2.TXT"}, parts)
+	})
+}
+
+func TestPinFolderNameTemplate(t *testing.T) {
+	dir := writeTree(t, "a.txt", "docs/b.md", "docs/c.json")
+	paths := []string{
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "docs", "b.md"),
+		filepath.Join(dir, "docs", "c.json"),
+	}
+
+	var parts []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		require.NoError(t, err)
+		parts = nil
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			// part.FileName drops the directories, so the name is read from the header
+			_, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			require.NoError(t, err)
+			if params["name"] == "file" {
+				parts = append(parts, params["filename"])
+			}
+		}
+		w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	t.Run("nested tree", func(t *testing.T) {
+		_, err := client.PinNestedFolders(dir, paths, &PinOptions{
+			PinataMetadata:   PinataMetadata{Name: "site"},
+			PartNameTemplate: "v1/{{.Rel}}",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"site/v1/a.txt", "site/v1/docs/b.md", "site/v1/docs/c.json"}, parts)
+	})
+
+	t.Run("flat folder", func(t *testing.T) {
+		_, err := client.PinFolder(paths, &PinOptions{
+			PinataMetadata: PinataMetadata{Name: "site"},
+			PartNameFunc:   func(path string) string { return "v1-" + filepath.Base(path) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"site/v1-a.txt", "site/v1-b.md", "site/v1-c.json"}, parts)
+	})
+
+	t.Run("metadata names leave parts unchanged", func(t *testing.T) {
+		_, err := client.PinNestedFolders(dir, paths, &PinOptions{
+			PinataMetadata: PinataMetadata{Name: "site"},
+			NameTemplate:   "v1/{{.Rel}}",
+			NameFunc:       func(path string) string { return "v1-" + filepath.Base(path) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"site/a.txt", "site/docs/b.md", "site/docs/c.json"}, parts)
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		parts = nil
+		_, err := client.PinNestedFolders(dir, paths, &PinOptions{
+			PinataMetadata:   PinataMetadata{Name: "site"},
+			PartNameTemplate: "{{.Dir}}",
+		})
+		require.ErrorIs(t, err, ErrNameCollision)
+		require.Contains(t, err.Error(), paths[2])
+		require.Nil(t, parts)
+	})
+}
+
+func TestPinFolderGoldenBody(t *testing.T) {
+	dir := writeTree(t, "a.txt", "nested/b.txt")
+	var bodies [][]byte
+	server := goldenServer(t, &bodies)
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartBoundary("golden-boundary"), withNameClock(func() time.Time { return goldenTime }))
+	client.baseURL = server.URL
+
+	files := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "nested", "b.txt")}
+	_, err := client.PinFolder(files, &PinOptions{
+		PinataMetadata: PinataMetadata{KeyValues: map[string]interface{}{"env": "test"}},
+		PinataOptions:  Options{CidVersion: 1},
+	})
+	require.NoError(t, err)
+	_, err = client.PinFolder(files, &PinOptions{PinataMetadata: PinataMetadata{Name: "site"}})
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 2)
+	requireGolden(t, "pin_folder_default_name.golden", bodies[0])
+	requireGolden(t, "pin_folder_named.golden", bodies[1])
+}
+
+func TestPinURLGoldenBody(t *testing.T) {
+	var bodies [][]byte
+	server := goldenServer(t, &bodies)
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithMultipartBoundary("golden-boundary"), withNameClock(func() time.Time { return goldenTime }))
+	client.baseURL = server.URL
+
+	_, err := client.PinURL(server.URL+"/origin", &PinOptions{
+		PinataMetadata: PinataMetadata{KeyValues: map[string]interface{}{"env": "test"}},
+	})
+	require.NoError(t, err)
+	_, err = client.PinURL(server.URL+"/origin", &PinOptions{PinataMetadata: PinataMetadata{Name: "origin"}})
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 2)
+	requireGolden(t, "pin_url_default_name.golden", bodies[0])
+	requireGolden(t, "pin_url_named.golden", bodies[1])
+}
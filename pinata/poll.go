@@ -0,0 +1,103 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// PollKeepAlive configures the connections of the polling helpers, WatchPinJobs and the waits of
+// PinCidToGroup and GroupsClient.PinCid, which may poll for an hour through middleboxes that reap
+// idle connections.
+// TCPKeepAlive is the interval between the TCP keep-alive probes of the connections dialed for
+// polls, which keep middleboxes from considering them idle (15 seconds when zero); a negative value
+// disables the probes.
+// IdleConnTimeout is how long a connection is kept open between polls (the 90 seconds of the
+// client when zero); set it below the idle timeout of the middleboxes to dial a new connection
+// rather than reuse a connection they dropped.
+// FreshConnection closes the connection after every poll, so each poll dials a new one.
+type PollKeepAlive struct {
+	TCPKeepAlive    time.Duration
+	IdleConnTimeout time.Duration
+	FreshConnection bool
+}
+
+// WithPollKeepAlive sets the connection settings of the polling helpers. Their requests go through
+// a connection pool of their own, so the settings do not affect the other requests of the client.
+// TCPKeepAlive and IdleConnTimeout only apply when the transport of the client is an
+// *http.Transport without a DialContext function of its own; FreshConnection always applies.
+//
+// Whether or not it is set, a poll whose connection is dropped, e.g. with an EOF, is made again
+// once on a new connection, which is reported as a WarningPollReconnected.
+func WithPollKeepAlive(policy PollKeepAlive) ClientOption {
+	return func(c *Client) {
+		c.pollKeepAlive = &policy
+	}
+}
+
+// pollHTTPClient returns the HTTP client of poll requests: the client of the other requests, or a
+// client with a transport configured by WithPollKeepAlive.
+func (c *Client) pollHTTPClient() *http.Client {
+	c.pollOnce.Do(func() {
+		c.pollClient = c.httpClient
+		base, ok := c.httpClient.Transport.(*http.Transport)
+		policy := c.pollKeepAlive
+		if policy == nil || !ok {
+			return
+		}
+
+		transport := base.Clone()
+		if policy.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = policy.IdleConnTimeout
+		}
+		if policy.TCPKeepAlive != 0 && transport.DialContext == nil {
+			dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: policy.TCPKeepAlive}
+			transport.DialContext = dialer.DialContext
+		}
+		c.pollClient = &http.Client{Transport: transport, Timeout: c.httpClient.Timeout}
+	})
+	return c.pollClient
+}
+
+// forPolling marks the request as a poll of a polling helper, sent with pollHTTPClient. When fresh
+// is set, or with PollKeepAlive.FreshConnection, it is sent on a new connection, closed afterwards.
+func (rb *requestBuilder) forPolling(fresh bool) *requestBuilder {
+	rb.polling = true
+	rb.freshConn = fresh || (rb.client.pollKeepAlive != nil && rb.client.pollKeepAlive.FreshConnection)
+	return rb
+}
+
+// httpClient returns the HTTP client the request is sent with.
+func (rb *requestBuilder) httpClient() *http.Client {
+	if rb.polling {
+		return rb.client.pollHTTPClient()
+	}
+	return rb.client.httpClient
+}
+
+// pollPinJobs lists pin by CID jobs for the polling helpers. A poll whose connection was dropped is
+// made again once on a new connection.
+func (c *Client) pollPinJobs(ctx context.Context, options *ListPinByCidOptions) (*ListPinByCidResponse, error) {
+	response, err := c.sendPinJobs(c.NewRequest(http.MethodGet, "/pinning/pinJobs").WithContext(ctx).forPolling(false), options)
+	if err == nil || ctx.Err() != nil || !connDropped(err) {
+		return response, err
+	}
+
+	c.warn(Warning{
+		Code:    WarningPollReconnected,
+		Message: "the connection of a poll was dropped, polling again on a new connection",
+		Detail:  map[string]interface{}{"path": "/pinning/pinJobs", "error": err.Error()},
+	})
+	return c.sendPinJobs(c.NewRequest(http.MethodGet, "/pinning/pinJobs").WithContext(ctx).forPolling(true), options)
+}
+
+// connDropped reports whether err is the failure of a connection closed by the server or by a
+// middlebox, as opposed to a failure of the request itself.
+func connDropped(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
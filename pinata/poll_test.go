@@ -0,0 +1,174 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reapingServer is a mock pinJobs endpoint that closes the connection after every response, as
+// middleboxes reaping idle connections do, without telling the client. The job is listed by the
+// first polls and gone afterwards; the response to the poll numbered truncate is cut short. pinList
+// lists the content of the job as pinned.
+type reapingServer struct {
+	*httptest.Server
+	polls    atomic.Int32
+	listed   int32
+	truncate int32
+}
+
+func newReapingServer(t *testing.T, listed, truncate int32) *reapingServer {
+	s := &reapingServer{listed: listed, truncate: truncate}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data/pinList" {
+			w.Write([]byte(`{"count":1,"rows":[{"id":"pin-1","ipfs_pin_hash":"` + cidV0 + `","date_pinned":"2024-01-01T00:00:00Z","date_unpinned":null}]}`))
+			return
+		}
+		n := s.polls.Add(1)
+		body := `{"count":0,"rows":[]}`
+		if n <= s.listed {
+			body = `{"count":1,"rows":[{"id":"job-1","ipfs_pin_hash":"` + cidV0 + `","status":"retrieving"}]}`
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		length := len(body)
+		if n == s.truncate {
+			body = body[:len(body)/2]
+		}
+		fmt.Fprintf(buf, "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s", length, body)
+		buf.Flush()
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestPollReconnect(t *testing.T) {
+	t.Run("waiters survive dropped connections", func(t *testing.T) {
+		server := newReapingServer(t, 3, 2)
+		var mu sync.Mutex
+		var warnings []Warning
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithWarningHandler(func(w Warning) {
+			mu.Lock()
+			defer mu.Unlock()
+			warnings = append(warnings, w)
+		}))
+		client.baseURL = server.URL
+		client.pinJobPollInterval = time.Millisecond
+
+		job := &PinByCidResponse{ID: "job-1", IpfsHash: cidV0}
+		require.NoError(t, client.waitForPinJob(context.Background(), job))
+		require.EqualValues(t, 4, server.polls.Load())
+		require.Len(t, warnings, 1)
+		require.Equal(t, WarningPollReconnected, warnings[0].Code)
+	})
+
+	t.Run("watchers survive dropped connections", func(t *testing.T) {
+		server := newReapingServer(t, 3, 2)
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events, err := client.WatchPinJobs(ctx, time.Millisecond, nil)
+		require.NoError(t, err)
+
+		var types []PinJobEventType
+		for event := range events {
+			types = append(types, event.Type)
+			if event.Type == PinJobEventCompleted {
+				cancel()
+			}
+		}
+		require.Equal(t, []PinJobEventType{PinJobEventQueued, PinJobEventCompleted}, types)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		var polls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			polls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+
+		require.Error(t, client.waitForPinJob(context.Background(), &PinByCidResponse{ID: "job-1", IpfsHash: cidV0}))
+		require.EqualValues(t, 1, polls.Load())
+	})
+}
+
+func TestPollKeepAlive(t *testing.T) {
+	var mu sync.Mutex
+	addrs := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		addrs[r.RemoteAddr] = true
+		mu.Unlock()
+		w.Write([]byte(`{"count":0,"rows":[]}`))
+	}))
+	defer server.Close()
+
+	poll := func(t *testing.T, client *Client, n int) int {
+		mu.Lock()
+		clear(addrs)
+		mu.Unlock()
+		for i := 0; i < n; i++ {
+			_, err := client.pollPinJobs(context.Background(), nil)
+			require.NoError(t, err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return len(addrs)
+	}
+
+	t.Run("connections are reused by default", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+		require.Equal(t, 1, poll(t, client, 3))
+		require.Same(t, client.httpClient, client.pollHTTPClient())
+	})
+
+	t.Run("fresh connection per poll", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPollKeepAlive(PollKeepAlive{FreshConnection: true}))
+		client.baseURL = server.URL
+		require.Equal(t, 3, poll(t, client, 3))
+
+		// other requests keep their connections
+		var connections int
+		mu.Lock()
+		clear(addrs)
+		mu.Unlock()
+		for i := 0; i < 3; i++ {
+			_, err := client.ListPinByCidJobs(nil)
+			require.NoError(t, err)
+		}
+		mu.Lock()
+		connections = len(addrs)
+		mu.Unlock()
+		require.Equal(t, 1, connections)
+	})
+
+	t.Run("polls have a transport of their own", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithPollKeepAlive(PollKeepAlive{
+			TCPKeepAlive:    5 * time.Second,
+			IdleConnTimeout: 20 * time.Second,
+		}))
+		client.baseURL = server.URL
+		require.Equal(t, 1, poll(t, client, 2))
+
+		transport := client.pollHTTPClient().Transport.(*http.Transport)
+		require.NotSame(t, client.transport, transport)
+		require.Equal(t, 20*time.Second, transport.IdleConnTimeout)
+		require.NotNil(t, transport.DialContext)
+		require.Equal(t, 90*time.Second, client.transport.IdleConnTimeout)
+		require.Nil(t, client.transport.DialContext)
+	})
+}
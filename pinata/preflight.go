@@ -0,0 +1,127 @@
+package pinata
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// ErrPreflight is returned by PinBatch and PinDirectory when the pre-flight check finds files that
+// cannot be uploaded.
+var ErrPreflight = errors.New("batch pre-flight check failed")
+
+// PreflightProblem classifies a file that cannot be uploaded.
+type PreflightProblem string
+
+const (
+	// ProblemNotRegular is a path that is a directory or another non-regular file.
+	ProblemNotRegular PreflightProblem = "not_regular"
+	// ProblemPermissionDenied is a file or directory the process is not allowed to read.
+	ProblemPermissionDenied PreflightProblem = "permission_denied"
+	// ProblemEmptyFile is a file of zero bytes, which Pinata does not accept.
+	ProblemEmptyFile PreflightProblem = "empty_file"
+)
+
+// PreflightIssue is a file found unfit for upload by the pre-flight check of a batch.
+// Index is the position of the file in the batch, or -1 for a directory PinDirectory could not
+// walk.
+// Path is the path of the file.
+// Source tells why the file is part of the batch: "paths" for a path given to PinBatch, or
+// "walk of <dir>" for a file found by PinDirectory.
+// Problem classifies the issue.
+// Err is the underlying error, if any.
+type PreflightIssue struct {
+	Index   int
+	Path    string
+	Source  string
+	Problem PreflightProblem
+	Err     error
+}
+
+// String describes the issue as "<path> (from <source>): <problem>", followed by the underlying
+// error when there is one.
+func (i PreflightIssue) String() string {
+	s := fmt.Sprintf("%s (from %s): %s", i.Path, i.Source, i.Problem)
+	if i.Err != nil {
+		s += ": " + i.Err.Error()
+	}
+	return s
+}
+
+// PreflightError is the report of a failed pre-flight check, listing every issue found. It matches
+// ErrPreflight with errors.Is.
+type PreflightError struct {
+	Issues []PreflightIssue
+}
+
+// Error implements the error interface.
+func (e *PreflightError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = issue.String()
+	}
+	return fmt.Sprintf("%s: %d file(s) cannot be uploaded: %s", ErrPreflight, len(e.Issues), strings.Join(issues, "; "))
+}
+
+// Unwrap returns the underlying error.
+func (e *PreflightError) Unwrap() error {
+	return ErrPreflight
+}
+
+// preflight stats and opens every file of jobs before anything is uploaded, and classifies the
+// files that cannot be uploaded. Missing files are not reported: their uploads fail as usual.
+func preflight(jobs []*batchJob, source string) []PreflightIssue {
+	var issues []PreflightIssue
+	for _, job := range jobs {
+		if problem, err := checkUploadable(job.path); problem != "" {
+			issues = append(issues, PreflightIssue{Index: job.index, Path: job.path, Source: source, Problem: problem, Err: err})
+		}
+	}
+	return issues
+}
+
+// checkUploadable returns the problem preventing the upload of the file at path, if any.
+func checkUploadable(path string) (PreflightProblem, error) {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return ProblemPermissionDenied, err
+	case err != nil:
+		return "", nil
+	case !info.Mode().IsRegular():
+		return ProblemNotRegular, nil
+	case info.Size() == 0:
+		return ProblemEmptyFile, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrPermission) {
+		return ProblemPermissionDenied, err
+	}
+	if err == nil {
+		f.Close()
+	}
+	return "", nil
+}
+
+// skippedResult is the result of a file skipped by the pre-flight check.
+func (c *Client) skippedResult(job *batchJob, issue PreflightIssue) BatchItemResult {
+	c.warnPreflight(issue)
+	return BatchItemResult{
+		Index: job.index,
+		Path:  job.path,
+		Name:  job.options.PinataMetadata.Name,
+		Err:   fmt.Errorf("%w: %s", ErrPreflight, issue),
+	}
+}
+
+// warnPreflight reports a file skipped by the pre-flight check as a WarningFileSkipped.
+func (c *Client) warnPreflight(issue PreflightIssue) {
+	c.warn(Warning{
+		Code:    WarningFileSkipped,
+		Message: "skipping file failing the pre-flight check",
+		Detail:  map[string]interface{}{"path": issue.Path, "source": issue.Source, "problem": string(issue.Problem)},
+	})
+}
@@ -0,0 +1,112 @@
+package pinata
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AuthStyle selects how a client's credentials are attached to API requests.
+type AuthStyle int
+
+const (
+	// AuthStyleAuto sends a JWT as an Authorization bearer token, and API key credentials in the
+	// pinata_api_key and pinata_secret_api_key headers. This is what the Pinata API expects.
+	AuthStyleAuto AuthStyle = iota
+	// AuthStyleBearer only sends a JWT as an Authorization bearer token; API key credentials are
+	// never sent.
+	AuthStyleBearer
+	// AuthStyleNone sends no credentials, for local services that do not authenticate requests.
+	AuthStyleNone
+)
+
+// Profile describes the hosts a client talks to and how it authenticates, so the client can be
+// pointed at services other than Pinata's production hosts, such as a staging environment or a
+// self-hosted service implementing the same API.
+// APIURL is the base URL of every API request that is not an upload.
+// UploadURL is the base URL of file uploads, i.e. requests to /pinning/pinFileToIPFS, and of the v3
+// upload and vector endpoints. When empty, uploads go to APIURL and v3 upload and vector requests
+// to UploadsURL, since the API host does not serve them.
+// GatewayURL is the gateway content is retrieved from when no gateways are set with WithGateways.
+// AuthStyle selects how credentials are sent.
+type Profile struct {
+	APIURL     string
+	UploadURL  string
+	GatewayURL string
+	AuthStyle  AuthStyle
+}
+
+// DefaultProfile returns the profile of Pinata's production service, which clients use unless
+// another one is set with WithProfile.
+func DefaultProfile() Profile {
+	return Profile{
+		APIURL:     BaseURL,
+		UploadURL:  BaseURL,
+		GatewayURL: GatewayURL,
+		AuthStyle:  AuthStyleAuto,
+	}
+}
+
+// WithProfile points the client at the hosts of profile and uses its AuthStyle. Empty APIURL and
+// GatewayURL fields keep the client's current values, and an empty UploadURL sends uploads to the
+// API host.
+//
+// The request builder chooses the host of each request by endpoint class: file uploads go to
+// UploadURL, v3 upload and vector requests to UploadURL or UploadsURL, and every other API request
+// to APIURL. Content retrieval and gateway probes go to the gateways set with WithGateways, or to
+// GatewayURL when there are none.
+func WithProfile(profile Profile) ClientOption {
+	return func(c *Client) {
+		if profile.APIURL != "" {
+			c.baseURL = strings.TrimRight(profile.APIURL, "/")
+		}
+		c.uploadURL = strings.TrimRight(profile.UploadURL, "/")
+		if profile.GatewayURL != "" {
+			c.gatewayURL = strings.TrimRight(profile.GatewayURL, "/")
+		}
+		c.authStyle = profile.AuthStyle
+	}
+}
+
+// uploadPaths are the API paths of the upload endpoint class.
+var uploadPaths = map[string]bool{
+	"/pinning/pinFileToIPFS": true,
+}
+
+// UploadsURL is the base URL of the v3 endpoints that Pinata only serves from its uploads host:
+// v3 file uploads and file vectorization.
+const UploadsURL = "https://uploads.pinata.cloud"
+
+// uploadsHostPaths are the API paths only served by the uploads host.
+var uploadsHostPaths = map[string]bool{
+	"/v3/files":                       true,
+	"/v3/vectorize/files/{id}":        true,
+	"/v3/vectorize/groups/{id}/query": true,
+}
+
+// hostFor returns the base URL that requests to the API path are sent to.
+func (c *Client) hostFor(path string) string {
+	switch {
+	case (uploadPaths[path] || uploadsHostPaths[path]) && c.uploadURL != "":
+		return c.uploadURL
+	case uploadsHostPaths[path]:
+		return UploadsURL
+	}
+	return c.baseURL
+}
+
+// setAuthHeader sets the credentials of auth on req in the client's AuthStyle.
+func (c *Client) setAuthHeader(auth *Auth, req *http.Request) {
+	if c.authStyle == AuthStyleAuto && c.fallingBack(auth) {
+		auth = &Auth{apiKey: auth.apiKey, apiSecret: auth.apiSecret}
+	}
+	switch c.authStyle {
+	case AuthStyleNone:
+		return
+	case AuthStyleBearer:
+		if auth.jwt != "" {
+			req.Header.Set("Authorization", "Bearer "+auth.jwt)
+		}
+		return
+	}
+	auth.setAuthHeader(req)
+}
@@ -0,0 +1,110 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// hostRecorder is a mock server that records the requests it receives.
+type hostRecorder struct {
+	*httptest.Server
+	mu       sync.Mutex
+	paths    []string
+	requests []*http.Request
+}
+
+func newHostRecorder(t *testing.T, response string) *hostRecorder {
+	h := &hostRecorder{}
+	h.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		h.mu.Lock()
+		h.paths = append(h.paths, r.URL.Path)
+		h.requests = append(h.requests, r)
+		h.mu.Unlock()
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(h.Close)
+	return h
+}
+
+func TestWithProfile(t *testing.T) {
+	t.Run("routes endpoint classes to their hosts", func(t *testing.T) {
+		api := newHostRecorder(t, `{"keys":[{"key":"api_key_1"}]}`)
+		uploads := newHostRecorder(t, `{"IpfsHash":"QmUpload","PinSize":10,"Timestamp":"2023-05-01T12:00:00Z"}`)
+		gateway := newHostRecorder(t, "content")
+
+		client := New(NewAuthWithJWT("test_token"), WithProfile(Profile{
+			APIURL:     api.URL,
+			UploadURL:  uploads.URL + "/",
+			GatewayURL: gateway.URL,
+		}))
+
+		keys, err := client.ListApiKeys()
+		require.NoError(t, err)
+		require.Len(t, keys.Keys, 1)
+
+		dir := writeTree(t, "a.txt")
+		pin, err := client.PinFile(filepath.Join(dir, "a.txt"), nil)
+		require.NoError(t, err)
+		require.Equal(t, "QmUpload", pin.IpfsHash)
+
+		content, err := client.GetContent(context.Background(), "QmUpload")
+		require.NoError(t, err)
+		defer content.Body.Close()
+
+		require.Equal(t, []string{"/users/apiKeys"}, api.paths)
+		require.Equal(t, []string{"/pinning/pinFileToIPFS"}, uploads.paths)
+		require.Equal(t, []string{"/ipfs/QmUpload"}, gateway.paths)
+	})
+
+	t.Run("uploads default to the api host", func(t *testing.T) {
+		api := newHostRecorder(t, `{"IpfsHash":"QmUpload","PinSize":10,"Timestamp":"2023-05-01T12:00:00Z"}`)
+
+		client := New(NewAuthWithJWT("test_token"), WithProfile(Profile{APIURL: api.URL}))
+
+		_, err := client.PinFile(filepath.Join(writeTree(t, "a.txt"), "a.txt"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"/pinning/pinFileToIPFS"}, api.paths)
+	})
+
+	t.Run("default profile", func(t *testing.T) {
+		client := New(NewAuthWithJWT("test_token"), WithProfile(DefaultProfile()))
+
+		require.Equal(t, BaseURL, client.hostFor("/users/apiKeys"))
+		require.Equal(t, BaseURL, client.hostFor("/pinning/pinFileToIPFS"))
+		require.Equal(t, []string{GatewayURL}, client.gatewayHosts(""))
+	})
+
+	authStyles := []struct {
+		name          string
+		style         AuthStyle
+		auth          *Auth
+		authorization string
+		apiKey        string
+	}{
+		{"auto with jwt", AuthStyleAuto, NewAuthWithJWT("test_token"), "Bearer test_token", ""},
+		{"auto with api key", AuthStyleAuto, NewAuth("key", "secret", ""), "", "key"},
+		{"bearer with api key", AuthStyleBearer, NewAuth("key", "secret", ""), "", ""},
+		{"bearer with jwt", AuthStyleBearer, NewAuth("key", "secret", "test_token"), "Bearer test_token", ""},
+		{"none", AuthStyleNone, NewAuthWithJWT("test_token"), "", ""},
+	}
+	for _, tc := range authStyles {
+		t.Run("auth style "+tc.name, func(t *testing.T) {
+			api := newHostRecorder(t, `{}`)
+			client := New(tc.auth, WithProfile(Profile{APIURL: api.URL, AuthStyle: tc.style}))
+
+			require.NoError(t, client.NewRequest(http.MethodGet, "/test").Send(nil))
+
+			require.Len(t, api.requests, 1)
+			require.Equal(t, tc.authorization, api.requests[0].Header.Get("Authorization"))
+			require.Equal(t, tc.apiKey, api.requests[0].Header.Get("pinata_api_key"))
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull is returned by PinQueue.Enqueue when the queue buffer is full.
+	ErrQueueFull = errors.New("pin queue is full")
+	// ErrQueueClosed is returned when enqueueing into a PinQueue that is shutting down, and is
+	// reported to the completion callback of jobs abandoned by Shutdown.
+	ErrQueueClosed = errors.New("pin queue is closed")
+)
+
+const (
+	// defaultPinQueueCapacity is the buffer size of a PinQueue when none is configured.
+	defaultPinQueueCapacity = 100
+	// defaultPinQueueWorkers is the number of uploads a PinQueue runs at once when none is configured.
+	defaultPinQueueWorkers = 5
+)
+
+// PinJob is a file upload handed to a PinQueue.
+// Path is the local path of the file to pin.
+// Options are the pin options of the upload. They are copied when the job is enqueued.
+// Done, if set, is called once with the pin response or the error of the upload. It is called from
+// a worker goroutine and should not block for long.
+type PinJob struct {
+	Path    string
+	Options *PinOptions
+	Done    func(*PinResponse, error)
+}
+
+// PinQueueOptions represents the options of a PinQueue.
+// Capacity is the number of jobs that can wait in the queue (100 when zero).
+// Workers is the number of uploads running at once (5 when zero).
+// Rate is the maximum number of uploads started per second; zero means no limit.
+// DrainOnShutdown makes Shutdown run every queued job before returning. Otherwise Shutdown
+// cancels running uploads and abandons queued jobs, which fail with ErrQueueClosed.
+type PinQueueOptions struct {
+	Capacity        int
+	Workers         int
+	Rate            float64
+	DrainOnShutdown bool
+}
+
+// PinQueueStats is a snapshot of the jobs of a PinQueue.
+// Queued is the number of jobs waiting for a worker.
+// InFlight is the number of jobs being uploaded, including jobs waiting for their turn under Rate.
+// Completed is the number of jobs pinned successfully.
+// Failed is the number of jobs that failed or were abandoned.
+type PinQueueStats struct {
+	Queued    int
+	InFlight  int
+	Completed int
+	Failed    int
+}
+
+// clock abstracts time so that rate limiting can be tested without waiting.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// PinQueue uploads files in the background with a bounded buffer and a pool of workers, for
+// ingesting pins faster than they can be uploaded. A full buffer is reported to the producer
+// instead of dropping work: Enqueue fails with ErrQueueFull and EnqueueWait blocks until there is
+// room. A PinQueue is safe for concurrent use and must be stopped with Shutdown.
+type PinQueue struct {
+	client  *Client
+	options PinQueueOptions
+	clock   clock
+	jobs    chan PinJob
+	stop    chan struct{} // closed by Shutdown, unblocks EnqueueWait
+	drain   chan struct{} // closed once no EnqueueWait is sending, ends the workers
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	senders sync.WaitGroup
+	drained sync.Once
+
+	mu     sync.Mutex
+	closed bool
+	stats  PinQueueStats
+	next   time.Time
+}
+
+// NewPinQueue creates a PinQueue uploading with the client and starts its workers. options may be nil.
+func (c *Client) NewPinQueue(options *PinQueueOptions) *PinQueue {
+	return newPinQueue(c, options, realClock{})
+}
+
+// newPinQueue implements NewPinQueue with the given clock.
+func newPinQueue(c *Client, options *PinQueueOptions, clk clock) *PinQueue {
+	var opts PinQueueOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Capacity <= 0 {
+		opts.Capacity = defaultPinQueueCapacity
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultPinQueueWorkers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &PinQueue{
+		client:  c,
+		options: opts,
+		clock:   clk,
+		jobs:    make(chan PinJob, opts.Capacity),
+		stop:    make(chan struct{}),
+		drain:   make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for i := 0; i < opts.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	return q
+}
+
+// Enqueue adds job to the queue without blocking. It returns ErrQueueFull when the buffer is full
+// and ErrQueueClosed once Shutdown was called.
+func (q *PinQueue) Enqueue(job PinJob) error {
+	if job.Path == "" {
+		return fmt.Errorf("filepath is required")
+	}
+
+	job.Options = job.Options.Clone()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	select {
+	case q.jobs <- job:
+		q.stats.Queued++
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// EnqueueWait adds job to the queue, blocking until there is room in the buffer, ctx is done or
+// the queue is shut down.
+func (q *PinQueue) EnqueueWait(ctx context.Context, job PinJob) error {
+	if job.Path == "" {
+		return fmt.Errorf("filepath is required")
+	}
+
+	job.Options = job.Options.Clone()
+
+	// count the job as queued before sending it, so a worker never sees it before it is counted
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrQueueClosed
+	}
+	q.stats.Queued++
+	q.senders.Add(1)
+	q.mu.Unlock()
+	defer q.senders.Done()
+
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-q.stop:
+		q.unqueue()
+		return ErrQueueClosed
+	case <-ctx.Done():
+		q.unqueue()
+		return transportError(ctx.Err())
+	}
+}
+
+// unqueue reverts the count of a job that could not be enqueued.
+func (q *PinQueue) unqueue() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stats.Queued--
+}
+
+// Stats returns a snapshot of the queue's job counts.
+func (q *PinQueue) Stats() PinQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
+
+// Shutdown stops the queue from accepting jobs and waits for its workers to finish. With
+// DrainOnShutdown every queued job is run first; otherwise running uploads are cancelled and
+// queued jobs are abandoned. If ctx is done before the queue is drained, the remaining jobs are
+// abandoned and the context error is returned. Shutdown may be called more than once.
+func (q *PinQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.stop)
+		if !q.options.DrainOnShutdown {
+			q.cancel()
+		}
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.senders.Wait()
+		q.drained.Do(func() { close(q.drain) })
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return transportError(ctx.Err())
+	}
+}
+
+// worker runs jobs until the queue is shut down, then runs or abandons the jobs left in the buffer.
+func (q *PinQueue) worker() {
+	defer q.wg.Done()
+	for {
+		select {
+		case job := <-q.jobs:
+			q.run(job)
+		case <-q.drain:
+			for {
+				select {
+				case job := <-q.jobs:
+					q.run(job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// run uploads job once its turn under the rate limit comes, records the outcome and reports it to
+// the job's callback. Jobs picked up after the queue was cancelled are abandoned.
+func (q *PinQueue) run(job PinJob) {
+	q.mu.Lock()
+	q.stats.Queued--
+	q.stats.InFlight++
+	q.mu.Unlock()
+
+	var response *PinResponse
+	err := q.waitTurn()
+	if err == nil {
+		response, err = q.client.pinFile(q.ctx, job.Path, job.Options)
+	}
+
+	q.mu.Lock()
+	q.stats.InFlight--
+	if err != nil {
+		q.stats.Failed++
+	} else {
+		q.stats.Completed++
+	}
+	q.mu.Unlock()
+
+	if job.Done != nil {
+		job.Done(response, err)
+	}
+}
+
+// waitTurn blocks until the next upload may start under Rate. Start times are reserved in order, so
+// uploads are spaced by 1/Rate seconds. It returns ErrQueueClosed if the queue is cancelled first.
+func (q *PinQueue) waitTurn() error {
+	if q.ctx.Err() != nil {
+		return ErrQueueClosed
+	}
+	if q.options.Rate <= 0 {
+		return nil
+	}
+
+	interval := time.Duration(float64(time.Second) / q.options.Rate)
+	q.mu.Lock()
+	now := q.clock.Now()
+	slot := q.next
+	if slot.Before(now) {
+		slot = now
+	}
+	q.next = slot.Add(interval)
+	q.mu.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		select {
+		case <-q.clock.After(wait):
+		case <-q.ctx.Done():
+			return ErrQueueClosed
+		}
+	}
+	return nil
+}
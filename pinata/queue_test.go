@@ -0,0 +1,204 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock that only moves when advanced.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, fakeTimer{at: f.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d and fires the timers that are due.
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	pending := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- f.now
+	}
+	f.waiters = pending
+}
+
+// Waiting returns the number of timers that have not fired yet.
+func (f *fakeClock) Waiting() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// jobResults collects the outcomes reported to PinJob.Done.
+type jobResults struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (r *jobResults) done(_ *PinResponse, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+func (r *jobResults) all() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+func TestPinQueue(t *testing.T) {
+	path := filepath.Join(writeTree(t, "a.txt"), "a.txt")
+
+	t.Run("uploads at the configured rate", func(t *testing.T) {
+		var requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			serveFixture(w, r)
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+		clk := newFakeClock()
+		queue := newPinQueue(client, &PinQueueOptions{Workers: 3, Rate: 1, DrainOnShutdown: true}, clk)
+
+		results := &jobResults{}
+		for i := 0; i < 3; i++ {
+			require.NoError(t, queue.Enqueue(PinJob{Path: path, Done: results.done}))
+		}
+
+		require.Eventually(t, func() bool { return requests.Load() == 1 && clk.Waiting() == 2 }, time.Second, time.Millisecond)
+		require.Eventually(t, func() bool { return queue.Stats() == PinQueueStats{InFlight: 2, Completed: 1} }, time.Second, time.Millisecond)
+
+		clk.Advance(time.Second)
+		require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+
+		clk.Advance(time.Second)
+		require.NoError(t, queue.Shutdown(context.Background()))
+
+		require.Equal(t, int32(3), requests.Load())
+		require.Equal(t, PinQueueStats{Completed: 3}, queue.Stats())
+		require.Equal(t, []error{nil, nil, nil}, results.all())
+	})
+
+	t.Run("reports a full buffer", func(t *testing.T) {
+		release := make(chan struct{})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			serveFixture(w, r)
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+		queue := newPinQueue(client, &PinQueueOptions{Workers: 1, Capacity: 1, DrainOnShutdown: true}, newFakeClock())
+
+		require.NoError(t, queue.Enqueue(PinJob{Path: path}))
+		require.Eventually(t, func() bool { return queue.Stats().InFlight == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, queue.Enqueue(PinJob{Path: path}))
+		require.ErrorIs(t, queue.Enqueue(PinJob{Path: path}), ErrQueueFull)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, queue.EnqueueWait(ctx, PinJob{Path: path}), context.DeadlineExceeded)
+		require.Equal(t, PinQueueStats{Queued: 1, InFlight: 1}, queue.Stats())
+
+		close(release)
+		require.NoError(t, queue.Shutdown(context.Background()))
+		require.Equal(t, PinQueueStats{Completed: 2}, queue.Stats())
+	})
+
+	t.Run("abandons jobs on shutdown", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the request context only ends on disconnect once the body was read
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+		queue := newPinQueue(client, &PinQueueOptions{Workers: 1}, newFakeClock())
+
+		results := &jobResults{}
+		for i := 0; i < 3; i++ {
+			require.NoError(t, queue.Enqueue(PinJob{Path: path, Done: results.done}))
+		}
+		require.Eventually(t, func() bool { return queue.Stats().InFlight == 1 }, time.Second, time.Millisecond)
+
+		require.NoError(t, queue.Shutdown(context.Background()))
+
+		errs := results.all()
+		require.Len(t, errs, 3)
+		require.ErrorIs(t, errs[0], context.Canceled)
+		require.ErrorIs(t, errs[1], ErrQueueClosed)
+		require.ErrorIs(t, errs[2], ErrQueueClosed)
+		require.Equal(t, PinQueueStats{Failed: 3}, queue.Stats())
+		require.ErrorIs(t, queue.Enqueue(PinJob{Path: path}), ErrQueueClosed)
+		require.ErrorIs(t, queue.EnqueueWait(context.Background(), PinJob{Path: path}), ErrQueueClosed)
+	})
+
+	t.Run("shutdown deadline abandons the rest", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the request context only ends on disconnect once the body was read
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+		queue := newPinQueue(client, &PinQueueOptions{Workers: 1, DrainOnShutdown: true}, newFakeClock())
+
+		require.NoError(t, queue.Enqueue(PinJob{Path: path}))
+		require.NoError(t, queue.Enqueue(PinJob{Path: path}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, queue.Shutdown(ctx), context.DeadlineExceeded)
+		require.Equal(t, PinQueueStats{Failed: 2}, queue.Stats())
+	})
+}
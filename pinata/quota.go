@@ -0,0 +1,130 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+func init() {
+	registerCapability(CapabilityQuotaGuard)
+}
+
+// ErrQuotaExceeded is returned by PinBatch and PinDirectory when a batch would take the pinned
+// data of the account over the limit configured with WithQuotaGuard.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// QuotaExceededError reports a batch refused by the quota guard. It matches ErrQuotaExceeded with
+// errors.Is.
+// Used is the number of bytes pinned by the account before the batch.
+// Planned is the total size in bytes of the files the batch would upload.
+// Limit is the number of bytes the batch may bring the account to: the plan limit minus the
+// safety margin.
+type QuotaExceededError struct {
+	Used    int64
+	Planned int64
+	Limit   int64
+}
+
+// Error implements the error interface.
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: %d bytes pinned and %d bytes planned exceed the limit of %d bytes", ErrQuotaExceeded, e.Used, e.Planned, e.Limit)
+}
+
+// Unwrap returns the underlying error.
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// QuotaGuard configures the storage quota check of PinBatch and PinDirectory.
+// PlanLimitBytes is the storage limit of the account's plan in bytes. The guard is disabled when
+// it is zero or less.
+// SafetyMargin is the number of bytes kept free below PlanLimitBytes, leaving room for uploads
+// made outside the batch.
+type QuotaGuard struct {
+	PlanLimitBytes int64
+	SafetyMargin   int64
+}
+
+// WithQuotaGuard makes PinBatch and PinDirectory check the remaining storage quota before
+// uploading anything. The pinned data total of the account is fetched with GetPinnedDataTotal and
+// the sizes of the files to upload are summed; when the total would exceed guard.PlanLimitBytes
+// minus guard.SafetyMargin, the batch fails with a *QuotaExceededError. Files a checkpoint store
+// records as pinned are not counted. BatchOptions.IgnoreQuota skips the check for a single call.
+func WithQuotaGuard(guard QuotaGuard) ClientOption {
+	return func(c *Client) {
+		c.quotaGuard = guard
+	}
+}
+
+// PinnedDataTotal represents the storage used by the account.
+// PinCount is the number of pins of the account.
+// PinSizeTotal is the total size of the pinned data in bytes.
+// PinSizeWithReplicationsTotal is the total size of the pinned data in bytes, counting every
+// replication.
+type PinnedDataTotal struct {
+	PinCount                     int64 `json:"pin_count"`
+	PinSizeTotal                 int64 `json:"pin_size_total"`
+	PinSizeWithReplicationsTotal int64 `json:"pin_size_with_replications_total"`
+}
+
+// GetPinnedDataTotal returns the number of pins and the storage used by the account. PinnedFileCount
+// and TotalStorageSize read their values from it.
+func (c *Client) GetPinnedDataTotal(ctx context.Context) (*PinnedDataTotal, error) {
+	var response PinnedDataTotal
+	err := c.NewRequest(http.MethodGet, "/data/userPinnedDataTotal").
+		WithContext(ctx).
+		Send(&response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// checkQuota fails with a *QuotaExceededError when uploading the jobs not recorded in store would
+// take the pinned data of the account over the limit of the quota guard. It does nothing when no
+// guard is configured.
+func (c *Client) checkQuota(ctx context.Context, jobs []*batchJob, store CheckpointStore) error {
+	guard := c.quotaGuard
+	if guard.PlanLimitBytes <= 0 {
+		return nil
+	}
+
+	var planned int64
+	for _, job := range jobs {
+		if store != nil {
+			key := job.checkpointKey
+			if key == "" {
+				var err error
+				if key, err = fileCheckpointKey(job.path); err != nil {
+					return err
+				}
+			}
+			_, ok, err := store.Get(key)
+			if err != nil {
+				return fmt.Errorf("failed to read checkpoint: %w", err)
+			}
+			if ok {
+				continue
+			}
+		}
+		info, err := os.Stat(job.path)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", job.path, err)
+		}
+		planned += info.Size()
+	}
+
+	total, err := c.GetPinnedDataTotal(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pinned data total: %w", err)
+	}
+
+	limit := guard.PlanLimitBytes - guard.SafetyMargin
+	if total.PinSizeTotal+planned > limit {
+		return &QuotaExceededError{Used: total.PinSizeTotal, Planned: planned, Limit: limit}
+	}
+	return nil
+}
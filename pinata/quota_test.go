@@ -0,0 +1,134 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// quotaServer is a mock API that reports pinSizeTotal bytes pinned and counts the uploads and
+// usage requests it receives.
+type quotaServer struct {
+	pinSizeTotal int64
+	uploads      atomic.Int32
+	usage        atomic.Int32
+}
+
+func (q *quotaServer) start(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/userPinnedDataTotal":
+			require.Equal(t, http.MethodGet, r.Method)
+			q.usage.Add(1)
+			w.Write([]byte(`{"pin_count":3,"pin_size_total":` + strconv.FormatInt(q.pinSizeTotal, 10) + `,"pin_size_with_replications_total":0}`))
+		case "/pinning/pinFileToIPFS":
+			q.uploads.Add(1)
+			serveFixture(w, r)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestQuotaGuard(t *testing.T) {
+	// every file of the tree is 16 bytes long
+	dir := writeTree(t, "a.txt", "b.txt")
+	paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+
+	newClient := func(q *quotaServer, guard QuotaGuard) *Client {
+		client := New(&Auth{jwt: "jwt"}, WithQuotaGuard(guard))
+		client.baseURL = q.start(t).URL
+		return client
+	}
+
+	t.Run("rejects a batch over the limit", func(t *testing.T) {
+		q := &quotaServer{pinSizeTotal: 100}
+		client := newClient(q, QuotaGuard{PlanLimitBytes: 140, SafetyMargin: 10})
+
+		results, err := client.PinBatch(context.Background(), paths, nil)
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		require.Nil(t, results)
+		var quotaErr *QuotaExceededError
+		require.True(t, errors.As(err, &quotaErr))
+		require.Equal(t, &QuotaExceededError{Used: 100, Planned: 32, Limit: 130}, quotaErr)
+		require.Contains(t, err.Error(), "100 bytes pinned and 32 bytes planned exceed the limit of 130 bytes")
+		require.Zero(t, q.uploads.Load())
+	})
+
+	t.Run("passes a batch under the limit", func(t *testing.T) {
+		q := &quotaServer{pinSizeTotal: 100}
+		client := newClient(q, QuotaGuard{PlanLimitBytes: 140, SafetyMargin: 8})
+
+		results, err := client.PinBatch(context.Background(), paths, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, int32(1), q.usage.Load())
+		require.Equal(t, int32(2), q.uploads.Load())
+	})
+
+	t.Run("applies to PinDirectory", func(t *testing.T) {
+		q := &quotaServer{pinSizeTotal: 100}
+		client := newClient(q, QuotaGuard{PlanLimitBytes: 120})
+
+		_, err := client.PinDirectory(context.Background(), dir, nil)
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		require.Zero(t, q.uploads.Load())
+	})
+
+	t.Run("override skips the check", func(t *testing.T) {
+		q := &quotaServer{pinSizeTotal: 100}
+		client := newClient(q, QuotaGuard{PlanLimitBytes: 120})
+
+		results, err := client.PinDirectory(context.Background(), dir, &BatchOptions{IgnoreQuota: true})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Zero(t, q.usage.Load())
+		require.Equal(t, int32(2), q.uploads.Load())
+	})
+
+	t.Run("checkpointed files are not counted", func(t *testing.T) {
+		store, err := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints"), 0)
+		require.NoError(t, err)
+		defer store.Close()
+		require.NoError(t, store.Set("a", Checkpoint{IpfsHash: "QmA"}))
+
+		q := &quotaServer{pinSizeTotal: 100}
+		client := newClient(q, QuotaGuard{PlanLimitBytes: 120})
+
+		results, err := client.PinBatch(context.Background(), paths, &BatchOptions{
+			Checkpoint:    store,
+			CheckpointKey: func(path string) string { return filepath.Base(path)[:1] },
+		})
+		require.NoError(t, err)
+		require.True(t, results[0].Skipped)
+		require.Equal(t, int32(1), q.uploads.Load())
+	})
+
+	t.Run("disabled without a plan limit", func(t *testing.T) {
+		q := &quotaServer{pinSizeTotal: 100}
+		client := newClient(q, QuotaGuard{})
+
+		_, err := client.PinBatch(context.Background(), paths, nil)
+		require.NoError(t, err)
+		require.Zero(t, q.usage.Load())
+	})
+}
+
+func TestGetPinnedDataTotal(t *testing.T) {
+	q := &quotaServer{pinSizeTotal: 2048}
+	client := New(&Auth{jwt: "jwt"})
+	client.baseURL = q.start(t).URL
+
+	total, err := client.GetPinnedDataTotal(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &PinnedDataTotal{PinCount: 3, PinSizeTotal: 2048}, total)
+}
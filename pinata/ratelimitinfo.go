@@ -0,0 +1,92 @@
+package pinata
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitInfo is the rate limit state reported by the headers of an API response, such as
+// X-RateLimit-Remaining, so that callers can slow down before being rejected with 429.
+// Limit is the number of requests allowed in the current window, or -1 when not reported.
+// Remaining is the number of requests left in the current window, or -1 when not reported.
+// Reset is when the current window ends, or the zero time when not reported.
+// Observed is when the response carrying the headers was received.
+type RateLimitInfo struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+	Observed  time.Time
+}
+
+// lastRateLimit holds the RateLimitInfo of the latest API response that carried one.
+type lastRateLimit struct {
+	mu   sync.Mutex
+	info *RateLimitInfo
+}
+
+// LastRateLimit returns the rate limit state reported by the latest API response of the client
+// that carried rate limit headers, and false when none did. Calls running concurrently share it,
+// so it describes the budget of the API key rather than of a single call. The state of rejected
+// requests is also available on APIError.RateLimit.
+func (c *Client) LastRateLimit() (RateLimitInfo, bool) {
+	c.lastRateLimit.mu.Lock()
+	defer c.lastRateLimit.mu.Unlock()
+	if c.lastRateLimit.info == nil {
+		return RateLimitInfo{}, false
+	}
+	return *c.lastRateLimit.info, true
+}
+
+// recordRateLimit records the rate limit headers of resp, if any, as the latest of the client.
+func (c *Client) recordRateLimit(resp *http.Response) {
+	info := parseRateLimit(resp.Header, c.now())
+	if info == nil {
+		return
+	}
+	c.lastRateLimit.mu.Lock()
+	defer c.lastRateLimit.mu.Unlock()
+	c.lastRateLimit.info = info
+}
+
+// parseRateLimit parses the rate limit headers of a response received at now, with or without the
+// X- prefix. It returns nil when none is present or valid. The reset is read as a Unix time when
+// it is large enough to be one, and as a number of seconds from now otherwise.
+func parseRateLimit(header http.Header, now time.Time) *RateLimitInfo {
+	info := &RateLimitInfo{Limit: -1, Remaining: -1, Observed: now}
+	found := false
+	if n, ok := rateLimitHeader(header, "Limit"); ok {
+		info.Limit, found = int(n), true
+	}
+	if n, ok := rateLimitHeader(header, "Remaining"); ok {
+		info.Remaining, found = int(n), true
+	}
+	if n, ok := rateLimitHeader(header, "Reset"); ok {
+		if n >= 1_000_000_000 {
+			info.Reset = time.Unix(n, 0)
+		} else {
+			info.Reset = now.Add(time.Duration(n) * time.Second)
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	return info
+}
+
+// rateLimitHeader returns the non-negative integer value of the X-RateLimit-<name> header, or of
+// the RateLimit-<name> header when the former is absent.
+func rateLimitHeader(header http.Header, name string) (int64, bool) {
+	value := header.Get("X-RateLimit-" + name)
+	if value == "" {
+		value = header.Get("RateLimit-" + name)
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
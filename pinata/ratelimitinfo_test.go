@@ -0,0 +1,98 @@
+package pinata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2024, 5, 21, 19, 27, 3, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   *RateLimitInfo
+	}{
+		{"no headers", nil, nil},
+		{"all headers", map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"},
+			&RateLimitInfo{Limit: 60, Remaining: 0, Reset: now.Add(30 * time.Second), Observed: now}},
+		{"unix reset", map[string]string{"X-RateLimit-Reset": "1716319653"},
+			&RateLimitInfo{Limit: -1, Remaining: -1, Reset: time.Unix(1716319653, 0), Observed: now}},
+		{"without the X- prefix", map[string]string{"RateLimit-Remaining": "12"},
+			&RateLimitInfo{Limit: -1, Remaining: 12, Observed: now}},
+		{"invalid values are ignored", map[string]string{"X-RateLimit-Limit": "many", "X-RateLimit-Remaining": " 7 ", "X-RateLimit-Reset": "-1"},
+			&RateLimitInfo{Limit: -1, Remaining: 7, Observed: now}},
+		{"only invalid values", map[string]string{"X-RateLimit-Limit": "", "X-RateLimit-Reset": "soon"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+			require.Equal(t, tt.want, parseRateLimit(header, now))
+		})
+	}
+}
+
+func TestRateLimitInfo(t *testing.T) {
+	remaining := 3
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data/testAuthentication" {
+			serveFixture(w, r)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "3")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		if remaining == 0 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":"Too many requests"}`))
+			return
+		}
+		remaining--
+		w.Header().Set("X-RateLimit-Remaining", string(rune('0'+remaining)))
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	clk := newFakeClock()
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithConcurrency(1))
+	client.baseURL = mockServer.URL
+	client.uploadURL = mockServer.URL
+	client.clock = clk
+
+	// responses without the headers leave nothing to report
+	_, err := client.TestAuthentication()
+	require.NoError(t, err)
+	_, ok := client.LastRateLimit()
+	require.False(t, ok)
+
+	dir := writeTree(t, "a.txt", "b.txt")
+	_, err = client.PinFilesAsync([]string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, nil)
+	require.NoError(t, err)
+	info, ok := client.LastRateLimit()
+	require.True(t, ok)
+	require.Equal(t, RateLimitInfo{Limit: 3, Remaining: 1, Reset: clk.Now().Add(time.Minute), Observed: clk.Now()}, info)
+
+	// a later response without the headers keeps the last state
+	_, err = client.TestAuthentication()
+	require.NoError(t, err)
+	_, ok = client.LastRateLimit()
+	require.True(t, ok)
+
+	_, err = client.PinFile(filepath.Join(dir, "a.txt"), nil)
+	require.NoError(t, err)
+	_, err = client.PinFile(filepath.Join(dir, "a.txt"), nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.ErrorIs(t, err, ErrRateLimited)
+	require.Equal(t, &RateLimitInfo{Limit: 3, Remaining: 0, Reset: clk.Now().Add(time.Minute), Observed: clk.Now()}, apiErr.RateLimit)
+	info, _ = client.LastRateLimit()
+	require.Equal(t, 0, info.Remaining)
+}
@@ -0,0 +1,140 @@
+package pinata
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityReadCache)
+}
+
+// ReadCachePolicy configures the read cache set with WithReadCache.
+// TTL is how long a cached value is served without asking the API again.
+// StaleTTL enables stale-while-revalidate: for StaleTTL past the TTL, the stale value is still
+// served at once while a single background request refreshes it. Zero serves nothing past the TTL.
+type ReadCachePolicy struct {
+	TTL      time.Duration
+	StaleTTL time.Duration
+}
+
+// WithReadCache caches the group reads of the client, GetGroup and ListGroups, for dashboards and
+// other callers that read the same data over and over. Values younger than policy.TTL are served
+// from the cache. Values older than the TTL but younger than the TTL plus policy.StaleTTL are
+// served as well, and refreshed in the background: concurrent callers share a single refresh, and
+// a failed refresh is reported as a WarningCacheRefreshFailed instead of to the caller, who keeps
+// getting the stale value. Older values are fetched again before returning.
+//
+// Creating, renaming or removing a group through the client empties the cache. Changes made by
+// other clients are only seen once the cached values expire. A TTL of zero or less disables the
+// cache, which is the default.
+func WithReadCache(policy ReadCachePolicy) ClientOption {
+	return func(c *Client) {
+		c.readCache = nil
+		if policy.TTL > 0 {
+			c.readCache = &readCache{policy: policy, entries: make(map[string]*cacheEntry)}
+		}
+	}
+}
+
+// readCache holds the values cached by cachedRead. gen is bumped by purge, so that refreshes
+// started before a purge do not store their outdated value. refreshes tracks background refreshes
+// for the tests.
+type readCache struct {
+	policy    ReadCachePolicy
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	gen       uint64
+	refreshes sync.WaitGroup
+}
+
+// cacheEntry is a cached value, the time it was fetched, and whether a background refresh is
+// running.
+type cacheEntry struct {
+	value      interface{}
+	fetched    time.Time
+	refreshing bool
+}
+
+// purge empties the cache. It is called after mutations of the cached resources.
+func (rc *readCache) purge() {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	clear(rc.entries)
+	rc.gen++
+}
+
+// store caches value under key, unless the cache was purged since generation gen.
+func (rc *readCache) store(key string, value interface{}, fetched time.Time, gen uint64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.gen == gen {
+		rc.entries[key] = &cacheEntry{value: value, fetched: fetched}
+	}
+}
+
+// cachedRead returns the value of key from the read cache of c, calling fetch when it is missing
+// or expired, or in the background when it is stale. Without a read cache, it calls fetch. Values
+// are shared between callers, so fetch must return values that callers cannot modify, or cachedRead
+// callers must copy them.
+func cachedRead[T any](ctx context.Context, c *Client, key string, fetch func(context.Context) (T, error)) (T, error) {
+	rc := c.readCache
+	if rc == nil {
+		return fetch(ctx)
+	}
+
+	now := c.now()
+	rc.mu.Lock()
+	gen := rc.gen
+	if e, ok := rc.entries[key]; ok {
+		age := now.Sub(e.fetched)
+		switch {
+		case age < rc.policy.TTL:
+			rc.mu.Unlock()
+			return e.value.(T), nil
+		case age < rc.policy.TTL+rc.policy.StaleTTL:
+			if !e.refreshing {
+				e.refreshing = true
+				rc.refreshes.Add(1)
+				go c.refreshCached(context.WithoutCancel(ctx), key, e, gen, func(ctx context.Context) (interface{}, error) {
+					return fetch(ctx)
+				})
+			}
+			rc.mu.Unlock()
+			return e.value.(T), nil
+		}
+	}
+	rc.mu.Unlock()
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return value, err
+	}
+	rc.store(key, value, now, gen)
+	return value, nil
+}
+
+// refreshCached refreshes the stale entry e of key in the background.
+func (c *Client) refreshCached(ctx context.Context, key string, e *cacheEntry, gen uint64, fetch func(context.Context) (interface{}, error)) {
+	rc := c.readCache
+	defer rc.refreshes.Done()
+
+	fetched := c.now()
+	value, err := fetch(ctx)
+	if err != nil {
+		rc.mu.Lock()
+		e.refreshing = false
+		rc.mu.Unlock()
+		c.warn(Warning{
+			Code:    WarningCacheRefreshFailed,
+			Message: "failed to refresh a cached value, serving the stale value",
+			Detail:  map[string]interface{}{"key": key, "error": err.Error()},
+		})
+		return
+	}
+	rc.store(key, value, fetched, gen)
+}
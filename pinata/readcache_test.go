@@ -0,0 +1,192 @@
+package pinata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// groupServer is a mock API server serving a group named after the number of requests made to it.
+// While block is set, requests wait for it to be closed. While fail is set, requests fail with 503.
+type groupServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	block    chan struct{}
+	fail     atomic.Bool
+}
+
+func newGroupServer(t *testing.T) *groupServer {
+	s := &groupServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.requests.Add(1)
+		if s.block != nil {
+			<-s.block
+		}
+		if s.fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		name := `"group ` + string(rune('0'+n)) + `"`
+		switch r.URL.Path {
+		case "/groups":
+			w.Write([]byte(`[{"id":"` + testCacheGroup + `","name":` + name + `}]`))
+		default:
+			w.Write([]byte(`{"id":"` + testCacheGroup + `","name":` + name + `}`))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+const testCacheGroup = "5b9a1c2e-0d57-4a8b-9a8e-7d1f0a1b2c3d"
+
+func newCachingClient(server *groupServer, clk *fakeClock, opts ...ClientOption) *Client {
+	opts = append([]ClientOption{WithReadCache(ReadCachePolicy{TTL: time.Minute, StaleTTL: time.Hour})}, opts...)
+	client := New(&Auth{jwt: "valid_jwt_token"}, opts...)
+	client.baseURL = server.URL
+	client.clock = clk
+	return client
+}
+
+func TestReadCache(t *testing.T) {
+	t.Run("fresh values are served from the cache", func(t *testing.T) {
+		server := newGroupServer(t)
+		client := newCachingClient(server, newFakeClock())
+
+		for i := 0; i < 3; i++ {
+			group, err := client.Groups().Get(testCacheGroup)
+			require.NoError(t, err)
+			require.Equal(t, "group 1", group.GroupName)
+		}
+		require.EqualValues(t, 1, server.requests.Load())
+
+		// values are copied, so that callers cannot modify the cache
+		groups, err := client.Groups().List(nil)
+		require.NoError(t, err)
+		groups[0].GroupName = "modified"
+		groups, err = client.Groups().List(nil)
+		require.NoError(t, err)
+		require.Equal(t, "group 2", groups[0].GroupName)
+
+		// other options are cached separately
+		_, err = client.Groups().List(&ListGroupsOptions{Limit: Int(1)})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, server.requests.Load())
+	})
+
+	t.Run("stale values are served while a single refresh runs", func(t *testing.T) {
+		server := newGroupServer(t)
+		clk := newFakeClock()
+		client := newCachingClient(server, clk)
+
+		_, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+
+		clk.Advance(2 * time.Minute)
+		server.block = make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				group, err := client.Groups().Get(testCacheGroup)
+				require.NoError(t, err)
+				require.Equal(t, "group 1", group.GroupName)
+			}()
+		}
+		wg.Wait()
+		close(server.block)
+		client.readCache.refreshes.Wait()
+		require.EqualValues(t, 2, server.requests.Load())
+
+		group, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		require.Equal(t, "group 2", group.GroupName)
+		require.EqualValues(t, 2, server.requests.Load())
+	})
+
+	t.Run("failed refreshes are reported as warnings", func(t *testing.T) {
+		server := newGroupServer(t)
+		clk := newFakeClock()
+		var warnings []Warning
+		client := newCachingClient(server, clk, WithWarningHandler(func(w Warning) {
+			warnings = append(warnings, w)
+		}))
+
+		_, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+
+		clk.Advance(2 * time.Minute)
+		server.fail.Store(true)
+		group, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		require.Equal(t, "group 1", group.GroupName)
+		client.readCache.refreshes.Wait()
+		require.Len(t, warnings, 1)
+		require.Equal(t, WarningCacheRefreshFailed, warnings[0].Code)
+		require.Equal(t, "group "+testCacheGroup, warnings[0].Detail["key"])
+
+		// the next read tries again
+		server.fail.Store(false)
+		_, err = client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		client.readCache.refreshes.Wait()
+		require.EqualValues(t, 3, server.requests.Load())
+	})
+
+	t.Run("expired values are fetched again", func(t *testing.T) {
+		server := newGroupServer(t)
+		clk := newFakeClock()
+		client := newCachingClient(server, clk)
+
+		_, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+
+		clk.Advance(time.Minute + time.Hour)
+		group, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		require.Equal(t, "group 2", group.GroupName)
+
+		// without StaleTTL, values expire with the TTL
+		client = New(&Auth{jwt: "valid_jwt_token"}, WithReadCache(ReadCachePolicy{TTL: time.Minute}))
+		client.baseURL = server.URL
+		client.clock = clk
+		_, err = client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		clk.Advance(time.Minute)
+		group, err = client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		require.Equal(t, "group 4", group.GroupName)
+	})
+
+	t.Run("mutations empty the cache", func(t *testing.T) {
+		server := newGroupServer(t)
+		client := newCachingClient(server, newFakeClock())
+
+		_, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		_, err = client.Groups().Update(testCacheGroup, "renamed")
+		require.NoError(t, err)
+		group, err := client.Groups().Get(testCacheGroup)
+		require.NoError(t, err)
+		require.Equal(t, "group 3", group.GroupName)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		server := newGroupServer(t)
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = server.URL
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Groups().Get(testCacheGroup)
+			require.NoError(t, err)
+		}
+		require.EqualValues(t, 2, server.requests.Load())
+		require.Nil(t, client.readCache)
+	})
+}
@@ -0,0 +1,84 @@
+package pinata
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"time"
+)
+
+// ReasonPolicy is the retry classification of an error reason returned by Pinata, such as
+// "CURRENTLY_PINNING", which the status code alone does not tell.
+// Retryable reports whether a request that failed with the reason may be retried; it takes
+// precedence over the classification of the status code.
+// Delay is the minimum pause before retrying such a request; zero keeps the backoff of the retry
+// policy.
+type ReasonPolicy struct {
+	Retryable bool
+	Delay     time.Duration
+}
+
+// defaultRetryReasons is the table returned by DefaultRetryReasons. It only lists transient
+// reasons: the reasons of rejected requests, such as "INVALID_CREDENTIALS", come with statuses that
+// are not retried, and gateways in front of the API may relay them with a 502 that is.
+var defaultRetryReasons = map[string]ReasonPolicy{
+	"CURRENTLY_PINNING": {Retryable: true, Delay: 5 * time.Second},
+	"RATE_LIMITED":      {Retryable: true, Delay: 10 * time.Second},
+	"TOO_MANY_REQUESTS": {Retryable: true, Delay: 10 * time.Second},
+}
+
+// DefaultRetryReasons returns a copy of the table of the error reasons known to the SDK, which
+// IsRetryable and the retries of WithRetryPolicy consult before the status code. Reasons that are
+// not in the table are classified by their status code.
+func DefaultRetryReasons() map[string]ReasonPolicy {
+	return maps.Clone(defaultRetryReasons)
+}
+
+// WithRetryReasons adds reasons to the error reasons consulted by the retries of WithRetryPolicy,
+// replacing the entries of DefaultRetryReasons with the same reason. It lets callers classify
+// reasons introduced by Pinata after the release of the SDK without waiting for an update.
+func WithRetryReasons(reasons map[string]ReasonPolicy) ClientOption {
+	return func(c *Client) {
+		if c.retryReasons == nil {
+			c.retryReasons = DefaultRetryReasons()
+		}
+		maps.Copy(c.retryReasons, reasons)
+	}
+}
+
+// reasonPolicy returns the policy of the reason of err in the table of c, if err is an APIError
+// carrying a known reason.
+func (c *Client) reasonPolicy(err error) (ReasonPolicy, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Reason == "" {
+		return ReasonPolicy{}, false
+	}
+	reasons := c.retryReasons
+	if reasons == nil {
+		reasons = defaultRetryReasons
+	}
+	policy, ok := reasons[apiErr.Reason]
+	return policy, ok
+}
+
+// isRetryable is IsRetryable with the error reasons of c.
+func (c *Client) isRetryable(err error) bool {
+	if policy, ok := c.reasonPolicy(err); ok {
+		return policy.Retryable
+	}
+	return IsRetryable(err)
+}
+
+// peekAPIError returns the APIError of the non-2xx response resp, leaving its body to be read
+// again.
+func (rb *requestBuilder) peekAPIError(resp *http.Response) error {
+	err := rb.apiError(resp)
+	resp.Body.Close()
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		resp.Body = io.NopCloser(bytes.NewReader(apiErr.Raw))
+	}
+	return err
+}
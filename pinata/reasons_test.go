@@ -0,0 +1,92 @@
+package pinata
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reasonBody returns a Pinata error body carrying reason.
+func reasonBody(reason string) string {
+	return `{"error":{"reason":"` + reason + `","details":"details of ` + reason + `"}}`
+}
+
+func TestRetryReasons(t *testing.T) {
+	t.Run("IsRetryable", func(t *testing.T) {
+		require.True(t, IsRetryable(&APIError{StatusCode: http.StatusBadRequest, Reason: "CURRENTLY_PINNING"}))
+		require.False(t, IsRetryable(&APIError{StatusCode: http.StatusBadRequest, Reason: "INVALID_FIELDS"}))
+		require.True(t, IsRetryable(&APIError{StatusCode: http.StatusServiceUnavailable, Reason: "INVALID_FIELDS"}))
+	})
+
+	t.Run("DefaultRetryReasons returns a copy", func(t *testing.T) {
+		reasons := DefaultRetryReasons()
+		require.True(t, reasons["CURRENTLY_PINNING"].Retryable)
+		reasons["CURRENTLY_PINNING"] = ReasonPolicy{}
+		require.True(t, DefaultRetryReasons()["CURRENTLY_PINNING"].Retryable)
+	})
+
+	t.Run("400 with a retryable reason is retried", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`, http.StatusBadRequest)
+		var warnings []Warning
+		policy := fastRetries
+		policy.MaxBackoff = 10 * time.Millisecond
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(policy), WithWarningHandler(func(w Warning) {
+			warnings = append(warnings, w)
+		}))
+		client.baseURL = server.URL
+		server.errorBody = reasonBody("CURRENTLY_PINNING")
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+		require.EqualValues(t, 2, server.requests.Load())
+		require.Len(t, warnings, 1)
+		require.Equal(t, "CURRENTLY_PINNING", warnings[0].Detail["reason"])
+		require.Equal(t, "10ms", warnings[0].Detail["delay"])
+	})
+
+	t.Run("validation 400 is not retried", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`, http.StatusBadRequest)
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(fastRetries))
+		client.baseURL = server.URL
+		server.errorBody = reasonBody("INVALID_FIELDS")
+
+		_, err := client.TestAuthentication()
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, "INVALID_FIELDS", apiErr.Reason)
+		require.Equal(t, "details of INVALID_FIELDS", apiErr.Details)
+		require.EqualValues(t, 1, server.requests.Load())
+	})
+
+	t.Run("reasons are overridable", func(t *testing.T) {
+		reasons := map[string]ReasonPolicy{
+			"PIN_QUEUE_FULL":    {Retryable: true, Delay: 30 * time.Millisecond},
+			"CURRENTLY_PINNING": {Retryable: false},
+		}
+
+		server := newFlakyServer(t, `{"message":"ok"}`, http.StatusBadRequest)
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(fastRetries), WithRetryReasons(reasons))
+		client.baseURL = server.URL
+		server.errorBody = reasonBody("PIN_QUEUE_FULL")
+
+		start := time.Now()
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+		require.EqualValues(t, 2, server.requests.Load())
+		// the delay of the reason outlasts the backoff of the policy
+		require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+		server = newFlakyServer(t, `{"message":"ok"}`, http.StatusBadRequest)
+		client.baseURL = server.URL
+		server.errorBody = reasonBody("CURRENTLY_PINNING")
+		_, err = client.TestAuthentication()
+		require.Error(t, err)
+		require.EqualValues(t, 1, server.requests.Load())
+
+		// the defaults are left untouched
+		require.True(t, DefaultRetryReasons()["CURRENTLY_PINNING"].Retryable)
+		require.True(t, client.isRetryable(&APIError{StatusCode: http.StatusBadRequest, Reason: "RATE_LIMITED"}))
+	})
+}
@@ -0,0 +1,194 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// groupCidBatchSize is the number of CIDs sent per request when adding or removing group members.
+const groupCidBatchSize = 100
+
+// ReconcileAction is the kind of a ReconcileChange.
+type ReconcileAction string
+
+const (
+	// ReconcileCreateGroup creates a desired group that does not exist.
+	ReconcileCreateGroup ReconcileAction = "create_group"
+	// ReconcileAddCids adds the desired CIDs missing from a group.
+	ReconcileAddCids ReconcileAction = "add_cids"
+	// ReconcileRemoveCids removes the CIDs of a group that are not desired.
+	ReconcileRemoveCids ReconcileAction = "remove_cids"
+)
+
+// ReconcileOptions represents the options of ReconcileGroups.
+// DryRun plans the changes without applying them.
+// RemoveExtraneous removes the CIDs of a desired group that are not in its desired set. Without it,
+// groups only gain members.
+type ReconcileOptions struct {
+	DryRun           bool
+	RemoveExtraneous bool
+}
+
+// ReconcileChange is a single change planned by ReconcileGroups.
+// Action is the kind of change.
+// Group is the name of the group, as given in the desired mapping.
+// GroupID is the ID of the group; it is empty for a group that is yet to be created.
+// Cids are the CIDs added or removed, sorted.
+// Executed reports whether the change was applied.
+type ReconcileChange struct {
+	Action   ReconcileAction
+	Group    string
+	GroupID  string
+	Cids     []string
+	Executed bool
+}
+
+// ReconcileReport lists the changes of a ReconcileGroups call in the order they are applied:
+// group by group in name order, creation first, then additions, then removals.
+// DryRun reports whether the changes were only planned.
+type ReconcileReport struct {
+	Changes []ReconcileChange
+	DryRun  bool
+}
+
+// ReconcileGroups makes the membership of Pinata groups match desired, a mapping of group names to
+// the CIDs the groups should contain. Desired groups that do not exist are created, and missing
+// CIDs are added in batches. CIDs of a desired group that are not in its set are removed only with
+// options.RemoveExtraneous. Groups missing from desired are left untouched.
+//
+// Groups are matched by name, after the normalization applied by CreateGroup; a name shared by
+// several groups is rejected before anything is changed. The current members of a group are the
+// pinned content listed with its group ID.
+//
+// The report lists every planned change and whether it was executed. If a change fails, the
+// report of the changes made so far is returned with the error; the failed change is reported as
+// not executed, although some of its batches may have been applied.
+func (c *Client) ReconcileGroups(ctx context.Context, desired map[string][]string, options ReconcileOptions) (*ReconcileReport, error) {
+	names := make(map[string]string, len(desired))
+	for name := range desired {
+		normalized, err := c.normalizeGroupName(name)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := names[normalized]; ok {
+			return nil, fmt.Errorf("group names %q and %q refer to the same group", other, name)
+		}
+		names[normalized] = name
+	}
+
+	groups, err := c.listAllGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(groups))
+	for _, group := range groups {
+		if _, ok := names[group.GroupName]; !ok {
+			continue
+		}
+		if _, ok := ids[group.GroupName]; ok {
+			return nil, fmt.Errorf("group name %q is shared by several groups", group.GroupName)
+		}
+		ids[group.GroupName] = group.ID
+	}
+
+	report := &ReconcileReport{DryRun: options.DryRun}
+	normalized := make([]string, 0, len(names))
+	for name := range names {
+		normalized = append(normalized, name)
+	}
+	sort.Strings(normalized)
+
+	for _, name := range normalized {
+		given := names[name]
+		want := make(map[string]bool)
+		for _, cid := range desired[given] {
+			if cid != "" {
+				want[cid] = true
+			}
+		}
+
+		groupID, exists := ids[name]
+		have := make(map[string]bool)
+		if exists {
+			err := c.forEachPinPage(ctx, &ListFilesOptions{GroupID: groupID}, func(rows []Pin) error {
+				for _, row := range rows {
+					have[row.IPFSPinHash] = true
+				}
+				return nil
+			})
+			if err != nil {
+				return report, err
+			}
+		}
+
+		var changes []ReconcileChange
+		if !exists {
+			changes = append(changes, ReconcileChange{Action: ReconcileCreateGroup, Group: given})
+		}
+		if add := setDifference(want, have); len(add) > 0 {
+			changes = append(changes, ReconcileChange{Action: ReconcileAddCids, Group: given, GroupID: groupID, Cids: add})
+		}
+		if remove := setDifference(have, want); len(remove) > 0 && options.RemoveExtraneous {
+			changes = append(changes, ReconcileChange{Action: ReconcileRemoveCids, Group: given, GroupID: groupID, Cids: remove})
+		}
+
+		for _, change := range changes {
+			report.Changes = append(report.Changes, change)
+			if options.DryRun {
+				continue
+			}
+			current := &report.Changes[len(report.Changes)-1]
+			if current.GroupID == "" {
+				current.GroupID = groupID
+			}
+			if err := c.applyReconcileChange(ctx, current); err != nil {
+				return report, fmt.Errorf("failed to apply %s to group %q: %w", current.Action, given, err)
+			}
+			if current.Action == ReconcileCreateGroup {
+				groupID = current.GroupID
+			}
+		}
+	}
+	return report, nil
+}
+
+// applyReconcileChange applies change, recording the ID of a created group on it, and marks it
+// executed.
+func (c *Client) applyReconcileChange(ctx context.Context, change *ReconcileChange) error {
+	switch change.Action {
+	case ReconcileCreateGroup:
+		group, err := c.createGroup(ctx, change.Group)
+		if err != nil {
+			return err
+		}
+		change.GroupID = group.ID
+	case ReconcileAddCids, ReconcileRemoveCids:
+		for start := 0; start < len(change.Cids); start += groupCidBatchSize {
+			batch := change.Cids[start:min(start+groupCidBatchSize, len(change.Cids))]
+			var err error
+			if change.Action == ReconcileAddCids {
+				err = c.addCidToGroup(ctx, change.GroupID, batch)
+			} else {
+				err = c.removeCidFromGroup(ctx, change.GroupID, batch)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	change.Executed = true
+	return nil
+}
+
+// setDifference returns the keys of a that are not in b, sorted.
+func setDifference(a, b map[string]bool) []string {
+	var diff []string
+	for key := range a {
+		if !b[key] {
+			diff = append(diff, key)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
@@ -0,0 +1,216 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGroups is an in-memory Pinata serving the group and pinList endpoints used by
+// ReconcileGroups. It records every mutating request.
+type fakeGroups struct {
+	mu        sync.Mutex
+	names     map[string]string
+	members   map[string]map[string]bool
+	mutations []string
+}
+
+func newFakeGroups(groups map[string][]string) *fakeGroups {
+	f := &fakeGroups{names: map[string]string{}, members: map[string]map[string]bool{}}
+	for name, cids := range groups {
+		id := "id-" + name
+		f.names[id] = name
+		f.members[id] = map[string]bool{}
+		for _, cid := range cids {
+			f.members[id][cid] = true
+		}
+	}
+	return f
+}
+
+// cids returns the members of the group named name, sorted.
+func (f *fakeGroups) cids(name string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var cids []string
+	for cid := range f.members["id-"+name] {
+		cids = append(cids, cid)
+	}
+	sort.Strings(cids)
+	return cids
+}
+
+func (f *fakeGroups) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/groups":
+		ids := make([]string, 0, len(f.names))
+		for id := range f.names {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		groups := []Group{}
+		for _, id := range ids[min(offset, len(ids)):] {
+			groups = append(groups, Group{ID: id, GroupName: f.names[id]})
+		}
+		json.NewEncoder(w).Encode(groups)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/groups":
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		id := "id-" + payload["name"]
+		f.names[id] = payload["name"]
+		f.members[id] = map[string]bool{}
+		f.mutations = append(f.mutations, "create "+payload["name"])
+		json.NewEncoder(w).Encode(Group{ID: id, GroupName: payload["name"]})
+
+	case r.Method == http.MethodGet && r.URL.Path == "/data/pinList":
+		var cids []string
+		for cid := range f.members[query.Get("groupId")] {
+			cids = append(cids, cid)
+		}
+		sort.Strings(cids)
+		offset, _ := strconv.Atoi(query.Get("pageOffset"))
+		rows := []Pin{}
+		for _, cid := range cids[min(offset, len(cids)):] {
+			rows = append(rows, Pin{IPFSPinHash: cid})
+		}
+		json.NewEncoder(w).Encode(ListFilesResponse{Count: len(rows), Rows: rows})
+
+	case strings.HasSuffix(r.URL.Path, "/cids"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/groups/"), "/cids")
+		var payload map[string][]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		for _, cid := range payload["cids"] {
+			if r.Method == http.MethodPut {
+				f.members[id][cid] = true
+			} else {
+				delete(f.members[id], cid)
+			}
+		}
+		f.mutations = append(f.mutations, fmt.Sprintf("%s %s %d", r.Method, f.names[id], len(payload["cids"])))
+		w.Write([]byte(`{}`))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestReconcileGroups(t *testing.T) {
+	newClient := func(t *testing.T, fake *fakeGroups) *Client {
+		server := httptest.NewServer(fake)
+		t.Cleanup(server.Close)
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = server.URL
+		return client
+	}
+
+	t.Run("no-op", func(t *testing.T) {
+		fake := newFakeGroups(map[string][]string{"docs": {"cid1", "cid2"}, "other": {"cid3"}})
+		client := newClient(t, fake)
+
+		report, err := client.ReconcileGroups(context.Background(), map[string][]string{"docs": {"cid2", "cid1", "cid1"}}, ReconcileOptions{RemoveExtraneous: true})
+
+		require.NoError(t, err)
+		require.Empty(t, report.Changes)
+		require.Empty(t, fake.mutations)
+	})
+
+	t.Run("additions only", func(t *testing.T) {
+		fake := newFakeGroups(map[string][]string{"docs": {"cid1", "stale"}})
+		client := newClient(t, fake)
+
+		desired := map[string][]string{"docs": {"cid1", "cid2"}, " images ": {"img1"}}
+		report, err := client.ReconcileGroups(context.Background(), desired, ReconcileOptions{})
+
+		require.NoError(t, err)
+		require.Equal(t, []ReconcileChange{
+			{Action: ReconcileAddCids, Group: "docs", GroupID: "id-docs", Cids: []string{"cid2"}, Executed: true},
+			{Action: ReconcileCreateGroup, Group: " images ", GroupID: "id-images", Executed: true},
+			{Action: ReconcileAddCids, Group: " images ", GroupID: "id-images", Cids: []string{"img1"}, Executed: true},
+		}, report.Changes)
+		require.Equal(t, []string{"PUT docs 1", "create images", "PUT images 1"}, fake.mutations)
+		require.Equal(t, []string{"cid1", "cid2", "stale"}, fake.cids("docs"))
+		require.Equal(t, []string{"img1"}, fake.cids("images"))
+	})
+
+	t.Run("removals gated by RemoveExtraneous", func(t *testing.T) {
+		fake := newFakeGroups(map[string][]string{"docs": {"cid1", "stale1", "stale2"}})
+		client := newClient(t, fake)
+		desired := map[string][]string{"docs": {"cid1"}}
+
+		report, err := client.ReconcileGroups(context.Background(), desired, ReconcileOptions{})
+		require.NoError(t, err)
+		require.Empty(t, report.Changes)
+		require.Empty(t, fake.mutations)
+
+		report, err = client.ReconcileGroups(context.Background(), desired, ReconcileOptions{RemoveExtraneous: true})
+		require.NoError(t, err)
+		require.Equal(t, []ReconcileChange{
+			{Action: ReconcileRemoveCids, Group: "docs", GroupID: "id-docs", Cids: []string{"stale1", "stale2"}, Executed: true},
+		}, report.Changes)
+		require.Equal(t, []string{"DELETE docs 2"}, fake.mutations)
+		require.Equal(t, []string{"cid1"}, fake.cids("docs"))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		fake := newFakeGroups(map[string][]string{"docs": {"stale"}})
+		client := newClient(t, fake)
+
+		desired := map[string][]string{"docs": {"cid1"}, "new": {"cid2"}}
+		report, err := client.ReconcileGroups(context.Background(), desired, ReconcileOptions{DryRun: true, RemoveExtraneous: true})
+
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, []ReconcileChange{
+			{Action: ReconcileAddCids, Group: "docs", GroupID: "id-docs", Cids: []string{"cid1"}},
+			{Action: ReconcileRemoveCids, Group: "docs", GroupID: "id-docs", Cids: []string{"stale"}},
+			{Action: ReconcileCreateGroup, Group: "new"},
+			{Action: ReconcileAddCids, Group: "new", Cids: []string{"cid2"}},
+		}, report.Changes)
+		require.Empty(t, fake.mutations)
+	})
+
+	t.Run("batched additions", func(t *testing.T) {
+		fake := newFakeGroups(map[string][]string{"docs": nil})
+		client := newClient(t, fake)
+
+		var cids []string
+		for i := 0; i < 2*groupCidBatchSize+1; i++ {
+			cids = append(cids, fmt.Sprintf("cid%03d", i))
+		}
+		report, err := client.ReconcileGroups(context.Background(), map[string][]string{"docs": cids}, ReconcileOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, report.Changes, 1)
+		require.Equal(t, []string{
+			fmt.Sprintf("PUT docs %d", groupCidBatchSize),
+			fmt.Sprintf("PUT docs %d", groupCidBatchSize),
+			"PUT docs 1",
+		}, fake.mutations)
+		require.Len(t, fake.cids("docs"), len(cids))
+	})
+
+	t.Run("duplicate group names", func(t *testing.T) {
+		fake := newFakeGroups(nil)
+		client := newClient(t, fake)
+
+		_, err := client.ReconcileGroups(context.Background(), map[string][]string{"docs": nil, " docs": nil}, ReconcileOptions{})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "refer to the same group")
+	})
+}
@@ -0,0 +1,48 @@
+package pinata
+
+import "fmt"
+
+// RegionID identifies a Pinata replication region, as used by pin policies.
+type RegionID string
+
+// Region IDs documented by Pinata. The API may add regions before they are listed here, so unknown
+// IDs are reported as warnings by ValidateRegions rather than rejected.
+const (
+	RegionFRA1 RegionID = "FRA1"
+	RegionNYC1 RegionID = "NYC1"
+)
+
+// knownRegions lists the documented region IDs, in the order returned by Regions.
+var knownRegions = []RegionID{RegionFRA1, RegionNYC1}
+
+// Regions returns the documented Pinata region IDs.
+func Regions() []RegionID {
+	return append([]RegionID(nil), knownRegions...)
+}
+
+// Known reports whether id is one of the documented region IDs.
+func (id RegionID) Known() bool {
+	for _, known := range knownRegions {
+		if id == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRegions returns a WarningUnknownRegion for every id that is not a documented region ID.
+// Unknown IDs are not errors, so policies naming regions added after this release keep working.
+func ValidateRegions(ids ...RegionID) []Warning {
+	var warnings []Warning
+	for _, id := range ids {
+		if id.Known() {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Code:    WarningUnknownRegion,
+			Message: fmt.Sprintf("unknown region %q, documented regions are %v", id, knownRegions),
+			Detail:  map[string]interface{}{"region": string(id)},
+		})
+	}
+	return warnings
+}
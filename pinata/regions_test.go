@@ -0,0 +1,40 @@
+package pinata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegions(t *testing.T) {
+	t.Run("documented regions", func(t *testing.T) {
+		require.Equal(t, []RegionID{RegionFRA1, RegionNYC1}, Regions())
+		require.True(t, RegionFRA1.Known())
+		require.True(t, RegionNYC1.Known())
+
+		regions := Regions()
+		regions[0] = "XXX1"
+		require.Equal(t, RegionFRA1, Regions()[0])
+	})
+
+	t.Run("unknown region is a warning", func(t *testing.T) {
+		require.False(t, RegionID("FRA2").Known())
+		require.Empty(t, ValidateRegions(RegionFRA1, RegionNYC1))
+
+		warnings := ValidateRegions(RegionFRA1, "fra1", "SIN1")
+
+		require.Len(t, warnings, 2)
+		require.Equal(t, WarningUnknownRegion, warnings[0].Code)
+		require.Equal(t, "fra1", warnings[0].Detail["region"])
+		require.Equal(t, "SIN1", warnings[1].Detail["region"])
+	})
+
+	t.Run("region ids decode from responses", func(t *testing.T) {
+		var row Pin
+		err := json.Unmarshal([]byte(`{"regions":[{"regionId":"NYC1","currentReplicationCount":1,"desiredReplicationCount":2}]}`), &row)
+
+		require.NoError(t, err)
+		require.Equal(t, RegionNYC1, row.Regions[0].RegionID)
+	})
+}
@@ -2,11 +2,13 @@ package pinata
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,6 +16,7 @@ import (
 // requestBuilder is a struct that encapsulates the parameters and options for building an HTTP request.
 // It provides methods for adding path parameters, query parameters, headers, and request bodies.
 type requestBuilder struct {
+	ctx         context.Context
 	client      *Client
 	method      string
 	path        string
@@ -21,7 +24,30 @@ type requestBuilder struct {
 	queryParams map[string]string
 	headers     map[string]string
 	body        io.Reader
+	bodyFactory func() (io.ReadCloser, error)
 	contentType string
+	length      int64
+	err         error
+	warnings    []Warning
+	sent        bool
+	attempts    int
+	payload     int64
+	polling     bool
+	freshConn   bool
+	op          *activeOp
+}
+
+// WithContext sets the context used for the request. The context controls cancellation
+// and deadlines of the underlying HTTP call. If no context is set, context.Background is used.
+func (rb *requestBuilder) WithContext(ctx context.Context) *requestBuilder {
+	rb.ctx = ctx
+	return rb
+}
+
+// withActiveOp counts the bytes of the request body in op, which ActiveOperations reports.
+func (rb *requestBuilder) withActiveOp(op *activeOp) *requestBuilder {
+	rb.op = op
+	return rb
 }
 
 // AddPathParam adds a path parameter to the request builder. Path parameters are used to
@@ -59,22 +85,47 @@ func (rb *requestBuilder) AddHeaders(key, value string) *requestBuilder {
 
 // SetBody sets the request body and content type for the request builder.
 // The body parameter is an io.Reader that provides the request body data.
-// The contentType parameter specifies the MIME type of the request body.
+// The contentType parameter specifies the MIME type of the request body; it is only sent when
+// the body is non-empty.
 // The requestBuilder is returned to allow for method chaining.
+//
+// Bodies given as *bytes.Buffer, *bytes.Reader or *strings.Reader are retry-safe: they can be
+// re-sent when a request is retried or redirected. Any other reader can only be read once, so
+// requests using it are never retried; use SetBodyFactory for re-readable streaming bodies.
 func (rb *requestBuilder) SetBody(body io.Reader, contentType string) *requestBuilder {
 	rb.body = body
+	rb.bodyFactory = nil
 	rb.contentType = contentType
 	return rb
 }
 
+// SetBodyFactory sets a request body that is produced by factory, which is called once per attempt
+// and must return a fresh reader of the full body each time. Bodies set this way are retry-safe
+// without being held in memory, e.g. a factory that re-opens and streams the source file.
+func (rb *requestBuilder) SetBodyFactory(factory func() (io.ReadCloser, error), contentType string) *requestBuilder {
+	rb.body = nil
+	rb.bodyFactory = factory
+	rb.contentType = contentType
+	return rb
+}
+
+// SetContentLength sets the exact length of the body in bytes, which is sent as the Content-Length
+// header. It is needed for bodies set with SetBodyFactory or from readers whose length net/http
+// cannot determine; such bodies are sent with chunked transfer encoding otherwise. A length of
+// zero or less leaves the length unknown.
+func (rb *requestBuilder) SetContentLength(length int64) *requestBuilder {
+	rb.length = length
+	return rb
+}
+
 // SetJSONBody sets the request body to the provided interface{} value, marshaling it to JSON
-// and setting the Content-Type header to "application/json". It returns the requestBuilder
+// with the client's Codec and setting the Content-Type header to "application/json". It returns the requestBuilder
 // to allow for method chaining.
 //
 // If there is an error marshaling the provided value to JSON, the error is returned along
 // with the requestBuilder.
 func (rb *requestBuilder) SetJSONBody(body interface{}) (*requestBuilder, error) {
-	jsonBody, err := json.Marshal(body)
+	jsonBody, err := rb.codec().Marshal(body)
 	if err != nil {
 		return rb, err
 	}
@@ -83,7 +134,8 @@ func (rb *requestBuilder) SetJSONBody(body interface{}) (*requestBuilder, error)
 
 // setListPinsQueryParams sets the query parameters for the list pins request.
 // It takes a ListFilesOptions struct as input and adds the corresponding query
-// parameters to the requestBuilder.
+// parameters to the requestBuilder. A metadata filter that cannot be encoded is
+// recorded as an error wrapping ErrInvalidMetadata that is returned by Send.
 func (rb *requestBuilder) setListPinsQueryParams(options *ListFilesOptions) *requestBuilder {
 	if options.Cid != "" {
 		rb.AddQueryParam("cid", options.Cid)
@@ -91,11 +143,14 @@ func (rb *requestBuilder) setListPinsQueryParams(options *ListFilesOptions) *req
 	if options.GroupID != "" {
 		rb.AddQueryParam("groupId", options.GroupID)
 	}
+	if options.Name != "" {
+		rb.AddQueryParam("metadata[name]", options.Name)
+	}
 	if options.Status != "" {
 		rb.AddQueryParam("status", options.Status)
 	}
 	if options.PageLimit > 0 {
-		rb.AddQueryParam("pageLimit", options.PageLimit)
+		rb.addLimitParam("pageLimit", options.PageLimit, MaxPinListPageLimit)
 	}
 	if options.PageOffset > 0 {
 		rb.AddQueryParam("pageOffset", options.PageOffset)
@@ -118,11 +173,15 @@ func (rb *requestBuilder) setListPinsQueryParams(options *ListFilesOptions) *req
 	if options.UnpinEnd != nil {
 		rb.AddQueryParam("unpinEnd", options.UnpinEnd.Format(time.RFC3339))
 	}
-	rb.AddQueryParam("includeCount", options.IncludeCount)
+	if options.IncludeCount {
+		rb.AddQueryParam("includeCount", true)
+	}
 
 	if options.Metadata != nil {
-		metadataJSON, err := json.Marshal(options.Metadata)
-		if err == nil {
+		metadataJSON, err := rb.codec().Marshal(options.Metadata)
+		if err != nil {
+			rb.err = fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+		} else {
 			rb.AddQueryParam("metadata", string(metadataJSON))
 		}
 	}
@@ -132,12 +191,13 @@ func (rb *requestBuilder) setListPinsQueryParams(options *ListFilesOptions) *req
 
 // setListApiKeysQueryParams sets the query parameters for the ListApiKeys API endpoint.
 // It adds parameters like name, offset, revoked, limitedUse, and exhausted to the request builder.
+// Unset (nil) and negative offsets are omitted; an explicit zero is sent.
 func (rb *requestBuilder) setListApiKeysQueryParams(options *ListApiKeysOptions) *requestBuilder {
 	if options.Name != "" {
 		rb.AddQueryParam("name", options.Name)
 	}
-	if options.Offset > 0 {
-		rb.AddQueryParam("offset", options.Offset)
+	if options.Offset != nil && *options.Offset >= 0 {
+		rb.AddQueryParam("offset", *options.Offset)
 	}
 	if options.Revoked != nil {
 		rb.AddQueryParam("revoked", *options.Revoked)
@@ -154,15 +214,16 @@ func (rb *requestBuilder) setListApiKeysQueryParams(options *ListApiKeysOptions)
 
 // setListGroupsQueryParams sets the query parameters for the ListGroups API endpoint.
 // It adds parameters like nameContains, limit, and offset to the request builder.
+// Unset (nil) and negative limits and offsets are omitted; an explicit zero is sent.
 func (rb *requestBuilder) setListGroupsQueryParams(options *ListGroupsOptions) *requestBuilder {
 	if options.NameContains != "" {
 		rb.AddQueryParam("nameContains", options.NameContains)
 	}
-	if options.Limit > 0 {
-		rb.AddQueryParam("limit", options.Limit)
+	if options.Limit != nil && *options.Limit >= 0 {
+		rb.AddQueryParam("limit", *options.Limit)
 	}
-	if options.Offset > 0 {
-		rb.AddQueryParam("offset", options.Offset)
+	if options.Offset != nil && *options.Offset >= 0 {
+		rb.AddQueryParam("offset", *options.Offset)
 	}
 	return rb
 }
@@ -174,6 +235,8 @@ func (rb *requestBuilder) setListGroupsQueryParams(options *ListGroupsOptions) *
 //   - ipfs_pin_hash: Filters the returned pins by their IPFS pin hash.
 //   - limit: Limits the number of pins returned.
 //   - offset: Specifies the offset for pagination of the returned pins.
+//
+// Unset (nil) and negative limits and offsets are omitted; an explicit zero is sent.
 func (rb *requestBuilder) setListPinsByCidQueryParams(options *ListPinByCidOptions) *requestBuilder {
 	if options.Sort != "" {
 		rb.AddQueryParam("sort", string(options.Sort))
@@ -184,22 +247,87 @@ func (rb *requestBuilder) setListPinsByCidQueryParams(options *ListPinByCidOptio
 	if options.IPFSPinHash != "" {
 		rb.AddQueryParam("ipfs_pin_hash", options.IPFSPinHash)
 	}
-	if options.Limit > 0 {
-		rb.AddQueryParam("limit", options.Limit)
+	if options.Limit != nil && *options.Limit >= 0 {
+		rb.addLimitParam("limit", *options.Limit, MaxPinJobsLimit)
 	}
-	if options.Offset > 0 {
-		rb.AddQueryParam("offset", options.Offset)
+	if options.Offset != nil && *options.Offset >= 0 {
+		rb.AddQueryParam("offset", *options.Offset)
 	}
 	return rb
 }
 
+// addLimitParam adds a page size query parameter, enforcing the server cap max according to the
+// client's PageLimitPolicy: values above the cap are clamped with a WarningPageLimitClamped, or
+// recorded as an error wrapping ErrPageLimitExceeded that is returned by Send.
+func (rb *requestBuilder) addLimitParam(key string, value, max int) {
+	if value > max {
+		if rb.client != nil && rb.client.pageLimitPolicy == PageLimitReject {
+			rb.err = fmt.Errorf("%w: %s %d exceeds the maximum of %d", ErrPageLimitExceeded, key, value, max)
+			return
+		}
+		rb.warn(Warning{
+			Code:    WarningPageLimitClamped,
+			Message: "page size exceeds the server cap, clamping",
+			Detail:  map[string]interface{}{"path": rb.path, "param": key, "requested": value, "max": max},
+		})
+		value = max
+	}
+	rb.AddQueryParam(key, value)
+}
+
+// warn records w on the request, so the caller can attach it to its result, and reports it to
+// the client's warning handler.
+func (rb *requestBuilder) warn(w Warning) {
+	rb.warnings = append(rb.warnings, w)
+	rb.client.warn(w)
+}
+
+// DefaultMaxURLLength is the default maximum length, in bytes, of API request URLs. It matches the
+// 8 KB request line limit common to servers and proxies.
+const DefaultMaxURLLength = 8192
+
+// ErrQueryTooLong is returned when the URL of a request exceeds the client's URL length limit.
+var ErrQueryTooLong = errors.New("query too long")
+
+// QueryTooLongError reports a request URL exceeding the URL length limit. It matches
+// ErrQueryTooLong with errors.Is.
+// Param is the longest query parameter, the one to shrink, such as "metadata" for a large
+// ListFilesOptions.Metadata filter.
+// ParamLength is the encoded length of Param in bytes.
+// Length is the length of the URL and Limit the maximum length, in bytes.
+type QueryTooLongError struct {
+	Param       string
+	ParamLength int
+	Length      int
+	Limit       int
+}
+
+// Error implements the error interface.
+func (e *QueryTooLongError) Error() string {
+	return fmt.Sprintf("%s: the request URL is %d bytes long, the maximum is %d; query parameter %q takes %d bytes",
+		ErrQueryTooLong, e.Length, e.Limit, e.Param, e.ParamLength)
+}
+
+// Unwrap returns the underlying error.
+func (e *QueryTooLongError) Unwrap() error {
+	return ErrQueryTooLong
+}
+
 // buildURL constructs the full URL for the request by replacing path parameters
 // in the request path with their corresponding values, and adding any query
 // parameters to the URL.
 //
-// If any path parameters are not found in the request path, an error is returned.
+// The path is joined to the base URL with exactly one slash, whether or not the base URL ends with
+// a slash and the path starts with one, and repeated slashes within the path are collapsed.
+//
+// If the path is empty or any path parameters are not found in the request path, an error is returned.
+// A URL longer than the client's URL length limit fails with a *QueryTooLongError.
 func (rb *requestBuilder) buildURL() (string, error) {
-	path := rb.path
+	path, err := normalizePath(rb.path)
+	if err != nil {
+		return "", err
+	}
+	template := path
 	for key, value := range rb.pathParams {
 		placeholder := "{" + key + "}"
 		if !strings.Contains(path, placeholder) {
@@ -209,7 +337,8 @@ func (rb *requestBuilder) buildURL() (string, error) {
 		path = strings.Replace(path, placeholder, url.PathEscape(value), -1)
 	}
 
-	reqURL, err := url.Parse(rb.client.baseURL + path)
+	base := strings.TrimRight(rb.client.hostFor(template), "/")
+	reqURL, err := url.Parse(base + path)
 	if err != nil {
 		return "", err
 	}
@@ -221,54 +350,329 @@ func (rb *requestBuilder) buildURL() (string, error) {
 	}
 	reqURL.RawQuery = q.Encode()
 
-	return reqURL.String(), nil
+	built := reqURL.String()
+	if err := rb.checkURLLength(built); err != nil {
+		return "", err
+	}
+	return built, nil
+}
+
+// checkURLLength fails with a *QueryTooLongError when u is longer than the client's URL length
+// limit.
+func (rb *requestBuilder) checkURLLength(u string) error {
+	limit := rb.client.maxURLLength
+	if limit < 0 {
+		return nil
+	}
+	if limit == 0 {
+		limit = DefaultMaxURLLength
+	}
+	if len(u) <= limit {
+		return nil
+	}
+
+	tooLong := &QueryTooLongError{Length: len(u), Limit: limit}
+	for k, v := range rb.queryParams {
+		length := len(url.Values{k: {v}}.Encode())
+		if length > tooLong.ParamLength || length == tooLong.ParamLength && k < tooLong.Param {
+			tooLong.Param, tooLong.ParamLength = k, length
+		}
+	}
+	return tooLong
+}
+
+// normalizePath returns path with a single leading slash and no repeated slashes. It fails for
+// empty paths.
+func normalizePath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("request path is required")
+	}
+	path = "/" + path
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	return path, nil
+}
+
+// hasBody reports whether req carries a body. Bodies that are known to be empty are replaced
+// by http.NoBody when the request is created.
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody
 }
 
 // Send sends the HTTP request and decodes the response into the provided interface.
 // If the response status code is not in the 2xx range, it will return an error with the response body.
+//
+// A builder is single-use: its body may have been consumed by the first call, so every later call
+// fails with ErrRequestAlreadySent without sending anything. Retries of the same request, such as
+// after a 401 (see WithOnUnauthorized), are made by Send itself from retry-safe bodies.
 func (rb *requestBuilder) Send(v interface{}) error {
-	reqURL, err := rb.buildURL()
+	resp, err := rb.send()
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return rb.apiError(resp)
+	}
+
+	if v != nil {
+		if err := rb.codec().NewDecoder(resp.Body).Decode(v); err != nil {
+			return readError(err)
+		}
+	}
+
+	return nil
+}
+
+// Do sends the request with ctx exactly like Send, with the same auth headers, path and query
+// parameters, body, retries and interceptors, but returns the raw response instead of decoding it,
+// so that callers can read its headers, such as rate limit counters and request IDs, or decode
+// responses of endpoints the SDK does not model yet. Non-2xx responses are returned as is, not as
+// an *APIError. The caller must close the response body.
+//
+// Like Send, Do can only be called once per builder; later calls fail with ErrRequestAlreadySent.
+func (rb *requestBuilder) Do(ctx context.Context) (*http.Response, error) {
+	if ctx != nil {
+		rb.ctx = ctx
+	}
+	return rb.send()
+}
+
+// send implements Send and Do: it sends the request, retrying it as configured, and returns the
+// final response, which the caller must close.
+func (rb *requestBuilder) send() (resp *http.Response, err error) {
+	if rb.sent {
+		return nil, ErrRequestAlreadySent
+	}
+	rb.sent = true
+	if rb.err != nil {
+		return nil, rb.err
+	}
 
-	req, err := http.NewRequest(rb.method, reqURL, rb.body)
+	reqURL, err := rb.buildURL()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	ctx := rb.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := rb.client.apiLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := rb.client.callDeadline(ctx, rb.payloadSize())
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	body := rb.body
+	if rb.bodyFactory != nil {
+		if body, err = rb.bodyFactory(); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rb.method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if rb.bodyFactory != nil {
+		req.GetBody = rb.bodyFactory
+	}
+	if body != nil && rb.length > 0 {
+		req.ContentLength = rb.length
+	}
+	req.Close = rb.freshConn
+	if rb.op != nil && hasBody(req) {
+		countBody(req, rb.op)
 	}
 
-	// Set headers
+	// Set headers; headers added to the builder override the User-Agent
+	rb.client.setUserAgent(req)
 	for k, v := range rb.headers {
 		req.Header.Set(k, v)
 	}
 
 	// Set auth header
-	rb.client.auth.setAuthHeader(req)
+	auth := rb.client.currentAuth()
+	rb.client.setAuthHeader(auth, req)
 
-	// Set content type if body is present
-	if rb.body != nil {
+	// Set content type only if a non-empty body is present. Bodiless requests, such as GETs, never
+	// carry a Content-Type, which some WAFs reject.
+	if !hasBody(req) {
+		req.Header.Del("Content-Type")
+	} else if rb.contentType != "" {
 		req.Header.Set("Content-Type", rb.contentType)
 	}
 
-	resp, err := rb.client.httpClient.Do(req)
-	if err != nil {
-		return err
+	if err := rb.client.interceptRequest(req); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	if err := rb.client.sign(req); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var errorMsg interface{} // TODO: use a concrete type here
-		if err := json.NewDecoder(resp.Body).Decode(&errorMsg); err != nil {
-			return err
+	resp, err = rb.do(req, auth)
+	policy := rb.client.retryPolicy()
+	for attempt := 1; attempt < policy.MaxAttempts && rb.retryable(req, resp, err); attempt++ {
+		if req, err = rb.nextAttempt(req, resp, err, attempt, policy); err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("%v", errorMsg)
+		resp, err = rb.do(req, rb.client.currentAuth())
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if v != nil {
-		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-			return err
+	if err := rb.client.interceptResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// do sends a single attempt of req, sent with the credentials auth, retrying it once with fresh
+// credentials after a 401 when WithOnUnauthorized is set.
+func (rb *requestBuilder) do(req *http.Request, auth *Auth) (*http.Response, error) {
+	resp, err := rb.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		rb.client.invalidateAuthHealth()
+	}
+	if resp.StatusCode == http.StatusUnauthorized && rb.client.canFallBack(auth, req) {
+		if resp, err = rb.retryWithAPIKey(req, resp, auth); err != nil {
+			return nil, err
 		}
 	}
+	if resp.StatusCode == http.StatusUnauthorized && rb.client.onUnauthorized != nil {
+		return rb.retryUnauthorized(req, resp, auth)
+	}
+	return resp, nil
+}
 
-	return nil
+// roundTrip sends req once, as every request sent to the API is sent: logged with the logger set
+// with WithLogger, traced under WithConnTrace, and with the rate limit reported by the response
+// recorded.
+func (rb *requestBuilder) roundTrip(req *http.Request) (resp *http.Response, err error) {
+	req, logged := rb.logAttempt(req)
+	defer func() { logged(resp, err) }()
+
+	traceReq, traced := rb.client.traceConn(req)
+	resp, err = rb.httpClient().Do(traceReq)
+	traced(resp)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	rb.client.recordRateLimit(resp)
+	return resp, nil
+}
+
+// resend sends req again with the credentials auth, once the limiter set with WithAPIRateLimit
+// allows it, through roundTrip as the first attempt. The changes of the request interceptors to req
+// are kept.
+func (rb *requestBuilder) resend(req *http.Request, auth *Auth) (*http.Response, error) {
+	if err := rb.client.apiLimiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	retry, err := rb.replay(req, auth)
+	if err != nil {
+		return nil, err
+	}
+	return rb.roundTrip(retry)
+}
+
+// apiError builds the APIError of a non-2xx response. The error shapes of Pinata are recognized by
+// parseErrorBody; other bodies, such as plain text, are reported verbatim, or as the status line
+// when empty, as for some 429 responses.
+func (rb *requestBuilder) apiError(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return readError(err)
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		RateLimit:  parseRateLimit(resp.Header, rb.client.now()),
+		Raw:        body,
+	}
+	var decoded interface{}
+	switch {
+	case rb.codec().NewDecoder(bytes.NewReader(body)).Decode(&decoded) == nil && parseErrorBody(decoded, apiErr):
+	case len(bytes.TrimSpace(body)) > 0:
+		apiErr.Message = string(bytes.TrimSpace(body))
+	default:
+		apiErr.Message = resp.Status
+	}
+	return apiErr
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+// It returns zero when the header is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// retryUnauthorized refreshes the client credentials after req was rejected with 401 and retries
+// the request once with the fresh credentials. Requests whose body cannot be replayed are not
+// retried and the original response is returned.
+func (rb *requestBuilder) retryUnauthorized(req *http.Request, resp *http.Response, stale *Auth) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	auth, err := rb.client.refreshAuth(req.Context(), stale)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to refresh credentials after 401: %w", err)
+	}
+
+	resp.Body.Close()
+	return rb.resend(req, auth)
+}
+
+// replay returns a copy of req to send again with the credentials auth, with a fresh body from
+// req.GetBody, if any.
+func (rb *requestBuilder) replay(req *http.Request, auth *Auth) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	retry.Header.Del("Authorization")
+	retry.Header.Del("pinata_api_key")
+	retry.Header.Del("pinata_secret_api_key")
+	rb.client.setAuthHeader(auth, retry)
+	if err := rb.client.sign(retry); err != nil {
+		return nil, err
+	}
+	return retry, nil
 }
@@ -2,14 +2,22 @@ package pinata
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
 )
 
 func TestAddPathParam(t *testing.T) {
@@ -325,6 +333,7 @@ func TestSetListPinsQueryParams(t *testing.T) {
 		options := &ListFilesOptions{
 			Cid:          "testCid",
 			GroupID:      "testGroupId",
+			Name:         "testName",
 			Status:       "testStatus",
 			PageLimit:    10,
 			PageOffset:   5,
@@ -343,6 +352,7 @@ func TestSetListPinsQueryParams(t *testing.T) {
 		require.Equal(t, rb, result)
 		require.Equal(t, "testCid", rb.queryParams["cid"])
 		require.Equal(t, "testGroupId", rb.queryParams["groupId"])
+		require.Equal(t, "testName", rb.queryParams["metadata[name]"])
 		require.Equal(t, "testStatus", rb.queryParams["status"])
 		require.Equal(t, "10", rb.queryParams["pageLimit"])
 		require.Equal(t, "5", rb.queryParams["pageOffset"])
@@ -367,8 +377,8 @@ func TestSetListPinsQueryParams(t *testing.T) {
 
 		require.Equal(t, rb, result)
 		require.Equal(t, "testCid", rb.queryParams["cid"])
-		require.Equal(t, "false", rb.queryParams["includeCount"])
-		require.Len(t, rb.queryParams, 2)
+		require.NotContains(t, rb.queryParams, "includeCount")
+		require.Len(t, rb.queryParams, 1)
 	})
 
 	t.Run("with zero values", func(t *testing.T) {
@@ -384,8 +394,8 @@ func TestSetListPinsQueryParams(t *testing.T) {
 		result := rb.setListPinsQueryParams(options)
 
 		require.Equal(t, rb, result)
-		require.Equal(t, "false", rb.queryParams["includeCount"])
-		require.Len(t, rb.queryParams, 1)
+		require.NotContains(t, rb.queryParams, "includeCount")
+		require.Empty(t, rb.queryParams)
 	})
 
 	t.Run("with nil time pointers", func(t *testing.T) {
@@ -406,6 +416,31 @@ func TestSetListPinsQueryParams(t *testing.T) {
 		require.NotContains(t, rb.queryParams, "unpinEnd")
 	})
 
+	t.Run("with pinned between helper", func(t *testing.T) {
+		rb := &requestBuilder{}
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		options := (&ListFilesOptions{}).WithPinnedBetween(start, end)
+
+		rb.setListPinsQueryParams(options)
+
+		require.Equal(t, "2024-01-01T00:00:00Z", rb.queryParams["pinStart"])
+		require.Equal(t, "2024-02-01T00:00:00Z", rb.queryParams["pinEnd"])
+		require.NotContains(t, rb.queryParams, "status")
+	})
+
+	t.Run("with unpinned between helper", func(t *testing.T) {
+		rb := &requestBuilder{}
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		options := (&ListFilesOptions{Status: "pinned"}).WithUnpinnedBetween(start, time.Time{})
+
+		rb.setListPinsQueryParams(options)
+
+		require.Equal(t, "2024-01-01T00:00:00Z", rb.queryParams["unpinStart"])
+		require.NotContains(t, rb.queryParams, "unpinEnd")
+		require.Equal(t, "unpinned", rb.queryParams["status"])
+	})
+
 	t.Run("with invalid metadata", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListFilesOptions{
@@ -417,6 +452,18 @@ func TestSetListPinsQueryParams(t *testing.T) {
 		require.Equal(t, rb, result)
 		require.NotContains(t, rb.queryParams, "metadata")
 	})
+
+	t.Run("with unencodable metadata", func(t *testing.T) {
+		rb := &requestBuilder{}
+		options := &ListFilesOptions{
+			Metadata: map[string]interface{}{"score": math.NaN()},
+		}
+
+		rb.setListPinsQueryParams(options)
+
+		require.ErrorIs(t, rb.err, ErrInvalidMetadata)
+		require.NotContains(t, rb.queryParams, "metadata")
+	})
 }
 
 func TestSetListApiKeysQueryParams(t *testing.T) {
@@ -427,7 +474,7 @@ func TestSetListApiKeysQueryParams(t *testing.T) {
 		exhausted := true
 		options := &ListApiKeysOptions{
 			Name:       "testName",
-			Offset:     10,
+			Offset:     Int(10),
 			Revoked:    &revoked,
 			LimitedUse: &limitedUse,
 			Exhausted:  &exhausted,
@@ -459,7 +506,7 @@ func TestSetListApiKeysQueryParams(t *testing.T) {
 	t.Run("with only offset set", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListApiKeysOptions{
-			Offset: 5,
+			Offset: Int(5),
 		}
 
 		result := rb.setListApiKeysQueryParams(options)
@@ -469,14 +516,24 @@ func TestSetListApiKeysQueryParams(t *testing.T) {
 		require.Len(t, rb.queryParams, 1)
 	})
 
-	t.Run("with zero offset", func(t *testing.T) {
+	t.Run("with explicit zero offset", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListApiKeysOptions{
-			Offset: 0,
+			Offset: Int(0),
 		}
 
 		result := rb.setListApiKeysQueryParams(options)
 
+		require.Equal(t, rb, result)
+		require.Equal(t, "0", rb.queryParams["offset"])
+		require.Len(t, rb.queryParams, 1)
+	})
+
+	t.Run("with unset offset", func(t *testing.T) {
+		rb := &requestBuilder{}
+
+		result := rb.setListApiKeysQueryParams(&ListApiKeysOptions{})
+
 		require.Equal(t, rb, result)
 		require.Len(t, rb.queryParams, 0)
 	})
@@ -505,7 +562,7 @@ func TestSetListApiKeysQueryParams(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListApiKeysOptions{
 			Name:   "testName",
-			Offset: 5,
+			Offset: Int(5),
 		}
 
 		result := rb.setListApiKeysQueryParams(options)
@@ -525,8 +582,8 @@ func TestSetListGroupsQueryParams(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListGroupsOptions{
 			NameContains: "test",
-			Limit:        10,
-			Offset:       5,
+			Limit:        Int(10),
+			Offset:       Int(5),
 		}
 
 		result := rb.setListGroupsQueryParams(options)
@@ -553,7 +610,7 @@ func TestSetListGroupsQueryParams(t *testing.T) {
 	t.Run("with only limit set", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListGroupsOptions{
-			Limit: 20,
+			Limit: Int(20),
 		}
 
 		result := rb.setListGroupsQueryParams(options)
@@ -566,7 +623,7 @@ func TestSetListGroupsQueryParams(t *testing.T) {
 	t.Run("with only offset set", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListGroupsOptions{
-			Offset: 15,
+			Offset: Int(15),
 		}
 
 		result := rb.setListGroupsQueryParams(options)
@@ -576,16 +633,27 @@ func TestSetListGroupsQueryParams(t *testing.T) {
 		require.Len(t, rb.queryParams, 1)
 	})
 
-	t.Run("with zero values", func(t *testing.T) {
+	t.Run("with explicit zero values", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListGroupsOptions{
 			NameContains: "",
-			Limit:        0,
-			Offset:       0,
+			Limit:        Int(0),
+			Offset:       Int(0),
 		}
 
 		result := rb.setListGroupsQueryParams(options)
 
+		require.Equal(t, rb, result)
+		require.Equal(t, "0", rb.queryParams["limit"])
+		require.Equal(t, "0", rb.queryParams["offset"])
+		require.Len(t, rb.queryParams, 2)
+	})
+
+	t.Run("with unset values", func(t *testing.T) {
+		rb := &requestBuilder{}
+
+		result := rb.setListGroupsQueryParams(&ListGroupsOptions{})
+
 		require.Equal(t, rb, result)
 		require.Len(t, rb.queryParams, 0)
 	})
@@ -593,8 +661,8 @@ func TestSetListGroupsQueryParams(t *testing.T) {
 	t.Run("with negative limit and offset", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListGroupsOptions{
-			Limit:  -10,
-			Offset: -5,
+			Limit:  Int(-10),
+			Offset: Int(-5),
 		}
 
 		result := rb.setListGroupsQueryParams(options)
@@ -611,8 +679,8 @@ func TestSetListPinsByCidQueryParams(t *testing.T) {
 			Sort:        SortOrderASC,
 			Status:      PinStatusRetrieving,
 			IPFSPinHash: "QmTest123",
-			Limit:       100,
-			Offset:      10,
+			Limit:       Int(100),
+			Offset:      Int(10),
 		}
 
 		result := rb.setListPinsByCidQueryParams(options)
@@ -658,25 +726,25 @@ func TestSetListPinsByCidQueryParams(t *testing.T) {
 		require.NotContains(t, rb.queryParams, "offset")
 	})
 
-	t.Run("with zero limit and offset", func(t *testing.T) {
+	t.Run("with explicit zero limit and offset", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListPinByCidOptions{
-			Limit:  0,
-			Offset: 0,
+			Limit:  Int(0),
+			Offset: Int(0),
 		}
 
 		result := rb.setListPinsByCidQueryParams(options)
 
 		require.Equal(t, rb, result)
-		require.NotContains(t, rb.queryParams, "limit")
-		require.NotContains(t, rb.queryParams, "offset")
+		require.Equal(t, "0", rb.queryParams["limit"])
+		require.Equal(t, "0", rb.queryParams["offset"])
 	})
 
 	t.Run("with negative limit and offset", func(t *testing.T) {
 		rb := &requestBuilder{}
 		options := &ListPinByCidOptions{
-			Limit:  -10,
-			Offset: -5,
+			Limit:  Int(-10),
+			Offset: Int(-5),
 		}
 
 		result := rb.setListPinsByCidQueryParams(options)
@@ -763,6 +831,78 @@ func TestBuildURL(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "https://api.pinata.cloud/v1/data?limit=10&offset=0&status=pinned", url)
 	})
+
+	joins := []struct {
+		base     string
+		path     string
+		expected string
+	}{
+		{"https://api.pinata.cloud", "/data/pinList", "https://api.pinata.cloud/data/pinList"},
+		{"https://api.pinata.cloud", "data/pinList", "https://api.pinata.cloud/data/pinList"},
+		{"https://api.pinata.cloud/", "/data/pinList", "https://api.pinata.cloud/data/pinList"},
+		{"https://api.pinata.cloud/", "data/pinList", "https://api.pinata.cloud/data/pinList"},
+		{"https://api.pinata.cloud//", "//data//pinList", "https://api.pinata.cloud/data/pinList"},
+		{"http://localhost:8080/pinata/", "/data/pinList/", "http://localhost:8080/pinata/data/pinList/"},
+	}
+	for _, tc := range joins {
+		t.Run(fmt.Sprintf("join %q and %q", tc.base, tc.path), func(t *testing.T) {
+			rb := &requestBuilder{
+				client: &Client{baseURL: tc.base},
+				path:   tc.path,
+			}
+
+			url, err := rb.buildURL()
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, url)
+		})
+	}
+
+	t.Run("path parameters after normalization", func(t *testing.T) {
+		rb := &requestBuilder{
+			client:     &Client{baseURL: "https://api.pinata.cloud/"},
+			path:       "groups//{groupId}",
+			pathParams: map[string]string{"groupId": "a/b"},
+		}
+
+		url, err := rb.buildURL()
+
+		require.NoError(t, err)
+		require.Equal(t, "https://api.pinata.cloud/groups/a%2Fb", url)
+	})
+
+	t.Run("URL length limit", func(t *testing.T) {
+		rb := &requestBuilder{
+			client: &Client{baseURL: "https://api.pinata.cloud", maxURLLength: 100},
+			path:   "/data/pinList",
+			queryParams: map[string]string{
+				"status":   "pinned",
+				"metadata": strings.Repeat("x", 80),
+			},
+		}
+
+		_, err := rb.buildURL()
+
+		require.ErrorIs(t, err, ErrQueryTooLong)
+		var tooLong *QueryTooLongError
+		require.ErrorAs(t, err, &tooLong)
+		require.Equal(t, QueryTooLongError{Param: "metadata", ParamLength: 89, Length: 141, Limit: 100}, *tooLong)
+
+		rb.client.maxURLLength = -1
+		_, err = rb.buildURL()
+		require.NoError(t, err)
+	})
+
+	for _, path := range []string{"", "  "} {
+		t.Run(fmt.Sprintf("empty path %q", path), func(t *testing.T) {
+			client := New(NewAuthWithJWT("test_token"))
+
+			err := client.NewRequest(http.MethodGet, path).Send(nil)
+
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "request path is required")
+		})
+	}
 }
 
 func TestSend(t *testing.T) {
@@ -936,4 +1076,569 @@ func TestSend(t *testing.T) {
 
 		require.Error(t, err)
 	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+
+		client := &Client{
+			baseURL:    mockServer.URL,
+			httpClient: mockServer.Client(),
+			auth:       NewAuthWithJWT("test_token"),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rb := &requestBuilder{
+			client: client,
+			method: http.MethodGet,
+			path:   "/test",
+		}
+
+		err := rb.WithContext(ctx).Send(nil)
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("no content type without body", func(t *testing.T) {
+		var contentTypes []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = mockServer.URL
+
+		require.NoError(t, client.NewRequest(http.MethodGet, "/test").SetBody(nil, "application/json").Send(nil))
+		require.NoError(t, client.NewRequest(http.MethodGet, "/test").SetBody(strings.NewReader(""), "application/json").Send(nil))
+		require.NoError(t, client.NewRequest(http.MethodHead, "/test").AddHeaders("Content-Type", "application/json").Send(nil))
+		require.NoError(t, client.NewRequest(http.MethodDelete, "/test").SetBody(&bytes.Buffer{}, "application/json").Send(nil))
+		require.NoError(t, client.NewRequest(http.MethodPost, "/test").SetBody(strings.NewReader("{}"), "application/json").Send(nil))
+
+		require.Equal(t, []string{"", "", "", "", "application/json"}, contentTypes)
+	})
+}
+
+func TestAPIError(t *testing.T) {
+	t.Run("json body", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Bad request"}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		err := client.NewRequest(http.MethodGet, "/test").Send(nil)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		require.Equal(t, "Bad request", apiErr.Error())
+		require.Zero(t, apiErr.RetryAfter)
+	})
+
+	t.Run("empty body with retry after", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		err := client.NewRequest(http.MethodGet, "/test").Send(nil)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		require.Equal(t, "429 Too Many Requests", apiErr.Error())
+		require.Equal(t, 3*time.Second, apiErr.RetryAfter)
+	})
+
+	t.Run("plain text body", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("upstream unavailable\n"))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		err := client.NewRequest(http.MethodGet, "/test").Send(nil)
+
+		require.EqualError(t, err, "upstream unavailable")
+	})
+}
+
+func TestSendTwice(t *testing.T) {
+	var bodies []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(raw))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	t.Run("second send fails", func(t *testing.T) {
+		bodies = nil
+		rb := client.NewRequest(http.MethodPost, "/test").SetBody(io.LimitReader(strings.NewReader(`{"a":1}`), 7), "application/json")
+
+		require.NoError(t, rb.Send(nil))
+		require.ErrorIs(t, rb.Send(nil), ErrRequestAlreadySent)
+		require.Equal(t, []string{`{"a":1}`}, bodies)
+	})
+
+	t.Run("after a failed request", func(t *testing.T) {
+		bodies = nil
+		rb := client.NewRequest(http.MethodPost, "/fail").SetBody(strings.NewReader(`{"a":1}`), "application/json")
+
+		var apiErr *APIError
+		require.ErrorAs(t, rb.Send(nil), &apiErr)
+		require.ErrorIs(t, rb.Send(nil), ErrRequestAlreadySent)
+		require.Len(t, bodies, 1)
+	})
+}
+
+func TestDo(t *testing.T) {
+	var requests []*http.Request
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		if r.URL.Path == "/v3/new/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{"shape":"unmodeled"}`))
+	}))
+	defer mockServer.Close()
+
+	build := func(c *Client, path string) *requestBuilder {
+		return c.NewRequest(http.MethodGet, path).
+			AddPathParam("name", "a b").
+			AddQueryParam("filter", "x&y=z").
+			AddQueryParam("limit", 10).
+			AddHeaders("X-Custom", "custom")
+	}
+
+	t.Run("sends the request like Send", func(t *testing.T) {
+		for _, auth := range []*Auth{NewAuthWithJWT("valid_jwt_token"), NewAuth("key", "secret", "")} {
+			requests = nil
+			client := New(auth)
+			client.baseURL = mockServer.URL
+
+			require.NoError(t, build(client, "/v3/new/{name}").Send(nil))
+			resp, err := build(client, "/v3/new/{name}").Do(context.Background())
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Len(t, requests, 2)
+			sent, done := requests[0], requests[1]
+			require.Equal(t, sent.URL.RawPath, done.URL.RawPath)
+			require.Equal(t, sent.URL.Path, done.URL.Path)
+			require.Equal(t, "x&y=z", done.URL.Query().Get("filter"))
+			require.Equal(t, sent.URL.RawQuery, done.URL.RawQuery)
+			for _, header := range []string{"Authorization", "pinata_api_key", "pinata_secret_api_key", "User-Agent", "X-Custom"} {
+				require.Equal(t, sent.Header.Get(header), done.Header.Get(header), header)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, `{"shape":"unmodeled"}`, string(body))
+			require.Equal(t, "req-1", resp.Header.Get("X-Request-Id"))
+			require.Equal(t, "59", resp.Header.Get("X-RateLimit-Remaining"))
+		}
+	})
+
+	t.Run("returns error responses as is", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		resp, err := client.NewRequest(http.MethodGet, "/v3/new/missing").Do(context.Background())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("sends a single request", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		rb := client.NewRequest(http.MethodGet, "/v3/new")
+		resp, err := rb.Do(context.Background())
+		require.NoError(t, err)
+		resp.Body.Close()
+		_, err = rb.Do(context.Background())
+		require.ErrorIs(t, err, ErrRequestAlreadySent)
+		require.ErrorIs(t, rb.Send(nil), ErrRequestAlreadySent)
+	})
+
+	t.Run("uses the context", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.NewRequest(http.MethodGet, "/v3/new").Do(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAPIErrorShapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+		reason  string
+		details string
+	}{
+		{"error string", `{"error":"Invalid request format."}`, "Invalid request format.", "", ""},
+		{"reason and details", `{"error":{"reason":"INVALID_CREDENTIALS","details":"Invalid/expired credentials provided"}}`, "INVALID_CREDENTIALS: Invalid/expired credentials provided", "INVALID_CREDENTIALS", "Invalid/expired credentials provided"},
+		{"reason only", `{"error":{"reason":"KEY_REVOKED"}}`, "KEY_REVOKED", "KEY_REVOKED", ""},
+		{"details only", `{"error":{"details":"missing cid"}}`, "missing cid", "", "missing cid"},
+		{"structured details", `{"error":{"reason":"INVALID_FIELDS","details":{"field":"name"}}}`, `INVALID_FIELDS: {"field":"name"}`, "INVALID_FIELDS", `{"field":"name"}`},
+		{"v3 message", `{"error":{"code":400,"message":"file too large"}}`, "file too large", "", ""},
+		{"v3 message with reason", `{"error":{"reason":"QUOTA","message":"storage limit reached"}}`, "storage limit reached", "QUOTA", ""},
+		{"other json object", `{"status":"failed"}`, `{"status":"failed"}`, "", ""},
+		{"empty error object", `{"error":{}}`, `{"error":{}}`, "", ""},
+		{"json string", `"not found"`, `"not found"`, "", ""},
+		{"plain text", "upstream unavailable\n", "upstream unavailable", "", ""},
+		{"empty body", "", "400 Bad Request", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(tt.body))
+			}))
+			defer mockServer.Close()
+
+			client := New(&Auth{jwt: "valid_jwt_token"})
+			client.baseURL = mockServer.URL
+
+			err := client.NewRequest(http.MethodGet, "/test").Send(nil)
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+			require.Equal(t, tt.message, apiErr.Message)
+			require.Equal(t, tt.reason, apiErr.Reason)
+			require.Equal(t, tt.details, apiErr.Details)
+			require.Equal(t, []byte(tt.body), apiErr.Raw)
+		})
+	}
+}
+
+func TestAPIErrorFromEndpoints(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/origin" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"reason":"NO_SCOPES_FOUND","details":"This key does not have the required scopes"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	path := filepath.Join(writeTree(t, "a.txt"), "a.txt")
+	calls := map[string]func() error{
+		"PinFile": func() error {
+			_, err := client.PinFile(path, nil)
+			return err
+		},
+		"PinJSON": func() error {
+			_, err := client.PinJSON(map[string]string{"a": "b"}, nil)
+			return err
+		},
+		"ListFiles": func() error {
+			_, err := client.ListFiles(nil)
+			return err
+		},
+		"Groups().Create": func() error {
+			_, err := client.Groups().Create("group")
+			return err
+		},
+		"Keys().Revoke": func() error { return client.Keys().Revoke("key") },
+		"DeleteFile":    func() error { return client.DeleteFile(cidV0) },
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+			require.ErrorContains(t, err, "NO_SCOPES_FOUND")
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+			require.Equal(t, "NO_SCOPES_FOUND", apiErr.Reason)
+			require.Equal(t, "This key does not have the required scopes", apiErr.Details)
+			require.JSONEq(t, `{"error":{"reason":"NO_SCOPES_FOUND","details":"This key does not have the required scopes"}}`, string(apiErr.Raw))
+		})
+	}
+
+	t.Run("origin of PinURL", func(t *testing.T) {
+		_, err := client.PinURL(mockServer.URL+"/origin", nil)
+		require.ErrorContains(t, err, "HTTP error: 404 Not Found")
+
+		var originErr *OriginError
+		require.ErrorAs(t, err, &originErr)
+		require.Equal(t, http.StatusNotFound, originErr.StatusCode)
+		require.Equal(t, mockServer.URL+"/origin", originErr.URL)
+		require.NotErrorIs(t, err, ErrNotFound)
+		require.False(t, errors.As(err, new(*APIError)))
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	require.Zero(t, parseRetryAfter(""))
+	require.Zero(t, parseRetryAfter("soon"))
+	require.Equal(t, 120*time.Second, parseRetryAfter("120"))
+	require.Zero(t, parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)))
+
+	later := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	require.InDelta(t, float64(time.Hour), float64(later), float64(2*time.Second))
+}
+
+func TestRetrySafeBodies(t *testing.T) {
+	// newRetryServer rejects the first request with 401 and records the full body of every request.
+	newRetryServer := func(t *testing.T, bodies *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			*bodies = append(*bodies, string(raw))
+
+			if len(*bodies) == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Unauthorized"}`))
+				return
+			}
+			w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+		}))
+	}
+	refresh := WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+		return NewAuthWithJWT("fresh_token"), nil
+	})
+
+	t.Run("file upload is re-read from disk", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "b.txt")
+		var bodies []string
+		mockServer := newRetryServer(t, &bodies)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), refresh)
+		client.baseURL = mockServer.URL
+
+		response, err := client.PinFile(filepath.Join(dir, "a.txt"), &PinOptions{PinataMetadata: PinataMetadata{Name: "a"}})
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+
+		require.Len(t, bodies, 2)
+		require.Contains(t, bodies[0], "content of a.txt")
+		require.Contains(t, bodies[0], `name="pinataMetadata"`)
+		require.Equal(t, bodies[0], bodies[1])
+	})
+
+	t.Run("folder upload is re-read from disk", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "b.txt")
+		var bodies []string
+		mockServer := newRetryServer(t, &bodies)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), refresh)
+		client.baseURL = mockServer.URL
+
+		_, err := client.PinFolder([]string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, nil)
+		require.NoError(t, err)
+
+		require.Len(t, bodies, 2)
+		require.Contains(t, bodies[0], "content of a.txt")
+		require.Contains(t, bodies[0], "content of b.txt")
+		require.Equal(t, bodies[0], bodies[1])
+	})
+
+	t.Run("body factory is called per attempt", func(t *testing.T) {
+		var bodies []string
+		mockServer := newRetryServer(t, &bodies)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), refresh)
+		client.baseURL = mockServer.URL
+
+		var calls int
+		err := client.NewRequest(http.MethodPost, "/test").
+			SetBodyFactory(func() (io.ReadCloser, error) {
+				calls++
+				return io.NopCloser(strings.NewReader("payload")), nil
+			}, "text/plain").
+			Send(nil)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, calls)
+		require.Equal(t, []string{"payload", "payload"}, bodies)
+	})
+
+	t.Run("one-shot reader is not retried", func(t *testing.T) {
+		var bodies []string
+		mockServer := newRetryServer(t, &bodies)
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("stale_token"), refresh)
+		client.baseURL = mockServer.URL
+
+		err := client.NewRequest(http.MethodPost, "/test").
+			SetBody(io.MultiReader(strings.NewReader("payload")), "text/plain").
+			Send(nil)
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unauthorized")
+		require.Len(t, bodies, 1)
+	})
+
+	t.Run("factory error", func(t *testing.T) {
+		client := New(NewAuthWithJWT("token"))
+
+		err := client.NewRequest(http.MethodPost, "/test").
+			SetBodyFactory(func() (io.ReadCloser, error) {
+				return nil, fmt.Errorf("source unavailable")
+			}, "text/plain").
+			Send(nil)
+
+		require.EqualError(t, err, "source unavailable")
+	})
+}
+
+func TestReplayedRequests(t *testing.T) {
+	var traces, unintercepted atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") == "" {
+			unintercepted.Add(1)
+		}
+		if r.Header.Get("Authorization") == "Bearer jwt" {
+			w.Header().Set("X-RateLimit-Remaining", "9")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "8")
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	replays := map[string]ClientOption{
+		"auth fallback": WithAuthFallback(time.Minute),
+		"on unauthorized": WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+			return NewAuthWithJWT("fresh"), nil
+		}),
+	}
+	for name, replay := range replays {
+		t.Run(name, func(t *testing.T) {
+			traces.Store(0)
+			logs := &logRecorder{level: slog.LevelDebug}
+			client := New(NewAuth("key", "secret", "jwt"), replay,
+				WithLogger(slog.New(logs)),
+				WithConnTrace(func(ConnTrace) { traces.Add(1) }),
+				WithRequestInterceptor(func(req *http.Request) error {
+					req.Header.Set("X-Trace", "trace-id")
+					return nil
+				}),
+				WithAPIRateLimit(50, 1),
+			)
+			client.baseURL = mockServer.URL
+
+			start := time.Now()
+			_, err := client.TestAuthentication()
+			require.NoError(t, err)
+
+			// the replay waits for the limiter, keeps the interceptor changes, and is logged and
+			// traced like the first attempt
+			require.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+			require.Zero(t, unintercepted.Load())
+			require.Equal(t, int32(2), traces.Load())
+			requests := logs.messages("api request")
+			require.Len(t, requests, 2)
+			require.EqualValues(t, http.StatusUnauthorized, requests[0]["status"])
+			require.EqualValues(t, http.StatusOK, requests[1]["status"])
+			require.EqualValues(t, 2, requests[1]["attempt"])
+
+			info, ok := client.LastRateLimit()
+			require.True(t, ok)
+			require.Equal(t, 8, info.Remaining)
+		})
+	}
+}
+
+func TestAPIRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	t.Run("paces requests", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithAPIRateLimit(50, 2))
+		client.baseURL = mockServer.URL
+
+		// the burst goes through at once, the rest at 20ms intervals
+		start := time.Now()
+		for i := 0; i < 2; i++ {
+			_, err := client.PinJSON(map[string]int{"i": i}, nil)
+			require.NoError(t, err)
+		}
+		require.Less(t, time.Since(start), 20*time.Millisecond)
+		for i := 0; i < 3; i++ {
+			_, err := client.PinJSON(map[string]int{"i": i}, nil)
+			require.NoError(t, err)
+		}
+		require.GreaterOrEqual(t, time.Since(start), 55*time.Millisecond)
+	})
+
+	t.Run("applies across the workers of DeleteFilesAsync", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithAPIRateLimit(50, 1), WithConcurrency(5))
+		client.baseURL = mockServer.URL
+
+		start := time.Now()
+		errs := client.DeleteFilesAsync([]string{cidV0, cidV0, cidV0, cidV0, cidV0})
+		require.Empty(t, errs)
+		require.GreaterOrEqual(t, time.Since(start), 75*time.Millisecond)
+	})
+
+	t.Run("applies across the workers of PinFilesAsync", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithAPIRateLimit(50, 1), WithConcurrency(5))
+		client.baseURL = mockServer.URL
+		dir := writeTree(t, "a.txt", "b.txt", "c.txt", "d.txt", "e.txt")
+		paths := []string{
+			filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "c.txt"),
+			filepath.Join(dir, "d.txt"), filepath.Join(dir, "e.txt"),
+		}
+
+		// five workers share one token every 20ms: the first upload goes at once, the last after 80ms
+		start := time.Now()
+		responses, err := client.PinFilesAsync(paths, nil)
+		require.NoError(t, err)
+		require.Len(t, responses, 5)
+		require.GreaterOrEqual(t, time.Since(start), 75*time.Millisecond)
+	})
+
+	t.Run("zero removes the limit", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithAPIRateLimit(1, 1), WithAPIRateLimit(0, 1))
+		require.Nil(t, client.apiLimiter)
+	})
 }
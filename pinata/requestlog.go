@@ -0,0 +1,155 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityRequestLog)
+}
+
+// DefaultLogBodyLimit is the default number of bytes of each request body logged at the debug
+// level by the logger set with WithLogger.
+const DefaultLogBodyLimit = 2048
+
+// WithLogBodyLimit sets how many bytes of each request body are logged at the debug level by the
+// logger set with WithLogger. Longer bodies, such as multipart uploads, are truncated, and their
+// size is logged as body_size. Zero keeps DefaultLogBodyLimit and a negative value logs no body.
+func WithLogBodyLimit(limit int) ClientOption {
+	return func(c *Client) {
+		c.logBodyLimit = limit
+	}
+}
+
+// WithLogRedactedHeaders adds headers, such as a token set with AddHeaders or by an interceptor, to
+// the request headers whose values are redacted in the logs of the logger set with WithLogger. Only
+// the Authorization, pinata_api_key and pinata_secret_api_key headers are redacted by default.
+// Header names are case-insensitive.
+func WithLogRedactedHeaders(names ...string) ClientOption {
+	return func(c *Client) {
+		if c.logRedactedHeaders == nil {
+			c.logRedactedHeaders = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			c.logRedactedHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// redactedHeaders are the request headers carrying credentials, whose values are never logged.
+var redactedHeaders = map[string]bool{
+	"Authorization":         true,
+	"Pinata_api_key":        true,
+	"Pinata_secret_api_key": true,
+}
+
+// logAttempt logs an attempt of an API request sent by requestBuilder.Send with the logger set
+// with WithLogger. Once the response, or the error, of req is known, the returned function logs
+// the method, path, status, duration and attempt number of the request, then its headers, with
+// credentials redacted, and the start of its body, all at the debug level. The body
+// is captured as the transport reads it, so that streamed uploads are neither buffered nor read
+// twice. Without a logger, req is returned as is and nothing is logged.
+func (rb *requestBuilder) logAttempt(req *http.Request) (*http.Request, func(*http.Response, error)) {
+	logger := rb.client.logger
+	if logger == nil || !logger.Enabled(req.Context(), slog.LevelDebug) {
+		return req, func(*http.Response, error) {}
+	}
+
+	rb.attempts++
+	attempt := rb.attempts
+	limit := rb.client.logBodyLimit
+	if limit == 0 {
+		limit = DefaultLogBodyLimit
+	}
+	var body *bodyCapture
+	if limit > 0 && hasBody(req) {
+		body = &bodyCapture{ReadCloser: req.Body, limit: limit}
+		req = req.WithContext(req.Context())
+		req.Body = body
+	}
+
+	start := time.Now()
+	return req, func(resp *http.Response, err error) {
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("attempt", attempt),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("sdk_version", Version()),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		} else {
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		}
+		ctx := context.WithoutCancel(req.Context())
+		logger.LogAttrs(ctx, slog.LevelDebug, "api request", attrs...)
+
+		attrs = []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("attempt", attempt),
+			headerAttrs(req.Header, rb.client.logRedactedHeaders),
+		}
+		if body != nil {
+			data, size := body.captured()
+			attrs = append(attrs, slog.String("body", string(data)), slog.Int64("body_size", size))
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "api request details", attrs...)
+	}
+}
+
+// headerAttrs returns the headers of a request as a group of attributes, sorted by name, with the
+// values of redactedHeaders and of the extra headers, keyed by canonical name, replaced.
+func headerAttrs(header http.Header, extra map[string]bool) slog.Attr {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if key := http.CanonicalHeaderKey(name); redactedHeaders[key] || extra[key] {
+			value = "[REDACTED]"
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+// bodyCapture keeps the first limit bytes read from a request body, and counts all of them. The
+// transport may still be reading the body when the response arrives, hence the mutex.
+type bodyCapture struct {
+	io.ReadCloser
+	limit int
+	mu    sync.Mutex
+	data  []byte
+	size  int64
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - len(b.data); room > 0 {
+		b.data = append(b.data, p[:min(n, room)]...)
+	}
+	b.size += int64(n)
+	return n, err
+}
+
+// captured returns the bytes of the body kept so far and the number of bytes read.
+func (b *bodyCapture) captured() ([]byte, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data, b.size
+}
@@ -0,0 +1,180 @@
+package pinata
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// logRecorder is a slog handler recording the attributes of every record at or above its level.
+type logRecorder struct {
+	level   slog.Level
+	mu      sync.Mutex
+	records []map[string]interface{}
+}
+
+func (h *logRecorder) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *logRecorder) Handle(_ context.Context, r slog.Record) error {
+	record := map[string]interface{}{"level": r.Level, "msg": r.Message}
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Value.Kind() == slog.KindGroup {
+			group := make(map[string]string)
+			for _, ga := range a.Value.Group() {
+				group[ga.Key] = ga.Value.String()
+			}
+			record[a.Key] = group
+			return true
+		}
+		record[a.Key] = a.Value.Any()
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *logRecorder) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *logRecorder) WithGroup(string) slog.Handler      { return h }
+
+// messages returns the records logged with msg.
+func (h *logRecorder) messages(msg string) []map[string]interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var records []map[string]interface{}
+	for _, record := range h.records {
+		if record["msg"] == msg {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func TestRequestLog(t *testing.T) {
+	t.Run("logs every attempt", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`, http.StatusServiceUnavailable)
+		recorder := &logRecorder{level: slog.LevelDebug}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(recorder)), WithRetryPolicy(fastRetries))
+		client.baseURL = server.URL
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+
+		requests := recorder.messages("api request")
+		require.Len(t, requests, 2)
+		for i, status := range []int64{503, 200} {
+			require.Equal(t, "GET", requests[i]["method"])
+			require.Equal(t, "/data/testAuthentication", requests[i]["path"])
+			require.EqualValues(t, i+1, requests[i]["attempt"])
+			require.Equal(t, status, requests[i]["status"])
+			require.Contains(t, requests[i], "duration")
+			require.Equal(t, slog.LevelDebug, requests[i]["level"])
+		}
+		require.Len(t, recorder.messages("api request details"), 2)
+	})
+
+	t.Run("nothing is logged above the debug level", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`)
+		recorder := &logRecorder{level: slog.LevelInfo}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(recorder)))
+		client.baseURL = server.URL
+
+		_, err := client.TestAuthentication()
+		require.NoError(t, err)
+		require.Empty(t, recorder.records)
+	})
+
+	t.Run("logs transport errors", func(t *testing.T) {
+		recorder := &logRecorder{level: slog.LevelDebug}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(recorder)))
+		client.baseURL = "http://127.0.0.1:1"
+
+		_, err := client.TestAuthentication()
+		require.Error(t, err)
+		requests := recorder.messages("api request")
+		require.Len(t, requests, 1)
+		require.NotContains(t, requests[0], "status")
+		require.Contains(t, requests[0]["error"], "connection refused")
+	})
+
+	t.Run("redacts credentials", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`)
+		for _, auth := range []*Auth{NewAuthWithJWT("secret_jwt"), NewAuth("key_id", "secret_key", "")} {
+			recorder := &logRecorder{level: slog.LevelDebug}
+			client := New(auth, WithLogger(slog.New(recorder)))
+			client.baseURL = server.URL
+
+			_, err := client.TestAuthentication()
+			require.NoError(t, err)
+
+			details := recorder.messages("api request details")
+			require.Len(t, details, 1)
+			headers := details[0]["headers"].(map[string]string)
+			require.Contains(t, headers, "User-Agent")
+			for name, value := range headers {
+				require.NotContains(t, value, "secret", name)
+			}
+			if auth.jwt != "" {
+				require.Equal(t, "[REDACTED]", headers["Authorization"])
+			} else {
+				require.Equal(t, "[REDACTED]", headers["Pinata_secret_api_key"])
+				require.Equal(t, "[REDACTED]", headers["Pinata_api_key"])
+			}
+		}
+	})
+
+	t.Run("redacts extra headers", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`)
+		recorder := &logRecorder{level: slog.LevelDebug}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(recorder)), WithLogRedactedHeaders("x-tenant-token"))
+		client.baseURL = server.URL
+
+		require.NoError(t, client.NewRequest(http.MethodGet, "/data/testAuthentication").
+			AddHeaders("X-Tenant-Token", "secret_token").
+			AddHeaders("X-Request-Tag", "audit").
+			Send(nil))
+
+		details := recorder.messages("api request details")
+		require.Len(t, details, 1)
+		headers := details[0]["headers"].(map[string]string)
+		require.Equal(t, "[REDACTED]", headers["X-Tenant-Token"])
+		require.Equal(t, "[REDACTED]", headers["Authorization"])
+		require.Equal(t, "audit", headers["X-Request-Tag"])
+	})
+
+	t.Run("caps logged bodies", func(t *testing.T) {
+		server := newFlakyServer(t, `{"IpfsHash":"`+cidV0+`"}`)
+		recorder := &logRecorder{level: slog.LevelDebug}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(recorder)), WithLogBodyLimit(16))
+		client.baseURL = server.URL
+
+		content := strings.Repeat("x", 1000)
+		_, err := client.PinJSON(map[string]string{"content": content}, nil)
+		require.NoError(t, err)
+
+		details := recorder.messages("api request details")
+		require.Len(t, details, 1)
+		require.Len(t, details[0]["body"], 16)
+		require.Greater(t, details[0]["body_size"], int64(1000))
+		// the server still received the whole body
+		require.Contains(t, server.bodies[0], content)
+
+		// a negative limit logs no body
+		recorder = &logRecorder{level: slog.LevelDebug}
+		client = New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(recorder)), WithLogBodyLimit(-1))
+		client.baseURL = server.URL
+		_, err = client.PinJSON(map[string]string{"content": content}, nil)
+		require.NoError(t, err)
+		details = recorder.messages("api request details")
+		require.Len(t, details, 1)
+		require.NotContains(t, details[0], "body")
+	})
+}
@@ -0,0 +1,160 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityRetryPolicy)
+}
+
+// Defaults of RetryPolicy.
+const (
+	DefaultRetryInitialBackoff = 500 * time.Millisecond
+	DefaultRetryMaxBackoff     = 30 * time.Second
+	DefaultRetryJitter         = 0.2
+)
+
+// RetryPolicy configures the retries of API requests. API requests are not retried by default.
+// MaxAttempts is the number of attempts of a request, including the first one; a value of 1 or
+// less disables retries.
+// InitialBackoff is the pause before the first retry; it doubles on every retry
+// (DefaultRetryInitialBackoff when zero). A longer Retry-After sent by the API is honored.
+// MaxBackoff caps every pause (DefaultRetryMaxBackoff when zero).
+// Jitter spreads every pause randomly by up to this fraction of it, in both directions, so that
+// clients throttled together do not retry together (DefaultRetryJitter when zero); a negative value
+// disables it.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Jitter         float64
+}
+
+// WithRetryPolicy sets the retry policy of API requests. A request is retried when it failed with a
+// retryable connection error or timeout, or when the API answered 429, 408, 425 or a 5xx status
+// other than 501 and 505, as classified by IsRetryable; other 4xx statuses fail immediately. Error
+// reasons known to be transient, such as "CURRENTLY_PINNING", are retried whatever their status
+// and may ask for a longer pause; see DefaultRetryReasons and WithRetryReasons.
+//
+// Only idempotent requests are retried: GET, HEAD, PUT, DELETE and OPTIONS requests, and the pin
+// uploads, whose content addressing makes a repeated upload pin the same CID. Their bodies are
+// rebuilt for every attempt, so files are read again from disk; uploads of streams that can only
+// be read once, such as PinReader without spooling, are not retried. Each retry is reported as a
+// WarningRequestRetry.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// retryablePosts are the POST paths that are safe to send again.
+var retryablePosts = map[string]bool{
+	"/pinning/pinFileToIPFS": true,
+	"/pinning/pinJSONToIPFS": true,
+}
+
+// retryPolicy returns the retry policy of c with the defaults applied.
+func (c *Client) retryPolicy() RetryPolicy {
+	policy := c.retry
+	policy.MaxAttempts = max(policy.MaxAttempts, 1)
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	switch {
+	case policy.Jitter < 0:
+		policy.Jitter = 0
+	case policy.Jitter == 0:
+		policy.Jitter = DefaultRetryJitter
+	}
+	return policy
+}
+
+// retryable reports whether the attempt of req that ended with resp or err may be retried.
+func (rb *requestBuilder) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+	default:
+		if req.Method != http.MethodPost || !retryablePosts[rb.path] {
+			return false
+		}
+	}
+	if hasBody(req) && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return rb.client.isRetryable(err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false
+	}
+	return rb.client.isRetryable(rb.peekAPIError(resp))
+}
+
+// nextAttempt waits before retrying req, whose attempt number attempt ended with resp or err, and
+// returns the request of the next attempt, with a fresh body and the current credentials.
+func (rb *requestBuilder) nextAttempt(req *http.Request, resp *http.Response, err error, attempt int, policy RetryPolicy) (*http.Request, error) {
+	delay := backoff(policy.InitialBackoff, policy.MaxBackoff, attempt)
+	delay += time.Duration(policy.Jitter * (2*rand.Float64() - 1) * float64(delay))
+
+	detail := map[string]interface{}{"attempt": attempt, "path": rb.path}
+	if err != nil {
+		detail["error"] = err.Error()
+	} else {
+		detail["status"] = resp.StatusCode
+		delay = max(delay, parseRetryAfter(resp.Header.Get("Retry-After")))
+		var apiErr *APIError
+		if errors.As(rb.peekAPIError(resp), &apiErr) {
+			if reason, ok := rb.client.reasonPolicy(apiErr); ok {
+				detail["reason"] = apiErr.Reason
+				delay = max(delay, reason.Delay)
+			}
+		}
+		resp.Body.Close()
+	}
+	delay = min(delay, policy.MaxBackoff)
+	detail["delay"] = delay.String()
+	rb.warn(Warning{Code: WarningRequestRetry, Message: "request failed, retrying", Detail: detail})
+
+	ctx := req.Context()
+	if err := sleep(ctx, delay); err != nil {
+		return nil, err
+	}
+	if err := rb.client.apiLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return rb.replay(req, rb.client.currentAuth())
+}
+
+// backoff returns the pause before the retry following attempt failures in a row: initial,
+// doubled after each further failure, up to limit.
+func backoff(initial, limit time.Duration, attempt int) time.Duration {
+	delay := initial << (attempt - 1)
+	if delay <= 0 || delay > limit {
+		delay = limit
+	}
+	return delay
+}
+
+// sleep pauses for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return transportError(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer is a mock API server that answers the first len(statuses) requests with statuses
+// and errorBody, and later requests with 200 OK and body. It records the body of every request.
+type flakyServer struct {
+	*httptest.Server
+	statuses  []int
+	body      string
+	errorBody string
+	requests  atomic.Int32
+	bodies    []string
+}
+
+func newFlakyServer(t *testing.T, body string, statuses ...int) *flakyServer {
+	s := &flakyServer{statuses: statuses, body: body, errorBody: `{"error":"try again"}`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		s.bodies = append(s.bodies, string(data))
+		n := int(s.requests.Add(1))
+		if n <= len(s.statuses) {
+			w.WriteHeader(s.statuses[n-1])
+			w.Write([]byte(s.errorBody))
+			return
+		}
+		w.Write([]byte(s.body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// fastRetries is a retry policy without pauses to speak of.
+var fastRetries = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Jitter: -1}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("retries 503 and 429 then succeeds", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		var warnings []Warning
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(fastRetries), WithWarningHandler(func(w Warning) {
+			warnings = append(warnings, w)
+		}))
+		client.baseURL = server.URL
+
+		res, err := client.TestAuthentication()
+		require.NoError(t, err)
+		require.Equal(t, "ok", res.Message)
+		require.EqualValues(t, 3, server.requests.Load())
+		require.Len(t, warnings, 2)
+		require.Equal(t, WarningRequestRetry, warnings[0].Code)
+		require.Equal(t, 503, warnings[0].Detail["status"])
+		require.Equal(t, 429, warnings[1].Detail["status"])
+	})
+
+	t.Run("replays multipart uploads", func(t *testing.T) {
+		server := newFlakyServer(t, `{"IpfsHash":"`+cidV0+`"}`, http.StatusBadGateway, http.StatusServiceUnavailable)
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(fastRetries))
+		client.baseURL = server.URL
+
+		path := filepath.Join(writeTree(t, "a.txt"), "a.txt")
+		res, err := client.PinFile(path, nil)
+		require.NoError(t, err)
+		require.Equal(t, cidV0, res.IpfsHash)
+		require.EqualValues(t, 3, server.requests.Load())
+		for _, body := range server.bodies {
+			require.Contains(t, body, "content of a.txt")
+			require.Equal(t, server.bodies[0], body)
+		}
+	})
+
+	t.Run("replays json uploads", func(t *testing.T) {
+		server := newFlakyServer(t, `{"IpfsHash":"`+cidV0+`"}`, http.StatusInternalServerError)
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(fastRetries))
+		client.baseURL = server.URL
+
+		_, err := client.PinJSON(map[string]string{"a": "b"}, nil)
+		require.NoError(t, err)
+		require.Len(t, server.bodies, 2)
+		require.Equal(t, server.bodies[0], server.bodies[1])
+		require.Contains(t, server.bodies[1], `"a":"b"`)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		server := newFlakyServer(t, `{"message":"ok"}`, 503, 503, 503, 503)
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(fastRetries))
+		client.baseURL = server.URL
+
+		_, err := client.TestAuthentication()
+		require.Error(t, err)
+		require.True(t, IsRetryable(err))
+		require.EqualValues(t, 3, server.requests.Load())
+	})
+
+	t.Run("does not retry", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			policy RetryPolicy
+			status int
+			call   func(c *Client) error
+		}{
+			{"4xx", fastRetries, http.StatusBadRequest, func(c *Client) error {
+				_, err := c.TestAuthentication()
+				return err
+			}},
+			{"without a policy", RetryPolicy{}, http.StatusServiceUnavailable, func(c *Client) error {
+				_, err := c.TestAuthentication()
+				return err
+			}},
+			{"non-idempotent requests", fastRetries, http.StatusServiceUnavailable, func(c *Client) error {
+				_, err := c.Groups().Create("group")
+				return err
+			}},
+			{"streams that cannot be replayed", fastRetries, http.StatusServiceUnavailable, func(c *Client) error {
+				_, err := c.PinReader(context.Background(), strings.NewReader("stream"), "stream", nil, nil)
+				return err
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := newFlakyServer(t, `{}`, tt.status)
+				client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(tt.policy))
+				client.baseURL = server.URL
+
+				var apiErr *APIError
+				require.ErrorAs(t, tt.call(client), &apiErr)
+				require.Equal(t, tt.status, apiErr.StatusCode)
+				require.EqualValues(t, 1, server.requests.Load())
+			})
+		}
+	})
+
+	t.Run("honors Retry-After and the context", func(t *testing.T) {
+		var requests atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, MaxBackoff: 2 * time.Hour}))
+		client.baseURL = mockServer.URL
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := client.TestAuthenticationWithContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.EqualValues(t, 1, requests.Load())
+	})
+}
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	policy := New(&Auth{jwt: "valid_jwt_token"}).retryPolicy()
+	require.Equal(t, RetryPolicy{
+		MaxAttempts:    1,
+		InitialBackoff: DefaultRetryInitialBackoff,
+		MaxBackoff:     DefaultRetryMaxBackoff,
+		Jitter:         DefaultRetryJitter,
+	}, policy)
+
+	policy = New(&Auth{jwt: "valid_jwt_token"}, WithRetryPolicy(RetryPolicy{MaxAttempts: 4, Jitter: -1})).retryPolicy()
+	require.Equal(t, 4, policy.MaxAttempts)
+	require.Zero(t, policy.Jitter)
+}
@@ -0,0 +1,32 @@
+package pinata
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zde37/pinata-go-sdk/pinata/internal/seam"
+)
+
+func init() {
+	seam.WithNameClock = func(now func() time.Time) interface{} {
+		return withNameClock(now)
+	}
+}
+
+// withNameClock makes c take the time in the names it generates from now. It is exposed to tests
+// outside the package by pinatatest.WithNameClock.
+func withNameClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.nameClock = now
+	}
+}
+
+// defaultName returns the name given to an upload without one: prefix followed by the current
+// time of the name clock.
+func (c *Client) defaultName(prefix string) string {
+	now := time.Now()
+	if c.nameClock != nil {
+		now = c.nameClock()
+	}
+	return fmt.Sprintf("%s_%s", prefix, now.String())
+}
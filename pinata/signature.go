@@ -1,39 +1,55 @@
 package pinata
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
 
-// cidSignature represents the response from the Pinata API for a CID signature.
-type cidSignature struct {
-	Data sigData `json:"data,omitempty"`
+func init() {
+	registerCapability(CapabilitySignatures)
 }
 
-// sigData represents the data for a CID signature, including the CID and the signature.
-type sigData struct {
+// CidSignature represents the response from the Pinata API for a CID signature.
+type CidSignature struct {
+	Data CidSignatureData `json:"data,omitempty"`
+}
+
+// CidSignatureData represents the data for a CID signature, including the CID and the signature.
+type CidSignatureData struct {
 	Cid       string `json:"cid,omitempty"`
 	Signature string `json:"signature,omitempty"`
 }
 
 // AddCidSignature adds a signature for the given CID. If either the CID or the
 // signature is empty, an error is returned.
-func (c *Client) AddCidSignature(cid, signature string) (*cidSignature, error) {
+func (c *Client) AddCidSignature(cid, signature string) (*CidSignature, error) {
+	return c.AddCidSignatureWithContext(context.Background(), cid, signature)
+}
+
+// AddCidSignatureWithContext is like AddCidSignature but uses ctx for the requests.
+func (c *Client) AddCidSignatureWithContext(ctx context.Context, cid, signature string) (*CidSignature, error) {
 	if cid == "" || signature == "" {
 		return nil, fmt.Errorf("cid and signature is required")
 	}
 
+	unlock, err := c.lockCids(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	payload := make(map[string]string)
 	payload["signature"] = signature
 
-	req, err := c.NewRequest(http.MethodPost, "/v3/ipfs/signature/{cid}").
+	req, err := c.NewRequest(http.MethodPost, "/v3/ipfs/signature/{cid}").WithContext(ctx).
 		AddPathParam("cid", cid).
 		SetJSONBody(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set JSON body: %w", err)
 	}
 
-	var response cidSignature
+	var response CidSignature
 	err = req.Send(&response)
 	if err != nil {
 		return nil, err
@@ -45,13 +61,18 @@ func (c *Client) AddCidSignature(cid, signature string) (*cidSignature, error) {
 // If the CID is empty, an error is returned.
 // The CidSignature struct is returned, which contains the CID and its signature.
 // If an error occurs during the API request, the error is returned.
-func (c *Client) GetCidSignature(cid string) (*cidSignature, error) {
+func (c *Client) GetCidSignature(cid string) (*CidSignature, error) {
+	return c.GetCidSignatureWithContext(context.Background(), cid)
+}
+
+// GetCidSignatureWithContext is like GetCidSignature but uses ctx for the requests.
+func (c *Client) GetCidSignatureWithContext(ctx context.Context, cid string) (*CidSignature, error) {
 	if cid == "" {
 		return nil, fmt.Errorf("cid is required")
 	}
 
-	var response cidSignature
-	err := c.NewRequest(http.MethodGet, "/v3/ipfs/signature/{cid}").
+	var response CidSignature
+	err := c.NewRequest(http.MethodGet, "/v3/ipfs/signature/{cid}").WithContext(ctx).
 		AddPathParam("cid", cid).
 		Send(&response)
 
@@ -65,11 +86,22 @@ func (c *Client) GetCidSignature(cid string) (*cidSignature, error) {
 // If the CID is empty, an error is returned.
 // If an error occurs during the API request, the error is returned.
 func (c *Client) RemoveCidSignature(cid string) error {
+	return c.RemoveCidSignatureWithContext(context.Background(), cid)
+}
+
+// RemoveCidSignatureWithContext is like RemoveCidSignature but uses ctx for the requests.
+func (c *Client) RemoveCidSignatureWithContext(ctx context.Context, cid string) error {
 	if cid == "" {
 		return fmt.Errorf("cid is required")
 	}
 
-	err := c.NewRequest(http.MethodDelete, "/v3/ipfs/signature/{cid}").
+	unlock, err := c.lockCids(ctx, cid)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = c.NewRequest(http.MethodDelete, "/v3/ipfs/signature/{cid}").WithContext(ctx).
 		AddPathParam("cid", cid).
 		Send(nil)
 
@@ -77,4 +109,4 @@ func (c *Client) RemoveCidSignature(cid string) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
 )
 
 func TestAddCidSignature(t *testing.T) {
@@ -14,7 +16,7 @@ func TestAddCidSignature(t *testing.T) {
 		auth := &Auth{jwt: "valid_jwt_token"}
 		client := New(auth)
 		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			require.Equal(t, "/v3/ipfs/signature/test_cid", r.URL.Path)
+			require.Equal(t, "/v3/ipfs/signature/"+fixtures.Cid, r.URL.Path)
 			require.Equal(t, http.MethodPost, r.Method)
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
@@ -25,17 +27,17 @@ func TestAddCidSignature(t *testing.T) {
 			require.Equal(t, "test_signature", payload["signature"])
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"data":{"cid":"test_cid","signature":"test_signature"}}`))
+			w.Write(fixture(t, "POST /v3/ipfs/signature/{cid}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
-		cidSignature, err := client.AddCidSignature("test_cid", "test_signature")
+		cidSignature, err := client.AddCidSignature(fixtures.Cid, "test_signature")
 
 		require.NoError(t, err)
 		require.NotNil(t, cidSignature)
-		require.Equal(t, "test_cid", cidSignature.Data.Cid)
-		require.Equal(t, "test_signature", cidSignature.Data.Signature)
+		require.Equal(t, fixtures.Cid, cidSignature.Data.Cid)
+		require.True(t, strings.HasPrefix(cidSignature.Data.Signature, "0x"))
 	})
 
 	t.Run("empty cid", func(t *testing.T) {
@@ -105,7 +107,7 @@ func TestGetCidSignature(t *testing.T) {
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"data":{"cid":"test_cid","signature":"test_signature"}}`))
+			w.Write(fixture(t, "GET /v3/ipfs/signature/{cid}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -114,8 +116,8 @@ func TestGetCidSignature(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, cidSignature)
-		require.Equal(t, "test_cid", cidSignature.Data.Cid)
-		require.Equal(t, "test_signature", cidSignature.Data.Signature)
+		require.Equal(t, fixtures.Cid, cidSignature.Data.Cid)
+		require.True(t, strings.HasPrefix(cidSignature.Data.Signature, "0x"))
 	})
 
 	t.Run("empty cid", func(t *testing.T) {
@@ -190,6 +192,7 @@ func TestRemoveCidSignature(t *testing.T) {
 			require.Equal(t, http.MethodDelete, r.Method)
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 			w.WriteHeader(http.StatusOK)
+			w.Write(fixture(t, "DELETE /v3/ipfs/signature/{cid}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -0,0 +1,158 @@
+package pinata
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// signerFunc adapts a function to the RequestSigner interface.
+type signerFunc func(req *http.Request) error
+
+func (f signerFunc) Sign(req *http.Request) error { return f(req) }
+
+func TestHMACSigner(t *testing.T) {
+	secret := []byte("audit-secret")
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newSigner := func() *HMACSigner {
+		signer := NewHMACSigner(secret)
+		signer.now = func() time.Time { return date }
+		return signer
+	}
+	expectedSignature := func(lines string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(lines))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("known request", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPut, "https://api.pinata.cloud/v3/ipfs/swap/test_cid?domain=a.b", strings.NewReader(`{"swapCid":"new_cid"}`))
+		require.NoError(t, err)
+
+		require.NoError(t, newSigner().Sign(req))
+
+		digest := sha256.Sum256([]byte(`{"swapCid":"new_cid"}`))
+		require.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", req.Header.Get("Date"))
+		require.Equal(t, expectedSignature("PUT\n/v3/ipfs/swap/test_cid?domain=a.b\nWed, 01 May 2024 12:00:00 GMT\n"+hex.EncodeToString(digest[:])), req.Header.Get(RequestSignatureHeader))
+		require.Equal(t, "3eee98012d8bae86558166fb28fde40b22b2e519ebe03a8ed0f24c388546da65", req.Header.Get(RequestSignatureHeader))
+
+		// the body is left unread for sending
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"swapCid":"new_cid"}`, string(body))
+	})
+
+	t.Run("bodiless request keeps its date", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "https://api.pinata.cloud/data/pinList", nil)
+		require.NoError(t, err)
+		req.Header.Set("Date", "Tue, 30 Apr 2024 08:00:00 GMT")
+
+		require.NoError(t, newSigner().Sign(req))
+
+		require.Equal(t, "Tue, 30 Apr 2024 08:00:00 GMT", req.Header.Get("Date"))
+		require.Equal(t, expectedSignature("GET\n/data/pinList\nTue, 30 Apr 2024 08:00:00 GMT\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"), req.Header.Get(RequestSignatureHeader))
+	})
+}
+
+func TestWithRequestSigner(t *testing.T) {
+	secret := []byte("audit-secret")
+
+	// verifySignature recomputes the HMACSigner signature of the request received by the server.
+	verifySignature := func(t *testing.T, r *http.Request) string {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		digest := sha256.Sum256(body)
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n" + r.Header.Get("Date") + "\n" + hex.EncodeToString(digest[:])))
+		require.Equal(t, hex.EncodeToString(mac.Sum(nil)), r.Header.Get(RequestSignatureHeader))
+		require.NotEmpty(t, r.Header.Get("Authorization"))
+		return string(body)
+	}
+
+	t.Run("mutations are signed", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, `{"groupId":"g1"}`, verifySignature(t, r))
+			w.Write([]byte(`{}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("test_token"), WithRequestSigner(NewHMACSigner(secret)))
+		client.baseURL = mockServer.URL
+
+		req, err := client.NewRequest(http.MethodPut, "/groups/{groupId}").
+			AddPathParam("groupId", "g1").
+			SetJSONBody(map[string]string{"groupId": "g1"})
+		require.NoError(t, err)
+		require.NoError(t, req.Send(nil))
+	})
+
+	t.Run("one-shot bodies are buffered", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "streamed body", verifySignature(t, r))
+			w.Write([]byte(`{}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(NewAuthWithJWT("test_token"), WithRequestSigner(NewHMACSigner(secret)))
+		client.baseURL = mockServer.URL
+
+		body := io.MultiReader(strings.NewReader("streamed "), strings.NewReader("body"))
+		err := client.NewRequest(http.MethodPost, "/pinning/pinJSONToIPFS").SetBody(body, "text/plain").Send(nil)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("signing failure", func(t *testing.T) {
+		var requests int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+		}))
+		defer mockServer.Close()
+
+		signErr := errors.New("signing key unavailable")
+		client := New(NewAuthWithJWT("test_token"), WithRequestSigner(signerFunc(func(*http.Request) error { return signErr })))
+		client.baseURL = mockServer.URL
+
+		err := client.NewRequest(http.MethodDelete, "/pinning/unpin/{cid}").AddPathParam("cid", "test_cid").Send(nil)
+
+		require.ErrorIs(t, err, signErr)
+		require.Zero(t, atomic.LoadInt32(&requests))
+	})
+
+	t.Run("signing failure closes the body", func(t *testing.T) {
+		signErr := errors.New("signing key unavailable")
+		client := New(NewAuthWithJWT("test_token"), WithRequestSigner(signerFunc(func(*http.Request) error { return signErr })))
+
+		// the factory streams through a pipe, as upload bodies do; its writer only returns once the
+		// body is read or closed
+		done := make(chan error, 1)
+		factory := func() (io.ReadCloser, error) {
+			pr, pw := io.Pipe()
+			go func() {
+				_, err := pw.Write([]byte("streamed body"))
+				done <- err
+			}()
+			return pr, nil
+		}
+		err := client.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").SetBodyFactory(factory, "text/plain").Send(nil)
+		require.ErrorIs(t, err, signErr)
+
+		select {
+		case err := <-done:
+			require.ErrorIs(t, err, io.ErrClosedPipe)
+		case <-time.After(time.Second):
+			t.Fatal("the body writer is still blocked: the body was not closed")
+		}
+	})
+}
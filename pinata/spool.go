@@ -0,0 +1,198 @@
+package pinata
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+func init() {
+	registerCapability(CapabilityReaderSpool)
+}
+
+// DefaultSpoolMemoryLimit is the number of bytes PinReader buffers in memory before spilling to a
+// temporary file, when PinReaderOptions.SpoolMemoryLimit is zero.
+const DefaultSpoolMemoryLimit int64 = 32 << 20
+
+// PinReaderOptions configures how PinReader reads its stream.
+// Spool makes PinReader read the whole stream before uploading it: up to SpoolMemoryLimit bytes
+// are kept in memory, and longer streams are spilled to a temporary file in SpoolDir, removed once
+// the call returns. A spooled upload has a known size, so it can carry a Content-Length (see
+// WithMultipartContentLength), is re-sent when the request is retried and reports a total to
+// Progress. Without spooling, the stream is sent as it is read, once, with chunked transfer
+// encoding.
+// SpoolMemoryLimit defaults to DefaultSpoolMemoryLimit; a negative value spills every stream.
+// SpoolDir defaults to os.TempDir().
+// Progress, if set, is called as content is sent. It starts over when a spooled upload is re-sent.
+type PinReaderOptions struct {
+	Spool            bool
+	SpoolMemoryLimit int64
+	SpoolDir         string
+	Progress         func(UploadProgress)
+}
+
+// UploadProgress reports the progress of an upload.
+// Sent is the number of content bytes sent so far.
+// Total is the size of the content, or -1 when it is unknown.
+type UploadProgress struct {
+	Sent  int64
+	Total int64
+}
+
+// PinReader uploads the content read from r to IPFS under the file name name and pins it to the
+// Pinata network. It suits streams of unknown length, such as the standard input of a tool piping
+// a database dump; see PinReaderOptions for spooling them so that they are sized and retryable.
+// readerOptions may be nil.
+func (c *Client) PinReader(ctx context.Context, r io.Reader, name string, options *PinOptions, readerOptions *PinReaderOptions) (*PinResponse, error) {
+	if r == nil {
+		return nil, fmt.Errorf("reader is required")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if readerOptions == nil {
+		readerOptions = &PinReaderOptions{}
+	}
+
+	options, err := c.normalizePinOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var content func() io.Reader
+	size := int64(-1)
+	if readerOptions.Spool {
+		s, err := newSpool(r, readerOptions.SpoolMemoryLimit, readerOptions.SpoolDir)
+		if err != nil {
+			return nil, err
+		}
+		defer s.Close()
+		content, size = s.reader, s.size
+	} else {
+		content = func() io.Reader { return r }
+	}
+
+	digest := integrityDigest(options)
+	body, contentType, length, err := c.pinMultipartBody(func(form *multipartForm) error {
+		if err := writePinFileFields(form, options); err != nil {
+			return err
+		}
+		form.digest = digest
+		var reader io.Reader
+		if form.size == nil {
+			reader = &progressReader{r: content(), total: size, report: readerOptions.Progress}
+		}
+		return writeFormReader(form, reader, size, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	op := c.startOp("PinReader", name)
+	defer c.endOp(op)
+
+	rb := c.NewRequest(http.MethodPost, "/pinning/pinFileToIPFS").WithContext(ctx).withActiveOp(op).withPayloadSize(size)
+	if readerOptions.Spool {
+		rb.SetBodyFactory(body, contentType).SetContentLength(length)
+	} else {
+		// the stream can only be read once, so the body is not retry-safe
+		reader, err := body()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		rb.SetBody(reader, contentType)
+	}
+
+	var response PinResponse
+	if err := rb.Send(&response); err != nil {
+		return nil, err
+	}
+	if digest != nil {
+		if err := c.verifyIntegrity(ctx, response.IpfsHash, digest); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.checkCidVersion(&response, options); err != nil {
+		return nil, err
+	}
+	c.warnDuplicate(&response, options)
+	return &response, nil
+}
+
+// spool holds the content of a stream, in memory or in a temporary file.
+type spool struct {
+	mem  []byte
+	file *os.File
+	size int64
+}
+
+// newSpool reads r up to EOF, keeping up to limit bytes in memory and spilling longer content to a
+// temporary file in dir.
+func newSpool(r io.Reader, limit int64, dir string) (*spool, error) {
+	if limit == 0 {
+		limit = DefaultSpoolMemoryLimit
+	}
+
+	var buf bytes.Buffer
+	if limit > 0 {
+		n, err := io.CopyN(&buf, r, limit+1)
+		if err == io.EOF {
+			return &spool{mem: buf.Bytes(), size: n}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read content: %w", err)
+		}
+	}
+
+	file, err := os.CreateTemp(dir, "pinata-spool-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool file: %w", err)
+	}
+	s := &spool{file: file}
+	size, err := io.Copy(file, io.MultiReader(&buf, r))
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to spool content: %w", err)
+	}
+	s.size = size
+	return s, nil
+}
+
+// reader returns a reader of the whole content.
+func (s *spool) reader() io.Reader {
+	if s.file == nil {
+		return bytes.NewReader(s.mem)
+	}
+	return io.NewSectionReader(s.file, 0, s.size)
+}
+
+// Close removes the temporary file of s, if any.
+func (s *spool) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
+
+// progressReader reports the bytes read from r.
+type progressReader struct {
+	r      io.Reader
+	total  int64
+	sent   int64
+	report func(UploadProgress)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+	if n > 0 && p.report != nil {
+		p.report(UploadProgress{Sent: p.sent, Total: p.total})
+	}
+	return n, err
+}
@@ -0,0 +1,141 @@
+package pinata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
+)
+
+// spoolServer is a mock pinning server that records the uploaded content and the entries of a
+// spool directory at the time of each request.
+type spoolServer struct {
+	mu            sync.Mutex
+	dir           string
+	status        []int
+	contents      []string
+	contentLength []int64
+	spoolEntries  []int
+}
+
+func (s *spoolServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, err := os.ReadDir(s.dir)
+		require.NoError(t, err)
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.contents = append(s.contents, string(content))
+		s.contentLength = append(s.contentLength, r.ContentLength)
+		s.spoolEntries = append(s.spoolEntries, len(entries))
+		if len(s.status) > 0 {
+			status := s.status[0]
+			s.status = s.status[1:]
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"try again"}`))
+			return
+		}
+		serveFixture(w, r)
+	}
+}
+
+func TestPinReader(t *testing.T) {
+	content := strings.Repeat("0123456789", 10)
+
+	setup := func(t *testing.T, options ...ClientOption) (*Client, *spoolServer) {
+		recorder := &spoolServer{dir: t.TempDir()}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		t.Cleanup(mockServer.Close)
+		client := New(&Auth{jwt: "valid_jwt_token"}, options...)
+		client.baseURL = mockServer.URL
+		return client, recorder
+	}
+
+	t.Run("spooled in memory", func(t *testing.T) {
+		client, recorder := setup(t, WithMultipartContentLength(true))
+		var last UploadProgress
+		response, err := client.PinReader(context.Background(), io.NopCloser(strings.NewReader(content)), "dump.sql", nil, &PinReaderOptions{
+			Spool:            true,
+			SpoolMemoryLimit: 1024,
+			SpoolDir:         recorder.dir,
+			Progress:         func(p UploadProgress) { last = p },
+		})
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.IpfsHash)
+		require.Equal(t, []string{content}, recorder.contents)
+		require.Positive(t, recorder.contentLength[0])
+		require.Equal(t, []int{0}, recorder.spoolEntries)
+		require.Equal(t, UploadProgress{Sent: 100, Total: 100}, last)
+	})
+
+	t.Run("spilled to disk and re-sent on retry", func(t *testing.T) {
+		refreshed := false
+		client, recorder := setup(t, WithMultipartContentLength(true), WithOnUnauthorized(func(ctx context.Context) (*Auth, error) {
+			refreshed = true
+			return &Auth{jwt: "fresh_jwt_token"}, nil
+		}))
+		recorder.status = []int{http.StatusUnauthorized}
+
+		_, err := client.PinReader(context.Background(), io.NopCloser(strings.NewReader(content)), "dump.sql", nil, &PinReaderOptions{
+			Spool:            true,
+			SpoolMemoryLimit: 10,
+			SpoolDir:         recorder.dir,
+		})
+		require.NoError(t, err)
+		require.True(t, refreshed)
+		require.Equal(t, []string{content, content}, recorder.contents)
+		require.Equal(t, recorder.contentLength[0], recorder.contentLength[1])
+		require.Positive(t, recorder.contentLength[0])
+		require.Equal(t, []int{1, 1}, recorder.spoolEntries)
+
+		entries, err := os.ReadDir(recorder.dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("spool file removed on failure", func(t *testing.T) {
+		client, recorder := setup(t)
+		recorder.status = []int{http.StatusInternalServerError}
+
+		_, err := client.PinReader(context.Background(), strings.NewReader(content), "dump.sql", nil, &PinReaderOptions{
+			Spool:            true,
+			SpoolMemoryLimit: -1,
+			SpoolDir:         recorder.dir,
+		})
+		require.Error(t, err)
+		require.Equal(t, []int{1}, recorder.spoolEntries)
+
+		entries, err := os.ReadDir(recorder.dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("streamed without spooling", func(t *testing.T) {
+		client, recorder := setup(t, WithMultipartContentLength(true))
+		var last UploadProgress
+		_, err := client.PinReader(context.Background(), io.NopCloser(strings.NewReader(content)), "dump.sql", nil, &PinReaderOptions{
+			Progress: func(p UploadProgress) { last = p },
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{content}, recorder.contents)
+		require.Equal(t, int64(-1), recorder.contentLength[0])
+		require.Equal(t, UploadProgress{Sent: 100, Total: -1}, last)
+	})
+
+	t.Run("name is required", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		_, err := client.PinReader(context.Background(), strings.NewReader(content), "", nil, nil)
+		require.ErrorContains(t, err, "name is required")
+	})
+}
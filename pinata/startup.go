@@ -0,0 +1,89 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ErrStartupCheck is returned by NewWithCheck when the credentials could not be verified.
+var ErrStartupCheck = errors.New("startup check failed")
+
+const (
+	// DefaultStartupCheckTimeout bounds each TestAuthentication probe of NewWithCheck.
+	DefaultStartupCheckTimeout = 10 * time.Second
+	// defaultStartupRetryDelay is the mean pause before the probe of NewWithCheck is retried. The
+	// pause is jittered by ±50%, so that instances booted together do not retry together.
+	defaultStartupRetryDelay = 500 * time.Millisecond
+)
+
+// WithStartupCheck sets whether NewWithCheck probes the credentials. The probe is enabled by
+// default; disabling it makes NewWithCheck behave as New, for offline or dry-run environments.
+// The option has no effect on New.
+func WithStartupCheck(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.skipStartupCheck = !enabled
+	}
+}
+
+// NewWithCheck creates a client as New does, then calls TestAuthentication so that bad
+// credentials fail at startup rather than on the first real request. Each probe is bounded by
+// DefaultStartupCheckTimeout, and a probe that failed with a timeout, a transport error or a
+// retryable status is retried once after a short jittered pause. Rejected credentials are not
+// retried.
+//
+// Failures are returned as an error wrapping ErrStartupCheck and the error of the last probe, which
+// tells rejected credentials from an unreachable API. The probe is skipped with
+// WithStartupCheck(false).
+func NewWithCheck(ctx context.Context, auth *Auth, opts ...ClientOption) (*Client, error) {
+	c := New(auth, opts...)
+	if c.skipStartupCheck {
+		return c, nil
+	}
+	if err := c.startupCheck(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// startupCheck probes the credentials of c, retrying once as documented on NewWithCheck.
+func (c *Client) startupCheck(ctx context.Context) error {
+	delay := c.startupRetryDelay
+	if delay <= 0 {
+		delay = defaultStartupRetryDelay
+	}
+
+	var err error
+	for attempt := 1; attempt <= 2; attempt++ {
+		if attempt > 1 {
+			jittered := delay/2 + time.Duration(rand.Int63n(int64(delay)))
+			timer := time.NewTimer(jittered)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%w: %w", ErrStartupCheck, transportError(ctx.Err()))
+			case <-timer.C:
+			}
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, DefaultStartupCheckTimeout)
+		_, err = c.testAuthentication(probeCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("%w: the Pinata API rejected the credentials (%d); check the JWT or the API key and secret: %w",
+				ErrStartupCheck, apiErr.StatusCode, err)
+		}
+		if ctx.Err() != nil || !IsRetryable(err) {
+			break
+		}
+	}
+	return fmt.Errorf("%w: could not verify the credentials with the Pinata API: %w", ErrStartupCheck, err)
+}
@@ -0,0 +1,89 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithCheck(t *testing.T) {
+	fastRetry := func(c *Client) { c.startupRetryDelay = time.Millisecond }
+
+	// authServer answers the probes with the given statuses in turn, then 200.
+	authServer := func(requests *atomic.Int32, statuses ...int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/data/testAuthentication", r.URL.Path)
+			n := int(requests.Add(1))
+			if n <= len(statuses) {
+				w.WriteHeader(statuses[n-1])
+				w.Write([]byte(`{"error":{"reason":"INVALID_CREDENTIALS","details":"token is malformed"}}`))
+				return
+			}
+			w.Write(fixture(t, "GET /data/testAuthentication"))
+		}))
+	}
+	withServer := func(s *httptest.Server) ClientOption {
+		return func(c *Client) { c.baseURL = s.URL }
+	}
+
+	t.Run("valid credentials", func(t *testing.T) {
+		var requests atomic.Int32
+		server := authServer(&requests)
+		defer server.Close()
+
+		client, err := NewWithCheck(context.Background(), NewAuthWithJWT("valid_jwt_token"), withServer(server))
+		require.NoError(t, err)
+		require.NotNil(t, client)
+		require.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("bad jwt", func(t *testing.T) {
+		var requests atomic.Int32
+		server := authServer(&requests, http.StatusUnauthorized)
+		defer server.Close()
+
+		client, err := NewWithCheck(context.Background(), NewAuthWithJWT("bad_jwt"), withServer(server), fastRetry)
+		require.Nil(t, client)
+		require.ErrorIs(t, err, ErrStartupCheck)
+		require.ErrorContains(t, err, "rejected the credentials (401)")
+		require.ErrorContains(t, err, "token is malformed")
+		require.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("retried once", func(t *testing.T) {
+		var requests atomic.Int32
+		server := authServer(&requests, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		_, err := NewWithCheck(context.Background(), NewAuthWithJWT("valid_jwt_token"), withServer(server), fastRetry)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, requests.Load())
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		var requests atomic.Int32
+		server := authServer(&requests, http.StatusBadGateway, http.StatusBadGateway)
+		defer server.Close()
+
+		_, err := NewWithCheck(context.Background(), NewAuthWithJWT("valid_jwt_token"), withServer(server), fastRetry)
+		require.ErrorIs(t, err, ErrStartupCheck)
+		require.ErrorContains(t, err, "could not verify the credentials")
+		require.EqualValues(t, 2, requests.Load())
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		var requests atomic.Int32
+		server := authServer(&requests, http.StatusUnauthorized)
+		defer server.Close()
+
+		client, err := NewWithCheck(context.Background(), NewAuthWithJWT("bad_jwt"), withServer(server), WithStartupCheck(false))
+		require.NoError(t, err)
+		require.NotNil(t, client)
+		require.Zero(t, requests.Load())
+	})
+}
@@ -0,0 +1,200 @@
+package pinata
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+)
+
+// pinStatsTopN is the number of largest pins reported by PinStats.
+const pinStatsTopN = 20
+
+// sizeHistogramBounds are the upper bounds (exclusive) of the PinStats size histogram buckets.
+// A final open-ended bucket collects everything at or above the last bound.
+var sizeHistogramBounds = []struct {
+	label string
+	max   int64
+}{
+	{"<1KB", 1 << 10},
+	{"1KB-1MB", 1 << 20},
+	{"1MB-100MB", 100 << 20},
+	{"100MB-1GB", 1 << 30},
+}
+
+// StatsBucket aggregates the number of pins and their total size.
+type StatsBucket struct {
+	Count int   `json:"count"`
+	Bytes int64 `json:"bytes"`
+}
+
+// SizeBucket is a single bucket of the PinStats size histogram.
+// Label describes the range, Min is inclusive and Max is exclusive (0 for the open-ended bucket).
+type SizeBucket struct {
+	Label string `json:"label"`
+	Min   int64  `json:"min"`
+	Max   int64  `json:"max,omitempty"`
+	StatsBucket
+}
+
+// PinSummary describes a single pin in the PinStats report.
+type PinSummary struct {
+	Cid      string `json:"cid"`
+	Name     string `json:"name,omitempty"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// PinStatsReport represents aggregate statistics over pinned content.
+// Total holds the number of pins and their total size.
+// ByMimeType holds totals per mime type ("unknown" when the API reports none).
+// ByGroup holds totals per group ID, for pins that belong to a group.
+// SizeHistogram holds the number of pins and bytes per size range.
+// Largest lists the largest pins, in descending order of size.
+type PinStatsReport struct {
+	Total         StatsBucket             `json:"total"`
+	ByMimeType    map[string]*StatsBucket `json:"byMimeType"`
+	ByGroup       map[string]*StatsBucket `json:"byGroup"`
+	SizeHistogram []SizeBucket            `json:"sizeHistogram"`
+	Largest       []PinSummary            `json:"largest"`
+}
+
+// PinStats streams all pins matching options and computes totals by mime type, by group, a size
+// histogram and the largest pins, without holding the full pin listing in memory.
+//
+// The pinList rows do not carry group membership, so group totals are computed with one additional
+// paginated listing per group. When options sets a GroupID, only that group is reported.
+func (c *Client) PinStats(ctx context.Context, options *ListFilesOptions) (*PinStatsReport, error) {
+	report := &PinStatsReport{
+		ByMimeType: make(map[string]*StatsBucket),
+		ByGroup:    make(map[string]*StatsBucket),
+	}
+	for i, bound := range sizeHistogramBounds {
+		var lower int64
+		if i > 0 {
+			lower = sizeHistogramBounds[i-1].max
+		}
+		report.SizeHistogram = append(report.SizeHistogram, SizeBucket{Label: bound.label, Min: lower, Max: bound.max})
+	}
+	last := sizeHistogramBounds[len(sizeHistogramBounds)-1]
+	report.SizeHistogram = append(report.SizeHistogram, SizeBucket{Label: ">=1GB", Min: last.max})
+
+	largest := &pinSizeHeap{}
+	err := c.forEachPinPage(ctx, options, func(rows []Pin) error {
+		for _, row := range rows {
+			report.add(row, largest)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Largest = make([]PinSummary, largest.Len())
+	for i := largest.Len() - 1; i >= 0; i-- {
+		report.Largest[i] = heap.Pop(largest).(PinSummary)
+	}
+
+	if err := c.addGroupStats(ctx, options, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// add records a single pin in the report and keeps the largest pins in the heap.
+func (r *PinStatsReport) add(row Pin, largest *pinSizeHeap) {
+	size := int64(row.Size)
+	r.Total.Count++
+	r.Total.Bytes += size
+
+	mimeType := row.MimeType
+	if mimeType == "" {
+		mimeType = "unknown"
+	}
+	bucket, ok := r.ByMimeType[mimeType]
+	if !ok {
+		bucket = &StatsBucket{}
+		r.ByMimeType[mimeType] = bucket
+	}
+	bucket.Count++
+	bucket.Bytes += size
+
+	for i := range r.SizeHistogram {
+		if r.SizeHistogram[i].Max == 0 || size < r.SizeHistogram[i].Max {
+			r.SizeHistogram[i].Count++
+			r.SizeHistogram[i].Bytes += size
+			break
+		}
+	}
+
+	name, _ := row.Metadata["name"].(string)
+	summary := PinSummary{Cid: row.IPFSPinHash, Name: name, Size: size, MimeType: row.MimeType}
+	if largest.Len() < pinStatsTopN {
+		heap.Push(largest, summary)
+	} else if size > (*largest)[0].Size {
+		(*largest)[0] = summary
+		heap.Fix(largest, 0)
+	}
+}
+
+// addGroupStats computes the totals of every group (or only options.GroupID when set).
+func (c *Client) addGroupStats(ctx context.Context, options *ListFilesOptions, report *PinStatsReport) error {
+	var groupIDs []string
+	if options != nil && options.GroupID != "" {
+		groupIDs = []string{options.GroupID}
+	} else {
+		groups, err := c.listAllGroups(ctx)
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			groupIDs = append(groupIDs, group.ID)
+		}
+	}
+	sort.Strings(groupIDs)
+
+	for _, groupID := range groupIDs {
+		filter := ListFilesOptions{}
+		if options != nil {
+			filter = *options
+		}
+		filter.GroupID = groupID
+
+		bucket := &StatsBucket{}
+		err := c.forEachPinPage(ctx, &filter, func(rows []Pin) error {
+			for _, row := range rows {
+				bucket.Count++
+				bucket.Bytes += int64(row.Size)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if bucket.Count > 0 {
+			report.ByGroup[groupID] = bucket
+		}
+	}
+
+	return nil
+}
+
+// pinSizeHeap is a min-heap of pins ordered by size, used to track the largest pins.
+type pinSizeHeap []PinSummary
+
+func (h pinSizeHeap) Len() int { return len(h) }
+func (h pinSizeHeap) Less(i, j int) bool {
+	if h[i].Size == h[j].Size {
+		return h[i].Cid > h[j].Cid
+	}
+	return h[i].Size < h[j].Size
+}
+func (h pinSizeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *pinSizeHeap) Push(x interface{}) { *h = append(*h, x.(PinSummary)) }
+func (h *pinSizeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
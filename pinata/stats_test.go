@@ -0,0 +1,136 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type statsFixturePin struct {
+	Pin
+	groupID string
+}
+
+// statsFixture returns 25 pins: sizes 1..25 KiB-ish spread over three mime types and two groups,
+// plus one 2 GiB video.
+func statsFixture() []statsFixturePin {
+	var pins []statsFixturePin
+	mimeTypes := []string{"image/png", "application/json", ""}
+	for i := 0; i < 24; i++ {
+		group := ""
+		if i%3 == 0 {
+			group = "group-a"
+		} else if i%3 == 1 {
+			group = "group-b"
+		}
+		pins = append(pins, statsFixturePin{
+			Pin: Pin{
+				IPFSPinHash: fmt.Sprintf("QmPin%02d", i),
+				Size:        (i + 1) * 512,
+				MimeType:    mimeTypes[i%3],
+				Metadata:    map[string]interface{}{"name": fmt.Sprintf("file-%02d", i)},
+			},
+			groupID: group,
+		})
+	}
+	pins = append(pins, statsFixturePin{
+		Pin:     Pin{IPFSPinHash: "QmVideo", Size: 2 << 30, MimeType: "video/mp4", Metadata: map[string]interface{}{"name": "movie"}},
+		groupID: "group-a",
+	})
+	return pins
+}
+
+func TestPinStats(t *testing.T) {
+	fixture := statsFixture()
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/groups":
+			json.NewEncoder(w).Encode([]Group{{ID: "group-a"}, {ID: "group-b"}, {ID: "group-empty"}})
+		case "/data/pinList":
+			limit, _ := strconv.Atoi(r.URL.Query().Get("pageLimit"))
+			offset, _ := strconv.Atoi(r.URL.Query().Get("pageOffset"))
+			groupID := r.URL.Query().Get("groupId")
+			var matching []Pin
+			for _, p := range fixture {
+				if groupID == "" || p.groupID == groupID {
+					matching = append(matching, p.Pin)
+				}
+			}
+			rows := []Pin{}
+			if offset < len(matching) {
+				rows = matching[offset:min(offset+limit, len(matching))]
+			}
+			json.NewEncoder(w).Encode(ListFilesResponse{Count: len(matching), Rows: rows})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	report, err := client.PinStats(context.Background(), nil)
+	require.NoError(t, err)
+
+	var expectedBytes int64
+	for _, p := range fixture {
+		expectedBytes += int64(p.Size)
+	}
+	require.Equal(t, 25, report.Total.Count)
+	require.Equal(t, expectedBytes, report.Total.Bytes)
+
+	require.Equal(t, 8, report.ByMimeType["image/png"].Count)
+	require.Equal(t, 8, report.ByMimeType["application/json"].Count)
+	require.Equal(t, 8, report.ByMimeType["unknown"].Count)
+	require.Equal(t, 1, report.ByMimeType["video/mp4"].Count)
+	require.Equal(t, int64(2<<30), report.ByMimeType["video/mp4"].Bytes)
+
+	require.Equal(t, 9, report.ByGroup["group-a"].Count)
+	require.Equal(t, 8, report.ByGroup["group-b"].Count)
+	require.NotContains(t, report.ByGroup, "group-empty")
+
+	require.Equal(t, "<1KB", report.SizeHistogram[0].Label)
+	require.Equal(t, 1, report.SizeHistogram[0].Count) // 512 bytes
+	require.Equal(t, 23, report.SizeHistogram[1].Count)
+	require.Equal(t, 1, report.SizeHistogram[len(report.SizeHistogram)-1].Count)
+
+	require.Len(t, report.Largest, pinStatsTopN)
+	require.Equal(t, "QmVideo", report.Largest[0].Cid)
+	require.Equal(t, "movie", report.Largest[0].Name)
+	require.Equal(t, "QmPin23", report.Largest[1].Cid)
+	for i := 1; i < len(report.Largest); i++ {
+		require.GreaterOrEqual(t, report.Largest[i-1].Size, report.Largest[i].Size)
+	}
+
+	encoded, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded PinStatsReport
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, report.Total, decoded.Total)
+	require.Equal(t, report.Largest, decoded.Largest)
+}
+
+func TestPinStatsError(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"Internal server error"}`))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	report, err := client.PinStats(context.Background(), nil)
+
+	require.Error(t, err)
+	require.Nil(t, report)
+	require.Contains(t, err.Error(), "Internal server error")
+}
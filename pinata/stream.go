@@ -0,0 +1,84 @@
+package pinata
+
+import (
+	"context"
+)
+
+func init() {
+	registerCapability(CapabilityStreaming)
+}
+
+// StreamFiles sends every pinned file matching options on the returned pin channel, which holds up
+// to buffer pins. Pages are fetched one at a time and only once the pins of the previous page were
+// all sent, so a consumer slower than the API holds the listing back rather than piling pins up in
+// memory: at most buffer pins and one page are held at any time.
+//
+// Like ListFilesAll it lists content of any status unless options sets one. Pages hold
+// options.PageLimit pins (MaxPinListPageLimit when zero) and the listing starts at
+// options.PageOffset. Rate limited pages are retried as configured with WithPagination.
+//
+// The error channel delivers at most one error, which ends the listing: the error of a page, or
+// the error of ctx once it is done. Both channels are closed when the listing ends, the error
+// channel after the pin channel, so the error, if any, can be read once the pins were ranged over.
+func (c *Client) StreamFiles(ctx context.Context, options *ListFilesOptions, buffer int) (<-chan Pin, <-chan error) {
+	filter := ListFilesOptions{Status: "all"}
+	if options != nil {
+		filter = *options.Clone()
+		if filter.Status == "" {
+			filter.Status = "all"
+		}
+	}
+	if filter.PageLimit <= 0 {
+		filter.PageLimit = MaxPinListPageLimit
+	}
+
+	pins := make(chan Pin, max(buffer, 0))
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(pins)
+		if err := c.streamPins(ctx, filter, pins, nil); err != nil {
+			errs <- err
+		}
+	}()
+	return pins, errs
+}
+
+// streamPins sends the pins listed with filter for which keep returns true (every pin when keep is
+// nil) on pins, fetching the next page once the pins of the previous one were sent. It returns the
+// error of a page, or the error of ctx once it is done.
+func (c *Client) streamPins(ctx context.Context, filter ListFilesOptions, pins chan<- Pin, keep func(Pin) bool) error {
+	pages := c.newPaginator("/data/pinList")
+	for {
+		var response *ListFilesResponse
+		err := pages.fetch(ctx, func() (int, error) {
+			var err error
+			response, err = c.listFiles(ctx, &filter)
+			if err != nil {
+				return 0, err
+			}
+			return len(response.Rows), nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return transportError(ctx.Err())
+			}
+			return err
+		}
+
+		for _, row := range response.Rows {
+			if keep != nil && !keep(row) {
+				continue
+			}
+			select {
+			case pins <- row:
+			case <-ctx.Done():
+				return transportError(ctx.Err())
+			}
+		}
+		if len(response.Rows) < min(filter.PageLimit, MaxPinListPageLimit) {
+			return nil
+		}
+		filter.PageOffset += len(response.Rows)
+	}
+}
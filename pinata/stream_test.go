@@ -0,0 +1,113 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pinListServer serves total rows from pinList, paginated with pageOffset and pageLimit, and
+// counts the pages served. Pages from failAt on fail with 400.
+func pinListServer(t *testing.T, total, failAt int, pages *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := int(pages.Add(1))
+		if failAt > 0 && page >= failAt {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad page"}`))
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("pageOffset"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("pageLimit"))
+		require.NoError(t, err)
+		var rows []string
+		for i := offset; i < min(offset+limit, total); i++ {
+			rows = append(rows, fmt.Sprintf(`{"ipfs_pin_hash":"Qm%d"}`, i))
+		}
+		w.Write([]byte(`{"rows":[` + strings.Join(rows, ",") + `]}`))
+	}))
+}
+
+func TestStreamFiles(t *testing.T) {
+	t.Run("back-pressure", func(t *testing.T) {
+		var pages atomic.Int32
+		mockServer := pinListServer(t, 9, 0, &pages)
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		pins, errs := client.StreamFiles(context.Background(), &ListFilesOptions{PageLimit: 2}, 1)
+
+		// nothing is consumed: the first page fills the buffer and the stream waits
+		time.Sleep(50 * time.Millisecond)
+		require.EqualValues(t, 1, pages.Load())
+
+		// consuming one page lets the next one be fetched, and no more
+		<-pins
+		<-pins
+		time.Sleep(50 * time.Millisecond)
+		require.EqualValues(t, 2, pages.Load())
+
+		var hashes []string
+		for p := range pins {
+			hashes = append(hashes, p.IPFSPinHash)
+			time.Sleep(time.Millisecond)
+		}
+		require.NoError(t, <-errs)
+		require.Equal(t, []string{"Qm2", "Qm3", "Qm4", "Qm5", "Qm6", "Qm7", "Qm8"}, hashes)
+		require.EqualValues(t, 5, pages.Load())
+	})
+
+	t.Run("page error", func(t *testing.T) {
+		var pages atomic.Int32
+		mockServer := pinListServer(t, 9, 2, &pages)
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		pins, errs := client.StreamFiles(context.Background(), &ListFilesOptions{PageLimit: 2}, 0)
+		var n int
+		for range pins {
+			n++
+		}
+		require.Equal(t, 2, n)
+
+		err := <-errs
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		_, open := <-errs
+		require.False(t, open)
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		var pages atomic.Int32
+		mockServer := pinListServer(t, 100, 0, &pages)
+		defer mockServer.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		ctx, cancel := context.WithCancel(context.Background())
+		pins, errs := client.StreamFiles(ctx, &ListFilesOptions{PageLimit: 10}, 0)
+		<-pins
+		cancel()
+
+		select {
+		case err := <-errs:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("the stream did not stop after cancellation")
+		}
+		for range pins {
+		}
+		require.EqualValues(t, 1, pages.Load())
+	})
+}
@@ -1,48 +1,137 @@
 package pinata
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 )
 
-// swapData represents the data for a single swap, including the mapped CID and the creation timestamp.
-type swapData struct {
+func init() {
+	registerCapability(CapabilitySwaps)
+}
+
+// ErrSwapTargetNotPinned is returned by AddSwap when the CID a swap points to is not pinned on the
+// account, which would make the gateway answer 404 for the swapped CID.
+var ErrSwapTargetNotPinned = errors.New("swap target is not pinned")
+
+// SwapRecord represents a single swap.
+// MappedCid is the CID the swapped CID resolves to.
+// CreatedAt is the creation timestamp of the swap.
+// Domain is the gateway domain the swap applies to. Records returned by GetSwapHistory carry the
+// queried domain when the API does not report one.
+type SwapRecord struct {
 	MappedCid string    `json:"mappedCid"`
 	CreatedAt time.Time `json:"createdAt"`
+	Domain    string    `json:"domain,omitempty"`
+}
+
+// AddSwapResponse is the response of AddSwap and SwapTo.
+type AddSwapResponse struct {
+	Data SwapRecord `json:"data"`
+}
+
+// RemoveSwapResponse represents the response from removing a swap.
+// Message is the confirmation returned by the API.
+type RemoveSwapResponse struct {
+	Message string `json:"message"`
 }
 
-type addSwapResponse struct {
-	Data swapData `json:"data"`
+// UnmarshalJSON decodes the {"data": ...} envelope of the removal response, where data is either
+// an object with a message or the message string itself.
+func (r *RemoveSwapResponse) UnmarshalJSON(b []byte) error {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return err
+	}
+
+	data := bytes.TrimSpace(envelope.Data)
+	switch {
+	case len(data) == 0 || bytes.Equal(data, []byte("null")):
+		*r = RemoveSwapResponse{}
+		return nil
+	case data[0] == '"':
+		*r = RemoveSwapResponse{}
+		return json.Unmarshal(data, &r.Message)
+	}
+
+	var object struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*r = RemoveSwapResponse{Message: object.Message}
+	return nil
 }
 
-type deleteSwapResponse struct {
-	Data interface{} `json:"data"`
+// GetSwapResponse is the response of GetSwapHistory.
+type GetSwapResponse struct {
+	Data []SwapRecord `json:"data"`
 }
 
-type getSwapResponse struct {
-	Data []swapData `json:"data"`
+// AddSwapOptions represents the options for adding a swap.
+// VerifyTarget makes AddSwap check that swapCid is pinned on the account before creating the swap.
+// It defaults to true when nil; set it to Bool(false) to skip the check.
+type AddSwapOptions struct {
+	VerifyTarget *bool
 }
 
 // AddSwap adds a new swap for the given CID. The swapCid parameter represents the CID
 // that will be mapped to the original CID. If either the cid or swapCid is empty,
-// an error is returned.
-func (c *Client) AddSwap(cid, swapCid string) (*addSwapResponse, error) {
+// an error is returned. Unless options.VerifyTarget is false, swapCid must be pinned on the
+// account, otherwise an error wrapping ErrSwapTargetNotPinned is returned. options may be nil.
+func (c *Client) AddSwap(cid, swapCid string, options *AddSwapOptions) (*AddSwapResponse, error) {
+	return c.addSwap(context.Background(), cid, swapCid, options)
+}
+
+// AddSwapWithContext is like AddSwap but uses ctx for the requests.
+func (c *Client) AddSwapWithContext(ctx context.Context, cid, swapCid string, options *AddSwapOptions) (*AddSwapResponse, error) {
+	return c.addSwap(ctx, cid, swapCid, options)
+}
+
+// addSwap implements AddSwap, using ctx for the requests.
+func (c *Client) addSwap(ctx context.Context, cid, swapCid string, options *AddSwapOptions) (*AddSwapResponse, error) {
 	if cid == "" || swapCid == "" {
 		return nil, fmt.Errorf("cid and swapcid are required")
 	}
 
+	if options == nil || options.VerifyTarget == nil || *options.VerifyTarget {
+		pinned, err := c.IsPinned(ctx, swapCid, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify swap target: %w", err)
+		}
+		if !pinned {
+			return nil, fmt.Errorf("%w: %s", ErrSwapTargetNotPinned, swapCid)
+		}
+	}
+
+	unlock, err := c.lockCids(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	payload := make(map[string]string)
 	payload["swapCid"] = swapCid
 
 	req, err := c.NewRequest(http.MethodPut, "/v3/ipfs/swap/{cid}").
+		WithContext(ctx).
 		AddPathParam("cid", cid).
 		SetJSONBody(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set JSON body: %w", err)
 	}
 
-	var response addSwapResponse
+	var response AddSwapResponse
 	err = req.Send(&response)
 	if err != nil {
 		return nil, err
@@ -50,16 +139,69 @@ func (c *Client) AddSwap(cid, swapCid string) (*addSwapResponse, error) {
 	return &response, nil
 }
 
+// SwapTo pins the file or directory at newContentPath and swaps cid to the pinned content in one
+// call. A directory is pinned as a folder holding the regular files beneath it, as
+// PinNestedFolders does. The returned swap maps cid to the CID of the new content. If the swap
+// fails after the upload, the new content stays pinned.
+func (c *Client) SwapTo(ctx context.Context, cid, newContentPath string) (*AddSwapResponse, error) {
+	if cid == "" || newContentPath == "" {
+		return nil, fmt.Errorf("cid and new content path are required")
+	}
+
+	info, err := os.Stat(newContentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin new content: %w", err)
+	}
+	var pinned *PinResponse
+	if info.IsDir() {
+		pinned, err = c.pinDirectoryFolder(ctx, newContentPath)
+	} else {
+		pinned, err = c.pinFile(ctx, newContentPath, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin new content: %w", err)
+	}
+	return c.addSwap(ctx, cid, pinned.IpfsHash, &AddSwapOptions{VerifyTarget: Bool(false)})
+}
+
+// pinDirectoryFolder pins the regular files beneath dir as a single folder, with their paths
+// relative to dir.
+func (c *Client) pinDirectoryFolder(ctx context.Context, dir string) (*PinResponse, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files found in %s", dir)
+	}
+	return c.pinNestedFolders(ctx, dir, paths, nil)
+}
+
 // GetSwapHistory retrieves the swap history for the given CID and domain.
 // The CID and domain parameters are required.
-// The function returns a getSwapResponse containing the swap history data, or an error if the request fails.
-func (c *Client) GetSwapHistory(cid, domain string) (*getSwapResponse, error) {
+// The function returns a GetSwapResponse containing the swap history data, with each record
+// carrying domain, or an error if the request fails.
+func (c *Client) GetSwapHistory(cid, domain string) (*GetSwapResponse, error) {
+	return c.GetSwapHistoryWithContext(context.Background(), cid, domain)
+}
+
+// GetSwapHistoryWithContext is like GetSwapHistory but uses ctx for the requests.
+func (c *Client) GetSwapHistoryWithContext(ctx context.Context, cid, domain string) (*GetSwapResponse, error) {
 	if cid == "" || domain == "" {
 		return nil, fmt.Errorf("cid and domain are required")
 	}
 
-	var response getSwapResponse
-	err := c.NewRequest(http.MethodDelete, "/v3/ipfs/swap/{cid}").
+	var response GetSwapResponse
+	err := c.NewRequest(http.MethodGet, "/v3/ipfs/swap/{cid}").WithContext(ctx).
 		AddPathParam("cid", cid).
 		AddQueryParam("domain", domain).
 		Send(&response)
@@ -67,17 +209,33 @@ func (c *Client) GetSwapHistory(cid, domain string) (*getSwapResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	for i := range response.Data {
+		if response.Data[i].Domain == "" {
+			response.Data[i].Domain = domain
+		}
+	}
 	return &response, nil
 }
 
 // RemoveSwap removes the swap for the given CID. If the cid is empty, an error is returned.
-func (c *Client) RemoveSwap(cid string) (*deleteSwapResponse, error) {
+func (c *Client) RemoveSwap(cid string) (*RemoveSwapResponse, error) {
+	return c.RemoveSwapWithContext(context.Background(), cid)
+}
+
+// RemoveSwapWithContext is like RemoveSwap but uses ctx for the requests.
+func (c *Client) RemoveSwapWithContext(ctx context.Context, cid string) (*RemoveSwapResponse, error) {
 	if cid == "" {
 		return nil, fmt.Errorf("cid is required")
 	}
 
-	var response deleteSwapResponse
-	err := c.NewRequest(http.MethodDelete, "/v3/ipfs/swap/{cid}").
+	unlock, err := c.lockCids(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var response RemoveSwapResponse
+	err = c.NewRequest(http.MethodDelete, "/v3/ipfs/swap/{cid}").WithContext(ctx).
 		AddPathParam("cid", cid).
 		Send(&response)
 
@@ -1,12 +1,20 @@
 package pinata
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"mime"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zde37/pinata-go-sdk/pinata/internal/fixtures"
 )
 
 func TestAddSwap(t *testing.T) {
@@ -24,23 +32,24 @@ func TestAddSwap(t *testing.T) {
 			require.Equal(t, "test_swap_cid", payload["swapCid"])
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"data" : {"mappedCid": "test_swap_cid"}}`))
+			w.Write(fixture(t, "PUT /v3/ipfs/swap/{cid}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
-		response, err := client.AddSwap("test_cid", "test_swap_cid")
+		response, err := client.AddSwap("test_cid", "test_swap_cid", &AddSwapOptions{VerifyTarget: Bool(false)})
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Equal(t, "test_swap_cid", response.Data.MappedCid) 
+		require.Equal(t, fixtures.Cid, response.Data.MappedCid)
+		require.Equal(t, time.Date(2024, 5, 21, 19, 27, 3, 391000000, time.UTC), response.Data.CreatedAt)
 	})
 
 	t.Run("empty cid", func(t *testing.T) {
 		auth := &Auth{jwt: "valid_jwt_token"}
 		client := New(auth)
 
-		response, err := client.AddSwap("", "test_swap_cid")
+		response, err := client.AddSwap("", "test_swap_cid", nil)
 
 		require.Error(t, err)
 		require.Nil(t, response)
@@ -51,7 +60,7 @@ func TestAddSwap(t *testing.T) {
 		auth := &Auth{jwt: "valid_jwt_token"}
 		client := New(auth)
 
-		response, err := client.AddSwap("test_cid", "")
+		response, err := client.AddSwap("test_cid", "", nil)
 
 		require.Error(t, err)
 		require.Nil(t, response)
@@ -68,7 +77,7 @@ func TestAddSwap(t *testing.T) {
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
-		response, err := client.AddSwap("test_cid", "test_swap_cid")
+		response, err := client.AddSwap("test_cid", "test_swap_cid", &AddSwapOptions{VerifyTarget: Bool(false)})
 
 		require.Error(t, err)
 		require.Nil(t, response)
@@ -85,12 +94,149 @@ func TestAddSwap(t *testing.T) {
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
-		response, err := client.AddSwap("test_cid", "test_swap_cid")
+		response, err := client.AddSwap("test_cid", "test_swap_cid", &AddSwapOptions{VerifyTarget: Bool(false)})
 
 		require.Error(t, err)
 		require.Nil(t, response)
 		require.Contains(t, err.Error(), "Unauthorized")
 	})
+
+	t.Run("unpinned target", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/data/pinList", r.URL.Path)
+			require.Equal(t, "test_swap_cid", r.URL.Query().Get("cid"))
+			require.Equal(t, "pinned", r.URL.Query().Get("status"))
+
+			w.Write([]byte(`{"count": 0, "rows": []}`))
+		}))
+		defer mockServer.Close()
+		client.baseURL = mockServer.URL
+
+		response, err := client.AddSwap("test_cid", "test_swap_cid", nil)
+
+		require.ErrorIs(t, err, ErrSwapTargetNotPinned)
+		require.Nil(t, response)
+	})
+
+	t.Run("pinned target", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		var requests []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			if r.URL.Path == "/data/pinList" {
+				require.Equal(t, fixtures.Cid, r.URL.Query().Get("cid"))
+				w.Write(fixture(t, "GET /data/pinList"))
+				return
+			}
+			w.Write(fixture(t, "PUT /v3/ipfs/swap/{cid}"))
+		}))
+		defer mockServer.Close()
+		client.baseURL = mockServer.URL
+
+		response, err := client.AddSwap("test_cid", fixtures.Cid, &AddSwapOptions{VerifyTarget: Bool(true)})
+
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.Data.MappedCid)
+		require.Equal(t, []string{"GET /data/pinList", "PUT /v3/ipfs/swap/test_cid"}, requests)
+	})
+}
+
+func TestSwapTo(t *testing.T) {
+	t.Run("pins and swaps", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "v2.txt")
+		require.NoError(t, os.WriteFile(path, []byte("version 2"), 0o644))
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		var requests []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			switch r.URL.Path {
+			case "/pinning/pinFileToIPFS":
+				w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+			case "/v3/ipfs/swap/old_cid":
+				var payload map[string]string
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				require.Equal(t, fixtures.Cid, payload["swapCid"])
+				w.Write(fixture(t, "PUT /v3/ipfs/swap/{cid}"))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+		defer mockServer.Close()
+		client.baseURL = mockServer.URL
+
+		response, err := client.SwapTo(context.Background(), "old_cid", path)
+
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.Data.MappedCid)
+		require.Equal(t, []string{"POST /pinning/pinFileToIPFS", "PUT /v3/ipfs/swap/old_cid"}, requests)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		dir := writeTree(t, "index.html", "assets/app.js")
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		var parts []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/pinning/pinFileToIPFS":
+				reader, err := r.MultipartReader()
+				require.NoError(t, err)
+				for {
+					part, err := reader.NextPart()
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+					// part.FileName drops the directories, so the name is read from the header
+					_, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+					require.NoError(t, err)
+					if params["name"] == "file" {
+						parts = append(parts, params["filename"])
+					}
+				}
+				w.Write(fixture(t, "POST /pinning/pinFileToIPFS"))
+			case "/v3/ipfs/swap/old_cid":
+				var payload map[string]string
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				require.Equal(t, fixtures.Cid, payload["swapCid"])
+				w.Write(fixture(t, "PUT /v3/ipfs/swap/{cid}"))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+		defer mockServer.Close()
+		client.baseURL = mockServer.URL
+
+		response, err := client.SwapTo(context.Background(), "old_cid", dir)
+
+		require.NoError(t, err)
+		require.Equal(t, fixtures.Cid, response.Data.MappedCid)
+		require.Len(t, parts, 2)
+		require.True(t, strings.HasSuffix(parts[0], "/assets/app.js"), parts[0])
+		require.True(t, strings.HasSuffix(parts[1], "/index.html"), parts[1])
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		response, err := client.SwapTo(context.Background(), "old_cid", t.TempDir())
+
+		require.Error(t, err)
+		require.Nil(t, response)
+		require.Contains(t, err.Error(), "no files found")
+	})
+
+	t.Run("upload failure", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		response, err := client.SwapTo(context.Background(), "old_cid", filepath.Join(t.TempDir(), "missing.txt"))
+
+		require.Error(t, err)
+		require.Nil(t, response)
+		require.Contains(t, err.Error(), "failed to pin new content")
+	})
 }
 
 func TestGetSwapHistory(t *testing.T) {
@@ -99,12 +245,12 @@ func TestGetSwapHistory(t *testing.T) {
 		client := New(auth)
 		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			require.Equal(t, "/v3/ipfs/swap/test_cid", r.URL.Path)
-			require.Equal(t, http.MethodDelete, r.Method)
+			require.Equal(t, http.MethodGet, r.Method)
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 			require.Equal(t, "test_domain", r.URL.Query().Get("domain"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"data": [{"createdAt": "2023-05-01T12:00:00Z", "mappedCid": "swap_cid_1"}]}`))
+			w.Write(fixture(t, "GET /v3/ipfs/swap/{cid}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -114,8 +260,25 @@ func TestGetSwapHistory(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, response)
 		require.Len(t, response.Data, 1)
-		require.Equal(t, "swap_cid_1", response.Data[0].MappedCid)
-		require.Equal(t, "2023-05-01 12:00:00 +0000 UTC", response.Data[0].CreatedAt.String())
+		require.Equal(t, fixtures.Cid, response.Data[0].MappedCid)
+		require.Equal(t, time.Date(2024, 5, 21, 19, 27, 3, 391000000, time.UTC), response.Data[0].CreatedAt)
+		require.Equal(t, "test_domain", response.Data[0].Domain)
+	})
+
+	t.Run("domain reported by the API", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": [{"mappedCid": "swap_cid_1", "domain": "other.mypinata.cloud"}, {"mappedCid": "swap_cid_2"}]}`))
+		}))
+		defer mockServer.Close()
+		client.baseURL = mockServer.URL
+
+		response, err := client.GetSwapHistory("test_cid", "test_domain")
+
+		require.NoError(t, err)
+		require.Len(t, response.Data, 2)
+		require.Equal(t, "other.mypinata.cloud", response.Data[0].Domain)
+		require.Equal(t, "test_domain", response.Data[1].Domain)
 	})
 
 	t.Run("empty cid", func(t *testing.T) {
@@ -185,7 +348,7 @@ func TestRemoveSwap(t *testing.T) {
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"data" : {"message": "Swap removed successfully"}}`))
+			w.Write(fixture(t, "DELETE /v3/ipfs/swap/{cid}"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -194,6 +357,7 @@ func TestRemoveSwap(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response) 
+		require.Equal(t, "OK", response.Message)
 	})
 
 	t.Run("empty cid", func(t *testing.T) {
@@ -241,3 +405,30 @@ func TestRemoveSwap(t *testing.T) {
 		require.Contains(t, err.Error(), "Swap not found")
 	})
 }
+
+func TestRemoveSwapResponseDecoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"object data", `{"data": {"message": "Swap removed"}}`, "Swap removed"},
+		{"string data", `{"data": "OK"}`, "OK"},
+		{"null data", `{"data": null}`, ""},
+		{"missing data", `{}`, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var response RemoveSwapResponse
+
+			require.NoError(t, json.Unmarshal([]byte(tc.body), &response))
+			require.Equal(t, tc.expected, response.Message)
+		})
+	}
+
+	t.Run("unexpected data", func(t *testing.T) {
+		var response RemoveSwapResponse
+
+		require.Error(t, json.Unmarshal([]byte(`{"data": [1, 2]}`), &response))
+	})
+}
@@ -0,0 +1,239 @@
+package pinata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	registerCapability(CapabilityTimeoutPolicy)
+}
+
+// TimeoutPolicy derives the time limit of a call from the size of its payload, so that small calls
+// fail fast while large uploads and downloads get the time they need. The limit of a payload of
+// size bytes is
+//
+//	Base + PerMB × ⌈size / 1 MiB⌉
+//
+// raised to Min and lowered to Max. Every started MiB counts, so a payload of one byte gets
+// Base + PerMB and an empty payload, such as a GET request, gets Base. A payload of unknown size
+// gets Base, within the same caps. A Min or Max of zero sets no cap, and a limit of zero means no
+// limit.
+type TimeoutPolicy struct {
+	Base  time.Duration
+	PerMB time.Duration
+	Min   time.Duration
+	Max   time.Duration
+}
+
+// Timeout returns the time limit of a call whose payload is size bytes long, or -1 when unknown.
+func (p TimeoutPolicy) Timeout(size int64) time.Duration {
+	timeout := p.Base
+	if size > 0 {
+		timeout += p.PerMB * time.Duration((size+1<<20-1)>>20)
+	}
+	if p.Min > 0 && timeout < p.Min {
+		timeout = p.Min
+	}
+	if p.Max > 0 && timeout > p.Max {
+		timeout = p.Max
+	}
+	return timeout
+}
+
+// WithTimeoutPolicy replaces the time limit set with WithTimeout, the same for every request, with
+// a limit derived from the payload of each call by policy. API requests are given a context
+// deadline from the size of their body, known for uploads of files, spooled streams and JSON
+// bodies, and retries share that deadline. The deadline can be read from the context of the
+// request passed to the interceptors added with WithRequestInterceptor. Downloads, from gateways,
+// access links and the origins fetched by PinURL and MirrorURL, are given the limit of an unknown
+// size until the response arrives, and then the limit of its Content-Length, counted from the
+// start of the download; they fail with a *TimeoutError once it is over. Every request of the
+// client is covered by one of these deadlines.
+//
+// A later WithTimeout sets a limit on every request again, on top of the policy.
+func WithTimeoutPolicy(policy TimeoutPolicy) ClientOption {
+	return func(c *Client) {
+		c.timeoutPolicy = &policy
+		c.httpClient.Timeout = 0
+	}
+}
+
+// withPayloadSize sets the size of the payload of the request, in bytes, for bodies whose size
+// cannot be told from the body itself, such as multipart uploads of files sent without a
+// Content-Length. A negative size leaves it unknown.
+func (rb *requestBuilder) withPayloadSize(size int64) *requestBuilder {
+	rb.payload = size
+	return rb
+}
+
+// payloadSize returns the size of the body of the request in bytes, 0 for bodiless requests, or
+// -1 when unknown.
+func (rb *requestBuilder) payloadSize() int64 {
+	switch {
+	case rb.payload > 0:
+		return rb.payload
+	case rb.length > 0:
+		return rb.length
+	}
+	switch body := rb.body.(type) {
+	case nil:
+		if rb.bodyFactory == nil {
+			return 0
+		}
+	case *bytes.Buffer:
+		return int64(body.Len())
+	case *bytes.Reader:
+		return int64(body.Len())
+	case *strings.Reader:
+		return int64(body.Len())
+	}
+	return -1
+}
+
+// callDeadline returns ctx with the deadline of a call whose payload is size bytes long, according
+// to the timeout policy of the client, and the function releasing it. Without a policy, ctx is
+// returned as is.
+func (c *Client) callDeadline(ctx context.Context, size int64) (context.Context, context.CancelFunc) {
+	if c.timeoutPolicy == nil {
+		return ctx, func() {}
+	}
+	timeout := c.timeoutPolicy.Timeout(size)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// cancelOnClose is a response body releasing the deadline of its call once closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// fileSizes returns the total size of the files at paths, or -1 when one cannot be read.
+func fileSizes(paths ...string) int64 {
+	var total int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return -1
+		}
+		total += info.Size()
+	}
+	return total
+}
+
+// downloadDeadline is the time limit of a download whose size is only known once the response
+// arrives. It starts with the limit of an unknown size and is moved by fit.
+type downloadDeadline struct {
+	policy TimeoutPolicy
+	start  time.Time
+	cancel context.CancelCauseFunc
+	mu     sync.Mutex
+	timer  *time.Timer
+}
+
+// errDownloadDeadline is the cause of the cancellation of a download that ran out of time.
+var errDownloadDeadline = errors.New("download deadline exceeded")
+
+// startDownload returns ctx canceled once the download started now runs out of time according to
+// the timeout policy of the client, and its deadline, or nil without a policy.
+func (c *Client) startDownload(ctx context.Context) (context.Context, *downloadDeadline) {
+	if c.timeoutPolicy == nil {
+		return ctx, nil
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	d := &downloadDeadline{policy: *c.timeoutPolicy, start: time.Now(), cancel: cancel}
+	if timeout := d.policy.Timeout(-1); timeout > 0 {
+		d.timer = time.AfterFunc(timeout, func() { cancel(errDownloadDeadline) })
+	}
+	return ctx, d
+}
+
+// fit moves the deadline to the limit of a download of size bytes, or -1 when unknown.
+func (d *downloadDeadline) fit(size int64) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil && !d.timer.Stop() {
+		// the limit is already over
+		return
+	}
+	timeout := d.policy.Timeout(size)
+	if timeout <= 0 {
+		return
+	}
+	remaining := timeout - time.Since(d.start)
+	if d.timer == nil {
+		d.timer = time.AfterFunc(remaining, func() { d.cancel(errDownloadDeadline) })
+		return
+	}
+	d.timer.Reset(remaining)
+}
+
+// stop releases the deadline.
+func (d *downloadDeadline) stop() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.mu.Unlock()
+	d.cancel(context.Canceled)
+}
+
+// err returns err as a *TimeoutError when the download ran out of time.
+func (d *downloadDeadline) err(ctx context.Context, err error) error {
+	if d != nil && err != nil && context.Cause(ctx) == errDownloadDeadline {
+		return &TimeoutError{Err: err}
+	}
+	return err
+}
+
+// body returns body reporting reads failing because the deadline is over as a *TimeoutError and
+// releasing the deadline once closed, or body itself without a deadline.
+func (d *downloadDeadline) body(ctx context.Context, body io.ReadCloser) io.ReadCloser {
+	if d == nil {
+		return body
+	}
+	return &deadlineBody{ReadCloser: body, ctx: ctx, deadline: d}
+}
+
+// deadlineBody is the body of a download, releasing its deadline once closed and reporting reads
+// failing because of it as a *TimeoutError.
+type deadlineBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	deadline *downloadDeadline
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = b.deadline.err(b.ctx, err)
+	}
+	return n, err
+}
+
+func (b *deadlineBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.deadline.stop()
+	return err
+}
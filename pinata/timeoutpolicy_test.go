@@ -0,0 +1,173 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutPolicy(t *testing.T) {
+	policy := TimeoutPolicy{Base: 10 * time.Second, PerMB: 2 * time.Second, Min: 11 * time.Second, Max: time.Minute}
+	tests := []struct {
+		name   string
+		policy TimeoutPolicy
+		size   int64
+		want   time.Duration
+	}{
+		{"unknown size gets the base", TimeoutPolicy{Base: 10 * time.Second, PerMB: 2 * time.Second}, -1, 10 * time.Second},
+		{"empty payload gets the base", TimeoutPolicy{Base: 10 * time.Second, PerMB: 2 * time.Second}, 0, 10 * time.Second},
+		{"unknown size is raised to min", policy, -1, 11 * time.Second},
+		{"one byte counts a MiB", policy, 1, 12 * time.Second},
+		{"exactly one MiB", policy, 1 << 20, 12 * time.Second},
+		{"one byte over a MiB", policy, 1<<20 + 1, 14 * time.Second},
+		{"last size under max", policy, 25 << 20, time.Minute},
+		{"one byte over max", policy, 25<<20 + 1, time.Minute},
+		{"huge payload is capped", policy, 1 << 40, time.Minute},
+		{"zero policy sets no limit", TimeoutPolicy{}, 1 << 30, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.policy.Timeout(tt.size))
+		})
+	}
+}
+
+func TestWithTimeoutPolicy(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if r.URL.Path == "/data/testAuthentication" {
+			serveFixture(w, r)
+			return
+		}
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	var deadlines []time.Duration
+	policy := TimeoutPolicy{Base: time.Minute, PerMB: time.Hour}
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithTimeout(time.Second), WithTimeoutPolicy(policy),
+		WithRequestInterceptor(func(req *http.Request) error {
+			deadline, ok := req.Context().Deadline()
+			require.True(t, ok)
+			deadlines = append(deadlines, time.Until(deadline))
+			return nil
+		}))
+	client.baseURL = mockServer.URL
+	client.uploadURL = mockServer.URL
+	require.Zero(t, client.httpClient.Timeout)
+
+	_, err := client.TestAuthentication()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "big.bin")
+	require.NoError(t, os.WriteFile(path, make([]byte, 1<<20+1), 0o644))
+	_, err = client.PinFile(path, nil)
+	require.NoError(t, err)
+
+	require.Len(t, deadlines, 2)
+	require.InDelta(t, time.Minute, deadlines[0], float64(5*time.Second))
+	require.InDelta(t, time.Minute+2*time.Hour, deadlines[1], float64(5*time.Second))
+}
+
+func TestDownloadTimeoutPolicy(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer gateway.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(gateway.URL),
+		WithTimeoutPolicy(TimeoutPolicy{Base: time.Minute, Max: 100 * time.Millisecond}))
+
+	content, err := client.Gateway().Get(context.Background(), "QmHello")
+	require.NoError(t, err)
+	defer content.Body.Close()
+
+	start := time.Now()
+	_, err = io.ReadAll(content.Body)
+	var timeoutErr *TimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "got %v", err)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// downloads within their limit are not affected
+	done := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 10)))
+	}))
+	defer done.Close()
+	client = New(&Auth{jwt: "valid_jwt_token"}, WithGateways(done.URL),
+		WithTimeoutPolicy(TimeoutPolicy{Base: time.Minute}))
+	content, err = client.Gateway().Get(context.Background(), "QmHello")
+	require.NoError(t, err)
+	body, err := io.ReadAll(content.Body)
+	require.NoError(t, err)
+	require.NoError(t, content.Body.Close())
+	require.Equal(t, strings.Repeat("a", 10), string(body))
+}
+
+func TestTimeoutPolicyCoversEveryRequest(t *testing.T) {
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data/pinList" {
+			w.Write(fixture(t, "GET /data/pinList"))
+			return
+		}
+		<-r.Context().Done()
+	}))
+	defer hanging.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(hanging.URL),
+		WithTimeoutPolicy(TimeoutPolicy{Base: 50 * time.Millisecond}))
+	client.baseURL = hanging.URL
+	client.uploadURL = hanging.URL
+	require.Zero(t, client.httpClient.Timeout)
+
+	ctx := context.Background()
+	calls := map[string]func() error{
+		"Gateway.Stat": func() error { _, err := client.Gateway().Stat(ctx, cidV0); return err },
+		"ListFolderContents": func() error {
+			_, err := client.ListFolderContents(ctx, cidV1)
+			return err
+		},
+		"Files.GetAccessLink": func() error {
+			_, err := client.Files().GetAccessLink(ctx, hanging.URL+"/files/link")
+			return err
+		},
+		"PinURL":    func() error { _, err := client.PinURLWithContext(ctx, hanging.URL+"/origin.txt", nil); return err },
+		"MirrorURL": func() error { _, err := client.MirrorURL(ctx, hanging.URL+"/origin.txt", nil); return err },
+		"AuditPins": func() error {
+			report, err := client.AuditPins(ctx, &AuditOptions{SampleRate: 1})
+			if err != nil {
+				return err
+			}
+			require.Len(t, report.Unreachable, 1)
+			return errors.New(report.Unreachable[0].Error)
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			err := call()
+			require.Error(t, err)
+			if name != "AuditPins" {
+				var timeoutErr *TimeoutError
+				require.Truef(t, errors.As(err, &timeoutErr), "got %v", err)
+			}
+			require.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
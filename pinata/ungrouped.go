@@ -0,0 +1,170 @@
+package pinata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+func init() {
+	registerCapability(CapabilityUngroupedPins)
+}
+
+// Defaults of the approximate CID set of ListUngroupedPins.
+const (
+	DefaultUngroupedExpectedCids      = 1_000_000
+	DefaultUngroupedFalsePositiveRate = 0.001
+)
+
+// UngroupedOptions represents the options of ListUngroupedPins.
+// Filter restricts the pins considered, as for StreamFiles; its GroupID, PageLimit and PageOffset
+// are managed by the listing. Only pinned content is considered unless Filter sets a status.
+// Approximate keeps the CIDs of the groups in a bloom filter instead of an exact set. Its memory
+// is fixed by ExpectedCids and FalsePositiveRate whatever the size of the groups, at the cost of
+// a grouped CID being mistaken now and then for an ungrouped one: a pin is never reported as
+// ungrouped while in a group, but an ungrouped pin is missed with a probability of about
+// FalsePositiveRate. Use it for accounts whose grouped CIDs do not fit in memory.
+// ExpectedCids is the number of grouped CIDs the bloom filter is sized for
+// (DefaultUngroupedExpectedCids when zero). Past it, the false positive rate grows.
+// FalsePositiveRate is the target false positive rate of the bloom filter
+// (DefaultUngroupedFalsePositiveRate when zero).
+// Buffer is the capacity of the pin channel.
+type UngroupedOptions struct {
+	Filter            *ListFilesOptions
+	Approximate       bool
+	ExpectedCids      int
+	FalsePositiveRate float64
+	Buffer            int
+}
+
+// ListUngroupedPins sends the pins that belong to no group on the returned pin channel. The pinList
+// rows do not carry group membership, so the CIDs of every group are collected first, with one
+// paginated listing per group, and the pin listing is then streamed as with StreamFiles, leaving
+// out the pins of the collected CIDs. Only the CIDs are held in memory, in an exact set or, with
+// options.Approximate, in a bloom filter of fixed size.
+//
+// The error channel delivers at most one error, which ends the listing, and both channels are
+// closed when it ends, as for StreamFiles.
+func (c *Client) ListUngroupedPins(ctx context.Context, options *UngroupedOptions) (<-chan Pin, <-chan error) {
+	var opts UngroupedOptions
+	if options != nil {
+		opts = *options
+	}
+	filter := ListFilesOptions{Status: "pinned"}
+	if opts.Filter != nil {
+		filter = *opts.Filter.Clone()
+		if filter.Status == "" {
+			filter.Status = "pinned"
+		}
+	}
+	filter.GroupID = ""
+	filter.PageLimit = MaxPinListPageLimit
+	filter.PageOffset = 0
+
+	pins := make(chan Pin, max(opts.Buffer, 0))
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(pins)
+
+		grouped, err := c.groupedCids(ctx, filter, opts)
+		if err != nil {
+			errs <- err
+			return
+		}
+		err = c.streamPins(ctx, filter, pins, func(p Pin) bool {
+			return !grouped.has(p.IPFSPinHash)
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return pins, errs
+}
+
+// groupedCids returns the set of the CIDs listed with filter in any group.
+func (c *Client) groupedCids(ctx context.Context, filter ListFilesOptions, opts UngroupedOptions) (cidSet, error) {
+	var set cidSet = exactCidSet{}
+	if opts.Approximate {
+		set = newBloomCidSet(opts.ExpectedCids, opts.FalsePositiveRate)
+	}
+
+	groups, err := c.listAllGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		filter.GroupID = group.ID
+		err := c.forEachPinPage(ctx, &filter, func(rows []Pin) error {
+			for _, row := range rows {
+				set.add(row.IPFSPinHash)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// cidSet is a set of CIDs.
+type cidSet interface {
+	add(cid string)
+	has(cid string) bool
+}
+
+// exactCidSet is a cidSet holding every CID.
+type exactCidSet map[string]struct{}
+
+func (s exactCidSet) add(cid string)      { s[cid] = struct{}{} }
+func (s exactCidSet) has(cid string) bool { _, ok := s[cid]; return ok }
+
+// bloomCidSet is a cidSet of fixed size that may report CIDs it does not hold, but never misses one
+// it holds.
+type bloomCidSet struct {
+	bits   []uint64
+	hashes int
+}
+
+// newBloomCidSet returns a bloomCidSet sized for n CIDs with a false positive rate of p.
+func newBloomCidSet(n int, p float64) *bloomCidSet {
+	if n <= 0 {
+		n = DefaultUngroupedExpectedCids
+	}
+	if p <= 0 || p >= 1 {
+		p = DefaultUngroupedFalsePositiveRate
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := max(1, int(math.Round(m/float64(n)*math.Ln2)))
+	return &bloomCidSet{bits: make([]uint64, int(m)/64+1), hashes: k}
+}
+
+// positions calls fn with the bit positions of cid, derived by double hashing from its SHA-256,
+// until fn returns false. It reports whether every call returned true.
+func (s *bloomCidSet) positions(cid string, fn func(bit uint64) bool) bool {
+	sum := sha256.Sum256([]byte(cid))
+	h1 := binary.LittleEndian.Uint64(sum[0:8])
+	h2 := binary.LittleEndian.Uint64(sum[8:16]) | 1
+	size := uint64(len(s.bits)) * 64
+	for i := 0; i < s.hashes; i++ {
+		if !fn((h1 + uint64(i)*h2) % size) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *bloomCidSet) add(cid string) {
+	s.positions(cid, func(bit uint64) bool {
+		s.bits[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+}
+
+func (s *bloomCidSet) has(cid string) bool {
+	return s.positions(cid, func(bit uint64) bool {
+		return s.bits[bit/64]&(1<<(bit%64)) != 0
+	})
+}
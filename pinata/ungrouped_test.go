@@ -0,0 +1,87 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUngroupedPins(t *testing.T) {
+	members := map[string][]string{
+		"g1": {"Qm1", "Qm2"},
+		"g2": {"Qm2", "Qm3"},
+		"":   {"Qm0", "Qm1", "Qm2", "Qm3", "Qm4", "Qm5"},
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/groups":
+			w.Write([]byte(`[{"id":"g1"},{"id":"g2"}]`))
+		case "/data/pinList":
+			require.Equal(t, "pinned", r.URL.Query().Get("status"))
+			var rows []map[string]string
+			for _, cid := range members[r.URL.Query().Get("groupId")] {
+				rows = append(rows, map[string]string{"ipfs_pin_hash": cid})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"rows": rows})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	for _, approximate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("approximate=%v", approximate), func(t *testing.T) {
+			client := New(&Auth{jwt: "valid_jwt_token"})
+			client.baseURL = mockServer.URL
+
+			pins, errs := client.ListUngroupedPins(context.Background(), &UngroupedOptions{
+				Approximate:  approximate,
+				ExpectedCids: 100,
+			})
+			var cids []string
+			for p := range pins {
+				cids = append(cids, p.IPFSPinHash)
+			}
+			require.NoError(t, <-errs)
+			require.Equal(t, []string{"Qm0", "Qm4", "Qm5"}, cids)
+		})
+	}
+
+	t.Run("group listing error", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"forbidden"}`))
+		}))
+		defer failing.Close()
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = failing.URL
+
+		pins, errs := client.ListUngroupedPins(context.Background(), nil)
+		_, open := <-pins
+		require.False(t, open)
+		require.ErrorContains(t, <-errs, "forbidden")
+	})
+}
+
+func TestBloomCidSet(t *testing.T) {
+	set := newBloomCidSet(10000, 0.001)
+	for i := 0; i < 10000; i++ {
+		set.add(fmt.Sprintf("Qm%d", i))
+	}
+	for i := 0; i < 10000; i++ {
+		require.True(t, set.has(fmt.Sprintf("Qm%d", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if set.has(fmt.Sprintf("bafy%d", i)) {
+			falsePositives++
+		}
+	}
+	require.Less(t, falsePositives, 50)
+}
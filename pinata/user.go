@@ -1,20 +1,24 @@
 package pinata
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 )
 
-// apiKeyResponse represents the response from an API key related request.
+// ApiKeyResponse represents the response from an API key related request.
 // It contains a slice of ApiKey structs and a count of the total number of keys.
-type apiKeyResponse struct {
-	Keys  []apiKey `json:"keys,omitempty"`
+// Warnings lists the WarningKeyUsesLow warnings raised for the listed keys.
+type ApiKeyResponse struct {
+	Keys  []ApiKey `json:"keys,omitempty"`
 	Count int      `json:"count,omitempty"`
+
+	Warnings []Warning `json:"-"`
 }
 
-// apiKey represents an API key for the Pinata service.
-type apiKey struct {
+// ApiKey represents an API key for the Pinata service.
+type ApiKey struct {
 	ID        string    `json:"id,omitempty"`
 	Name      string    `json:"name,omitempty"`
 	Key       string    `json:"key,omitempty"`
@@ -28,6 +32,15 @@ type apiKey struct {
 	UpdatedAt time.Time `json:"updatedAt,omitempty"`
 }
 
+// RemainingUses returns the number of uses left before the key is exhausted, 0 for an exhausted
+// key, or -1 when the key has no use limit (MaxUses is 0).
+func (k ApiKey) RemainingUses() int {
+	if k.MaxUses <= 0 {
+		return -1
+	}
+	return max(k.MaxUses-k.Uses, 0)
+}
+
 // scope represents the permissions and access scopes for an API key.
 // The Endpoints field contains the specific permissions for different API endpoints,
 // while the Admin field indicates if the API key has full administrative access.
@@ -104,11 +117,11 @@ type GenerateApiKeyOptions struct {
 	MaxUses     int         `json:"maxUses,omitempty"`
 }
 
-// secret represents the secret information returned when generating a new API key.
+// ApiKeySecret represents the secret information returned when generating a new API key.
 // The JWT field contains the JSON Web Token for the generated API key.
 // The PinataApiKey field contains the API key itself.
 // The PinataApiSecret field contains the API secret for the generated API key.
-type secret struct {
+type ApiKeySecret struct {
 	JWT             string `json:"JWT,omitempty"`
 	PinataApiKey    string `json:"pinata_api_key,omitempty"`
 	PinataApiSecret string `json:"pinata_api_secret,omitempty"`
@@ -163,23 +176,14 @@ type Pinning struct {
 // LimitedUse indicates whether to include API keys with limited use in the response.
 // Exhausted indicates whether to include exhausted API keys in the response.
 // Name is a filter to only include API keys with the specified name.
-// Offset is the number of API keys to skip before returning the results.
+// Offset is the number of API keys to skip before returning the results; it is only sent when set,
+// so an explicit zero can be requested with Int(0).
 type ListApiKeysOptions struct {
 	Revoked    *bool  `json:"revoked,omitempty"`
 	LimitedUse *bool  `json:"limitedUse,omitempty"`
 	Exhausted  *bool  `json:"exhausted,omitempty"`
 	Name       string `json:"name,omitempty"`
-	Offset     int    `json:"offset,omitempty"`
-}
-
-// pinnedFileCountResponse represents the response from the Pinata API for the total count and size of pinned files.
-// PinCount is the total number of files pinned.
-// PinSizeTotal is the total size of all pinned files.
-// PinSizeWithReplicationsTotal is the total size of all pinned files including replications.
-type pinnedFileCountResponse struct {
-	PinCount                     int `json:"pin_count,omitempty"`
-	PinSizeTotal                 int `json:"pin_size_total,omitempty"`
-	PinSizeWithReplicationsTotal int `json:"pin_size_with_replications_total,omitempty"`
+	Offset     *int   `json:"offset,omitempty"`
 }
 
 // GenerateApiKey generates a new API key for the Pinata platform.
@@ -189,47 +193,24 @@ type pinnedFileCountResponse struct {
 //
 // The function returns a Secret struct containing the new API key and secret.
 // If there is an error generating the API key, an error will be returned.
-func (c *Client) GenerateApiKey(options *GenerateApiKeyOptions) (*secret, error) {
-	if options == nil {
-		return nil, fmt.Errorf("options cannot be nil")
-	}
-
-	req, err := c.NewRequest(http.MethodPost, "/users/generateApiKey").
-		SetJSONBody(options)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to set JSON body: %w", err)
-	}
-
-	var response secret
-	err = req.Send(&response)
-	if err != nil {
-		return nil, err
-	}
-
-	return &response, nil
+func (c *Client) GenerateApiKey(options *GenerateApiKeyOptions) (*ApiKeySecret, error) {
+	return c.GenerateApiKeyWithContext(context.Background(), options)
 }
 
-// GenerateApiKeyV3 generates a new API key for the Pinata platform.
-//
-// The provided GenerateApiKeyOptions struct specifies the options for the new API key, such as the name, permissions, and expiration.
-// If the options are nil, an error will be returned.
-//
-// The function returns a Secret struct containing the new API key and secret.
-// If there is an error generating the API key, an error will be returned.
-func (c *Client) GenerateApiKeyV3(options *GenerateApiKeyOptions) (*secret, error) {
+// GenerateApiKeyWithContext is like GenerateApiKey but uses ctx for the requests.
+func (c *Client) GenerateApiKeyWithContext(ctx context.Context, options *GenerateApiKeyOptions) (*ApiKeySecret, error) {
 	if options == nil {
 		return nil, fmt.Errorf("options cannot be nil")
 	}
 
-	req, err := c.NewRequest(http.MethodPost, "/v3/pinata/keys").
+	req, err := c.NewRequest(http.MethodPost, "/users/generateApiKey").WithContext(ctx).
 		SetJSONBody(options)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to set JSON body: %w", err)
 	}
 
-	var response secret
+	var response ApiKeySecret
 	err = req.Send(&response)
 	if err != nil {
 		return nil, err
@@ -238,41 +219,46 @@ func (c *Client) GenerateApiKeyV3(options *GenerateApiKeyOptions) (*secret, erro
 	return &response, nil
 }
 
+// GenerateApiKeyV3 generates a new API key with the v3 keys endpoint. It is an alias of
+// c.Keys().Generate, kept for compatibility.
+func (c *Client) GenerateApiKeyV3(options *GenerateApiKeyOptions) (*ApiKeySecret, error) {
+	return c.Keys().Generate(options)
+}
+
 // ListApiKeys returns a list of API keys associated with the current user.
 // The response includes information about each API key, such as whether it is revoked, limited use, or exhausted.
 // The options parameter can be used to filter the results by various criteria.
-func (c *Client) ListApiKeys() (*apiKeyResponse, error) {
-	var response apiKeyResponse
-	err := c.NewRequest(http.MethodGet, "/users/apiKeys").
+func (c *Client) ListApiKeys() (*ApiKeyResponse, error) {
+	return c.ListApiKeysWithContext(context.Background())
+}
+
+// ListApiKeysWithContext is like ListApiKeys but uses ctx for the requests.
+func (c *Client) ListApiKeysWithContext(ctx context.Context) (*ApiKeyResponse, error) {
+	var response ApiKeyResponse
+	err := c.NewRequest(http.MethodGet, "/users/apiKeys").WithContext(ctx).
 		Send(&response)
 
 	if err != nil {
 		return nil, err
 	}
+	c.checkKeyUsage(&response)
 	return &response, nil
 }
 
-// ListApiKeyV3 returns a list of API keys associated with the current user.
-// The response includes information about each API key, such as whether it is revoked, limited use, or exhausted.
-// The options parameter can be used to filter the results by various criteria.
-func (c *Client) ListApiKeyV3(options *ListApiKeysOptions) (*apiKeyResponse, error) {
-	req := c.NewRequest(http.MethodGet, "/v3/pinata/keys")
-	if options != nil {
-		req.setListApiKeysQueryParams(options)
-	}
-
-	var response apiKeyResponse
-	err := req.Send(&response)
-	if err != nil {
-		return nil, err
-	}
-
-	return &response, nil
+// ListApiKeyV3 lists API keys with the v3 keys endpoint. It is an alias of c.Keys().List, kept
+// for compatibility.
+func (c *Client) ListApiKeyV3(options *ListApiKeysOptions) (*ApiKeyResponse, error) {
+	return c.Keys().List(options)
 }
 
 // RevokeApiKey revokes the specified API key.
 // If the apiKey parameter is empty, an error is returned.
 func (c *Client) RevokeApiKey(apiKey string) error {
+	return c.RevokeApiKeyWithContext(context.Background(), apiKey)
+}
+
+// RevokeApiKeyWithContext is like RevokeApiKey but uses ctx for the requests.
+func (c *Client) RevokeApiKeyWithContext(ctx context.Context, apiKey string) error {
 	if apiKey == "" {
 		return fmt.Errorf("api key is required")
 	}
@@ -280,7 +266,7 @@ func (c *Client) RevokeApiKey(apiKey string) error {
 	payload := make(map[string]string)
 	payload["apiKey"] = apiKey
 
-	req, err := c.NewRequest(http.MethodPut, "/users/revokeApiKey").
+	req, err := c.NewRequest(http.MethodPut, "/users/revokeApiKey").WithContext(ctx).
 		SetJSONBody(payload)
 	if err != nil {
 		return fmt.Errorf("failed to set JSON body: %w", err)
@@ -293,46 +279,38 @@ func (c *Client) RevokeApiKey(apiKey string) error {
 	return nil
 }
 
-// RevokeApiKeyV3 revokes the specified API key.
-// The key parameter is required and must be a valid API key.
-// If the key is successfully revoked, this method returns nil. Otherwise, it returns an error.
+// RevokeApiKeyV3 revokes key with the v3 keys endpoint. It is an alias of c.Keys().Revoke, kept
+// for compatibility.
 func (c *Client) RevokeApiKeyV3(key string) error {
-	if key == "" {
-		return fmt.Errorf("key is required")
-	}
-
-	err := c.NewRequest(http.MethodPut, "/v3/pinata/keys/{key}").
-		AddPathParam("key", key).
-		Send(nil)
-
-	if err != nil {
-		return err
-	}
-	return nil
+	return c.Keys().Revoke(key)
 }
 
 // PinnedFileCount returns the total number of files pinned by the user.
 // If an error occurs while fetching the pinned file count, the error is returned.
 func (c *Client) PinnedFileCount() (int, error) {
-	var response pinnedFileCountResponse
-	err := c.NewRequest(http.MethodGet, "/data/userPinnedDataTotal").
-		Send(&response)
+	return c.PinnedFileCountWithContext(context.Background())
+}
 
+// PinnedFileCountWithContext is like PinnedFileCount but uses ctx for the requests.
+func (c *Client) PinnedFileCountWithContext(ctx context.Context) (int, error) {
+	total, err := c.GetPinnedDataTotal(ctx)
 	if err != nil {
 		return 0, err
 	}
-	return response.PinCount, nil
+	return int(total.PinCount), nil
 }
 
 // TotalStorageSize returns the total number of bytes pinned by the user and the total number of bytes pinned with replications.
 // If an error occurs while fetching the pinned file size, the error is returned.
 func (c *Client) TotalStorageSize() (int, int, error) {
-	var response pinnedFileCountResponse
-	err := c.NewRequest(http.MethodGet, "/data/userPinnedDataTotal").
-		Send(&response)
+	return c.TotalStorageSizeWithContext(context.Background())
+}
 
+// TotalStorageSizeWithContext is like TotalStorageSize but uses ctx for the requests.
+func (c *Client) TotalStorageSizeWithContext(ctx context.Context) (int, int, error) {
+	total, err := c.GetPinnedDataTotal(ctx)
 	if err != nil {
 		return 0, 0, err
 	}
-	return response.PinSizeTotal, response.PinSizeWithReplicationsTotal, nil
-}
\ No newline at end of file
+	return int(total.PinSizeTotal), int(total.PinSizeWithReplicationsTotal), nil
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -25,7 +26,7 @@ func TestGenerateApiKey(t *testing.T) {
 			require.Equal(t, "test_key", payload.KeyName)
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"pinata_api_key":"generated_api_key","pinata_api_secret":"generated_api_secret"}`))
+			w.Write(fixture(t, "POST /users/generateApiKey"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -35,8 +36,9 @@ func TestGenerateApiKey(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, secret)
-		require.Equal(t, "generated_api_key", secret.PinataApiKey)
-		require.Equal(t, "generated_api_secret", secret.PinataApiSecret)
+		require.Equal(t, "0a1b2c3d4e5f6a7b8c9d", secret.PinataApiKey)
+		require.Len(t, secret.PinataApiSecret, 64)
+		require.NotEmpty(t, secret.JWT)
 	})
 
 	t.Run("nil options", func(t *testing.T) {
@@ -103,7 +105,7 @@ func TestGenerateApiKeyV3(t *testing.T) {
 			require.Equal(t, "test_key_v3", payload.KeyName)
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"pinata_api_key":"generated_api_key_v3","pinata_api_secret":"generated_api_secret_v3"}`))
+			w.Write(fixture(t, "POST /v3/pinata/keys"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -113,8 +115,9 @@ func TestGenerateApiKeyV3(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, secret)
-		require.Equal(t, "generated_api_key_v3", secret.PinataApiKey)
-		require.Equal(t, "generated_api_secret_v3", secret.PinataApiSecret)
+		require.Equal(t, "0a1b2c3d4e5f6a7b8c9d", secret.PinataApiKey)
+		require.Len(t, secret.PinataApiSecret, 64)
+		require.NotEmpty(t, secret.JWT)
 	})
 
 	t.Run("nil options", func(t *testing.T) {
@@ -175,7 +178,7 @@ func TestListApiKeys(t *testing.T) {
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"keys": [{"key": "api_key_1"}, {"key": "api_key_2"}]}`))
+			w.Write(fixture(t, "GET /users/apiKeys"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -184,9 +187,13 @@ func TestListApiKeys(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Len(t, response.Keys, 2)
-		require.Equal(t, "api_key_1", response.Keys[0].Key)
-		require.Equal(t, "api_key_2", response.Keys[1].Key)
+		require.Len(t, response.Keys, 1)
+		key := response.Keys[0]
+		require.Equal(t, "0a1b2c3d4e5f6a7b8c9d", key.Key)
+		require.Equal(t, 97, key.RemainingUses())
+		require.True(t, key.Scopes.Endpoints.Pinning.PinFileToIPFS)
+		require.False(t, key.Scopes.Admin)
+		require.Equal(t, time.Date(2024, 5, 21, 19, 27, 3, 391000000, time.UTC), key.CreatedAt)
 	})
 
 	t.Run("empty API key list", func(t *testing.T) {
@@ -269,22 +276,21 @@ func TestListApiKeyV3(t *testing.T) {
 			require.Equal(t, "20", r.URL.Query().Get("offset"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"keys": [{"key": "api_key_1"}, {"key": "api_key_2"}], "count": 2}`))
+			w.Write(fixture(t, "GET /v3/pinata/keys"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
 
 		options := &ListApiKeysOptions{
-			Offset: 20,
+			Offset: Int(20),
 		}
 		response, err := client.ListApiKeyV3(options)
 
 		require.NoError(t, err)
 		require.NotNil(t, response)
-		require.Len(t, response.Keys, 2)
-		require.Equal(t, "api_key_1", response.Keys[0].Key)
-		require.Equal(t, "api_key_2", response.Keys[1].Key)
-		require.Equal(t, 2, response.Count)
+		require.Len(t, response.Keys, 1)
+		require.Equal(t, "0a1b2c3d4e5f6a7b8c9d", response.Keys[0].Key)
+		require.Equal(t, 1, response.Count)
 	})
 
 	t.Run("successful API key listing without options", func(t *testing.T) {
@@ -297,7 +303,7 @@ func TestListApiKeyV3(t *testing.T) {
 			require.Empty(t, r.URL.Query().Get("offset"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"keys": [{"key": "api_key_1"}], "count": 1}`))
+			w.Write(fixture(t, "GET /v3/pinata/keys"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -307,7 +313,11 @@ func TestListApiKeyV3(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, response)
 		require.Len(t, response.Keys, 1)
-		require.Equal(t, "api_key_1", response.Keys[0].Key)
+		key := response.Keys[0]
+		require.Equal(t, "0a1b2c3d4e5f6a7b8c9d", key.Key)
+		require.Equal(t, "ci", key.Name)
+		require.False(t, key.Revoked)
+		require.Equal(t, time.Date(2024, 5, 21, 19, 27, 3, 391000000, time.UTC), key.UpdatedAt)
 		require.Equal(t, 1, response.Count)
 	})
 
@@ -523,7 +533,7 @@ func TestPinnedFileCount(t *testing.T) {
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"pin_count": 42}`))
+			w.Write(fixture(t, "GET /data/userPinnedDataTotal"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -531,7 +541,7 @@ func TestPinnedFileCount(t *testing.T) {
 		count, err := client.PinnedFileCount()
 
 		require.NoError(t, err)
-		require.Equal(t, 42, count)
+		require.Equal(t, 1, count)
 	})
 
 	t.Run("server error", func(t *testing.T) {
@@ -607,7 +617,7 @@ func TestTotalStorageSize(t *testing.T) {
 			require.Equal(t, "Bearer valid_jwt_token", r.Header.Get("Authorization"))
 
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"pin_size_total": 1000, "pin_size_with_replications_total": 2000}`))
+			w.Write(fixture(t, "GET /data/userPinnedDataTotal"))
 		}))
 		defer mockServer.Close()
 		client.baseURL = mockServer.URL
@@ -615,8 +625,8 @@ func TestTotalStorageSize(t *testing.T) {
 		pinSizeTotal, pinSizeWithReplicationsTotal, err := client.TotalStorageSize()
 
 		require.NoError(t, err)
-		require.Equal(t, 1000, pinSizeTotal)
-		require.Equal(t, 2000, pinSizeWithReplicationsTotal)
+		require.Equal(t, 13, pinSizeTotal)
+		require.Equal(t, 26, pinSizeWithReplicationsTotal)
 	})
 
 	t.Run("server error", func(t *testing.T) {
@@ -684,3 +694,22 @@ func TestTotalStorageSize(t *testing.T) {
 		require.Equal(t, 0, pinSizeWithReplicationsTotal)
 	})
 }
+
+func TestRemainingUses(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      ApiKey
+		expected int
+	}{
+		{"unlimited", ApiKey{MaxUses: 0, Uses: 12}, -1},
+		{"unused", ApiKey{MaxUses: 10}, 10},
+		{"partly used", ApiKey{MaxUses: 10, Uses: 7}, 3},
+		{"exhausted", ApiKey{MaxUses: 10, Uses: 10}, 0},
+		{"overused", ApiKey{MaxUses: 10, Uses: 11}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.key.RemainingUses())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package pinata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxGroupNameLength is the default maximum length, in characters, of a group name.
+	DefaultMaxGroupNameLength = 255
+	// DefaultMaxMetadataNameLength is the default maximum length, in characters, of a pinataMetadata name.
+	DefaultMaxMetadataNameLength = 255
+)
+
+// ErrInvalidName is returned when a group name or metadata name fails client-side validation.
+var ErrInvalidName = errors.New("invalid name")
+
+// ErrInvalidDateRange is returned when a date range of ListFilesOptions ends before it starts.
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// ErrInvalidMetadata is returned when a metadata filter holds a value that cannot be encoded, such
+// as a channel, a func or NaN.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// WithNameLimits overrides the maximum lengths, in characters, enforced for group names and
+// pinataMetadata names. A value of zero keeps the corresponding default.
+func WithNameLimits(maxGroupName, maxMetadataName int) ClientOption {
+	return func(c *Client) {
+		c.maxGroupNameLength = maxGroupName
+		c.maxMetadataNameLength = maxMetadataName
+	}
+}
+
+// normalizeName trims surrounding whitespace from name and validates it against maxLength.
+// Names that are empty after trimming or that contain control characters are rejected.
+func normalizeName(kind, name string, maxLength int) (string, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrInvalidName, kind)
+	}
+	if strings.IndexFunc(trimmed, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("%w: %s contains control characters", ErrInvalidName, kind)
+	}
+	if n := utf8.RuneCountInString(trimmed); n > maxLength {
+		return "", fmt.Errorf("%w: %s is %d characters long, the maximum is %d", ErrInvalidName, kind, n, maxLength)
+	}
+	return trimmed, nil
+}
+
+// normalizeGroupName validates a group name against the client's group name limit.
+func (c *Client) normalizeGroupName(name string) (string, error) {
+	limit := c.maxGroupNameLength
+	if limit <= 0 {
+		limit = DefaultMaxGroupNameLength
+	}
+	return normalizeName("group name", name, limit)
+}
+
+// normalizeMetadataName validates a pinataMetadata name against the client's metadata name limit.
+// An empty name is valid since metadata names are optional.
+func (c *Client) normalizeMetadataName(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	limit := c.maxMetadataNameLength
+	if limit <= 0 {
+		limit = DefaultMaxMetadataNameLength
+	}
+	return normalizeName("metadata name", name, limit)
+}
+
+// normalizePinOptions returns a deep copy of options with a validated, trimmed metadata name.
+// The caller's options are never modified.
+func (c *Client) normalizePinOptions(options *PinOptions) (*PinOptions, error) {
+	if options == nil {
+		return nil, nil
+	}
+	name, err := c.normalizeMetadataName(options.PinataMetadata.Name)
+	if err != nil {
+		return nil, err
+	}
+	normalized := options.Clone()
+	normalized.PinataMetadata.Name = name
+	return normalized, nil
+}
+
+// Validate checks that the pin and unpin date ranges of the options are not inverted.
+func (o *ListFilesOptions) Validate() error {
+	if o.PinStart != nil && o.PinEnd != nil && o.PinEnd.Before(*o.PinStart) {
+		return fmt.Errorf("%w: pinEnd %s is before pinStart %s", ErrInvalidDateRange,
+			o.PinEnd.Format(time.RFC3339), o.PinStart.Format(time.RFC3339))
+	}
+	if o.UnpinStart != nil && o.UnpinEnd != nil && o.UnpinEnd.Before(*o.UnpinStart) {
+		return fmt.Errorf("%w: unpinEnd %s is before unpinStart %s", ErrInvalidDateRange,
+			o.UnpinEnd.Format(time.RFC3339), o.UnpinStart.Format(time.RFC3339))
+	}
+	return nil
+}
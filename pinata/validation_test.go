@@ -0,0 +1,198 @@
+package pinata
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	t.Run("at limit", func(t *testing.T) {
+		name, err := normalizeName("group name", strings.Repeat("a", 10), 10)
+
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("a", 10), name)
+	})
+
+	t.Run("limit plus one", func(t *testing.T) {
+		name, err := normalizeName("group name", strings.Repeat("a", 11), 10)
+
+		require.ErrorIs(t, err, ErrInvalidName)
+		require.Empty(t, name)
+		require.Contains(t, err.Error(), "11 characters long, the maximum is 10")
+	})
+
+	t.Run("counts characters rather than bytes", func(t *testing.T) {
+		name, err := normalizeName("group name", strings.Repeat("é", 10), 10)
+
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("é", 10), name)
+	})
+
+	t.Run("trims surrounding whitespace before checking the limit", func(t *testing.T) {
+		name, err := normalizeName("group name", "  "+strings.Repeat("a", 10)+"\n", 10)
+
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("a", 10), name)
+	})
+
+	t.Run("empty after trim", func(t *testing.T) {
+		name, err := normalizeName("group name", " \t\n ", 10)
+
+		require.ErrorIs(t, err, ErrInvalidName)
+		require.Empty(t, name)
+		require.Contains(t, err.Error(), "group name is empty")
+	})
+
+	t.Run("control characters", func(t *testing.T) {
+		name, err := normalizeName("metadata name", "bad\x00name", 10)
+
+		require.ErrorIs(t, err, ErrInvalidName)
+		require.Empty(t, name)
+		require.Contains(t, err.Error(), "control characters")
+	})
+}
+
+func TestNameValidation(t *testing.T) {
+	t.Run("create group trims name", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			require.Equal(t, "My Group", payload["name"])
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id":"group_id","name":"My Group"}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		group, err := client.CreateGroup("  My Group  ")
+
+		require.NoError(t, err)
+		require.Equal(t, "My Group", group.GroupName)
+	})
+
+	t.Run("create group over default limit", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		group, err := client.CreateGroup(strings.Repeat("g", DefaultMaxGroupNameLength+1))
+
+		require.ErrorIs(t, err, ErrInvalidName)
+		require.Nil(t, group)
+	})
+
+	t.Run("update group with whitespace name", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+
+		group, err := client.UpdateGroup("group_id", "   ")
+
+		require.ErrorIs(t, err, ErrInvalidName)
+		require.Nil(t, group)
+	})
+
+	t.Run("overridden limits", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithNameLimits(5, 3))
+
+		_, err := client.UpdateGroup("group_id", "abcdef")
+		require.ErrorIs(t, err, ErrInvalidName)
+
+		_, err = client.PinJSON(map[string]string{"k": "v"}, &PinOptions{PinataMetadata: PinataMetadata{Name: "abcd"}})
+		require.ErrorIs(t, err, ErrInvalidName)
+
+		err = client.UpdateFileMetadata("QmHash", &PinMetadataUpdateOptions{Name: "abcd"})
+		require.ErrorIs(t, err, ErrInvalidName)
+
+		_, err = client.PinByCid("QmHash", &PinByCidOptions{PinataMetadata: PinataMetadata{Name: "abcd"}})
+		require.ErrorIs(t, err, ErrInvalidName)
+	})
+
+	t.Run("metadata name is trimmed without modifying options", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				PinataMetadata PinataMetadata `json:"pinataMetadata"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			require.Equal(t, "doc.json", payload.PinataMetadata.Name)
+
+			w.WriteHeader(http.StatusOK)
+			serveFixture(w, r)
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		options := &PinOptions{PinataMetadata: PinataMetadata{Name: " doc.json "}}
+		_, err := client.PinJSON(map[string]string{"k": "v"}, options)
+
+		require.NoError(t, err)
+		require.Equal(t, " doc.json ", options.PinataMetadata.Name)
+	})
+}
+
+func TestListFilesOptionsValidate(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("valid ranges", func(t *testing.T) {
+		require.NoError(t, (&ListFilesOptions{}).Validate())
+		require.NoError(t, (&ListFilesOptions{}).WithPinnedBetween(early, late).Validate())
+		require.NoError(t, (&ListFilesOptions{}).WithUnpinnedBetween(early, early).Validate())
+		require.NoError(t, (&ListFilesOptions{}).WithPinnedBetween(late, time.Time{}).Validate())
+	})
+
+	t.Run("inverted ranges", func(t *testing.T) {
+		err := (&ListFilesOptions{}).WithPinnedBetween(late, early).Validate()
+		require.ErrorIs(t, err, ErrInvalidDateRange)
+
+		err = (&ListFilesOptions{}).WithUnpinnedBetween(late, early).Validate()
+		require.ErrorIs(t, err, ErrInvalidDateRange)
+	})
+
+	t.Run("rejected before sending", func(t *testing.T) {
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = "http://invalid.invalid"
+
+		_, err := client.ListFiles((&ListFilesOptions{}).WithPinnedBetween(late, early))
+		require.ErrorIs(t, err, ErrInvalidDateRange)
+	})
+}
+
+func TestListFilesInvalidMetadata(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"count":0,"rows":[]}`))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"})
+	client.baseURL = mockServer.URL
+
+	t.Run("NaN value", func(t *testing.T) {
+		_, err := client.ListFiles(&ListFilesOptions{Metadata: map[string]interface{}{"score": math.NaN()}})
+		require.ErrorIs(t, err, ErrInvalidMetadata)
+	})
+
+	t.Run("nested NaN value", func(t *testing.T) {
+		_, err := client.ListFilesAll(&ListFilesOptions{
+			Metadata: map[string]interface{}{"keyvalues": map[string]interface{}{"score": map[string]interface{}{"value": math.NaN(), "op": "gt"}}},
+		})
+		require.ErrorIs(t, err, ErrInvalidMetadata)
+	})
+
+	t.Run("channel value", func(t *testing.T) {
+		_, err := client.ListFilesPage(&ListFilesOptions{Metadata: map[string]interface{}{"ch": make(chan int)}})
+		require.ErrorIs(t, err, ErrInvalidMetadata)
+	})
+
+	require.Zero(t, requests)
+}
@@ -0,0 +1,104 @@
+package pinata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+func init() {
+	registerCapability(CapabilityVectors)
+}
+
+// VectorMatch is a file matching a vector query.
+// FileID is the v3 ID of the file.
+// Cid is the CID of the file.
+// Score is the similarity of the file to the query text; higher scores are closer matches.
+type VectorMatch struct {
+	FileID string  `json:"file_id"`
+	Cid    string  `json:"cid"`
+	Score  float64 `json:"score"`
+}
+
+// VectorQueryResult is the result of a vector query.
+// Count is the number of matches found by the API, before any topK limit.
+// Matches are the matching files, best match first.
+type VectorQueryResult struct {
+	Count   int           `json:"count"`
+	Matches []VectorMatch `json:"matches"`
+}
+
+// vectorStatusResponse is the response of the vectorize and vector deletion endpoints.
+type vectorStatusResponse struct {
+	Status bool `json:"status"`
+}
+
+// vectorQueryResponse is the response of the vector query endpoint.
+type vectorQueryResponse struct {
+	Data VectorQueryResult `json:"data"`
+}
+
+// VectorizeFile creates the vector embeddings of the file with the v3 file ID fileID, so it is
+// matched by QueryVectors on the groups the file belongs to. The file must be in a group.
+func (c *Client) VectorizeFile(ctx context.Context, fileID string) error {
+	return c.sendVectorStatus(ctx, http.MethodPost, fileID)
+}
+
+// DeleteFileVectors deletes the vector embeddings of the file with the v3 file ID fileID. The file
+// itself stays pinned.
+func (c *Client) DeleteFileVectors(ctx context.Context, fileID string) error {
+	return c.sendVectorStatus(ctx, http.MethodDelete, fileID)
+}
+
+// sendVectorStatus sends a request to the vectorize endpoint of fileID and fails when the API does
+// not report success.
+func (c *Client) sendVectorStatus(ctx context.Context, method, fileID string) error {
+	if fileID == "" {
+		return fmt.Errorf("fileID is required")
+	}
+
+	var response vectorStatusResponse
+	err := c.NewRequest(method, "/v3/vectorize/files/{id}").
+		WithContext(ctx).
+		AddPathParam("id", fileID).
+		Send(&response)
+	if err != nil {
+		return err
+	}
+	if !response.Status {
+		return fmt.Errorf("vectorize request for file %s was not accepted", fileID)
+	}
+	return nil
+}
+
+// QueryVectors searches the vectorized files of the group with the v3 group ID groupID for the
+// files closest to text. The matches are sorted by decreasing score and limited to the topK best;
+// a topK of zero or less returns every match.
+func (c *Client) QueryVectors(ctx context.Context, groupID string, text string, topK int) (*VectorQueryResult, error) {
+	if groupID == "" || text == "" {
+		return nil, fmt.Errorf("groupID and text are required")
+	}
+
+	req, err := c.NewRequest(http.MethodPost, "/v3/vectorize/groups/{id}/query").
+		WithContext(ctx).
+		AddPathParam("id", groupID).
+		SetJSONBody(map[string]string{"text": text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set JSON body: %w", err)
+	}
+
+	var response vectorQueryResponse
+	if err := req.Send(&response); err != nil {
+		return nil, err
+	}
+
+	result := response.Data
+	sort.SliceStable(result.Matches, func(i, j int) bool {
+		return result.Matches[i].Score > result.Matches[j].Score
+	})
+	if topK > 0 && len(result.Matches) > topK {
+		result.Matches = result.Matches[:topK]
+	}
+	return &result, nil
+}
@@ -0,0 +1,95 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vectorQueryFixture is a recorded response of the vector query endpoint.
+const vectorQueryFixture = `{
+	"data": {
+		"count": 3,
+		"matches": [
+			{"file_id": "0192a3a2-7e1f-7c1a-9c3b-2f1c5d2e8a01", "cid": "bafkreih5aznjvttude6c3wbvqeebb6rlx5wkbzyppv7garjiubll2ceym4", "score": 0.61},
+			{"file_id": "0192a3a2-7e1f-7c1a-9c3b-2f1c5d2e8a02", "cid": "bafkreiapgrp2jatc2xzdmf6hgyqxowqchshgnmhsvkyrhf5z2u2bz4tycm", "score": 0.87},
+			{"file_id": "0192a3a2-7e1f-7c1a-9c3b-2f1c5d2e8a03", "cid": "bafkreidivzimqfqtoqxkrpge6bjyhlvxqs3rhe73owtmdulaxr5do5in7u", "score": 0.42}
+		]
+	}
+}`
+
+func TestVectors(t *testing.T) {
+	newClient := func(t *testing.T, handler http.HandlerFunc) *Client {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		client := New(NewAuthWithJWT("test_token"), WithProfile(Profile{UploadURL: server.URL}))
+		return client
+	}
+
+	t.Run("vectorize file", func(t *testing.T) {
+		var requests []string
+		client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			serveFixture(w, r)
+		})
+
+		require.NoError(t, client.VectorizeFile(context.Background(), "file-id"))
+		require.NoError(t, client.DeleteFileVectors(context.Background(), "file-id"))
+		require.Equal(t, []string{"POST /v3/vectorize/files/file-id", "DELETE /v3/vectorize/files/file-id"}, requests)
+	})
+
+	t.Run("vectorize rejected", func(t *testing.T) {
+		client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"status": false}`))
+		})
+
+		err := client.VectorizeFile(context.Background(), "file-id")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "was not accepted")
+	})
+
+	t.Run("query", func(t *testing.T) {
+		var body map[string]interface{}
+		client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/v3/vectorize/groups/group-id/query", r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.Write([]byte(vectorQueryFixture))
+		})
+
+		result, err := client.QueryVectors(context.Background(), "group-id", "invoices from May", 2)
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"text": "invoices from May"}, body)
+		require.Equal(t, 3, result.Count)
+		require.Equal(t, []VectorMatch{
+			{FileID: "0192a3a2-7e1f-7c1a-9c3b-2f1c5d2e8a02", Cid: "bafkreiapgrp2jatc2xzdmf6hgyqxowqchshgnmhsvkyrhf5z2u2bz4tycm", Score: 0.87},
+			{FileID: "0192a3a2-7e1f-7c1a-9c3b-2f1c5d2e8a01", Cid: "bafkreih5aznjvttude6c3wbvqeebb6rlx5wkbzyppv7garjiubll2ceym4", Score: 0.61},
+		}, result.Matches)
+
+		result, err = client.QueryVectors(context.Background(), "group-id", "invoices from May", 0)
+		require.NoError(t, err)
+		require.Len(t, result.Matches, 3)
+	})
+
+	t.Run("required arguments", func(t *testing.T) {
+		client := New(NewAuthWithJWT("test_token"))
+
+		require.Error(t, client.VectorizeFile(context.Background(), ""))
+		require.Error(t, client.DeleteFileVectors(context.Background(), ""))
+		_, err := client.QueryVectors(context.Background(), "group-id", "", 5)
+		require.Error(t, err)
+	})
+
+	t.Run("uploads host", func(t *testing.T) {
+		client := New(NewAuthWithJWT("test_token"))
+
+		require.Equal(t, UploadsURL, client.hostFor("/v3/vectorize/files/{id}"))
+		require.Equal(t, UploadsURL, client.hostFor("/v3/vectorize/groups/{id}/query"))
+		require.Equal(t, BaseURL, client.hostFor("/pinning/pinFileToIPFS"))
+	})
+}
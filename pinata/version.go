@@ -0,0 +1,154 @@
+package pinata
+
+import (
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// modulePath is the module path of the SDK, used to find its version in the build information.
+const modulePath = "github.com/zde37/pinata-go-sdk"
+
+// version is the version of the SDK reported when the build information does not carry one, as
+// for builds of the SDK repository itself. Release builds may set it with
+// -ldflags "-X github.com/zde37/pinata-go-sdk/pinata.version=v1.2.3".
+var version = "v0.0.0-devel"
+
+// Capabilities reported by Capabilities. Each is registered by the file implementing the feature.
+const (
+	// CapabilityActiveOperations is the registry of operations in flight reported by
+	// ActiveOperations.
+	CapabilityActiveOperations = "active-operations"
+	// CapabilityAuthFallback is the fallback from a rejected JWT to the API key configured with
+	// WithAuthFallback.
+	CapabilityAuthFallback = "auth-fallback"
+	// CapabilityBatch is PinBatch and PinDirectory.
+	CapabilityBatch = "batch"
+	// CapabilityCheckpoints is resuming batches from a CheckpointStore.
+	CapabilityCheckpoints = "checkpoints"
+	// CapabilityConnTrace is the connection tracing configured with WithConnTrace.
+	CapabilityConnTrace = "conn-trace"
+	// CapabilityGateway is the GatewayClient returned by Client.Gateway.
+	CapabilityGateway = "gateway"
+	// CapabilityGroups is the groups API.
+	CapabilityGroups = "groups"
+	// CapabilityInterceptors is the request and response interceptors added with
+	// WithRequestInterceptor and WithResponseInterceptor.
+	CapabilityInterceptors = "interceptors"
+	// CapabilityLargeDirectory is PinLargeDirectory.
+	CapabilityLargeDirectory = "large-directory"
+	// CapabilityNameSearch is SearchFilesByName.
+	CapabilityNameSearch = "name-search"
+	// CapabilityPerCidLocking is the serialization of mutations of a CID configured with
+	// WithPerCidLocking.
+	CapabilityPerCidLocking = "per-cid-locking"
+	// CapabilityQuotaGuard is the storage quota check configured with WithQuotaGuard.
+	CapabilityQuotaGuard = "quota-guard"
+	// CapabilityReadCache is the read cache configured with WithReadCache.
+	CapabilityReadCache = "read-cache"
+	// CapabilityReaderSpool is PinReader and the spooling of streams of unknown length.
+	CapabilityReaderSpool = "reader-spool"
+	// CapabilityRequestLog is the logging of API requests with the logger set with WithLogger.
+	CapabilityRequestLog = "request-log"
+	// CapabilityRequestSigning is the request signing configured with WithRequestSigner.
+	CapabilityRequestSigning = "request-signing"
+	// CapabilityRetryPolicy is the retries of API requests configured with WithRetryPolicy.
+	CapabilityRetryPolicy = "retry-policy"
+	// CapabilitySignatures is the CID signatures API.
+	CapabilitySignatures = "signatures"
+	// CapabilityStreaming is the channel listings, such as StreamFiles.
+	CapabilityStreaming = "streaming"
+	// CapabilitySwaps is the hot swaps API.
+	CapabilitySwaps = "swaps"
+	// CapabilityTimeoutPolicy is the time limits derived from payload sizes configured with
+	// WithTimeoutPolicy.
+	CapabilityTimeoutPolicy = "timeout-policy"
+	// CapabilityUngroupedPins is ListUngroupedPins.
+	CapabilityUngroupedPins = "ungrouped-pins"
+	// CapabilityV3AccessLinks is the access links to private v3 files.
+	CapabilityV3AccessLinks = "v3-access-links"
+	// CapabilityV3Files is the FilesClient returned by Client.Files.
+	CapabilityV3Files = "v3-files"
+	// CapabilityV3Keys is the KeysClient returned by Client.Keys.
+	CapabilityV3Keys = "v3-keys"
+	// CapabilityVectors is file vectorization and vector queries.
+	CapabilityVectors = "vectors"
+)
+
+var (
+	capabilitiesMu sync.Mutex
+	capabilities   = map[string]bool{}
+)
+
+// registerCapability adds name to the capabilities reported by Capabilities. It is called from the
+// init functions of the files implementing each feature.
+func registerCapability(name string) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	capabilities[name] = true
+}
+
+// Version returns the version of the SDK, such as "v1.2.3". It is the module version recorded in
+// the build information of the program when the SDK is a dependency, and a development version
+// otherwise.
+func Version() string {
+	return buildVersion()
+}
+
+// buildVersion reads the version of the SDK from the build information once, since it is sent in
+// the User-Agent of every request.
+var buildVersion = sync.OnceValue(func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path != modulePath {
+				continue
+			}
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			if dep.Version != "" && dep.Version != "(devel)" {
+				return dep.Version
+			}
+		}
+	}
+	return version
+})
+
+// Capabilities returns the names of the features supported by this build of the SDK, such as
+// CapabilityV3Files, sorted. Tools can check for a name to decide which code path to take.
+func Capabilities() []string {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	names := make([]string, 0, len(capabilities))
+	for name := range capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// userAgent returns the User-Agent sent with every request of the SDK.
+func userAgent() string {
+	return "pinata-go-sdk/" + Version()
+}
+
+// WithUserAgentSuffix appends suffix, such as "my-app/1.4.0", to the User-Agent sent with every
+// request of the client, so that its traffic can be told apart in Pinata's logs and in proxies. The
+// User-Agent becomes "pinata-go-sdk/<version> <suffix>". A User-Agent added to a request builder with
+// AddHeaders still replaces the whole header.
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.userAgentSuffix = strings.TrimSpace(suffix)
+	}
+}
+
+// setUserAgent sets the User-Agent of req to the SDK's, followed by the suffix of c if any.
+func (c *Client) setUserAgent(req *http.Request) {
+	agent := userAgent()
+	if c.userAgentSuffix != "" {
+		agent += " " + c.userAgentSuffix
+	}
+	req.Header.Set("User-Agent", agent)
+}
@@ -0,0 +1,88 @@
+package pinata
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	require.NotEmpty(t, Version())
+	require.True(t, strings.HasPrefix(Version(), "v"), Version())
+	require.Equal(t, "pinata-go-sdk/"+Version(), userAgent())
+}
+
+func TestCapabilities(t *testing.T) {
+	capabilities := Capabilities()
+	require.NotEmpty(t, capabilities)
+	require.True(t, sort.StringsAreSorted(capabilities), "capabilities are not sorted: %v", capabilities)
+	require.Contains(t, capabilities, CapabilityV3Files)
+	require.Contains(t, capabilities, CapabilityBatch)
+
+	capabilities[0] = "changed"
+	require.NotEqual(t, "changed", Capabilities()[0])
+}
+
+func TestUserAgent(t *testing.T) {
+	var agents []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		w.Write(fixture(t, "GET /data/testAuthentication"))
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(mockServer.URL))
+	client.baseURL = mockServer.URL
+
+	_, err := client.TestAuthentication()
+	require.NoError(t, err)
+	_, err = client.StatContent(context.Background(), cidV0)
+	require.NoError(t, err)
+	require.NoError(t, client.NewRequest(http.MethodGet, "/data/testAuthentication").
+		AddHeaders("User-Agent", "custom/1.0").
+		Send(nil))
+
+	require.Equal(t, []string{userAgent(), userAgent(), "custom/1.0"}, agents)
+}
+
+func TestLoggerVersion(t *testing.T) {
+	var logs bytes.Buffer
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	client.warn(Warning{Code: WarningPageLimitClamped, Message: "clamped"})
+	require.Contains(t, logs.String(), "sdk_version="+Version())
+}
+
+func TestUserAgentSuffix(t *testing.T) {
+	var agents []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Method+" "+r.Header.Get("User-Agent"))
+		serveFixture(w, r)
+	}))
+	defer mockServer.Close()
+
+	client := New(&Auth{jwt: "valid_jwt_token"}, WithUserAgentSuffix(" my-app/1.4.0 "))
+	client.baseURL = mockServer.URL
+	client.uploadURL = mockServer.URL
+
+	_, err := client.PinFile(filepath.Join(writeTree(t, "a.txt"), "a.txt"), nil)
+	require.NoError(t, err)
+	_, err = client.PinJSON(map[string]string{"a": "b"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteFile(cidV0))
+	require.NoError(t, client.NewRequest(http.MethodDelete, "/pinning/unpin/{cid}").
+		AddPathParam("cid", cidV0).
+		AddHeaders("User-Agent", "custom/1.0").
+		Send(nil))
+
+	agent := userAgent() + " my-app/1.4.0"
+	require.Equal(t, []string{"POST " + agent, "POST " + agent, "DELETE " + agent, "DELETE custom/1.0"}, agents)
+}
@@ -0,0 +1,109 @@
+package pinata
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// WarningCode identifies the kind of a Warning.
+type WarningCode string
+
+const (
+	// WarningPageLimitClamped is emitted when a page size above the server cap is lowered to the cap.
+	WarningPageLimitClamped WarningCode = "page_limit_clamped"
+	// WarningFileSkipped is emitted when a directory walk skips a symbolic link or another
+	// non-regular file.
+	WarningFileSkipped WarningCode = "file_skipped"
+	// WarningDuplicatePin is emitted when Pinata reports an upload as a duplicate of existing
+	// content, in which case the metadata sent with the upload is not applied.
+	WarningDuplicatePin WarningCode = "duplicate_pin"
+	// WarningUnknownRegion is reported for a region ID that is not one of the documented regions.
+	WarningUnknownRegion WarningCode = "unknown_region"
+	// WarningGatewayFallback is emitted when a gateway failed and the request moved on to the next one.
+	WarningGatewayFallback WarningCode = "gateway_fallback"
+	// WarningGatewayRetry is emitted when a gateway request is retried after a 429, a 5xx status or
+	// connection errors, according to the gateway retry policy.
+	WarningGatewayRetry WarningCode = "gateway_retry"
+	// WarningRequestRetry is emitted when an API request is retried, according to the retry policy
+	// set with WithRetryPolicy.
+	WarningRequestRetry WarningCode = "request_retry"
+	// WarningJWTRejected is emitted when the JWT of the client was rejected and the request is
+	// retried with its API key, as set with WithAuthFallback.
+	WarningJWTRejected WarningCode = "jwt_rejected"
+	// WarningCacheRefreshFailed is emitted when the background refresh of a stale value of the read
+	// cache set with WithReadCache failed. The stale value was served.
+	WarningCacheRefreshFailed WarningCode = "cache_refresh_failed"
+	// WarningKeyUsesLow is emitted when a listing shows that the client's own limited-use API key has
+	// no more uses left than the threshold set with WithKeyUsageWarning.
+	WarningKeyUsesLow WarningCode = "key_uses_low"
+	// WarningAttemptsRace is emitted when the attempt count read back after an update differs from
+	// the count written, because another upload of the same content updated it concurrently.
+	WarningAttemptsRace WarningCode = "attempts_race"
+	// WarningAttemptsNotRecorded is emitted when the attempt count of a duplicate upload could not be
+	// read or updated. The upload itself succeeded.
+	WarningAttemptsNotRecorded WarningCode = "attempts_not_recorded"
+	// WarningPollReconnected is emitted when the connection of a poll of a polling helper, such as
+	// WatchPinJobs, was dropped and the poll is made again on a new connection.
+	WarningPollReconnected WarningCode = "poll_reconnected"
+)
+
+// Warning describes a non-fatal condition encountered while serving a call.
+// Code identifies the kind of warning.
+// Message is a human readable description.
+// Detail holds structured context, such as the affected path or the requested page size.
+type Warning struct {
+	Code    WarningCode            `json:"code"`
+	Message string                 `json:"message"`
+	Detail  map[string]interface{} `json:"detail,omitempty"`
+}
+
+// WithWarningHandler sets a callback that observes every Warning emitted by the client. The
+// handler may be called concurrently by calls running in parallel, such as batch uploads.
+// Warnings are also returned on the results of the calls that produced them, where the result
+// type has a Warnings field.
+func WithWarningHandler(handler func(Warning)) ClientOption {
+	return func(c *Client) {
+		c.onWarning = handler
+	}
+}
+
+// warn reports w to the warning handler and to the logger, when they are set.
+func (c *Client) warn(w Warning) {
+	if c == nil {
+		return
+	}
+	if c.onWarning != nil {
+		c.onWarning(w)
+	}
+	if c.logger != nil {
+		keys := make([]string, 0, len(w.Detail))
+		for k := range w.Detail {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		args := []any{slog.String("code", string(w.Code)), slog.String("sdk_version", Version())}
+		for _, k := range keys {
+			args = append(args, slog.Any(k, w.Detail[k]))
+		}
+		c.logger.Warn(w.Message, args...)
+	}
+}
+
+// warnDuplicate emits WarningDuplicatePin when response reports a duplicate upload that carried
+// metadata, and records the warning on response.
+func (c *Client) warnDuplicate(response *PinResponse, options *PinOptions) {
+	if !response.IsDuplicate || options == nil {
+		return
+	}
+	if options.PinataMetadata.Name == "" && len(options.PinataMetadata.KeyValues) == 0 {
+		return
+	}
+	w := Warning{
+		Code:    WarningDuplicatePin,
+		Message: "content is already pinned, the upload metadata was not applied",
+		Detail:  map[string]interface{}{"cid": response.IpfsHash},
+	}
+	response.Warnings = append(response.Warnings, w)
+	c.warn(w)
+}
@@ -0,0 +1,145 @@
+package pinata
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// warningRecorder collects the warnings reported to a client's warning handler.
+type warningRecorder struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+func (r *warningRecorder) handle(w Warning) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warnings = append(r.warnings, w)
+}
+
+func (r *warningRecorder) codes() []WarningCode {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	codes := make([]WarningCode, len(r.warnings))
+	for i, w := range r.warnings {
+		codes[i] = w.Code
+	}
+	return codes
+}
+
+func TestWarnings(t *testing.T) {
+	t.Run("clamped page size", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"count":0,"rows":[]}`))
+		}))
+		defer mockServer.Close()
+
+		var logs bytes.Buffer
+		recorder := &warningRecorder{}
+		client := New(&Auth{jwt: "valid_jwt_token"},
+			WithWarningHandler(recorder.handle),
+			WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+		client.baseURL = mockServer.URL
+
+		page, err := client.ListFilesPage(&ListFilesOptions{PageLimit: 5000})
+
+		require.NoError(t, err)
+		require.Len(t, page.Warnings, 1)
+		require.Equal(t, WarningPageLimitClamped, page.Warnings[0].Code)
+		require.Equal(t, 5000, page.Warnings[0].Detail["requested"])
+		require.Equal(t, []WarningCode{WarningPageLimitClamped}, recorder.codes())
+		require.Contains(t, logs.String(), "code=page_limit_clamped")
+	})
+
+	t.Run("skipped files in directory walk", func(t *testing.T) {
+		dir := writeTree(t, "a.txt", "docs/b.md")
+		require.NoError(t, os.Symlink(filepath.Join(dir, "a.txt"), filepath.Join(dir, "link.txt")))
+
+		recorder := &metadataRecorder{}
+		mockServer := httptest.NewServer(recorder.handler(t))
+		defer mockServer.Close()
+
+		warnings := &warningRecorder{}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithWarningHandler(warnings.handle))
+		client.baseURL = mockServer.URL
+
+		results, err := client.PinDirectory(context.Background(), dir, &BatchOptions{NameTemplate: "{{.Rel}}"})
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, []WarningCode{WarningFileSkipped}, warnings.codes())
+		require.Equal(t, filepath.Join(dir, "link.txt"), warnings.warnings[0].Detail["path"])
+	})
+
+	t.Run("metadata of duplicate pin", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"IpfsHash":"QmDuplicate","PinSize":10,"Timestamp":"2023-05-01T12:00:00Z","isDuplicate":true}`))
+		}))
+		defer mockServer.Close()
+
+		recorder := &warningRecorder{}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithWarningHandler(recorder.handle))
+		client.baseURL = mockServer.URL
+
+		dir := writeTree(t, "a.txt")
+		response, err := client.PinFile(filepath.Join(dir, "a.txt"), &PinOptions{PinataMetadata: PinataMetadata{Name: "renamed"}})
+
+		require.NoError(t, err)
+		require.True(t, response.IsDuplicate)
+		require.Len(t, response.Warnings, 1)
+		require.Equal(t, WarningDuplicatePin, response.Warnings[0].Code)
+		require.Equal(t, "QmDuplicate", response.Warnings[0].Detail["cid"])
+		require.Equal(t, []WarningCode{WarningDuplicatePin}, recorder.codes())
+
+		_, err = client.PinFile(filepath.Join(dir, "a.txt"), nil)
+		require.NoError(t, err)
+		require.Len(t, recorder.codes(), 1)
+	})
+
+	t.Run("gateway fallback", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer failing.Close()
+		healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer healthy.Close()
+
+		recorder := &warningRecorder{}
+		client := New(&Auth{jwt: "valid_jwt_token"}, WithGateways(failing.URL, healthy.URL), WithWarningHandler(recorder.handle))
+
+		stat, err := client.StatContent(context.Background(), "QmTest")
+
+		require.NoError(t, err)
+		require.Equal(t, healthy.URL, stat.Gateway)
+		require.Len(t, stat.Warnings, 1)
+		require.Equal(t, WarningGatewayFallback, stat.Warnings[0].Code)
+		require.Equal(t, failing.URL, stat.Warnings[0].Detail["gateway"])
+		require.Equal(t, []WarningCode{WarningGatewayFallback}, recorder.codes())
+	})
+
+	t.Run("no handler", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"count":0,"rows":[]}`))
+		}))
+		defer mockServer.Close()
+
+		client := New(&Auth{jwt: "valid_jwt_token"})
+		client.baseURL = mockServer.URL
+
+		response, err := client.ListFiles(&ListFilesOptions{PageLimit: 5000})
+
+		require.NoError(t, err)
+		require.Len(t, response.Warnings, 1)
+	})
+}
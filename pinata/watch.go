@@ -0,0 +1,160 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// PinJobEventType identifies the kind of a PinJobEvent.
+type PinJobEventType string
+
+const (
+	// PinJobEventQueued is emitted for a job seen for the first time, including the jobs of the
+	// first poll.
+	PinJobEventQueued PinJobEventType = "queued"
+	// PinJobEventStatusChanged is emitted when the status of a job changed to another non-failure
+	// status, such as from "prechecking" to "retrieving".
+	PinJobEventStatusChanged PinJobEventType = "status_changed"
+	// PinJobEventCompleted is emitted when a job left the listing without failing, which is how
+	// pinJobs reports that the content was pinned.
+	PinJobEventCompleted PinJobEventType = "completed"
+	// PinJobEventFailed is emitted when a job moved to a failure status, such as "expired".
+	PinJobEventFailed PinJobEventType = "failed"
+	// PinJobEventError is emitted when a poll failed with an error other than a rate limit. The
+	// watch goes on and the next poll is diffed against the last successful one.
+	PinJobEventError PinJobEventType = "error"
+)
+
+// PinJobEvent describes a change of the pin by CID jobs observed by WatchPinJobs.
+// Type is the kind of change.
+// Before is the job as of the previous poll; it is nil for PinJobEventQueued.
+// After is the job as of the current poll; it is nil for PinJobEventCompleted.
+// Err is the poll error of a PinJobEventError.
+type PinJobEvent struct {
+	Type   PinJobEventType
+	Before *PinEntry
+	After  *PinEntry
+	Err    error
+}
+
+// WatchPinJobs polls pinJobs every interval (5 seconds when zero) and emits an event on the
+// returned channel whenever a job matching filter is queued, changes status, completes or fails.
+// Jobs are told apart by ID. When filter.Limit is not set, every poll asks for MaxPinJobsLimit jobs,
+// so a job is only reported as completed when it is no longer listed.
+//
+// The first poll is made before WatchPinJobs returns, and its error, if any, is returned. Later
+// polls rejected with 429 are skipped, waiting for the Retry-After delay when it is longer than
+// interval, and produce no events, as each poll is compared with the last successful one. The
+// channel is closed once ctx is done.
+func (c *Client) WatchPinJobs(ctx context.Context, interval time.Duration, filter *ListPinByCidOptions) (<-chan PinJobEvent, error) {
+	if interval <= 0 {
+		interval = defaultPinJobPollInterval
+	}
+	var options ListPinByCidOptions
+	if filter != nil {
+		options = *filter.Clone()
+	}
+	if options.Limit == nil {
+		options.Limit = Int(MaxPinJobsLimit)
+	}
+
+	response, err := c.pollPinJobs(ctx, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan PinJobEvent)
+	go func() {
+		defer close(events)
+
+		previous := response.Rows
+		if !sendPinJobEvents(ctx, events, diffPinJobs(nil, previous)) {
+			return
+		}
+
+		wait := interval
+		for {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			wait = interval
+			response, err := c.pollPinJobs(ctx, &options)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				var apiErr *APIError
+				if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+					wait = max(interval, apiErr.RetryAfter)
+					continue
+				}
+				err = fmt.Errorf("failed to poll pin jobs: %w", err)
+				if !sendPinJobEvents(ctx, events, []PinJobEvent{{Type: PinJobEventError, Err: err}}) {
+					return
+				}
+				continue
+			}
+
+			current := response.Rows
+			if !sendPinJobEvents(ctx, events, diffPinJobs(previous, current)) {
+				return
+			}
+			previous = current
+		}
+	}()
+	return events, nil
+}
+
+// diffPinJobs returns the events that turn the jobs of the previous poll into the jobs of the
+// current one: new and changed jobs in the order of current, then the jobs that left the listing
+// in the order of previous.
+func diffPinJobs(previous, current []PinEntry) []PinJobEvent {
+	before := make(map[string]*PinEntry, len(previous))
+	for i := range previous {
+		before[previous[i].ID] = &previous[i]
+	}
+
+	var events []PinJobEvent
+	seen := make(map[string]bool, len(current))
+	for i := range current {
+		after := &current[i]
+		seen[after.ID] = true
+		old, ok := before[after.ID]
+		switch {
+		case !ok:
+			events = append(events, PinJobEvent{Type: PinJobEventQueued, After: after})
+		case old.Status == after.Status:
+		case pinJobFailed(after.Status):
+			events = append(events, PinJobEvent{Type: PinJobEventFailed, Before: old, After: after})
+		default:
+			events = append(events, PinJobEvent{Type: PinJobEventStatusChanged, Before: old, After: after})
+		}
+	}
+	for i := range previous {
+		old := &previous[i]
+		if !seen[old.ID] && !pinJobFailed(old.Status) {
+			events = append(events, PinJobEvent{Type: PinJobEventCompleted, Before: old})
+		}
+	}
+	return events
+}
+
+// sendPinJobEvents sends events in order, returning false if ctx is done first.
+func sendPinJobEvents(ctx context.Context, ch chan<- PinJobEvent, events []PinJobEvent) bool {
+	for _, event := range events {
+		select {
+		case ch <- event:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchPinJobs(t *testing.T) {
+	job := func(id, status string) PinEntry {
+		return PinEntry{ID: id, IPFSPinHash: "Qm" + id, Status: status}
+	}
+	type step struct {
+		status int
+		rows   []PinEntry
+	}
+	script := []step{
+		{http.StatusOK, []PinEntry{job("a", "prechecking"), job("b", "prechecking")}},
+		{http.StatusOK, []PinEntry{job("a", "retrieving"), job("b", "prechecking")}},
+		{http.StatusTooManyRequests, nil},
+		{http.StatusOK, []PinEntry{job("a", "retrieving"), job("b", "expired")}},
+		{http.StatusOK, []PinEntry{job("b", "expired")}},
+		{http.StatusBadRequest, nil},
+		{http.StatusOK, []PinEntry{}},
+		{http.StatusOK, []PinEntry{job("c", "prechecking")}},
+	}
+
+	t.Run("scripted polls", func(t *testing.T) {
+		var polls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/pinning/pinJobs", r.URL.Path)
+			require.Equal(t, "1000", r.URL.Query().Get("limit"))
+			require.Equal(t, "ASC", r.URL.Query().Get("sort"))
+
+			n := int(atomic.AddInt32(&polls, 1)) - 1
+			current := script[min(n, len(script)-1)]
+			if current.status != http.StatusOK {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(current.status)
+				return
+			}
+			json.NewEncoder(w).Encode(ListPinByCidResponse{Count: len(current.rows), Rows: current.rows})
+		}))
+		defer server.Close()
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = server.URL
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := client.WatchPinJobs(ctx, time.Millisecond, &ListPinByCidOptions{Sort: SortOrderASC})
+		require.NoError(t, err)
+
+		expected := []struct {
+			eventType PinJobEventType
+			id        string
+			before    string
+			after     string
+		}{
+			{PinJobEventQueued, "a", "", "prechecking"},
+			{PinJobEventQueued, "b", "", "prechecking"},
+			{PinJobEventStatusChanged, "a", "prechecking", "retrieving"},
+			{PinJobEventFailed, "b", "prechecking", "expired"},
+			{PinJobEventCompleted, "a", "retrieving", ""},
+			{PinJobEventError, "", "", ""},
+			{PinJobEventQueued, "c", "", "prechecking"},
+		}
+		for _, want := range expected {
+			var event PinJobEvent
+			select {
+			case event = <-events:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for a %s event", want.eventType)
+			}
+
+			require.Equal(t, want.eventType, event.Type)
+			if want.eventType == PinJobEventError {
+				require.Error(t, event.Err)
+				var apiErr *APIError
+				require.ErrorAs(t, event.Err, &apiErr)
+				require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+				continue
+			}
+			require.NoError(t, event.Err)
+			if want.before == "" {
+				require.Nil(t, event.Before)
+			} else {
+				require.Equal(t, want.id, event.Before.ID)
+				require.Equal(t, want.before, event.Before.Status)
+			}
+			if want.after == "" {
+				require.Nil(t, event.After)
+			} else {
+				require.Equal(t, want.id, event.After.ID)
+				require.Equal(t, want.after, event.After.Status)
+			}
+		}
+
+		// the last response is served again by every later poll, which must not produce events
+		time.Sleep(20 * time.Millisecond)
+		select {
+		case event := <-events:
+			t.Fatalf("unexpected event %+v", event)
+		default:
+		}
+
+		cancel()
+		select {
+		case _, ok := <-events:
+			require.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Fatal("events channel was not closed after cancellation")
+		}
+	})
+
+	t.Run("first poll error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := New(NewAuthWithJWT("test_token"))
+		client.baseURL = server.URL
+		events, err := client.WatchPinJobs(context.Background(), time.Millisecond, nil)
+
+		require.Error(t, err)
+		require.Nil(t, events)
+	})
+}
+
+func TestDiffPinJobs(t *testing.T) {
+	previous := []PinEntry{{ID: "a", Status: "retrieving"}, {ID: "b", Status: "bad_host_node"}}
+
+	t.Run("unchanged snapshot", func(t *testing.T) {
+		require.Empty(t, diffPinJobs(previous, previous))
+	})
+
+	t.Run("failed jobs do not complete", func(t *testing.T) {
+		events := diffPinJobs(previous, nil)
+
+		require.Len(t, events, 1)
+		require.Equal(t, PinJobEventCompleted, events[0].Type)
+		require.Equal(t, "a", events[0].Before.ID)
+	})
+}